@@ -0,0 +1,492 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: barservice.proto
+
+package barservice
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type BarInterval int32
+
+const (
+	BarInterval_ONE_SECOND   BarInterval = 0
+	BarInterval_ONE_MINUTE   BarInterval = 1
+	BarInterval_FIVE_MINUTES BarInterval = 2
+	BarInterval_ONE_HOUR     BarInterval = 3
+	BarInterval_ONE_DAY      BarInterval = 4
+)
+
+var BarInterval_name = map[int32]string{
+	0: "ONE_SECOND",
+	1: "ONE_MINUTE",
+	2: "FIVE_MINUTES",
+	3: "ONE_HOUR",
+	4: "ONE_DAY",
+}
+
+var BarInterval_value = map[string]int32{
+	"ONE_SECOND":   0,
+	"ONE_MINUTE":   1,
+	"FIVE_MINUTES": 2,
+	"ONE_HOUR":     3,
+	"ONE_DAY":      4,
+}
+
+func (x BarInterval) String() string {
+	return proto.EnumName(BarInterval_name, int32(x))
+}
+
+func (BarInterval) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_78aa0a1e93c38cf6, []int{0}
+}
+
+// The open, high, low, close and volume of the trades of a listing in the interval beginning at the start time.  Bars
+// are aligned to the UTC day, an interval with no trades has no bar.
+type Bar struct {
+	ListingId            int32            `protobuf:"varint,1,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	Interval             BarInterval      `protobuf:"varint,2,opt,name=interval,proto3,enum=barservice.BarInterval" json:"interval,omitempty"`
+	StartTime            *model.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	Open                 float64          `protobuf:"fixed64,4,opt,name=open,proto3" json:"open,omitempty"`
+	High                 float64          `protobuf:"fixed64,5,opt,name=high,proto3" json:"high,omitempty"`
+	Low                  float64          `protobuf:"fixed64,6,opt,name=low,proto3" json:"low,omitempty"`
+	Close                float64          `protobuf:"fixed64,7,opt,name=close,proto3" json:"close,omitempty"`
+	Volume               float64          `protobuf:"fixed64,8,opt,name=volume,proto3" json:"volume,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Bar) Reset()         { *m = Bar{} }
+func (m *Bar) String() string { return proto.CompactTextString(m) }
+func (*Bar) ProtoMessage()    {}
+func (*Bar) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78aa0a1e93c38cf6, []int{0}
+}
+
+func (m *Bar) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Bar.Unmarshal(m, b)
+}
+func (m *Bar) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Bar.Marshal(b, m, deterministic)
+}
+func (m *Bar) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bar.Merge(m, src)
+}
+func (m *Bar) XXX_Size() int {
+	return xxx_messageInfo_Bar.Size(m)
+}
+func (m *Bar) XXX_DiscardUnknown() {
+	xxx_messageInfo_Bar.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Bar proto.InternalMessageInfo
+
+func (m *Bar) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func (m *Bar) GetInterval() BarInterval {
+	if m != nil {
+		return m.Interval
+	}
+	return BarInterval_ONE_SECOND
+}
+
+func (m *Bar) GetStartTime() *model.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *Bar) GetOpen() float64 {
+	if m != nil {
+		return m.Open
+	}
+	return 0
+}
+
+func (m *Bar) GetHigh() float64 {
+	if m != nil {
+		return m.High
+	}
+	return 0
+}
+
+func (m *Bar) GetLow() float64 {
+	if m != nil {
+		return m.Low
+	}
+	return 0
+}
+
+func (m *Bar) GetClose() float64 {
+	if m != nil {
+		return m.Close
+	}
+	return 0
+}
+
+func (m *Bar) GetVolume() float64 {
+	if m != nil {
+		return m.Volume
+	}
+	return 0
+}
+
+// Returns the bars starting at or after the from time and before the to time, an unset to time returns the bars up to
+// and including the current bar.
+type GetBarsParams struct {
+	ListingId            int32            `protobuf:"varint,1,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	Interval             BarInterval      `protobuf:"varint,2,opt,name=interval,proto3,enum=barservice.BarInterval" json:"interval,omitempty"`
+	From                 *model.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *model.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetBarsParams) Reset()         { *m = GetBarsParams{} }
+func (m *GetBarsParams) String() string { return proto.CompactTextString(m) }
+func (*GetBarsParams) ProtoMessage()    {}
+func (*GetBarsParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78aa0a1e93c38cf6, []int{1}
+}
+
+func (m *GetBarsParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBarsParams.Unmarshal(m, b)
+}
+func (m *GetBarsParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBarsParams.Marshal(b, m, deterministic)
+}
+func (m *GetBarsParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBarsParams.Merge(m, src)
+}
+func (m *GetBarsParams) XXX_Size() int {
+	return xxx_messageInfo_GetBarsParams.Size(m)
+}
+func (m *GetBarsParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBarsParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBarsParams proto.InternalMessageInfo
+
+func (m *GetBarsParams) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func (m *GetBarsParams) GetInterval() BarInterval {
+	if m != nil {
+		return m.Interval
+	}
+	return BarInterval_ONE_SECOND
+}
+
+func (m *GetBarsParams) GetFrom() *model.Timestamp {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *GetBarsParams) GetTo() *model.Timestamp {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+type Bars struct {
+	Bars                 []*Bar   `protobuf:"bytes,1,rep,name=bars,proto3" json:"bars,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Bars) Reset()         { *m = Bars{} }
+func (m *Bars) String() string { return proto.CompactTextString(m) }
+func (*Bars) ProtoMessage()    {}
+func (*Bars) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78aa0a1e93c38cf6, []int{2}
+}
+
+func (m *Bars) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Bars.Unmarshal(m, b)
+}
+func (m *Bars) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Bars.Marshal(b, m, deterministic)
+}
+func (m *Bars) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bars.Merge(m, src)
+}
+func (m *Bars) XXX_Size() int {
+	return xxx_messageInfo_Bars.Size(m)
+}
+func (m *Bars) XXX_DiscardUnknown() {
+	xxx_messageInfo_Bars.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Bars proto.InternalMessageInfo
+
+func (m *Bars) GetBars() []*Bar {
+	if m != nil {
+		return m.Bars
+	}
+	return nil
+}
+
+type SubscribeToBarsParams struct {
+	ListingId            int32       `protobuf:"varint,1,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	Interval             BarInterval `protobuf:"varint,2,opt,name=interval,proto3,enum=barservice.BarInterval" json:"interval,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SubscribeToBarsParams) Reset()         { *m = SubscribeToBarsParams{} }
+func (m *SubscribeToBarsParams) String() string { return proto.CompactTextString(m) }
+func (*SubscribeToBarsParams) ProtoMessage()    {}
+func (*SubscribeToBarsParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78aa0a1e93c38cf6, []int{3}
+}
+
+func (m *SubscribeToBarsParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeToBarsParams.Unmarshal(m, b)
+}
+func (m *SubscribeToBarsParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeToBarsParams.Marshal(b, m, deterministic)
+}
+func (m *SubscribeToBarsParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeToBarsParams.Merge(m, src)
+}
+func (m *SubscribeToBarsParams) XXX_Size() int {
+	return xxx_messageInfo_SubscribeToBarsParams.Size(m)
+}
+func (m *SubscribeToBarsParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeToBarsParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeToBarsParams proto.InternalMessageInfo
+
+func (m *SubscribeToBarsParams) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func (m *SubscribeToBarsParams) GetInterval() BarInterval {
+	if m != nil {
+		return m.Interval
+	}
+	return BarInterval_ONE_SECOND
+}
+
+func init() {
+	proto.RegisterEnum("barservice.BarInterval", BarInterval_name, BarInterval_value)
+	proto.RegisterType((*Bar)(nil), "barservice.Bar")
+	proto.RegisterType((*GetBarsParams)(nil), "barservice.GetBarsParams")
+	proto.RegisterType((*Bars)(nil), "barservice.Bars")
+	proto.RegisterType((*SubscribeToBarsParams)(nil), "barservice.SubscribeToBarsParams")
+}
+
+func init() { proto.RegisterFile("barservice.proto", fileDescriptor_78aa0a1e93c38cf6) }
+
+var fileDescriptor_78aa0a1e93c38cf6 = []byte{
+	// 415 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x93, 0xcf, 0x6e, 0x13, 0x31,
+	0x10, 0xc6, 0xe3, 0xec, 0xe6, 0x4f, 0x27, 0xa5, 0x35, 0x23, 0xfe, 0x98, 0x88, 0xc3, 0xb2, 0x70,
+	0x58, 0x81, 0xb4, 0x42, 0xe9, 0x85, 0x2b, 0xa1, 0x01, 0xf6, 0x40, 0x82, 0x36, 0x29, 0x12, 0x5c,
+	0x90, 0x93, 0x9a, 0x76, 0xd1, 0x3a, 0x8e, 0x6c, 0x37, 0x3c, 0x07, 0xaf, 0xc1, 0xd3, 0xf1, 0x08,
+	0xc8, 0xde, 0x94, 0x2c, 0x2b, 0x7a, 0xec, 0x6d, 0xe6, 0xf7, 0x7d, 0x92, 0xbf, 0x99, 0x91, 0x81,
+	0x2e, 0xb9, 0x36, 0x42, 0x6f, 0x8b, 0x95, 0x48, 0x37, 0x5a, 0x59, 0x85, 0xb0, 0x27, 0xc3, 0xbb,
+	0x52, 0x9d, 0x8b, 0x72, 0xa5, 0xa4, 0x54, 0xeb, 0x4a, 0x8e, 0x7f, 0x13, 0x08, 0xc6, 0x5c, 0xe3,
+	0x63, 0x38, 0x28, 0x0b, 0x63, 0x8b, 0xf5, 0x45, 0x76, 0xce, 0x48, 0x44, 0x92, 0x4e, 0xbe, 0x07,
+	0x78, 0x02, 0xfd, 0x62, 0x6d, 0x85, 0xde, 0xf2, 0x92, 0xb5, 0x23, 0x92, 0x1c, 0x8d, 0x1e, 0xa6,
+	0xb5, 0x97, 0xc6, 0x5c, 0x67, 0x3b, 0x39, 0xff, 0x6b, 0xc4, 0x14, 0x0e, 0x8c, 0xe5, 0xda, 0x2e,
+	0x0a, 0x29, 0x58, 0x10, 0x91, 0x64, 0x30, 0xa2, 0xa9, 0x4f, 0x90, 0x3a, 0x64, 0x2c, 0x97, 0x9b,
+	0x7c, 0x6f, 0x41, 0x84, 0x50, 0x6d, 0xc4, 0x9a, 0x85, 0x11, 0x49, 0x48, 0xee, 0x6b, 0xc7, 0x2e,
+	0x8b, 0x8b, 0x4b, 0xd6, 0xa9, 0x98, 0xab, 0x91, 0x42, 0x50, 0xaa, 0x1f, 0xac, 0xeb, 0x91, 0x2b,
+	0xf1, 0x1e, 0x74, 0x56, 0xa5, 0x32, 0x82, 0xf5, 0x3c, 0xab, 0x1a, 0x7c, 0x00, 0xdd, 0xad, 0x2a,
+	0xaf, 0xa4, 0x60, 0x7d, 0x8f, 0x77, 0x5d, 0xfc, 0x8b, 0xc0, 0x9d, 0x77, 0xc2, 0x8e, 0xb9, 0x36,
+	0x1f, 0xb9, 0xe6, 0xd2, 0xdc, 0xc6, 0xf0, 0xcf, 0x20, 0xfc, 0xa6, 0x95, 0xbc, 0x71, 0x6e, 0xaf,
+	0x62, 0x04, 0x6d, 0xab, 0x58, 0x78, 0x83, 0xa7, 0x6d, 0x55, 0xfc, 0x02, 0x42, 0x17, 0x14, 0x9f,
+	0x42, 0xe8, 0xde, 0x64, 0x24, 0x0a, 0x92, 0xc1, 0xe8, 0xb8, 0x11, 0x20, 0xf7, 0x62, 0xfc, 0x1d,
+	0xee, 0xcf, 0xaf, 0x96, 0x66, 0xa5, 0x8b, 0xa5, 0x58, 0xa8, 0x5b, 0x1d, 0xf0, 0xf9, 0x17, 0x18,
+	0xd4, 0x04, 0x3c, 0x02, 0x98, 0x4d, 0x27, 0x5f, 0xe7, 0x93, 0x37, 0xb3, 0xe9, 0x29, 0x6d, 0x5d,
+	0xf7, 0x1f, 0xb2, 0xe9, 0xd9, 0x62, 0x42, 0x09, 0x52, 0x38, 0x7c, 0x9b, 0x7d, 0xba, 0x06, 0x73,
+	0xda, 0xc6, 0x43, 0xe8, 0x3b, 0xc7, 0xfb, 0xd9, 0x59, 0x4e, 0x03, 0x1c, 0x40, 0xcf, 0x75, 0xa7,
+	0xaf, 0x3f, 0xd3, 0x70, 0xf4, 0x93, 0x00, 0x8c, 0xb9, 0x9e, 0x57, 0x01, 0xf0, 0x15, 0xf4, 0x76,
+	0xf7, 0xc2, 0x47, 0xf5, 0x60, 0xff, 0x1c, 0x71, 0x48, 0x1b, 0x99, 0x4d, 0xdc, 0xc2, 0x0c, 0x8e,
+	0x1b, 0x0b, 0xc1, 0x27, 0x75, 0xdb, 0x7f, 0xb7, 0x35, 0x6c, 0x6e, 0x37, 0x6e, 0xbd, 0x24, 0xcb,
+	0xae, 0xff, 0x2f, 0x27, 0x7f, 0x06, 0x00, 0x25, 0x3d, 0xf6, 0x2f, 0x62, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BarServiceClient is the client API for BarService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BarServiceClient interface {
+	GetBars(ctx context.Context, in *GetBarsParams, opts ...grpc.CallOption) (*Bars, error)
+	SubscribeToBars(ctx context.Context, in *SubscribeToBarsParams, opts ...grpc.CallOption) (BarService_SubscribeToBarsClient, error)
+}
+
+type barServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBarServiceClient(cc *grpc.ClientConn) BarServiceClient {
+	return &barServiceClient{cc}
+}
+
+func (c *barServiceClient) GetBars(ctx context.Context, in *GetBarsParams, opts ...grpc.CallOption) (*Bars, error) {
+	out := new(Bars)
+	err := c.cc.Invoke(ctx, "/barservice.BarService/GetBars", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *barServiceClient) SubscribeToBars(ctx context.Context, in *SubscribeToBarsParams, opts ...grpc.CallOption) (BarService_SubscribeToBarsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BarService_serviceDesc.Streams[0], "/barservice.BarService/SubscribeToBars", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &barServiceSubscribeToBarsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BarService_SubscribeToBarsClient interface {
+	Recv() (*Bar, error)
+	grpc.ClientStream
+}
+
+type barServiceSubscribeToBarsClient struct {
+	grpc.ClientStream
+}
+
+func (x *barServiceSubscribeToBarsClient) Recv() (*Bar, error) {
+	m := new(Bar)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BarServiceServer is the server API for BarService service.
+type BarServiceServer interface {
+	GetBars(context.Context, *GetBarsParams) (*Bars, error)
+	SubscribeToBars(*SubscribeToBarsParams, BarService_SubscribeToBarsServer) error
+}
+
+// UnimplementedBarServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedBarServiceServer struct {
+}
+
+func (*UnimplementedBarServiceServer) GetBars(ctx context.Context, req *GetBarsParams) (*Bars, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBars not implemented")
+}
+func (*UnimplementedBarServiceServer) SubscribeToBars(req *SubscribeToBarsParams, srv BarService_SubscribeToBarsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToBars not implemented")
+}
+
+func RegisterBarServiceServer(s *grpc.Server, srv BarServiceServer) {
+	s.RegisterService(&_BarService_serviceDesc, srv)
+}
+
+func _BarService_GetBars_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBarsParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BarServiceServer).GetBars(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/barservice.BarService/GetBars",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BarServiceServer).GetBars(ctx, req.(*GetBarsParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BarService_SubscribeToBars_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToBarsParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BarServiceServer).SubscribeToBars(m, &barServiceSubscribeToBarsServer{stream})
+}
+
+type BarService_SubscribeToBarsServer interface {
+	Send(*Bar) error
+	grpc.ServerStream
+}
+
+type barServiceSubscribeToBarsServer struct {
+	grpc.ServerStream
+}
+
+func (x *barServiceSubscribeToBarsServer) Send(m *Bar) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BarService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "barservice.BarService",
+	HandlerType: (*BarServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBars",
+			Handler:    _BarService_GetBars_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeToBars",
+			Handler:       _BarService_SubscribeToBars_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "barservice.proto",
+}
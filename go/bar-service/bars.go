@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/bar-service/api/barservice"
+	"github.com/ettec/otp-common/model"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// intervals are the durations of the bar intervals, every interval divides a day so that bars truncated from the zero
+// time are aligned to the UTC day.
+var intervals = map[api.BarInterval]time.Duration{
+	api.BarInterval_ONE_SECOND:   time.Second,
+	api.BarInterval_ONE_MINUTE:   time.Minute,
+	api.BarInterval_FIVE_MINUTES: 5 * time.Minute,
+	api.BarInterval_ONE_HOUR:     time.Hour,
+	api.BarInterval_ONE_DAY:      24 * time.Hour,
+}
+
+func intervalDuration(interval api.BarInterval) (time.Duration, error) {
+	duration, ok := intervals[interval]
+	if !ok {
+		return 0, fmt.Errorf("unknown bar interval: %v", interval)
+	}
+
+	return duration, nil
+}
+
+type barKey struct {
+	listingId int32
+	interval  api.BarInterval
+}
+
+type bar struct {
+	barKey
+	start  time.Time
+	open   float64
+	high   float64
+	low    float64
+	close  float64
+	volume float64
+}
+
+func (b bar) toApi() *api.Bar {
+	return &api.Bar{
+		ListingId: b.listingId,
+		Interval:  b.interval,
+		StartTime: model.NewTimeStamp(b.start),
+		Open:      b.open,
+		High:      b.high,
+		Low:       b.low,
+		Close:     b.close,
+		Volume:    b.volume,
+	}
+}
+
+// barStart identifies a bar by its listing, interval and start time.
+type barStart struct {
+	barKey
+	start time.Time
+}
+
+type lastTrade struct {
+	price    float64
+	quantity float64
+}
+
+// barEngine builds the bars of each subscribed listing from the trades reported in the listing's quotes.  The volume
+// traded between quotes is the increase in the quote's traded volume, a quote with no traded volume is taken to report
+// a trade of its last quantity when its last price or last quantity changes.  The first quote of a listing, and the
+// first after the quote stream is interrupted, sets the listing's baseline and is not counted as a trade.
+type barEngine struct {
+	mutex            sync.Mutex
+	bars             map[barKey]*bar
+	unstored         map[barStart]bar
+	lastTradedVolume map[int32]float64
+	lastTrades       map[int32]lastTrade
+	subscriptions    map[*barSubscription]bool
+
+	subscriptionsMutex sync.Mutex
+	subscribeToQuotes  func(listingId int32) error
+	subscribed         map[int32]bool
+}
+
+// barSubscription records the bars of the subscribed listing and interval that have changed since the subscriber last
+// took the changes, a bar that completes before the changes are taken is kept so that its final state is sent.
+type barSubscription struct {
+	key     barKey
+	pending map[time.Time]bar
+	changed chan struct{}
+}
+
+func newBarEngine(subscribeToQuotes func(listingId int32) error) *barEngine {
+	return &barEngine{
+		bars:              map[barKey]*bar{},
+		unstored:          map[barStart]bar{},
+		lastTradedVolume:  map[int32]float64{},
+		lastTrades:        map[int32]lastTrade{},
+		subscriptions:     map[*barSubscription]bool{},
+		subscribeToQuotes: subscribeToQuotes,
+		subscribed:        map[int32]bool{},
+	}
+}
+
+// record starts building the bars of the listing if they are not already being built.
+func (e *barEngine) record(listingId int32) {
+	e.subscriptionsMutex.Lock()
+	defer e.subscriptionsMutex.Unlock()
+
+	if e.subscribed[listingId] {
+		return
+	}
+
+	if err := e.subscribeToQuotes(listingId); err != nil {
+		slog.Error("failed to subscribe to quotes, bars of the listing will not be built", "listingId", listingId,
+			"error", err)
+		return
+	}
+
+	e.subscribed[listingId] = true
+}
+
+// restore sets the current bars, as loaded from the store on start up, so that trades in their intervals are added to
+// them.
+func (e *barEngine) restore(bars []bar) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, b := range bars {
+		if current, ok := e.bars[b.barKey]; !ok || current.start.Before(b.start) {
+			restored := b
+			e.bars[b.barKey] = &restored
+		}
+	}
+}
+
+func (e *barEngine) onQuote(quote *model.ClobQuote, now time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if quote.StreamInterrupted {
+		// The trades made while the stream was interrupted cannot be attributed to an interval
+		delete(e.lastTradedVolume, quote.ListingId)
+		delete(e.lastTrades, quote.ListingId)
+		return
+	}
+
+	price, volume, ok := e.getTrade(quote)
+	if !ok {
+		return
+	}
+
+	for interval, duration := range intervals {
+		e.addTrade(barKey{listingId: quote.ListingId, interval: interval}, now.UTC().Truncate(duration), price, volume)
+	}
+}
+
+// getTrade returns the price and volume of the trades reported by the quote, if any.
+func (e *barEngine) getTrade(quote *model.ClobQuote) (float64, float64, bool) {
+	if quote.LastPrice == nil {
+		return 0, 0, false
+	}
+	price := quote.LastPrice.ToFloat()
+
+	if quote.TradedVolume != nil {
+		tradedVolume := quote.TradedVolume.ToFloat()
+		lastTradedVolume, ok := e.lastTradedVolume[quote.ListingId]
+		e.lastTradedVolume[quote.ListingId] = tradedVolume
+		if !ok {
+			return 0, 0, false
+		}
+
+		volume := tradedVolume - lastTradedVolume
+		if volume < 0 {
+			// The traded volume is reset at the start of each trading day
+			volume = tradedVolume
+		}
+
+		return price, volume, volume > 0
+	}
+
+	if quote.LastQuantity == nil {
+		return 0, 0, false
+	}
+
+	trade := lastTrade{price: price, quantity: quote.LastQuantity.ToFloat()}
+	previous, ok := e.lastTrades[quote.ListingId]
+	e.lastTrades[quote.ListingId] = trade
+
+	return trade.price, trade.quantity, ok && trade != previous && trade.quantity > 0
+}
+
+func (e *barEngine) addTrade(key barKey, start time.Time, price float64, volume float64) {
+	b, ok := e.bars[key]
+	if !ok || b.start.Before(start) {
+		b = &bar{barKey: key, start: start, open: price, high: price, low: price, close: price}
+		e.bars[key] = b
+	} else if b.start.After(start) {
+		return
+	} else {
+		if price > b.high {
+			b.high = price
+		}
+		if price < b.low {
+			b.low = price
+		}
+		b.close = price
+	}
+
+	b.volume += volume
+
+	e.unstored[barStart{barKey: key, start: start}] = *b
+
+	for subscription := range e.subscriptions {
+		if subscription.key == key {
+			subscription.pending[start] = *b
+			select {
+			case subscription.changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// getLatestBars returns the bars of the listing and interval that have not yet been stored and the current bar, in
+// start time order.
+func (e *barEngine) getLatestBars(key barKey) []bar {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var result []bar
+	for start, b := range e.unstored {
+		if start.barKey == key {
+			result = append(result, b)
+		}
+	}
+
+	if b, ok := e.bars[key]; ok {
+		if _, unstored := e.unstored[barStart{barKey: key, start: b.start}]; !unstored {
+			result = append(result, *b)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].start.Before(result[j].start)
+	})
+
+	return result
+}
+
+// takeUnstored returns the bars that have changed since they were last taken.
+func (e *barEngine) takeUnstored() []bar {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	result := make([]bar, 0, len(e.unstored))
+	for _, b := range e.unstored {
+		result = append(result, b)
+	}
+	e.unstored = map[barStart]bar{}
+
+	return result
+}
+
+// returnUnstored returns bars that failed to be stored to the engine, a bar that has changed since it was taken is
+// not replaced.
+func (e *barEngine) returnUnstored(bars []bar) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, b := range bars {
+		key := barStart{barKey: b.barKey, start: b.start}
+		if _, ok := e.unstored[key]; !ok {
+			e.unstored[key] = b
+		}
+	}
+}
+
+// subscribe returns the current bar of the listing and interval, if any, and a subscription to its changes.
+func (e *barEngine) subscribe(key barKey) (*bar, *barSubscription) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	subscription := &barSubscription{
+		key:     key,
+		pending: map[time.Time]bar{},
+		changed: make(chan struct{}, 1),
+	}
+	e.subscriptions[subscription] = true
+
+	if b, ok := e.bars[key]; ok {
+		current := *b
+		return &current, subscription
+	}
+
+	return nil, subscription
+}
+
+// takeChanges returns the latest state of each bar changed since the changes were last taken, in start time order.
+func (e *barEngine) takeChanges(subscription *barSubscription) []bar {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	result := make([]bar, 0, len(subscription.pending))
+	for _, b := range subscription.pending {
+		result = append(result, b)
+	}
+	subscription.pending = map[time.Time]bar{}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].start.Before(result[j].start)
+	})
+
+	return result
+}
+
+func (e *barEngine) unsubscribe(subscription *barSubscription) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	delete(e.subscriptions, subscription)
+}
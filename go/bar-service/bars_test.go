@@ -0,0 +1,124 @@
+package main
+
+import (
+	api "github.com/ettec/open-trading-platform/go/bar-service/api/barservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func minuteBarKey(listingId int32) barKey {
+	return barKey{listingId: listingId, interval: api.BarInterval_ONE_MINUTE}
+}
+
+func TestBarsAreBuiltFromTradedVolumeIncrements(t *testing.T) {
+	engine := newBarEngine(func(int32) error { return nil })
+	now := time.Date(2024, time.March, 4, 10, 15, 30, 0, time.UTC)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), TradedVolume: model.IasD(1000)}, now)
+	assert.Empty(t, engine.getLatestBars(minuteBarKey(1)))
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(102), TradedVolume: model.IasD(1010)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(99), TradedVolume: model.IasD(1030)},
+		now.Add(10*time.Second))
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(110), TradedVolume: model.IasD(1030)},
+		now.Add(15*time.Second))
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(101), TradedVolume: model.IasD(1035)},
+		now.Add(20*time.Second))
+
+	minute := now.Truncate(time.Minute)
+	assert.Equal(t, []bar{{barKey: minuteBarKey(1), start: minute, open: 102, high: 102, low: 99, close: 101, volume: 35}},
+		engine.getLatestBars(minuteBarKey(1)))
+
+	hourKey := barKey{listingId: 1, interval: api.BarInterval_ONE_HOUR}
+	dayKey := barKey{listingId: 1, interval: api.BarInterval_ONE_DAY}
+	assert.Equal(t, time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC), engine.getLatestBars(hourKey)[0].start)
+	assert.Equal(t, time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), engine.getLatestBars(dayKey)[0].start)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(103), TradedVolume: model.IasD(1045)},
+		now.Add(time.Minute))
+
+	// Four second bars, two minute bars and a five minute, hour and day bar
+	stored := engine.takeUnstored()
+	assert.Len(t, stored, 9)
+	assert.Empty(t, engine.takeUnstored())
+
+	assert.Equal(t, []bar{{barKey: minuteBarKey(1), start: minute.Add(time.Minute), open: 103, high: 103, low: 103,
+		close: 103, volume: 10}}, engine.getLatestBars(minuteBarKey(1)))
+	assert.Equal(t, bar{barKey: dayKey, start: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), open: 102,
+		high: 103, low: 99, close: 103, volume: 45}, engine.getLatestBars(dayKey)[0])
+}
+
+func TestTradedVolumeResetAndStreamInterruption(t *testing.T) {
+	engine := newBarEngine(func(int32) error { return nil })
+	now := time.Date(2024, time.March, 4, 10, 15, 30, 0, time.UTC)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), TradedVolume: model.IasD(1000)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), TradedVolume: model.IasD(5)}, now)
+	assert.Equal(t, float64(5), engine.getLatestBars(minuteBarKey(1))[0].volume)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, StreamInterrupted: true}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), TradedVolume: model.IasD(50)}, now)
+	assert.Equal(t, float64(5), engine.getLatestBars(minuteBarKey(1))[0].volume)
+}
+
+func TestQuotesWithoutTradedVolumeReportTradesOfTheLastQuantity(t *testing.T) {
+	engine := newBarEngine(func(int32) error { return nil })
+	now := time.Date(2024, time.March, 4, 10, 15, 30, 0, time.UTC)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), LastQuantity: model.IasD(10)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), LastQuantity: model.IasD(10)}, now)
+	assert.Empty(t, engine.getLatestBars(minuteBarKey(1)))
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(101), LastQuantity: model.IasD(10)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(101), LastQuantity: model.IasD(20)}, now)
+
+	b := engine.getLatestBars(minuteBarKey(1))[0]
+	assert.Equal(t, float64(101), b.close)
+	assert.Equal(t, float64(30), b.volume)
+}
+
+func TestRestoredBarsAreContinued(t *testing.T) {
+	engine := newBarEngine(func(int32) error { return nil })
+	now := time.Date(2024, time.March, 4, 10, 15, 30, 0, time.UTC)
+
+	engine.restore([]bar{{barKey: minuteBarKey(1), start: now.Truncate(time.Minute), open: 90, high: 95, low: 85,
+		close: 92, volume: 100}})
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), TradedVolume: model.IasD(1000)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(96), TradedVolume: model.IasD(1010)}, now)
+
+	assert.Equal(t, []bar{{barKey: minuteBarKey(1), start: now.Truncate(time.Minute), open: 90, high: 96, low: 85,
+		close: 96, volume: 110}}, engine.getLatestBars(minuteBarKey(1)))
+}
+
+func TestSubscriptionReceivesTheFinalStateOfACompletedBar(t *testing.T) {
+	engine := newBarEngine(func(int32) error { return nil })
+	now := time.Date(2024, time.March, 4, 10, 15, 30, 0, time.UTC)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), TradedVolume: model.IasD(1000)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100), TradedVolume: model.IasD(1010)}, now)
+
+	current, subscription := engine.subscribe(minuteBarKey(1))
+	assert.Equal(t, float64(10), current.volume)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(101), TradedVolume: model.IasD(1015)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(102), TradedVolume: model.IasD(1020)},
+		now.Add(time.Minute))
+	engine.onQuote(&model.ClobQuote{ListingId: 2, LastPrice: model.IasD(50), TradedVolume: model.IasD(10)}, now)
+
+	<-subscription.changed
+	changes := engine.takeChanges(subscription)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, now.Truncate(time.Minute), changes[0].start)
+	assert.Equal(t, float64(15), changes[0].volume)
+	assert.Equal(t, float64(101), changes[0].close)
+	assert.Equal(t, now.Truncate(time.Minute).Add(time.Minute), changes[1].start)
+	assert.Equal(t, float64(5), changes[1].volume)
+
+	engine.unsubscribe(subscription)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(102), TradedVolume: model.IasD(1030)},
+		now.Add(time.Minute))
+	assert.Empty(t, engine.takeChanges(subscription))
+}
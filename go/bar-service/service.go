@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/bar-service/api/barservice"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const maxConnectRetry = 2 * time.Second
+
+// maxBarsPerQuery is the maximum number of intervals a bars query may span.
+const maxBarsPerQuery = 10000
+
+type barStore interface {
+	storeBars(ctx context.Context, bars []bar) error
+	getBars(ctx context.Context, key barKey, from time.Time, to time.Time) ([]bar, error)
+}
+
+type service struct {
+	engine *barEngine
+	store  barStore
+}
+
+func newService(engine *barEngine, store barStore) *service {
+	return &service{engine: engine, store: store}
+}
+
+// GetBars returns the stored bars of the query merged with the bars built since they were last stored.  The listing's
+// bars are built from the time of the first query or subscription for the listing onwards.
+func (s *service) GetBars(ctx context.Context, params *api.GetBarsParams) (*api.Bars, error) {
+	duration, err := intervalDuration(params.Interval)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if params.From == nil {
+		return nil, status.Error(codes.InvalidArgument, "from time must be set")
+	}
+
+	from := toTime(params.From)
+	to := time.Now().UTC().Truncate(duration).Add(duration)
+	if params.To != nil {
+		to = toTime(params.To)
+	}
+
+	if !to.After(from) {
+		return nil, status.Errorf(codes.InvalidArgument, "to time %v must be after from time %v", to, from)
+	}
+
+	if to.Sub(from)/duration > maxBarsPerQuery {
+		return nil, status.Errorf(codes.InvalidArgument, "query spans more than the maximum of %v bars", maxBarsPerQuery)
+	}
+
+	s.engine.record(params.ListingId)
+
+	key := barKey{listingId: params.ListingId, interval: params.Interval}
+	stored, err := s.store.getBars(ctx, key, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bars: %w", err)
+	}
+
+	bars := map[time.Time]bar{}
+	for _, b := range stored {
+		bars[b.start] = b
+	}
+
+	for _, b := range s.engine.getLatestBars(key) {
+		if !b.start.Before(from) && b.start.Before(to) {
+			bars[b.start] = b
+		}
+	}
+
+	result := make([]*api.Bar, 0, len(bars))
+	for _, b := range bars {
+		result = append(result, b.toApi())
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartTime.Before(result[j].StartTime)
+	})
+
+	return &api.Bars{Bars: result}, nil
+}
+
+// SubscribeToBars sends the current bar of the listing and interval, if any, followed by the latest state of each bar
+// as it changes.
+func (s *service) SubscribeToBars(params *api.SubscribeToBarsParams, stream api.BarService_SubscribeToBarsServer) error {
+	if _, err := intervalDuration(params.Interval); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	slog.Info("subscribing to bars", "listingId", params.ListingId, "interval", params.Interval)
+
+	s.engine.record(params.ListingId)
+
+	current, subscription := s.engine.subscribe(barKey{listingId: params.ListingId, interval: params.Interval})
+	defer func() {
+		s.engine.unsubscribe(subscription)
+		slog.Info("unsubscribed from bars", "listingId", params.ListingId, "interval", params.Interval)
+	}()
+
+	if current != nil {
+		if err := stream.Send(current.toApi()); err != nil {
+			return fmt.Errorf("failed to send bar: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-subscription.changed:
+		}
+
+		for _, b := range s.engine.takeChanges(subscription) {
+			if err := stream.Send(b.toApi()); err != nil {
+				return fmt.Errorf("failed to send bar: %w", err)
+			}
+		}
+	}
+}
+
+func toTime(timestamp *model.Timestamp) time.Time {
+	return time.Unix(timestamp.Seconds, int64(timestamp.Nanoseconds)).UTC()
+}
+
+// run builds bars from the quotes and stores the changed bars at each store interval, until the context is cancelled
+// or the quote channel is closed.  Bars that fail to be stored are retried at the next store interval.
+func run(ctx context.Context, engine *barEngine, quotes <-chan *model.ClobQuote, store barStore,
+	storeInterval time.Duration) error {
+
+	ticker := time.NewTicker(storeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			storeBars(context.Background(), engine, store)
+			return nil
+		case quote, ok := <-quotes:
+			if !ok {
+				storeBars(context.Background(), engine, store)
+				return errors.New("quote channel closed")
+			}
+			engine.onQuote(quote, time.Now())
+		case <-ticker.C:
+			storeBars(ctx, engine, store)
+		}
+	}
+}
+
+func storeBars(ctx context.Context, engine *barEngine, store barStore) {
+	bars := engine.takeUnstored()
+	if len(bars) == 0 {
+		return
+	}
+
+	if err := store.storeBars(ctx, bars); err != nil {
+		slog.Error("failed to store bars, will retry at the next store interval", "error", err)
+		engine.returnUnstored(bars)
+	}
+}
+
+func parseListingIds(value string) ([]int32, error) {
+	var result []int32
+	for _, id := range strings.Split(value, ",") {
+		if strings.TrimSpace(id) == "" {
+			continue
+		}
+
+		listingId, err := strconv.ParseInt(strings.TrimSpace(id), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid listing id %q: %w", id, err)
+		}
+		result = append(result, int32(listingId))
+	}
+
+	return result, nil
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	dbString := bootstrap.GetEnvVar("DB_CONN_STRING")
+	dbDriverName := bootstrap.GetEnvVar("DB_DRIVER_NAME")
+	storeInterval := time.Duration(bootstrap.GetOptionalIntEnvVar("STORE_INTERVAL_MILLIS", 1000)) * time.Millisecond
+	activeDays := bootstrap.GetOptionalIntEnvVar("ACTIVE_LISTING_DAYS", 30)
+
+	listingIds, err := parseListingIds(bootstrap.GetOptionalEnvVar("LISTING_IDS", ""))
+	if err != nil {
+		log.Panicf("invalid listing ids: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id, err := os.Hostname()
+	if err != nil {
+		log.Panicf("failed to get hostname: %v", err)
+	}
+
+	mdsAddress := bootstrap.GetOptionalEnvVar("MARKET_DATA_SERVICE_ADDRESS", "")
+	if mdsAddress == "" {
+		mdsAddress, err = k8s.GetServiceAddress("market-data-service")
+		if err != nil {
+			log.Panicf("failed to get market data service address: %v", err)
+		}
+	}
+
+	store, err := newSqlBarStore(dbDriverName, dbString)
+	if err != nil {
+		log.Panicf("failed to create bar store: %v", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			slog.Error("error closing bar store", "error", err)
+		}
+	}()
+
+	quoteStream, err := marketdata.NewQuoteStreamFromMarketDataService(ctx, id, mdsAddress, maxConnectRetry,
+		bootstrap.GetOptionalIntEnvVar("QUOTE_BUFFER_SIZE", 1000))
+	if err != nil {
+		log.Panicf("failed to create quote stream: %v", err)
+	}
+	defer quoteStream.Close()
+
+	engine := newBarEngine(quoteStream.Subscribe)
+
+	currentBars, err := store.getCurrentBars(ctx, time.Now())
+	if err != nil {
+		log.Panicf("failed to load current bars: %v", err)
+	}
+	engine.restore(currentBars)
+
+	activeListingIds, err := store.getListingIds(ctx, api.BarInterval_ONE_DAY,
+		time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -activeDays))
+	if err != nil {
+		log.Panicf("failed to load active listings: %v", err)
+	}
+
+	for _, listingId := range append(listingIds, activeListingIds...) {
+		engine.record(listingId)
+	}
+
+	port := "50551"
+	slog.Info("Starting bar service", "port", port, "listings", len(listingIds)+len(activeListingIds))
+	listener, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	s := grpc.NewServer()
+	api.RegisterBarServiceServer(s, newService(engine, store))
+	reflection.Register(s)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+		s.GracefulStop()
+	}()
+
+	go func() {
+		if err := run(ctx, engine, quoteStream.Chan(), store, storeInterval); err != nil {
+			log.Panicf("bar building failed: %v", err)
+		}
+	}()
+
+	if err := s.Serve(listener); err != nil {
+		log.Panicf("Error while serving : %v", err)
+	}
+}
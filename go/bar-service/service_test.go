@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	api "github.com/ettec/open-trading-platform/go/bar-service/api/barservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+type testBarStore struct {
+	bars     []bar
+	stored   [][]bar
+	storeErr error
+}
+
+func (t *testBarStore) storeBars(_ context.Context, bars []bar) error {
+	if t.storeErr != nil {
+		return t.storeErr
+	}
+	t.stored = append(t.stored, bars)
+	return nil
+}
+
+func (t *testBarStore) getBars(_ context.Context, key barKey, from time.Time, to time.Time) ([]bar, error) {
+	var result []bar
+	for _, b := range t.bars {
+		if b.barKey == key && !b.start.Before(from) && b.start.Before(to) {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+func TestGetBarsMergesStoredAndLatestBars(t *testing.T) {
+	var subscribed []int32
+	engine := newBarEngine(func(listingId int32) error {
+		subscribed = append(subscribed, listingId)
+		return nil
+	})
+
+	now := time.Now().UTC()
+	minute := now.Truncate(time.Minute)
+	store := &testBarStore{bars: []bar{
+		{barKey: minuteBarKey(1), start: minute.Add(-2 * time.Minute), open: 1, high: 1, low: 1, close: 1, volume: 1},
+		{barKey: minuteBarKey(1), start: minute, open: 2, high: 2, low: 2, close: 2, volume: 2},
+	}}
+
+	engine.restore([]bar{{barKey: minuteBarKey(1), start: minute, open: 2, high: 2, low: 2, close: 2, volume: 2}})
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(3), TradedVolume: model.IasD(10)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(3), TradedVolume: model.IasD(15)}, now)
+
+	s := newService(engine, store)
+	bars, err := s.GetBars(context.Background(), &api.GetBarsParams{ListingId: 1, Interval: api.BarInterval_ONE_MINUTE,
+		From: model.NewTimeStamp(minute.Add(-time.Hour))})
+	assert.NoError(t, err)
+
+	assert.Len(t, bars.Bars, 2)
+	assert.Equal(t, float64(1), bars.Bars[0].Volume)
+	assert.Equal(t, float64(7), bars.Bars[1].Volume)
+	assert.Equal(t, float64(3), bars.Bars[1].Close)
+	assert.Equal(t, []int32{1}, subscribed)
+
+	bars, err = s.GetBars(context.Background(), &api.GetBarsParams{ListingId: 1, Interval: api.BarInterval_ONE_MINUTE,
+		From: model.NewTimeStamp(minute.Add(-time.Hour)), To: model.NewTimeStamp(minute)})
+	assert.NoError(t, err)
+	assert.Len(t, bars.Bars, 1)
+	assert.Equal(t, []int32{1}, subscribed)
+}
+
+func TestGetBarsValidatesTheQuery(t *testing.T) {
+	s := newService(newBarEngine(func(int32) error { return nil }), &testBarStore{})
+	now := time.Now()
+
+	for _, params := range []*api.GetBarsParams{
+		{Interval: api.BarInterval(10), From: model.NewTimeStamp(now)},
+		{Interval: api.BarInterval_ONE_MINUTE},
+		{Interval: api.BarInterval_ONE_MINUTE, From: model.NewTimeStamp(now), To: model.NewTimeStamp(now)},
+		{Interval: api.BarInterval_ONE_SECOND, From: model.NewTimeStamp(now.Add(-24 * time.Hour))},
+	} {
+		_, err := s.GetBars(context.Background(), params)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestBarsThatFailToBeStoredAreRetried(t *testing.T) {
+	engine := newBarEngine(func(int32) error { return nil })
+	store := &testBarStore{storeErr: errors.New("database unavailable")}
+	now := time.Now()
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(3), TradedVolume: model.IasD(10)}, now)
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(3), TradedVolume: model.IasD(15)}, now)
+
+	storeBars(context.Background(), engine, store)
+	assert.Empty(t, store.stored)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(4), TradedVolume: model.IasD(20)}, now)
+
+	store.storeErr = nil
+	storeBars(context.Background(), engine, store)
+	assert.Len(t, store.stored, 1)
+	assert.Len(t, store.stored[0], len(intervals))
+	for _, b := range store.stored[0] {
+		assert.Equal(t, float64(10), b.volume)
+		assert.Equal(t, float64(4), b.close)
+	}
+}
+
+func TestParseListingIds(t *testing.T) {
+	listingIds, err := parseListingIds(" 1,2, 3,")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, listingIds)
+
+	_, err = parseListingIds("1,a")
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/bar-service/api/barservice"
+	"time"
+)
+
+// sqlBarStore persists bars in the marketdata.bars table, a bar is identified by its listing, interval length in
+// seconds and start time.
+type sqlBarStore struct {
+	db *sql.DB
+}
+
+func newSqlBarStore(driverName string, dbConnString string) (*sqlBarStore, error) {
+	db, err := sql.Open(driverName, dbConnString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &sqlBarStore{db: db}, nil
+}
+
+func (s *sqlBarStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqlBarStore) storeBars(ctx context.Context, bars []bar) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	for _, b := range bars {
+		_, err := tx.ExecContext(ctx, `INSERT INTO marketdata.bars
+			(listing_id, interval_secs, start_time, open, high, low, close, volume)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (listing_id, interval_secs, start_time)
+			DO UPDATE SET open = EXCLUDED.open, high = EXCLUDED.high, low = EXCLUDED.low, close = EXCLUDED.close,
+			volume = EXCLUDED.volume`,
+			b.listingId, int64(intervals[b.interval]/time.Second), b.start, b.open, b.high, b.low, b.close, b.volume)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to store bar: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit bars: %w", err)
+	}
+
+	return nil
+}
+
+// getBars returns the bars of the listing and interval starting in [from, to) in start time order.
+func (s *sqlBarStore) getBars(ctx context.Context, key barKey, from time.Time, to time.Time) ([]bar, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT start_time, open, high, low, close, volume FROM marketdata.bars
+		WHERE listing_id = $1 AND interval_secs = $2 AND start_time >= $3 AND start_time < $4 ORDER BY start_time`,
+		key.listingId, int64(intervals[key.interval]/time.Second), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bars: %w", err)
+	}
+	defer rows.Close()
+
+	var result []bar
+	for rows.Next() {
+		b := bar{barKey: key}
+		if err := rows.Scan(&b.start, &b.open, &b.high, &b.low, &b.close, &b.volume); err != nil {
+			return nil, fmt.Errorf("failed to scan bar: %w", err)
+		}
+		b.start = b.start.UTC()
+		result = append(result, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bars: %w", err)
+	}
+
+	return result, nil
+}
+
+// getCurrentBars returns the stored bars of every listing and interval whose interval includes the given time.
+func (s *sqlBarStore) getCurrentBars(ctx context.Context, now time.Time) ([]bar, error) {
+	var result []bar
+	for interval, duration := range intervals {
+		rows, err := s.db.QueryContext(ctx, `SELECT listing_id, open, high, low, close, volume FROM marketdata.bars
+			WHERE interval_secs = $1 AND start_time = $2`, int64(duration/time.Second), now.UTC().Truncate(duration))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query current bars: %w", err)
+		}
+
+		for rows.Next() {
+			b := bar{barKey: barKey{interval: interval}, start: now.UTC().Truncate(duration)}
+			if err := rows.Scan(&b.listingId, &b.open, &b.high, &b.low, &b.close, &b.volume); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan bar: %w", err)
+			}
+			result = append(result, b)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read current bars: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// getListingIds returns the listings that have a bar of the interval starting at or after the given time.
+func (s *sqlBarStore) getListingIds(ctx context.Context, interval api.BarInterval, since time.Time) ([]int32, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT listing_id FROM marketdata.bars
+		WHERE interval_secs = $1 AND start_time >= $2`, int64(intervals[interval]/time.Second), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query listing ids: %w", err)
+	}
+	defer rows.Close()
+
+	var result []int32
+	for rows.Next() {
+		var listingId int32
+		if err := rows.Scan(&listingId); err != nil {
+			return nil, fmt.Errorf("failed to scan listing id: %w", err)
+		}
+		result = append(result, listingId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read listing ids: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/quickfixgo/quickfix"
+	"log/slog"
+)
+
+// dropCopyApplication is the quickfix application of the drop copy sessions, the sessions are send only and inbound
+// application messages are rejected as unsupported.
+type dropCopyApplication struct {
+	inboundRouter *quickfix.MessageRouter
+}
+
+func newDropCopyApplication() *dropCopyApplication {
+	return &dropCopyApplication{inboundRouter: quickfix.NewMessageRouter()}
+}
+
+func logSessionMsg(sessionID quickfix.SessionID, msg string) {
+	slog.Info(msg, "sessionID", sessionID.String())
+}
+
+// Notification of a session begin created.
+func (a *dropCopyApplication) OnCreate(sessionID quickfix.SessionID) {
+	logSessionMsg(sessionID, "created")
+}
+
+// Notification of a session successfully logging on.
+func (a *dropCopyApplication) OnLogon(sessionID quickfix.SessionID) {
+	logSessionMsg(sessionID, "logon received")
+}
+
+// Notification of a session logging off or disconnecting.
+func (a *dropCopyApplication) OnLogout(sessionID quickfix.SessionID) {
+	logSessionMsg(sessionID, "logout received")
+}
+
+// Notification of admin message being sent to target.
+func (a *dropCopyApplication) ToAdmin(message *quickfix.Message, sessionID quickfix.SessionID) {
+}
+
+// Notification of app message being sent to target.
+func (a *dropCopyApplication) ToApp(message *quickfix.Message, sessionID quickfix.SessionID) error {
+	return nil
+}
+
+// Notification of admin message being received from target.
+func (a *dropCopyApplication) FromAdmin(message *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+
+// Notification of app message being received from target.
+func (a *dropCopyApplication) FromApp(message *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	return a.inboundRouter.Route(message, sessionID)
+}
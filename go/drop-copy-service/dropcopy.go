@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/staticdata"
+	"github.com/quickfixgo/quickfix"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// orderUpdate is an order update read from the orders topic.
+type orderUpdate struct {
+	offset    int64
+	order     *model.Order
+	writeTime time.Time
+}
+
+type orderUpdateSource interface {
+	// readOrderUpdates returns the order updates at the given offsets of the orders topic keyed by offset.
+	readOrderUpdates(ctx context.Context, offsets []int64) (map[int64]orderUpdate, error)
+}
+
+// dropCopy sends an execution report to every drop copy session for each change to an order.  The offset of the last
+// order update processed is persisted, on restart the updates up to and including that offset are used only to restore
+// the state of the orders, when there is no persisted offset the updates written before the drop copy started are
+// treated as history.
+type dropCopy struct {
+	sessions       []quickfix.SessionID
+	history        orderUpdateSource
+	getListing     func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult)
+	send           func(msg quickfix.Messagable, sessionID quickfix.SessionID) error
+	lastOffsetPath string
+	startTime      time.Time
+}
+
+func newDropCopy(sessions []quickfix.SessionID, history orderUpdateSource,
+	getListing func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult),
+	send func(msg quickfix.Messagable, sessionID quickfix.SessionID) error, lastOffsetPath string) *dropCopy {
+	return &dropCopy{
+		sessions:       sessions,
+		history:        history,
+		getListing:     getListing,
+		send:           send,
+		lastOffsetPath: lastOffsetPath,
+		startTime:      time.Now(),
+	}
+}
+
+// run sends the execution reports for the order updates until the context is cancelled or the updates channel is
+// closed.
+func (d *dropCopy) run(ctx context.Context, updates <-chan orderUpdate) error {
+
+	lastOffset, hasLastOffset, err := d.loadLastOffset()
+	if err != nil {
+		return err
+	}
+
+	if hasLastOffset {
+		slog.Info("restoring order state up to last processed offset", "offset", lastOffset)
+	} else {
+		slog.Info("no last processed offset, restoring order state from updates written before start",
+			"startTime", d.startTime)
+	}
+
+	orders := map[string]*model.Order{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return errors.New("order updates channel closed")
+			}
+
+			previous := orders[update.order.Id]
+			orders[update.order.Id] = update.order
+
+			if hasLastOffset && update.offset <= lastOffset {
+				continue
+			}
+
+			if !hasLastOffset && update.writeTime.Before(d.startTime) {
+				continue
+			}
+
+			reports := getExecutionReports(update.offset, previous, update.order)
+			if len(reports) > 0 {
+				listing, err := d.getListingSync(ctx, update.order.ListingId)
+				if err != nil {
+					return err
+				}
+
+				for _, report := range reports {
+					d.sendToSessions(report, listing, update.writeTime)
+				}
+			}
+
+			if err := d.storeLastOffset(update.offset); err != nil {
+				return err
+			}
+			lastOffset = update.offset
+			hasLastOffset = true
+		}
+	}
+}
+
+func (d *dropCopy) sendToSessions(report executionReport, listing *model.Listing, transactTime time.Time) {
+	for _, sessionID := range d.sessions {
+		msg, err := report.toMessage(listing, transactTime)
+		if err != nil {
+			slog.Error("failed to create execution report", "orderId", report.order.Id, "execId", report.execId,
+				"error", err)
+			return
+		}
+
+		// reports are persisted and sent on logon if the session is not logged on
+		if err := d.send(msg, sessionID); err != nil {
+			slog.Error("failed to send execution report", "sessionID", sessionID.String(), "orderId",
+				report.order.Id, "execId", report.execId, "error", err)
+		}
+	}
+}
+
+func (d *dropCopy) getListingSync(ctx context.Context, listingId int32) (*model.Listing, error) {
+	resultChan := make(chan staticdata.ListingResult, 1)
+	d.getListing(ctx, listingId, resultChan)
+	result := <-resultChan
+	if result.Err != nil {
+		return nil, fmt.Errorf("failed to get listing %v: %w", listingId, result.Err)
+	}
+
+	return result.Listing, nil
+}
+
+// getExecutionReportMessages rebuilds execution reports from the order updates in the orders topic.
+func (d *dropCopy) getExecutionReportMessages(refs []reportRef) (map[string]*quickfix.Message, error) {
+	ctx := context.Background()
+
+	offsetSet := map[int64]bool{}
+	for _, ref := range refs {
+		offset, _, err := parseExecId(ref.execId)
+		if err != nil {
+			return nil, err
+		}
+		offsetSet[offset] = true
+	}
+
+	offsets := make([]int64, 0, len(offsetSet))
+	for offset := range offsetSet {
+		offsets = append(offsets, offset)
+	}
+
+	updates, err := d.history.readOrderUpdates(ctx, offsets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order updates: %w", err)
+	}
+
+	result := map[string]*quickfix.Message{}
+	for _, ref := range refs {
+		offset, _, _ := parseExecId(ref.execId)
+		update, ok := updates[offset]
+		if !ok {
+			continue
+		}
+
+		listing, err := d.getListingSync(ctx, update.order.ListingId)
+		if err != nil {
+			return nil, err
+		}
+
+		report := executionReport{execId: ref.execId, execType: ref.execType, order: update.order}
+		msg, err := report.toMessage(listing, update.writeTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create execution report %v: %w", ref.execId, err)
+		}
+
+		result[ref.execId] = msg
+	}
+
+	return result, nil
+}
+
+func (d *dropCopy) loadLastOffset() (int64, bool, error) {
+	offsetBytes, err := os.ReadFile(d.lastOffsetPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read last offset file %v: %w", d.lastOffsetPath, err)
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(offsetBytes)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse last offset file %v: %w", d.lastOffsetPath, err)
+	}
+
+	return offset, true, nil
+}
+
+// storeLastOffset writes the offset to a temporary file that then replaces the last offset file so that the file is
+// never partially written.
+func (d *dropCopy) storeLastOffset(offset int64) error {
+	tmpPath := filepath.Join(filepath.Dir(d.lastOffsetPath), "."+filepath.Base(d.lastOffsetPath)+".tmp")
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatInt(offset, 10)), 0644); err != nil {
+		return fmt.Errorf("failed to write last offset file %v: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, d.lastOffsetPath); err != nil {
+		return fmt.Errorf("failed to replace last offset file %v: %w", d.lastOffsetPath, err)
+	}
+
+	return nil
+}
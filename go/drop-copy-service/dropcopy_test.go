@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/staticdata"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type sentMessage struct {
+	sessionID quickfix.SessionID
+	execId    string
+	execType  enum.ExecType
+}
+
+type testOrderHistory struct {
+	updates map[int64]orderUpdate
+}
+
+func (h *testOrderHistory) readOrderUpdates(_ context.Context, offsets []int64) (map[int64]orderUpdate, error) {
+	result := map[int64]orderUpdate{}
+	for _, offset := range offsets {
+		if update, ok := h.updates[offset]; ok {
+			result[offset] = update
+		}
+	}
+	return result, nil
+}
+
+func getTestListing(_ context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+	result <- staticdata.ListingResult{Listing: &model.Listing{Id: listingId, MarketSymbol: "AAPL"}}
+}
+
+func newTestDropCopy(t *testing.T, lastOffsetPath string, history orderUpdateSource) (*dropCopy, *[]sentMessage) {
+	sessions := []quickfix.SessionID{
+		{BeginString: enum.BeginStringFIX44, SenderCompID: "OTPDROPCOPY", TargetCompID: "MO"},
+		{BeginString: enum.BeginStringFIXT11, SenderCompID: "OTPDROPCOPY", TargetCompID: "RISK"},
+	}
+
+	var sent []sentMessage
+	send := func(m quickfix.Messagable, sessionID quickfix.SessionID) error {
+		msg := m.ToMessage()
+		execId, err := msg.Body.GetString(tag.ExecID)
+		assert.NoError(t, err)
+		execType, err := msg.Body.GetString(tag.ExecType)
+		assert.NoError(t, err)
+		sent = append(sent, sentMessage{sessionID: sessionID, execId: execId, execType: enum.ExecType(execType)})
+		return nil
+	}
+
+	return newDropCopy(sessions, history, getTestListing, send, lastOffsetPath), &sent
+}
+
+func runToCompletion(t *testing.T, dc *dropCopy, updates []orderUpdate) {
+	updatesChan := make(chan orderUpdate, len(updates))
+	for _, update := range updates {
+		updatesChan <- update
+	}
+	close(updatesChan)
+
+	assert.Error(t, dc.run(context.Background(), updatesChan))
+}
+
+func testOrder(status model.OrderStatus, lastExecId string) *model.Order {
+	return &model.Order{Id: "o1", ListingId: 1, Side: model.Side_BUY, Status: status,
+		Quantity: &model.Decimal64{Mantissa: 10}, Price: &model.Decimal64{Mantissa: 100}, LastExecId: lastExecId}
+}
+
+func TestDropCopySendsReportsToAllSessions(t *testing.T) {
+	lastOffsetPath := filepath.Join(t.TempDir(), "lastoffset")
+	dc, sent := newTestDropCopy(t, lastOffsetPath, &testOrderHistory{})
+
+	now := time.Now()
+	runToCompletion(t, dc, []orderUpdate{
+		{offset: 1, order: testOrder(model.OrderStatus_NONE, ""), writeTime: now},
+		{offset: 2, order: testOrder(model.OrderStatus_LIVE, ""), writeTime: now},
+		{offset: 3, order: testOrder(model.OrderStatus_FILLED, "e1"), writeTime: now},
+	})
+
+	assert.Len(t, *sent, 4)
+	for i, sessionID := range dc.sessions {
+		assert.Equal(t, sentMessage{sessionID: sessionID, execId: "2.0", execType: enum.ExecType_NEW}, (*sent)[i])
+		assert.Equal(t, sentMessage{sessionID: sessionID, execId: "3.0", execType: enum.ExecType_TRADE}, (*sent)[2+i])
+	}
+
+	lastOffset, err := os.ReadFile(lastOffsetPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", string(lastOffset))
+}
+
+func TestDropCopyDoesNotSendHistoricUpdates(t *testing.T) {
+	dc, sent := newTestDropCopy(t, filepath.Join(t.TempDir(), "lastoffset"), &testOrderHistory{})
+
+	runToCompletion(t, dc, []orderUpdate{
+		{offset: 1, order: testOrder(model.OrderStatus_LIVE, ""), writeTime: dc.startTime.Add(-time.Minute)},
+		{offset: 2, order: testOrder(model.OrderStatus_CANCELLED, ""), writeTime: dc.startTime.Add(time.Second)},
+	})
+
+	assert.Len(t, *sent, 2)
+	for _, msg := range *sent {
+		assert.Equal(t, "2.0", msg.execId)
+		assert.Equal(t, enum.ExecType_CANCELED, msg.execType)
+	}
+}
+
+func TestDropCopyResumesFromLastOffset(t *testing.T) {
+	lastOffsetPath := filepath.Join(t.TempDir(), "lastoffset")
+	assert.NoError(t, os.WriteFile(lastOffsetPath, []byte("2"), 0644))
+
+	dc, sent := newTestDropCopy(t, lastOffsetPath, &testOrderHistory{})
+
+	// updates that were processed before the restart are not resent even though they were written after the start
+	future := time.Now().Add(time.Hour)
+	runToCompletion(t, dc, []orderUpdate{
+		{offset: 1, order: testOrder(model.OrderStatus_LIVE, ""), writeTime: future},
+		{offset: 2, order: testOrder(model.OrderStatus_LIVE, "e1"), writeTime: future},
+		{offset: 3, order: testOrder(model.OrderStatus_LIVE, "e2"), writeTime: future},
+	})
+
+	assert.Len(t, *sent, 2)
+	for _, msg := range *sent {
+		assert.Equal(t, "3.0", msg.execId)
+		assert.Equal(t, enum.ExecType_TRADE, msg.execType)
+	}
+}
+
+func TestDropCopyRebuildsExecutionReports(t *testing.T) {
+	now := time.Now()
+	history := &testOrderHistory{updates: map[int64]orderUpdate{
+		3: {offset: 3, order: testOrder(model.OrderStatus_FILLED, "e1"), writeTime: now},
+	}}
+
+	dc, _ := newTestDropCopy(t, filepath.Join(t.TempDir(), "lastoffset"), history)
+
+	messages, err := dc.getExecutionReportMessages([]reportRef{
+		{execId: "3.0", execType: enum.ExecType_NEW},
+		{execId: "3.1", execType: enum.ExecType_TRADE},
+		{execId: "4.0", execType: enum.ExecType_CANCELED},
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, messages, 2)
+	execType, err := messages["3.1"].Body.GetString(tag.ExecType)
+	assert.NoError(t, err)
+	assert.Equal(t, string(enum.ExecType_TRADE), execType)
+
+	secondaryExecId, err := messages["3.1"].Body.GetString(tag.SecondaryExecID)
+	assert.NoError(t, err)
+	assert.Equal(t, "e1", secondaryExecId)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// executionReport is an execution report derived from an order update read from the orders topic, the exec id
+// identifies the update's offset in the topic and the report's index amongst the reports derived from the update so
+// that the report can be rebuilt from the topic when a resend is requested.
+type executionReport struct {
+	execId   string
+	execType enum.ExecType
+	order    *model.Order
+}
+
+func newExecId(offset int64, index int) string {
+	return fmt.Sprintf("%d.%d", offset, index)
+}
+
+func parseExecId(execId string) (offset int64, index int, err error) {
+	offsetStr, indexStr, ok := strings.Cut(execId, ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid exec id %v", execId)
+	}
+
+	if offset, err = strconv.ParseInt(offsetStr, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid exec id offset %v: %w", execId, err)
+	}
+
+	if index, err = strconv.Atoi(indexStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid exec id index %v: %w", execId, err)
+	}
+
+	return offset, index, nil
+}
+
+// getExecutionReports returns the execution reports for the changes between the previous and current versions of an
+// order, previous is nil for a new order.  An order that is accepted is reported as new, a change to the quantity or
+// price of a live order as replaced, each new execution as a trade and a cancellation as cancelled.
+func getExecutionReports(offset int64, previous *model.Order, current *model.Order) []executionReport {
+
+	previousStatus := model.OrderStatus_NONE
+	var previousLastExecId string
+	if previous != nil {
+		previousStatus = previous.Status
+		previousLastExecId = previous.LastExecId
+	}
+
+	var execTypes []enum.ExecType
+
+	if previousStatus == model.OrderStatus_NONE &&
+		(current.Status == model.OrderStatus_LIVE || current.Status == model.OrderStatus_FILLED) {
+		execTypes = append(execTypes, enum.ExecType_NEW)
+	}
+
+	if previous != nil && previousStatus == model.OrderStatus_LIVE && current.Status == model.OrderStatus_LIVE &&
+		(!decimalsEqual(previous.Quantity, current.Quantity) || !decimalsEqual(previous.Price, current.Price)) {
+		execTypes = append(execTypes, enum.ExecType_REPLACED)
+	}
+
+	if current.LastExecId != "" && current.LastExecId != previousLastExecId {
+		execTypes = append(execTypes, enum.ExecType_TRADE)
+	}
+
+	if current.Status == model.OrderStatus_CANCELLED && previousStatus != model.OrderStatus_CANCELLED {
+		execTypes = append(execTypes, enum.ExecType_CANCELED)
+	}
+
+	var reports []executionReport
+	for i, execType := range execTypes {
+		reports = append(reports, executionReport{execId: newExecId(offset, i), execType: execType, order: current})
+	}
+
+	return reports
+}
+
+func decimalsEqual(d1 *model.Decimal64, d2 *model.Decimal64) bool {
+	if d1 == nil || d2 == nil {
+		return d1 == d2
+	}
+
+	return d1.AsDecimal().Equal(d2.AsDecimal())
+}
+
+func getOrdStatus(order *model.Order) enum.OrdStatus {
+	switch order.Status {
+	case model.OrderStatus_LIVE:
+		if order.GetTradedQuantity() != nil && order.TradedQuantity.AsDecimal().GreaterThan(decimal.Zero) {
+			return enum.OrdStatus_PARTIALLY_FILLED
+		}
+		return enum.OrdStatus_NEW
+	case model.OrderStatus_FILLED:
+		return enum.OrdStatus_FILLED
+	case model.OrderStatus_CANCELLED:
+		return enum.OrdStatus_CANCELED
+	default:
+		return enum.OrdStatus_PENDING_NEW
+	}
+}
+
+func getFixSide(side model.Side) (enum.Side, error) {
+	switch side {
+	case model.Side_BUY:
+		return enum.Side_BUY, nil
+	case model.Side_SELL:
+		return enum.Side_SELL, nil
+	default:
+		return "", fmt.Errorf("side %v not supported", side)
+	}
+}
+
+func toFixDecimal(d *model.Decimal64) (decimal.Decimal, int32) {
+	if d == nil {
+		return decimal.Zero, 0
+	}
+
+	var scale int32 = 0
+	if d.Exponent < 0 {
+		scale = -d.Exponent
+	}
+
+	return decimal.New(d.GetMantissa(), d.GetExponent()), scale
+}
+
+// toMessage returns the execution report as a fix message, the message body is common to FIX 4.4 and FIX 5.0.  The
+// venue's exec id of a trade is set as the secondary exec id.
+func (r executionReport) toMessage(listing *model.Listing, transactTime time.Time) (*quickfix.Message, error) {
+	order := r.order
+
+	side, err := getFixSide(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_EXECUTION_REPORT))
+
+	msg.Body.Set(field.NewOrderID(order.Id))
+	msg.Body.Set(field.NewExecID(r.execId))
+	msg.Body.Set(field.NewExecType(r.execType))
+	msg.Body.Set(field.NewOrdStatus(getOrdStatus(order)))
+	msg.Body.Set(field.NewSide(side))
+	msg.Body.Set(field.NewSymbol(listing.MarketSymbol))
+	if listing.Market != nil && listing.Market.Mic != "" {
+		msg.Body.Set(field.NewSecurityExchange(listing.Market.Mic))
+	}
+	if order.OriginatorRef != "" {
+		msg.Body.Set(field.NewClOrdID(order.OriginatorRef))
+	}
+	if order.RootOriginatorId != "" {
+		msg.Body.Set(field.NewAccount(order.RootOriginatorId))
+	}
+	if order.Destination != "" {
+		msg.Body.Set(field.NewExDestination(enum.ExDestination(order.Destination)))
+	}
+
+	msg.Body.Set(field.NewOrderQty(toFixDecimal(order.Quantity)))
+	if order.Price != nil {
+		msg.Body.Set(field.NewPrice(toFixDecimal(order.Price)))
+	}
+	msg.Body.Set(field.NewLeavesQty(toFixDecimal(getLeavesQuantity(order))))
+	msg.Body.Set(field.NewCumQty(toFixDecimal(order.TradedQuantity)))
+	msg.Body.Set(field.NewAvgPx(toFixDecimal(order.AvgTradePrice)))
+
+	if r.execType == enum.ExecType_TRADE {
+		msg.Body.Set(field.NewLastQty(toFixDecimal(order.LastExecQuantity)))
+		msg.Body.Set(field.NewLastPx(toFixDecimal(order.LastExecPrice)))
+		msg.Body.Set(field.NewSecondaryExecID(order.LastExecId))
+	}
+
+	if order.ErrorMessage != "" {
+		msg.Body.Set(field.NewText(order.ErrorMessage))
+	}
+
+	msg.Body.Set(field.NewTransactTime(transactTime))
+
+	return msg, nil
+}
+
+// getLeavesQuantity returns the quantity open for further execution, zero if the order is done.
+func getLeavesQuantity(order *model.Order) *model.Decimal64 {
+	if order.IsTerminalState() {
+		return &model.Decimal64{}
+	}
+
+	return order.RemainingQuantity
+}
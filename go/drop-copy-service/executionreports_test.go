@@ -0,0 +1,139 @@
+package main
+
+import (
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetExecutionReports(t *testing.T) {
+
+	newOrder := func(status model.OrderStatus, qty int64, price int64, lastExecId string) *model.Order {
+		return &model.Order{Id: "o1", Status: status, Quantity: &model.Decimal64{Mantissa: qty},
+			Price: &model.Decimal64{Mantissa: price}, LastExecId: lastExecId}
+	}
+
+	tests := []struct {
+		name      string
+		previous  *model.Order
+		current   *model.Order
+		execTypes []enum.ExecType
+	}{
+		{"pending new", nil, newOrder(model.OrderStatus_NONE, 10, 100, ""), nil},
+		{"new", nil, newOrder(model.OrderStatus_LIVE, 10, 100, ""), []enum.ExecType{enum.ExecType_NEW}},
+		{"new after pending", newOrder(model.OrderStatus_NONE, 10, 100, ""), newOrder(model.OrderStatus_LIVE, 10, 100, ""),
+			[]enum.ExecType{enum.ExecType_NEW}},
+		{"new and filled", nil, newOrder(model.OrderStatus_FILLED, 10, 100, "e1"),
+			[]enum.ExecType{enum.ExecType_NEW, enum.ExecType_TRADE}},
+		{"no change", newOrder(model.OrderStatus_LIVE, 10, 100, ""), newOrder(model.OrderStatus_LIVE, 10, 100, ""), nil},
+		{"replaced quantity", newOrder(model.OrderStatus_LIVE, 10, 100, ""), newOrder(model.OrderStatus_LIVE, 20, 100, ""),
+			[]enum.ExecType{enum.ExecType_REPLACED}},
+		{"replaced price", newOrder(model.OrderStatus_LIVE, 10, 100, ""), newOrder(model.OrderStatus_LIVE, 10, 101, ""),
+			[]enum.ExecType{enum.ExecType_REPLACED}},
+		{"trade", newOrder(model.OrderStatus_LIVE, 10, 100, "e1"), newOrder(model.OrderStatus_LIVE, 10, 100, "e2"),
+			[]enum.ExecType{enum.ExecType_TRADE}},
+		{"filled", newOrder(model.OrderStatus_LIVE, 10, 100, "e1"), newOrder(model.OrderStatus_FILLED, 10, 100, "e2"),
+			[]enum.ExecType{enum.ExecType_TRADE}},
+		{"cancelled", newOrder(model.OrderStatus_LIVE, 10, 100, "e1"), newOrder(model.OrderStatus_CANCELLED, 10, 100, "e1"),
+			[]enum.ExecType{enum.ExecType_CANCELED}},
+		{"cancelled before acceptance", newOrder(model.OrderStatus_NONE, 10, 100, ""),
+			newOrder(model.OrderStatus_CANCELLED, 10, 100, ""), []enum.ExecType{enum.ExecType_CANCELED}},
+		{"already cancelled", newOrder(model.OrderStatus_CANCELLED, 10, 100, ""),
+			newOrder(model.OrderStatus_CANCELLED, 10, 100, ""), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reports := getExecutionReports(7, test.previous, test.current)
+
+			var execTypes []enum.ExecType
+			for i, report := range reports {
+				execTypes = append(execTypes, report.execType)
+				assert.Equal(t, newExecId(7, i), report.execId)
+				assert.Equal(t, test.current, report.order)
+			}
+
+			assert.Equal(t, test.execTypes, execTypes)
+		})
+	}
+}
+
+func TestParseExecId(t *testing.T) {
+	offset, index, err := parseExecId(newExecId(12345, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), offset)
+	assert.Equal(t, 2, index)
+
+	for _, execId := range []string{"", "12345", "a.1", "1.b"} {
+		_, _, err = parseExecId(execId)
+		assert.Error(t, err, execId)
+	}
+}
+
+func TestTradeToMessage(t *testing.T) {
+	order := &model.Order{
+		Id:                "o1",
+		Side:              model.Side_SELL,
+		Quantity:          &model.Decimal64{Mantissa: 10},
+		Price:             &model.Decimal64{Mantissa: 1005, Exponent: -1},
+		RemainingQuantity: &model.Decimal64{Mantissa: 6},
+		TradedQuantity:    &model.Decimal64{Mantissa: 4},
+		AvgTradePrice:     &model.Decimal64{Mantissa: 1005, Exponent: -1},
+		LastExecQuantity:  &model.Decimal64{Mantissa: 4},
+		LastExecPrice:     &model.Decimal64{Mantissa: 1005, Exponent: -1},
+		LastExecId:        "venue-e1",
+		Status:            model.OrderStatus_LIVE,
+		OriginatorRef:     "clordid1",
+		RootOriginatorId:  "desk1",
+		Destination:       "XNAS",
+	}
+
+	listing := &model.Listing{MarketSymbol: "AAPL", Market: &model.Market{Mic: "XNAS"}}
+
+	report := executionReport{execId: "3.1", execType: enum.ExecType_TRADE, order: order}
+	msg, err := report.toMessage(listing, time.Now())
+	assert.NoError(t, err)
+
+	getString := func(fieldTag quickfix.Tag) string {
+		value, err := msg.Body.GetString(fieldTag)
+		assert.NoError(t, err)
+		return value
+	}
+
+	assert.Equal(t, "o1", getString(tag.OrderID))
+	assert.Equal(t, "3.1", getString(tag.ExecID))
+	assert.Equal(t, string(enum.ExecType_TRADE), getString(tag.ExecType))
+	assert.Equal(t, string(enum.OrdStatus_PARTIALLY_FILLED), getString(tag.OrdStatus))
+	assert.Equal(t, string(enum.Side_SELL), getString(tag.Side))
+	assert.Equal(t, "AAPL", getString(tag.Symbol))
+	assert.Equal(t, "XNAS", getString(tag.SecurityExchange))
+	assert.Equal(t, "clordid1", getString(tag.ClOrdID))
+	assert.Equal(t, "desk1", getString(tag.Account))
+	assert.Equal(t, "100.5", getString(tag.Price))
+	assert.Equal(t, "6", getString(tag.LeavesQty))
+	assert.Equal(t, "4", getString(tag.CumQty))
+	assert.Equal(t, "4", getString(tag.LastQty))
+	assert.Equal(t, "100.5", getString(tag.LastPx))
+	assert.Equal(t, "venue-e1", getString(tag.SecondaryExecID))
+}
+
+func TestLeavesQuantityOfDoneOrderIsZero(t *testing.T) {
+	order := &model.Order{Side: model.Side_BUY, Status: model.OrderStatus_CANCELLED,
+		Quantity: &model.Decimal64{Mantissa: 10}, RemainingQuantity: &model.Decimal64{Mantissa: 10}}
+
+	report := executionReport{execId: "1.0", execType: enum.ExecType_CANCELED, order: order}
+	msg, err := report.toMessage(&model.Listing{MarketSymbol: "AAPL"}, time.Now())
+	assert.NoError(t, err)
+
+	leavesQty, err := msg.Body.GetString(tag.LeavesQty)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", leavesQty)
+
+	ordStatus, err := msg.Body.GetString(tag.OrdStatus)
+	assert.NoError(t, err)
+	assert.Equal(t, string(enum.OrdStatus_CANCELED), ordStatus)
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/tag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// reportRef identifies an execution report that has been sent to a drop copy session.
+type reportRef struct {
+	execId   string
+	execType enum.ExecType
+}
+
+type executionReportSource interface {
+	// getExecutionReportMessages rebuilds the messages of the given execution reports, the messages of reports that
+	// can no longer be rebuilt, e.g. because the order update is beyond the orders topic's retention period, are
+	// omitted from the result.
+	getExecutionReportMessages(refs []reportRef) (map[string]*quickfix.Message, error)
+}
+
+type sentReport struct {
+	reportRef
+	sendingTime string
+}
+
+// kafkaHistoryStore is a quickfix message store that persists the session's sequence numbers using a quickfix file
+// store but rather than persisting the messages sent it persists an index of the execution reports sent by sequence
+// number.  On a resend request the execution reports are rebuilt from the order updates in the orders topic, other
+// messages are gap filled.
+type kafkaHistoryStore struct {
+	quickfix.MessageStore
+	sessionID quickfix.SessionID
+	reports   executionReportSource
+
+	mux       sync.Mutex
+	indexPath string
+	indexFile *os.File
+	index     map[int]sentReport
+}
+
+func newKafkaHistoryStore(sessionID quickfix.SessionID, seqNumStore quickfix.MessageStore, indexPath string,
+	reports executionReportSource) (*kafkaHistoryStore, error) {
+
+	store := &kafkaHistoryStore{
+		MessageStore: seqNumStore,
+		sessionID:    sessionID,
+		reports:      reports,
+		indexPath:    indexPath,
+	}
+
+	if err := store.loadIndex(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (s *kafkaHistoryStore) loadIndex() error {
+	if s.indexFile != nil {
+		if err := s.indexFile.Close(); err != nil {
+			return fmt.Errorf("failed to close index file %v: %w", s.indexPath, err)
+		}
+	}
+
+	indexFile, err := os.OpenFile(s.indexPath, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open index file %v: %w", s.indexPath, err)
+	}
+
+	index := map[int]sentReport{}
+	scanner := bufio.NewScanner(indexFile)
+	for scanner.Scan() {
+		seqNum, report, err := parseIndexEntry(scanner.Text())
+		if err != nil {
+			_ = indexFile.Close()
+			return fmt.Errorf("failed to parse index file %v: %w", s.indexPath, err)
+		}
+		index[seqNum] = report
+	}
+
+	if err := scanner.Err(); err != nil {
+		_ = indexFile.Close()
+		return fmt.Errorf("failed to read index file %v: %w", s.indexPath, err)
+	}
+
+	s.indexFile = indexFile
+	s.index = index
+
+	return nil
+}
+
+func formatIndexEntry(seqNum int, report sentReport) string {
+	return fmt.Sprintf("%d,%s,%s,%s\n", seqNum, report.execId, report.execType, report.sendingTime)
+}
+
+func parseIndexEntry(entry string) (int, sentReport, error) {
+	fields := strings.Split(entry, ",")
+	if len(fields) != 4 {
+		return 0, sentReport{}, fmt.Errorf("invalid index entry %v", entry)
+	}
+
+	seqNum, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, sentReport{}, fmt.Errorf("invalid index entry sequence number %v: %w", entry, err)
+	}
+
+	return seqNum, sentReport{
+		reportRef:   reportRef{execId: fields[1], execType: enum.ExecType(fields[2])},
+		sendingTime: fields[3],
+	}, nil
+}
+
+// SaveMessage adds sent execution reports to the index, other messages are not saved.
+func (s *kafkaHistoryStore) SaveMessage(seqNum int, msgBytes []byte) error {
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBuffer(msgBytes)); err != nil {
+		return fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	msgType, err := msg.Header.GetString(tag.MsgType)
+	if err != nil {
+		return fmt.Errorf("failed to get message type: %w", err)
+	}
+
+	if enum.MsgType(msgType) != enum.MsgType_EXECUTION_REPORT {
+		return nil
+	}
+
+	execId, err := msg.Body.GetString(tag.ExecID)
+	if err != nil {
+		return fmt.Errorf("failed to get exec id: %w", err)
+	}
+
+	execType, err := msg.Body.GetString(tag.ExecType)
+	if err != nil {
+		return fmt.Errorf("failed to get exec type: %w", err)
+	}
+
+	sendingTime, err := msg.Header.GetString(tag.SendingTime)
+	if err != nil {
+		return fmt.Errorf("failed to get sending time: %w", err)
+	}
+
+	report := sentReport{reportRef: reportRef{execId: execId, execType: enum.ExecType(execType)}, sendingTime: sendingTime}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, err := s.indexFile.WriteString(formatIndexEntry(seqNum, report)); err != nil {
+		return fmt.Errorf("failed to write to index file %v: %w", s.indexPath, err)
+	}
+
+	if err := s.indexFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync index file %v: %w", s.indexPath, err)
+	}
+
+	s.index[seqNum] = report
+
+	return nil
+}
+
+// GetMessages rebuilds the execution reports sent in the given sequence number range.
+func (s *kafkaHistoryStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	s.mux.Lock()
+	var seqNums []int
+	var refs []reportRef
+	sent := map[int]sentReport{}
+	for seqNum, report := range s.index {
+		if seqNum >= beginSeqNum && seqNum <= endSeqNum {
+			seqNums = append(seqNums, seqNum)
+			refs = append(refs, report.reportRef)
+			sent[seqNum] = report
+		}
+	}
+	s.mux.Unlock()
+
+	if len(seqNums) == 0 {
+		return nil, nil
+	}
+
+	sort.Ints(seqNums)
+
+	messages, err := s.reports.getExecutionReportMessages(refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution reports for resend: %w", err)
+	}
+
+	var result [][]byte
+	for _, seqNum := range seqNums {
+		report := sent[seqNum]
+		msg, ok := messages[report.execId]
+		if !ok {
+			slog.Warn("execution report no longer available for resend, it will be gap filled", "sessionID",
+				s.sessionID.String(), "seqNum", seqNum, "execId", report.execId)
+			continue
+		}
+
+		msg.Header.SetString(tag.BeginString, s.sessionID.BeginString)
+		msg.Header.SetString(tag.SenderCompID, s.sessionID.SenderCompID)
+		msg.Header.SetString(tag.TargetCompID, s.sessionID.TargetCompID)
+		msg.Header.SetInt(tag.MsgSeqNum, seqNum)
+		msg.Header.SetString(tag.SendingTime, report.sendingTime)
+
+		result = append(result, []byte(msg.String()))
+	}
+
+	return result, nil
+}
+
+func (s *kafkaHistoryStore) Reset() error {
+	if err := s.MessageStore.Reset(); err != nil {
+		return err
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if err := s.indexFile.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate index file %v: %w", s.indexPath, err)
+	}
+
+	s.index = map[int]sentReport{}
+
+	return nil
+}
+
+func (s *kafkaHistoryStore) Refresh() error {
+	if err := s.MessageStore.Refresh(); err != nil {
+		return err
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.loadIndex()
+}
+
+func (s *kafkaHistoryStore) Close() error {
+	if err := s.MessageStore.Close(); err != nil {
+		return err
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.indexFile == nil {
+		return nil
+	}
+
+	err := s.indexFile.Close()
+	s.indexFile = nil
+
+	return err
+}
+
+type kafkaHistoryStoreFactory struct {
+	seqNumStoreFactory quickfix.MessageStoreFactory
+	indexDir           string
+	reports            executionReportSource
+}
+
+// newKafkaHistoryStoreFactory returns a factory of kafka history stores, the sequence numbers are persisted using the
+// given factory's stores and the indexes of sent execution reports are written to the index directory.
+func newKafkaHistoryStoreFactory(seqNumStoreFactory quickfix.MessageStoreFactory, indexDir string,
+	reports executionReportSource) *kafkaHistoryStoreFactory {
+	return &kafkaHistoryStoreFactory{seqNumStoreFactory: seqNumStoreFactory, indexDir: indexDir, reports: reports}
+}
+
+var nonFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9.]+`)
+
+func (f *kafkaHistoryStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	seqNumStore, err := f.seqNumStoreFactory.Create(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sequence number store: %w", err)
+	}
+
+	if err := os.MkdirAll(f.indexDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create index directory %v: %w", f.indexDir, err)
+	}
+
+	indexPath := filepath.Join(f.indexDir, nonFileNameChars.ReplaceAllString(sessionID.String(), "-")+".index")
+
+	return newKafkaHistoryStore(sessionID, seqNumStore, indexPath, f.reports)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testReportSource struct {
+	requested []reportRef
+	messages  map[string]*quickfix.Message
+}
+
+func (s *testReportSource) getExecutionReportMessages(refs []reportRef) (map[string]*quickfix.Message, error) {
+	s.requested = append(s.requested, refs...)
+	result := map[string]*quickfix.Message{}
+	for _, ref := range refs {
+		if msg, ok := s.messages[ref.execId]; ok {
+			result[ref.execId] = msg
+		}
+	}
+	return result, nil
+}
+
+func newTestReportMessage(t *testing.T, execId string, execType enum.ExecType) *quickfix.Message {
+	order := &model.Order{Id: "o1", Side: model.Side_BUY, Status: model.OrderStatus_LIVE,
+		Quantity: &model.Decimal64{Mantissa: 10}, RemainingQuantity: &model.Decimal64{Mantissa: 10}}
+	msg, err := executionReport{execId: execId, execType: execType, order: order}.toMessage(
+		&model.Listing{MarketSymbol: "AAPL"}, time.Now())
+	assert.NoError(t, err)
+	return msg
+}
+
+func toSentBytes(t *testing.T, sessionID quickfix.SessionID, seqNum int, msg *quickfix.Message,
+	sendingTime string) []byte {
+	msg.Header.SetString(tag.BeginString, sessionID.BeginString)
+	msg.Header.SetString(tag.SenderCompID, sessionID.SenderCompID)
+	msg.Header.SetString(tag.TargetCompID, sessionID.TargetCompID)
+	msg.Header.SetInt(tag.MsgSeqNum, seqNum)
+	msg.Header.SetString(tag.SendingTime, sendingTime)
+	return []byte(msg.String())
+}
+
+func parseTestMessage(t *testing.T, msgBytes []byte) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	assert.NoError(t, quickfix.ParseMessage(msg, bytes.NewBuffer(msgBytes)))
+	return msg
+}
+
+func TestKafkaHistoryStoreRebuildsSentExecutionReports(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: enum.BeginStringFIX44, SenderCompID: "OTPDROPCOPY", TargetCompID: "MO"}
+	seqNumStore, err := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	assert.NoError(t, err)
+
+	source := &testReportSource{messages: map[string]*quickfix.Message{
+		"5.0": newTestReportMessage(t, "5.0", enum.ExecType_NEW),
+	}}
+
+	indexPath := filepath.Join(t.TempDir(), "session.index")
+	store, err := newKafkaHistoryStore(sessionID, seqNumStore, indexPath, source)
+	assert.NoError(t, err)
+
+	heartbeat := quickfix.NewMessage()
+	heartbeat.Header.Set(field.NewMsgType(enum.MsgType_HEARTBEAT))
+	assert.NoError(t, store.SaveMessage(1, toSentBytes(t, sessionID, 1, heartbeat, "20240101-10:00:00.000")))
+	assert.NoError(t, store.SaveMessage(2, toSentBytes(t, sessionID, 2,
+		newTestReportMessage(t, "5.0", enum.ExecType_NEW), "20240101-10:00:01.000")))
+	assert.NoError(t, store.SaveMessage(3, toSentBytes(t, sessionID, 3,
+		newTestReportMessage(t, "6.0", enum.ExecType_CANCELED), "20240101-10:00:02.000")))
+	assert.NoError(t, store.Close())
+
+	// the index is restored on restart
+	seqNumStore, err = quickfix.NewMemoryStoreFactory().Create(sessionID)
+	assert.NoError(t, err)
+	store, err = newKafkaHistoryStore(sessionID, seqNumStore, indexPath, source)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	messages, err := store.GetMessages(1, 3)
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []reportRef{{execId: "5.0", execType: enum.ExecType_NEW},
+		{execId: "6.0", execType: enum.ExecType_CANCELED}}, source.requested)
+
+	// the heartbeat and the report that is no longer available are gap filled
+	if assert.Len(t, messages, 1) {
+		msg := parseTestMessage(t, messages[0])
+		seqNum, err := msg.Header.GetInt(tag.MsgSeqNum)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, seqNum)
+
+		sendingTime, err := msg.Header.GetString(tag.SendingTime)
+		assert.NoError(t, err)
+		assert.Equal(t, "20240101-10:00:01.000", sendingTime)
+
+		targetCompID, err := msg.Header.GetString(tag.TargetCompID)
+		assert.NoError(t, err)
+		assert.Equal(t, "MO", targetCompID)
+
+		execId, err := msg.Body.GetString(tag.ExecID)
+		assert.NoError(t, err)
+		assert.Equal(t, "5.0", execId)
+	}
+}
+
+func TestKafkaHistoryStoreResetClearsIndex(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: enum.BeginStringFIX44, SenderCompID: "OTPDROPCOPY", TargetCompID: "MO"}
+	seqNumStore, err := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	assert.NoError(t, err)
+
+	source := &testReportSource{}
+	indexPath := filepath.Join(t.TempDir(), "session.index")
+	store, err := newKafkaHistoryStore(sessionID, seqNumStore, indexPath, source)
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.SaveMessage(1, toSentBytes(t, sessionID, 1,
+		newTestReportMessage(t, "5.0", enum.ExecType_NEW), "20240101-10:00:01.000")))
+	assert.NoError(t, store.Reset())
+	assert.NoError(t, store.Refresh())
+
+	messages, err := store.GetMessages(1, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+	assert.Empty(t, source.requested)
+	assert.NoError(t, store.Close())
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// maxOffsetReadTime is the time after which an order update that is no longer in the orders topic is considered
+// unavailable, the kafka reader does not report an offset that is beyond the topic's retention as an error.
+const maxOffsetReadTime = 5 * time.Second
+
+// streamOrderUpdates returns a channel of all the order updates in the orders topic from the first available offset,
+// the channel is closed if the context is cancelled or an update cannot be read.
+func streamOrderUpdates(ctx context.Context, readerConfig kafka.ReaderConfig, bufferSize int) <-chan orderUpdate {
+	out := make(chan orderUpdate, bufferSize)
+
+	go func() {
+		defer close(out)
+		reader := kafka.NewReader(readerConfig)
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing kafka reader", "error", err)
+			}
+		}()
+
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("failed to read order update", "error", err)
+				}
+				return
+			}
+
+			order := &model.Order{}
+			if err = proto.Unmarshal(msg.Value, order); err != nil {
+				slog.Error("failed to unmarshal order", "offset", msg.Offset, "error", err)
+				return
+			}
+
+			select {
+			case out <- orderUpdate{offset: msg.Offset, order: order, writeTime: msg.Time}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+type kafkaOrderHistory struct {
+	readerConfig kafka.ReaderConfig
+}
+
+func newKafkaOrderHistory(readerConfig kafka.ReaderConfig) *kafkaOrderHistory {
+	return &kafkaOrderHistory{readerConfig: readerConfig}
+}
+
+func (k *kafkaOrderHistory) readOrderUpdates(ctx context.Context, offsets []int64) (map[int64]orderUpdate, error) {
+	sorted := append([]int64{}, offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	reader := kafka.NewReader(k.readerConfig)
+	defer func() {
+		if err := reader.Close(); err != nil {
+			slog.Error("error closing kafka reader", "error", err)
+		}
+	}()
+
+	result := map[int64]orderUpdate{}
+	nextOffset := int64(-1)
+	for _, offset := range sorted {
+		if offset != nextOffset {
+			if err := reader.SetOffset(offset); err != nil {
+				return nil, fmt.Errorf("failed to set reader offset to %v: %w", offset, err)
+			}
+		}
+
+		readCtx, cancel := context.WithTimeout(ctx, maxOffsetReadTime)
+		msg, err := reader.ReadMessage(readCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				slog.Warn("order update not available", "offset", offset)
+				nextOffset = -1
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to read order update at offset %v: %w", offset, err)
+		}
+
+		nextOffset = msg.Offset + 1
+		if msg.Offset != offset {
+			slog.Warn("order update not available", "offset", offset)
+			continue
+		}
+
+		order := &model.Order{}
+		if err = proto.Unmarshal(msg.Value, order); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal order at offset %v: %w", offset, err)
+		}
+
+		result[offset] = orderUpdate{offset: offset, order: order, writeTime: msg.Time}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	common "github.com/ettec/otp-common"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/orderstore"
+	"github.com/ettec/otp-common/staticdata"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+	senderCompID := bootstrap.GetOptionalEnvVar("FIX_SENDER_COMP_ID", "OTPDROPCOPY")
+	acceptPort := bootstrap.GetOptionalEnvVar("FIX_SOCKET_ACCEPT_PORT", "9878")
+	fileLogPath := bootstrap.GetEnvVar("FIX_LOG_FILE_PATH")
+	fileStorePath := bootstrap.GetEnvVar("FIX_FILE_STORE_PATH")
+
+	sessions, err := parseSessions(senderCompID, bootstrap.GetEnvVar("DROP_COPY_SESSIONS"))
+	if err != nil {
+		log.Panicf("failed to parse drop copy sessions: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sds, err := staticdata.NewStaticDataSource(ctx)
+	if err != nil {
+		log.Panicf("failed to create static data source:%v", err)
+	}
+
+	readerConfig := orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers)
+
+	dc := newDropCopy(sessions, newKafkaOrderHistory(readerConfig), sds.GetListing, quickfix.SendToTarget,
+		filepath.Join(fileStorePath, "lastoffset"))
+
+	fixConfig := getFixConfig(sessions, acceptPort, fileLogPath, fileStorePath)
+	slog.Info("Creating fix engine", "config", fixConfig)
+
+	appSettings, err := quickfix.ParseSettings(strings.NewReader(fixConfig))
+	if err != nil {
+		log.Panicf("failed parse config: %v", err)
+	}
+
+	storeFactory := newKafkaHistoryStoreFactory(quickfix.NewFileStoreFactory(appSettings), fileStorePath, dc)
+	logFactory, err := quickfix.NewFileLogFactory(appSettings)
+	if err != nil {
+		log.Panicf("failed to create logFactory: %v", err)
+	}
+
+	acceptor, err := quickfix.NewAcceptor(newDropCopyApplication(), storeFactory, appSettings, logFactory)
+	if err != nil {
+		log.Panicf("failed to create acceptor: %v", err)
+	}
+
+	if err = acceptor.Start(); err != nil {
+		log.Panicf("failed to start the fix engine: %v", err)
+	}
+	defer acceptor.Stop()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	slog.Info("Starting drop copy", "port", acceptPort, "sessions", len(sessions))
+
+	updates := streamOrderUpdates(ctx, readerConfig, bootstrap.GetOptionalIntEnvVar("ORDER_UPDATES_BUFFER_SIZE", 1000))
+	if err := dc.run(ctx, updates); err != nil {
+		log.Panicf("drop copy failed: %v", err)
+	}
+}
+
+// parseSessions parses a comma separated list of sessions of the form <beginString>:<targetCompID>, e.g.
+// "FIX.4.4:MIDOFFICE,FIXT.1.1:RISK".
+func parseSessions(senderCompID string, sessionsString string) ([]quickfix.SessionID, error) {
+	var sessions []quickfix.SessionID
+	for _, entry := range strings.Split(sessionsString, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		beginString, targetCompID, ok := strings.Cut(entry, ":")
+		if !ok || targetCompID == "" {
+			return nil, fmt.Errorf("invalid session %q, expected <beginString>:<targetCompID>", entry)
+		}
+
+		if beginString != enum.BeginStringFIX44 && beginString != enum.BeginStringFIXT11 {
+			return nil, fmt.Errorf("invalid session %q, begin string must be %v or %v", entry,
+				enum.BeginStringFIX44, enum.BeginStringFIXT11)
+		}
+
+		sessions = append(sessions, quickfix.SessionID{BeginString: beginString, SenderCompID: senderCompID,
+			TargetCompID: targetCompID})
+	}
+
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sessions configured")
+	}
+
+	return sessions, nil
+}
+
+func getFixConfig(sessions []quickfix.SessionID, acceptPort string, fileLogPath string, fileStorePath string) string {
+
+	if tproot, exists := os.LookupEnv("TELEPRESENCE_ROOT"); exists {
+		fileLogPath = tproot + fileLogPath
+		fileStorePath = tproot + fileStorePath
+	}
+
+	template :=
+		"[DEFAULT]\n" +
+			"ConnectionType=acceptor\n" +
+			"SocketAcceptPort=" + acceptPort + "\n" +
+			"FileStorePath=" + fileStorePath + "\n" +
+			"FileLogPath=" + fileLogPath + "\n" +
+			"StartTime=00:00:00\n" +
+			"EndTime=00:00:00\n" +
+			"HeartBtInt=20\n"
+
+	for _, session := range sessions {
+		template +=
+			"\n" +
+				"[SESSION]\n" +
+				"BeginString=" + session.BeginString + "\n" +
+				"SenderCompID=" + session.SenderCompID + "\n" +
+				"TargetCompID=" + session.TargetCompID + "\n"
+
+		if session.BeginString == enum.BeginStringFIXT11 {
+			template += "DefaultApplVerID=FIX.5.0SP2\n"
+		}
+	}
+
+	return template
+}
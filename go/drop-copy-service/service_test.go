@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/quickfixgo/quickfix"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSessions(t *testing.T) {
+	sessions, err := parseSessions("OTPDROPCOPY", "FIX.4.4:MIDOFFICE, FIXT.1.1:RISK")
+	assert.NoError(t, err)
+	assert.Equal(t, []quickfix.SessionID{
+		{BeginString: "FIX.4.4", SenderCompID: "OTPDROPCOPY", TargetCompID: "MIDOFFICE"},
+		{BeginString: "FIXT.1.1", SenderCompID: "OTPDROPCOPY", TargetCompID: "RISK"},
+	}, sessions)
+
+	for _, invalid := range []string{"", "FIX.4.4", "FIX.4.4:", "FIX.4.2:MIDOFFICE"} {
+		_, err := parseSessions("OTPDROPCOPY", invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,151 @@
+// Package executions records the executions applied to the orders of an execution venue.  The record is private to the
+// venue so it is kept in a compacted kafka topic owned by the venue rather than with the order on the shared orders
+// topic.
+package executions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Execution is an execution applied to an order, a reversed execution has been cancelled or corrected by the venue.
+type Execution struct {
+	Id       string          `json:"id"`
+	Price    model.Decimal64 `json:"price"`
+	Qty      model.Decimal64 `json:"qty"`
+	Reversed bool            `json:"reversed,omitempty"`
+}
+
+type Executions []*Execution
+
+// Find returns the execution with the given id or nil if the execution has not been applied to the order.
+func (e Executions) Find(id string) *Execution {
+	for _, execution := range e {
+		if execution.Id == id {
+			return execution
+		}
+	}
+
+	return nil
+}
+
+func (e Executions) copy() Executions {
+	var result Executions
+	for _, execution := range e {
+		c := *execution
+		result = append(result, &c)
+	}
+
+	return result
+}
+
+type reader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	ReadLag(ctx context.Context) (int64, error)
+	Close() error
+}
+
+type writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Store holds the executions applied to each order of a venue.  The executions are persisted to a compacted kafka topic
+// keyed by venue id and order id so that one topic can be shared by all the venue's instances, each instance loads
+// only its own orders' executions.
+type Store struct {
+	mutex     sync.Mutex
+	venueId   string
+	byOrderId map[string]Executions
+	writer    writer
+}
+
+// NewStore loads the venue's executions from the reader, which is closed once the executions have been loaded.
+func NewStore(ctx context.Context, venueId string, reader reader, writer writer) (*Store, error) {
+	s := &Store{
+		venueId:   venueId,
+		byOrderId: map[string]Executions{},
+		writer:    writer,
+	}
+
+	defer func() {
+		if err := reader.Close(); err != nil {
+			slog.Error("error closing executions reader", "error", err)
+		}
+	}()
+
+	prefix := s.key("")
+	for {
+		lag, err := reader.ReadLag(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read executions topic lag: %w", err)
+		}
+
+		if lag <= 0 {
+			break
+		}
+
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read executions: %w", err)
+		}
+
+		key := string(msg.Key)
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		orderId := strings.TrimPrefix(key, prefix)
+		if len(msg.Value) == 0 {
+			delete(s.byOrderId, orderId)
+			continue
+		}
+
+		var executions Executions
+		if err := json.Unmarshal(msg.Value, &executions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal executions of order %v: %w", orderId, err)
+		}
+
+		s.byOrderId[orderId] = executions
+	}
+
+	slog.Info("loaded order executions", "venueId", venueId, "numOrders", len(s.byOrderId))
+
+	return s, nil
+}
+
+// Get returns a copy of the executions applied to the order.
+func (s *Store) Get(orderId string) Executions {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.byOrderId[orderId].copy()
+}
+
+// Set persists the executions applied to the order, replacing any previously recorded.
+func (s *Store) Set(ctx context.Context, orderId string, executions Executions) error {
+	value, err := json.Marshal(executions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal executions of order %v: %w", orderId, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.writer.WriteMessages(ctx, kafka.Message{Key: []byte(s.key(orderId)), Value: value}); err != nil {
+		return fmt.Errorf("failed to write executions of order %v: %w", orderId, err)
+	}
+
+	s.byOrderId[orderId] = executions.copy()
+
+	return nil
+}
+
+func (s *Store) key(orderId string) string {
+	return s.venueId + "/" + orderId
+}
@@ -0,0 +1,114 @@
+package executions
+
+import (
+	"context"
+	"errors"
+	"github.com/ettec/otp-common/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testReader struct {
+	msgs   []kafka.Message
+	closed bool
+}
+
+func (t *testReader) ReadMessage(context.Context) (kafka.Message, error) {
+	if len(t.msgs) == 0 {
+		return kafka.Message{}, errors.New("no more messages")
+	}
+
+	msg := t.msgs[0]
+	t.msgs = t.msgs[1:]
+	return msg, nil
+}
+
+func (t *testReader) ReadLag(context.Context) (int64, error) {
+	return int64(len(t.msgs)), nil
+}
+
+func (t *testReader) Close() error {
+	t.closed = true
+	return nil
+}
+
+type testWriter struct {
+	written []kafka.Message
+	err     error
+}
+
+func (t *testWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if t.err != nil {
+		return t.err
+	}
+
+	t.written = append(t.written, msgs...)
+	return nil
+}
+
+func TestExecutionsAreRestoredFromTheTopic(t *testing.T) {
+	executions := Executions{
+		{Id: "exec1", Price: *model.IasD(100), Qty: *model.IasD(5)},
+		{Id: "exec2", Price: *model.IasD(101), Qty: *model.IasD(5), Reversed: true},
+	}
+
+	writer := &testWriter{}
+	store, err := NewStore(context.Background(), "xosr-0", &testReader{}, writer)
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Set(context.Background(), "order1", Executions{{Id: "exec1"}}))
+	assert.NoError(t, store.Set(context.Background(), "order1", executions))
+	assert.Equal(t, "xosr-0/order1", string(writer.written[1].Key))
+
+	otherVenueWriter := &testWriter{}
+	otherVenueStore, err := NewStore(context.Background(), "xosr-1", &testReader{}, otherVenueWriter)
+	assert.NoError(t, err)
+	assert.NoError(t, otherVenueStore.Set(context.Background(), "order2", Executions{{Id: "exec3"}}))
+
+	reader := &testReader{msgs: append(writer.written, otherVenueWriter.written...)}
+	restored, err := NewStore(context.Background(), "xosr-0", reader, &testWriter{})
+	assert.NoError(t, err)
+	assert.True(t, reader.closed)
+
+	got := restored.Get("order1")
+	assert.Equal(t, executions, got)
+	assert.Equal(t, "exec2", got.Find("exec2").Id)
+	assert.Nil(t, got.Find("exec3"))
+	assert.Nil(t, restored.Get("order2"))
+}
+
+func TestGetReturnsACopy(t *testing.T) {
+	store, err := NewStore(context.Background(), "xosr-0", &testReader{}, &testWriter{})
+	assert.NoError(t, err)
+
+	executions := Executions{{Id: "exec1"}}
+	assert.NoError(t, store.Set(context.Background(), "order1", executions))
+	executions[0].Reversed = true
+
+	got := store.Get("order1")
+	assert.False(t, got[0].Reversed)
+	got[0].Reversed = true
+
+	assert.False(t, store.Get("order1")[0].Reversed)
+}
+
+func TestFailedWriteDoesNotChangeExecutions(t *testing.T) {
+	writer := &testWriter{}
+	store, err := NewStore(context.Background(), "xosr-0", &testReader{}, writer)
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Set(context.Background(), "order1", Executions{{Id: "exec1"}}))
+
+	writer.err = errors.New("broker unavailable")
+	assert.Error(t, store.Set(context.Background(), "order1", Executions{{Id: "exec1"}, {Id: "exec2"}}))
+
+	assert.Len(t, store.Get("order1"), 1)
+}
+
+func TestGetOrderWithoutExecutions(t *testing.T) {
+	store, err := NewStore(context.Background(), "xosr-0", &testReader{}, &testWriter{})
+	assert.NoError(t, err)
+
+	assert.Nil(t, store.Get("order1"))
+}
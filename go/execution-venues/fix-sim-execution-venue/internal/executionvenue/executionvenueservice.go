@@ -3,6 +3,7 @@ package executionvenue
 import (
 	"context"
 	"fmt"
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/orderparams"
 	api "github.com/ettec/otp-common/api/executionvenue"
 	"github.com/ettec/otp-common/model"
 	"log/slog"
@@ -34,8 +35,17 @@ func (s *ExecVenueService) CreateAndRouteOrder(_ context.Context, params *api.Cr
 		return nil, fmt.Errorf("quantity required on params:%v", params)
 	}
 
-	if params.GetPrice() == nil {
-		return nil, fmt.Errorf("price required on params:%v", params)
+	orderParams, err := orderparams.Parse(params.GetExecParametersJson())
+	if err != nil {
+		return nil, fmt.Errorf("invalid execution parameters on params:%v, error:%w", params, err)
+	}
+
+	if orderParams.StopTriggered {
+		return nil, fmt.Errorf("stop triggered must not be set on params:%v", params)
+	}
+
+	if orderParams.RequiresPrice() && params.GetPrice() == nil {
+		return nil, fmt.Errorf("price required for %v order on params:%v", orderParams.OrderType, params)
 	}
 
 	if params.GetListingId() == 0 {
@@ -80,5 +90,5 @@ func (s *ExecVenueService) ModifyOrder(_ context.Context, params *api.ModifyOrde
 }
 
 func (s *ExecVenueService) GetExecutionParametersMetaData(context.Context, *model.Empty) (*api.ExecParamsMetaDataJson, error) {
-	return &api.ExecParamsMetaDataJson{}, nil
+	return &api.ExecParamsMetaDataJson{Json: orderparams.Schema}, nil
 }
@@ -3,44 +3,80 @@ package executionvenue
 import (
 	"context"
 	"fmt"
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/executions"
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/orderparams"
 	api "github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/marketdata"
 	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/ordermanagement"
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"log/slog"
+	"time"
 )
 
 type orderGateway interface {
-	Send(order *model.Order, listing *model.Listing) error
+	Send(order *model.Order, listing *model.Listing, params *orderparams.Parameters) error
 	Cancel(order *model.Order) error
-	Modify(order *model.Order, listing *model.Listing, Quantity *model.Decimal64, Price *model.Decimal64) error
+	Modify(order *model.Order, listing *model.Listing, Quantity *model.Decimal64, Price *model.Decimal64,
+		params *orderparams.Parameters) error
 }
 
+// localStop is a stop order held by the order manager until it is triggered by the last traded price of its listing.
+type localStop struct {
+	side   model.Side
+	params *orderparams.Parameters
+}
+
+const localStopExpiryCheckInterval = time.Second
+
 // orderManager is responsible for the creation, modification and cancellation of orders.  It depends on two resources
 // that are single threaded and IO bound, the order cache and the order gateway. Therefore it is effectively single
 // threaded.  To increase throughput additional instances of the execution venue should be deployed.  The order manager
 // uses channels to queue commands, primarily to ensure that cancel commands are prioritised above all others and
-// additionally to ensure that commands are executed fairly, i.e. in the order they are received, across clients
+// additionally to ensure that commands are executed fairly, i.e. in the order they are received, across clients.  When a quote stream is provided stop orders are held by the order manager and sent to the venue as
+// market or limit orders when the last traded price of the listing reaches the stop price, otherwise stop orders are
+// sent to the venue.
 type orderManagerImpl struct {
 	createOrderChan    chan createAndRouteOrderCmd
 	cancelOrderChan    chan cancelOrderCmd
 	modifyOrderChan    chan modifyOrderCmd
 	setOrderStatusChan chan setOrderStatusCmd
 	setOrderErrMsgChan chan setOrderErrorMsgCmd
+	rejectRequestChan  chan rejectRequestCmd
 	addExecChan        chan addExecutionCmd
+	reverseExecChan    chan reverseExecutionCmd
+	getOrderChan       chan getOrderCmd
+	activeOrdersChan   chan activeOrdersCmd
+
+	orderStore     *ordermanagement.OrderCache
+	executionStore *executions.Store
+	gateway        orderGateway
+	getListing     func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult)
+
+	quoteStream        marketdata.QuoteStream
+	subscribedListings map[int32]bool
+	localStops         map[int32]map[string]*localStop
 
-	orderStore *ordermanagement.OrderCache
-	gateway    orderGateway
-	getListing func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult)
+	// activeOrderIds are the ids of the orders that were not in a terminal state when last seen by the order manager
+	activeOrderIds map[string]bool
 }
 
-func NewOrderManager(ctx context.Context, cache *ordermanagement.OrderCache, gateway orderGateway,
+// NewOrderManager returns an order manager, if quoteStream is nil stop orders are sent to the venue.  restoredOrders
+// are the orders loaded from the order store, the untriggered stop orders amongst them are held by the order manager.
+// The executions applied to the orders are recorded in executionStore.
+func NewOrderManager(ctx context.Context, cache *ordermanagement.OrderCache, executionStore *executions.Store,
+	gateway orderGateway,
 	getListing func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult),
-	cmdBufferSize int) *orderManagerImpl {
+	quoteStream marketdata.QuoteStream, restoredOrders []*model.Order, cmdBufferSize int) *orderManagerImpl {
 
 	om := &orderManagerImpl{
-		getListing: getListing,
+		getListing:         getListing,
+		quoteStream:        quoteStream,
+		subscribedListings: map[int32]bool{},
+		localStops:         map[int32]map[string]*localStop{},
+		activeOrderIds:     map[string]bool{},
 	}
 
 	om.createOrderChan = make(chan createAndRouteOrderCmd, cmdBufferSize)
@@ -48,11 +84,39 @@ func NewOrderManager(ctx context.Context, cache *ordermanagement.OrderCache, gat
 	om.modifyOrderChan = make(chan modifyOrderCmd, cmdBufferSize)
 	om.setOrderStatusChan = make(chan setOrderStatusCmd, cmdBufferSize)
 	om.setOrderErrMsgChan = make(chan setOrderErrorMsgCmd, cmdBufferSize)
+	om.rejectRequestChan = make(chan rejectRequestCmd, cmdBufferSize)
 	om.addExecChan = make(chan addExecutionCmd, cmdBufferSize)
+	om.reverseExecChan = make(chan reverseExecutionCmd, cmdBufferSize)
+	om.getOrderChan = make(chan getOrderCmd, cmdBufferSize)
+	om.activeOrdersChan = make(chan activeOrdersCmd, cmdBufferSize)
 
 	om.orderStore = cache
+	om.executionStore = executionStore
 	om.gateway = gateway
 
+	for _, order := range restoredOrders {
+		if !order.IsTerminalState() {
+			om.activeOrderIds[order.Id] = true
+		}
+	}
+
+	if quoteStream != nil {
+		for _, order := range restoredOrders {
+			if order.IsTerminalState() {
+				continue
+			}
+
+			params, err := orderparams.Parse(order.ExecParametersJson)
+			if err != nil || !params.IsStop() || params.StopTriggered {
+				continue
+			}
+
+			if err := om.addLocalStop(order, params); err != nil {
+				slog.Error("failed to restore stop order", "orderId", order.Id, "error", err)
+			}
+		}
+	}
+
 	go om.executeOrderCommands(ctx)
 
 	return om
@@ -60,6 +124,15 @@ func NewOrderManager(ctx context.Context, cache *ordermanagement.OrderCache, gat
 
 func (om *orderManagerImpl) executeOrderCommands(ctx context.Context) {
 
+	var quotes <-chan *model.ClobQuote
+	var expiryCheck <-chan time.Time
+	if om.quoteStream != nil {
+		quotes = om.quoteStream.Chan()
+		ticker := time.NewTicker(localStopExpiryCheckInterval)
+		defer ticker.Stop()
+		expiryCheck = ticker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -81,8 +154,25 @@ func (om *orderManagerImpl) executeOrderCommands(ctx context.Context) {
 				om.executeSetOrderStatusCmd(ctx, su.orderId, su.status, su.ResultChan)
 			case em := <-om.setOrderErrMsgChan:
 				om.executeSetErrorMsg(ctx, em.orderId, em.msg, em.ResultChan)
+			case rr := <-om.rejectRequestChan:
+				om.executeRejectRequestCmd(ctx, rr.orderId, rr.ResultChan)
 			case tu := <-om.addExecChan:
 				om.executeUpdateTradedQntCmd(ctx, tu.orderId, tu.lastPrice, tu.lastQty, tu.execId, tu.ResultChan)
+			case re := <-om.reverseExecChan:
+				om.executeReverseExecutionCmd(ctx, re.orderId, re.execId, re.refExecId, re.correction, re.ResultChan)
+			case gc := <-om.getOrderChan:
+				om.executeGetOrderCmd(gc.orderId, gc.ResultChan)
+			case ao := <-om.activeOrdersChan:
+				om.executeActiveOrdersCmd(ao.ResultChan)
+			case q, ok := <-quotes:
+				if !ok {
+					slog.Error("quote stream closed, stop orders will no longer be triggered")
+					quotes = nil
+					continue
+				}
+				om.triggerLocalStops(ctx, q)
+			case now := <-expiryCheck:
+				om.expireLocalStops(ctx, now)
 			}
 		}
 	}
@@ -105,6 +195,23 @@ func (om *orderManagerImpl) SetErrorMsg(orderId string, msg string) error {
 	return result.Error
 }
 
+// RejectRequest is called when the venue rejects the pending request on an order.  A rejected new order is cancelled,
+// a rejected modify or cancel leaves the order as it was before the request was made.
+func (om *orderManagerImpl) RejectRequest(orderId string) error {
+	slog.Info("rejecting pending order request", "orderId", orderId)
+
+	resultChan := make(chan errorCmdResult)
+
+	om.rejectRequestChan <- rejectRequestCmd{
+		orderId:    orderId,
+		ResultChan: resultChan,
+	}
+
+	result := <-resultChan
+
+	return result.Error
+}
+
 func (om *orderManagerImpl) SetOrderStatus(orderId string, status model.OrderStatus) error {
 	slog.Info("updating order status", "orderId", orderId, "newStatus", status)
 
@@ -140,6 +247,68 @@ func (om *orderManagerImpl) AddExecution(orderId string, lastPrice model.Decimal
 	return result.Error
 }
 
+// CancelExecution reverses the execution refExecId of the order, execId is the id of the trade cancel.
+func (om *orderManagerImpl) CancelExecution(orderId string, execId string, refExecId string) error {
+	slog.Info("cancelling order execution", "orderId", orderId, "execId", execId, "refExecId", refExecId)
+
+	return om.reverseExecution(orderId, execId, refExecId, nil)
+}
+
+// CorrectExecution reverses the execution refExecId of the order and adds an execution with the corrected price and
+// quantity, execId is the id of the trade correction.
+func (om *orderManagerImpl) CorrectExecution(orderId string, execId string, refExecId string, lastPrice model.Decimal64,
+	lastQty model.Decimal64) error {
+	slog.Info("correcting order execution", "orderId", orderId, "execId", execId, "refExecId", refExecId,
+		"price", lastPrice, "quantity", lastQty)
+
+	return om.reverseExecution(orderId, execId, refExecId, &model.Execution{Id: execId, Price: lastPrice, Qty: lastQty})
+}
+
+func (om *orderManagerImpl) reverseExecution(orderId string, execId string, refExecId string,
+	correction *model.Execution) error {
+	resultChan := make(chan errorCmdResult)
+
+	om.reverseExecChan <- reverseExecutionCmd{
+		orderId:    orderId,
+		execId:     execId,
+		refExecId:  refExecId,
+		correction: correction,
+		ResultChan: resultChan,
+	}
+
+	result := <-resultChan
+
+	return result.Error
+}
+
+// GetOrder returns the current state of the order and true if found, otherwise a nil value and false.
+func (om *orderManagerImpl) GetOrder(orderId string) (*model.Order, bool, error) {
+	resultChan := make(chan getOrderCmdResult)
+
+	om.getOrderChan <- getOrderCmd{
+		orderId:    orderId,
+		ResultChan: resultChan,
+	}
+
+	result := <-resultChan
+
+	return result.Order, result.Exists, result.Error
+}
+
+// GetActiveOrders returns the orders that are not in a terminal state and have been sent to the venue, stop orders
+// held by the order manager are excluded.
+func (om *orderManagerImpl) GetActiveOrders() ([]*model.Order, error) {
+	resultChan := make(chan activeOrdersCmdResult)
+
+	om.activeOrdersChan <- activeOrdersCmd{
+		ResultChan: resultChan,
+	}
+
+	result := <-resultChan
+
+	return result.Orders, result.Error
+}
+
 func (om *orderManagerImpl) CreateAndRouteOrder(params *api.CreateAndRouteOrderParams) (*api.OrderId, error) {
 
 	resultChan := make(chan createAndRouteOrderCmdResult)
@@ -203,6 +372,13 @@ func (om *orderManagerImpl) executeUpdateTradedQntCmd(ctx context.Context, id st
 		return
 	}
 
+	applied := om.getAppliedExecutions(order)
+	if applied.Find(execId) != nil {
+		slog.Warn("ignoring duplicate execution", "orderId", id, "execId", execId)
+		resultChan <- errorCmdResult{}
+		return
+	}
+
 	err = order.AddExecution(model.Execution{
 		Id:    execId,
 		Price: lastPrice,
@@ -214,9 +390,143 @@ func (om *orderManagerImpl) executeUpdateTradedQntCmd(ctx context.Context, id st
 		return
 	}
 
-	err = om.orderStore.Store(ctx, order)
-	resultChan <- errorCmdResult{Error: err}
+	resultChan <- errorCmdResult{Error: om.storeWithExecutions(ctx, order,
+		append(applied, &executions.Execution{Id: execId, Price: lastPrice, Qty: lastQty}))}
+}
+
+// executeReverseExecutionCmd applies a reversing execution for the execution refExecId, and for a correction adds the
+// corrected execution.  The status of the order is left unchanged.
+func (om *orderManagerImpl) executeReverseExecutionCmd(ctx context.Context, id string, execId string, refExecId string,
+	correction *model.Execution, resultChan chan errorCmdResult) {
 
+	order, exists, err := om.orderStore.GetOrder(id)
+	if err != nil {
+		resultChan <- errorCmdResult{Error: err}
+		return
+	}
+
+	if !exists {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("reverse execution failed, no order found for id %v", id)}
+		return
+	}
+
+	applied := om.getAppliedExecutions(order)
+	if applied.Find(execId) != nil {
+		slog.Warn("ignoring duplicate execution", "orderId", id, "execId", execId)
+		resultChan <- errorCmdResult{}
+		return
+	}
+
+	reversed := applied.Find(refExecId)
+	if reversed == nil {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("reverse execution failed, no execution %v found on order %v",
+			refExecId, id)}
+		return
+	}
+
+	if reversed.Reversed {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("reverse execution failed, execution %v of order %v is already reversed",
+			refExecId, id)}
+		return
+	}
+
+	if correction != nil && correction.Qty.AsDecimal().Equal(decimal.New(0, 0)) {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("reverse execution failed, corrected quantity of execution %v of order %v is zero",
+			refExecId, id)}
+		return
+	}
+
+	reversed.Reversed = true
+	applyReversingExecution(order, execId, reversed.Price, reversed.Qty, applied)
+
+	if correction != nil {
+		if err = order.AddExecution(*correction); err != nil {
+			resultChan <- errorCmdResult{Error: err}
+			return
+		}
+		applied = append(applied, &executions.Execution{Id: execId, Price: correction.Price, Qty: correction.Qty})
+	} else {
+		applied = append(applied, &executions.Execution{Id: execId, Price: reversed.Price,
+			Qty: *model.ToDecimal64(reversed.Qty.AsDecimal().Neg()), Reversed: true})
+	}
+
+	resultChan <- errorCmdResult{Error: om.storeWithExecutions(ctx, order, applied)}
+}
+
+// storeWithExecutions stores the order before recording its applied executions.  The order is written synchronously so
+// that a failure after the order is stored leaves at most the order's last execution unrecorded, which
+// getAppliedExecutions recovers from the order, rather than recording an execution that the stored order does not
+// include.
+func (om *orderManagerImpl) storeWithExecutions(ctx context.Context, order *model.Order,
+	applied executions.Executions) error {
+	if err := om.orderStore.Store(ctx, order); err != nil {
+		return err
+	}
+
+	if err := om.executionStore.Set(ctx, order.Id, applied); err != nil {
+		return fmt.Errorf("order %v stored but its executions were not recorded: %w", order.Id, err)
+	}
+
+	return nil
+}
+
+// getAppliedExecutions returns the executions recorded for the order, including the order's last execution if it was
+// applied to the stored order but could not be recorded.  The stored order does not identify the execution that a
+// recovered cancel or correction reversed, that execution remains recorded as not reversed.
+func (om *orderManagerImpl) getAppliedExecutions(order *model.Order) executions.Executions {
+	applied := om.executionStore.Get(order.Id)
+	if order.LastExecId == "" || applied.Find(order.LastExecId) != nil {
+		return applied
+	}
+
+	slog.Warn("recovering unrecorded execution of order", "orderId", order.Id, "execId", order.LastExecId)
+
+	recovered := &executions.Execution{Id: order.LastExecId}
+	if order.LastExecPrice != nil {
+		recovered.Price = *order.LastExecPrice
+	}
+	if order.LastExecQuantity != nil {
+		recovered.Qty = *order.LastExecQuantity
+		recovered.Reversed = recovered.Qty.AsDecimal().IsNegative()
+	}
+
+	return append(applied, recovered)
+}
+
+// applyReversingExecution removes the quantity of a reversed execution from the traded quantity of the order and
+// recalculates the order's average traded price.  The average price is calculated from the applied executions that
+// have not been reversed when they account for all of the order's traded quantity, otherwise it is derived from the
+// order's current average price.
+func applyReversingExecution(order *model.Order, execId string, price model.Decimal64, qty model.Decimal64,
+	applied executions.Executions) {
+	tradedQty := order.GetTradedQuantity().AsDecimal()
+	newTradedQty := tradedQty.Sub(qty.AsDecimal())
+
+	appliedQty := decimal.New(0, 0)
+	appliedValue := decimal.New(0, 0)
+	for _, execution := range applied {
+		if !execution.Reversed {
+			appliedQty = appliedQty.Add(execution.Qty.AsDecimal())
+			appliedValue = appliedValue.Add(execution.Price.AsDecimal().Mul(execution.Qty.AsDecimal()))
+		}
+	}
+
+	avgPrice := decimal.New(0, 0)
+	if !newTradedQty.Equal(decimal.New(0, 0)) {
+		if appliedQty.Equal(newTradedQty) {
+			avgPrice = appliedValue.Div(newTradedQty)
+		} else {
+			tradedValue := order.GetAvgTradePrice().AsDecimal().Mul(tradedQty)
+			avgPrice = tradedValue.Sub(price.AsDecimal().Mul(qty.AsDecimal())).Div(newTradedQty)
+		}
+	}
+
+	order.TradedQuantity = model.ToDecimal64(newTradedQty)
+	order.RemainingQuantity = model.ToDecimal64(order.GetRemainingQuantity().AsDecimal().Add(qty.AsDecimal()))
+	order.AvgTradePrice = model.ToDecimal64(avgPrice)
+	order.LastExecId = execId
+	order.LastExecPrice = &price
+	order.LastExecQuantity = model.ToDecimal64(qty.AsDecimal().Neg())
 }
 
 func (om *orderManagerImpl) executeSetErrorMsg(ctx context.Context, id string, msg string, resultChan chan errorCmdResult) {
@@ -224,6 +534,7 @@ func (om *orderManagerImpl) executeSetErrorMsg(ctx context.Context, id string, m
 	order, exists, err := om.orderStore.GetOrder(id)
 	if err != nil {
 		resultChan <- errorCmdResult{Error: fmt.Errorf("failed to get order for id %s from cache: %w", id, err)}
+		return
 	}
 
 	if !exists {
@@ -234,6 +545,74 @@ func (om *orderManagerImpl) executeSetErrorMsg(ctx context.Context, id string, m
 	order.ErrorMessage = msg
 
 	err = om.orderStore.Store(ctx, order)
+	resultChan <- errorCmdResult{Error: err}
+}
+
+func (om *orderManagerImpl) executeRejectRequestCmd(ctx context.Context, id string, resultChan chan errorCmdResult) {
+
+	order, exists, err := om.orderStore.GetOrder(id)
+	if err != nil {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("failed to get order for id %s from cache: %w", id, err)}
+		return
+	}
+
+	if !exists {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("reject request failed, no order found for id %s", id)}
+		return
+	}
+
+	if order.IsTerminalState() {
+		resultChan <- errorCmdResult{}
+		return
+	}
+
+	order.TargetStatus = model.OrderStatus_NONE
+	if order.Status == model.OrderStatus_NONE {
+		if err = order.SetTargetStatus(model.OrderStatus_CANCELLED); err != nil {
+			resultChan <- errorCmdResult{Error: err}
+			return
+		}
+
+		if err = order.SetStatus(model.OrderStatus_CANCELLED); err != nil {
+			resultChan <- errorCmdResult{Error: err}
+			return
+		}
+
+		om.removeLocalStop(order)
+	}
+
+	err = om.orderStore.Store(ctx, order)
+	resultChan <- errorCmdResult{Error: err}
+}
+
+func (om *orderManagerImpl) executeGetOrderCmd(id string, resultChan chan getOrderCmdResult) {
+	order, exists, err := om.orderStore.GetOrder(id)
+	resultChan <- getOrderCmdResult{Order: order, Exists: exists, Error: err}
+}
+
+func (om *orderManagerImpl) executeActiveOrdersCmd(resultChan chan activeOrdersCmdResult) {
+
+	var orders []*model.Order
+	for id := range om.activeOrderIds {
+		order, exists, err := om.orderStore.GetOrder(id)
+		if err != nil {
+			resultChan <- activeOrdersCmdResult{Error: fmt.Errorf("failed to get order for id %s from cache: %w", id, err)}
+			return
+		}
+
+		if !exists || order.IsTerminalState() {
+			delete(om.activeOrderIds, id)
+			continue
+		}
+
+		if om.isLocalStop(order) {
+			continue
+		}
+
+		orders = append(orders, order)
+	}
+
+	resultChan <- activeOrdersCmdResult{Orders: orders}
 }
 
 func (om *orderManagerImpl) executeSetOrderStatusCmd(ctx context.Context, id string, status model.OrderStatus,
@@ -250,6 +629,16 @@ func (om *orderManagerImpl) executeSetOrderStatusCmd(ctx context.Context, id str
 		return
 	}
 
+	// an unsolicited cancel, e.g. the expiry of an order or a cancel missed whilst disconnected from the venue
+	if status == model.OrderStatus_CANCELLED && order.TargetStatus != model.OrderStatus_CANCELLED &&
+		!order.IsTerminalState() {
+		order.TargetStatus = model.OrderStatus_NONE
+		if err = order.SetTargetStatus(model.OrderStatus_CANCELLED); err != nil {
+			resultChan <- errorCmdResult{Error: err}
+			return
+		}
+	}
+
 	err = order.SetStatus(status)
 	if err != nil {
 		resultChan <- errorCmdResult{Error: err}
@@ -275,6 +664,17 @@ func (om *orderManagerImpl) executeModifyOrderCmd(ctx context.Context, params *a
 		return
 	}
 
+	orderParams, err := orderparams.Parse(order.ExecParametersJson)
+	if err != nil {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("failed to parse order parameters: %w", err)}
+		return
+	}
+
+	if orderParams.RequiresPrice() && params.Price == nil {
+		resultChan <- errorCmdResult{Error: fmt.Errorf("price required to modify %v order", orderParams.OrderType)}
+		return
+	}
+
 	err = order.SetTargetStatus(model.OrderStatus_LIVE)
 	if err != nil {
 		resultChan <- errorCmdResult{Error: err}
@@ -284,6 +684,17 @@ func (om *orderManagerImpl) executeModifyOrderCmd(ctx context.Context, params *a
 	order.Price = params.Price
 	order.Quantity = params.Quantity
 
+	if om.isLocalStop(order) {
+		// the order has not been sent to the venue so the modification is complete
+		if err = order.SetStatus(model.OrderStatus_LIVE); err != nil {
+			resultChan <- errorCmdResult{Error: err}
+			return
+		}
+
+		resultChan <- errorCmdResult{Error: om.orderStore.Store(ctx, order)}
+		return
+	}
+
 	err = om.orderStore.Store(ctx, order)
 	if err != nil {
 		resultChan <- errorCmdResult{Error: err}
@@ -298,7 +709,7 @@ func (om *orderManagerImpl) executeModifyOrderCmd(ctx context.Context, params *a
 		return
 	}
 
-	err = om.gateway.Modify(order, listingResult.Listing, params.Quantity, params.Price)
+	err = om.gateway.Modify(order, listingResult.Listing, params.Quantity, params.Price, getVenueParameters(orderParams))
 
 	resultChan <- errorCmdResult{Error: err}
 }
@@ -323,6 +734,11 @@ func (om *orderManagerImpl) executeCancelOrderCmd(ctx context.Context,
 		return
 	}
 
+	if om.isLocalStop(order) {
+		resultChan <- errorCmdResult{Error: om.cancelLocalStop(ctx, order)}
+		return
+	}
+
 	err = om.orderStore.Store(ctx, order)
 	if err != nil {
 		resultChan <- errorCmdResult{Error: err}
@@ -347,14 +763,54 @@ func (om *orderManagerImpl) executeCreateAndRouteOrderCmd(ctx context.Context, p
 
 	id := uniqueId.String()
 
+	orderParams, err := orderparams.Parse(params.ExecParametersJson)
+	if err != nil {
+		resultChan <- createAndRouteOrderCmdResult{
+			OrderId: nil,
+			Error:   fmt.Errorf("invalid order parameters: %w", err),
+		}
+		return
+	}
+
 	order := model.NewOrder(id, params.OrderSide, params.Quantity,
 		params.Price, params.ListingId, params.OriginatorId, params.OriginatorRef,
 		params.RootOriginatorId, params.RootOriginatorRef, params.Destination)
+	order.ExecParametersJson = params.ExecParametersJson
 
 	if err = order.SetTargetStatus(model.OrderStatus_LIVE); err != nil {
 		slog.Error("failed to set target status to live", "error", err)
 	}
 
+	if om.quoteStream != nil && orderParams.IsStop() {
+		// the stop order is accepted and held until it is triggered
+		if err = om.addLocalStop(order, orderParams); err != nil {
+			resultChan <- createAndRouteOrderCmdResult{
+				OrderId: nil,
+				Error:   err,
+			}
+			return
+		}
+
+		if err = order.SetStatus(model.OrderStatus_LIVE); err != nil {
+			slog.Error("failed to set status to live", "error", err)
+		}
+
+		err = om.orderStore.Store(ctx, order)
+		if err != nil {
+			om.removeLocalStop(order)
+		} else {
+			om.activeOrderIds[order.Id] = true
+		}
+
+		resultChan <- createAndRouteOrderCmdResult{
+			OrderId: &api.OrderId{
+				OrderId: order.Id,
+			},
+			Error: err,
+		}
+		return
+	}
+
 	err = om.orderStore.Store(ctx, order)
 	if err != nil {
 		resultChan <- createAndRouteOrderCmdResult{
@@ -365,6 +821,8 @@ func (om *orderManagerImpl) executeCreateAndRouteOrderCmd(ctx context.Context, p
 		return
 	}
 
+	om.activeOrderIds[order.Id] = true
+
 	listingChan := make(chan staticdata.ListingResult, 1)
 	om.getListing(ctx, params.ListingId, listingChan)
 
@@ -377,7 +835,7 @@ func (om *orderManagerImpl) executeCreateAndRouteOrderCmd(ctx context.Context, p
 		return
 	}
 
-	err = om.gateway.Send(order, listingResult.Listing)
+	err = om.gateway.Send(order, listingResult.Listing, orderParams)
 
 	resultChan <- createAndRouteOrderCmdResult{
 		OrderId: &api.OrderId{
@@ -388,6 +846,166 @@ func (om *orderManagerImpl) executeCreateAndRouteOrderCmd(ctx context.Context, p
 
 }
 
+func (om *orderManagerImpl) addLocalStop(order *model.Order, params *orderparams.Parameters) error {
+	if !om.subscribedListings[order.ListingId] {
+		if err := om.quoteStream.Subscribe(order.ListingId); err != nil {
+			return fmt.Errorf("failed to subscribe to quotes for listing %v: %w", order.ListingId, err)
+		}
+		om.subscribedListings[order.ListingId] = true
+	}
+
+	stops, ok := om.localStops[order.ListingId]
+	if !ok {
+		stops = map[string]*localStop{}
+		om.localStops[order.ListingId] = stops
+	}
+
+	stops[order.Id] = &localStop{side: order.Side, params: params}
+
+	return nil
+}
+
+func (om *orderManagerImpl) removeLocalStop(order *model.Order) {
+	stops := om.localStops[order.ListingId]
+	delete(stops, order.Id)
+	if len(stops) == 0 {
+		delete(om.localStops, order.ListingId)
+	}
+}
+
+func (om *orderManagerImpl) isLocalStop(order *model.Order) bool {
+	_, ok := om.localStops[order.ListingId][order.Id]
+	return ok
+}
+
+func (om *orderManagerImpl) cancelLocalStop(ctx context.Context, order *model.Order) error {
+	if err := order.SetStatus(model.OrderStatus_CANCELLED); err != nil {
+		return err
+	}
+
+	if err := om.orderStore.Store(ctx, order); err != nil {
+		return err
+	}
+
+	om.removeLocalStop(order)
+
+	return nil
+}
+
+func (om *orderManagerImpl) triggerLocalStops(ctx context.Context, quote *model.ClobQuote) {
+	for orderId, stop := range om.localStops[quote.ListingId] {
+		if stop.params.IsTriggeredBy(stop.side, quote.LastPrice) {
+			slog.Info("stop order triggered", "orderId", orderId, "stopPrice", stop.params.StopPrice,
+				"lastPrice", quote.LastPrice)
+			om.triggerLocalStop(ctx, orderId, quote.ListingId, stop)
+		}
+	}
+}
+
+// triggerLocalStop sends the stop order to the venue, the order's parameters are updated to record that it has been
+// triggered so that the order is not held again when the order manager is restarted.
+func (om *orderManagerImpl) triggerLocalStop(ctx context.Context, orderId string, listingId int32, stop *localStop) {
+
+	om.removeLocalStop(&model.Order{Id: orderId, ListingId: listingId})
+
+	order, exists, err := om.orderStore.GetOrder(orderId)
+	if err != nil {
+		slog.Error("failed to get triggered stop order", "orderId", orderId, "error", err)
+		return
+	}
+
+	if !exists {
+		slog.Error("triggered stop order not found", "orderId", orderId)
+		return
+	}
+
+	if order.IsTerminalState() {
+		return
+	}
+
+	triggeredParams := *stop.params
+	triggeredParams.StopTriggered = true
+	order.ExecParametersJson, err = triggeredParams.ToJson()
+	if err != nil {
+		slog.Error("failed to set triggered stop order parameters", "orderId", orderId, "error", err)
+		return
+	}
+
+	if err = order.SetTargetStatus(model.OrderStatus_LIVE); err != nil {
+		slog.Error("failed to set triggered stop order target status", "orderId", orderId, "error", err)
+		return
+	}
+
+	if err = om.orderStore.Store(ctx, order); err != nil {
+		slog.Error("failed to store triggered stop order", "orderId", orderId, "error", err)
+		return
+	}
+
+	listingChan := make(chan staticdata.ListingResult, 1)
+	om.getListing(ctx, listingId, listingChan)
+	listingResult := <-listingChan
+	if listingResult.Err != nil {
+		om.setTriggeredStopError(ctx, order, fmt.Errorf("failed to get listing: %w", listingResult.Err))
+		return
+	}
+
+	if err = om.gateway.Send(order, listingResult.Listing, triggeredParams.Triggered()); err != nil {
+		om.setTriggeredStopError(ctx, order, err)
+	}
+}
+
+func (om *orderManagerImpl) setTriggeredStopError(ctx context.Context, order *model.Order, err error) {
+	slog.Error("failed to send triggered stop order", "orderId", order.Id, "error", err)
+	order.ErrorMessage = fmt.Sprintf("failed to send triggered stop order: %v", err)
+	if err = om.orderStore.Store(ctx, order); err != nil {
+		slog.Error("failed to store triggered stop order error message", "orderId", order.Id, "error", err)
+	}
+}
+
+// expireLocalStops cancels the held GTD stop orders whose expire time has passed.
+func (om *orderManagerImpl) expireLocalStops(ctx context.Context, now time.Time) {
+	for listingId, stops := range om.localStops {
+		for orderId, stop := range stops {
+			if stop.params.TimeInForce != orderparams.GoodTillDate || now.Before(stop.params.GetExpireTime()) {
+				continue
+			}
+
+			order, exists, err := om.orderStore.GetOrder(orderId)
+			if err != nil {
+				slog.Error("failed to get expired stop order", "orderId", orderId, "error", err)
+				continue
+			}
+
+			if !exists {
+				om.removeLocalStop(&model.Order{Id: orderId, ListingId: listingId})
+				continue
+			}
+
+			if err = order.SetTargetStatus(model.OrderStatus_CANCELLED); err != nil {
+				slog.Error("failed to set expired stop order target status", "orderId", orderId, "error", err)
+				continue
+			}
+
+			if err = om.cancelLocalStop(ctx, order); err != nil {
+				slog.Error("failed to cancel expired stop order", "orderId", orderId, "error", err)
+				continue
+			}
+
+			slog.Info("expired stop order cancelled", "orderId", orderId)
+		}
+	}
+}
+
+// getVenueParameters returns the parameters with which an order is sent to the venue, a triggered stop order is sent
+// as a market or limit order.
+func getVenueParameters(params *orderparams.Parameters) *orderparams.Parameters {
+	if params.StopTriggered {
+		return params.Triggered()
+	}
+
+	return params
+}
+
 type addExecutionCmd struct {
 	orderId    string
 	lastPrice  model.Decimal64
@@ -408,6 +1026,11 @@ type setOrderErrorMsgCmd struct {
 	ResultChan chan errorCmdResult
 }
 
+type rejectRequestCmd struct {
+	orderId    string
+	ResultChan chan errorCmdResult
+}
+
 type createAndRouteOrderCmd struct {
 	Params     *api.CreateAndRouteOrderParams
 	ResultChan chan createAndRouteOrderCmdResult
@@ -431,3 +1054,31 @@ type modifyOrderCmd struct {
 type errorCmdResult struct {
 	Error error
 }
+
+type reverseExecutionCmd struct {
+	orderId    string
+	execId     string
+	refExecId  string
+	correction *model.Execution
+	ResultChan chan errorCmdResult
+}
+
+type getOrderCmd struct {
+	orderId    string
+	ResultChan chan getOrderCmdResult
+}
+
+type getOrderCmdResult struct {
+	Order  *model.Order
+	Exists bool
+	Error  error
+}
+
+type activeOrdersCmd struct {
+	ResultChan chan activeOrdersCmdResult
+}
+
+type activeOrdersCmdResult struct {
+	Orders []*model.Order
+	Error  error
+}
@@ -2,11 +2,15 @@ package executionvenue
 
 import (
 	"context"
+	"errors"
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/executions"
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/orderparams"
 	api "github.com/ettec/otp-common/api/executionvenue"
 	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/ordermanagement"
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -21,6 +25,7 @@ func TestMain(m *testing.M) {
 }
 
 var orderCache *ordermanagement.OrderCache
+var executionStore *executions.Store
 var om orderManager
 
 func setup(ctx context.Context) {
@@ -30,9 +35,10 @@ func setup(ctx context.Context) {
 		panic(err)
 	}
 
-	om = NewOrderManager(ctx, orderCache, &TestOrderManager{}, func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+	executionStore = newTestExecutionStore()
+	om = NewOrderManager(ctx, orderCache, executionStore, &TestOrderManager{}, func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
 		result <- staticdata.ListingResult{Listing: &model.Listing{Id: 1}}
-	}, 100)
+	}, nil, nil, 100)
 }
 
 func IntToDecimal64(i int) *model.Decimal64 {
@@ -230,10 +236,278 @@ func TestCancelOrder(t *testing.T) {
 
 }
 
+func TestRejectNewOrderRequest(t *testing.T) {
+
+	params := &api.CreateAndRouteOrderParams{
+		OrderSide: model.Side_BUY,
+		Quantity:  IntToDecimal64(10),
+		Price:     IntToDecimal64(20),
+		ListingId: 1,
+	}
+
+	id, err := om.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+
+	err = om.SetErrorMsg(id.OrderId, "unknown symbol")
+	assert.NoError(t, err)
+
+	err = om.(*orderManagerImpl).RejectRequest(id.OrderId)
+	assert.NoError(t, err)
+
+	order, _, _ := orderCache.GetOrder(id.OrderId)
+
+	assert.Equal(t, model.OrderStatus_CANCELLED, order.Status)
+	assert.Equal(t, model.OrderStatus_NONE, order.TargetStatus)
+	assert.Equal(t, "unknown symbol", order.ErrorMessage)
+}
+
+func TestRejectModifyOrderRequest(t *testing.T) {
+
+	params := &api.CreateAndRouteOrderParams{
+		OrderSide: model.Side_BUY,
+		Quantity:  IntToDecimal64(10),
+		Price:     IntToDecimal64(20),
+		ListingId: 1,
+	}
+
+	id, err := om.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+
+	err = om.SetOrderStatus(id.OrderId, model.OrderStatus_LIVE)
+	assert.NoError(t, err)
+
+	err = om.ModifyOrder(&api.ModifyOrderParams{
+		OrderId:   id.OrderId,
+		ListingId: 1,
+		Quantity:  IntToDecimal64(15),
+		Price:     IntToDecimal64(21),
+	})
+	assert.NoError(t, err)
+
+	err = om.(*orderManagerImpl).RejectRequest(id.OrderId)
+	assert.NoError(t, err)
+
+	order, _, _ := orderCache.GetOrder(id.OrderId)
+
+	assert.Equal(t, model.OrderStatus_LIVE, order.Status)
+	assert.Equal(t, model.OrderStatus_NONE, order.TargetStatus)
+}
+
+func TestUnsolicitedCancel(t *testing.T) {
+
+	params := &api.CreateAndRouteOrderParams{
+		OrderSide: model.Side_BUY,
+		Quantity:  IntToDecimal64(10),
+		Price:     IntToDecimal64(20),
+		ListingId: 1,
+	}
+
+	id, err := om.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+
+	err = om.SetOrderStatus(id.OrderId, model.OrderStatus_LIVE)
+	assert.NoError(t, err)
+
+	err = om.SetOrderStatus(id.OrderId, model.OrderStatus_CANCELLED)
+	assert.NoError(t, err)
+
+	order, _, _ := orderCache.GetOrder(id.OrderId)
+
+	assert.Equal(t, model.OrderStatus_CANCELLED, order.Status)
+	assert.Equal(t, model.OrderStatus_NONE, order.TargetStatus)
+}
+
+func TestGetActiveOrders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache, err := ordermanagement.NewOwnerOrderCache(ctx, "", newTestOrderStore())
+	assert.NoError(t, err)
+
+	restored := &model.Order{Id: "restored", Status: model.OrderStatus_LIVE}
+	assert.NoError(t, cache.Store(ctx, restored))
+
+	manager := NewOrderManager(ctx, cache, newTestExecutionStore(), &TestOrderManager{}, func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: 1}}
+	}, nil, []*model.Order{restored, {Id: "done", Status: model.OrderStatus_FILLED}}, 100)
+
+	params := &api.CreateAndRouteOrderParams{
+		OrderSide: model.Side_BUY,
+		Quantity:  IntToDecimal64(10),
+		Price:     IntToDecimal64(20),
+		ListingId: 1,
+	}
+
+	live, err := manager.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+
+	cancelled, err := manager.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SetOrderStatus(cancelled.OrderId, model.OrderStatus_CANCELLED))
+
+	orders, err := manager.GetActiveOrders()
+	assert.NoError(t, err)
+
+	var ids []string
+	for _, order := range orders {
+		ids = append(ids, order.Id)
+	}
+	assert.ElementsMatch(t, []string{"restored", live.OrderId}, ids)
+
+	order, exists, err := manager.GetOrder(live.OrderId)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, live.OrderId, order.Id)
+}
+
+func TestDuplicateExecutionsAreIgnored(t *testing.T) {
+
+	params := &api.CreateAndRouteOrderParams{
+		OrderSide: model.Side_BUY,
+		Quantity:  IntToDecimal64(10),
+		Price:     IntToDecimal64(20),
+		ListingId: 1,
+	}
+
+	id, err := om.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+
+	assert.NoError(t, om.AddExecution(id.OrderId, *IntToDecimal64(20), *IntToDecimal64(4), "exec1"))
+	assert.NoError(t, om.AddExecution(id.OrderId, *IntToDecimal64(20), *IntToDecimal64(4), "exec1"))
+
+	order, _, _ := orderCache.GetOrder(id.OrderId)
+	assert.True(t, order.TradedQuantity.Equal(IntToDecimal64(4)))
+	assert.True(t, order.RemainingQuantity.Equal(IntToDecimal64(6)))
+
+	assert.Len(t, executionStore.Get(id.OrderId), 1)
+	assert.Empty(t, order.XXX_unrecognized, "executions should not be sent to other consumers of the order")
+}
+
+func TestCancelAndCorrectExecutions(t *testing.T) {
+
+	params := &api.CreateAndRouteOrderParams{
+		OrderSide: model.Side_BUY,
+		Quantity:  IntToDecimal64(10),
+		Price:     IntToDecimal64(20),
+		ListingId: 1,
+	}
+
+	id, err := om.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+
+	manager := om.(*orderManagerImpl)
+
+	assert.NoError(t, om.AddExecution(id.OrderId, *IntToDecimal64(20), *IntToDecimal64(4), "exec1"))
+	assert.NoError(t, om.AddExecution(id.OrderId, *IntToDecimal64(10), *IntToDecimal64(2), "exec2"))
+
+	assert.NoError(t, manager.CancelExecution(id.OrderId, "exec3", "exec2"))
+	assert.NoError(t, manager.CancelExecution(id.OrderId, "exec3", "exec2"))
+
+	order, _, _ := orderCache.GetOrder(id.OrderId)
+	assert.True(t, order.TradedQuantity.Equal(IntToDecimal64(4)))
+	assert.True(t, order.RemainingQuantity.Equal(IntToDecimal64(6)))
+	assert.True(t, order.AvgTradePrice.Equal(IntToDecimal64(20)))
+	assert.Equal(t, "exec3", order.LastExecId)
+	assert.True(t, order.LastExecQuantity.Equal(IntToDecimal64(-2)))
+
+	assert.NoError(t, manager.CorrectExecution(id.OrderId, "exec4", "exec1", *IntToDecimal64(22), *IntToDecimal64(5)))
+
+	order, _, _ = orderCache.GetOrder(id.OrderId)
+	assert.True(t, order.TradedQuantity.Equal(IntToDecimal64(5)))
+	assert.True(t, order.RemainingQuantity.Equal(IntToDecimal64(5)))
+	assert.True(t, order.AvgTradePrice.Equal(IntToDecimal64(22)))
+	assert.Equal(t, "exec4", order.LastExecId)
+
+	assert.Error(t, manager.CancelExecution(id.OrderId, "exec5", "exec1"))
+	assert.Error(t, manager.CancelExecution(id.OrderId, "exec6", "unknown"))
+
+	assert.NoError(t, manager.CancelExecution(id.OrderId, "exec7", "exec4"))
+
+	order, _, _ = orderCache.GetOrder(id.OrderId)
+	assert.True(t, order.TradedQuantity.Equal(IntToDecimal64(0)))
+	assert.True(t, order.RemainingQuantity.Equal(IntToDecimal64(10)))
+	assert.True(t, order.AvgTradePrice.Equal(IntToDecimal64(0)))
+}
+
+func TestZeroQuantityCorrectionDoesNotChangeOrder(t *testing.T) {
+
+	params := &api.CreateAndRouteOrderParams{
+		OrderSide: model.Side_BUY,
+		Quantity:  IntToDecimal64(10),
+		Price:     IntToDecimal64(20),
+		ListingId: 1,
+	}
+
+	id, err := om.CreateAndRouteOrder(params)
+	assert.NoError(t, err)
+
+	manager := om.(*orderManagerImpl)
+
+	assert.NoError(t, om.AddExecution(id.OrderId, *IntToDecimal64(20), *IntToDecimal64(4), "exec1"))
+	before, _, _ := orderCache.GetOrder(id.OrderId)
+
+	assert.Error(t, manager.CorrectExecution(id.OrderId, "exec2", "exec1", *IntToDecimal64(22), *IntToDecimal64(0)))
+
+	after, _, _ := orderCache.GetOrder(id.OrderId)
+	assert.True(t, proto.Equal(before, after))
+	assert.Equal(t, executions.Executions{{Id: "exec1", Price: *IntToDecimal64(20), Qty: *IntToDecimal64(4)}},
+		executionStore.Get(id.OrderId))
+
+	assert.NoError(t, manager.CorrectExecution(id.OrderId, "exec3", "exec1", *IntToDecimal64(22), *IntToDecimal64(3)))
+
+	order, _, _ := orderCache.GetOrder(id.OrderId)
+	assert.True(t, order.TradedQuantity.Equal(IntToDecimal64(3)))
+	assert.True(t, order.AvgTradePrice.Equal(IntToDecimal64(22)))
+}
+
+func TestExecutionOfStoredOrderIsNotAppliedAgainIfNotRecorded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache, err := ordermanagement.NewOwnerOrderCache(ctx, "", newTestOrderStore())
+	assert.NoError(t, err)
+
+	topic := &testExecutionsTopic{}
+	store, err := executions.NewStore(ctx, "", topic, topic)
+	assert.NoError(t, err)
+
+	manager := NewOrderManager(ctx, cache, store, &TestOrderManager{}, func(ctx context.Context, listingId int32,
+		result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: 1}}
+	}, nil, nil, 100)
+
+	id, err := manager.CreateAndRouteOrder(&api.CreateAndRouteOrderParams{OrderSide: model.Side_BUY,
+		Quantity: IntToDecimal64(10), Price: IntToDecimal64(20), ListingId: 1})
+	assert.NoError(t, err)
+
+	topic.err = errors.New("broker unavailable")
+	assert.Error(t, manager.AddExecution(id.OrderId, *IntToDecimal64(20), *IntToDecimal64(4), "exec1"))
+	assert.Empty(t, store.Get(id.OrderId))
+
+	// the execution is applied to the stored order so a resend of it is a duplicate
+	topic.err = nil
+	assert.NoError(t, manager.AddExecution(id.OrderId, *IntToDecimal64(20), *IntToDecimal64(4), "exec1"))
+
+	order, _, _ := cache.GetOrder(id.OrderId)
+	assert.True(t, order.TradedQuantity.Equal(IntToDecimal64(4)))
+
+	assert.NoError(t, manager.AddExecution(id.OrderId, *IntToDecimal64(22), *IntToDecimal64(2), "exec2"))
+	assert.Equal(t, executions.Executions{
+		{Id: "exec1", Price: *IntToDecimal64(20), Qty: *IntToDecimal64(4)},
+		{Id: "exec2", Price: *IntToDecimal64(22), Qty: *IntToDecimal64(2)},
+	}, store.Get(id.OrderId))
+
+	assert.NoError(t, manager.CancelExecution(id.OrderId, "exec3", "exec1"))
+
+	order, _, _ = cache.GetOrder(id.OrderId)
+	assert.True(t, order.TradedQuantity.Equal(IntToDecimal64(2)))
+	assert.True(t, order.AvgTradePrice.Equal(IntToDecimal64(22)))
+}
+
 type TestOrderManager struct {
 }
 
-func (f *TestOrderManager) Send(_ *model.Order, _ *model.Listing) error {
+func (f *TestOrderManager) Send(_ *model.Order, _ *model.Listing, _ *orderparams.Parameters) error {
 	return nil
 }
 
@@ -241,10 +515,41 @@ func (f *TestOrderManager) Cancel(_ *model.Order) error {
 	return nil
 }
 
-func (f *TestOrderManager) Modify(_ *model.Order, _ *model.Listing, _ *model.Decimal64, _ *model.Decimal64) error {
+func (f *TestOrderManager) Modify(_ *model.Order, _ *model.Listing, _ *model.Decimal64, _ *model.Decimal64,
+	_ *orderparams.Parameters) error {
+	return nil
+}
+
+// testExecutionsTopic is an empty executions topic that discards the executions written to it, or fails the write if
+// err is set.
+type testExecutionsTopic struct {
+	err error
+}
+
+func (t *testExecutionsTopic) ReadMessage(context.Context) (kafka.Message, error) {
+	return kafka.Message{}, nil
+}
+
+func (t *testExecutionsTopic) ReadLag(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (t *testExecutionsTopic) Close() error {
 	return nil
 }
 
+func (t *testExecutionsTopic) WriteMessages(context.Context, ...kafka.Message) error {
+	return t.err
+}
+
+func newTestExecutionStore() *executions.Store {
+	store, err := executions.NewStore(context.Background(), "", &testExecutionsTopic{}, &testExecutionsTopic{})
+	if err != nil {
+		panic(err)
+	}
+	return store
+}
+
 func newTestOrderStore() *testOrderStore {
 	t := testOrderStore{
 		orders:    make([]*model.Order, 0, 10),
@@ -280,3 +585,228 @@ func (t *testOrderStore) GetOrder(orderId string) (model.Order, bool) {
 func (t *testOrderStore) Close() {
 
 }
+
+type sentOrder struct {
+	order  *model.Order
+	params *orderparams.Parameters
+}
+
+type recordingGateway struct {
+	sent chan sentOrder
+}
+
+func (r *recordingGateway) Send(order *model.Order, _ *model.Listing, params *orderparams.Parameters) error {
+	r.sent <- sentOrder{order: order, params: params}
+	return nil
+}
+
+func (r *recordingGateway) Cancel(_ *model.Order) error {
+	return nil
+}
+
+func (r *recordingGateway) Modify(_ *model.Order, _ *model.Listing, _ *model.Decimal64, _ *model.Decimal64,
+	_ *orderparams.Parameters) error {
+	return nil
+}
+
+type testQuoteStream struct {
+	subscriptions chan int32
+	quotes        chan *model.ClobQuote
+}
+
+func newTestQuoteStream() *testQuoteStream {
+	return &testQuoteStream{subscriptions: make(chan int32, 10), quotes: make(chan *model.ClobQuote)}
+}
+
+func (t *testQuoteStream) Subscribe(listingId int32) error {
+	t.subscriptions <- listingId
+	return nil
+}
+
+func (t *testQuoteStream) Chan() <-chan *model.ClobQuote {
+	return t.quotes
+}
+
+func (t *testQuoteStream) Close() {
+}
+
+func newLocalStopsOrderManager(ctx context.Context, t *testing.T, restoredOrders []*model.Order) (*orderManagerImpl,
+	*ordermanagement.OrderCache, *recordingGateway, *testQuoteStream) {
+	cache, err := ordermanagement.NewOwnerOrderCache(ctx, "", newTestOrderStore())
+	assert.NoError(t, err)
+
+	gateway := &recordingGateway{sent: make(chan sentOrder, 10)}
+	quoteStream := newTestQuoteStream()
+
+	stopsOm := NewOrderManager(ctx, cache, newTestExecutionStore(), gateway, func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: listingId}}
+	}, quoteStream, restoredOrders, 100)
+
+	return stopsOm, cache, gateway, quoteStream
+}
+
+func TestStopOrderIsHeldUntilTriggered(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopsOm, cache, gateway, quoteStream := newLocalStopsOrderManager(ctx, t, nil)
+
+	id, err := stopsOm.CreateAndRouteOrder(&api.CreateAndRouteOrderParams{
+		OrderSide:          model.Side_BUY,
+		Quantity:           IntToDecimal64(10),
+		ListingId:          1,
+		ExecParametersJson: `{"orderType":"STOP","stopPrice":25}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), <-quoteStream.subscriptions)
+
+	order, _, _ := cache.GetOrder(id.OrderId)
+	assert.Equal(t, model.OrderStatus_LIVE, order.Status)
+	assert.Equal(t, model.OrderStatus_NONE, order.TargetStatus)
+
+	quoteStream.quotes <- &model.ClobQuote{ListingId: 1, LastPrice: IntToDecimal64(24)}
+	quoteStream.quotes <- &model.ClobQuote{ListingId: 2, LastPrice: IntToDecimal64(30)}
+	assert.Empty(t, gateway.sent)
+
+	quoteStream.quotes <- &model.ClobQuote{ListingId: 1, LastPrice: IntToDecimal64(25)}
+	sent := <-gateway.sent
+	assert.Equal(t, id.OrderId, sent.order.Id)
+	assert.Equal(t, orderparams.Market, sent.params.OrderType)
+	assert.Nil(t, sent.params.GetStopPrice())
+
+	order, _, _ = cache.GetOrder(id.OrderId)
+	assert.Equal(t, model.OrderStatus_LIVE, order.TargetStatus)
+	params, err := orderparams.Parse(order.ExecParametersJson)
+	assert.NoError(t, err)
+	assert.Equal(t, orderparams.Stop, params.OrderType)
+	assert.True(t, params.StopTriggered)
+
+	quoteStream.quotes <- &model.ClobQuote{ListingId: 1, LastPrice: IntToDecimal64(26)}
+	assert.Empty(t, gateway.sent)
+}
+
+func TestSellStopLimitOrderIsSentAsLimitOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopsOm, _, gateway, quoteStream := newLocalStopsOrderManager(ctx, t, nil)
+
+	id, err := stopsOm.CreateAndRouteOrder(&api.CreateAndRouteOrderParams{
+		OrderSide:          model.Side_SELL,
+		Quantity:           IntToDecimal64(10),
+		Price:              IntToDecimal64(19),
+		ListingId:          1,
+		ExecParametersJson: `{"orderType":"STOP_LIMIT","stopPrice":20,"timeInForce":"IOC"}`,
+	})
+	assert.NoError(t, err)
+
+	quoteStream.quotes <- &model.ClobQuote{ListingId: 1, LastPrice: IntToDecimal64(21)}
+	quoteStream.quotes <- &model.ClobQuote{ListingId: 1, LastPrice: IntToDecimal64(20)}
+
+	sent := <-gateway.sent
+	assert.Equal(t, id.OrderId, sent.order.Id)
+	assert.Equal(t, orderparams.Limit, sent.params.OrderType)
+	assert.Equal(t, orderparams.ImmediateOrCancel, sent.params.TimeInForce)
+	assert.True(t, proto.Equal(IntToDecimal64(19), sent.order.Price))
+}
+
+func TestCancelHeldStopOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopsOm, cache, gateway, quoteStream := newLocalStopsOrderManager(ctx, t, nil)
+
+	id, err := stopsOm.CreateAndRouteOrder(&api.CreateAndRouteOrderParams{
+		OrderSide:          model.Side_BUY,
+		Quantity:           IntToDecimal64(10),
+		ListingId:          1,
+		ExecParametersJson: `{"orderType":"STOP","stopPrice":25}`,
+	})
+	assert.NoError(t, err)
+
+	err = stopsOm.CancelOrder(&api.CancelOrderParams{OrderId: id.OrderId, ListingId: 1})
+	assert.NoError(t, err)
+
+	order, _, _ := cache.GetOrder(id.OrderId)
+	assert.Equal(t, model.OrderStatus_CANCELLED, order.Status)
+	assert.Equal(t, model.OrderStatus_NONE, order.TargetStatus)
+
+	quoteStream.quotes <- &model.ClobQuote{ListingId: 1, LastPrice: IntToDecimal64(30)}
+	assert.Empty(t, gateway.sent)
+}
+
+func TestModifyHeldStopOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopsOm, cache, _, _ := newLocalStopsOrderManager(ctx, t, nil)
+
+	id, err := stopsOm.CreateAndRouteOrder(&api.CreateAndRouteOrderParams{
+		OrderSide:          model.Side_BUY,
+		Quantity:           IntToDecimal64(10),
+		Price:              IntToDecimal64(26),
+		ListingId:          1,
+		ExecParametersJson: `{"orderType":"STOP_LIMIT","stopPrice":25}`,
+	})
+	assert.NoError(t, err)
+
+	err = stopsOm.ModifyOrder(&api.ModifyOrderParams{OrderId: id.OrderId, ListingId: 1, Quantity: IntToDecimal64(20)})
+	assert.Error(t, err)
+
+	err = stopsOm.ModifyOrder(&api.ModifyOrderParams{OrderId: id.OrderId, ListingId: 1, Quantity: IntToDecimal64(20),
+		Price: IntToDecimal64(27)})
+	assert.NoError(t, err)
+
+	order, _, _ := cache.GetOrder(id.OrderId)
+	assert.Equal(t, model.OrderStatus_LIVE, order.Status)
+	assert.Equal(t, model.OrderStatus_NONE, order.TargetStatus)
+	assert.True(t, proto.Equal(IntToDecimal64(20), order.Quantity))
+	assert.True(t, proto.Equal(IntToDecimal64(27), order.Price))
+}
+
+func TestUntriggeredStopOrdersAreRestored(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	restoredOrders := []*model.Order{
+		{Id: "untriggered", Side: model.Side_SELL, ListingId: 1, Status: model.OrderStatus_LIVE,
+			ExecParametersJson: `{"orderType":"STOP","stopPrice":20}`},
+		{Id: "triggered", Side: model.Side_SELL, ListingId: 1, Status: model.OrderStatus_LIVE,
+			ExecParametersJson: `{"orderType":"STOP","stopPrice":20,"stopTriggered":true}`},
+		{Id: "cancelled", Side: model.Side_SELL, ListingId: 1, Status: model.OrderStatus_CANCELLED,
+			ExecParametersJson: `{"orderType":"STOP","stopPrice":20}`},
+		{Id: "limit", Side: model.Side_SELL, ListingId: 1, Status: model.OrderStatus_LIVE},
+	}
+
+	stopsOm, _, _, _ := newLocalStopsOrderManager(ctx, t, restoredOrders)
+
+	assert.True(t, stopsOm.isLocalStop(restoredOrders[0]))
+	assert.False(t, stopsOm.isLocalStop(restoredOrders[1]))
+	assert.False(t, stopsOm.isLocalStop(restoredOrders[2]))
+	assert.False(t, stopsOm.isLocalStop(restoredOrders[3]))
+}
+
+func TestStopOrdersAreSentToVenueWithoutQuoteStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache, err := ordermanagement.NewOwnerOrderCache(ctx, "", newTestOrderStore())
+	assert.NoError(t, err)
+	gateway := &recordingGateway{sent: make(chan sentOrder, 10)}
+
+	nativeOm := NewOrderManager(ctx, cache, newTestExecutionStore(), gateway, func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: listingId}}
+	}, nil, nil, 100)
+
+	_, err = nativeOm.CreateAndRouteOrder(&api.CreateAndRouteOrderParams{
+		OrderSide:          model.Side_BUY,
+		Quantity:           IntToDecimal64(10),
+		ListingId:          1,
+		ExecParametersJson: `{"orderType":"STOP","stopPrice":25}`,
+	})
+	assert.NoError(t, err)
+
+	sent := <-gateway.sent
+	assert.Equal(t, orderparams.Stop, sent.params.OrderType)
+	assert.Equal(t, 25.0, sent.params.StopPrice)
+}
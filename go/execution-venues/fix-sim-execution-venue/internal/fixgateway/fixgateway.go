@@ -2,19 +2,23 @@ package fixgateway
 
 import (
 	"fmt"
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/orderparams"
 	"github.com/ettec/otp-common/model"
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/quickfix/enum"
 	"github.com/quickfixgo/quickfix/field"
-	"github.com/quickfixgo/quickfix/fix40/ordercancelreject"
-	"github.com/quickfixgo/quickfix/fix42/businessmessagereject"
 	"github.com/quickfixgo/quickfix/fix50sp1/ordercancelreplacerequest"
 	"github.com/quickfixgo/quickfix/fix50sp1/ordercancelrequest"
+	"github.com/quickfixgo/quickfix/fix50sp2/businessmessagereject"
 	"github.com/quickfixgo/quickfix/fix50sp2/executionreport"
 	"github.com/quickfixgo/quickfix/fix50sp2/newordersingle"
+	"github.com/quickfixgo/quickfix/fix50sp2/ordercancelreject"
+	"github.com/quickfixgo/quickfix/fixt11/reject"
+	"github.com/quickfixgo/quickfix/tag"
 	"github.com/shopspring/decimal"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -29,18 +33,28 @@ func NewFixOrderGateway(sessionID quickfix.SessionID) *fixOrderGateway {
 	}
 }
 
-func (f *fixOrderGateway) Send(order *model.Order, listing *model.Listing) error {
+func (f *fixOrderGateway) Send(order *model.Order, listing *model.Listing, params *orderparams.Parameters) error {
 
 	side, err := getFixSide(order.Side)
 	if err != nil {
 		return fmt.Errorf("failed to get fix side: %w", err)
 	}
 
+	ordType, err := getFixOrdType(params.OrderType)
+	if err != nil {
+		return fmt.Errorf("failed to get fix order type: %w", err)
+	}
+
+	timeInForce, err := getFixTimeInForce(params.TimeInForce)
+	if err != nil {
+		return fmt.Errorf("failed to get fix time in force: %w", err)
+	}
+
 	msg := newordersingle.New(field.NewClOrdID(order.Id), field.NewSide(side),
-		field.NewTransactTime(time.Now()), field.NewOrdType(enum.OrdType_LIMIT_OR_BETTER))
+		field.NewTransactTime(time.Now()), field.NewOrdType(ordType))
 
 	msg.SetOrderQty(toFixDecimal(order.GetQuantity()))
-	msg.SetPrice(toFixDecimal(order.GetPrice()))
+	setOrderParameterFields(msg.Message, order.GetPrice(), params, timeInForce)
 	msg.SetSymbol(listing.MarketSymbol)
 
 	logSessionMsg(f.sessionID, "sending new order single:"+toReadableString(msg.Message))
@@ -48,17 +62,28 @@ func (f *fixOrderGateway) Send(order *model.Order, listing *model.Listing) error
 	return quickfix.SendToTarget(msg, f.sessionID)
 }
 
-func (f *fixOrderGateway) Modify(order *model.Order, listing *model.Listing, quantity *model.Decimal64, price *model.Decimal64) error {
+func (f *fixOrderGateway) Modify(order *model.Order, listing *model.Listing, quantity *model.Decimal64,
+	price *model.Decimal64, params *orderparams.Parameters) error {
 	side, err := getFixSide(order.Side)
 	if err != nil {
 		return err
 	}
 
+	ordType, err := getFixOrdType(params.OrderType)
+	if err != nil {
+		return fmt.Errorf("failed to get fix order type: %w", err)
+	}
+
+	timeInForce, err := getFixTimeInForce(params.TimeInForce)
+	if err != nil {
+		return fmt.Errorf("failed to get fix time in force: %w", err)
+	}
+
 	msg := ordercancelreplacerequest.New(field.NewClOrdID(order.Id), field.NewSide(side),
-		field.NewTransactTime(time.Now()), field.NewOrdType(enum.OrdType_LIMIT_OR_BETTER))
+		field.NewTransactTime(time.Now()), field.NewOrdType(ordType))
 
 	msg.SetOrderQty(toFixDecimal(quantity))
-	msg.SetPrice(toFixDecimal(price))
+	setOrderParameterFields(msg.Message, price, params, timeInForce)
 	msg.SetSymbol(listing.MarketSymbol)
 
 	logSessionMsg(f.sessionID, "sending order cancel replace request:"+toReadableString(msg.Message))
@@ -66,6 +91,26 @@ func (f *fixOrderGateway) Modify(order *model.Order, listing *model.Listing, qua
 	return quickfix.SendToTarget(msg, f.sessionID)
 }
 
+// setOrderParameterFields sets the price, stop price, time in force and expire time of an order message, market and stop
+// orders are sent without a price.
+func setOrderParameterFields(msg *quickfix.Message, price *model.Decimal64, params *orderparams.Parameters,
+	timeInForce enum.TimeInForce) {
+
+	if price != nil && params.OrderType != orderparams.Market && params.OrderType != orderparams.Stop {
+		msg.Body.Set(field.NewPrice(toFixDecimal(price)))
+	}
+
+	if stopPrice := params.GetStopPrice(); stopPrice != nil {
+		msg.Body.Set(field.NewStopPx(toFixDecimal(stopPrice)))
+	}
+
+	msg.Body.Set(field.NewTimeInForce(timeInForce))
+
+	if expireTime := params.GetExpireTime(); !expireTime.IsZero() {
+		msg.Body.Set(field.NewExpireTime(expireTime))
+	}
+}
+
 func toFixDecimal(d *model.Decimal64) (decimal.Decimal, int32) {
 	var scale int32 = 0
 	if d.Exponent < 0 {
@@ -105,25 +150,134 @@ func getFixSide(side model.Side) (enum.Side, error) {
 
 }
 
+// getFixOrdType returns the fix order type of an order type, limit orders are sent as limit or better orders.
+func getFixOrdType(orderType orderparams.OrderType) (enum.OrdType, error) {
+
+	switch orderType {
+	case orderparams.Market:
+		return enum.OrdType_MARKET, nil
+	case orderparams.Limit:
+		return enum.OrdType_LIMIT_OR_BETTER, nil
+	case orderparams.Stop:
+		return enum.OrdType_STOP, nil
+	case orderparams.StopLimit:
+		return enum.OrdType_STOP_LIMIT, nil
+	default:
+		return "", fmt.Errorf("order type %v not supported", orderType)
+	}
+
+}
+
+func getFixTimeInForce(timeInForce orderparams.TimeInForce) (enum.TimeInForce, error) {
+
+	switch timeInForce {
+	case orderparams.Day:
+		return enum.TimeInForce_DAY, nil
+	case orderparams.ImmediateOrCancel:
+		return enum.TimeInForce_IMMEDIATE_OR_CANCEL, nil
+	case orderparams.FillOrKill:
+		return enum.TimeInForce_FILL_OR_KILL, nil
+	case orderparams.GoodTillDate:
+		return enum.TimeInForce_GOOD_TILL_DATE, nil
+	default:
+		return "", fmt.Errorf("time in force %v not supported", timeInForce)
+	}
+
+}
+
 type OrderHandler interface {
 	SetOrderStatus(orderId string, status model.OrderStatus) error
 	SetErrorMsg(orderId string, msg string) error
 	AddExecution(orderId string, lastPrice model.Decimal64, lastQty model.Decimal64, execId string) error
+	RejectRequest(orderId string) error
+	CancelExecution(orderId string, execId string, refExecId string) error
+	CorrectExecution(orderId string, execId string, refExecId string, lastPrice model.Decimal64, lastQty model.Decimal64) error
+	GetOrder(orderId string) (*model.Order, bool, error)
+	GetActiveOrders() ([]*model.Order, error)
+}
+
+// maxSentRequests is the number of sent order requests per session that can be correlated with a reject by their
+// sequence number.
+const maxSentRequests = 10000
+
+type sentRequest struct {
+	clOrdId string
+	msgType enum.MsgType
+}
+
+// sentRequests maps the sequence numbers of the most recently sent order requests to their ClOrdID and message type.
+type sentRequests struct {
+	mutex    sync.Mutex
+	requests map[int]sentRequest
+	seqNums  []int
+}
+
+func newSentRequests() *sentRequests {
+	return &sentRequests{requests: map[int]sentRequest{}}
+}
+
+func (s *sentRequests) put(seqNum int, request sentRequest) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.requests[seqNum]; !exists {
+		s.seqNums = append(s.seqNums, seqNum)
+		if len(s.seqNums) > maxSentRequests {
+			delete(s.requests, s.seqNums[0])
+			s.seqNums = s.seqNums[1:]
+		}
+	}
+
+	s.requests[seqNum] = request
+}
+
+func (s *sentRequests) get(seqNum int) (sentRequest, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	request, exists := s.requests[seqNum]
+	return request, exists
 }
 
 type fixHandler struct {
-	sessionToHandler map[quickfix.SessionID]OrderHandler
-	inboundRouter    *quickfix.MessageRouter
-	outboundRouter   *quickfix.MessageRouter
+	sessionToHandler      map[quickfix.SessionID]OrderHandler
+	sessionToSentRequests map[quickfix.SessionID]*sentRequests
+	inboundRouter         *quickfix.MessageRouter
+	inboundAdminRouter    *quickfix.MessageRouter
+
+	reconciliationsMutex  sync.Mutex
+	reconciliations       map[quickfix.SessionID]*reconciliation
+	reconciliationTimeout time.Duration
+	publisher             reconciliationPublisher
+	send                  func(m quickfix.Messagable, sessionID quickfix.SessionID) error
+}
+
+// NewFixHandler creates the fix application for the session, the summary of each reconciliation of the session's
+// orders on logon is published to the given publisher.
+func NewFixHandler(sessionID quickfix.SessionID, handler OrderHandler, publisher reconciliationPublisher) quickfix.Application {
+	return newFixHandler(sessionID, handler, defaultReconciliationTimeout, publisher, quickfix.SendToTarget)
 }
 
-func NewFixHandler(sessionID quickfix.SessionID, handler OrderHandler) quickfix.Application {
-	f := fixHandler{sessionToHandler: make(map[quickfix.SessionID]OrderHandler)}
+func newFixHandler(sessionID quickfix.SessionID, handler OrderHandler, reconciliationTimeout time.Duration,
+	publisher reconciliationPublisher, send func(m quickfix.Messagable, sessionID quickfix.SessionID) error) *fixHandler {
+	f := fixHandler{
+		sessionToHandler:      make(map[quickfix.SessionID]OrderHandler),
+		sessionToSentRequests: make(map[quickfix.SessionID]*sentRequests),
+		reconciliations:       make(map[quickfix.SessionID]*reconciliation),
+		reconciliationTimeout: reconciliationTimeout,
+		publisher:             publisher,
+		send:                  send,
+	}
 
 	f.sessionToHandler[sessionID] = handler
+	f.sessionToSentRequests[sessionID] = newSentRequests()
 	f.inboundRouter = quickfix.NewMessageRouter()
 	f.inboundRouter.AddRoute(executionreport.Route(f.onExecutionReport))
 	f.inboundRouter.AddRoute(ordercancelreject.Route(f.onOrderCancelReject))
+	f.inboundRouter.AddRoute(businessmessagereject.Route(f.onBusinessMessageReject))
+
+	f.inboundAdminRouter = quickfix.NewMessageRouter()
+	f.inboundAdminRouter.AddRoute(reject.Route(f.onReject))
 
 	return &f
 }
@@ -164,11 +318,134 @@ func logSessionMsgf(sessionID quickfix.SessionID, format string, v ...any) {
 	slog.Info(fmt.Sprintf(format, v...), "sessionID", sessionID.String())
 }
 
-func (f *fixHandler) onOutboundBusinessMessageReject(msg businessmessagereject.BusinessMessageReject, sessionID quickfix.SessionID) (err quickfix.MessageRejectError) {
-	logSessionMsgf(sessionID, "Sending reject message to target: %v", toReadableString(msg.Message))
+// onBusinessMessageReject rejects the pending request of the order identified by the BusinessRejectRefID of the message,
+// or if that is not set, the order whose request was sent with the RefSeqNum of the message.
+func (f *fixHandler) onBusinessMessageReject(msg businessmessagereject.BusinessMessageReject, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+
+	logSessionMsg(sessionID, "received business message reject:"+toReadableString(msg.Message))
+
+	var request sentRequest
+	if msg.HasBusinessRejectRefID() {
+		refId, msgRejectErr := msg.GetBusinessRejectRefID()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		refMsgType, msgRejectErr := msg.GetRefMsgType()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		request = sentRequest{clOrdId: refId, msgType: enum.MsgType(refMsgType)}
+	} else if msg.HasRefSeqNum() {
+		refSeqNum, msgRejectErr := msg.GetRefSeqNum()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		var exists bool
+		if request, exists = f.getSentRequest(sessionID, refSeqNum); !exists {
+			logSessionMsgf(sessionID, "ignoring business message reject, no order request found for RefSeqNum %v", refSeqNum)
+			return nil
+		}
+	} else {
+		logSessionMsg(sessionID, "ignoring business message reject, neither BusinessRejectRefID nor RefSeqNum is set")
+		return nil
+	}
+
+	reason, msgRejectErr := msg.GetBusinessRejectReason()
+	if msgRejectErr != nil {
+		return msgRejectErr
+	}
+
+	errMsg := fmt.Sprintf("business message reject, reason %v", reason)
+	if msg.HasText() {
+		if errMsg, msgRejectErr = msg.GetText(); msgRejectErr != nil {
+			return msgRejectErr
+		}
+	}
+
+	f.onRequestRejected(sessionID, request, errMsg)
+
+	return nil
+}
+
+// onReject rejects the pending request of the order whose request was sent with the RefSeqNum of the session level
+// reject.  Rejects of messages other than order requests are ignored.
+func (f *fixHandler) onReject(msg reject.Reject, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+
+	logSessionMsg(sessionID, "received session reject:"+toReadableString(msg.Message))
+
+	refSeqNum, msgRejectErr := msg.GetRefSeqNum()
+	if msgRejectErr != nil {
+		return msgRejectErr
+	}
+
+	request, exists := f.getSentRequest(sessionID, refSeqNum)
+	if !exists {
+		logSessionMsgf(sessionID, "ignoring session reject, no order request found for RefSeqNum %v", refSeqNum)
+		return nil
+	}
+
+	errMsg := "session reject"
+	if msg.HasText() {
+		text, msgRejectErr := msg.GetText()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+		errMsg = errMsg + ": " + text
+	}
+
+	if msg.HasSessionRejectReason() {
+		reason, msgRejectErr := msg.GetSessionRejectReason()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+		errMsg = fmt.Sprintf("%v, reason %v", errMsg, reason)
+	}
+
+	f.onRequestRejected(sessionID, request, errMsg)
+
 	return nil
 }
 
+func (f *fixHandler) getSentRequest(sessionID quickfix.SessionID, seqNum int) (sentRequest, bool) {
+	requests, exists := f.sessionToSentRequests[sessionID]
+	if !exists {
+		return sentRequest{}, false
+	}
+
+	return requests.get(seqNum)
+}
+
+// onRequestRejected rejects the pending request of the order, a rejected order status request leaves the order
+// unreconciled.
+func (f *fixHandler) onRequestRejected(sessionID quickfix.SessionID, request sentRequest, errMsg string) {
+	if request.msgType == enum.MsgType_ORDER_STATUS_REQUEST {
+		f.onOrderStatusRequestRejected(sessionID, request.clOrdId, errMsg)
+		return
+	}
+
+	f.rejectRequest(sessionID, request.clOrdId, errMsg)
+}
+
+// rejectRequest sets the error message on the order and moves it to its rejected state.
+func (f *fixHandler) rejectRequest(sessionID quickfix.SessionID, orderId string, errMsg string) {
+	handler, exists := f.sessionToHandler[sessionID]
+	if !exists {
+		logSessionMsg(sessionID, "Error: No handler found for session id")
+		return
+	}
+
+	if err := handler.SetErrorMsg(orderId, errMsg); err != nil {
+		slog.Error("failed to set error msg on order", "orderId", orderId, "errorMessage", errMsg, "error", err)
+	}
+
+	if err := handler.RejectRequest(orderId); err != nil {
+		slog.Error("failed to reject order request", "orderId", orderId, "error", err)
+	}
+}
+
 func (f *fixHandler) onExecutionReport(msg executionreport.ExecutionReport, sessionID quickfix.SessionID) quickfix.MessageRejectError {
 
 	logSessionMsg(sessionID, "received execution report:"+toReadableString(msg.Message))
@@ -189,6 +466,10 @@ func (f *fixHandler) onExecutionReport(msg executionreport.ExecutionReport, sess
 		return msgRejectErr
 	}
 
+	if execType == enum.ExecType_ORDER_STATUS {
+		return f.onOrderStatus(msg, sessionID, handler, orderId)
+	}
+
 	switch execType {
 	case enum.ExecType_NEW:
 		err := handler.SetOrderStatus(orderId, model.OrderStatus_LIVE)
@@ -225,9 +506,61 @@ func (f *fixHandler) onExecutionReport(msg executionreport.ExecutionReport, sess
 			return msgRejectErr
 		}
 
+		cumQty, msgRejectErr := msg.GetCumQty()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		if isFillReconciled(handler, orderId, cumQty) {
+			logSessionMsgf(sessionID, "ignoring execution %v of order %v, its cumulative quantity %v has been reconciled",
+				execId, orderId, cumQty)
+			return nil
+		}
+
 		if err := handler.AddExecution(orderId, *model.ToDecimal64(lastPrice), *model.ToDecimal64(lastQty), execId); err != nil {
 			slog.Error("failed to add execution to order", "orderId", orderId, "error", err)
 		}
+	case enum.ExecType_TRADE_CANCEL:
+		execId, msgRejectErr := msg.GetExecID()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		refExecId, msgRejectErr := msg.GetExecRefID()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		if err := handler.CancelExecution(orderId, execId, refExecId); err != nil {
+			slog.Error("failed to cancel order execution", "orderId", orderId, "execId", execId, "refExecId", refExecId,
+				"error", err)
+		}
+	case enum.ExecType_TRADE_CORRECT:
+		lastQty, msgRejectErr := msg.GetLastQty()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		lastPrice, msgRejectErr := msg.GetLastPx()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		execId, msgRejectErr := msg.GetExecID()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		refExecId, msgRejectErr := msg.GetExecRefID()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		if err := handler.CorrectExecution(orderId, execId, refExecId, *model.ToDecimal64(lastPrice),
+			*model.ToDecimal64(lastQty)); err != nil {
+			slog.Error("failed to correct order execution", "orderId", orderId, "execId", execId, "refExecId", refExecId,
+				"error", err)
+		}
 	}
 
 	return nil
@@ -238,9 +571,10 @@ func (f *fixHandler) OnCreate(sessionID quickfix.SessionID) {
 	logSessionMsg(sessionID, "created")
 }
 
-// Notification of a session successfully logging on.
+// Notification of a session successfully logging on, the state of the active orders is reconciled with the venue.
 func (f *fixHandler) OnLogon(sessionID quickfix.SessionID) {
 	logSessionMsg(sessionID, "logon received")
+	go f.reconcile(sessionID)
 }
 
 // Notification of a session logging off or disconnecting.
@@ -252,13 +586,44 @@ func (f *fixHandler) OnLogout(sessionID quickfix.SessionID) {
 func (f *fixHandler) ToAdmin(message *quickfix.Message, sessionID quickfix.SessionID) {
 }
 
-// Notification of app message being sent to target.
+// Notification of app message being sent to target, the sequence numbers of order and order status requests are
+// recorded so that session level rejects can be correlated with the order.
 func (f *fixHandler) ToApp(message *quickfix.Message, sessionID quickfix.SessionID) error {
+	msgType, err := message.MsgType()
+	if err != nil {
+		return nil
+	}
+
+	switch msgType {
+	case enum.MsgType_ORDER_SINGLE, enum.MsgType_ORDER_CANCEL_REQUEST, enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST,
+		enum.MsgType_ORDER_STATUS_REQUEST:
+		requests, exists := f.sessionToSentRequests[sessionID]
+		if !exists {
+			return nil
+		}
+
+		seqNum, err := message.Header.GetInt(tag.MsgSeqNum)
+		if err != nil {
+			return nil
+		}
+
+		clOrdId, err := message.Body.GetString(tag.ClOrdID)
+		if err != nil {
+			return nil
+		}
+
+		requests.put(seqNum, sentRequest{clOrdId: clOrdId, msgType: msgType})
+	}
+
 	return nil
 }
 
 // Notification of admin message being received from target.
 func (f *fixHandler) FromAdmin(message *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	if msgType, err := message.MsgType(); err == nil && msgType == enum.MsgType_REJECT {
+		return f.inboundAdminRouter.Route(message, sessionID)
+	}
+
 	return nil
 }
 
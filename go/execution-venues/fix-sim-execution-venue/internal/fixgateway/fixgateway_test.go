@@ -1,8 +1,22 @@
 package fixgateway
 
 import (
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/orderparams"
 	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/quickfixgo/quickfix/fix50sp2/businessmessagereject"
+	"github.com/quickfixgo/quickfix/fix50sp2/executionreport"
+	"github.com/quickfixgo/quickfix/fix50sp2/newordersingle"
+	"github.com/quickfixgo/quickfix/fixt11/reject"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"sync"
 	"testing"
+	"time"
 )
 
 func toFixString(decimal64 model.Decimal64) string {
@@ -41,3 +55,319 @@ func TestToFixDecimal(t *testing.T) {
 		})
 	}
 }
+
+func TestSetOrderParameterFields(t *testing.T) {
+	price := &model.Decimal64{Mantissa: 1936, Exponent: -2}
+
+	tests := []struct {
+		name            string
+		params          *orderparams.Parameters
+		wantPrice       string
+		wantStopPx      string
+		wantTimeInForce enum.TimeInForce
+		wantExpireTime  bool
+	}{
+		{name: "limit", params: &orderparams.Parameters{OrderType: orderparams.Limit, TimeInForce: orderparams.Day},
+			wantPrice: "19.36", wantTimeInForce: enum.TimeInForce_DAY},
+		{name: "market", params: &orderparams.Parameters{OrderType: orderparams.Market, TimeInForce: orderparams.ImmediateOrCancel},
+			wantTimeInForce: enum.TimeInForce_IMMEDIATE_OR_CANCEL},
+		{name: "stop", params: &orderparams.Parameters{OrderType: orderparams.Stop, TimeInForce: orderparams.FillOrKill, StopPrice: 19.5},
+			wantStopPx: "19.5", wantTimeInForce: enum.TimeInForce_FILL_OR_KILL},
+		{name: "stop limit gtd", params: &orderparams.Parameters{OrderType: orderparams.StopLimit, TimeInForce: orderparams.GoodTillDate,
+			StopPrice: 19.5, UtcExpireTimeSecs: 1700000000},
+			wantPrice: "19.36", wantStopPx: "19.5", wantTimeInForce: enum.TimeInForce_GOOD_TILL_DATE, wantExpireTime: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := quickfix.NewMessage()
+			timeInForce, err := getFixTimeInForce(tt.params.TimeInForce)
+			assert.NoError(t, err)
+
+			setOrderParameterFields(msg, price, tt.params, timeInForce)
+
+			gotPrice, _ := msg.Body.GetString(tag.Price)
+			assert.Equal(t, tt.wantPrice, gotPrice)
+			gotStopPx, _ := msg.Body.GetString(tag.StopPx)
+			assert.Equal(t, tt.wantStopPx, gotStopPx)
+			gotTimeInForce, _ := msg.Body.GetString(tag.TimeInForce)
+			assert.Equal(t, string(tt.wantTimeInForce), gotTimeInForce)
+			assert.Equal(t, tt.wantExpireTime, msg.Body.Has(tag.ExpireTime))
+		})
+	}
+}
+
+func TestGetFixOrdType(t *testing.T) {
+	tests := []struct {
+		orderType orderparams.OrderType
+		want      enum.OrdType
+		wantErr   bool
+	}{
+		{orderType: orderparams.Market, want: enum.OrdType_MARKET},
+		{orderType: orderparams.Limit, want: enum.OrdType_LIMIT_OR_BETTER},
+		{orderType: orderparams.Stop, want: enum.OrdType_STOP},
+		{orderType: orderparams.StopLimit, want: enum.OrdType_STOP_LIMIT},
+		{orderType: "PEGGED", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.orderType), func(t *testing.T) {
+			got, err := getFixOrdType(tt.orderType)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type testExecution struct {
+	orderId string
+	price   string
+	qty     string
+	execId  string
+}
+
+type testReversal struct {
+	orderId    string
+	execId     string
+	refExecId  string
+	correction *testExecution
+}
+
+type testOrderHandler struct {
+	mutex            sync.Mutex
+	orders           map[string]*model.Order
+	errorMsgs        map[string]string
+	rejectedRequests []string
+	statuses         map[string]model.OrderStatus
+	executions       []testExecution
+	reversals        []testReversal
+}
+
+func newTestOrderHandler(orders ...*model.Order) *testOrderHandler {
+	h := &testOrderHandler{
+		orders:    map[string]*model.Order{},
+		errorMsgs: map[string]string{},
+		statuses:  map[string]model.OrderStatus{},
+	}
+
+	for _, order := range orders {
+		h.orders[order.Id] = order
+	}
+
+	return h
+}
+
+func (h *testOrderHandler) SetOrderStatus(orderId string, status model.OrderStatus) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.statuses[orderId] = status
+	if order, exists := h.orders[orderId]; exists {
+		order.Status = status
+	}
+	return nil
+}
+
+func (h *testOrderHandler) SetErrorMsg(orderId string, msg string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.errorMsgs[orderId] = msg
+	return nil
+}
+
+func (h *testOrderHandler) AddExecution(orderId string, lastPrice model.Decimal64, lastQty model.Decimal64, execId string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.executions = append(h.executions, testExecution{orderId: orderId, price: lastPrice.AsDecimal().String(),
+		qty: lastQty.AsDecimal().String(), execId: execId})
+	if order, exists := h.orders[orderId]; exists {
+		return order.AddExecution(model.Execution{Id: execId, Price: lastPrice, Qty: lastQty})
+	}
+	return nil
+}
+
+func (h *testOrderHandler) RejectRequest(orderId string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.rejectedRequests = append(h.rejectedRequests, orderId)
+	return nil
+}
+
+func (h *testOrderHandler) CancelExecution(orderId string, execId string, refExecId string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.reversals = append(h.reversals, testReversal{orderId: orderId, execId: execId, refExecId: refExecId})
+	return nil
+}
+
+func (h *testOrderHandler) CorrectExecution(orderId string, execId string, refExecId string, lastPrice model.Decimal64,
+	lastQty model.Decimal64) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.reversals = append(h.reversals, testReversal{orderId: orderId, execId: execId, refExecId: refExecId,
+		correction: &testExecution{orderId: orderId, price: lastPrice.AsDecimal().String(),
+			qty: lastQty.AsDecimal().String(), execId: execId}})
+	return nil
+}
+
+func (h *testOrderHandler) GetOrder(orderId string) (*model.Order, bool, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	order, exists := h.orders[orderId]
+	if !exists {
+		return nil, false, nil
+	}
+	return proto.Clone(order).(*model.Order), true, nil
+}
+
+func (h *testOrderHandler) GetActiveOrders() ([]*model.Order, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	var orders []*model.Order
+	for _, order := range h.orders {
+		if !order.IsTerminalState() {
+			orders = append(orders, proto.Clone(order).(*model.Order))
+		}
+	}
+	return orders, nil
+}
+
+var testSessionID = quickfix.SessionID{BeginString: enum.BeginStringFIXT11, SenderCompID: "OTP", TargetCompID: "SIM"}
+
+func newTestFixHandler() (quickfix.Application, *testOrderHandler) {
+	handler := newTestOrderHandler()
+	return NewFixHandler(testSessionID, handler, &testReconciliationPublisher{}), handler
+}
+
+func sendNewOrderSingle(t *testing.T, app quickfix.Application, clOrdId string, seqNum int) {
+	msg := newordersingle.New(field.NewClOrdID(clOrdId), field.NewSide(enum.Side_BUY),
+		field.NewTransactTime(time.Now()), field.NewOrdType(enum.OrdType_LIMIT))
+	msg.Header.Set(field.NewMsgSeqNum(seqNum))
+
+	assert.NoError(t, app.ToApp(msg.Message, testSessionID))
+}
+
+func newBusinessMessageReject(text string) businessmessagereject.BusinessMessageReject {
+	msg := businessmessagereject.New(field.NewRefMsgType(string(enum.MsgType_ORDER_SINGLE)),
+		field.NewBusinessRejectReason(enum.BusinessRejectReason_UNKNOWN_SECURITY))
+	msg.Header.Set(field.NewApplVerID(enum.ApplVerID_FIX50SP2))
+	if text != "" {
+		msg.SetText(text)
+	}
+
+	return msg
+}
+
+func TestBusinessMessageRejectCorrelatedByRefId(t *testing.T) {
+	app, handler := newTestFixHandler()
+
+	msg := newBusinessMessageReject("unknown symbol")
+	msg.SetBusinessRejectRefID("order1")
+
+	assert.Nil(t, app.FromApp(msg.Message, testSessionID))
+
+	assert.Equal(t, map[string]string{"order1": "unknown symbol"}, handler.errorMsgs)
+	assert.Equal(t, []string{"order1"}, handler.rejectedRequests)
+}
+
+func TestBusinessMessageRejectCorrelatedBySeqNum(t *testing.T) {
+	app, handler := newTestFixHandler()
+
+	sendNewOrderSingle(t, app, "order1", 5)
+	sendNewOrderSingle(t, app, "order2", 6)
+
+	msg := newBusinessMessageReject("")
+	msg.SetRefSeqNum(6)
+
+	assert.Nil(t, app.FromApp(msg.Message, testSessionID))
+
+	assert.Equal(t, map[string]string{"order2": "business message reject, reason 2"}, handler.errorMsgs)
+	assert.Equal(t, []string{"order2"}, handler.rejectedRequests)
+}
+
+func TestSessionRejectCorrelatedBySeqNum(t *testing.T) {
+	app, handler := newTestFixHandler()
+
+	sendNewOrderSingle(t, app, "order1", 5)
+
+	msg := reject.New(field.NewRefSeqNum(5))
+	msg.SetText("value is incorrect for this tag")
+	msg.SetSessionRejectReason(enum.SessionRejectReason_VALUE_IS_INCORRECT)
+
+	assert.Nil(t, app.FromAdmin(msg.Message, testSessionID))
+
+	assert.Equal(t, map[string]string{"order1": "session reject: value is incorrect for this tag, reason 5"}, handler.errorMsgs)
+	assert.Equal(t, []string{"order1"}, handler.rejectedRequests)
+}
+
+func TestRejectOfUnknownSeqNumIsIgnored(t *testing.T) {
+	app, handler := newTestFixHandler()
+
+	sendNewOrderSingle(t, app, "order1", 5)
+
+	sessionReject := reject.New(field.NewRefSeqNum(4))
+	assert.Nil(t, app.FromAdmin(sessionReject.Message, testSessionID))
+
+	businessReject := newBusinessMessageReject("unknown")
+	businessReject.SetRefSeqNum(7)
+	assert.Nil(t, app.FromApp(businessReject.Message, testSessionID))
+
+	assert.Empty(t, handler.errorMsgs)
+	assert.Empty(t, handler.rejectedRequests)
+}
+
+func TestSentRequestsAreBounded(t *testing.T) {
+	requests := newSentRequests()
+
+	for i := 1; i <= maxSentRequests+1; i++ {
+		requests.put(i, sentRequest{clOrdId: "order", msgType: enum.MsgType_ORDER_SINGLE})
+	}
+
+	_, exists := requests.get(1)
+	assert.False(t, exists)
+	_, exists = requests.get(maxSentRequests + 1)
+	assert.True(t, exists)
+	assert.Len(t, requests.requests, maxSentRequests)
+}
+
+func newTradeExecutionReport(execType enum.ExecType, execId string, refExecId string, lastPx int64,
+	lastQty int64) *quickfix.Message {
+	msg := executionreport.New(field.NewOrderID("venue-order1"), field.NewExecID(execId), field.NewExecType(execType),
+		field.NewOrdStatus(enum.OrdStatus_PARTIALLY_FILLED), field.NewSide(enum.Side_BUY),
+		field.NewLeavesQty(decimal.New(5, 0), 0), field.NewCumQty(decimal.New(5, 0), 0))
+	msg.Header.Set(field.NewApplVerID(enum.ApplVerID_FIX50SP2))
+	msg.SetClOrdID("order1")
+	msg.SetLastPx(decimal.New(lastPx, 0), 0)
+	msg.SetLastQty(decimal.New(lastQty, 0), 0)
+	if refExecId != "" {
+		msg.SetExecRefID(refExecId)
+	}
+
+	return msg.Message
+}
+
+func TestTradeCancelAndCorrect(t *testing.T) {
+	app, handler := newTestFixHandler()
+
+	assert.Nil(t, app.FromApp(newTradeExecutionReport(enum.ExecType_TRADE, "exec1", "", 100, 5), testSessionID))
+	assert.Nil(t, app.FromApp(newTradeExecutionReport(enum.ExecType_TRADE_CANCEL, "exec2", "exec1", 100, 5), testSessionID))
+	assert.Nil(t, app.FromApp(newTradeExecutionReport(enum.ExecType_TRADE_CORRECT, "exec3", "exec1", 101, 4), testSessionID))
+
+	assert.Equal(t, []testExecution{{orderId: "order1", price: "100", qty: "5", execId: "exec1"}}, handler.executions)
+	assert.Equal(t, []testReversal{
+		{orderId: "order1", execId: "exec2", refExecId: "exec1"},
+		{orderId: "order1", execId: "exec3", refExecId: "exec1",
+			correction: &testExecution{orderId: "order1", price: "101", qty: "4", execId: "exec3"}},
+	}, handler.reversals)
+}
+
+func TestTradeCancelWithoutExecRefIdIsRejected(t *testing.T) {
+	app, handler := newTestFixHandler()
+
+	assert.NotNil(t, app.FromApp(newTradeExecutionReport(enum.ExecType_TRADE_CANCEL, "exec2", "", 100, 5), testSessionID))
+	assert.Empty(t, handler.reversals)
+}
@@ -0,0 +1,39 @@
+package fixgateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaReconciliationPublisher publishes reconciliation summaries as json to a kafka topic, keyed by session id.
+type KafkaReconciliationPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaReconciliationPublisher(writerConfig kafka.WriterConfig) *KafkaReconciliationPublisher {
+	return &KafkaReconciliationPublisher{writer: kafka.NewWriter(writerConfig)}
+}
+
+func (k *KafkaReconciliationPublisher) Publish(ctx context.Context, summary ReconciliationSummary) error {
+	summaryJson, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reconciliation summary: %w", err)
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(summary.SessionID),
+		Value: summaryJson,
+	}
+
+	if err = k.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to write reconciliation summary to kafka: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KafkaReconciliationPublisher) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,334 @@
+package fixgateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/quickfixgo/quickfix/fix50sp2/executionreport"
+	"github.com/quickfixgo/quickfix/fix50sp2/orderstatusrequest"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const defaultReconciliationTimeout = 30 * time.Second
+
+// ReconciliationSummary is the outcome of reconciling the active orders of a session with the venue.
+type ReconciliationSummary struct {
+	SessionID      string    `json:"sessionId"`
+	Time           time.Time `json:"time"`
+	Orders         int       `json:"orders"`
+	InSync         int       `json:"inSync"`
+	FillsApplied   int       `json:"fillsApplied"`
+	CancelsApplied int       `json:"cancelsApplied"`
+	AcksApplied    int       `json:"acksApplied"`
+	// Unknown are the ids of the orders the venue has no record of, they are flagged for manual review.
+	Unknown []string `json:"unknown"`
+	// Mismatched are the ids of the orders whose venue state could not be applied, they are flagged for manual review.
+	Mismatched []string `json:"mismatched"`
+	// Unconfirmed are the ids of the orders whose status request was rejected or not responded to in time.
+	Unconfirmed []string `json:"unconfirmed"`
+}
+
+type reconciliationPublisher interface {
+	Publish(ctx context.Context, summary ReconciliationSummary) error
+}
+
+// reconciliation tracks the order status requests sent for the active orders of a session on logon.
+type reconciliation struct {
+	mutex   sync.Mutex
+	pending map[string]bool
+	summary ReconciliationSummary
+	done    chan struct{}
+}
+
+func newReconciliation(orders []*model.Order) *reconciliation {
+	r := &reconciliation{
+		pending: map[string]bool{},
+		summary: ReconciliationSummary{Orders: len(orders)},
+		done:    make(chan struct{}),
+	}
+
+	for _, order := range orders {
+		r.pending[order.Id] = true
+	}
+
+	if len(r.pending) == 0 {
+		close(r.done)
+	}
+
+	return r
+}
+
+func (r *reconciliation) isPending(orderId string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.pending[orderId]
+}
+
+// complete removes the order from the pending orders and records its outcome in the summary.
+func (r *reconciliation) complete(orderId string, record func(summary *ReconciliationSummary)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.pending[orderId] {
+		return
+	}
+
+	delete(r.pending, orderId)
+	record(&r.summary)
+
+	if len(r.pending) == 0 {
+		close(r.done)
+	}
+}
+
+// finish returns the summary, orders still pending are recorded as unconfirmed.
+func (r *reconciliation) finish() ReconciliationSummary {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for orderId := range r.pending {
+		r.summary.Unconfirmed = append(r.summary.Unconfirmed, orderId)
+	}
+	r.pending = map[string]bool{}
+
+	return r.summary
+}
+
+// reconcile sends an order status request for every active order of the session and applies the fills and cancels
+// that were missed whilst disconnected from the venue, it returns when all requests have been responded to or the
+// reconciliation times out.  The summary of the reconciliation is published.
+func (f *fixHandler) reconcile(sessionID quickfix.SessionID) ReconciliationSummary {
+
+	handler, exists := f.sessionToHandler[sessionID]
+	if !exists {
+		logSessionMsg(sessionID, "Error: No handler found for session id")
+		return ReconciliationSummary{}
+	}
+
+	orders, err := handler.GetActiveOrders()
+	if err != nil {
+		slog.Error("failed to get active orders, orders will not be reconciled", "sessionID", sessionID.String(),
+			"error", err)
+		return ReconciliationSummary{}
+	}
+
+	r := newReconciliation(orders)
+
+	f.reconciliationsMutex.Lock()
+	f.reconciliations[sessionID] = r
+	f.reconciliationsMutex.Unlock()
+
+	logSessionMsgf(sessionID, "reconciling %v active orders with the venue", len(orders))
+
+	for _, order := range orders {
+		side, err := getFixSide(order.Side)
+		if err != nil {
+			r.complete(order.Id, func(summary *ReconciliationSummary) {
+				summary.Unconfirmed = append(summary.Unconfirmed, order.Id)
+			})
+			continue
+		}
+
+		msg := orderstatusrequest.New(field.NewSide(side))
+		msg.SetClOrdID(order.Id)
+
+		if err := f.send(msg, sessionID); err != nil {
+			slog.Error("failed to send order status request", "orderId", order.Id, "error", err)
+			r.complete(order.Id, func(summary *ReconciliationSummary) {
+				summary.Unconfirmed = append(summary.Unconfirmed, order.Id)
+			})
+		}
+	}
+
+	select {
+	case <-r.done:
+	case <-time.After(f.reconciliationTimeout):
+		logSessionMsg(sessionID, "timed out waiting for order status responses")
+	}
+
+	f.reconciliationsMutex.Lock()
+	if f.reconciliations[sessionID] == r {
+		delete(f.reconciliations, sessionID)
+	}
+	f.reconciliationsMutex.Unlock()
+
+	summary := r.finish()
+	summary.SessionID = sessionID.String()
+	summary.Time = time.Now()
+
+	slog.Info("order reconciliation complete", "sessionID", sessionID.String(), "orders", summary.Orders,
+		"inSync", summary.InSync, "fillsApplied", summary.FillsApplied, "cancelsApplied", summary.CancelsApplied,
+		"acksApplied", summary.AcksApplied, "unknown", summary.Unknown, "mismatched", summary.Mismatched,
+		"unconfirmed", summary.Unconfirmed)
+
+	if err := f.publisher.Publish(context.Background(), summary); err != nil {
+		slog.Error("failed to publish order reconciliation summary", "sessionID", sessionID.String(), "error", err)
+	}
+
+	return summary
+}
+
+func (f *fixHandler) getReconciliation(sessionID quickfix.SessionID) *reconciliation {
+	f.reconciliationsMutex.Lock()
+	defer f.reconciliationsMutex.Unlock()
+
+	return f.reconciliations[sessionID]
+}
+
+func (f *fixHandler) onOrderStatusRequestRejected(sessionID quickfix.SessionID, orderId string, errMsg string) {
+	logSessionMsgf(sessionID, "order status request for order %v rejected: %v", orderId, errMsg)
+
+	if r := f.getReconciliation(sessionID); r != nil {
+		r.complete(orderId, func(summary *ReconciliationSummary) {
+			summary.Unconfirmed = append(summary.Unconfirmed, orderId)
+		})
+	}
+}
+
+// onOrderStatus applies the venue's state of an order in response to an order status request.  Missed fills are added
+// as a single execution at the price that brings the order's average price in line with the venue, missed cancels and
+// acknowledgements of new orders are applied to the order status.  Orders unknown to the venue or whose state cannot
+// be applied are flagged for manual review via their error message.
+func (f *fixHandler) onOrderStatus(msg executionreport.ExecutionReport, sessionID quickfix.SessionID,
+	handler OrderHandler, orderId string) quickfix.MessageRejectError {
+
+	r := f.getReconciliation(sessionID)
+	if r == nil || !r.isPending(orderId) {
+		logSessionMsgf(sessionID, "ignoring order status for order %v, no reconciliation in progress", orderId)
+		return nil
+	}
+
+	ordStatus, msgRejectErr := msg.GetOrdStatus()
+	if msgRejectErr != nil {
+		return msgRejectErr
+	}
+
+	if ordStatus == enum.OrdStatus_REJECTED {
+		flagForReview(handler, orderId, "order is unknown to the venue")
+		r.complete(orderId, func(summary *ReconciliationSummary) {
+			summary.Unknown = append(summary.Unknown, orderId)
+		})
+		return nil
+	}
+
+	cumQty, msgRejectErr := msg.GetCumQty()
+	if msgRejectErr != nil {
+		return msgRejectErr
+	}
+
+	order, exists, err := handler.GetOrder(orderId)
+	if err != nil || !exists {
+		slog.Error("failed to get order to reconcile", "orderId", orderId, "error", err)
+		r.complete(orderId, func(summary *ReconciliationSummary) {
+			summary.Unconfirmed = append(summary.Unconfirmed, orderId)
+		})
+		return nil
+	}
+
+	mismatched := func(reason string) {
+		flagForReview(handler, orderId, reason)
+		r.complete(orderId, func(summary *ReconciliationSummary) {
+			summary.Mismatched = append(summary.Mismatched, orderId)
+		})
+	}
+
+	fillApplied := false
+	tradedQty := order.GetTradedQuantity().AsDecimal()
+	missedQty := cumQty.Sub(tradedQty)
+	if missedQty.LessThan(decimal.New(0, 0)) {
+		mismatched(fmt.Sprintf("venue cumulative quantity %v is less than the traded quantity %v", cumQty, tradedQty))
+		return nil
+	}
+
+	if missedQty.GreaterThan(decimal.New(0, 0)) {
+		if !msg.HasAvgPx() {
+			mismatched(fmt.Sprintf("venue cumulative quantity %v differs from the traded quantity %v and no average price was given",
+				cumQty, tradedQty))
+			return nil
+		}
+
+		avgPx, msgRejectErr := msg.GetAvgPx()
+		if msgRejectErr != nil {
+			return msgRejectErr
+		}
+
+		missedValue := avgPx.Mul(cumQty).Sub(order.GetAvgTradePrice().AsDecimal().Mul(tradedQty))
+		missedPx := missedValue.Div(missedQty)
+		execId := fmt.Sprintf("%v.reconciled.%v", orderId, cumQty)
+
+		if err := handler.AddExecution(orderId, *model.ToDecimal64(missedPx), *model.ToDecimal64(missedQty), execId); err != nil {
+			mismatched(fmt.Sprintf("failed to apply missed fill of %v@%v: %v", missedQty, missedPx, err))
+			return nil
+		}
+
+		fillApplied = true
+		if order, _, err = handler.GetOrder(orderId); err != nil {
+			slog.Error("failed to get order to reconcile", "orderId", orderId, "error", err)
+		}
+	}
+
+	cancelApplied := false
+	ackApplied := false
+	if order != nil && !order.IsTerminalState() {
+		switch ordStatus {
+		case enum.OrdStatus_CANCELED, enum.OrdStatus_EXPIRED, enum.OrdStatus_DONE_FOR_DAY:
+			if err := handler.SetOrderStatus(orderId, model.OrderStatus_CANCELLED); err != nil {
+				mismatched(fmt.Sprintf("failed to apply missed cancel: %v", err))
+				return nil
+			}
+			cancelApplied = true
+		case enum.OrdStatus_NEW, enum.OrdStatus_PARTIALLY_FILLED, enum.OrdStatus_REPLACED:
+			if order.Status == model.OrderStatus_NONE {
+				if err := handler.SetOrderStatus(orderId, model.OrderStatus_LIVE); err != nil {
+					mismatched(fmt.Sprintf("failed to apply missed acknowledgement: %v", err))
+					return nil
+				}
+				ackApplied = true
+			}
+		}
+	}
+
+	r.complete(orderId, func(summary *ReconciliationSummary) {
+		if fillApplied {
+			summary.FillsApplied++
+		}
+		if cancelApplied {
+			summary.CancelsApplied++
+		}
+		if ackApplied {
+			summary.AcksApplied++
+		}
+		if !fillApplied && !cancelApplied && !ackApplied {
+			summary.InSync++
+		}
+	})
+
+	return nil
+}
+
+// isFillReconciled returns true if the order's traded quantity already includes the fill of an execution report with
+// the given cumulative quantity.  Reconciliation applies the fills missed whilst disconnected as a single execution, so
+// when the venue's resend of the missed execution reports is received after the order status the resent fills are
+// already included in the order's traded quantity.
+func isFillReconciled(handler OrderHandler, orderId string, cumQty decimal.Decimal) bool {
+	order, exists, err := handler.GetOrder(orderId)
+	if err != nil || !exists {
+		return false
+	}
+
+	return order.GetTradedQuantity().AsDecimal().GreaterThanOrEqual(cumQty)
+}
+
+func flagForReview(handler OrderHandler, orderId string, reason string) {
+	errMsg := "reconciliation requires manual review: " + reason
+	if err := handler.SetErrorMsg(orderId, errMsg); err != nil {
+		slog.Error("failed to set error msg on order", "orderId", orderId, "errorMessage", errMsg, "error", err)
+	}
+}
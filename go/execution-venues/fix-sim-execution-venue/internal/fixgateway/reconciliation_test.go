@@ -0,0 +1,190 @@
+package fixgateway
+
+import (
+	"context"
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/quickfixgo/quickfix/fix50sp2/executionreport"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+	"time"
+)
+
+func newTestOrder(id string, status model.OrderStatus, targetStatus model.OrderStatus, tradedQty int, avgPrice int) *model.Order {
+	order := model.NewOrder(id, model.Side_BUY, model.IasD(10), model.IasD(10), 1, "desk1", "trader1",
+		"desk1", "trader1", "XNAS")
+	order.Status = status
+	order.TargetStatus = targetStatus
+	order.TradedQuantity = model.IasD(tradedQty)
+	order.RemainingQuantity = model.IasD(10 - tradedQty)
+	order.AvgTradePrice = model.IasD(avgPrice)
+	return order
+}
+
+type testReconciliationPublisher struct {
+	summaries []ReconciliationSummary
+}
+
+func (p *testReconciliationPublisher) Publish(_ context.Context, summary ReconciliationSummary) error {
+	p.summaries = append(p.summaries, summary)
+	return nil
+}
+
+func newOrderStatusReport(orderId string, ordStatus enum.OrdStatus, cumQty int64, avgPx int64) *quickfix.Message {
+	msg := executionreport.New(field.NewOrderID("venue-"+orderId), field.NewExecID("exec-"+orderId),
+		field.NewExecType(enum.ExecType_ORDER_STATUS), field.NewOrdStatus(ordStatus), field.NewSide(enum.Side_BUY),
+		field.NewLeavesQty(decimal.New(10-cumQty, 0), 0), field.NewCumQty(decimal.New(cumQty, 0), 0))
+	msg.Header.Set(field.NewApplVerID(enum.ApplVerID_FIX50SP2))
+	msg.SetClOrdID(orderId)
+	if ordStatus == enum.OrdStatus_REJECTED {
+		msg.SetOrdRejReason(enum.OrdRejReason_UNKNOWN_ORDER)
+	} else {
+		msg.SetAvgPx(decimal.New(avgPx, 0), 0)
+	}
+
+	return msg.Message
+}
+
+func TestReconciliationOnLogon(t *testing.T) {
+	handler := newTestOrderHandler(
+		newTestOrder("inSync", model.OrderStatus_LIVE, model.OrderStatus_NONE, 0, 0),
+		newTestOrder("missedFill", model.OrderStatus_LIVE, model.OrderStatus_NONE, 2, 10),
+		newTestOrder("missedCancel", model.OrderStatus_LIVE, model.OrderStatus_NONE, 0, 0),
+		newTestOrder("missedAck", model.OrderStatus_NONE, model.OrderStatus_LIVE, 0, 0),
+		newTestOrder("unknown", model.OrderStatus_LIVE, model.OrderStatus_NONE, 0, 0),
+		newTestOrder("overfilled", model.OrderStatus_LIVE, model.OrderStatus_NONE, 5, 10),
+		newTestOrder("noResponse", model.OrderStatus_LIVE, model.OrderStatus_NONE, 0, 0),
+		newTestOrder("statusRequestRejected", model.OrderStatus_LIVE, model.OrderStatus_NONE, 0, 0),
+		newTestOrder("filled", model.OrderStatus_FILLED, model.OrderStatus_NONE, 10, 10),
+	)
+
+	responses := map[string]*quickfix.Message{
+		"inSync":       newOrderStatusReport("inSync", enum.OrdStatus_NEW, 0, 0),
+		"missedFill":   newOrderStatusReport("missedFill", enum.OrdStatus_PARTIALLY_FILLED, 4, 11),
+		"missedCancel": newOrderStatusReport("missedCancel", enum.OrdStatus_CANCELED, 0, 0),
+		"missedAck":    newOrderStatusReport("missedAck", enum.OrdStatus_NEW, 0, 0),
+		"unknown":      newOrderStatusReport("unknown", enum.OrdStatus_REJECTED, 0, 0),
+		"overfilled":   newOrderStatusReport("overfilled", enum.OrdStatus_PARTIALLY_FILLED, 3, 10),
+	}
+
+	statusRequestReject := newBusinessMessageReject("unsupported message type")
+	statusRequestReject.SetRefMsgType(string(enum.MsgType_ORDER_STATUS_REQUEST))
+	statusRequestReject.SetBusinessRejectRefID("statusRequestRejected")
+	responses["statusRequestRejected"] = statusRequestReject.Message
+
+	publisher := &testReconciliationPublisher{}
+
+	var f *fixHandler
+	var requested []string
+	f = newFixHandler(testSessionID, handler, 100*time.Millisecond, publisher,
+		func(m quickfix.Messagable, sessionID quickfix.SessionID) error {
+			msg := m.ToMessage()
+			msgType, _ := msg.MsgType()
+			assert.Equal(t, enum.MsgType_ORDER_STATUS_REQUEST, msgType)
+
+			clOrdId, _ := msg.Body.GetString(tag.ClOrdID)
+			requested = append(requested, clOrdId)
+
+			if response, exists := responses[clOrdId]; exists {
+				assert.Nil(t, f.FromApp(response, sessionID))
+			}
+			return nil
+		})
+
+	summary := f.reconcile(testSessionID)
+
+	sort.Strings(requested)
+	assert.Equal(t, []string{"inSync", "missedAck", "missedCancel", "missedFill", "noResponse", "overfilled",
+		"statusRequestRejected", "unknown"}, requested)
+
+	assert.Equal(t, 8, summary.Orders)
+	assert.Equal(t, 1, summary.InSync)
+	assert.Equal(t, 1, summary.FillsApplied)
+	assert.Equal(t, 1, summary.CancelsApplied)
+	assert.Equal(t, 1, summary.AcksApplied)
+	assert.Equal(t, []string{"unknown"}, summary.Unknown)
+	assert.Equal(t, []string{"overfilled"}, summary.Mismatched)
+	sort.Strings(summary.Unconfirmed)
+	assert.Equal(t, []string{"noResponse", "statusRequestRejected"}, summary.Unconfirmed)
+	assert.Equal(t, testSessionID.String(), summary.SessionID)
+
+	assert.Equal(t, []ReconciliationSummary{summary}, publisher.summaries)
+
+	assert.Equal(t, []testExecution{{orderId: "missedFill", price: "12", qty: "2", execId: "missedFill.reconciled.4"}},
+		handler.executions)
+	assert.Equal(t, map[string]model.OrderStatus{"missedCancel": model.OrderStatus_CANCELLED,
+		"missedAck": model.OrderStatus_LIVE}, handler.statuses)
+
+	assert.Len(t, handler.errorMsgs, 2)
+	assert.Contains(t, handler.errorMsgs["unknown"], "manual review")
+	assert.Contains(t, handler.errorMsgs["overfilled"], "manual review")
+	assert.Empty(t, handler.rejectedRequests)
+}
+
+func TestOrderStatusIgnoredWhenNoReconciliationInProgress(t *testing.T) {
+	handler := newTestOrderHandler(newTestOrder("order1", model.OrderStatus_LIVE, model.OrderStatus_NONE, 0, 0))
+	f := newFixHandler(testSessionID, handler, time.Second, &testReconciliationPublisher{}, quickfix.SendToTarget)
+
+	assert.Nil(t, f.FromApp(newOrderStatusReport("order1", enum.OrdStatus_CANCELED, 0, 0), testSessionID))
+
+	assert.Empty(t, handler.statuses)
+	assert.Empty(t, handler.executions)
+}
+
+func TestReconciliationWithNoActiveOrders(t *testing.T) {
+	handler := newTestOrderHandler()
+	f := newFixHandler(testSessionID, handler, time.Hour, &testReconciliationPublisher{},
+		func(m quickfix.Messagable, sessionID quickfix.SessionID) error {
+			t.Fatal("no order status request expected")
+			return nil
+		})
+
+	summary := f.reconcile(testSessionID)
+
+	assert.Equal(t, ReconciliationSummary{SessionID: testSessionID.String(), Time: summary.Time}, summary)
+}
+
+func newFillExecutionReport(orderId string, execId string, lastPx int64, lastQty int64, cumQty int64) *quickfix.Message {
+	msg := executionreport.New(field.NewOrderID("venue-"+orderId), field.NewExecID(execId),
+		field.NewExecType(enum.ExecType_TRADE), field.NewOrdStatus(enum.OrdStatus_PARTIALLY_FILLED),
+		field.NewSide(enum.Side_BUY), field.NewLeavesQty(decimal.New(10-cumQty, 0), 0),
+		field.NewCumQty(decimal.New(cumQty, 0), 0))
+	msg.Header.Set(field.NewApplVerID(enum.ApplVerID_FIX50SP2))
+	msg.SetClOrdID(orderId)
+	msg.SetLastPx(decimal.New(lastPx, 0), 0)
+	msg.SetLastQty(decimal.New(lastQty, 0), 0)
+
+	return msg.Message
+}
+
+func TestResentFillsIncludedByReconciliationAreNotAppliedAgain(t *testing.T) {
+	handler := newTestOrderHandler(newTestOrder("order1", model.OrderStatus_LIVE, model.OrderStatus_NONE, 2, 12))
+
+	var f *fixHandler
+	f = newFixHandler(testSessionID, handler, time.Second, &testReconciliationPublisher{},
+		func(m quickfix.Messagable, sessionID quickfix.SessionID) error {
+			assert.Nil(t, f.FromApp(newOrderStatusReport("order1", enum.OrdStatus_PARTIALLY_FILLED, 5, 12), sessionID))
+			return nil
+		})
+
+	summary := f.reconcile(testSessionID)
+	assert.Equal(t, 1, summary.FillsApplied)
+
+	// the venue's resend of the fills missed whilst disconnected is received after the order status
+	assert.Nil(t, f.FromApp(newFillExecutionReport("order1", "exec2", 12, 2, 4), testSessionID))
+	assert.Nil(t, f.FromApp(newFillExecutionReport("order1", "exec3", 12, 1, 5), testSessionID))
+	assert.Nil(t, f.FromApp(newFillExecutionReport("order1", "exec4", 13, 1, 6), testSessionID))
+
+	assert.Equal(t, []testExecution{
+		{orderId: "order1", price: "12", qty: "3", execId: "order1.reconciled.5"},
+		{orderId: "order1", price: "13", qty: "1", execId: "exec4"},
+	}, handler.executions)
+
+	order, _, _ := handler.GetOrder("order1")
+	assert.True(t, order.TradedQuantity.Equal(model.IasD(6)))
+}
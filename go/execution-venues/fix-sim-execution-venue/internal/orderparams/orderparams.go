@@ -0,0 +1,190 @@
+// Package orderparams contains the order type and time in force execution parameters accepted by the fix sim execution
+// venue.
+package orderparams
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"strings"
+	"time"
+)
+
+type OrderType string
+
+const (
+	Market    OrderType = "MARKET"
+	Limit     OrderType = "LIMIT"
+	Stop      OrderType = "STOP"
+	StopLimit OrderType = "STOP_LIMIT"
+)
+
+type TimeInForce string
+
+const (
+	Day               TimeInForce = "DAY"
+	ImmediateOrCancel TimeInForce = "IOC"
+	FillOrKill        TimeInForce = "FOK"
+	GoodTillDate      TimeInForce = "GTD"
+)
+
+// Parameters are the execution parameters of an order, an order without parameters is a limit order that is good for
+// the day.  StopTriggered is not set by clients, it records that a stop order held by the execution venue has been
+// triggered and sent to the market.
+type Parameters struct {
+	OrderType         OrderType   `json:"orderType,omitempty"`
+	TimeInForce       TimeInForce `json:"timeInForce,omitempty"`
+	StopPrice         float64     `json:"stopPrice,omitempty"`
+	UtcExpireTimeSecs int64       `json:"utcExpireTimeSecs,omitempty"`
+	StopTriggered     bool        `json:"stopTriggered,omitempty"`
+}
+
+const Schema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Order parameters",
+  "type": "object",
+  "properties": {
+    "orderType": {
+      "type": "string",
+      "description": "The order type, the price is required for limit and stop limit orders",
+      "enum": ["MARKET", "LIMIT", "STOP", "STOP_LIMIT"],
+      "default": "LIMIT"
+    },
+    "timeInForce": {
+      "type": "string",
+      "description": "How long the order remains in effect",
+      "enum": ["DAY", "IOC", "FOK", "GTD"],
+      "default": "DAY"
+    },
+    "stopPrice": {
+      "type": "number",
+      "description": "The last traded price at which a stop or stop limit order is triggered",
+      "exclusiveMinimum": 0
+    },
+    "utcExpireTimeSecs": {
+      "type": "integer",
+      "description": "The expiry time of a GTD order in seconds since the unix epoch",
+      "exclusiveMinimum": 0
+    }
+  }
+}`
+
+// Parse parses and validates the execution parameters of an order, empty parameters are those of a limit order that
+// is good for the day.
+func Parse(paramsJson string) (*Parameters, error) {
+	params := &Parameters{}
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), params); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal parameters: %w", err)
+		}
+	}
+
+	if params.OrderType == "" {
+		params.OrderType = Limit
+	}
+
+	if params.TimeInForce == "" {
+		params.TimeInForce = Day
+	}
+
+	switch params.OrderType {
+	case Market, Limit:
+		if params.StopPrice != 0 {
+			return nil, fmt.Errorf("stop price is only valid for stop and stop limit orders, order type: %v", params.OrderType)
+		}
+	case Stop, StopLimit:
+		if params.StopPrice <= 0 {
+			return nil, fmt.Errorf("stop price must be greater than 0 for %v orders, stop price: %v", params.OrderType,
+				params.StopPrice)
+		}
+	default:
+		return nil, fmt.Errorf("order type %v not supported", params.OrderType)
+	}
+
+	switch params.TimeInForce {
+	case Day, ImmediateOrCancel, FillOrKill:
+		if params.UtcExpireTimeSecs != 0 {
+			return nil, fmt.Errorf("expire time is only valid for GTD orders, time in force: %v", params.TimeInForce)
+		}
+	case GoodTillDate:
+		if params.UtcExpireTimeSecs <= 0 {
+			return nil, fmt.Errorf("expire time must be set for GTD orders")
+		}
+	default:
+		return nil, fmt.Errorf("time in force %v not supported", params.TimeInForce)
+	}
+
+	return params, nil
+}
+
+// RequiresPrice returns true if orders of the parameters' type must have a price.
+func (p *Parameters) RequiresPrice() bool {
+	return p.OrderType == Limit || p.OrderType == StopLimit
+}
+
+func (p *Parameters) IsStop() bool {
+	return p.OrderType == Stop || p.OrderType == StopLimit
+}
+
+// Triggered returns the parameters of the order that is sent to the market when a stop order is triggered, a stop
+// order becomes a market order and a stop limit order becomes a limit order.
+func (p *Parameters) Triggered() *Parameters {
+	triggered := *p
+	triggered.StopPrice = 0
+	triggered.StopTriggered = true
+
+	switch p.OrderType {
+	case Stop:
+		triggered.OrderType = Market
+	case StopLimit:
+		triggered.OrderType = Limit
+	}
+
+	return &triggered
+}
+
+// IsTriggeredBy returns true if a trade at the given price triggers a stop order on the given side, a buy stop is
+// triggered by a trade at or above the stop price and a sell stop by a trade at or below it.
+func (p *Parameters) IsTriggeredBy(side model.Side, lastPrice *model.Decimal64) bool {
+	if !p.IsStop() || lastPrice == nil {
+		return false
+	}
+
+	stopPrice := decimal.NewFromFloat(p.StopPrice)
+	switch side {
+	case model.Side_BUY:
+		return lastPrice.AsDecimal().GreaterThanOrEqual(stopPrice)
+	case model.Side_SELL:
+		return lastPrice.AsDecimal().LessThanOrEqual(stopPrice)
+	default:
+		return false
+	}
+}
+
+// GetStopPrice returns the stop price as a decimal, nil if the parameters have no stop price.
+func (p *Parameters) GetStopPrice() *model.Decimal64 {
+	if p.StopPrice == 0 {
+		return nil
+	}
+
+	return model.ToDecimal64(decimal.NewFromFloat(p.StopPrice))
+}
+
+// GetExpireTime returns the expire time of a GTD order, the zero time if the parameters have no expire time.
+func (p *Parameters) GetExpireTime() time.Time {
+	if p.UtcExpireTimeSecs == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(p.UtcExpireTimeSecs, 0).UTC()
+}
+
+func (p *Parameters) ToJson() (string, error) {
+	paramsJson, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal parameters: %w", err)
+	}
+
+	return string(paramsJson), nil
+}
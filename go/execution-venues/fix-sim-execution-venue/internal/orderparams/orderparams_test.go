@@ -0,0 +1,81 @@
+package orderparams
+
+import (
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    *Parameters
+		wantErr bool
+	}{
+		{name: "empty is a day limit order", json: "", want: &Parameters{OrderType: Limit, TimeInForce: Day}},
+		{name: "market ioc", json: `{"orderType":"MARKET","timeInForce":"IOC"}`,
+			want: &Parameters{OrderType: Market, TimeInForce: ImmediateOrCancel}},
+		{name: "stop", json: `{"orderType":"STOP","stopPrice":10.5}`,
+			want: &Parameters{OrderType: Stop, TimeInForce: Day, StopPrice: 10.5}},
+		{name: "stop limit gtd", json: `{"orderType":"STOP_LIMIT","stopPrice":10,"timeInForce":"GTD","utcExpireTimeSecs":1700000000}`,
+			want: &Parameters{OrderType: StopLimit, TimeInForce: GoodTillDate, StopPrice: 10, UtcExpireTimeSecs: 1700000000}},
+		{name: "stop without stop price", json: `{"orderType":"STOP"}`, wantErr: true},
+		{name: "limit with stop price", json: `{"orderType":"LIMIT","stopPrice":10}`, wantErr: true},
+		{name: "gtd without expire time", json: `{"timeInForce":"GTD"}`, wantErr: true},
+		{name: "day with expire time", json: `{"utcExpireTimeSecs":1700000000}`, wantErr: true},
+		{name: "unknown order type", json: `{"orderType":"PEGGED"}`, wantErr: true},
+		{name: "unknown time in force", json: `{"timeInForce":"GTC"}`, wantErr: true},
+		{name: "invalid json", json: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.json)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsTriggeredBy(t *testing.T) {
+	stop := &Parameters{OrderType: Stop, StopPrice: 20}
+	limit := &Parameters{OrderType: Limit}
+
+	tests := []struct {
+		name      string
+		params    *Parameters
+		side      model.Side
+		lastPrice *model.Decimal64
+		want      bool
+	}{
+		{name: "buy below stop", params: stop, side: model.Side_BUY, lastPrice: &model.Decimal64{Mantissa: 1999, Exponent: -2}, want: false},
+		{name: "buy at stop", params: stop, side: model.Side_BUY, lastPrice: &model.Decimal64{Mantissa: 20}, want: true},
+		{name: "buy above stop", params: stop, side: model.Side_BUY, lastPrice: &model.Decimal64{Mantissa: 21}, want: true},
+		{name: "sell above stop", params: stop, side: model.Side_SELL, lastPrice: &model.Decimal64{Mantissa: 2001, Exponent: -2}, want: false},
+		{name: "sell at stop", params: stop, side: model.Side_SELL, lastPrice: &model.Decimal64{Mantissa: 20}, want: true},
+		{name: "sell below stop", params: stop, side: model.Side_SELL, lastPrice: &model.Decimal64{Mantissa: 19}, want: true},
+		{name: "no last price", params: stop, side: model.Side_BUY, lastPrice: nil, want: false},
+		{name: "not a stop", params: limit, side: model.Side_BUY, lastPrice: &model.Decimal64{Mantissa: 21}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.params.IsTriggeredBy(tt.side, tt.lastPrice))
+		})
+	}
+}
+
+func TestTriggered(t *testing.T) {
+	stop := &Parameters{OrderType: Stop, TimeInForce: FillOrKill, StopPrice: 20}
+	assert.Equal(t, &Parameters{OrderType: Market, TimeInForce: FillOrKill, StopTriggered: true}, stop.Triggered())
+
+	stopLimit := &Parameters{OrderType: StopLimit, TimeInForce: GoodTillDate, StopPrice: 20, UtcExpireTimeSecs: 1700000000}
+	assert.Equal(t, &Parameters{OrderType: Limit, TimeInForce: GoodTillDate, UtcExpireTimeSecs: 1700000000, StopTriggered: true},
+		stopLimit.Triggered())
+}
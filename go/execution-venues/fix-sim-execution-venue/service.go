@@ -3,15 +3,21 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/executions"
 	"github.com/ettec/open-trading-platform/go/execution-venues/fix-sim-execution-venue/internal/executionvenue"
 	common "github.com/ettec/otp-common"
 	api "github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/ordermanagement"
 	"github.com/ettec/otp-common/orderstore"
 	"github.com/ettec/otp-common/staticdata"
+	"github.com/segmentio/kafka-go"
 	"log/slog"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/ettec/otp-common/bootstrap"
 
@@ -42,19 +48,54 @@ func main() {
 	}
 
 	brokers := strings.Split(kafkaBrokers, ",")
-	store, err := orderstore.NewKafkaStore(orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, brokers),
-		orderstore.DefaultWriterConfig(common.ORDERS_TOPIC, brokers), id)
+	// orders are written synchronously so that an order is stored before the executions applied to it are recorded
+	ordersWriterConfig := orderstore.DefaultWriterConfig(common.ORDERS_TOPIC, brokers)
+	ordersWriterConfig.Async = false
+
+	kafkaStore, err := orderstore.NewKafkaStore(orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, brokers),
+		ordersWriterConfig, id)
 
 	if err != nil {
 		log.Panicf("failed to create order store: %v", err)
 	}
 
+	store := &restoringOrderStore{KafkaStore: kafkaStore}
 	orderCache, err := ordermanagement.NewOwnerOrderCache(ctx, id, store)
 
 	if err != nil {
 		log.Panicf("failed to create order cache:%v", err)
 	}
 
+	executionsTopic := bootstrap.GetOptionalEnvVar("EXECUTIONS_TOPIC", "fix-sim-executions")
+	executionsWriterConfig := orderstore.DefaultWriterConfig(executionsTopic, brokers)
+	executionsWriterConfig.Async = false
+	executionsWriterConfig.Balancer = &kafka.Hash{}
+
+	executionStore, err := executions.NewStore(ctx, id,
+		kafka.NewReader(orderstore.DefaultReaderConfig(executionsTopic, brokers)), kafka.NewWriter(executionsWriterConfig))
+	if err != nil {
+		log.Panicf("failed to create execution store: %v", err)
+	}
+
+	var quoteStream marketdata.QuoteStream
+	if bootstrap.GetOptionalBoolEnvVar("FIX_NATIVE_STOP_ORDERS", false) {
+		slog.Info("stop orders will be sent to the venue")
+	} else {
+		mdsAddress, err := k8s.GetServiceAddress("market-data-service")
+		if err != nil {
+			log.Panicf("failed to get market data service address: %v", err)
+		}
+
+		maxConnectRetry := time.Duration(bootstrap.GetOptionalIntEnvVar("MAX_CONNECT_RETRY_SECONDS", 60)) * time.Second
+		quoteStream, err = marketdata.NewQuoteStreamFromMarketDataService(ctx, id, mdsAddress, maxConnectRetry,
+			bootstrap.GetOptionalIntEnvVar("INBOUND_QUOTE_BUFFER_SIZE", 1000))
+		if err != nil {
+			log.Panicf("failed to create quote stream from market data service: %v", err)
+		}
+
+		slog.Info("stop orders will be held and triggered locally", "marketDataService", mdsAddress)
+	}
+
 	beginString := "FIXT.1.1"
 	targetCompID := "EXEC"
 	sendCompID := id
@@ -62,10 +103,15 @@ func main() {
 
 	gateway := fixgateway.NewFixOrderGateway(sessionID)
 
-	om := executionvenue.NewOrderManager(ctx, orderCache, gateway, sds.GetListing,
+	om := executionvenue.NewOrderManager(ctx, orderCache, executionStore, gateway, sds.GetListing, quoteStream, store.restored,
 		bootstrap.GetOptionalIntEnvVar("ORDER_MANAGER_CMD_BUFFER_SIZE", 100))
 
-	closeFixGatewayFn, err := createFixGateway(sessionID, om)
+	reconciliationsTopic := bootstrap.GetOptionalEnvVar("RECONCILIATIONS_TOPIC", "fix-sim-reconciliations")
+	reconciliationPublisher := fixgateway.NewKafkaReconciliationPublisher(
+		orderstore.DefaultWriterConfig(reconciliationsTopic, brokers))
+	defer reconciliationPublisher.Close()
+
+	closeFixGatewayFn, err := createFixGateway(sessionID, om, reconciliationPublisher)
 	if err != nil {
 		log.Panicf("failed to create fix gateway: %v", err)
 	}
@@ -102,6 +148,26 @@ func main() {
 	}
 }
 
+// restoringOrderStore retains the orders loaded by the order cache so that the order manager can restore the stop
+// orders it holds.
+type restoringOrderStore struct {
+	*orderstore.KafkaStore
+	restored []*model.Order
+}
+
+func (r *restoringOrderStore) LoadOrders(ctx context.Context, where func(order *model.Order) bool) (map[string]*model.Order, error) {
+	orders, err := r.KafkaStore.LoadOrders(ctx, where)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range orders {
+		r.restored = append(r.restored, order)
+	}
+
+	return orders, nil
+}
+
 func getFixConfig(sessionId quickfix.SessionID) string {
 
 	allRequiredEnvVars := true
@@ -142,11 +208,12 @@ func getFixConfig(sessionId quickfix.SessionID) string {
 	return template
 }
 
-func createFixGateway(id quickfix.SessionID, handler fixgateway.OrderHandler) (close func(), err error) {
+func createFixGateway(id quickfix.SessionID, handler fixgateway.OrderHandler,
+	publisher *fixgateway.KafkaReconciliationPublisher) (close func(), err error) {
 
 	fixConfig := getFixConfig(id)
 
-	app := fixgateway.NewFixHandler(id, handler)
+	app := fixgateway.NewFixHandler(id, handler, publisher)
 
 	slog.Info("Creating fix engine", "config", fixConfig)
 
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/strategy"
+)
+
+// icebergParameters are the execution parameters of an iceberg order.  Only the display quantity of the order is shown
+// to the market at any one time, when refreshVariancePercent is set each refresh of the displayed quantity is randomised
+// by up to that percentage of the display quantity so that the refreshes are harder to detect.
+type icebergParameters struct {
+	DisplayQuantity        float64 `json:"displayQuantity"`
+	RefreshVariancePercent float64 `json:"refreshVariancePercent"`
+}
+
+const icebergParametersSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Iceberg parameters",
+  "type": "object",
+  "properties": {
+    "displayQuantity": {
+      "type": "number",
+      "description": "The quantity of the order shown to the market at any one time",
+      "exclusiveMinimum": 0
+    },
+    "refreshVariancePercent": {
+      "type": "number",
+      "description": "The maximum random variation of each refresh of the displayed quantity as a percentage of the display quantity",
+      "minimum": 0,
+      "exclusiveMaximum": 100,
+      "default": 0
+    }
+  },
+  "required": ["displayQuantity"]
+}`
+
+func getIcebergParameters(icebergParamsJson string) (*icebergParameters, error) {
+	params := &icebergParameters{}
+	if err := json.Unmarshal([]byte(icebergParamsJson), params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameters: %w", err)
+	}
+
+	if params.DisplayQuantity <= 0 {
+		return nil, fmt.Errorf("display quantity must be greater than 0, display quantity: %v", params.DisplayQuantity)
+	}
+
+	if params.RefreshVariancePercent < 0 || params.RefreshVariancePercent >= 100 {
+		return nil, fmt.Errorf("refresh variance must be greater than or equal to 0 and less than 100, refresh variance: %v",
+			params.RefreshVariancePercent)
+	}
+
+	return params, nil
+}
+
+// getSliceQuantity returns the quantity of the next displayed slice of the order, randomFloat must return a number in
+// the interval [0,1).  The slice is at least one lot and no more than the available quantity.
+func getSliceQuantity(params *icebergParameters, listing *model.Listing, availableQty *model.Decimal64,
+	randomFloat func() float64) *model.Decimal64 {
+
+	variance := params.RefreshVariancePercent / 100 * (2*randomFloat() - 1)
+	quantity := listing.RoundToLotSize(params.DisplayQuantity * (1 + variance))
+
+	minQuantity := listing.RoundToLotSize(1)
+	if quantity.LessThan(minQuantity) {
+		quantity = minQuantity
+	}
+
+	if quantity.GreaterThan(availableQty) {
+		quantity = availableQty
+	}
+
+	return quantity
+}
+
+// executeAsIcebergStrategy keeps one child order for a slice of the order live until the order is done, a nil params
+// sends no child orders and is used where the order is to be cancelled before it is worked.
+func executeAsIcebergStrategy(ctx context.Context, om *strategy.Strategy, params *icebergParameters, listing *model.Listing,
+	randomFloat func() float64) {
+
+	go func() {
+
+		if om.ParentOrder.GetTargetStatus() == model.OrderStatus_LIVE {
+			err := om.ParentOrder.SetStatus(model.OrderStatus_LIVE)
+			if err != nil {
+				msg := fmt.Sprintf("failed to set order status, cancelling order:%v", err)
+				om.Log.Error(msg)
+				om.CancelChan <- msg
+			}
+		}
+
+		om.Log.Info("order initialised", "params", params)
+
+		lastChildOrderId := ""
+
+		for {
+			done, err := om.CheckIfDone(ctx)
+			if err != nil {
+				msg := fmt.Sprintf("failed to check if done, cancelling order:%v", err)
+				om.Log.Error(msg)
+				om.CancelChan <- msg
+			}
+
+			if done {
+				break
+			}
+
+			if params != nil && om.ParentOrder.GetTargetStatus() == model.OrderStatus_NONE &&
+				!om.ParentOrder.IsTerminalState() && !hasLiveChildOrder(om.ParentOrder.ChildOrders) {
+				lastChildOrderId, err = replenish(om, params, listing, randomFloat, lastChildOrderId)
+				if err != nil {
+					om.ParentOrder.ErrorMessage = err.Error()
+					if err := om.CancelChildOrdersAndStrategyOrder(); err != nil {
+						om.Log.Error("failed to cancel order", "error", err)
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case errMsg := <-om.CancelChan:
+				if errMsg != "" {
+					om.ParentOrder.ErrorMessage = errMsg
+				}
+				err := om.CancelChildOrdersAndStrategyOrder()
+				if err != nil {
+					om.Log.Error("failed to cancel order", "error", err)
+				}
+			case co, ok := <-om.ChildOrderUpdateChan:
+				err = om.OnChildOrderUpdate(ok, co)
+				if err != nil {
+					om.Log.Error("failed to process child order update", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// replenish sends the next displayed slice of the order once the previous slice has been filled and returns the id of
+// the sent child order.  An error is returned if the previous slice was cancelled, e.g. it was rejected by the venue,
+// rather than resend a slice that is likely to meet the same fate.
+func replenish(om *strategy.Strategy, params *icebergParameters, listing *model.Listing, randomFloat func() float64,
+	lastChildOrderId string) (string, error) {
+
+	if lastChildOrder, ok := om.ParentOrder.ChildOrders[lastChildOrderId]; ok &&
+		lastChildOrder.GetStatus() == model.OrderStatus_CANCELLED {
+		return lastChildOrderId, fmt.Errorf("child order %v was cancelled:%v", lastChildOrderId,
+			lastChildOrder.GetErrorMessage())
+	}
+
+	availableQty := om.ParentOrder.GetAvailableQty()
+	if !availableQty.GreaterThan(&model.Decimal64{}) {
+		return lastChildOrderId, nil
+	}
+
+	quantity := getSliceQuantity(params, listing, availableQty, randomFloat)
+	err := om.SendChildOrder(om.ParentOrder.Side, quantity, om.ParentOrder.Price, listing.Id, listing.Market.Mic, "")
+	if err != nil {
+		return lastChildOrderId, fmt.Errorf("failed to send child order:%w", err)
+	}
+
+	return getLiveChildOrderId(om.ParentOrder.ChildOrders), nil
+}
+
+func hasLiveChildOrder(childOrders map[string]*model.Order) bool {
+	for _, co := range childOrders {
+		if !co.IsTerminalState() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getLiveChildOrderId(childOrders map[string]*model.Order) string {
+	for id, co := range childOrders {
+		if !co.IsTerminalState() {
+			return id
+		}
+	}
+
+	return ""
+}
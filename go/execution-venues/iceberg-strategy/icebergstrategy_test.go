@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_getIcebergParameters(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		json       string
+		wantParams *icebergParameters
+		wantErr    bool
+	}{
+		{name: "display quantity only", json: `{"displayQuantity":100}`,
+			wantParams: &icebergParameters{DisplayQuantity: 100}},
+		{name: "with refresh variance", json: `{"displayQuantity":100,"refreshVariancePercent":20}`,
+			wantParams: &icebergParameters{DisplayQuantity: 100, RefreshVariancePercent: 20}},
+		{name: "missing display quantity", json: `{"refreshVariancePercent":20}`, wantErr: true},
+		{name: "negative display quantity", json: `{"displayQuantity":-10}`, wantErr: true},
+		{name: "negative refresh variance", json: `{"displayQuantity":100,"refreshVariancePercent":-1}`, wantErr: true},
+		{name: "refresh variance of 100", json: `{"displayQuantity":100,"refreshVariancePercent":100}`, wantErr: true},
+		{name: "invalid json", json: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := getIcebergParameters(tt.json)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantParams, params)
+			}
+		})
+	}
+}
+
+func Test_icebergParametersSchemaIsValidJson(t *testing.T) {
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(icebergParametersSchema), &schema))
+}
+
+func Test_getSliceQuantity(t *testing.T) {
+
+	listing := &model.Listing{}
+
+	tests := []struct {
+		name         string
+		params       *icebergParameters
+		availableQty *model.Decimal64
+		random       float64
+		want         *model.Decimal64
+	}{
+		{name: "no variance", params: &icebergParameters{DisplayQuantity: 100}, availableQty: model.IasD(1000),
+			random: 0.9, want: model.IasD(100)},
+		{name: "lowest variance", params: &icebergParameters{DisplayQuantity: 100, RefreshVariancePercent: 20},
+			availableQty: model.IasD(1000), random: 0, want: model.IasD(80)},
+		{name: "mid variance", params: &icebergParameters{DisplayQuantity: 100, RefreshVariancePercent: 20},
+			availableQty: model.IasD(1000), random: 0.5, want: model.IasD(100)},
+		{name: "high variance", params: &icebergParameters{DisplayQuantity: 100, RefreshVariancePercent: 20},
+			availableQty: model.IasD(1000), random: 0.75, want: model.IasD(110)},
+		{name: "limited to available quantity", params: &icebergParameters{DisplayQuantity: 100},
+			availableQty: model.IasD(30), random: 0.5, want: model.IasD(30)},
+		{name: "at least one lot", params: &icebergParameters{DisplayQuantity: 0.2},
+			availableQty: model.IasD(30), random: 0.5, want: model.IasD(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getSliceQuantity(tt.params, listing, tt.availableQty, func() float64 { return tt.random })
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
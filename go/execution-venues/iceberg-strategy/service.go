@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	common "github.com/ettec/otp-common"
+	"github.com/ettec/otp-common/api"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/ordermanagement"
+	"github.com/ettec/otp-common/orderstore"
+	"github.com/ettec/otp-common/staticdata"
+	"github.com/ettec/otp-common/strategy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"log"
+	"log/slog"
+	"math/rand"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// icebergStrategyService publishes the iceberg parameters schema in addition to the strategy manager's execution venue api.
+type icebergStrategyService struct {
+	*strategy.Manager
+}
+
+func (i *icebergStrategyService) GetExecutionParametersMetaData(context.Context, *model.Empty) (*executionvenue.ExecParamsMetaDataJson, error) {
+	return &executionvenue.ExecParamsMetaDataJson{Json: icebergParametersSchema}, nil
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	id := bootstrap.GetEnvVar("ID")
+	maxConnectRetry := time.Duration(bootstrap.GetOptionalIntEnvVar("MAX_CONNECT_RETRY_SECONDS", 60)) * time.Second
+	kafkaBrokersString := bootstrap.GetEnvVar("KAFKA_BROKERS")
+
+	slog.Info("Starting iceberg strategy")
+
+	kafkaBrokers := strings.Split(kafkaBrokersString, ",")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sds, err := staticdata.NewStaticDataSource(ctx)
+	if err != nil {
+		log.Panicf("failed to create static data source:%v", err)
+	}
+
+	orderRouter, err := api.GetOrderRouter(k8s.GetK8sClientSet(false), maxConnectRetry)
+	if err != nil {
+		log.Panicf("failed to get order router: %v", err)
+	}
+
+	executeFn := func(om *strategy.Strategy) {
+
+		om.Log.Info("executing strategy", "params", om.ParentOrder.GetExecParametersJson())
+
+		params, err := getIcebergParameters(om.ParentOrder.GetExecParametersJson())
+		if err != nil {
+			om.CancelChan <- fmt.Sprintf("invalid parameters:%v", err)
+		}
+
+		listingIn := make(chan staticdata.ListingResult)
+		sds.GetListing(ctx, om.ParentOrder.ListingId, listingIn)
+		listingResult := <-listingIn
+		close(listingIn)
+		if listingResult.Err != nil {
+			om.CancelChan <- fmt.Sprintf("failed to get listing %v", listingResult.Err)
+			listingResult.Listing = &model.Listing{Id: om.ParentOrder.ListingId, Market: &model.Market{}}
+			params = nil
+		}
+
+		executeAsIcebergStrategy(ctx, om, params, listingResult.Listing, rand.Float64)
+	}
+
+	store, err := orderstore.NewKafkaStore(orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers),
+		orderstore.DefaultWriterConfig(common.ORDERS_TOPIC, kafkaBrokers), id)
+
+	if err != nil {
+		log.Panicf("failed to create order store: %v", err)
+	}
+
+	childOrderUpdates, err := ordermanagement.GetChildOrders(ctx, id, orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers),
+		bootstrap.GetOptionalIntEnvVar("ICEBERGSTRATEGY_CHILD_ORDER_UPDATES_BUFFER_SIZE", 1000))
+
+	if err != nil {
+		log.Panicf("failed to create child order updates channel:%v", err)
+	}
+
+	distributor := ordermanagement.NewChildOrderUpdatesDistributor(childOrderUpdates, 10000)
+
+	sm, err := strategy.NewStrategyManager(ctx, id, store, distributor, orderRouter, executeFn)
+	if err != nil {
+		log.Panicf("failed to create strategy manager:%v", err)
+	}
+
+	s := grpc.NewServer()
+	executionvenue.RegisterExecutionVenueServer(s, &icebergStrategyService{Manager: sm})
+
+	reflection.Register(s)
+
+	port := "50551"
+	slog.Info("Starting Execution Venue Service", "port", port)
+	lis, err := net.Listen("tcp", "0.0.0.0:"+port)
+
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		s.GracefulStop()
+	}()
+
+	if err := s.Serve(lis); err != nil {
+		log.Panicf("error while serving : %v", err)
+	}
+
+}
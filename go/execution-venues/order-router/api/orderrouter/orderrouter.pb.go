@@ -0,0 +1,323 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: orderrouter.proto
+
+package orderrouter
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// The connection state is the grpc connectivity state of the router's connection to the venue, new orders are only
+// routed to a venue that is not healthy if none of the venues for its mic are healthy
+type RoutedVenue struct {
+	Mic                  string   `protobuf:"bytes,1,opt,name=mic,proto3" json:"mic,omitempty"`
+	Ordinal              int32    `protobuf:"varint,2,opt,name=ordinal,proto3" json:"ordinal,omitempty"`
+	OwnerId              string   `protobuf:"bytes,3,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	TargetAddress        string   `protobuf:"bytes,4,opt,name=targetAddress,proto3" json:"targetAddress,omitempty"`
+	ConnectionState      string   `protobuf:"bytes,5,opt,name=connectionState,proto3" json:"connectionState,omitempty"`
+	Healthy              bool     `protobuf:"varint,6,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	PendingRequests      int32    `protobuf:"varint,7,opt,name=pendingRequests,proto3" json:"pendingRequests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoutedVenue) Reset()         { *m = RoutedVenue{} }
+func (m *RoutedVenue) String() string { return proto.CompactTextString(m) }
+func (*RoutedVenue) ProtoMessage()    {}
+func (*RoutedVenue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c95472f792eafa6, []int{0}
+}
+
+func (m *RoutedVenue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoutedVenue.Unmarshal(m, b)
+}
+func (m *RoutedVenue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoutedVenue.Marshal(b, m, deterministic)
+}
+func (m *RoutedVenue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoutedVenue.Merge(m, src)
+}
+func (m *RoutedVenue) XXX_Size() int {
+	return xxx_messageInfo_RoutedVenue.Size(m)
+}
+func (m *RoutedVenue) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoutedVenue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoutedVenue proto.InternalMessageInfo
+
+func (m *RoutedVenue) GetMic() string {
+	if m != nil {
+		return m.Mic
+	}
+	return ""
+}
+
+func (m *RoutedVenue) GetOrdinal() int32 {
+	if m != nil {
+		return m.Ordinal
+	}
+	return 0
+}
+
+func (m *RoutedVenue) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *RoutedVenue) GetTargetAddress() string {
+	if m != nil {
+		return m.TargetAddress
+	}
+	return ""
+}
+
+func (m *RoutedVenue) GetConnectionState() string {
+	if m != nil {
+		return m.ConnectionState
+	}
+	return ""
+}
+
+func (m *RoutedVenue) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *RoutedVenue) GetPendingRequests() int32 {
+	if m != nil {
+		return m.PendingRequests
+	}
+	return 0
+}
+
+// Requests pending for an owner that is not currently known to the router, these are replayed if the owner returns
+type PendingOwnerRequests struct {
+	OwnerId              string   `protobuf:"bytes,1,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	PendingRequests      int32    `protobuf:"varint,2,opt,name=pendingRequests,proto3" json:"pendingRequests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PendingOwnerRequests) Reset()         { *m = PendingOwnerRequests{} }
+func (m *PendingOwnerRequests) String() string { return proto.CompactTextString(m) }
+func (*PendingOwnerRequests) ProtoMessage()    {}
+func (*PendingOwnerRequests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c95472f792eafa6, []int{1}
+}
+
+func (m *PendingOwnerRequests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingOwnerRequests.Unmarshal(m, b)
+}
+func (m *PendingOwnerRequests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingOwnerRequests.Marshal(b, m, deterministic)
+}
+func (m *PendingOwnerRequests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingOwnerRequests.Merge(m, src)
+}
+func (m *PendingOwnerRequests) XXX_Size() int {
+	return xxx_messageInfo_PendingOwnerRequests.Size(m)
+}
+func (m *PendingOwnerRequests) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingOwnerRequests.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingOwnerRequests proto.InternalMessageInfo
+
+func (m *PendingOwnerRequests) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *PendingOwnerRequests) GetPendingRequests() int32 {
+	if m != nil {
+		return m.PendingRequests
+	}
+	return 0
+}
+
+type RoutingTable struct {
+	Venues               []*RoutedVenue          `protobuf:"bytes,1,rep,name=venues,proto3" json:"venues,omitempty"`
+	UnknownOwnerRequests []*PendingOwnerRequests `protobuf:"bytes,2,rep,name=unknownOwnerRequests,proto3" json:"unknownOwnerRequests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *RoutingTable) Reset()         { *m = RoutingTable{} }
+func (m *RoutingTable) String() string { return proto.CompactTextString(m) }
+func (*RoutingTable) ProtoMessage()    {}
+func (*RoutingTable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c95472f792eafa6, []int{2}
+}
+
+func (m *RoutingTable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoutingTable.Unmarshal(m, b)
+}
+func (m *RoutingTable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoutingTable.Marshal(b, m, deterministic)
+}
+func (m *RoutingTable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoutingTable.Merge(m, src)
+}
+func (m *RoutingTable) XXX_Size() int {
+	return xxx_messageInfo_RoutingTable.Size(m)
+}
+func (m *RoutingTable) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoutingTable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoutingTable proto.InternalMessageInfo
+
+func (m *RoutingTable) GetVenues() []*RoutedVenue {
+	if m != nil {
+		return m.Venues
+	}
+	return nil
+}
+
+func (m *RoutingTable) GetUnknownOwnerRequests() []*PendingOwnerRequests {
+	if m != nil {
+		return m.UnknownOwnerRequests
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RoutedVenue)(nil), "orderrouter.RoutedVenue")
+	proto.RegisterType((*PendingOwnerRequests)(nil), "orderrouter.PendingOwnerRequests")
+	proto.RegisterType((*RoutingTable)(nil), "orderrouter.RoutingTable")
+}
+
+func init() { proto.RegisterFile("orderrouter.proto", fileDescriptor_5c95472f792eafa6) }
+
+var fileDescriptor_5c95472f792eafa6 = []byte{
+	// 325 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0x3d, 0x4f, 0xf3, 0x30,
+	0x10, 0x7e, 0xdd, 0xbe, 0x6d, 0xc1, 0x29, 0x2a, 0xb5, 0x3a, 0x98, 0x4e, 0xa1, 0x62, 0xc8, 0x14,
+	0xa1, 0xb2, 0xb2, 0x30, 0x20, 0x84, 0x18, 0x8a, 0xcc, 0xc7, 0xc0, 0x96, 0xc6, 0xa7, 0x34, 0x22,
+	0x39, 0x07, 0xc7, 0xa1, 0xea, 0x2f, 0xe1, 0x27, 0xf2, 0x37, 0x90, 0x5d, 0x22, 0x25, 0x25, 0x5b,
+	0xee, 0xf9, 0x38, 0x3f, 0x77, 0x39, 0x3a, 0x55, 0x5a, 0x82, 0xd6, 0xaa, 0x32, 0xa0, 0xc3, 0x42,
+	0x2b, 0xa3, 0x98, 0xd7, 0x80, 0xe6, 0xd3, 0x5c, 0x49, 0xc8, 0x62, 0x95, 0xe7, 0x0a, 0xf7, 0xfc,
+	0xe2, 0x9b, 0x50, 0x4f, 0x58, 0x56, 0xbe, 0x02, 0x56, 0xc0, 0x4e, 0x69, 0x3f, 0x4f, 0x63, 0x4e,
+	0x7c, 0x12, 0x1c, 0x0b, 0xfb, 0xc9, 0x38, 0x1d, 0x29, 0x2d, 0x53, 0x8c, 0x32, 0xde, 0xf3, 0x49,
+	0x30, 0x10, 0x75, 0xe9, 0x98, 0x2d, 0x82, 0xbe, 0x97, 0xbc, 0xef, 0xf4, 0x75, 0xc9, 0x2e, 0xe8,
+	0x89, 0x89, 0x74, 0x02, 0xe6, 0x46, 0x4a, 0x0d, 0x65, 0xc9, 0xff, 0x3b, 0xbe, 0x0d, 0xb2, 0x80,
+	0x4e, 0x62, 0x85, 0x08, 0xb1, 0x49, 0x15, 0x3e, 0x99, 0xc8, 0x00, 0x1f, 0x38, 0xdd, 0x21, 0x6c,
+	0x5f, 0xda, 0x40, 0x94, 0x99, 0xcd, 0x8e, 0x0f, 0x7d, 0x12, 0x1c, 0x89, 0xba, 0xb4, 0x3d, 0x0a,
+	0x40, 0x99, 0x62, 0x22, 0xe0, 0xa3, 0x82, 0xd2, 0x94, 0x7c, 0xe4, 0x52, 0x1e, 0xc2, 0x8b, 0x37,
+	0x3a, 0x7b, 0xdc, 0x43, 0x2b, 0x9b, 0xb2, 0xc6, 0x9b, 0x53, 0x90, 0xf6, 0x14, 0x1d, 0xbd, 0x7b,
+	0xdd, 0xbd, 0xbf, 0x08, 0x1d, 0xdb, 0x2d, 0xa6, 0x98, 0x3c, 0x47, 0xeb, 0x0c, 0xd8, 0x25, 0x1d,
+	0x7e, 0xda, 0x7d, 0x96, 0x9c, 0xf8, 0xfd, 0xc0, 0x5b, 0xf2, 0xb0, 0xf9, 0x6b, 0x1a, 0x0b, 0x17,
+	0xbf, 0x3a, 0xf6, 0x42, 0x67, 0x15, 0xbe, 0xa3, 0xda, 0x62, 0x2b, 0x1e, 0xef, 0x39, 0xff, 0x79,
+	0xcb, 0xdf, 0x35, 0x87, 0xe8, 0xb4, 0x2f, 0x1f, 0xa8, 0xb7, 0xb2, 0x4e, 0xf7, 0xa4, 0x66, 0xd7,
+	0x74, 0x72, 0x07, 0xa6, 0x15, 0x75, 0x1c, 0xba, 0xab, 0x08, 0x6f, 0xf3, 0xc2, 0xec, 0xe6, 0x67,
+	0x7f, 0x82, 0xd6, 0xc2, 0xc5, 0xbf, 0xf5, 0xd0, 0xdd, 0xcc, 0xd5, 0xcf, 0x00, 0xc4, 0x11, 0x7b,
+	0xab, 0x68, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// OrderRouterClient is the client API for OrderRouter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type OrderRouterClient interface {
+	GetRoutingTable(ctx context.Context, in *model.Empty, opts ...grpc.CallOption) (*RoutingTable, error)
+}
+
+type orderRouterClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOrderRouterClient(cc *grpc.ClientConn) OrderRouterClient {
+	return &orderRouterClient{cc}
+}
+
+func (c *orderRouterClient) GetRoutingTable(ctx context.Context, in *model.Empty, opts ...grpc.CallOption) (*RoutingTable, error) {
+	out := new(RoutingTable)
+	err := c.cc.Invoke(ctx, "/orderrouter.OrderRouter/GetRoutingTable", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderRouterServer is the server API for OrderRouter service.
+type OrderRouterServer interface {
+	GetRoutingTable(context.Context, *model.Empty) (*RoutingTable, error)
+}
+
+// UnimplementedOrderRouterServer can be embedded to have forward compatible implementations.
+type UnimplementedOrderRouterServer struct {
+}
+
+func (*UnimplementedOrderRouterServer) GetRoutingTable(ctx context.Context, req *model.Empty) (*RoutingTable, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoutingTable not implemented")
+}
+
+func RegisterOrderRouterServer(s *grpc.Server, srv OrderRouterServer) {
+	s.RegisterService(&_OrderRouter_serviceDesc, srv)
+}
+
+func _OrderRouter_GetRoutingTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(model.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderRouterServer).GetRoutingTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderrouter.OrderRouter/GetRoutingTable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderRouterServer).GetRoutingTable(ctx, req.(*model.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _OrderRouter_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "orderrouter.OrderRouter",
+	HandlerType: (*OrderRouterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRoutingTable",
+			Handler:    _OrderRouter_GetRoutingTable_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "orderrouter.proto",
+}
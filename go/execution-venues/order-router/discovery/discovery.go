@@ -0,0 +1,39 @@
+// Package discovery contains the sources from which the order router discovers the execution venues it routes to.
+package discovery
+
+import (
+	"context"
+	"github.com/ettec/otp-common/loadbalancing"
+)
+
+type EventType int
+
+const (
+	VenueAdded EventType = iota
+	VenueRemoved
+)
+
+func (e EventType) String() string {
+	switch e {
+	case VenueAdded:
+		return "VenueAdded"
+	case VenueRemoved:
+		return "VenueRemoved"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event reports that an execution venue has been added or removed, the venue's name is the owner id of the orders it
+// manages and its ordinal is its index amongst the venues of its mic.
+type Event struct {
+	Type  EventType
+	Venue *loadbalancing.BalancingStatefulPod
+}
+
+// VenueDiscovery reports the execution venues that are available to the order router.
+type VenueDiscovery interface {
+	// Watch returns a channel that reports an added event for each currently known venue followed by subsequent
+	// changes, the channel is closed when the context is cancelled or discovery fails.
+	Watch(ctx context.Context) (<-chan Event, error)
+}
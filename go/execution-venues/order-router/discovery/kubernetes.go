@@ -0,0 +1,141 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"github.com/ettec/otp-common/loadbalancing"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"log/slog"
+)
+
+const ExecVenueLabelSelector = "servicetype in (execution-venue, execution-venue-and-market-data-gateway)"
+
+// KubernetesDiscovery discovers execution venues by watching the pods that match a label selector, the pods must have
+// a mic label and an api container port.
+type KubernetesDiscovery struct {
+	clientSet     kubernetes.Interface
+	namespace     string
+	labelSelector string
+}
+
+func NewKubernetesDiscovery(clientSet kubernetes.Interface, namespace string, labelSelector string) *KubernetesDiscovery {
+	return &KubernetesDiscovery{clientSet: clientSet, namespace: namespace, labelSelector: labelSelector}
+}
+
+// Watch watches the venue pods.  The venue pods are listed before each watch is established, including when the watch
+// is re-established after being closed by the server, and the differences from the venues already reported are
+// reported so that venues removed whilst there was no watch are reported as removed.
+func (k *KubernetesDiscovery) Watch(ctx context.Context) (<-chan Event, error) {
+	current, pods, err := k.listAndWatchPods()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+		defer func() {
+			if pods != nil {
+				pods.Stop()
+			}
+		}()
+
+		if !sendEvents(ctx, events, diffVenues(nil, current)) {
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-pods.ResultChan():
+				if !ok {
+					slog.Info("execution venue pod watch closed, re-establishing watch")
+					var listed map[string]*loadbalancing.BalancingStatefulPod
+					listed, pods, err = k.listAndWatchPods()
+					if err != nil {
+						slog.Error("failed to re-establish execution venue pod watch", "error", err)
+						return
+					}
+
+					changes := diffVenues(current, listed)
+					current = listed
+					if !sendEvents(ctx, events, changes) {
+						return
+					}
+					continue
+				}
+
+				if e.Type != watch.Added && e.Type != watch.Deleted {
+					continue
+				}
+
+				pod, ok := e.Object.(*corev1.Pod)
+				if !ok {
+					continue
+				}
+
+				bsp, err := loadbalancing.GetBalancingStatefulPod(*pod)
+				if err != nil {
+					slog.Error("failed to get balancing stateful pod, ignoring pod", "pod", pod.Name, "error", err)
+					continue
+				}
+
+				updated := make(map[string]*loadbalancing.BalancingStatefulPod, len(current))
+				for name, venue := range current {
+					updated[name] = venue
+				}
+
+				if e.Type == watch.Added {
+					updated[bsp.Name] = bsp
+				} else {
+					delete(updated, bsp.Name)
+				}
+
+				changes := diffVenues(current, updated)
+				current = updated
+				if !sendEvents(ctx, events, changes) {
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// listAndWatchPods returns the current venue pods and a watch of the changes to the pods from the time of the list.
+func (k *KubernetesDiscovery) listAndWatchPods() (map[string]*loadbalancing.BalancingStatefulPod, watch.Interface, error) {
+	list, err := k.clientSet.CoreV1().Pods(k.namespace).List(v1.ListOptions{
+		LabelSelector: k.labelSelector,
+	})
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods with label selector \"%s\", error: %w", k.labelSelector, err)
+	}
+
+	venues := map[string]*loadbalancing.BalancingStatefulPod{}
+	for _, pod := range list.Items {
+		bsp, err := loadbalancing.GetBalancingStatefulPod(pod)
+		if err != nil {
+			slog.Error("failed to get balancing stateful pod, ignoring pod", "pod", pod.Name, "error", err)
+			continue
+		}
+		venues[bsp.Name] = bsp
+	}
+
+	pods, err := k.clientSet.CoreV1().Pods(k.namespace).Watch(v1.ListOptions{
+		LabelSelector:   k.labelSelector,
+		ResourceVersion: list.ResourceVersion,
+	})
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to watch pods with label selector \"%s\", error: %w", k.labelSelector, err)
+	}
+
+	return venues, pods, nil
+}
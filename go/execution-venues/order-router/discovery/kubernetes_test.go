@@ -0,0 +1,110 @@
+package discovery
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"testing"
+)
+
+func newVenuePod(name string, mic string) *corev1.Pod {
+	labels := map[string]string{"servicetype": "execution-venue"}
+	if mic != "" {
+		labels["mic"] = mic
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Ports: []corev1.ContainerPort{{Name: "api", ContainerPort: 50551}},
+		}}},
+	}
+}
+
+func TestKubernetesDiscovery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clientSet := fake.NewSimpleClientset()
+	events, err := NewKubernetesDiscovery(clientSet, "default", ExecVenueLabelSelector).Watch(ctx)
+	assert.NoError(t, err)
+
+	pods := clientSet.CoreV1().Pods("default")
+
+	_, err = pods.Create(newVenuePod("no-mic-0", ""))
+	assert.NoError(t, err)
+
+	_, err = pods.Create(newVenuePod("xosr-order-gateway-1", "XOSR"))
+	assert.NoError(t, err)
+
+	e := <-events
+	assert.Equal(t, VenueAdded, e.Type)
+	assert.Equal(t, "XOSR", e.Venue.Mic)
+	assert.Equal(t, 1, e.Venue.Ordinal)
+	assert.Equal(t, "xosr-order-gateway-1.xosr-order-gateway:50551", e.Venue.TargetAddress)
+
+	assert.NoError(t, pods.Delete("xosr-order-gateway-1", &v1.DeleteOptions{}))
+
+	e = <-events
+	assert.Equal(t, VenueRemoved, e.Type)
+	assert.Equal(t, "xosr-order-gateway-1", e.Venue.Name)
+}
+
+func TestKubernetesDiscoveryRelistsWhenWatchIsReestablished(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clientSet := fake.NewSimpleClientset(newVenuePod("xosr-order-gateway-0", "XOSR"),
+		newVenuePod("xosr-order-gateway-1", "XOSR"))
+
+	watchers := make(chan *watch.FakeWatcher, 2)
+	clientSet.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watcher := watch.NewFake()
+		watchers <- watcher
+		return true, watcher, nil
+	})
+
+	events, err := NewKubernetesDiscovery(clientSet, "default", ExecVenueLabelSelector).Watch(ctx)
+	assert.NoError(t, err)
+
+	for _, name := range []string{"xosr-order-gateway-0", "xosr-order-gateway-1"} {
+		e := <-events
+		assert.Equal(t, VenueAdded, e.Type)
+		assert.Equal(t, name, e.Venue.Name)
+	}
+
+	firstWatch := <-watchers
+	firstWatch.Add(newVenuePod("xosr-order-gateway-2", "XOSR"))
+	e := <-events
+	assert.Equal(t, VenueAdded, e.Type)
+	assert.Equal(t, "xosr-order-gateway-2", e.Venue.Name)
+
+	// pods changed whilst there is no watch are reported from the list made when the watch is re-established
+	pods := clientSet.CoreV1().Pods("default")
+	assert.NoError(t, pods.Delete("xosr-order-gateway-1", &v1.DeleteOptions{}))
+	_, err = pods.Create(newVenuePod("xosr-order-gateway-3", "XOSR"))
+	assert.NoError(t, err)
+	firstWatch.Stop()
+
+	e = <-events
+	assert.Equal(t, VenueRemoved, e.Type)
+	assert.Equal(t, "xosr-order-gateway-1", e.Venue.Name)
+
+	e = <-events
+	assert.Equal(t, VenueRemoved, e.Type)
+	assert.Equal(t, "xosr-order-gateway-2", e.Venue.Name)
+
+	e = <-events
+	assert.Equal(t, VenueAdded, e.Type)
+	assert.Equal(t, "xosr-order-gateway-3", e.Venue.Name)
+
+	secondWatch := <-watchers
+	secondWatch.Delete(newVenuePod("xosr-order-gateway-0", "XOSR"))
+	e = <-events
+	assert.Equal(t, VenueRemoved, e.Type)
+	assert.Equal(t, "xosr-order-gateway-0", e.Venue.Name)
+}
@@ -0,0 +1,264 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/otp-common/loadbalancing"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Venue is the static configuration of an execution venue.  The name must match the id the venue is started with as it
+// is the owner id of the orders the venue manages, if the ordinal is not set it is taken from the numeric suffix of the
+// name as with a stateful set pod.
+type Venue struct {
+	Name          string `json:"name"`
+	Mic           string `json:"mic"`
+	TargetAddress string `json:"targetAddress"`
+	Ordinal       *int   `json:"ordinal,omitempty"`
+}
+
+func (v Venue) toBalancingStatefulPod() (*loadbalancing.BalancingStatefulPod, error) {
+	if v.Name == "" || v.Mic == "" || v.TargetAddress == "" {
+		return nil, fmt.Errorf("venue name, mic and target address must be set, venue: %+v", v)
+	}
+
+	var ordinal int
+	if v.Ordinal != nil {
+		ordinal = *v.Ordinal
+	} else {
+		var err error
+		ordinal, err = loadbalancing.GetStatefulSetPodOrdinalFromName(v.Name)
+		if err != nil {
+			return nil, fmt.Errorf("venue has no ordinal and its name does not end with one, venue: %+v", v)
+		}
+	}
+
+	return &loadbalancing.BalancingStatefulPod{
+		TargetAddress: v.TargetAddress,
+		Ordinal:       ordinal,
+		Name:          v.Name,
+		Mic:           v.Mic,
+	}, nil
+}
+
+// ParseVenues parses a json array of venues.
+func ParseVenues(venuesJson []byte) ([]Venue, error) {
+	var venues []Venue
+	if err := json.Unmarshal(venuesJson, &venues); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venues: %w", err)
+	}
+
+	return venues, nil
+}
+
+// ParseVenuesString parses a comma separated list of venues of the form <name>=<mic>@<targetAddress>, e.g.
+// "xosr-order-gateway-0=XOSR@localhost:50551,xosr-order-gateway-1=XOSR@localhost:50552".
+func ParseVenuesString(venuesString string) ([]Venue, error) {
+	var venues []Venue
+	for _, entry := range strings.Split(venuesString, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, micAndAddress, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid venue %q, expected <name>=<mic>@<targetAddress>", entry)
+		}
+
+		mic, address, ok := strings.Cut(micAndAddress, "@")
+		if !ok {
+			return nil, fmt.Errorf("invalid venue %q, expected <name>=<mic>@<targetAddress>", entry)
+		}
+
+		venues = append(venues, Venue{Name: name, Mic: mic, TargetAddress: address})
+	}
+
+	return venues, nil
+}
+
+// StaticDiscovery reports a configured set of venues, when the venues are loaded from a file the file is checked for
+// changes and added and removed events are reported for the differences.
+type StaticDiscovery struct {
+	venues         []Venue
+	path           string
+	reloadInterval time.Duration
+}
+
+// NewStaticDiscovery returns a discovery that reports the given venues.
+func NewStaticDiscovery(venues []Venue) *StaticDiscovery {
+	return &StaticDiscovery{venues: venues}
+}
+
+// NewFileDiscovery returns a discovery that reports the venues in the given json file and checks the file for changes
+// at the given interval.
+func NewFileDiscovery(path string, reloadInterval time.Duration) *StaticDiscovery {
+	return &StaticDiscovery{path: path, reloadInterval: reloadInterval}
+}
+
+func (s *StaticDiscovery) Watch(ctx context.Context) (<-chan Event, error) {
+	venues := s.venues
+	var modTime time.Time
+	if s.path != "" {
+		var err error
+		venues, modTime, err = loadVenuesFile(s.path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := toBalancingStatefulPods(venues)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		if !sendEvents(ctx, events, diffVenues(nil, current)) {
+			return
+		}
+
+		if s.path == "" {
+			<-ctx.Done()
+			return
+		}
+
+		ticker := time.NewTicker(s.reloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reloaded, reloadedModTime, modified, err := reloadVenuesFileIfModified(s.path, modTime)
+				if err != nil {
+					slog.Error("failed to reload venues file, retaining current venues", "path", s.path, "error", err)
+					continue
+				}
+
+				if !modified {
+					continue
+				}
+
+				modTime = reloadedModTime
+				changes := diffVenues(current, reloaded)
+				current = reloaded
+				slog.Info("reloaded venues file", "path", s.path, "changes", len(changes))
+				if !sendEvents(ctx, events, changes) {
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func reloadVenuesFileIfModified(path string, modTime time.Time) (map[string]*loadbalancing.BalancingStatefulPod,
+	time.Time, bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, modTime, false, fmt.Errorf("failed to stat venues file %s: %w", path, err)
+	}
+
+	if info.ModTime().Equal(modTime) {
+		return nil, modTime, false, nil
+	}
+
+	venues, reloadedModTime, err := loadVenuesFile(path)
+	if err != nil {
+		return nil, modTime, false, err
+	}
+
+	reloaded, err := toBalancingStatefulPods(venues)
+	if err != nil {
+		return nil, modTime, false, err
+	}
+
+	return reloaded, reloadedModTime, true, nil
+}
+
+func loadVenuesFile(path string) ([]Venue, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to stat venues file %s: %w", path, err)
+	}
+
+	venuesJson, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read venues file %s: %w", path, err)
+	}
+
+	venues, err := ParseVenues(venuesJson)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse venues file %s: %w", path, err)
+	}
+
+	return venues, info.ModTime(), nil
+}
+
+func toBalancingStatefulPods(venues []Venue) (map[string]*loadbalancing.BalancingStatefulPod, error) {
+	result := map[string]*loadbalancing.BalancingStatefulPod{}
+	for _, venue := range venues {
+		bsp, err := venue.toBalancingStatefulPod()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, exists := result[bsp.Name]; exists {
+			return nil, fmt.Errorf("duplicate venue name %v", bsp.Name)
+		}
+
+		result[bsp.Name] = bsp
+	}
+
+	return result, nil
+}
+
+// diffVenues returns the events that change the previous venues into the current venues, a venue whose configuration
+// has changed is removed and then added.  Removals are reported before additions and events are ordered by venue name.
+func diffVenues(previous map[string]*loadbalancing.BalancingStatefulPod,
+	current map[string]*loadbalancing.BalancingStatefulPod) []Event {
+
+	var removed, added []Event
+	for name, bsp := range previous {
+		if currentBsp, ok := current[name]; !ok || *currentBsp != *bsp {
+			removed = append(removed, Event{Type: VenueRemoved, Venue: bsp})
+		}
+	}
+
+	for name, bsp := range current {
+		if previousBsp, ok := previous[name]; !ok || *previousBsp != *bsp {
+			added = append(added, Event{Type: VenueAdded, Venue: bsp})
+		}
+	}
+
+	byName := func(events []Event) {
+		sort.Slice(events, func(i, j int) bool { return events[i].Venue.Name < events[j].Venue.Name })
+	}
+	byName(removed)
+	byName(added)
+
+	return append(removed, added...)
+}
+
+func sendEvents(ctx context.Context, events chan<- Event, toSend []Event) bool {
+	for _, e := range toSend {
+		select {
+		case events <- e:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package discovery
+
+import (
+	"context"
+	"github.com/ettec/otp-common/loadbalancing"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseVenuesString(t *testing.T) {
+	venues, err := ParseVenuesString("xosr-order-gateway-0=XOSR@localhost:50551, xosr-order-gateway-1=XOSR@localhost:50552")
+	assert.NoError(t, err)
+	assert.Equal(t, []Venue{
+		{Name: "xosr-order-gateway-0", Mic: "XOSR", TargetAddress: "localhost:50551"},
+		{Name: "xosr-order-gateway-1", Mic: "XOSR", TargetAddress: "localhost:50552"},
+	}, venues)
+
+	_, err = ParseVenuesString("xosr-order-gateway-0=localhost:50551")
+	assert.Error(t, err)
+
+	_, err = ParseVenuesString("xosr-order-gateway-0")
+	assert.Error(t, err)
+}
+
+func TestParseVenues(t *testing.T) {
+	venues, err := ParseVenues([]byte(`[{"name":"xnas","mic":"XNAS","targetAddress":"localhost:50551","ordinal":0}]`))
+	assert.NoError(t, err)
+
+	bsp, err := venues[0].toBalancingStatefulPod()
+	assert.NoError(t, err)
+	assert.Equal(t, &loadbalancing.BalancingStatefulPod{Name: "xnas", Mic: "XNAS", TargetAddress: "localhost:50551"}, bsp)
+}
+
+func TestVenueWithoutOrdinalRequiresOrdinalSuffix(t *testing.T) {
+	bsp, err := Venue{Name: "xosr-order-gateway-1", Mic: "XOSR", TargetAddress: "localhost:50552"}.toBalancingStatefulPod()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, bsp.Ordinal)
+
+	_, err = Venue{Name: "xosr", Mic: "XOSR", TargetAddress: "localhost:50552"}.toBalancingStatefulPod()
+	assert.Error(t, err)
+}
+
+func TestStaticDiscovery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	venues, err := ParseVenuesString("xosr-1=XOSR@localhost:50552,xosr-0=XOSR@localhost:50551")
+	assert.NoError(t, err)
+
+	events, err := NewStaticDiscovery(venues).Watch(ctx)
+	assert.NoError(t, err)
+
+	assertEvent(t, events, VenueAdded, "xosr-0")
+	assertEvent(t, events, VenueAdded, "xosr-1")
+
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestStaticDiscoveryRejectsDuplicateVenues(t *testing.T) {
+	venues, err := ParseVenuesString("xosr-0=XOSR@localhost:50551,xosr-0=XOSR@localhost:50552")
+	assert.NoError(t, err)
+
+	_, err = NewStaticDiscovery(venues).Watch(context.Background())
+	assert.Error(t, err)
+}
+
+func TestFileDiscoveryReportsChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "venues.json")
+	writeVenuesFile(t, path, `[{"name":"xosr-0","mic":"XOSR","targetAddress":"localhost:50551"},
+		{"name":"xvwap-0","mic":"XVWAP","targetAddress":"localhost:50561"}]`, time.Now().Add(-time.Minute))
+
+	events, err := NewFileDiscovery(path, 10*time.Millisecond).Watch(ctx)
+	assert.NoError(t, err)
+
+	assertEvent(t, events, VenueAdded, "xosr-0")
+	assertEvent(t, events, VenueAdded, "xvwap-0")
+
+	writeVenuesFile(t, path, `[{"name":"xosr-0","mic":"XOSR","targetAddress":"localhost:50551"},
+		{"name":"xosr-1","mic":"XOSR","targetAddress":"localhost:50552"}]`, time.Now())
+
+	assertEvent(t, events, VenueRemoved, "xvwap-0")
+	assertEvent(t, events, VenueAdded, "xosr-1")
+}
+
+func TestFileDiscoveryRetainsVenuesOnInvalidFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "venues.json")
+	writeVenuesFile(t, path, `[{"name":"xosr-0","mic":"XOSR","targetAddress":"localhost:50551"}]`,
+		time.Now().Add(-time.Minute))
+
+	events, err := NewFileDiscovery(path, 10*time.Millisecond).Watch(ctx)
+	assert.NoError(t, err)
+	assertEvent(t, events, VenueAdded, "xosr-0")
+
+	writeVenuesFile(t, path, `[{`, time.Now().Add(-30*time.Second))
+
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event: %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	writeVenuesFile(t, path, `[]`, time.Now())
+	assertEvent(t, events, VenueRemoved, "xosr-0")
+}
+
+func writeVenuesFile(t *testing.T, path string, venuesJson string, modTime time.Time) {
+	assert.NoError(t, os.WriteFile(path, []byte(venuesJson), 0644))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func assertEvent(t *testing.T, events <-chan Event, eventType EventType, name string) {
+	t.Helper()
+	select {
+	case e := <-events:
+		assert.Equal(t, eventType, e.Type)
+		assert.Equal(t, name, e.Venue.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %v event for venue %v", eventType, name)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/model"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"sync"
+)
+
+// execParamsSchema is the JSON schema of the execution parameters accepted by the execution venues of a destination, a
+// nil schema means the destination does not publish a schema and its execution parameters are not validated.
+type execParamsSchema struct {
+	json   string
+	schema *jsonschema.Schema
+}
+
+func newExecParamsSchema(mic string, schemaJson string) (*execParamsSchema, error) {
+	if strings.TrimSpace(schemaJson) == "" {
+		return &execParamsSchema{}, nil
+	}
+
+	schema, err := jsonschema.CompileString(mic, schemaJson)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile execution parameters schema for destination %v: %w", mic, err)
+	}
+
+	return &execParamsSchema{json: schemaJson, schema: schema}, nil
+}
+
+// validate returns an InvalidArgument error if the execution parameters do not conform to the schema, empty execution
+// parameters are validated as an empty object.
+func (e *execParamsSchema) validate(execParamsJson string) error {
+	if e.schema == nil {
+		return nil
+	}
+
+	if strings.TrimSpace(execParamsJson) == "" {
+		execParamsJson = "{}"
+	}
+
+	var params interface{}
+	decoder := json.NewDecoder(strings.NewReader(execParamsJson))
+	decoder.UseNumber()
+	if err := decoder.Decode(&params); err != nil {
+		return status.Errorf(codes.InvalidArgument, "execution parameters are not valid json: %v", err)
+	}
+
+	if err := e.schema.Validate(params); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid execution parameters: %v", err)
+	}
+
+	return nil
+}
+
+// execParamsSchemaCache caches the execution parameter schemas of destinations, the schema of a destination is fetched
+// from one of its execution venues on first use as the venues may not be ready to serve requests when discovered.
+type execParamsSchemaCache struct {
+	mux     sync.Mutex
+	schemas map[string]*execParamsSchema
+}
+
+func newExecParamsSchemaCache() *execParamsSchemaCache {
+	return &execParamsSchemaCache{schemas: map[string]*execParamsSchema{}}
+}
+
+func (c *execParamsSchemaCache) getSchema(ctx context.Context, mic string,
+	client executionvenue.ExecutionVenueClient) (*execParamsSchema, error) {
+	c.mux.Lock()
+	schema, ok := c.schemas[mic]
+	c.mux.Unlock()
+
+	if ok {
+		return schema, nil
+	}
+
+	metaData, err := client.GetExecutionParametersMetaData(ctx, &model.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution parameters metadata for destination %v: %w", mic, err)
+	}
+
+	schema, err = newExecParamsSchema(mic, metaData.GetJson())
+	if err != nil {
+		return nil, err
+	}
+
+	c.mux.Lock()
+	c.schemas[mic] = schema
+	c.mux.Unlock()
+
+	return schema, nil
+}
+
+// remove discards the cached schema of the destination so that it is fetched again, e.g. after a redeployment of the
+// destination's execution venues.
+func (c *execParamsSchemaCache) remove(mic string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	delete(c.schemas, mic)
+}
+
+// aggregateExecParamsSchemas returns a JSON object whose fields are the destination mics and whose values are the
+// execution parameter schemas of the destinations, destinations without a schema are omitted.
+func aggregateExecParamsSchemas(micToSchema map[string]*execParamsSchema) (string, error) {
+	aggregated := map[string]json.RawMessage{}
+	for mic, schema := range micToSchema {
+		if schema.json != "" {
+			aggregated[mic] = json.RawMessage(schema.json)
+		}
+	}
+
+	result, err := json.Marshal(aggregated)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal execution parameter schemas: %w", err)
+	}
+
+	return string(result), nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+const testSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "targetRatePercent": {"type": "number", "exclusiveMinimum": 0, "maximum": 100},
+    "buckets": {"type": "integer", "minimum": 1}
+  },
+  "required": ["targetRatePercent"]
+}`
+
+type testMetaDataClient struct {
+	executionvenue.ExecutionVenueClient
+	json  string
+	calls int
+}
+
+func (t *testMetaDataClient) GetExecutionParametersMetaData(context.Context, *model.Empty, ...grpc.CallOption) (*executionvenue.ExecParamsMetaDataJson, error) {
+	t.calls++
+	return &executionvenue.ExecParamsMetaDataJson{Json: t.json}, nil
+}
+
+func Test_execParamsSchemaValidate(t *testing.T) {
+	schema, err := newExecParamsSchema("XPOV", testSchema)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "valid", params: `{"targetRatePercent":10,"buckets":5}`},
+		{name: "missing required", params: `{"buckets":5}`, wantErr: true},
+		{name: "empty validated as empty object", params: "", wantErr: true},
+		{name: "out of bounds", params: `{"targetRatePercent":110}`, wantErr: true},
+		{name: "wrong type", params: `{"targetRatePercent":10,"buckets":1.5}`, wantErr: true},
+		{name: "invalid json", params: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.validate(tt.params)
+			if tt.wantErr {
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_execParamsSchemaWithoutSchemaDoesNotValidate(t *testing.T) {
+	schema, err := newExecParamsSchema("XNAS", "")
+	assert.NoError(t, err)
+	assert.NoError(t, schema.validate(`{"anything":1}`))
+	assert.NoError(t, schema.validate(`not json`))
+}
+
+func Test_newExecParamsSchemaRejectsInvalidSchema(t *testing.T) {
+	_, err := newExecParamsSchema("XPOV", `{"type": 5}`)
+	assert.Error(t, err)
+}
+
+func Test_execParamsSchemaCache(t *testing.T) {
+	cache := newExecParamsSchemaCache()
+	client := &testMetaDataClient{json: testSchema}
+
+	schema, err := cache.getSchema(context.Background(), "XPOV", client)
+	assert.NoError(t, err)
+	assert.Equal(t, testSchema, schema.json)
+
+	_, err = cache.getSchema(context.Background(), "XPOV", client)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.calls)
+
+	cache.remove("XPOV")
+	_, err = cache.getSchema(context.Background(), "XPOV", client)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, client.calls)
+}
+
+func Test_aggregateExecParamsSchemas(t *testing.T) {
+	povSchema, err := newExecParamsSchema("XPOV", testSchema)
+	assert.NoError(t, err)
+	dmaSchema, err := newExecParamsSchema("XNAS", "")
+	assert.NoError(t, err)
+
+	aggregated, err := aggregateExecParamsSchemas(map[string]*execParamsSchema{"XPOV": povSchema, "XNAS": dmaSchema})
+	assert.NoError(t, err)
+
+	var micToSchema map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(aggregated), &micToSchema))
+	assert.Len(t, micToSchema, 1)
+	assert.Equal(t, "object", micToSchema["XPOV"]["type"])
+}
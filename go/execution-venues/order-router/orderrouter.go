@@ -3,55 +3,78 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/ettec/open-trading-platform/go/order-router/discovery"
 	"github.com/ettec/otp-common/api/executionvenue"
-	"github.com/ettec/otp-common/k8s"
 	"github.com/ettec/otp-common/loadbalancing"
 	"github.com/ettec/otp-common/model"
 	"google.golang.org/grpc"
-	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
-	"log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 )
 
+type preTradeRiskChecker interface {
+	Check(ctx context.Context, user string, params *executionvenue.CreateAndRouteOrderParams) (string, error)
+	OnOrderRouted(reservationId string, orderId string)
+	OnRouteFailed(reservationId string)
+}
+
+type buyingPowerChecker interface {
+	Reserve(ctx context.Context, user string, params *executionvenue.CreateAndRouteOrderParams) (string, error)
+	OnOrderRouted(ctx context.Context, user string, reservationId string, orderId string)
+	OnRouteFailed(ctx context.Context, user string, reservationId string)
+}
+
+type haltChecker interface {
+	Check(params *executionvenue.CreateAndRouteOrderParams) error
+}
+
 type orderRouter struct {
+	ctx                context.Context
 	micToExecVenue     map[string]map[int]*execVenue
 	ownerIdToExecVenue map[string]*execVenue
+	knownOwnerIds      map[string]bool
 	mux                sync.Mutex
+	riskChecker        preTradeRiskChecker
+	buyingPowerChecker buyingPowerChecker
+	haltChecker        haltChecker
+	execParamsSchemas  *execParamsSchemaCache
+	pendingRequests    *pendingRequestQueue
 }
 
-func NewOrderRouter(_ context.Context, connectRetrySecs int) (*orderRouter, error) {
+// NewOrderRouter creates an order router that routes to the execution venues reported by the given venue discovery.
+func NewOrderRouter(ctx context.Context, connectRetrySecs int, venueDiscovery discovery.VenueDiscovery,
+	riskChecker preTradeRiskChecker, buyingPowerChecker buyingPowerChecker, haltChecker haltChecker,
+	pendingRequestTtl time.Duration) (*orderRouter, error) {
 
 	router := &orderRouter{
+		ctx:                ctx,
 		micToExecVenue:     map[string]map[int]*execVenue{},
 		ownerIdToExecVenue: map[string]*execVenue{},
+		knownOwnerIds:      map[string]bool{},
 		mux:                sync.Mutex{},
+		riskChecker:        riskChecker,
+		buyingPowerChecker: buyingPowerChecker,
+		haltChecker:        haltChecker,
+		execParamsSchemas:  newExecParamsSchemaCache(),
+		pendingRequests:    newPendingRequestQueue(pendingRequestTtl),
 	}
 
-	namespace := "default"
-	clientSet := k8s.GetK8sClientSet(false)
-	labelSelector := "servicetype in (execution-venue, execution-venue-and-market-data-gateway)"
-	pods, err := clientSet.CoreV1().Pods(namespace).Watch(v1.ListOptions{
-		LabelSelector: labelSelector,
-	})
-
+	venueEvents, err := venueDiscovery.Watch(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to watch pods with label selector \"%s\", error: %v", labelSelector, err)
+		return nil, fmt.Errorf("failed to watch for execution venues: %w", err)
 	}
 
 	go func() {
-
-		for e := range pods.ResultChan() {
-			pod := e.Object.(*corev1.Pod)
-			bsp, err := loadbalancing.GetBalancingStatefulPod(*pod)
-			if err != nil {
-				log.Panicf("failed to get balancing stateful pod: %v", err)
-			}
-
-			if e.Type == watch.Added {
+		for e := range venueEvents {
+			bsp := e.Venue
+			switch e.Type {
+			case discovery.VenueAdded:
 				client, err := createExecVenueConnection(time.Duration(connectRetrySecs)*time.Second, bsp.TargetAddress)
 				if err != nil {
 					slog.Error("failed to create connection to execution venue service", "targetAddress", bsp.TargetAddress, "error", err)
@@ -59,10 +82,26 @@ func NewOrderRouter(_ context.Context, connectRetrySecs int) (*orderRouter, erro
 				}
 
 				router.addExecVenue(bsp, client)
-			} else if e.Type == watch.Deleted {
+			case discovery.VenueRemoved:
 				router.removeExecVenue(bsp)
 			}
 		}
+
+		slog.Warn("execution venue discovery stopped")
+	}()
+
+	go func() {
+		ticker := time.NewTicker(pendingRequestTtl)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				router.pendingRequests.purgeExpired()
+			}
+		}
 	}()
 
 	return router, nil
@@ -74,9 +113,17 @@ func (o *orderRouter) removeExecVenue(bsp *loadbalancing.BalancingStatefulPod) {
 
 	if ordinalToExecutionVenue, ok := o.micToExecVenue[bsp.Mic]; ok {
 		delete(ordinalToExecutionVenue, bsp.Ordinal)
+		if len(ordinalToExecutionVenue) == 0 {
+			delete(o.micToExecVenue, bsp.Mic)
+			o.execParamsSchemas.remove(bsp.Mic)
+		}
 	}
 
-	delete(o.ownerIdToExecVenue, bsp.Name)
+	if ev, ok := o.ownerIdToExecVenue[bsp.Name]; ok {
+		ev.stopHealthMonitor()
+		closeExecVenueConnection(ev)
+		delete(o.ownerIdToExecVenue, bsp.Name)
+	}
 
 	slog.Info("removed execution venue", "mic", bsp.Mic,
 		"targetAddress", bsp.TargetAddress, "ordinal", bsp.Ordinal)
@@ -90,16 +137,64 @@ func (o *orderRouter) addExecVenue(bsp *loadbalancing.BalancingStatefulPod, ev *
 		o.micToExecVenue[bsp.Mic] = map[int]*execVenue{}
 	}
 
+	// A venue is reported again if the discovery's watch is re-established
+	if existing, ok := o.ownerIdToExecVenue[bsp.Name]; ok {
+		existing.stopHealthMonitor()
+		closeExecVenueConnection(existing)
+	}
+
+	ev.ownerId = bsp.Name
+	ev.mic = bsp.Mic
+	ev.ordinal = bsp.Ordinal
+	ev.targetAddress = bsp.TargetAddress
+
 	o.micToExecVenue[bsp.Mic][bsp.Ordinal] = ev
 
 	o.ownerIdToExecVenue[bsp.Name] = ev
+	o.knownOwnerIds[bsp.Name] = true
+
+	o.startHealthMonitor(ev)
 
 	slog.Info("added execution venue", "mic", bsp.Mic,
 		"targetAddress", bsp.TargetAddress, "ordinal", bsp.Ordinal)
 }
 
-func (o *orderRouter) GetExecutionParametersMetaData(context.Context, *model.Empty) (*executionvenue.ExecParamsMetaDataJson, error) {
-	return &executionvenue.ExecParamsMetaDataJson{}, nil
+// GetExecutionParametersMetaData returns the execution parameter schemas of all discovered destinations as a JSON object
+// keyed by destination mic.
+func (o *orderRouter) GetExecutionParametersMetaData(ctx context.Context, _ *model.Empty) (*executionvenue.ExecParamsMetaDataJson, error) {
+	micToSchema := map[string]*execParamsSchema{}
+	for mic, client := range o.getExecutionVenueClientPerMic() {
+		schema, err := o.execParamsSchemas.getSchema(ctx, mic, client)
+		if err != nil {
+			slog.Warn("failed to get execution parameters schema", "mic", mic, "error", err)
+			continue
+		}
+
+		micToSchema[mic] = schema
+	}
+
+	metaDataJson, err := aggregateExecParamsSchemas(micToSchema)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &executionvenue.ExecParamsMetaDataJson{Json: metaDataJson}, nil
+}
+
+func (o *orderRouter) getExecutionVenueClientPerMic() map[string]executionvenue.ExecutionVenueClient {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	result := map[string]executionvenue.ExecutionVenueClient{}
+	for mic, evs := range o.micToExecVenue {
+		for _, ev := range evs {
+			if _, ok := result[mic]; !ok || ev.isHealthy() {
+				result[mic] = ev.client
+			}
+		}
+	}
+
+	return result
 }
 
 func (o *orderRouter) CreateAndRouteOrder(c context.Context, p *executionvenue.CreateAndRouteOrderParams) (*executionvenue.OrderId, error) {
@@ -111,74 +206,245 @@ func (o *orderRouter) CreateAndRouteOrder(c context.Context, p *executionvenue.C
 			p.ListingId, p.Destination, err)
 	}
 
+	// Strategies validate their own parameters, so orders are still routed if the destination's schema is unavailable
+	if schema, err := o.execParamsSchemas.getSchema(c, p.Destination, ev.client); err != nil {
+		slog.Warn("failed to get execution parameters schema, parameters not validated", "destination", p.Destination,
+			"error", err)
+	} else if err := schema.validate(p.ExecParametersJson); err != nil {
+		slog.Warn("create order request rejected due to invalid execution parameters", "request", p, "error", err)
+		return nil, err
+	}
+
+	if err := o.haltChecker.Check(p); err != nil {
+		slog.Warn("create order request rejected due to trading halt", "request", p, "error", err)
+		return nil, err
+	}
+
+	user := getUserName(c)
+	openOrderReservationId, err := o.riskChecker.Check(c, user, p)
+	if err != nil {
+		slog.Warn("create order request rejected by pre-trade risk check", "user", user, "request", p, "error", err)
+		return nil, err
+	}
+
+	reservationId, err := o.buyingPowerChecker.Reserve(c, user, p)
+	if err != nil {
+		slog.Warn("create order request rejected by buying power check", "user", user, "request", p, "error", err)
+		o.riskChecker.OnRouteFailed(openOrderReservationId)
+		return nil, err
+	}
+
+	// The reservation is assigned or released with the router's context so that it is not left unassigned if the
+	// client cancels the request once the order has been routed
 	id, err := ev.client.CreateAndRouteOrder(c, p)
 	if err != nil {
 		slog.Error("failed to route create order request", "request ", p, "error", err)
+		o.riskChecker.OnRouteFailed(openOrderReservationId)
+		o.buyingPowerChecker.OnRouteFailed(o.ctx, user, reservationId)
 		return nil, fmt.Errorf("failed to route order:%w", err)
 	}
 
-	slog.Info("routed create order request", "request", p, "executionVenue", ev.podId, "orderId", id)
+	o.riskChecker.OnOrderRouted(openOrderReservationId, id.OrderId)
+	o.buyingPowerChecker.OnOrderRouted(o.ctx, user, reservationId, id.OrderId)
+
+	slog.Info("routed create order request", "request", p, "executionVenue", ev.ownerId, "orderId", id)
 
 	return id, nil
 
 }
 
-func (o *orderRouter) getExecutionVenueForOwnerId(ownerId string) (*execVenue, error) {
-	o.mux.Lock()
-	defer o.mux.Unlock()
-	if ev, ok := o.ownerIdToExecVenue[ownerId]; ok {
-		return ev, nil
-
-	} else {
-		return nil, fmt.Errorf("failed to find execution venue for owner id:%v", ownerId)
+// getUserName returns the user name set in the request metadata by the client, requests originating from strategies
+// do not carry a user name.
+func getUserName(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if usernames := md.Get("user-name"); len(usernames) == 1 {
+			return usernames[0]
+		}
 	}
 
+	return ""
+}
+
+// getExecutionVenueForOwnerId returns the owner's venue if the owner is currently known and whether the owner has ever
+// been known to the router.
+func (o *orderRouter) getExecutionVenueForOwnerId(ownerId string) (*execVenue, bool, bool) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	ev, ok := o.ownerIdToExecVenue[ownerId]
+	return ev, ok, o.knownOwnerIds[ownerId]
 }
 
 func (o *orderRouter) getExecutionVenueForListing(listingId int32, destination string) (*execVenue, error) {
 	o.mux.Lock()
 	defer o.mux.Unlock()
-	if evs, ok := o.micToExecVenue[destination]; ok {
-		numVenues := int32(len(evs))
-		ordinal := loadbalancing.GetBalancingOrdinal(listingId, numVenues)
-		return evs[ordinal], nil
+	if evs, ok := o.micToExecVenue[destination]; ok && len(evs) > 0 {
+		return selectExecVenue(listingId, evs), nil
 	} else {
-		return nil, fmt.Errorf("no execution venue found for destination %v", destination)
+		return nil, status.Errorf(codes.Unavailable, "no execution venue found for destination %v", destination)
 	}
 }
 
-func (o *orderRouter) ModifyOrder(c context.Context, p *executionvenue.ModifyOrderParams) (*model.Empty, error) {
+// selectExecVenue returns the venue at the listing's balancing ordinal if it is healthy, the balancing ordinal is
+// calculated over the full range of ordinals so that listings keep their venue whilst other venues are unavailable.  If
+// that venue is missing or unhealthy a healthy venue is selected by listing id from the healthy venues and if there are
+// no healthy venues a venue is selected by listing id from all the venues.
+func selectExecVenue(listingId int32, evs map[int]*execVenue) *execVenue {
+	maxOrdinal := 0
+	var ordinals, healthyOrdinals []int
+	for ordinal, ev := range evs {
+		maxOrdinal = max(maxOrdinal, ordinal)
+		ordinals = append(ordinals, ordinal)
+		if ev.isHealthy() {
+			healthyOrdinals = append(healthyOrdinals, ordinal)
+		}
+	}
 
-	ev, err := o.getExecutionVenueForOwnerId(p.OwnerId)
+	if ev, ok := evs[loadbalancing.GetBalancingOrdinal(listingId, int32(maxOrdinal+1))]; ok && ev.isHealthy() {
+		return ev
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to modify order %v, error: %v", p.OrderId, err)
+	if len(healthyOrdinals) == 0 {
+		healthyOrdinals = ordinals
 	}
 
-	_, err = ev.client.ModifyOrder(c, p)
+	sort.Ints(healthyOrdinals)
+	return evs[healthyOrdinals[loadbalancing.GetBalancingOrdinal(listingId, int32(len(healthyOrdinals)))]]
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to modify order: %v, error: %v", p.OrderId, err)
+// ModifyOrder routes the request to the order's owner, if the owner is unavailable the request is queued, sent when the
+// owner becomes available and the request fails with status Unavailable.
+func (o *orderRouter) ModifyOrder(c context.Context, p *executionvenue.ModifyOrderParams) (*model.Empty, error) {
+	send := func(ctx context.Context, client executionvenue.ExecutionVenueClient) error {
+		_, err := client.ModifyOrder(ctx, p)
+		return err
+	}
+
+	if err := o.routeOwnerRequest(c, p.OwnerId, p.OrderId, fmt.Sprintf("modify %v", p), send); err != nil {
+		return nil, err
 	}
 
 	return &model.Empty{}, nil
 }
 
+// CancelOrder routes the request to the order's owner, if the owner is unavailable the request is queued, sent when the
+// owner becomes available and the request fails with status Unavailable.
 func (o *orderRouter) CancelOrder(c context.Context, p *executionvenue.CancelOrderParams) (*model.Empty, error) {
+	send := func(ctx context.Context, client executionvenue.ExecutionVenueClient) error {
+		_, err := client.CancelOrder(ctx, p)
+		return err
+	}
 
-	ev, err := o.getExecutionVenueForOwnerId(p.OwnerId)
+	if err := o.routeOwnerRequest(c, p.OwnerId, p.OrderId, fmt.Sprintf("cancel %v", p), send); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to cancel order %v, error: %v", p.OrderId, err)
+	return &model.Empty{}, nil
+}
+
+// routeOwnerRequest sends the request to the owner of the order.  A request for an owner that the router has never known
+// is rejected as the owner id is most likely wrong, whereas a request for a known owner that is unavailable is queued
+// and the caller is told so with status Unavailable.
+func (o *orderRouter) routeOwnerRequest(ctx context.Context, ownerId string, orderId string, description string,
+	send func(ctx context.Context, client executionvenue.ExecutionVenueClient) error) error {
+
+	if ownerId == "" {
+		return status.Error(codes.InvalidArgument, "the order has no owner")
+	}
+
+	ev, ok, known := o.getExecutionVenueForOwnerId(ownerId)
+	if !known {
+		return status.Errorf(codes.NotFound, "the order owner %v is unknown", ownerId)
 	}
 
-	_, err = ev.client.CancelOrder(c, p)
+	if !ok || !ev.isHealthy() {
+		return o.queueRequest(ownerId, &pendingRequest{orderId: orderId, description: description, send: send}, nil)
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to cancel order %v: , error: %v", p.OrderId, err)
+	err := send(ctx, ev.client)
+	if status.Code(err) == codes.Unavailable {
+		return o.queueRequest(ownerId, &pendingRequest{orderId: orderId, description: description, send: send}, err)
 	}
 
-	return &model.Empty{}, nil
+	return err
+}
+
+func (o *orderRouter) queueRequest(ownerId string, request *pendingRequest, sendErr error) error {
+	o.pendingRequests.add(ownerId, request)
+	slog.Warn("owner unavailable, queued request", "ownerId", ownerId, "orderId", request.orderId,
+		"request", request.description, "error", sendErr)
+
+	return status.Errorf(codes.Unavailable, "the order owner %v is unavailable, the request has been queued and will "+
+		"be sent when the owner is available or discarded if it is not sent within %v", ownerId, o.pendingRequests.ttl)
+}
+
+// startHealthMonitor follows the connectivity state of the venue's connection and sends the venue's pending requests
+// whenever the connection becomes ready.
+func (o *orderRouter) startHealthMonitor(ev *execVenue) {
+	ctx, cancel := context.WithCancel(o.ctx)
+	ev.stopHealthMonitor = cancel
+
+	if ev.conn == nil {
+		return
+	}
+
+	go func() {
+		for {
+			state := ev.conn.GetState()
+			slog.Info("execution venue connection state", "ownerId", ev.ownerId, "state", state.String())
+			if state == connectivity.Ready {
+				o.sendPendingRequests(ctx, ev)
+			}
+
+			if !ev.conn.WaitForStateChange(ctx, state) {
+				return
+			}
+		}
+	}()
+}
+
+// sendPendingRequests sends the owner's pending requests to the venue while its health monitor is running, a venue that
+// has been removed or replaced has its health monitor stopped under the router's lock so its requests are not taken
+// for a venue that is no longer the owner's.
+func (o *orderRouter) sendPendingRequests(ctx context.Context, ev *execVenue) {
+	o.mux.Lock()
+	if ctx.Err() != nil {
+		o.mux.Unlock()
+		return
+	}
+	requests := o.pendingRequests.take(ev.ownerId)
+	o.mux.Unlock()
+
+	for i, request := range requests {
+		if ctx.Err() != nil {
+			o.pendingRequests.requeue(ev.ownerId, requests[i:])
+			return
+		}
+
+		err := request.send(ctx, ev.client)
+		if status.Code(err) == codes.Unavailable {
+			slog.Warn("owner unavailable, re-queuing pending requests", "ownerId", ev.ownerId, "error", err)
+			o.pendingRequests.requeue(ev.ownerId, requests[i:])
+			return
+		}
+
+		if err != nil {
+			slog.Error("failed to send pending request", "ownerId", ev.ownerId, "orderId", request.orderId,
+				"request", request.description, "error", err)
+		} else {
+			slog.Info("sent pending request", "ownerId", ev.ownerId, "orderId", request.orderId,
+				"request", request.description)
+		}
+	}
+}
+
+func closeExecVenueConnection(ev *execVenue) {
+	if ev.conn == nil {
+		return
+	}
+
+	if err := ev.conn.Close(); err != nil {
+		slog.Warn("failed to close execution venue connection", "error", err)
+	}
 }
 
 func createExecVenueConnection(maxReconnectInterval time.Duration, targetAddress string) (cac *execVenue,
@@ -195,7 +461,8 @@ func createExecVenueConnection(maxReconnectInterval time.Duration, targetAddress
 	client := executionvenue.NewExecutionVenueClient(conn)
 
 	return &execVenue{
-		client: client,
-		conn:   conn,
+		client:            client,
+		conn:              conn,
+		stopHealthMonitor: func() {},
 	}, nil
 }
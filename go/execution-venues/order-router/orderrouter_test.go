@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	api "github.com/ettec/open-trading-platform/go/order-router/api/orderrouter"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/loadbalancing"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testConn struct {
+	mux     sync.Mutex
+	state   connectivity.State
+	changed chan struct{}
+}
+
+func newTestConn(state connectivity.State) *testConn {
+	return &testConn{state: state, changed: make(chan struct{})}
+}
+
+func (t *testConn) GetState() connectivity.State {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.state
+}
+
+func (t *testConn) WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool {
+	for {
+		t.mux.Lock()
+		state, changed := t.state, t.changed
+		t.mux.Unlock()
+
+		if state != sourceState {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-changed:
+		}
+	}
+}
+
+func (t *testConn) Close() error {
+	return nil
+}
+
+func (t *testConn) setState(state connectivity.State) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.state = state
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+type testVenueClient struct {
+	executionvenue.ExecutionVenueClient
+	mux       sync.Mutex
+	err       error
+	cancelled []string
+	modified  []string
+}
+
+func (t *testVenueClient) CancelOrder(_ context.Context, in *executionvenue.CancelOrderParams, _ ...grpc.CallOption) (*model.Empty, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.err != nil {
+		return nil, t.err
+	}
+	t.cancelled = append(t.cancelled, in.OrderId)
+	return &model.Empty{}, nil
+}
+
+func (t *testVenueClient) ModifyOrder(_ context.Context, in *executionvenue.ModifyOrderParams, _ ...grpc.CallOption) (*model.Empty, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.err != nil {
+		return nil, t.err
+	}
+	t.modified = append(t.modified, in.OrderId)
+	return &model.Empty{}, nil
+}
+
+func (t *testVenueClient) getCancelled() []string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return append([]string{}, t.cancelled...)
+}
+
+func (t *testVenueClient) getModified() []string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return append([]string{}, t.modified...)
+}
+
+func newTestOrderRouter(ctx context.Context) *orderRouter {
+	return &orderRouter{
+		ctx:                ctx,
+		micToExecVenue:     map[string]map[int]*execVenue{},
+		ownerIdToExecVenue: map[string]*execVenue{},
+		knownOwnerIds:      map[string]bool{},
+		execParamsSchemas:  newExecParamsSchemaCache(),
+		pendingRequests:    newPendingRequestQueue(time.Minute),
+	}
+}
+
+func addTestVenue(router *orderRouter, name string, mic string, ordinal int, conn *testConn,
+	client *testVenueClient) *execVenue {
+	ev := &execVenue{client: client, conn: conn}
+	router.addExecVenue(&loadbalancing.BalancingStatefulPod{Name: name, Mic: mic, Ordinal: ordinal,
+		TargetAddress: name + ":50551"}, ev)
+	return ev
+}
+
+func Test_selectExecVenue(t *testing.T) {
+	venue := func(ordinal int, state connectivity.State) *execVenue {
+		return &execVenue{ordinal: ordinal, conn: newTestConn(state)}
+	}
+
+	tests := []struct {
+		name        string
+		venues      []*execVenue
+		listingId   int32
+		wantOrdinal int
+	}{
+		{name: "balancing ordinal", venues: []*execVenue{venue(0, connectivity.Ready), venue(1, connectivity.Ready),
+			venue(2, connectivity.Ready)}, listingId: 5, wantOrdinal: 2},
+		{name: "balancing ordinal is stable when another venue is missing", venues: []*execVenue{
+			venue(0, connectivity.Ready), venue(2, connectivity.Ready)}, listingId: 5, wantOrdinal: 2},
+		{name: "missing ordinal", venues: []*execVenue{venue(0, connectivity.Ready), venue(2, connectivity.Ready)},
+			listingId: 4, wantOrdinal: 0},
+		{name: "unhealthy ordinal", venues: []*execVenue{venue(0, connectivity.Ready),
+			venue(1, connectivity.TransientFailure), venue(2, connectivity.Ready)}, listingId: 4, wantOrdinal: 0},
+		{name: "idle is healthy", venues: []*execVenue{venue(0, connectivity.Ready), venue(1, connectivity.Idle)},
+			listingId: 1, wantOrdinal: 1},
+		{name: "no healthy venues", venues: []*execVenue{venue(0, connectivity.TransientFailure),
+			venue(1, connectivity.Connecting)}, listingId: 3, wantOrdinal: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evs := map[int]*execVenue{}
+			for _, ev := range tt.venues {
+				evs[ev.ordinal] = ev
+			}
+
+			assert.Equal(t, tt.wantOrdinal, selectExecVenue(tt.listingId, evs).ordinal)
+		})
+	}
+}
+
+func Test_getExecutionVenueForListingWithNoVenues(t *testing.T) {
+	router := newTestOrderRouter(context.Background())
+
+	_, err := router.getExecutionVenueForListing(1, "XOSR")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	ev := addTestVenue(router, "xosr-0", "XOSR", 0, newTestConn(connectivity.Ready), &testVenueClient{})
+	router.removeExecVenue(&loadbalancing.BalancingStatefulPod{Name: "xosr-0", Mic: "XOSR", Ordinal: 0})
+	ev.stopHealthMonitor()
+
+	_, err = router.getExecutionVenueForListing(1, "XOSR")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func Test_requestsForRemovedOwnerAreQueuedAndSentWhenOwnerAdded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router := newTestOrderRouter(ctx)
+	addTestVenue(router, "xosr-0", "XOSR", 0, newTestConn(connectivity.Ready), &testVenueClient{})
+	router.removeExecVenue(&loadbalancing.BalancingStatefulPod{Name: "xosr-0", Mic: "XOSR", Ordinal: 0})
+
+	_, err := router.CancelOrder(ctx, &executionvenue.CancelOrderParams{OrderId: "1", OwnerId: "xosr-0"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = router.ModifyOrder(ctx, &executionvenue.ModifyOrderParams{OrderId: "2", OwnerId: "xosr-0"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	table, err := router.GetRoutingTable(ctx, &model.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.PendingOwnerRequests{{OwnerId: "xosr-0", PendingRequests: 2}}, table.UnknownOwnerRequests)
+
+	client := &testVenueClient{}
+	addTestVenue(router, "xosr-0", "XOSR", 0, newTestConn(connectivity.Ready), client)
+
+	assert.Eventually(t, func() bool {
+		return len(client.getCancelled()) == 1 && len(client.getModified()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, map[string]int{}, router.pendingRequests.counts())
+}
+
+func Test_requestsForUnhealthyOwnerAreQueuedAndSentWhenOwnerReady(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router := newTestOrderRouter(ctx)
+	conn := newTestConn(connectivity.TransientFailure)
+	client := &testVenueClient{}
+	addTestVenue(router, "xosr-0", "XOSR", 0, conn, client)
+
+	_, err := router.CancelOrder(ctx, &executionvenue.CancelOrderParams{OrderId: "1", OwnerId: "xosr-0"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	table, err := router.GetRoutingTable(ctx, &model.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.RoutedVenue{{Mic: "XOSR", Ordinal: 0, OwnerId: "xosr-0", TargetAddress: "xosr-0:50551",
+		ConnectionState: "TRANSIENT_FAILURE", Healthy: false, PendingRequests: 1}}, table.Venues)
+	assert.Empty(t, client.getCancelled())
+
+	conn.setState(connectivity.Ready)
+
+	assert.Eventually(t, func() bool {
+		return len(client.getCancelled()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_requestFailingWithUnavailableIsQueued(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router := newTestOrderRouter(ctx)
+	client := &testVenueClient{err: status.Error(codes.Unavailable, "connection refused")}
+	addTestVenue(router, "xosr-0", "XOSR", 0, newTestConn(connectivity.Idle), client)
+
+	_, err := router.CancelOrder(ctx, &executionvenue.CancelOrderParams{OrderId: "1", OwnerId: "xosr-0"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, map[string]int{"xosr-0": 1}, router.pendingRequests.counts())
+
+	client.err = status.Error(codes.NotFound, "unknown order")
+	_, err = router.CancelOrder(ctx, &executionvenue.CancelOrderParams{OrderId: "2", OwnerId: "xosr-0"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, map[string]int{"xosr-0": 1}, router.pendingRequests.counts())
+}
+
+func Test_requestWithoutOwnerIsRejected(t *testing.T) {
+	router := newTestOrderRouter(context.Background())
+
+	_, err := router.CancelOrder(context.Background(), &executionvenue.CancelOrderParams{OrderId: "1"})
+	assert.Error(t, err)
+	assert.Equal(t, map[string]int{}, router.pendingRequests.counts())
+}
+
+func Test_requestForOwnerNeverKnownIsRejected(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	router := newTestOrderRouter(ctx)
+	addTestVenue(router, "xosr-0", "XOSR", 0, newTestConn(connectivity.Ready), &testVenueClient{})
+
+	_, err := router.CancelOrder(ctx, &executionvenue.CancelOrderParams{OrderId: "1", OwnerId: "xosr-9"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = router.ModifyOrder(ctx, &executionvenue.ModifyOrderParams{OrderId: "2", OwnerId: "xosr-9"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, map[string]int{}, router.pendingRequests.counts())
+}
+
+func Test_pendingRequestQueueExpiry(t *testing.T) {
+	now := time.Now()
+	queue := newPendingRequestQueue(time.Minute)
+	queue.now = func() time.Time { return now }
+
+	queue.add("xosr-0", &pendingRequest{orderId: "1"})
+	now = now.Add(30 * time.Second)
+	queue.add("xosr-0", &pendingRequest{orderId: "2"})
+	queue.add("xosr-1", &pendingRequest{orderId: "3"})
+
+	now = now.Add(45 * time.Second)
+	queue.purgeExpired()
+	assert.Equal(t, map[string]int{"xosr-0": 1, "xosr-1": 1}, queue.counts())
+
+	requests := queue.take("xosr-0")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "2", requests[0].orderId)
+
+	queue.requeue("xosr-1", requests)
+	requests = queue.take("xosr-1")
+	assert.Equal(t, "2", requests[0].orderId)
+	assert.Equal(t, "3", requests[1].orderId)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// pendingRequest is a modify or cancel request for an order whose owning execution venue was unavailable when the
+// request was received.
+type pendingRequest struct {
+	orderId     string
+	description string
+	queued      time.Time
+	send        func(ctx context.Context, client executionvenue.ExecutionVenueClient) error
+}
+
+// pendingRequestQueue holds the pending requests of each owner in the order they were received until the owner is
+// available again, requests older than the ttl are discarded.
+type pendingRequestQueue struct {
+	mux     sync.Mutex
+	byOwner map[string][]*pendingRequest
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+func newPendingRequestQueue(ttl time.Duration) *pendingRequestQueue {
+	return &pendingRequestQueue{
+		byOwner: map[string][]*pendingRequest{},
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (q *pendingRequestQueue) add(ownerId string, request *pendingRequest) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	request.queued = q.now()
+	q.byOwner[ownerId] = append(q.byOwner[ownerId], request)
+}
+
+// requeue returns requests that could not be sent to the front of the owner's queue.
+func (q *pendingRequestQueue) requeue(ownerId string, requests []*pendingRequest) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.byOwner[ownerId] = append(append([]*pendingRequest{}, requests...), q.byOwner[ownerId]...)
+}
+
+// take removes and returns the owner's unexpired requests.
+func (q *pendingRequestQueue) take(ownerId string) []*pendingRequest {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	requests := q.unexpired(ownerId, q.byOwner[ownerId])
+	delete(q.byOwner, ownerId)
+
+	return requests
+}
+
+// purgeExpired discards the expired requests of all owners, including those of owners that do not return.
+func (q *pendingRequestQueue) purgeExpired() {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	for ownerId, requests := range q.byOwner {
+		if unexpired := q.unexpired(ownerId, requests); len(unexpired) > 0 {
+			q.byOwner[ownerId] = unexpired
+		} else {
+			delete(q.byOwner, ownerId)
+		}
+	}
+}
+
+func (q *pendingRequestQueue) unexpired(ownerId string, requests []*pendingRequest) []*pendingRequest {
+	var result []*pendingRequest
+	for _, request := range requests {
+		if q.now().Sub(request.queued) > q.ttl {
+			slog.Error("discarded pending request that was not sent within its ttl", "ownerId", ownerId,
+				"orderId", request.orderId, "request", request.description, "queued", request.queued)
+			continue
+		}
+
+		result = append(result, request)
+	}
+
+	return result
+}
+
+func (q *pendingRequestQueue) counts() map[string]int {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	result := map[string]int{}
+	for ownerId, requests := range q.byOwner {
+		result[ownerId] = len(requests)
+	}
+
+	return result
+}
@@ -0,0 +1,171 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	wallet "github.com/ettec/open-trading-platform/go/wallet-service/api/walletservice"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"time"
+)
+
+const (
+	InsufficientFunds Reason = "INSUFFICIENT_FUNDS"
+	PriceRequired     Reason = "PRICE_REQUIRED"
+)
+
+type walletClient interface {
+	ReserveFunds(ctx context.Context, in *wallet.ReserveFundsRequest, opts ...grpc.CallOption) (*wallet.ReserveFundsResponse, error)
+	AssignReservation(ctx context.Context, in *wallet.AssignReservationRequest, opts ...grpc.CallOption) (*wallet.AssignReservationResponse, error)
+	ReleaseFunds(ctx context.Context, in *wallet.ReleaseFundsRequest, opts ...grpc.CallOption) (*wallet.ReleaseFundsResponse, error)
+}
+
+// BuyingPowerChecker reserves the price times the quantity of a user's buy order in the user's wallet before the order
+// is routed.  The reservation is assigned to the order once it has been routed, after which the wallet service settles
+// the reservation as the order is filled and releases what remains when the order is done.
+type BuyingPowerChecker struct {
+	wallet               walletClient
+	publisher            rejectionPublisher
+	currency             string
+	maxAssignAttempts    int
+	initialAssignBackoff time.Duration
+}
+
+const (
+	defaultMaxAssignAttempts    = 10
+	defaultInitialAssignBackoff = 100 * time.Millisecond
+	maxAssignBackoff            = 10 * time.Second
+)
+
+func NewBuyingPowerChecker(wallet walletClient, publisher rejectionPublisher, currency string) *BuyingPowerChecker {
+	return &BuyingPowerChecker{wallet: wallet, publisher: publisher, currency: currency,
+		maxAssignAttempts: defaultMaxAssignAttempts, initialAssignBackoff: defaultInitialAssignBackoff}
+}
+
+// walletContext returns a context that identifies the user to the wallet service, which only permits a user to act on
+// their own account.
+func walletContext(ctx context.Context, user string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "user-name", user)
+}
+
+// Reserve reserves the funds required by the order and returns the id of the reservation, or an empty id if the order
+// does not require funds.  Only the root buy orders of a user require funds.  The wallet service settles reservations
+// at the order's limit price, so a buy order without a limit price is rejected with a grpc status error with code
+// FailedPrecondition.  If the user's available balance is insufficient a grpc status error with code
+// ResourceExhausted is returned, distinguishing it from the rejections of the risk limits and trading halts.  Every
+// rejection is published.
+func (b *BuyingPowerChecker) Reserve(ctx context.Context, user string,
+	params *executionvenue.CreateAndRouteOrderParams) (string, error) {
+
+	if user == "" || params.OrderSide != model.Side_BUY ||
+		params.OriginatorId != params.RootOriginatorId || params.OriginatorRef != params.RootOriginatorRef {
+		return "", nil
+	}
+
+	if params.Price == nil {
+		return "", b.reject(ctx, user, params, codes.FailedPrecondition, PriceRequired,
+			"buy orders without a limit price cannot be checked against the available balance")
+	}
+
+	amount := params.Price.ToFloat() * params.Quantity.ToFloat()
+
+	response, err := b.wallet.ReserveFunds(walletContext(ctx, user), &wallet.ReserveFundsRequest{UserId: user, Currency: b.currency,
+		Amount: amount})
+	if status.Code(err) == codes.FailedPrecondition {
+		return "", b.reject(ctx, user, params, codes.ResourceExhausted, InsufficientFunds,
+			fmt.Sprintf("order value %v %v exceeds the available balance", amount, b.currency))
+	}
+
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "failed to reserve funds for order: %v", err)
+	}
+
+	return response.ReservationId, nil
+}
+
+func (b *BuyingPowerChecker) reject(ctx context.Context, user string, params *executionvenue.CreateAndRouteOrderParams,
+	code codes.Code, reason Reason, detail string) error {
+	rejection := newRejection(reason, detail, scopedLimits{scope: "user", scopeId: user}, user, params)
+
+	if err := b.publisher.Publish(ctx, rejection); err != nil {
+		slog.Error("failed to publish risk rejection", "rejection", rejection, "error", err)
+	}
+
+	return status.Error(code, rejection.Description())
+}
+
+// OnOrderRouted assigns the user's reservation to the routed order.  An assignment that fails with a transient error is
+// retried in the background with an exponential backoff, and if it cannot be made within the max attempts the
+// reservation is released so that the user's funds do not remain reserved for an order that will not settle them.
+func (b *BuyingPowerChecker) OnOrderRouted(ctx context.Context, user string, reservationId string, orderId string) {
+	if reservationId == "" {
+		return
+	}
+
+	if !b.assign(ctx, user, reservationId, orderId, 1) {
+		return
+	}
+
+	go func() {
+		backoff := b.initialAssignBackoff
+		for attempt := 2; attempt <= b.maxAssignAttempts; attempt++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			if !b.assign(ctx, user, reservationId, orderId, attempt) {
+				return
+			}
+
+			backoff = min(2*backoff, maxAssignBackoff)
+		}
+
+		slog.Error("failed to assign funds reservation to order, releasing the reservation", "reservationId",
+			reservationId, "orderId", orderId, "attempts", b.maxAssignAttempts)
+		b.release(ctx, user, reservationId)
+	}()
+}
+
+// assign assigns the reservation to the order and returns true if the assignment failed and should be retried.
+func (b *BuyingPowerChecker) assign(ctx context.Context, user string, reservationId string, orderId string,
+	attempt int) bool {
+	_, err := b.wallet.AssignReservation(walletContext(ctx, user), &wallet.AssignReservationRequest{
+		ReservationId: reservationId, OrderId: orderId})
+	if err == nil {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition, codes.PermissionDenied,
+		codes.Unauthenticated:
+		slog.Error("failed to assign funds reservation to order", "reservationId", reservationId, "orderId", orderId,
+			"error", err)
+		return false
+	}
+
+	slog.Warn("failed to assign funds reservation to order, retrying", "reservationId", reservationId, "orderId",
+		orderId, "attempt", attempt, "error", err)
+	return true
+}
+
+// OnRouteFailed releases the user's reservation of an order that could not be routed.
+func (b *BuyingPowerChecker) OnRouteFailed(ctx context.Context, user string, reservationId string) {
+	if reservationId == "" {
+		return
+	}
+
+	b.release(ctx, user, reservationId)
+}
+
+func (b *BuyingPowerChecker) release(ctx context.Context, user string, reservationId string) {
+	if _, err := b.wallet.ReleaseFunds(walletContext(ctx, user), &wallet.ReleaseFundsRequest{ReservationId: reservationId}); err != nil {
+		slog.Error("failed to release funds reservation", "reservationId", reservationId, "error", err)
+	}
+}
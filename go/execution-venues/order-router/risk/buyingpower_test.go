@@ -0,0 +1,230 @@
+package risk
+
+import (
+	"context"
+	wallet "github.com/ettec/open-trading-platform/go/wallet-service/api/walletservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testWalletClient struct {
+	mutex      sync.Mutex
+	available  float64
+	reserved   map[string]float64
+	assigned   map[string]string
+	assignErrs []error
+	released   []string
+	users      []string
+}
+
+func newTestWalletClient(available float64) *testWalletClient {
+	return &testWalletClient{available: available, reserved: map[string]float64{}, assigned: map[string]string{}}
+}
+
+// recordUser records the user the request is made for, which must be set in the outgoing metadata for the wallet
+// service to accept the request.
+func (t *testWalletClient) recordUser(ctx context.Context) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	t.users = append(t.users, md.Get("user-name")...)
+}
+
+func (t *testWalletClient) ReserveFunds(ctx context.Context, in *wallet.ReserveFundsRequest, _ ...grpc.CallOption) (*wallet.ReserveFundsResponse, error) {
+	t.recordUser(ctx)
+	if in.Amount > t.available {
+		return nil, status.Error(codes.FailedPrecondition, "insufficient funds")
+	}
+
+	t.available -= in.Amount
+	reservationId := in.UserId + "-" + in.Currency
+	t.reserved[reservationId] += in.Amount
+	return &wallet.ReserveFundsResponse{ReservationId: reservationId}, nil
+}
+
+func (t *testWalletClient) AssignReservation(ctx context.Context, in *wallet.AssignReservationRequest, _ ...grpc.CallOption) (*wallet.AssignReservationResponse, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.recordUser(ctx)
+	if len(t.assignErrs) > 0 {
+		err := t.assignErrs[0]
+		t.assignErrs = t.assignErrs[1:]
+		return nil, err
+	}
+
+	t.assigned[in.ReservationId] = in.OrderId
+	return &wallet.AssignReservationResponse{}, nil
+}
+
+func (t *testWalletClient) ReleaseFunds(ctx context.Context, in *wallet.ReleaseFundsRequest, _ ...grpc.CallOption) (*wallet.ReleaseFundsResponse, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.recordUser(ctx)
+	t.released = append(t.released, in.ReservationId)
+	return &wallet.ReleaseFundsResponse{}, nil
+}
+
+func TestBuyOrdersReserveTheirValue(t *testing.T) {
+	ctx := context.Background()
+	client := newTestWalletClient(1000)
+	checker := NewBuyingPowerChecker(client, &testPublisher{}, "USD")
+
+	reservationId, err := checker.Reserve(ctx, "userA", newParams(10, 20))
+	assert.NoError(t, err)
+	assert.Equal(t, "userA-USD", reservationId)
+	assert.Equal(t, 200.0, client.reserved[reservationId])
+
+	checker.OnOrderRouted(ctx, "userA", reservationId, "order1")
+	assert.Equal(t, "order1", client.assigned[reservationId])
+
+	checker.OnRouteFailed(ctx, "userA", reservationId)
+	assert.Equal(t, []string{reservationId}, client.released)
+	assert.Equal(t, []string{"userA", "userA", "userA"}, client.users)
+}
+
+func TestOrdersThatDoNotRequireFundsAreNotReserved(t *testing.T) {
+	ctx := context.Background()
+	client := newTestWalletClient(1000)
+	checker := NewBuyingPowerChecker(client, &testPublisher{}, "USD")
+
+	sell := newParams(10, 20)
+	sell.OrderSide = model.Side_SELL
+
+	child := newParams(10, 20)
+	child.OriginatorId = "XVWAP"
+
+	reservationId, err := checker.Reserve(ctx, "userA", sell)
+	assert.NoError(t, err)
+	assert.Empty(t, reservationId)
+
+	reservationId, err = checker.Reserve(ctx, "userA", child)
+	assert.NoError(t, err)
+	assert.Empty(t, reservationId)
+
+	reservationId, err = checker.Reserve(ctx, "", newParams(10, 20))
+	assert.NoError(t, err)
+	assert.Empty(t, reservationId)
+
+	checker.OnOrderRouted(ctx, "userA", "", "order1")
+	checker.OnRouteFailed(ctx, "userA", "")
+	assert.Empty(t, client.reserved)
+	assert.Empty(t, client.assigned)
+	assert.Empty(t, client.released)
+}
+
+func TestOrdersExceedingTheAvailableBalanceAreRejected(t *testing.T) {
+	ctx := context.Background()
+	publisher := &testPublisher{}
+	checker := NewBuyingPowerChecker(newTestWalletClient(100), publisher, "USD")
+
+	_, err := checker.Reserve(ctx, "userA", newParams(10, 20))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, err.Error(), string(InsufficientFunds))
+
+	if assert.Len(t, publisher.rejections, 1) {
+		rejection := publisher.rejections[0]
+		assert.Equal(t, InsufficientFunds, rejection.Reason)
+		assert.Equal(t, "user", rejection.Scope)
+		assert.Equal(t, "userA", rejection.ScopeId)
+		assert.Equal(t, 200.0, rejection.Price*rejection.Quantity)
+	}
+}
+
+func TestBuyOrdersWithoutALimitPriceAreRejected(t *testing.T) {
+	ctx := context.Background()
+	publisher := &testPublisher{}
+	client := newTestWalletClient(1000)
+	checker := NewBuyingPowerChecker(client, publisher, "USD")
+
+	market := newParams(10, 20)
+	market.Price = nil
+
+	_, err := checker.Reserve(ctx, "userA", market)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), string(PriceRequired))
+	assert.Empty(t, client.reserved)
+
+	if assert.Len(t, publisher.rejections, 1) {
+		assert.Equal(t, PriceRequired, publisher.rejections[0].Reason)
+	}
+
+	// market orders of strategies are not checked
+	childMarket := newParams(10, 20)
+	childMarket.Price = nil
+	childMarket.OriginatorId = "XVWAP"
+
+	reservationId, err := checker.Reserve(ctx, "userA", childMarket)
+	assert.NoError(t, err)
+	assert.Empty(t, reservationId)
+}
+
+func (t *testWalletClient) getAssigned(reservationId string) string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.assigned[reservationId]
+}
+
+func (t *testWalletClient) getReleased() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]string{}, t.released...)
+}
+
+func newRetryingTestChecker(client *testWalletClient) *BuyingPowerChecker {
+	checker := NewBuyingPowerChecker(client, &testPublisher{}, "USD")
+	checker.maxAssignAttempts = 3
+	checker.initialAssignBackoff = time.Millisecond
+	return checker
+}
+
+func TestFailedAssignmentsAreRetried(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := newTestWalletClient(1000)
+	client.assignErrs = []error{status.Error(codes.Unavailable, "wallet unavailable"),
+		status.Error(codes.Unavailable, "wallet unavailable")}
+	checker := newRetryingTestChecker(client)
+
+	checker.OnOrderRouted(ctx, "userA", "userA-USD", "order1")
+
+	assert.Eventually(t, func() bool { return client.getAssigned("userA-USD") == "order1" }, time.Second,
+		time.Millisecond)
+	assert.Empty(t, client.getReleased())
+}
+
+func TestReservationIsReleasedIfItCannotBeAssigned(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := newTestWalletClient(1000)
+	for i := 0; i < 3; i++ {
+		client.assignErrs = append(client.assignErrs, status.Error(codes.Unavailable, "wallet unavailable"))
+	}
+	checker := newRetryingTestChecker(client)
+
+	checker.OnOrderRouted(ctx, "userA", "userA-USD", "order1")
+
+	assert.Eventually(t, func() bool { return len(client.getReleased()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"userA-USD"}, client.getReleased())
+	assert.Empty(t, client.getAssigned("userA-USD"))
+}
+
+func TestAssignmentsThatCannotSucceedAreNotRetried(t *testing.T) {
+	client := newTestWalletClient(1000)
+	client.assignErrs = []error{status.Error(codes.NotFound, "no reservation")}
+	checker := newRetryingTestChecker(client)
+
+	checker.OnOrderRouted(context.Background(), "userA", "userA-USD", "order1")
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, client.getAssigned("userA-USD"))
+	assert.Empty(t, client.getReleased())
+}
@@ -0,0 +1,432 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type Reason string
+
+const (
+	MaxOrderQuantityBreached Reason = "MAX_ORDER_QUANTITY"
+	MaxOrderNotionalBreached Reason = "MAX_ORDER_NOTIONAL"
+	PriceCollarBreached      Reason = "PRICE_COLLAR"
+	MaxOpenOrdersBreached    Reason = "MAX_OPEN_ORDERS"
+	PriceUnavailable         Reason = "PRICE_UNAVAILABLE"
+)
+
+type configSource interface {
+	Config() *Config
+}
+
+type rejectionPublisher interface {
+	Publish(ctx context.Context, rejection *Rejection) error
+}
+
+// Checker applies the pre-trade risk limits to new orders.
+type Checker struct {
+	configSource configSource
+	publisher    rejectionPublisher
+	lastPrices   *lastPrices
+	openOrders   *openOrders
+}
+
+// NewChecker creates a checker that tracks last traded prices using the given quote stream and the open orders per
+// originator using the given initial orders and order updates.  Quotes are subscribed to for the listings of the
+// initial orders when the checker is created and for the listings of new orders as their updates are received.
+func NewChecker(ctx context.Context, configSource configSource, publisher rejectionPublisher,
+	quoteStream marketdata.QuoteStream, initialOrders map[string]*model.Order, orderUpdates <-chan *model.Order) *Checker {
+
+	c := &Checker{
+		configSource: configSource,
+		publisher:    publisher,
+		lastPrices:   newLastPrices(quoteStream),
+		openOrders:   newOpenOrders(),
+	}
+
+	for _, order := range initialOrders {
+		c.lastPrices.subscribe(order.ListingId)
+		c.openOrders.onOrderUpdate(order)
+	}
+
+	go func() {
+		defer quoteStream.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case quote, ok := <-quoteStream.Chan():
+				if !ok {
+					slog.Error("risk checker quote stream closed, price collars will use stale prices")
+					return
+				}
+				c.lastPrices.onQuote(quote)
+			case order, ok := <-orderUpdates:
+				if !ok {
+					slog.Error("risk checker order updates channel closed, open order counts will no longer be updated")
+					orderUpdates = nil
+					continue
+				}
+				c.lastPrices.subscribe(order.ListingId)
+				c.openOrders.onOrderUpdate(order)
+			}
+		}
+	}()
+
+	return c
+}
+
+// Check returns a grpc status error with code FailedPrecondition if the order breaches any of the limits that apply to
+// the given user or to the order's desk, every rejection is published.  An order within the limits is counted towards
+// its originator's open orders from the time it is checked, so that concurrent orders from one originator cannot
+// exceed the max open orders, and the id of its open order reservation is returned.  The reservation must be assigned
+// to the order once it is routed, or released if it is not.
+func (c *Checker) Check(ctx context.Context, user string, params *executionvenue.CreateAndRouteOrderParams) (string, error) {
+	var scopes []scopedLimits
+	if config := c.configSource.Config(); config != nil {
+		scopes = config.limitsFor(user, params.RootOriginatorId)
+	}
+
+	for _, sl := range scopes {
+		if reason, detail := c.checkLimits(sl.limits, params); reason != "" {
+			return "", c.reject(ctx, reason, detail, sl, user, params)
+		}
+	}
+
+	reservationId, breached, open := c.openOrders.reserve(params.OriginatorId, scopes)
+	if breached != nil {
+		detail := fmt.Sprintf("originator %v has %v open orders, the max open orders is %v", params.OriginatorId, open,
+			breached.limits.MaxOpenOrders)
+		return "", c.reject(ctx, MaxOpenOrdersBreached, detail, *breached, user, params)
+	}
+
+	return reservationId, nil
+}
+
+func (c *Checker) reject(ctx context.Context, reason Reason, detail string, sl scopedLimits, user string,
+	params *executionvenue.CreateAndRouteOrderParams) error {
+	rejection := newRejection(reason, detail, sl, user, params)
+
+	if err := c.publisher.Publish(ctx, rejection); err != nil {
+		slog.Error("failed to publish risk rejection", "rejection", rejection, "error", err)
+	}
+
+	return status.Error(codes.FailedPrecondition, rejection.Description())
+}
+
+// OnOrderRouted assigns the open order reservation to the routed order, the order remains counted towards its
+// originator's open orders until its terminal update is received.
+func (c *Checker) OnOrderRouted(reservationId string, orderId string) {
+	c.openOrders.assign(reservationId, orderId)
+}
+
+// OnRouteFailed releases the open order reservation of an order that was not routed.
+func (c *Checker) OnRouteFailed(reservationId string) {
+	c.openOrders.release(reservationId)
+}
+
+func (c *Checker) checkLimits(limits Limits, params *executionvenue.CreateAndRouteOrderParams) (Reason, string) {
+	quantity := params.Quantity.ToFloat()
+
+	if limits.MaxOrderQuantity > 0 && quantity > limits.MaxOrderQuantity {
+		return MaxOrderQuantityBreached, fmt.Sprintf("order quantity %v exceeds max order quantity %v", quantity,
+			limits.MaxOrderQuantity)
+	}
+
+	lastPrice, haveLastPrice := c.lastPrices.get(params.ListingId)
+	haveLastPrice = haveLastPrice && lastPrice != 0
+
+	// An order that cannot be priced is rejected rather than routed without the limits that need its price
+	needLastPrice := (limits.MaxOrderNotional > 0 && params.Price == nil) ||
+		(limits.PriceCollarPercent > 0 && params.Price != nil)
+	if needLastPrice && !haveLastPrice {
+		return PriceUnavailable, fmt.Sprintf("no last traded price is available for listing %v", params.ListingId)
+	}
+
+	if limits.MaxOrderNotional > 0 {
+		price := lastPrice
+		if params.Price != nil {
+			price = params.Price.ToFloat()
+		}
+
+		notional := math.Abs(price * quantity)
+		if notional > limits.MaxOrderNotional {
+			return MaxOrderNotionalBreached, fmt.Sprintf("order notional %v exceeds max order notional %v", notional,
+				limits.MaxOrderNotional)
+		}
+	}
+
+	if limits.PriceCollarPercent > 0 && params.Price != nil {
+		price := params.Price.ToFloat()
+		deviationPercent := math.Abs(price-lastPrice) / math.Abs(lastPrice) * 100
+		if deviationPercent > limits.PriceCollarPercent {
+			return PriceCollarBreached, fmt.Sprintf("order price %v is %.2f%% from the last traded price %v, the price collar is %v%%",
+				price, deviationPercent, lastPrice, limits.PriceCollarPercent)
+		}
+	}
+
+	return "", ""
+}
+
+// Rejection describes an order that was rejected by the pre-trade risk checks.
+type Rejection struct {
+	Time             time.Time `json:"time"`
+	Reason           Reason    `json:"reason"`
+	Detail           string    `json:"detail"`
+	Scope            string    `json:"scope"`
+	ScopeId          string    `json:"scopeId"`
+	User             string    `json:"user"`
+	OriginatorId     string    `json:"originatorId"`
+	OriginatorRef    string    `json:"originatorRef"`
+	RootOriginatorId string    `json:"rootOriginatorId"`
+	ListingId        int32     `json:"listingId"`
+	Destination      string    `json:"destination"`
+	Side             string    `json:"side"`
+	Quantity         float64   `json:"quantity"`
+	Price            float64   `json:"price"`
+}
+
+func newRejection(reason Reason, detail string, sl scopedLimits, user string,
+	params *executionvenue.CreateAndRouteOrderParams) *Rejection {
+	rejection := &Rejection{
+		Time:             time.Now(),
+		Reason:           reason,
+		Detail:           detail,
+		Scope:            sl.scope,
+		ScopeId:          sl.scopeId,
+		User:             user,
+		OriginatorId:     params.OriginatorId,
+		OriginatorRef:    params.OriginatorRef,
+		RootOriginatorId: params.RootOriginatorId,
+		ListingId:        params.ListingId,
+		Destination:      params.Destination,
+		Side:             params.OrderSide.String(),
+		Quantity:         params.Quantity.ToFloat(),
+	}
+
+	if params.Price != nil {
+		rejection.Price = params.Price.ToFloat()
+	}
+
+	return rejection
+}
+
+func (r *Rejection) Description() string {
+	if r.ScopeId != "" {
+		return fmt.Sprintf("order rejected by pre-trade risk check %v for %v %v: %v", r.Reason, r.Scope, r.ScopeId, r.Detail)
+	}
+
+	return fmt.Sprintf("order rejected by pre-trade risk check %v: %v", r.Reason, r.Detail)
+}
+
+// lastPrices tracks the last traded price of the listings it is subscribed to, a listing is subscribed to when first
+// requested if it is not already.
+type lastPrices struct {
+	mutex      sync.Mutex
+	stream     marketdata.QuoteStream
+	prices     map[int32]float64
+	subscribed map[int32]bool
+}
+
+func newLastPrices(stream marketdata.QuoteStream) *lastPrices {
+	return &lastPrices{
+		stream:     stream,
+		prices:     map[int32]float64{},
+		subscribed: map[int32]bool{},
+	}
+}
+
+func (l *lastPrices) get(listingId int32) (float64, bool) {
+	l.subscribe(listingId)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	price, ok := l.prices[listingId]
+	return price, ok
+}
+
+func (l *lastPrices) subscribe(listingId int32) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.subscribed[listingId] {
+		return
+	}
+
+	if err := l.stream.Subscribe(listingId); err != nil {
+		slog.Error("failed to subscribe to quotes for listing", "listingId", listingId, "error", err)
+		return
+	}
+
+	l.subscribed[listingId] = true
+}
+
+func (l *lastPrices) onQuote(quote *model.ClobQuote) {
+	if quote.LastPrice == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.prices[quote.ListingId] = quote.LastPrice.ToFloat()
+}
+
+// terminatedOrderRetention is how long the id of a terminated order is kept so that the order is not counted as open
+// if the router records it as routed after its terminal update has been received.
+const terminatedOrderRetention = time.Minute
+
+type terminatedOrder struct {
+	id   string
+	time time.Time
+}
+
+// openOrders tracks the non-terminal orders of each originator, including the orders that have passed the risk check
+// and are being routed.
+type openOrders struct {
+	mutex                   sync.Mutex
+	orderToOriginator       map[string]string
+	reservationToOriginator map[string]string
+	nextReservationId       int
+	originatorToCount       map[string]int
+	terminatedOrderIds      map[string]bool
+	terminatedOrders        []terminatedOrder
+	now                     func() time.Time
+}
+
+func newOpenOrders() *openOrders {
+	return &openOrders{
+		orderToOriginator:       map[string]string{},
+		reservationToOriginator: map[string]string{},
+		originatorToCount:       map[string]int{},
+		terminatedOrderIds:      map[string]bool{},
+		now:                     time.Now,
+	}
+}
+
+// reserve counts an order towards the originator's open orders unless the originator has reached the max open orders
+// of any of the given scopes, in which case the breached scope and the originator's open order count are returned.
+func (o *openOrders) reserve(originatorId string, scopes []scopedLimits) (string, *scopedLimits, int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	open := o.originatorToCount[originatorId]
+	for i, sl := range scopes {
+		if sl.limits.MaxOpenOrders > 0 && open >= sl.limits.MaxOpenOrders {
+			return "", &scopes[i], open
+		}
+	}
+
+	o.nextReservationId++
+	reservationId := strconv.Itoa(o.nextReservationId)
+	o.reservationToOriginator[reservationId] = originatorId
+	o.originatorToCount[originatorId]++
+
+	return reservationId, nil, open
+}
+
+// assign replaces the reservation with the order, if an update of the order has already been received the order is
+// already counted, or has terminated, and the reservation is released.
+func (o *openOrders) assign(reservationId string, orderId string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	originatorId, ok := o.reservationToOriginator[reservationId]
+	if !ok {
+		return
+	}
+	delete(o.reservationToOriginator, reservationId)
+
+	if _, ok := o.orderToOriginator[orderId]; ok || o.terminatedOrderIds[orderId] {
+		o.decrement(originatorId)
+		return
+	}
+
+	o.orderToOriginator[orderId] = originatorId
+}
+
+func (o *openOrders) release(reservationId string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if originatorId, ok := o.reservationToOriginator[reservationId]; ok {
+		delete(o.reservationToOriginator, reservationId)
+		o.decrement(originatorId)
+	}
+}
+
+func (o *openOrders) decrement(originatorId string) {
+	o.originatorToCount[originatorId]--
+	if o.originatorToCount[originatorId] <= 0 {
+		delete(o.originatorToCount, originatorId)
+	}
+}
+
+func (o *openOrders) onOrderUpdate(order *model.Order) {
+	if order.IsTerminalState() {
+		o.remove(order.Id)
+	} else {
+		o.add(order.Id, order.OriginatorId)
+	}
+}
+
+func (o *openOrders) add(orderId string, originatorId string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.orderToOriginator[orderId]; ok || o.terminatedOrderIds[orderId] {
+		return
+	}
+
+	o.orderToOriginator[orderId] = originatorId
+	o.originatorToCount[originatorId]++
+}
+
+func (o *openOrders) remove(orderId string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	now := o.now()
+	o.purgeTerminatedOrders(now)
+
+	if !o.terminatedOrderIds[orderId] {
+		o.terminatedOrderIds[orderId] = true
+		o.terminatedOrders = append(o.terminatedOrders, terminatedOrder{id: orderId, time: now})
+	}
+
+	if originatorId, ok := o.orderToOriginator[orderId]; ok {
+		delete(o.orderToOriginator, orderId)
+		o.decrement(originatorId)
+	}
+}
+
+// purgeTerminatedOrders removes the terminated orders that have been retained for longer than the retention period,
+// the terminated orders are held in the order they were terminated.
+func (o *openOrders) purgeTerminatedOrders(now time.Time) {
+	purged := 0
+	for _, order := range o.terminatedOrders {
+		if now.Sub(order.time) < terminatedOrderRetention {
+			break
+		}
+		delete(o.terminatedOrderIds, order.id)
+		purged++
+	}
+
+	o.terminatedOrders = o.terminatedOrders[purged:]
+}
+
+func (o *openOrders) count(originatorId string) int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.originatorToCount[originatorId]
+}
@@ -0,0 +1,328 @@
+package risk
+
+import (
+	"context"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testQuoteStream struct {
+	subscribed []int32
+	out        chan *model.ClobQuote
+}
+
+func newTestQuoteStream() *testQuoteStream {
+	return &testQuoteStream{out: make(chan *model.ClobQuote)}
+}
+
+func (t *testQuoteStream) Subscribe(listingId int32) error {
+	t.subscribed = append(t.subscribed, listingId)
+	return nil
+}
+
+func (t *testQuoteStream) Chan() <-chan *model.ClobQuote {
+	return t.out
+}
+
+func (t *testQuoteStream) Close() {
+}
+
+type testPublisher struct {
+	mutex      sync.Mutex
+	rejections []*Rejection
+}
+
+func (t *testPublisher) Publish(_ context.Context, rejection *Rejection) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rejections = append(t.rejections, rejection)
+	return nil
+}
+
+func newTestChecker(t *testing.T, config *Config) (*Checker, *testPublisher, *testQuoteStream, chan *model.Order) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	publisher := &testPublisher{}
+	quoteStream := newTestQuoteStream()
+	orderUpdates := make(chan *model.Order)
+
+	checker := NewChecker(ctx, NewStaticConfigSource(config), publisher, quoteStream, map[string]*model.Order{},
+		orderUpdates)
+
+	return checker, publisher, quoteStream, orderUpdates
+}
+
+func newParams(quantity float64, price float64) *executionvenue.CreateAndRouteOrderParams {
+	return &executionvenue.CreateAndRouteOrderParams{
+		OrderSide:        model.Side_BUY,
+		Quantity:         model.FasD(quantity),
+		Price:            model.FasD(price),
+		ListingId:        1,
+		Destination:      "XNAS",
+		OriginatorId:     "deskA",
+		RootOriginatorId: "deskA",
+	}
+}
+
+func TestCheckOrderLimits(t *testing.T) {
+
+	config := &Config{
+		Default: Limits{MaxOrderQuantity: 1000},
+		Users: map[string]Limits{
+			"userA": {MaxOrderQuantity: 100, MaxOrderNotional: 5000},
+		},
+		Desks: map[string]Limits{
+			"deskA": {MaxOrderNotional: 2000},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		user       string
+		params     *executionvenue.CreateAndRouteOrderParams
+		wantReason Reason
+		wantScope  string
+	}{
+		{name: "within user and desk limits", user: "userA", params: newParams(10, 100)},
+		{name: "breaches user max quantity", user: "userA", params: newParams(101, 1),
+			wantReason: MaxOrderQuantityBreached, wantScope: "user"},
+		{name: "breaches desk max notional", user: "userA", params: newParams(30, 100),
+			wantReason: MaxOrderNotionalBreached, wantScope: "desk"},
+		{name: "unknown user is subject to desk limits only", user: "userB", params: newParams(1500, 1),
+			wantReason: ""},
+		{name: "default limits apply when no user or desk limits exist", user: "userB",
+			params: &executionvenue.CreateAndRouteOrderParams{Quantity: model.FasD(1001), Price: model.FasD(1),
+				OriginatorId: "deskB", RootOriginatorId: "deskB"},
+			wantReason: MaxOrderQuantityBreached, wantScope: "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker, publisher, _, _ := newTestChecker(t, config)
+
+			_, err := checker.Check(context.Background(), tt.user, tt.params)
+
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				assert.Empty(t, publisher.rejections)
+				return
+			}
+
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			if assert.Len(t, publisher.rejections, 1) {
+				assert.Equal(t, tt.wantReason, publisher.rejections[0].Reason)
+				assert.Equal(t, tt.wantScope, publisher.rejections[0].Scope)
+				assert.Equal(t, tt.user, publisher.rejections[0].User)
+			}
+		})
+	}
+}
+
+func TestPriceCollarIsAppliedAgainstLastTradedPrice(t *testing.T) {
+	checker, publisher, quoteStream, _ := newTestChecker(t, &Config{Default: Limits{PriceCollarPercent: 10}})
+
+	_, err := checker.Check(context.Background(), "", newParams(10, 200))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "order should be rejected when the last traded price is unknown")
+	assert.Equal(t, PriceUnavailable, publisher.rejections[0].Reason)
+	assert.Equal(t, []int32{1}, quoteStream.subscribed)
+
+	quoteStream.out <- &model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100)}
+	quoteStream.out <- &model.ClobQuote{ListingId: 2}
+
+	_, err = checker.Check(context.Background(), "", newParams(10, 109))
+	assert.NoError(t, err)
+
+	_, err = checker.Check(context.Background(), "", newParams(10, 111))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, PriceCollarBreached, publisher.rejections[1].Reason)
+	assert.Equal(t, []int32{1}, quoteStream.subscribed)
+}
+
+func TestMarketOrderNotionalIsCheckedAgainstLastTradedPrice(t *testing.T) {
+	checker, publisher, quoteStream, _ := newTestChecker(t, &Config{Default: Limits{MaxOrderNotional: 1000,
+		PriceCollarPercent: 10}})
+
+	marketOrder := newParams(10, 0)
+	marketOrder.Price = nil
+
+	_, err := checker.Check(context.Background(), "", marketOrder)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, PriceUnavailable, publisher.rejections[0].Reason)
+
+	quoteStream.out <- &model.ClobQuote{ListingId: 1, LastPrice: model.IasD(90)}
+	quoteStream.out <- &model.ClobQuote{ListingId: 2}
+
+	_, err = checker.Check(context.Background(), "", marketOrder)
+	assert.NoError(t, err)
+
+	marketOrder.Quantity = model.IasD(12)
+	_, err = checker.Check(context.Background(), "", marketOrder)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, MaxOrderNotionalBreached, publisher.rejections[1].Reason)
+}
+
+func TestQuotesAreSubscribedForListingsOfExistingAndNewOrders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	quoteStream := newTestQuoteStream()
+	orderUpdates := make(chan *model.Order)
+	checker := NewChecker(ctx, NewStaticConfigSource(&Config{Default: Limits{PriceCollarPercent: 10}}), &testPublisher{},
+		quoteStream, map[string]*model.Order{"o1": {Id: "o1", ListingId: 1, Status: model.OrderStatus_FILLED}},
+		orderUpdates)
+
+	orderUpdates <- &model.Order{Id: "o2", ListingId: 2, Status: model.OrderStatus_LIVE}
+	orderUpdates <- &model.Order{Id: "o3", ListingId: 1, Status: model.OrderStatus_LIVE}
+	quoteStream.out <- &model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100)}
+
+	// the first order for a listing is priced if the listing's quote was received before the order
+	_, err := checker.Check(context.Background(), "", newParams(10, 101))
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 2}, quoteStream.subscribed)
+}
+
+func TestMaxOpenOrdersPerOriginator(t *testing.T) {
+	checker, publisher, _, orderUpdates := newTestChecker(t, &Config{Default: Limits{MaxOpenOrders: 2}})
+
+	params := newParams(10, 100)
+	reservationId, err := checker.Check(context.Background(), "", params)
+	assert.NoError(t, err)
+	checker.OnOrderRouted(reservationId, "o1")
+	orderUpdates <- &model.Order{Id: "o1", OriginatorId: "deskA", Status: model.OrderStatus_LIVE}
+	orderUpdates <- &model.Order{Id: "o2", OriginatorId: "deskA", Status: model.OrderStatus_LIVE}
+	orderUpdates <- &model.Order{Id: "o3", OriginatorId: "deskB", Status: model.OrderStatus_LIVE}
+
+	_, err = checker.Check(context.Background(), "", params)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, MaxOpenOrdersBreached, publisher.rejections[0].Reason)
+
+	orderUpdates <- &model.Order{Id: "o1", OriginatorId: "deskA", Status: model.OrderStatus_CANCELLED}
+	orderUpdates <- &model.Order{Id: "o4", OriginatorId: "deskB", Status: model.OrderStatus_LIVE}
+
+	_, err = checker.Check(context.Background(), "", params)
+	assert.NoError(t, err)
+}
+
+func TestConcurrentOrdersCannotExceedMaxOpenOrders(t *testing.T) {
+	checker, publisher, _, _ := newTestChecker(t, &Config{Default: Limits{MaxOpenOrders: 3}})
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var reservationIds []string
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reservationId, err := checker.Check(context.Background(), "", newParams(10, 100)); err == nil {
+				mutex.Lock()
+				reservationIds = append(reservationIds, reservationId)
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, reservationIds, 3)
+	assert.Len(t, publisher.rejections, 7)
+}
+
+func TestOpenOrderReservationIsReleasedWhenRouteFails(t *testing.T) {
+	checker, _, _, _ := newTestChecker(t, &Config{Default: Limits{MaxOpenOrders: 1}})
+
+	reservationId, err := checker.Check(context.Background(), "", newParams(10, 100))
+	assert.NoError(t, err)
+
+	_, err = checker.Check(context.Background(), "", newParams(10, 100))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	checker.OnRouteFailed(reservationId)
+
+	_, err = checker.Check(context.Background(), "", newParams(10, 100))
+	assert.NoError(t, err)
+}
+
+func TestOrderRoutedAfterTerminalUpdateIsNotCountedAsOpen(t *testing.T) {
+	orders := newOpenOrders()
+
+	reservationId, _, _ := orders.reserve("deskA", nil)
+	orders.onOrderUpdate(&model.Order{Id: "o1", OriginatorId: "deskA", Status: model.OrderStatus_FILLED})
+	orders.assign(reservationId, "o1")
+
+	assert.Equal(t, 0, orders.count("deskA"))
+}
+
+func TestOrderRoutedAfterLiveUpdateIsCountedOnce(t *testing.T) {
+	orders := newOpenOrders()
+
+	reservationId, _, _ := orders.reserve("deskA", nil)
+	orders.onOrderUpdate(&model.Order{Id: "o1", OriginatorId: "deskA", Status: model.OrderStatus_LIVE})
+	orders.assign(reservationId, "o1")
+
+	assert.Equal(t, 1, orders.count("deskA"))
+}
+
+func TestTerminatedOrdersAreRetainedForTheRetentionPeriod(t *testing.T) {
+	orders := newOpenOrders()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	orders.now = func() time.Time { return now }
+
+	orders.onOrderUpdate(&model.Order{Id: "o1", OriginatorId: "deskA", Status: model.OrderStatus_LIVE})
+	orders.onOrderUpdate(&model.Order{Id: "o1", OriginatorId: "deskA", Status: model.OrderStatus_FILLED})
+	now = now.Add(terminatedOrderRetention / 2)
+	orders.onOrderUpdate(&model.Order{Id: "o2", OriginatorId: "deskA", Status: model.OrderStatus_CANCELLED})
+	assert.Equal(t, map[string]bool{"o1": true, "o2": true}, orders.terminatedOrderIds)
+
+	now = now.Add(terminatedOrderRetention / 2)
+	orders.onOrderUpdate(&model.Order{Id: "o3", OriginatorId: "deskA", Status: model.OrderStatus_FILLED})
+	assert.Equal(t, map[string]bool{"o2": true, "o3": true}, orders.terminatedOrderIds)
+	assert.Equal(t, 2, len(orders.terminatedOrders))
+
+	reservationId, _, _ := orders.reserve("deskA", nil)
+	orders.assign(reservationId, "o2")
+	assert.Equal(t, 0, orders.count("deskA"))
+}
+
+func TestFileConfigSourceReloadsModifiedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	if err := os.WriteFile(path, []byte(`{"default":{"maxOrderQuantity":10}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := NewFileConfigSource(ctx, path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 10.0, source.Config().Default.MaxOrderQuantity)
+
+	if err := os.WriteFile(path, []byte(`not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Now(), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 10.0, source.Config().Default.MaxOrderQuantity, "invalid config should not replace the loaded config")
+
+	if err := os.WriteFile(path, []byte(`{"users":{"userA":{"maxOpenOrders":5}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Eventually(t, func() bool {
+		return source.Config().Users["userA"].MaxOpenOrders == 5
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,149 @@
+// Package risk contains the pre-trade risk checks that the order router applies to new orders before they are routed
+// to an execution venue.
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Limits are the pre-trade risk limits applied to an order, a limit with a zero value is not applied.
+type Limits struct {
+	MaxOrderQuantity float64 `json:"maxOrderQuantity"`
+	MaxOrderNotional float64 `json:"maxOrderNotional"`
+
+	// PriceCollarPercent is the maximum permitted deviation of an order's price from the last traded price of the
+	// listing, expressed as a percentage of the last traded price.
+	PriceCollarPercent float64 `json:"priceCollarPercent"`
+
+	// MaxOpenOrders is the maximum number of non-terminal orders permitted per originator.
+	MaxOpenOrders int `json:"maxOpenOrders"`
+}
+
+// Config holds the per user and per desk limits.  The default limits are applied to an order only when neither the
+// user nor the desk of the order has its own limits.
+type Config struct {
+	Default Limits            `json:"default"`
+	Users   map[string]Limits `json:"users"`
+	Desks   map[string]Limits `json:"desks"`
+}
+
+type scopedLimits struct {
+	scope   string
+	scopeId string
+	limits  Limits
+}
+
+func (c *Config) limitsFor(user string, desk string) []scopedLimits {
+	var result []scopedLimits
+
+	if limits, ok := c.Users[user]; ok && user != "" {
+		result = append(result, scopedLimits{scope: "user", scopeId: user, limits: limits})
+	}
+
+	if limits, ok := c.Desks[desk]; ok && desk != "" {
+		result = append(result, scopedLimits{scope: "desk", scopeId: desk, limits: limits})
+	}
+
+	if len(result) == 0 {
+		result = append(result, scopedLimits{scope: "default", limits: c.Default})
+	}
+
+	return result
+}
+
+// ParseConfig parses a json risk configuration.
+func ParseConfig(configJson []byte) (*Config, error) {
+	config := &Config{}
+	if err := json.Unmarshal(configJson, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal risk config: %w", err)
+	}
+
+	return config, nil
+}
+
+type staticConfigSource struct {
+	config *Config
+}
+
+// NewStaticConfigSource returns a config source that always returns the given config.
+func NewStaticConfigSource(config *Config) *staticConfigSource {
+	return &staticConfigSource{config: config}
+}
+
+func (s *staticConfigSource) Config() *Config {
+	return s.config
+}
+
+// FileConfigSource loads the risk config from a json file and reloads it whenever the file's modification time changes.
+type FileConfigSource struct {
+	path    string
+	config  atomic.Pointer[Config]
+	modTime time.Time
+}
+
+// NewFileConfigSource loads the risk config from the given file and then checks the file for changes at the given
+// interval until the context is cancelled.  If a changed file cannot be parsed the previously loaded config is retained.
+func NewFileConfigSource(ctx context.Context, path string, reloadInterval time.Duration) (*FileConfigSource, error) {
+	f := &FileConfigSource{path: path}
+
+	if _, err := f.reloadIfModified(); err != nil {
+		return nil, fmt.Errorf("failed to load risk config from %s: %w", path, err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reloaded, err := f.reloadIfModified()
+				if err != nil {
+					slog.Error("failed to reload risk config, retaining previous config", "path", path, "error", err)
+				} else if reloaded {
+					slog.Info("reloaded risk config", "path", path, "config", f.Config())
+				}
+			}
+		}
+	}()
+
+	return f, nil
+}
+
+func (f *FileConfigSource) Config() *Config {
+	return f.config.Load()
+}
+
+func (f *FileConfigSource) reloadIfModified() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if info.ModTime().Equal(f.modTime) {
+		return false, nil
+	}
+
+	configJson, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	config, err := ParseConfig(configJson)
+	if err != nil {
+		return false, err
+	}
+
+	f.config.Store(config)
+	f.modTime = info.ModTime()
+
+	return true, nil
+}
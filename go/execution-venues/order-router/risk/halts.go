@@ -0,0 +1,116 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/open-trading-platform/go/shared/halts"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"sync"
+)
+
+type haltsReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	ReadLag(ctx context.Context) (int64, error)
+	Close() error
+}
+
+// HaltTracker follows the halts topic published by the order monitor and rejects new orders to which an active halt
+// applies.
+type HaltTracker struct {
+	mutex sync.Mutex
+	halts map[string]*halts.Halt
+}
+
+// NewHaltTracker reads the halts topic up to its current end before returning so that no order is accepted for a
+// halted scope after a restart, it then continues to follow the topic until the context is cancelled.
+func NewHaltTracker(ctx context.Context, reader haltsReader) (*HaltTracker, error) {
+	h := &HaltTracker{halts: map[string]*halts.Halt{}}
+
+	for {
+		lag, err := reader.ReadLag(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read halts topic lag: %w", err)
+		}
+
+		if lag <= 0 {
+			break
+		}
+
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read halt: %w", err)
+		}
+
+		h.onMessage(msg)
+	}
+
+	slog.Info("loaded trading halts", "halts", h.halts)
+
+	go func() {
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing halts reader", "error", err)
+			}
+		}()
+
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				slog.Error("failed to read halt, trading halts will no longer be updated", "error", err)
+				return
+			}
+
+			h.onMessage(msg)
+		}
+	}()
+
+	return h, nil
+}
+
+func (h *HaltTracker) onMessage(msg kafka.Message) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := string(msg.Key)
+	if len(msg.Value) == 0 {
+		if halt, ok := h.halts[key]; ok {
+			delete(h.halts, key)
+			slog.Info("trading halt lifted", "halt", halt)
+		}
+		return
+	}
+
+	halt := &halts.Halt{}
+	if err := json.Unmarshal(msg.Value, halt); err != nil {
+		slog.Error("failed to unmarshal halt", "key", key, "error", err)
+		return
+	}
+
+	h.halts[key] = halt
+	slog.Info("trading halted", "halt", halt)
+}
+
+// Check returns a grpc status error with code FailedPrecondition if trading is halted for the order's originator, desk
+// or listing, or globally.
+func (h *HaltTracker) Check(params *executionvenue.CreateAndRouteOrderParams) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, halt := range h.halts {
+		if halt.AppliesTo(params.OriginatorId, params.RootOriginatorId, params.ListingId) {
+			if halt.Scope == halts.Global {
+				return status.Errorf(codes.FailedPrecondition, "trading is halted: %v", halt.Reason)
+			}
+
+			return status.Errorf(codes.FailedPrecondition, "trading is halted for %v %v: %v",
+				halt.Scope, halt.ScopeId, halt.Reason)
+		}
+	}
+
+	return nil
+}
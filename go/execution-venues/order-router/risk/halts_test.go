@@ -0,0 +1,101 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+type testHaltsReader struct {
+	initial []kafka.Message
+	updates chan kafka.Message
+}
+
+func (t *testHaltsReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if len(t.initial) > 0 {
+		msg := t.initial[0]
+		t.initial = t.initial[1:]
+		return msg, nil
+	}
+
+	select {
+	case msg := <-t.updates:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, errors.New("closed")
+	}
+}
+
+func (t *testHaltsReader) ReadLag(context.Context) (int64, error) {
+	return int64(len(t.initial)), nil
+}
+
+func (t *testHaltsReader) Close() error {
+	return nil
+}
+
+func haltMessage(key string, value string) kafka.Message {
+	msg := kafka.Message{Key: []byte(key)}
+	if value != "" {
+		msg.Value = []byte(value)
+	}
+	return msg
+}
+
+func TestHaltTrackerRejectsOrdersForHaltedScopes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := &testHaltsReader{
+		initial: []kafka.Message{
+			haltMessage("DESK/deskA", `{"scope":"DESK","scopeId":"deskA","reason":"desk limit"}`),
+			haltMessage("LISTING/7", `{"scope":"LISTING","scopeId":"7","reason":"news pending"}`),
+			haltMessage("LISTING/7", ""),
+			haltMessage("ORIGINATOR/strategy-1", `{"scope":"ORIGINATOR","scopeId":"strategy-1","reason":"runaway"}`),
+		},
+		updates: make(chan kafka.Message),
+	}
+
+	tracker, err := NewHaltTracker(ctx, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		params     *executionvenue.CreateAndRouteOrderParams
+		wantHalted bool
+	}{
+		{name: "halted desk", params: &executionvenue.CreateAndRouteOrderParams{RootOriginatorId: "deskA", OriginatorId: "deskA"}, wantHalted: true},
+		{name: "halted originator", params: &executionvenue.CreateAndRouteOrderParams{RootOriginatorId: "deskB", OriginatorId: "strategy-1"}, wantHalted: true},
+		{name: "lifted listing halt", params: &executionvenue.CreateAndRouteOrderParams{RootOriginatorId: "deskB", OriginatorId: "deskB", ListingId: 7}},
+		{name: "not halted", params: &executionvenue.CreateAndRouteOrderParams{RootOriginatorId: "deskB", OriginatorId: "deskB", ListingId: 8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tracker.Check(tt.params)
+			if tt.wantHalted {
+				assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	notHalted := &executionvenue.CreateAndRouteOrderParams{RootOriginatorId: "deskB", OriginatorId: "deskB", ListingId: 8}
+
+	reader.updates <- haltMessage("GLOBAL/", `{"scope":"GLOBAL","reason":"market wide halt"}`)
+	reader.updates <- haltMessage("DESK/deskA", "")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(tracker.Check(notHalted)))
+
+	reader.updates <- haltMessage("GLOBAL/", "")
+	reader.updates <- haltMessage("DESK/deskC", `{"scope":"DESK","scopeId":"deskC"}`)
+	assert.NoError(t, tracker.Check(notHalted))
+	assert.NoError(t, tracker.Check(&executionvenue.CreateAndRouteOrderParams{RootOriginatorId: "deskA"}))
+}
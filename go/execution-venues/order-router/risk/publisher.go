@@ -0,0 +1,39 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaRejectionPublisher publishes risk rejections as json to a kafka topic, keyed by originator id.
+type KafkaRejectionPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaRejectionPublisher(writerConfig kafka.WriterConfig) *KafkaRejectionPublisher {
+	return &KafkaRejectionPublisher{writer: kafka.NewWriter(writerConfig)}
+}
+
+func (k *KafkaRejectionPublisher) Publish(ctx context.Context, rejection *Rejection) error {
+	rejectionJson, err := json.Marshal(rejection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rejection: %w", err)
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(rejection.OriginatorId),
+		Value: rejectionJson,
+	}
+
+	if err = k.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to write rejection to kafka: %w", err)
+	}
+
+	return nil
+}
+
+func (k *KafkaRejectionPublisher) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	api "github.com/ettec/open-trading-platform/go/order-router/api/orderrouter"
+	"github.com/ettec/otp-common/model"
+	"sort"
+)
+
+// GetRoutingTable returns the venues the router currently routes to and the requests pending for each owner.
+func (o *orderRouter) GetRoutingTable(context.Context, *model.Empty) (*api.RoutingTable, error) {
+	pendingCounts := o.pendingRequests.counts()
+
+	o.mux.Lock()
+	table := &api.RoutingTable{}
+	for _, evs := range o.micToExecVenue {
+		for _, ev := range evs {
+			table.Venues = append(table.Venues, &api.RoutedVenue{
+				Mic:             ev.mic,
+				Ordinal:         int32(ev.ordinal),
+				OwnerId:         ev.ownerId,
+				TargetAddress:   ev.targetAddress,
+				ConnectionState: ev.connectionState().String(),
+				Healthy:         ev.isHealthy(),
+				PendingRequests: int32(pendingCounts[ev.ownerId]),
+			})
+		}
+	}
+
+	for ownerId, count := range pendingCounts {
+		if _, ok := o.ownerIdToExecVenue[ownerId]; !ok {
+			table.UnknownOwnerRequests = append(table.UnknownOwnerRequests,
+				&api.PendingOwnerRequests{OwnerId: ownerId, PendingRequests: int32(count)})
+		}
+	}
+	o.mux.Unlock()
+
+	sort.Slice(table.Venues, func(i, j int) bool {
+		if table.Venues[i].Mic != table.Venues[j].Mic {
+			return table.Venues[i].Mic < table.Venues[j].Mic
+		}
+		return table.Venues[i].Ordinal < table.Venues[j].Ordinal
+	})
+
+	sort.Slice(table.UnknownOwnerRequests, func(i, j int) bool {
+		return table.UnknownOwnerRequests[i].OwnerId < table.UnknownOwnerRequests[j].OwnerId
+	})
+
+	return table, nil
+}
@@ -2,25 +2,62 @@ package main
 
 import (
 	"context"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/order-router/api/orderrouter"
+	"github.com/ettec/open-trading-platform/go/order-router/discovery"
+	"github.com/ettec/open-trading-platform/go/order-router/risk"
+	"github.com/ettec/open-trading-platform/go/wallet-service/api/walletservice"
+	common "github.com/ettec/otp-common"
 	"github.com/ettec/otp-common/api/executionvenue"
 	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/orderstore"
+	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/reflection"
-	"k8s.io/apimachinery/pkg/types"
 	"log"
 	"log/slog"
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 )
 
 var errLog = log.New(os.Stderr, "", log.Ltime|log.Lshortfile)
 
+// venueConnection is the connection to an execution venue, it is implemented by *grpc.ClientConn.
+type venueConnection interface {
+	GetState() connectivity.State
+	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
+	Close() error
+}
+
 type execVenue struct {
-	podId  types.UID
-	client executionvenue.ExecutionVenueClient
-	conn   *grpc.ClientConn
+	ownerId           string
+	mic               string
+	ordinal           int
+	targetAddress     string
+	client            executionvenue.ExecutionVenueClient
+	conn              venueConnection
+	stopHealthMonitor context.CancelFunc
+}
+
+// isHealthy returns true if the connection to the venue is ready or idle, an idle connection reconnects on its next use.
+func (e *execVenue) isHealthy() bool {
+	state := e.connectionState()
+	return state == connectivity.Ready || state == connectivity.Idle
+}
+
+func (e *execVenue) connectionState() connectivity.State {
+	if e.conn == nil {
+		return connectivity.Ready
+	}
+
+	return e.conn.GetState()
 }
 
 func main() {
@@ -28,11 +65,41 @@ func main() {
 	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
 
 	maxConnectRetrySecs := bootstrap.GetOptionalIntEnvVar("MAX_CONNECT_RETRY_SECONDS", 60)
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+	riskLimitsFile := bootstrap.GetOptionalEnvVar("RISK_LIMITS_FILE", "")
+	riskLimitsReloadInterval := time.Duration(bootstrap.GetOptionalIntEnvVar("RISK_LIMITS_RELOAD_INTERVAL_SECS", 10)) * time.Second
+	riskRejectionsTopic := bootstrap.GetOptionalEnvVar("RISK_REJECTIONS_TOPIC", "risk-rejections")
+	haltsTopic := bootstrap.GetOptionalEnvVar("TRADING_HALTS_TOPIC", "trading-halts")
+	venueDiscoveryType := bootstrap.GetOptionalEnvVar("EXEC_VENUE_DISCOVERY", "kubernetes")
+	pendingRequestTtl := time.Duration(bootstrap.GetOptionalIntEnvVar("PENDING_REQUEST_TTL_SECS", 300)) * time.Second
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	orderRouter, err := NewOrderRouter(ctx, maxConnectRetrySecs)
+	riskChecker, err := newRiskChecker(ctx, riskLimitsFile, riskLimitsReloadInterval, kafkaBrokers, riskRejectionsTopic,
+		time.Duration(maxConnectRetrySecs)*time.Second)
+	if err != nil {
+		log.Panicf("failed to create risk checker: %v", err)
+	}
+
+	haltTracker, err := risk.NewHaltTracker(ctx, kafka.NewReader(orderstore.DefaultReaderConfig(haltsTopic, kafkaBrokers)))
+	if err != nil {
+		log.Panicf("failed to create halt tracker: %v", err)
+	}
+
+	buyingPowerChecker, err := newBuyingPowerChecker(kafkaBrokers, riskRejectionsTopic,
+		bootstrap.GetOptionalEnvVar("TRADING_CURRENCY", "USD"))
+	if err != nil {
+		log.Panicf("failed to create buying power checker: %v", err)
+	}
+
+	venueDiscovery, err := newVenueDiscovery(venueDiscoveryType)
+	if err != nil {
+		log.Panicf("failed to create venue discovery: %v", err)
+	}
+
+	orderRouter, err := NewOrderRouter(ctx, maxConnectRetrySecs, venueDiscovery, riskChecker, buyingPowerChecker,
+		haltTracker, pendingRequestTtl)
 	if err != nil {
 		log.Panicf("failed to create order router: %v", err)
 	}
@@ -48,6 +115,7 @@ func main() {
 	s := grpc.NewServer()
 
 	executionvenue.RegisterExecutionVenueServer(s, orderRouter)
+	api.RegisterOrderRouterServer(s, orderRouter)
 
 	reflection.Register(s)
 
@@ -65,3 +133,101 @@ func main() {
 		log.Panicf("Error while serving : %v", err)
 	}
 }
+
+// newVenueDiscovery returns the execution venue discovery of the given type, static discovery reads the venues from the
+// json file given by EXEC_VENUES_FILE or, if that is not set, from the EXEC_VENUES list.
+func newVenueDiscovery(discoveryType string) (discovery.VenueDiscovery, error) {
+	switch discoveryType {
+	case "kubernetes":
+		return discovery.NewKubernetesDiscovery(k8s.GetK8sClientSet(false), "default", discovery.ExecVenueLabelSelector), nil
+	case "static":
+		if venuesFile := bootstrap.GetOptionalEnvVar("EXEC_VENUES_FILE", ""); venuesFile != "" {
+			reloadInterval := time.Duration(bootstrap.GetOptionalIntEnvVar("EXEC_VENUES_RELOAD_INTERVAL_SECS", 10)) * time.Second
+			return discovery.NewFileDiscovery(venuesFile, reloadInterval), nil
+		}
+
+		venues, err := discovery.ParseVenuesString(bootstrap.GetEnvVar("EXEC_VENUES"))
+		if err != nil {
+			return nil, err
+		}
+
+		return discovery.NewStaticDiscovery(venues), nil
+	default:
+		return nil, fmt.Errorf("unknown venue discovery type: %v", discoveryType)
+	}
+}
+
+func newRiskChecker(ctx context.Context, limitsFile string, reloadInterval time.Duration, kafkaBrokers []string,
+	rejectionsTopic string, maxConnectRetry time.Duration) (*risk.Checker, error) {
+
+	var configSource interface{ Config() *risk.Config }
+	if limitsFile != "" {
+		fileConfigSource, err := risk.NewFileConfigSource(ctx, limitsFile, reloadInterval)
+		if err != nil {
+			return nil, err
+		}
+		configSource = fileConfigSource
+	} else {
+		slog.Warn("no risk limits file specified, pre-trade risk limits will not be applied")
+		configSource = risk.NewStaticConfigSource(&risk.Config{})
+	}
+
+	id, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	mdsAddress := bootstrap.GetOptionalEnvVar("MARKET_DATA_SERVICE_ADDRESS", "")
+	if mdsAddress == "" {
+		mdsAddress, err = k8s.GetServiceAddress("market-data-service")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get market data service address: %w", err)
+		}
+	}
+
+	quoteStream, err := marketdata.NewQuoteStreamFromMarketDataService(ctx, id, mdsAddress, maxConnectRetry,
+		bootstrap.GetOptionalIntEnvVar("RISK_QUOTE_BUFFER_SIZE", 1000))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quote stream: %w", err)
+	}
+
+	store, err := orderstore.NewKafkaStore(orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers),
+		orderstore.DefaultWriterConfig(common.ORDERS_TOPIC, kafkaBrokers), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order store: %w", err)
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	initialOrders, orderUpdates, err := store.SubscribeToAllOrders(ctx, startOfDay,
+		bootstrap.GetOptionalIntEnvVar("RISK_ORDER_UPDATES_BUFFER_SIZE", 1000))
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to order updates: %w", err)
+	}
+
+	publisher := risk.NewKafkaRejectionPublisher(orderstore.DefaultWriterConfig(rejectionsTopic, kafkaBrokers))
+
+	return risk.NewChecker(ctx, configSource, publisher, quoteStream, initialOrders, orderUpdates), nil
+}
+
+// newBuyingPowerChecker creates a checker that reserves the funds of orders in the wallet service, outside Kubernetes
+// the wallet service address is set with WALLET_SERVICE_ADDRESS.
+func newBuyingPowerChecker(kafkaBrokers []string, rejectionsTopic string, currency string) (*risk.BuyingPowerChecker, error) {
+	walletAddress := bootstrap.GetOptionalEnvVar("WALLET_SERVICE_ADDRESS", "")
+	if walletAddress == "" {
+		var err error
+		walletAddress, err = k8s.GetServiceAddress("wallet-service")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wallet service address: %w", err)
+		}
+	}
+
+	conn, err := grpc.Dial(walletAddress, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial wallet service: %w", err)
+	}
+
+	publisher := risk.NewKafkaRejectionPublisher(orderstore.DefaultWriterConfig(rejectionsTopic, kafkaBrokers))
+
+	return risk.NewBuyingPowerChecker(walletservice.NewWalletServiceClient(conn), publisher, currency), nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/strategy"
+)
+
+// povParameters are the execution parameters of a percent of volume order, rates are expressed as percentages of the
+// listing's traded volume since the start of the order.
+type povParameters struct {
+	TargetRatePercent float64 `json:"targetRatePercent"`
+	MinRatePercent    float64 `json:"minRatePercent"`
+	MaxRatePercent    float64 `json:"maxRatePercent"`
+}
+
+const povParametersSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Percent of volume parameters",
+  "type": "object",
+  "properties": {
+    "targetRatePercent": {
+      "type": "number",
+      "description": "The target participation rate as a percentage of the listing's traded volume",
+      "exclusiveMinimum": 0,
+      "maximum": 100
+    },
+    "minRatePercent": {
+      "type": "number",
+      "description": "Below this participation rate child orders cross the spread to catch up, defaults to the target rate",
+      "minimum": 0,
+      "maximum": 100
+    },
+    "maxRatePercent": {
+      "type": "number",
+      "description": "Above this participation rate exposed child orders are cancelled, defaults to the target rate",
+      "minimum": 0,
+      "maximum": 100
+    }
+  },
+  "required": ["targetRatePercent"]
+}`
+
+func getPovParameters(povParamsJson string) (*povParameters, error) {
+	params := &povParameters{}
+	if err := json.Unmarshal([]byte(povParamsJson), params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameters: %w", err)
+	}
+
+	if params.MinRatePercent == 0 {
+		params.MinRatePercent = params.TargetRatePercent
+	}
+
+	if params.MaxRatePercent == 0 {
+		params.MaxRatePercent = params.TargetRatePercent
+	}
+
+	if params.TargetRatePercent <= 0 || params.TargetRatePercent > 100 {
+		return nil, fmt.Errorf("target rate must be greater than 0 and less than or equal to 100, target rate: %v",
+			params.TargetRatePercent)
+	}
+
+	if params.MinRatePercent > params.TargetRatePercent || params.MaxRatePercent < params.TargetRatePercent ||
+		params.MaxRatePercent > 100 {
+		return nil, fmt.Errorf("rates must satisfy min rate <= target rate <= max rate <= 100, min rate: %v, target rate: %v, max rate: %v",
+			params.MinRatePercent, params.TargetRatePercent, params.MaxRatePercent)
+	}
+
+	return params, nil
+}
+
+// marketVolumeTracker accumulates the traded volume of a listing from the increments in the quote's traded volume.
+type marketVolumeTracker struct {
+	lastTradedVolume     float64
+	haveLastTradedVolume bool
+	volume               float64
+}
+
+func (m *marketVolumeTracker) onQuote(quote *model.ClobQuote) {
+	if quote.StreamInterrupted {
+		// Volume traded while the stream is interrupted is not counted
+		m.haveLastTradedVolume = false
+		return
+	}
+
+	if quote.TradedVolume == nil {
+		return
+	}
+
+	tradedVolume := quote.TradedVolume.ToFloat()
+	if m.haveLastTradedVolume {
+		delta := tradedVolume - m.lastTradedVolume
+		if delta < 0 {
+			// The traded volume is reset at the start of each trading day
+			delta = tradedVolume
+		}
+		m.volume += delta
+	}
+
+	m.lastTradedVolume = tradedVolume
+	m.haveLastTradedVolume = true
+}
+
+type childOrderAction struct {
+	cancelLiveChildOrders bool
+	sendQuantity          float64
+	aggressive            bool
+}
+
+// getChildOrderAction decides how the child orders should be adjusted to keep the order's participation within the
+// min and max rates.  At most one child order is live at a time, it is priced passively while the order is at or above
+// its min rate and aggressively when below it.
+func getChildOrderAction(params *povParameters, marketVolume float64, tradedQty float64, exposedQty float64,
+	hasLiveChildOrder bool, liveChildOrderAggressive bool) childOrderAction {
+
+	minQty := marketVolume * params.MinRatePercent / 100
+	targetQty := marketVolume * params.TargetRatePercent / 100
+	maxQty := marketVolume * params.MaxRatePercent / 100
+
+	behind := tradedQty < minQty
+
+	if hasLiveChildOrder {
+		if tradedQty+exposedQty > maxQty || (behind && !liveChildOrderAggressive) {
+			return childOrderAction{cancelLiveChildOrders: true}
+		}
+
+		return childOrderAction{}
+	}
+
+	if tradedQty < targetQty {
+		return childOrderAction{sendQuantity: targetQty - tradedQty, aggressive: behind}
+	}
+
+	return childOrderAction{}
+}
+
+// getChildOrderPrice returns the best price on the near side of the book for a passive child order or on the far side
+// for an aggressive child order, limited by the parent order's price.
+func getChildOrderPrice(side model.Side, limitPrice *model.Decimal64, quote *model.ClobQuote, aggressive bool) *model.Decimal64 {
+	var lines []*model.ClobLine
+	if (side == model.Side_BUY) == aggressive {
+		lines = quote.GetOffers()
+	} else {
+		lines = quote.GetBids()
+	}
+
+	if len(lines) == 0 || lines[0].Price == nil {
+		return limitPrice
+	}
+
+	price := lines[0].Price
+	if limitPrice == nil {
+		return price
+	}
+
+	if side == model.Side_BUY && price.GreaterThan(limitPrice) {
+		return limitPrice
+	}
+
+	if side == model.Side_SELL && price.LessThan(limitPrice) {
+		return limitPrice
+	}
+
+	return price
+}
+
+func executeAsPovStrategy(ctx context.Context, om *strategy.Strategy, params *povParameters, listing *model.Listing,
+	quoteStream marketdata.QuoteStream, orderRouter executionvenue.ExecutionVenueClient) {
+
+	if err := quoteStream.Subscribe(listing.Id); err != nil {
+		om.CancelChan <- fmt.Sprintf("failed to subscribe to quotes for listing %v: %v", listing.Id, err)
+	}
+
+	go func() {
+		defer quoteStream.Close()
+
+		if om.ParentOrder.GetTargetStatus() == model.OrderStatus_LIVE {
+			err := om.ParentOrder.SetStatus(model.OrderStatus_LIVE)
+			if err != nil {
+				msg := fmt.Sprintf("failed to set order status, cancelling order:%v", err)
+				om.Log.Error(msg)
+				om.CancelChan <- msg
+			}
+		}
+
+		om.Log.Info("order initialised", "params", params)
+
+		quotes := quoteStream.Chan()
+		volumeTracker := &marketVolumeTracker{}
+		var lastQuote *model.ClobQuote
+		liveChildOrderAggressive := false
+		cancelsSent := map[string]bool{}
+
+		for {
+			done, err := om.CheckIfDone(ctx)
+			if err != nil {
+				msg := fmt.Sprintf("failed to check if done, cancelling order:%v", err)
+				om.Log.Error(msg)
+				om.CancelChan <- msg
+			}
+
+			if done {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case q, ok := <-quotes:
+				if !ok {
+					quotes = nil
+					om.CancelChan <- "quote stream closed"
+					continue
+				}
+
+				if q.ListingId != listing.Id {
+					continue
+				}
+
+				volumeTracker.onQuote(q)
+				if !q.StreamInterrupted {
+					lastQuote = q
+				}
+			case errMsg := <-om.CancelChan:
+				if errMsg != "" {
+					om.ParentOrder.ErrorMessage = errMsg
+				}
+				err := om.CancelChildOrdersAndStrategyOrder()
+				if err != nil {
+					om.Log.Error("failed to cancel order", "error", err)
+				}
+				continue
+			case co, ok := <-om.ChildOrderUpdateChan:
+				err = om.OnChildOrderUpdate(ok, co)
+				if err != nil {
+					om.Log.Error("failed to process child order update", "error", err)
+				}
+			}
+
+			if lastQuote == nil || om.ParentOrder.GetTargetStatus() != model.OrderStatus_NONE ||
+				om.ParentOrder.IsTerminalState() {
+				continue
+			}
+
+			var liveChildOrders []*model.Order
+			for _, co := range om.ParentOrder.ChildOrders {
+				if !co.IsTerminalState() {
+					liveChildOrders = append(liveChildOrders, co)
+				}
+			}
+
+			action := getChildOrderAction(params, volumeTracker.volume, om.ParentOrder.GetTradedQuantity().ToFloat(),
+				om.ParentOrder.GetExposedQuantity().ToFloat(), len(liveChildOrders) > 0, liveChildOrderAggressive)
+
+			if action.cancelLiveChildOrders {
+				for _, co := range liveChildOrders {
+					if cancelsSent[co.Id] || co.OwnerId == "" {
+						continue
+					}
+
+					cancelsSent[co.Id] = true
+					_, err := orderRouter.CancelOrder(ctx, &executionvenue.CancelOrderParams{
+						OrderId:   co.Id,
+						ListingId: co.ListingId,
+						OwnerId:   co.OwnerId,
+					})
+					if err != nil {
+						om.Log.Error("failed to cancel child order", "childOrderId", co.Id, "error", err)
+					}
+				}
+			}
+
+			if action.sendQuantity > 0 {
+				quantity := listing.RoundToLotSize(action.sendQuantity)
+				if quantity.GreaterThan(om.ParentOrder.GetAvailableQty()) {
+					quantity = om.ParentOrder.GetAvailableQty()
+				}
+
+				if quantity.GreaterThan(&model.Decimal64{}) {
+					price := getChildOrderPrice(om.ParentOrder.Side, om.ParentOrder.Price, lastQuote, action.aggressive)
+					err := om.SendChildOrder(om.ParentOrder.Side, quantity, price, listing.Id, listing.Market.Mic, "")
+					if err != nil {
+						om.CancelChan <- fmt.Sprintf("failed to send child order:%v", err)
+					}
+					liveChildOrderAggressive = action.aggressive
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_getPovParameters(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		json       string
+		wantParams *povParameters
+		wantErr    bool
+	}{
+		{name: "min and max default to target", json: `{"targetRatePercent":10}`,
+			wantParams: &povParameters{TargetRatePercent: 10, MinRatePercent: 10, MaxRatePercent: 10}},
+		{name: "all rates", json: `{"targetRatePercent":10,"minRatePercent":5,"maxRatePercent":20}`,
+			wantParams: &povParameters{TargetRatePercent: 10, MinRatePercent: 5, MaxRatePercent: 20}},
+		{name: "missing target", json: `{"minRatePercent":5}`, wantErr: true},
+		{name: "min above target", json: `{"targetRatePercent":10,"minRatePercent":15}`, wantErr: true},
+		{name: "max below target", json: `{"targetRatePercent":10,"maxRatePercent":5}`, wantErr: true},
+		{name: "max above 100", json: `{"targetRatePercent":10,"maxRatePercent":101}`, wantErr: true},
+		{name: "invalid json", json: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := getPovParameters(tt.json)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantParams, params)
+			}
+		})
+	}
+}
+
+func Test_povParametersSchemaIsValidJson(t *testing.T) {
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(povParametersSchema), &schema))
+}
+
+func Test_marketVolumeTracker(t *testing.T) {
+	tracker := &marketVolumeTracker{}
+
+	tracker.onQuote(&model.ClobQuote{TradedVolume: model.IasD(1000)})
+	assert.Equal(t, 0.0, tracker.volume)
+
+	tracker.onQuote(&model.ClobQuote{TradedVolume: model.IasD(1100)})
+	tracker.onQuote(&model.ClobQuote{})
+	tracker.onQuote(&model.ClobQuote{TradedVolume: model.IasD(1150)})
+	assert.Equal(t, 150.0, tracker.volume)
+
+	tracker.onQuote(&model.ClobQuote{StreamInterrupted: true})
+	tracker.onQuote(&model.ClobQuote{TradedVolume: model.IasD(2000)})
+	assert.Equal(t, 150.0, tracker.volume)
+
+	tracker.onQuote(&model.ClobQuote{TradedVolume: model.IasD(20)})
+	assert.Equal(t, 170.0, tracker.volume)
+}
+
+func Test_getChildOrderAction(t *testing.T) {
+
+	params := &povParameters{TargetRatePercent: 10, MinRatePercent: 5, MaxRatePercent: 20}
+
+	tests := []struct {
+		name                     string
+		marketVolume             float64
+		tradedQty                float64
+		exposedQty               float64
+		hasLiveChildOrder        bool
+		liveChildOrderAggressive bool
+		want                     childOrderAction
+	}{
+		{name: "no volume", want: childOrderAction{}},
+		{name: "passive below target", marketVolume: 1000, tradedQty: 80,
+			want: childOrderAction{sendQuantity: 20}},
+		{name: "aggressive below min rate", marketVolume: 1000, tradedQty: 40,
+			want: childOrderAction{sendQuantity: 60, aggressive: true}},
+		{name: "at target", marketVolume: 1000, tradedQty: 100, want: childOrderAction{}},
+		{name: "live child order within rates", marketVolume: 1000, tradedQty: 80, exposedQty: 20,
+			hasLiveChildOrder: true, want: childOrderAction{}},
+		{name: "live child order exceeds max rate", marketVolume: 1000, tradedQty: 150, exposedQty: 60,
+			hasLiveChildOrder: true, want: childOrderAction{cancelLiveChildOrders: true}},
+		{name: "passive live child order below min rate", marketVolume: 1000, tradedQty: 40, exposedQty: 60,
+			hasLiveChildOrder: true, want: childOrderAction{cancelLiveChildOrders: true}},
+		{name: "aggressive live child order below min rate", marketVolume: 1000, tradedQty: 40, exposedQty: 60,
+			hasLiveChildOrder: true, liveChildOrderAggressive: true, want: childOrderAction{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getChildOrderAction(params, tt.marketVolume, tt.tradedQty, tt.exposedQty, tt.hasLiveChildOrder,
+				tt.liveChildOrderAggressive)
+			assert.Equal(t, tt.want.cancelLiveChildOrders, got.cancelLiveChildOrders)
+			assert.Equal(t, tt.want.aggressive, got.aggressive)
+			assert.InDelta(t, tt.want.sendQuantity, got.sendQuantity, 0.000001)
+		})
+	}
+}
+
+func Test_getChildOrderPrice(t *testing.T) {
+
+	quote := &model.ClobQuote{
+		Bids:   []*model.ClobLine{{Size: model.IasD(10), Price: model.IasD(99)}},
+		Offers: []*model.ClobLine{{Size: model.IasD(10), Price: model.IasD(101)}},
+	}
+
+	assert.Equal(t, model.IasD(99), getChildOrderPrice(model.Side_BUY, model.IasD(105), quote, false))
+	assert.Equal(t, model.IasD(101), getChildOrderPrice(model.Side_BUY, model.IasD(105), quote, true))
+	assert.Equal(t, model.IasD(100), getChildOrderPrice(model.Side_BUY, model.IasD(100), quote, true))
+	assert.Equal(t, model.IasD(101), getChildOrderPrice(model.Side_SELL, model.IasD(95), quote, false))
+	assert.Equal(t, model.IasD(99), getChildOrderPrice(model.Side_SELL, model.IasD(95), quote, true))
+	assert.Equal(t, model.IasD(100), getChildOrderPrice(model.Side_SELL, model.IasD(100), quote, true))
+	assert.Equal(t, model.IasD(100), getChildOrderPrice(model.Side_SELL, model.IasD(100), &model.ClobQuote{}, true))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ettec/otp-common/api/executionvenue"
 	"github.com/ettec/otp-common/k8s"
 	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/ordermanagement"
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/ettec/otp-common/strategy"
@@ -27,6 +28,16 @@ import (
 	"strings"
 )
 
+// smartRouterService publishes the smart router parameters schema in addition to the strategy manager's execution venue
+// api.
+type smartRouterService struct {
+	*strategy.Manager
+}
+
+func (s *smartRouterService) GetExecutionParametersMetaData(context.Context, *model.Empty) (*executionvenue.ExecParamsMetaDataJson, error) {
+	return &executionvenue.ExecParamsMetaDataJson{Json: smartRouterParametersSchema}, nil
+}
+
 func main() {
 
 	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
@@ -89,7 +100,7 @@ func main() {
 	}
 
 	s := grpc.NewServer()
-	executionvenue.RegisterExecutionVenueServer(s, sm)
+	executionvenue.RegisterExecutionVenueServer(s, &smartRouterService{Manager: sm})
 	reflection.Register(s)
 
 	port := "50551"
@@ -16,6 +16,15 @@ func init() {
 	zero = &model.Decimal64{}
 }
 
+// smartRouterParametersSchema describes the smart router's execution parameters, it takes none as the execution venue
+// is selected from the best prices across the instrument's listings.
+const smartRouterParametersSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Smart router parameters",
+  "type": "object",
+  "properties": {}
+}`
+
 type GetListingsWithSameInstrument = func(ctx context.Context, listingId int32, listingGroupsIn chan<- staticdata.ListingsResult)
 
 func ExecuteAsSmartRouterStrategy(ctx context.Context, om *strategy.Strategy,
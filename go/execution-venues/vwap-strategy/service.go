@@ -9,11 +9,13 @@ import (
 	"github.com/ettec/otp-common/api/executionvenue"
 	"github.com/ettec/otp-common/bootstrap"
 	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/marketdata"
 	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/ordermanagement"
 	"github.com/ettec/otp-common/orderstore"
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/ettec/otp-common/strategy"
+	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"log"
@@ -21,11 +23,55 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 )
 
+// vwapStrategyService publishes the vwap parameters schema in addition to the strategy manager's execution venue api.
+type vwapStrategyService struct {
+	*strategy.Manager
+}
+
+func (v *vwapStrategyService) GetExecutionParametersMetaData(context.Context, *model.Empty) (*executionvenue.ExecParamsMetaDataJson, error) {
+	return &executionvenue.ExecParamsMetaDataJson{Json: vwapParametersSchema}, nil
+}
+
+// getRecordedListingIds returns the listings whose volume is recorded from start up, these are the listings in the
+// VWAP_VOLUME_LISTING_IDS environment variable and the listings with volume recorded within the profile days.
+func getRecordedListingIds(ctx context.Context, store *sqlVolumeStore, profileDays int) ([]int32, error) {
+	listingIds, err := parseListingIds(bootstrap.GetOptionalEnvVar("VWAP_VOLUME_LISTING_IDS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse VWAP_VOLUME_LISTING_IDS: %w", err)
+	}
+
+	fromDate := time.Now().UTC().Truncate(secondsPerDay*time.Second).AddDate(0, 0, -profileDays)
+	recentListingIds, err := store.getRecordedListingIds(ctx, fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listings with recent volume: %w", err)
+	}
+
+	return append(listingIds, recentListingIds...), nil
+}
+
+func parseListingIds(value string) ([]int32, error) {
+	var result []int32
+	for _, id := range strings.Split(value, ",") {
+		if strings.TrimSpace(id) == "" {
+			continue
+		}
+
+		listingId, err := strconv.ParseInt(strings.TrimSpace(id), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid listing id %q: %w", id, err)
+		}
+		result = append(result, int32(listingId))
+	}
+
+	return result, nil
+}
+
 func main() {
 
 	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
@@ -33,6 +79,11 @@ func main() {
 	id := bootstrap.GetEnvVar("ID")
 	maxConnectRetry := time.Duration(bootstrap.GetOptionalIntEnvVar("MAX_CONNECT_RETRY_SECONDS", 60)) * time.Second
 	kafkaBrokersString := bootstrap.GetEnvVar("KAFKA_BROKERS")
+	dbDriverName := bootstrap.GetEnvVar("DB_DRIVER_NAME")
+	dbConnString := bootstrap.GetEnvVar("DB_CONN_STRING")
+	profileIntervalSecs := int64(bootstrap.GetOptionalIntEnvVar("VWAP_VOLUME_PROFILE_INTERVAL_SECS", 300))
+	profileDays := bootstrap.GetOptionalIntEnvVar("VWAP_VOLUME_PROFILE_DAYS", 20)
+	volumeFlushInterval := time.Duration(bootstrap.GetOptionalIntEnvVar("VWAP_VOLUME_FLUSH_INTERVAL_SECS", 10)) * time.Second
 
 	slog.Info("Starting vwap strategy")
 
@@ -46,6 +97,44 @@ func main() {
 		log.Panicf("failed to create static data source:%v", err)
 	}
 
+	volumeStore, err := newSqlVolumeStore(dbDriverName, dbConnString)
+	if err != nil {
+		log.Panicf("failed to create volume store:%v", err)
+	}
+	defer func() {
+		if err := volumeStore.Close(); err != nil {
+			slog.Error("failed to close volume store", "error", err)
+		}
+	}()
+
+	mdsAddress, err := k8s.GetServiceAddress("market-data-service")
+	if err != nil {
+		log.Panicf("failed to get market data service address: %v", err)
+	}
+
+	mdsQuoteStream, err := marketdata.NewQuoteStreamFromMarketDataService(ctx, id, mdsAddress, maxConnectRetry,
+		bootstrap.GetOptionalIntEnvVar("VWAPSTRATEGY_INBOUND_QUOTE_BUFFER_SIZE", 1000))
+	if err != nil {
+		log.Panicf("failed to create quote stream from market data service: %v", err)
+	}
+
+	recordedListingIds, err := getRecordedListingIds(ctx, volumeStore, profileDays)
+	if err != nil {
+		log.Panicf("failed to get listings to record volume for: %v", err)
+	}
+
+	volumeRecorder, err := newVolumeRecorder(ctx, volumeStore, mdsQuoteStream, id, profileIntervalSecs,
+		volumeFlushInterval, recordedListingIds)
+	if err != nil {
+		log.Panicf("failed to create volume recorder: %v", err)
+	}
+
+	profileSource := &volumeProfileSource{
+		store:        volumeStore,
+		intervalSecs: profileIntervalSecs,
+		days:         profileDays,
+	}
+
 	clientSet := k8s.GetK8sClientSet(false)
 
 	orderRouter, err := api.GetOrderRouter(clientSet, maxConnectRetry)
@@ -95,7 +184,16 @@ func main() {
 			om.CancelChan <- "num Buckets must be less than or equal to the quantity"
 		}
 
-		buckets, err := getBucketsFromParamsString(vwapParamsJson, *quantity, listingResult.Listing)
+		volumeRecorder.record(om.ParentOrder.ListingId)
+
+		profile, err := profileSource.getVolumeProfile(ctx, om.ParentOrder.ListingId)
+		if err != nil {
+			om.Log.Error("failed to get volume profile, using a TWAP profile", "error", err)
+		} else if profile == nil {
+			om.Log.Info("no volume history for listing, using a TWAP profile")
+		}
+
+		buckets, err := getBucketsFromParamsString(vwapParamsJson, *quantity, listingResult.Listing, profile)
 		if err != nil {
 			om.CancelChan <- fmt.Sprintf("failed to get Buckets from params:%v", err)
 		}
@@ -125,7 +223,7 @@ func main() {
 	}
 
 	s := grpc.NewServer()
-	executionvenue.RegisterExecutionVenueServer(s, sm)
+	executionvenue.RegisterExecutionVenueServer(s, &vwapStrategyService{Manager: sm})
 
 	reflection.Register(s)
 
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// volumeProfile is the average traded volume of a listing for each interval of the day, the first interval starts at
+// UTC midnight.
+type volumeProfile struct {
+	intervalSecs int64
+	volumes      []float64
+}
+
+// volumeBetween returns the profile volume between the given times, the volume of an interval is assumed to be evenly
+// distributed across the interval.
+func (p *volumeProfile) volumeBetween(utcStartTimeSecs int64, utcEndTimeSecs int64) float64 {
+	volume := 0.0
+	for t := utcStartTimeSecs; t < utcEndTimeSecs; {
+		secondOfDay := (t%secondsPerDay + secondsPerDay) % secondsPerDay
+		intervalIdx := secondOfDay / p.intervalSecs
+		intervalEnd := t + p.intervalSecs - secondOfDay%p.intervalSecs
+		segmentEnd := min(intervalEnd, utcEndTimeSecs)
+
+		if intervalIdx < int64(len(p.volumes)) {
+			volume += p.volumes[intervalIdx] * float64(segmentEnd-t) / float64(p.intervalSecs)
+		}
+
+		t = segmentEnd
+	}
+
+	return volume
+}
+
+type volumeStore interface {
+	setIntervalVolumes(ctx context.Context, source string, volumes []intervalVolume) error
+	getVolumeProfile(ctx context.Context, listingId int32, intervalSecs int64, fromDate time.Time,
+		toDate time.Time) (*volumeProfile, error)
+}
+
+type intervalVolume struct {
+	listingId    int32
+	tradeDate    time.Time
+	intervalSecs int64
+	intervalIdx  int
+	volume       float64
+}
+
+type intervalKey struct {
+	listingId   int32
+	tradeDate   time.Time
+	intervalIdx int
+}
+
+// volumeRecorder records the traded volume of each subscribed listing per intraday interval from the increments in
+// the quote's traded volume.  The recorder's total volume for each interval of the current day is stored under the
+// recorder's source id, so that each replica of the strategy records its own observation of the interval volumes.
+type volumeRecorder struct {
+	store        volumeStore
+	quoteStream  marketdata.QuoteStream
+	source       string
+	intervalSecs int64
+
+	subscriptionsMutex sync.Mutex
+	subscribed         map[int32]bool
+
+	lastTradedVolume map[int32]float64
+	tradeDate        time.Time
+	intervalVolumes  map[intervalKey]float64
+	unflushed        map[intervalKey]bool
+}
+
+// newVolumeRecorder creates a recorder that records the volume of the given listings from the time it is created, the
+// volume of other listings is recorded once record is called for the listing.
+func newVolumeRecorder(ctx context.Context, store volumeStore, quoteStream marketdata.QuoteStream, source string,
+	intervalSecs int64, flushInterval time.Duration, listingIds []int32) (*volumeRecorder, error) {
+
+	if intervalSecs <= 0 || secondsPerDay%intervalSecs != 0 {
+		return nil, fmt.Errorf("the volume profile interval must be a divisor of a day in seconds, interval: %v",
+			intervalSecs)
+	}
+
+	r := &volumeRecorder{
+		store:            store,
+		quoteStream:      quoteStream,
+		source:           source,
+		intervalSecs:     intervalSecs,
+		subscribed:       map[int32]bool{},
+		lastTradedVolume: map[int32]float64{},
+		intervalVolumes:  map[intervalKey]float64{},
+		unflushed:        map[intervalKey]bool{},
+	}
+
+	for _, listingId := range listingIds {
+		r.record(listingId)
+	}
+
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				r.flush(context.Background())
+				return
+			case quote, ok := <-quoteStream.Chan():
+				if !ok {
+					slog.Error("volume recorder quote stream closed")
+					r.flush(context.Background())
+					return
+				}
+				r.onQuote(quote, time.Now())
+			case <-ticker.C:
+				r.flush(ctx)
+			}
+		}
+	}()
+
+	return r, nil
+}
+
+// record starts recording the traded volume of the listing if it is not already being recorded.
+func (r *volumeRecorder) record(listingId int32) {
+	r.subscriptionsMutex.Lock()
+	defer r.subscriptionsMutex.Unlock()
+
+	if r.subscribed[listingId] {
+		return
+	}
+
+	if err := r.quoteStream.Subscribe(listingId); err != nil {
+		slog.Error("failed to subscribe to quotes to record traded volume", "listingId", listingId, "error", err)
+		return
+	}
+
+	r.subscribed[listingId] = true
+}
+
+func (r *volumeRecorder) onQuote(quote *model.ClobQuote, now time.Time) {
+	if quote.StreamInterrupted {
+		// The volume traded while the stream was interrupted cannot be attributed to an interval
+		delete(r.lastTradedVolume, quote.ListingId)
+		return
+	}
+
+	if quote.TradedVolume == nil {
+		return
+	}
+
+	tradedVolume := quote.TradedVolume.ToFloat()
+	lastTradedVolume, ok := r.lastTradedVolume[quote.ListingId]
+	r.lastTradedVolume[quote.ListingId] = tradedVolume
+
+	if !ok {
+		return
+	}
+
+	delta := tradedVolume - lastTradedVolume
+	if delta < 0 {
+		// The traded volume is reset at the start of each trading day
+		delta = tradedVolume
+	}
+
+	if delta == 0 {
+		return
+	}
+
+	utcNow := now.UTC()
+	key := intervalKey{
+		listingId:   quote.ListingId,
+		tradeDate:   utcNow.Truncate(secondsPerDay * time.Second),
+		intervalIdx: int((utcNow.Unix() % secondsPerDay) / r.intervalSecs),
+	}
+
+	if key.tradeDate.After(r.tradeDate) {
+		r.tradeDate = key.tradeDate
+	}
+
+	r.intervalVolumes[key] += delta
+	r.unflushed[key] = true
+}
+
+// flush stores the total volume of the intervals whose volume has changed since the last flush, once stored the
+// volumes of previous days are discarded.
+func (r *volumeRecorder) flush(ctx context.Context) {
+	if len(r.unflushed) == 0 {
+		return
+	}
+
+	volumes := make([]intervalVolume, 0, len(r.unflushed))
+	for key := range r.unflushed {
+		volumes = append(volumes, intervalVolume{
+			listingId:    key.listingId,
+			tradeDate:    key.tradeDate,
+			intervalSecs: r.intervalSecs,
+			intervalIdx:  key.intervalIdx,
+			volume:       r.intervalVolumes[key],
+		})
+	}
+
+	if err := r.store.setIntervalVolumes(ctx, r.source, volumes); err != nil {
+		slog.Error("failed to store interval volumes, will retry on next flush", "error", err)
+		return
+	}
+
+	r.unflushed = map[intervalKey]bool{}
+	for key := range r.intervalVolumes {
+		if key.tradeDate.Before(r.tradeDate) {
+			delete(r.intervalVolumes, key)
+		}
+	}
+}
+
+type volumeProfileSource struct {
+	store        volumeStore
+	intervalSecs int64
+	days         int
+}
+
+// getVolumeProfile returns the listing's average intraday volume profile over the configured number of days prior
+// to today, or nil if there is no volume history for the listing.
+func (v *volumeProfileSource) getVolumeProfile(ctx context.Context, listingId int32) (*volumeProfile, error) {
+	today := time.Now().UTC().Truncate(secondsPerDay * time.Second)
+	fromDate := today.AddDate(0, 0, -v.days)
+
+	profile, err := v.store.getVolumeProfile(ctx, listingId, v.intervalSecs, fromDate, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume profile for listing %v: %w", listingId, err)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type sqlVolumeStore struct {
+	db *sql.DB
+}
+
+func newSqlVolumeStore(driverName string, dbConnString string) (*sqlVolumeStore, error) {
+	db, err := sql.Open(driverName, dbConnString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &sqlVolumeStore{db: db}, nil
+}
+
+// setIntervalVolumes stores the volume traded in each interval as observed by the given source.  The volumes are the
+// source's total for the interval so a write can be repeated, and a stored volume is never reduced, so that a source
+// that restarts part way through an interval does not replace the volume it recorded before the restart.
+func (s *sqlVolumeStore) setIntervalVolumes(ctx context.Context, source string, volumes []intervalVolume) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	for _, v := range volumes {
+		_, err := tx.ExecContext(ctx, `INSERT INTO marketdata.intraday_volume
+			(listing_id, trade_date, interval_secs, interval_index, source, volume) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (listing_id, trade_date, interval_secs, interval_index, source)
+			DO UPDATE SET volume = GREATEST(marketdata.intraday_volume.volume, EXCLUDED.volume)`,
+			v.listingId, v.tradeDate, v.intervalSecs, v.intervalIdx, source, v.volume)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to set interval volume: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit interval volumes: %w", err)
+	}
+
+	return nil
+}
+
+// getRecordedListingIds returns the ids of the listings with volume recorded on or after the given date.
+func (s *sqlVolumeStore) getRecordedListingIds(ctx context.Context, fromDate time.Time) ([]int32, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT listing_id FROM marketdata.intraday_volume
+		WHERE trade_date >= $1`, fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recorded listing ids: %w", err)
+	}
+	defer rows.Close()
+
+	var listingIds []int32
+	for rows.Next() {
+		var listingId int32
+		if err := rows.Scan(&listingId); err != nil {
+			return nil, fmt.Errorf("failed to scan listing id: %w", err)
+		}
+		listingIds = append(listingIds, listingId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recorded listing ids: %w", err)
+	}
+
+	return listingIds, nil
+}
+
+func (s *sqlVolumeStore) getVolumeProfile(ctx context.Context, listingId int32, intervalSecs int64, fromDate time.Time,
+	toDate time.Time) (*volumeProfile, error) {
+
+	var days int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(DISTINCT trade_date) FROM marketdata.intraday_volume
+		WHERE listing_id = $1 AND interval_secs = $2 AND trade_date >= $3 AND trade_date < $4`,
+		listingId, intervalSecs, fromDate, toDate).Scan(&days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count trade dates: %w", err)
+	}
+
+	if days == 0 {
+		return nil, nil
+	}
+
+	// Every source observes the same market volume, the largest volume recorded for an interval is used as a source
+	// that started recording part way through the interval records less than the interval's volume
+	rows, err := s.db.QueryContext(ctx, `SELECT interval_index, SUM(volume) FROM
+		(SELECT trade_date, interval_index, MAX(volume) AS volume FROM marketdata.intraday_volume
+		WHERE listing_id = $1 AND interval_secs = $2 AND trade_date >= $3 AND trade_date < $4
+		GROUP BY trade_date, interval_index) AS interval_volume
+		GROUP BY interval_index`, listingId, intervalSecs, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interval volumes: %w", err)
+	}
+	defer rows.Close()
+
+	profile := &volumeProfile{
+		intervalSecs: intervalSecs,
+		volumes:      make([]float64, secondsPerDay/intervalSecs),
+	}
+
+	for rows.Next() {
+		var intervalIdx int
+		var volume float64
+		if err := rows.Scan(&intervalIdx, &volume); err != nil {
+			return nil, fmt.Errorf("failed to scan interval volume: %w", err)
+		}
+
+		if intervalIdx >= 0 && intervalIdx < len(profile.volumes) {
+			profile.volumes[intervalIdx] = volume / float64(days)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read interval volumes: %w", err)
+	}
+
+	return profile, nil
+}
+
+func (s *sqlVolumeStore) Close() error {
+	return s.db.Close()
+}
@@ -15,6 +15,31 @@ type vwapParameters struct {
 	Buckets          int   `json:"buckets"`
 }
 
+const vwapParametersSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "VWAP parameters",
+  "type": "object",
+  "properties": {
+    "utcStartTimeSecs": {
+      "type": "integer",
+      "description": "The start time of the order in seconds since the unix epoch",
+      "exclusiveMinimum": 0
+    },
+    "utcEndTimeSecs": {
+      "type": "integer",
+      "description": "The end time of the order in seconds since the unix epoch, must be after the start time",
+      "exclusiveMinimum": 0
+    },
+    "buckets": {
+      "type": "integer",
+      "description": "The number of time buckets the order is split into, must not exceed the order quantity",
+      "minimum": 1,
+      "default": 10
+    }
+  },
+  "required": ["utcStartTimeSecs", "utcEndTimeSecs"]
+}`
+
 func executeAsVwapStrategy(ctx context.Context, om *strategy.Strategy, buckets []bucket, listing *model.Listing) {
 
 	go func() {
@@ -92,7 +117,8 @@ func executeAsVwapStrategy(ctx context.Context, om *strategy.Strategy, buckets [
 	}()
 }
 
-func getBucketsFromParamsString(vwapParamsJson string, quantity model.Decimal64, listing *model.Listing) ([]bucket, error) {
+func getBucketsFromParamsString(vwapParamsJson string, quantity model.Decimal64, listing *model.Listing,
+	profile *volumeProfile) ([]bucket, error) {
 	vwapParameters := &vwapParameters{}
 	err := json.Unmarshal([]byte(vwapParamsJson), vwapParameters)
 	if err != nil {
@@ -104,7 +130,8 @@ func getBucketsFromParamsString(vwapParamsJson string, quantity model.Decimal64,
 		numBuckets = 10
 	}
 
-	buckets := getBuckets(listing, vwapParameters.UtcStartTimeSecs, vwapParameters.UtcEndTimeSecs, numBuckets, quantity)
+	buckets := getBuckets(listing, vwapParameters.UtcStartTimeSecs, vwapParameters.UtcEndTimeSecs, numBuckets, quantity,
+		profile)
 	return buckets, nil
 }
 
@@ -114,20 +141,38 @@ type bucket struct {
 	utcEndTimeSecs   int64
 }
 
-func getBuckets(listing *model.Listing, utcStartTimeSecs int64, utcEndTimeSecs int64, buckets int, quantity model.Decimal64) (result []bucket) {
-	// need historical traded volume data, for now use a TWAP profile
+// getBuckets splits the quantity into buckets in proportion to the volume profile's volume within each bucket, if there
+// is no profile or the profile has no volume within the order's time interval a TWAP profile is used.
+func getBuckets(listing *model.Listing, utcStartTimeSecs int64, utcEndTimeSecs int64, buckets int, quantity model.Decimal64,
+	profile *volumeProfile) (result []bucket) {
 	bucketInterval := (utcEndTimeSecs - utcStartTimeSecs) / int64(buckets)
 
-	fBuckets := float64(buckets)
+	weights := make([]float64, buckets)
+	totalWeight := 0.0
+	if profile != nil {
+		startTime := utcStartTimeSecs
+		for i := 0; i < buckets; i++ {
+			weights[i] = profile.volumeBetween(startTime, startTime+bucketInterval)
+			totalWeight += weights[i]
+			startTime += bucketInterval
+		}
+	}
+
+	if totalWeight == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		totalWeight = float64(buckets)
+	}
+
 	fQuantity := quantity.ToFloat()
-	bucketQnt := fQuantity / fBuckets
 
 	startTime := utcStartTimeSecs
 	endTime := startTime + bucketInterval
 
 	for i := 0; i < buckets; i++ {
 		bucket := bucket{
-			quantity:         *listing.RoundToLotSize(bucketQnt),
+			quantity:         *listing.RoundToLotSize(fQuantity * weights[i] / totalWeight),
 			utcStartTimeSecs: startTime,
 			utcEndTimeSecs:   endTime,
 		}
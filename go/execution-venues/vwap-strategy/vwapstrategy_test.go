@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/ettec/otp-common/model"
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_paramsUnmarshal(t *testing.T) {
@@ -128,9 +130,196 @@ func Test_getBuckets(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotResult := getBuckets(tt.args.listing, tt.args.utcStartTimeSecs, tt.args.utcEndTimeSecs, tt.args.buckets, tt.args.quantity); !reflect.DeepEqual(gotResult, tt.wantResult) {
+			if gotResult := getBuckets(tt.args.listing, tt.args.utcStartTimeSecs, tt.args.utcEndTimeSecs, tt.args.buckets, tt.args.quantity, nil); !reflect.DeepEqual(gotResult, tt.wantResult) {
 				t.Errorf("getBuckets() = %v, want %v", gotResult, tt.wantResult)
 			}
 		})
 	}
 }
+
+func Test_getBucketsFromVolumeProfile(t *testing.T) {
+
+	testListing := &model.Listing{}
+
+	profile := &volumeProfile{
+		intervalSecs: 3600,
+		volumes:      make([]float64, 24),
+	}
+	profile.volumes[9] = 100
+	profile.volumes[10] = 300
+
+	dayStart := int64(1598659200)
+
+	gotResult := getBuckets(testListing, dayStart+9*3600, dayStart+11*3600, 4, *model.IasD(120), profile)
+
+	wantResult := []bucket{
+		{quantity: *model.IasD(15), utcStartTimeSecs: dayStart + 9*3600, utcEndTimeSecs: dayStart + 9*3600 + 1800},
+		{quantity: *model.IasD(15), utcStartTimeSecs: dayStart + 9*3600 + 1800, utcEndTimeSecs: dayStart + 10*3600},
+		{quantity: *model.IasD(45), utcStartTimeSecs: dayStart + 10*3600, utcEndTimeSecs: dayStart + 10*3600 + 1800},
+		{quantity: *model.IasD(45), utcStartTimeSecs: dayStart + 10*3600 + 1800, utcEndTimeSecs: dayStart + 11*3600},
+	}
+
+	assert.Equal(t, wantResult, gotResult)
+}
+
+func Test_getBucketsFallsBackToTwapWhenProfileHasNoVolume(t *testing.T) {
+
+	testListing := &model.Listing{}
+
+	profile := &volumeProfile{
+		intervalSecs: 3600,
+		volumes:      make([]float64, 24),
+	}
+
+	assert.Equal(t, getBuckets(testListing, 0, 15, 5, *model.IasD(10), nil),
+		getBuckets(testListing, 0, 15, 5, *model.IasD(10), profile))
+}
+
+func Test_volumeProfileVolumeBetween(t *testing.T) {
+
+	profile := &volumeProfile{
+		intervalSecs: 300,
+		volumes:      make([]float64, secondsPerDay/300),
+	}
+	profile.volumes[0] = 30
+	profile.volumes[1] = 60
+	profile.volumes[len(profile.volumes)-1] = 90
+
+	assert.InDelta(t, 15.0, profile.volumeBetween(150, 300), 0.0001)
+	assert.InDelta(t, 45.0, profile.volumeBetween(150, 450), 0.0001)
+	assert.InDelta(t, 120.0, profile.volumeBetween(-300, 300), 0.0001)
+	assert.InDelta(t, 120.0, profile.volumeBetween(secondsPerDay-300, secondsPerDay+300), 0.0001)
+}
+
+type testVolumeStore struct {
+	source  string
+	volumes []intervalVolume
+}
+
+func (t *testVolumeStore) setIntervalVolumes(_ context.Context, source string, volumes []intervalVolume) error {
+	t.source = source
+	t.volumes = append(t.volumes, volumes...)
+	return nil
+}
+
+func (t *testVolumeStore) getVolumeProfile(context.Context, int32, int64, time.Time, time.Time) (*volumeProfile, error) {
+	return nil, nil
+}
+
+func newTestVolumeRecorder(store volumeStore) *volumeRecorder {
+	return &volumeRecorder{
+		store:            store,
+		source:           "vwap-strategy-0",
+		intervalSecs:     300,
+		lastTradedVolume: map[int32]float64{},
+		intervalVolumes:  map[intervalKey]float64{},
+		unflushed:        map[intervalKey]bool{},
+	}
+}
+
+func Test_volumeRecorderRecordsTradedVolumeDeltasPerInterval(t *testing.T) {
+
+	store := &testVolumeStore{}
+	recorder := newTestVolumeRecorder(store)
+
+	day := time.Date(2020, 8, 29, 0, 0, 0, 0, time.UTC)
+
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1000)}, day.Add(10*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1010)}, day.Add(20*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1030)}, day.Add(310*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, StreamInterrupted: true}, day.Add(320*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1500)}, day.Add(330*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1505)}, day.Add(340*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 2, TradedVolume: model.IasD(7)}, day.Add(340*time.Second))
+
+	recorder.flush(context.Background())
+
+	assert.Equal(t, "vwap-strategy-0", store.source)
+	assert.ElementsMatch(t, []intervalVolume{
+		{listingId: 1, tradeDate: day, intervalSecs: 300, intervalIdx: 0, volume: 10},
+		{listingId: 1, tradeDate: day, intervalSecs: 300, intervalIdx: 1, volume: 25},
+	}, store.volumes)
+	assert.Empty(t, recorder.unflushed)
+}
+
+func Test_volumeRecorderStoresTheIntervalTotalOfChangedIntervals(t *testing.T) {
+
+	store := &testVolumeStore{}
+	recorder := newTestVolumeRecorder(store)
+
+	day := time.Date(2020, 8, 29, 0, 0, 0, 0, time.UTC)
+
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1000)}, day.Add(10*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1010)}, day.Add(20*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1020)}, day.Add(310*time.Second))
+	recorder.flush(context.Background())
+
+	store.volumes = nil
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1025)}, day.Add(320*time.Second))
+	recorder.flush(context.Background())
+
+	assert.Equal(t, []intervalVolume{
+		{listingId: 1, tradeDate: day, intervalSecs: 300, intervalIdx: 1, volume: 15},
+	}, store.volumes)
+
+	store.volumes = nil
+	recorder.flush(context.Background())
+	assert.Empty(t, store.volumes)
+}
+
+func Test_volumeRecorderDiscardsPreviousDaysOnceStored(t *testing.T) {
+
+	store := &testVolumeStore{}
+	recorder := newTestVolumeRecorder(store)
+
+	day := time.Date(2020, 8, 29, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1000)}, day.Add(10*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(1010)}, day.Add(20*time.Second))
+	recorder.onQuote(&model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(5)}, nextDay.Add(20*time.Second))
+	recorder.flush(context.Background())
+
+	assert.Equal(t, map[intervalKey]float64{{listingId: 1, tradeDate: nextDay, intervalIdx: 0}: 5},
+		recorder.intervalVolumes)
+}
+
+type testRecordingQuoteStream struct {
+	subscribed []int32
+}
+
+func (t *testRecordingQuoteStream) Subscribe(listingId int32) error {
+	t.subscribed = append(t.subscribed, listingId)
+	return nil
+}
+
+func (t *testRecordingQuoteStream) Chan() <-chan *model.ClobQuote {
+	return nil
+}
+
+func (t *testRecordingQuoteStream) Close() {
+}
+
+func Test_volumeRecorderRecordsGivenListingsFromCreation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	quoteStream := &testRecordingQuoteStream{}
+	recorder, err := newVolumeRecorder(ctx, &testVolumeStore{}, quoteStream, "vwap-strategy-0", 300, time.Minute,
+		[]int32{1, 2, 1})
+	assert.NoError(t, err)
+
+	recorder.record(2)
+	recorder.record(3)
+
+	assert.Equal(t, []int32{1, 2, 3}, quoteStream.subscribed)
+}
+
+func Test_parseListingIds(t *testing.T) {
+	listingIds, err := parseListingIds(" 1,2, ,3")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, listingIds)
+
+	_, err = parseListingIds("1,x")
+	assert.Error(t, err)
+}
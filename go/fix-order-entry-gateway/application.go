@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"log/slog"
+)
+
+// orderEntryApplication is the quickfix application of the client sessions, NewOrderSingle, OrderCancelRequest and
+// OrderCancelReplaceRequest messages are routed to the gateway and other application messages are rejected as
+// unsupported.
+type orderEntryApplication struct {
+	inboundRouter *quickfix.MessageRouter
+}
+
+func newOrderEntryApplication(gateway *orderEntryGateway) *orderEntryApplication {
+	router := quickfix.NewMessageRouter()
+
+	for _, fixVersion := range []string{enum.BeginStringFIX44, string(enum.ApplVerID_FIX50SP2)} {
+		router.AddRoute(fixVersion, string(enum.MsgType_ORDER_SINGLE), gateway.onNewOrderSingle)
+		router.AddRoute(fixVersion, string(enum.MsgType_ORDER_CANCEL_REQUEST), gateway.onOrderCancelRequest)
+		router.AddRoute(fixVersion, string(enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST), gateway.onOrderCancelReplaceRequest)
+	}
+
+	return &orderEntryApplication{inboundRouter: router}
+}
+
+func logSessionMsg(sessionID quickfix.SessionID, msg string) {
+	slog.Info(msg, "sessionID", sessionID.String())
+}
+
+// Notification of a session begin created.
+func (a *orderEntryApplication) OnCreate(sessionID quickfix.SessionID) {
+	logSessionMsg(sessionID, "created")
+}
+
+// Notification of a session successfully logging on.
+func (a *orderEntryApplication) OnLogon(sessionID quickfix.SessionID) {
+	logSessionMsg(sessionID, "logon received")
+}
+
+// Notification of a session logging off or disconnecting.
+func (a *orderEntryApplication) OnLogout(sessionID quickfix.SessionID) {
+	logSessionMsg(sessionID, "logout received")
+}
+
+// Notification of admin message being sent to target.
+func (a *orderEntryApplication) ToAdmin(message *quickfix.Message, sessionID quickfix.SessionID) {
+}
+
+// Notification of app message being sent to target.
+func (a *orderEntryApplication) ToApp(message *quickfix.Message, sessionID quickfix.SessionID) error {
+	return nil
+}
+
+// Notification of admin message being received from target.
+func (a *orderEntryApplication) FromAdmin(message *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+
+// Notification of app message being received from target.
+func (a *orderEntryApplication) FromApp(message *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	return a.inboundRouter.Route(message, sessionID)
+}
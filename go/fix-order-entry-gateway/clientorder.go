@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+)
+
+// pendingRequest is a cancel or cancel/replace request that has been sent to the order router and is awaiting
+// confirmation by an order update.
+type pendingRequest struct {
+	clOrdId     string
+	origClOrdId string
+	isCancel    bool
+}
+
+// clientOrder is an order created by a client session.
+type clientOrder struct {
+	sessionID quickfix.SessionID
+	clOrdId   string
+	order     *model.Order
+	pending   *pendingRequest
+}
+
+// onUpdate applies an update of the order and returns the execution reports and any cancel reject to be sent to the
+// client.  An accepted order is reported as new, a change to the quantity or price of a live order as replaced if the
+// client requested it and restated otherwise, each new execution as a trade and a cancellation as cancelled.  An order
+// that is cancelled with an error before it is accepted is reported as rejected.  A pending request is rejected if the
+// order is done before the request is confirmed or if the update reports an error.
+func (c *clientOrder) onUpdate(order *model.Order) ([]executionReport, *cancelReject) {
+	previous := c.order
+	c.order = order
+
+	previousStatus := model.OrderStatus_NONE
+	var previousLastExecId, previousErrorMsg string
+	if previous != nil {
+		previousStatus = previous.Status
+		previousLastExecId = previous.LastExecId
+		previousErrorMsg = previous.ErrorMessage
+	}
+
+	pending := c.pending
+	resolved := false
+
+	var reports []executionReport
+
+	if previousStatus == model.OrderStatus_NONE &&
+		(order.Status == model.OrderStatus_LIVE || order.Status == model.OrderStatus_FILLED) {
+		reports = append(reports, executionReport{execType: enum.ExecType_NEW, clOrdId: c.clOrdId})
+	}
+
+	if previous != nil && previousStatus == model.OrderStatus_LIVE && order.Status == model.OrderStatus_LIVE &&
+		(!decimalsEqual(previous.Quantity, order.Quantity) || !decimalsEqual(previous.Price, order.Price)) {
+		if pending != nil && !pending.isCancel {
+			reports = append(reports, executionReport{execType: enum.ExecType_REPLACED, clOrdId: pending.clOrdId,
+				origClOrdId: c.clOrdId})
+			c.clOrdId = pending.clOrdId
+			resolved = true
+		} else {
+			reports = append(reports, executionReport{execType: enum.ExecType_RESTATED, clOrdId: c.clOrdId})
+		}
+	}
+
+	if order.LastExecId != "" && order.LastExecId != previousLastExecId {
+		reports = append(reports, executionReport{execType: enum.ExecType_TRADE, clOrdId: c.clOrdId})
+	}
+
+	if order.Status == model.OrderStatus_CANCELLED && previousStatus != model.OrderStatus_CANCELLED {
+		switch {
+		case pending != nil && pending.isCancel:
+			reports = append(reports, executionReport{execType: enum.ExecType_CANCELED, clOrdId: pending.clOrdId,
+				origClOrdId: c.clOrdId})
+			c.clOrdId = pending.clOrdId
+			resolved = true
+		case previousStatus == model.OrderStatus_NONE && order.ErrorMessage != "":
+			reports = append(reports, executionReport{execType: enum.ExecType_REJECTED, clOrdId: c.clOrdId})
+		default:
+			reports = append(reports, executionReport{execType: enum.ExecType_CANCELED, clOrdId: c.clOrdId})
+		}
+	}
+
+	var reject *cancelReject
+	if pending != nil && !resolved {
+		if order.IsTerminalState() {
+			reject = &cancelReject{request: pending, reason: enum.CxlRejReason_TOO_LATE_TO_CANCEL,
+				errorMsg: "the order is done"}
+		} else if order.ErrorMessage != "" && order.ErrorMessage != previousErrorMsg {
+			reject = &cancelReject{request: pending, reason: enum.CxlRejReason_OTHER, errorMsg: order.ErrorMessage}
+		}
+	}
+
+	if resolved || reject != nil {
+		c.pending = nil
+	}
+
+	return reports, reject
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestOrder(status model.OrderStatus, qty int64, price int64, lastExecId string, errorMsg string) *model.Order {
+	return &model.Order{Id: "o1", Status: status, Quantity: &model.Decimal64{Mantissa: qty},
+		Price: &model.Decimal64{Mantissa: price}, LastExecId: lastExecId, ErrorMessage: errorMsg}
+}
+
+func TestClientOrderOnUpdate(t *testing.T) {
+
+	replace := &pendingRequest{clOrdId: "c2", origClOrdId: "c1"}
+	cancel := &pendingRequest{clOrdId: "c2", origClOrdId: "c1", isCancel: true}
+
+	tests := []struct {
+		name         string
+		previous     *model.Order
+		pending      *pendingRequest
+		current      *model.Order
+		reports      []executionReport
+		reject       *cancelReject
+		clOrdId      string
+		stillPending bool
+	}{
+		{name: "pending new", current: newTestOrder(model.OrderStatus_NONE, 10, 100, "", ""), clOrdId: "c1"},
+		{name: "new", current: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			reports: []executionReport{{execType: enum.ExecType_NEW, clOrdId: "c1"}}, clOrdId: "c1"},
+		{name: "rejected", previous: newTestOrder(model.OrderStatus_NONE, 10, 100, "", ""),
+			current: newTestOrder(model.OrderStatus_CANCELLED, 10, 100, "", "invalid listing"),
+			reports: []executionReport{{execType: enum.ExecType_REJECTED, clOrdId: "c1"}}, clOrdId: "c1"},
+		{name: "replaced", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""), pending: replace,
+			current: newTestOrder(model.OrderStatus_LIVE, 20, 100, "", ""),
+			reports: []executionReport{{execType: enum.ExecType_REPLACED, clOrdId: "c2", origClOrdId: "c1"}},
+			clOrdId: "c2"},
+		{name: "restated", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			current: newTestOrder(model.OrderStatus_LIVE, 10, 101, "", ""),
+			reports: []executionReport{{execType: enum.ExecType_RESTATED, clOrdId: "c1"}}, clOrdId: "c1"},
+		{name: "trade", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			current: newTestOrder(model.OrderStatus_LIVE, 10, 100, "e1", ""),
+			reports: []executionReport{{execType: enum.ExecType_TRADE, clOrdId: "c1"}}, clOrdId: "c1"},
+		{name: "trade with pending replace", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			pending: replace, current: newTestOrder(model.OrderStatus_LIVE, 10, 100, "e1", ""),
+			reports: []executionReport{{execType: enum.ExecType_TRADE, clOrdId: "c1"}}, clOrdId: "c1",
+			stillPending: true},
+		{name: "cancelled on request", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			pending: cancel, current: newTestOrder(model.OrderStatus_CANCELLED, 10, 100, "", ""),
+			reports: []executionReport{{execType: enum.ExecType_CANCELED, clOrdId: "c2", origClOrdId: "c1"}},
+			clOrdId: "c2"},
+		{name: "unsolicited cancel", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			current: newTestOrder(model.OrderStatus_CANCELLED, 10, 100, "", ""),
+			reports: []executionReport{{execType: enum.ExecType_CANCELED, clOrdId: "c1"}}, clOrdId: "c1"},
+		{name: "filled before cancel", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			pending: cancel, current: newTestOrder(model.OrderStatus_FILLED, 10, 100, "e1", ""),
+			reports: []executionReport{{execType: enum.ExecType_TRADE, clOrdId: "c1"}},
+			reject: &cancelReject{request: cancel, reason: enum.CxlRejReason_TOO_LATE_TO_CANCEL,
+				errorMsg: "the order is done"}, clOrdId: "c1"},
+		{name: "replace rejected", previous: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", ""),
+			pending: replace, current: newTestOrder(model.OrderStatus_LIVE, 10, 100, "", "price out of range"),
+			reject:  &cancelReject{request: replace, reason: enum.CxlRejReason_OTHER, errorMsg: "price out of range"},
+			clOrdId: "c1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			co := &clientOrder{clOrdId: "c1", order: test.previous, pending: test.pending}
+
+			reports, reject := co.onUpdate(test.current)
+
+			assert.Equal(t, test.reports, reports)
+			assert.Equal(t, test.reject, reject)
+			assert.Equal(t, test.clOrdId, co.clOrdId)
+			assert.Equal(t, test.current, co.order)
+			if test.stillPending {
+				assert.Equal(t, test.pending, co.pending)
+			} else {
+				assert.Nil(t, co.pending)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+type storedClientOrder struct {
+	sessionID string
+	clOrdId   string
+}
+
+// clientOrderStore persists the session and current ClOrdID of the orders created by client sessions so that the
+// orders and their ClOrdIDs can be restored on restart.  Entries are appended when an order is created and when its
+// ClOrdID is changed by a cancel or cancel/replace request, the last entry of an order is its current state.
+type clientOrderStore struct {
+	mux    sync.Mutex
+	path   string
+	file   *os.File
+	orders map[string]storedClientOrder
+}
+
+func newClientOrderStore(path string) (*clientOrderStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open client order file %v: %w", path, err)
+	}
+
+	orders := map[string]storedClientOrder{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := strings.SplitN(scanner.Text(), ",", 3)
+		if len(entry) != 3 {
+			_ = file.Close()
+			return nil, fmt.Errorf("invalid entry in client order file %v: %v", path, scanner.Text())
+		}
+		orders[entry[0]] = storedClientOrder{sessionID: entry[1], clOrdId: entry[2]}
+	}
+
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read client order file %v: %w", path, err)
+	}
+
+	return &clientOrderStore{path: path, file: file, orders: orders}, nil
+}
+
+func (s *clientOrderStore) get(orderId string) (storedClientOrder, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	order, ok := s.orders[orderId]
+	return order, ok
+}
+
+func (s *clientOrderStore) put(orderId string, sessionID string, clOrdId string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, err := s.file.WriteString(orderId + "," + sessionID + "," + clOrdId + "\n"); err != nil {
+		return fmt.Errorf("failed to write to client order file %v: %w", s.path, err)
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync client order file %v: %w", s.path, err)
+	}
+
+	s.orders[orderId] = storedClientOrder{sessionID: sessionID, clOrdId: clOrdId}
+
+	return nil
+}
+
+func (s *clientOrderStore) close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.file.Close()
+}
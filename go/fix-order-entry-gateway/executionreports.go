@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// executionReport is an execution report to be sent to the client in response to an order update.
+type executionReport struct {
+	execType    enum.ExecType
+	clOrdId     string
+	origClOrdId string
+}
+
+// cancelReject is an order cancel reject to be sent to the client in response to an order update.
+type cancelReject struct {
+	request  *pendingRequest
+	reason   enum.CxlRejReason
+	errorMsg string
+}
+
+func newExecId(order *model.Order, index int) string {
+	return fmt.Sprintf("%s.%d.%d", order.Id, order.Version, index)
+}
+
+func decimalsEqual(d1 *model.Decimal64, d2 *model.Decimal64) bool {
+	if d1 == nil || d2 == nil {
+		return d1 == d2
+	}
+
+	return d1.AsDecimal().Equal(d2.AsDecimal())
+}
+
+func getOrdStatus(order *model.Order) enum.OrdStatus {
+	switch order.Status {
+	case model.OrderStatus_LIVE:
+		if order.GetTradedQuantity() != nil && order.TradedQuantity.AsDecimal().GreaterThan(decimal.Zero) {
+			return enum.OrdStatus_PARTIALLY_FILLED
+		}
+		return enum.OrdStatus_NEW
+	case model.OrderStatus_FILLED:
+		return enum.OrdStatus_FILLED
+	case model.OrderStatus_CANCELLED:
+		return enum.OrdStatus_CANCELED
+	default:
+		return enum.OrdStatus_PENDING_NEW
+	}
+}
+
+func getFixSide(side model.Side) enum.Side {
+	if side == model.Side_SELL {
+		return enum.Side_SELL
+	}
+
+	return enum.Side_BUY
+}
+
+func toFixDecimal(d *model.Decimal64) (decimal.Decimal, int32) {
+	if d == nil {
+		return decimal.Zero, 0
+	}
+
+	var scale int32 = 0
+	if d.Exponent < 0 {
+		scale = -d.Exponent
+	}
+
+	return decimal.New(d.GetMantissa(), d.GetExponent()), scale
+}
+
+// getLeavesQuantity returns the quantity open for further execution, zero if the order is done.
+func getLeavesQuantity(order *model.Order) *model.Decimal64 {
+	if order.IsTerminalState() {
+		return &model.Decimal64{}
+	}
+
+	return order.RemainingQuantity
+}
+
+func setInstrumentFields(msg *quickfix.Message, listing *model.Listing) {
+	if listing.Instrument != nil {
+		msg.Body.Set(field.NewSymbol(listing.Instrument.DisplaySymbol))
+	}
+
+	if listing.Market != nil {
+		msg.Body.Set(field.NewSecurityExchange(listing.Market.Mic))
+	}
+}
+
+// newExecutionReportMessage returns the execution report of an order, the message body is common to FIX 4.4 and
+// FIX 5.0.
+func newExecutionReportMessage(order *model.Order, listing *model.Listing, report executionReport, execId string,
+	transactTime time.Time) *quickfix.Message {
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_EXECUTION_REPORT))
+
+	msg.Body.Set(field.NewOrderID(order.Id))
+	msg.Body.Set(field.NewClOrdID(report.clOrdId))
+	if report.origClOrdId != "" {
+		msg.Body.Set(field.NewOrigClOrdID(report.origClOrdId))
+	}
+	msg.Body.Set(field.NewExecID(execId))
+	msg.Body.Set(field.NewExecType(report.execType))
+	if report.execType == enum.ExecType_REJECTED {
+		msg.Body.Set(field.NewOrdStatus(enum.OrdStatus_REJECTED))
+		msg.Body.Set(field.NewOrdRejReason(enum.OrdRejReason_BROKER))
+	} else {
+		msg.Body.Set(field.NewOrdStatus(getOrdStatus(order)))
+	}
+	msg.Body.Set(field.NewSide(getFixSide(order.Side)))
+	setInstrumentFields(msg, listing)
+
+	msg.Body.Set(field.NewOrderQty(toFixDecimal(order.Quantity)))
+	if order.Price != nil {
+		msg.Body.Set(field.NewPrice(toFixDecimal(order.Price)))
+	}
+	msg.Body.Set(field.NewLeavesQty(toFixDecimal(getLeavesQuantity(order))))
+	msg.Body.Set(field.NewCumQty(toFixDecimal(order.TradedQuantity)))
+	msg.Body.Set(field.NewAvgPx(toFixDecimal(order.AvgTradePrice)))
+
+	if report.execType == enum.ExecType_TRADE {
+		msg.Body.Set(field.NewLastQty(toFixDecimal(order.LastExecQuantity)))
+		msg.Body.Set(field.NewLastPx(toFixDecimal(order.LastExecPrice)))
+	}
+
+	if order.ErrorMessage != "" {
+		msg.Body.Set(field.NewText(order.ErrorMessage))
+	}
+
+	msg.Body.Set(field.NewTransactTime(transactTime))
+
+	return msg
+}
+
+// newOrderRejectMessage returns an execution report rejecting a new order single that was not accepted by the
+// platform.
+func newOrderRejectMessage(request *newOrderRequest, execId string, reason enum.OrdRejReason, text string,
+	transactTime time.Time) *quickfix.Message {
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_EXECUTION_REPORT))
+
+	msg.Body.Set(field.NewOrderID("NONE"))
+	msg.Body.Set(field.NewClOrdID(request.clOrdId))
+	msg.Body.Set(field.NewExecID(execId))
+	msg.Body.Set(field.NewExecType(enum.ExecType_REJECTED))
+	msg.Body.Set(field.NewOrdStatus(enum.OrdStatus_REJECTED))
+	msg.Body.Set(field.NewOrdRejReason(reason))
+	msg.Body.Set(field.NewSide(request.side))
+	msg.Body.Set(field.NewSymbol(request.symbol))
+	if request.mic != "" {
+		msg.Body.Set(field.NewSecurityExchange(request.mic))
+	}
+	msg.Body.Set(field.NewOrderQty(request.quantity, request.quantity.Exponent()*-1))
+	msg.Body.Set(field.NewLeavesQty(decimal.Zero, 0))
+	msg.Body.Set(field.NewCumQty(decimal.Zero, 0))
+	msg.Body.Set(field.NewAvgPx(decimal.Zero, 0))
+	msg.Body.Set(field.NewText(text))
+	msg.Body.Set(field.NewTransactTime(transactTime))
+
+	return msg
+}
+
+// newCancelRejectMessage returns an order cancel reject of a cancel or cancel/replace request, orderId and ordStatus
+// are those of the order if it is known.
+func newCancelRejectMessage(orderId string, ordStatus enum.OrdStatus, clOrdId string, origClOrdId string,
+	isCancel bool, reason enum.CxlRejReason, text string) *quickfix.Message {
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_CANCEL_REJECT))
+
+	if orderId == "" {
+		orderId = "NONE"
+	}
+
+	msg.Body.Set(field.NewOrderID(orderId))
+	msg.Body.Set(field.NewClOrdID(clOrdId))
+	msg.Body.Set(field.NewOrigClOrdID(origClOrdId))
+	msg.Body.Set(field.NewOrdStatus(ordStatus))
+	if isCancel {
+		msg.Body.Set(field.NewCxlRejResponseTo(enum.CxlRejResponseTo_ORDER_CANCEL_REQUEST))
+	} else {
+		msg.Body.Set(field.NewCxlRejResponseTo(enum.CxlRejResponseTo_ORDER_CANCEL_REPLACE_REQUEST))
+	}
+	msg.Body.Set(field.NewCxlRejReason(reason))
+	if text != "" {
+		msg.Body.Set(field.NewText(text))
+	}
+
+	return msg
+}
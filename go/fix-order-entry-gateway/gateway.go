@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/model"
+	"github.com/google/uuid"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// clientSession is a client's FIX session, orders created by the session are created with the session's originator
+// id as their originator and root originator id and with the session's user as their root originator ref.  The ClOrdID
+// of a new order is its originator ref.  The session's user must have trading permissions.
+type clientSession struct {
+	sessionID    quickfix.SessionID
+	originatorId string
+	user         string
+}
+
+type originator struct {
+	originatorId string
+	user         string
+}
+
+type listingSource interface {
+	getListing(ctx context.Context, listingId int32) (*model.Listing, error)
+	getListingMatching(ctx context.Context, symbol string, mic string) (*model.Listing, error)
+}
+
+// orderEntryGateway maps the order requests received from client sessions onto the order router and sends the
+// execution reports of the orders created by the sessions to the clients.
+type orderEntryGateway struct {
+	ctx            context.Context
+	sessions       map[quickfix.SessionID]clientSession
+	sessionIDs     map[originator]quickfix.SessionID
+	orderRouter    executionvenue.ExecutionVenueClient
+	listings       listingSource
+	tradingUsers   map[string]bool
+	store          *clientOrderStore
+	send           func(m quickfix.Messagable, sessionID quickfix.SessionID) error
+	requestTimeout time.Duration
+
+	mux sync.Mutex
+	// orders are the client orders keyed by order id
+	orders map[string]*clientOrder
+	// orderIds are the ids of the orders of each session keyed by ClOrdID
+	orderIds map[quickfix.SessionID]map[string]string
+	// newOrders are the ClOrdIDs of each session's new orders that are being routed
+	newOrders map[quickfix.SessionID]map[string]bool
+}
+
+func newOrderEntryGateway(ctx context.Context, sessions []clientSession, orderRouter executionvenue.ExecutionVenueClient,
+	listings listingSource, tradingUsers map[string]bool, store *clientOrderStore,
+	send func(m quickfix.Messagable, sessionID quickfix.SessionID) error, requestTimeout time.Duration) *orderEntryGateway {
+
+	g := &orderEntryGateway{
+		ctx:            ctx,
+		sessions:       map[quickfix.SessionID]clientSession{},
+		sessionIDs:     map[originator]quickfix.SessionID{},
+		orderRouter:    orderRouter,
+		listings:       listings,
+		tradingUsers:   tradingUsers,
+		store:          store,
+		send:           send,
+		requestTimeout: requestTimeout,
+		orders:         map[string]*clientOrder{},
+		orderIds:       map[quickfix.SessionID]map[string]string{},
+		newOrders:      map[quickfix.SessionID]map[string]bool{},
+	}
+
+	for _, session := range sessions {
+		g.sessions[session.sessionID] = session
+		g.sessionIDs[originator{originatorId: session.originatorId, user: session.user}] = session.sessionID
+		g.orderIds[session.sessionID] = map[string]string{}
+		g.newOrders[session.sessionID] = map[string]bool{}
+	}
+
+	return g
+}
+
+// run sends execution reports for the order updates until the context is cancelled or the updates channel is closed.
+// The initial orders are the state of the orders when the gateway starts, no reports are sent for them.
+func (g *orderEntryGateway) run(initialOrders map[string]*model.Order, updates <-chan *model.Order) {
+	g.mux.Lock()
+	for _, order := range initialOrders {
+		if co, ok := g.getClientOrder(order); ok {
+			co.order = order
+		}
+	}
+	g.mux.Unlock()
+
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case order, ok := <-updates:
+			if !ok {
+				slog.Error("order updates channel closed")
+				return
+			}
+
+			g.onOrderUpdate(order)
+		}
+	}
+}
+
+// getClientOrder returns the client order of the order if it was created by a client session, an update of a new
+// order may be received before the order router has returned the order's id.  Must be called with the lock held.
+func (g *orderEntryGateway) getClientOrder(order *model.Order) (*clientOrder, bool) {
+	if co, ok := g.orders[order.Id]; ok {
+		return co, true
+	}
+
+	var co *clientOrder
+	if stored, ok := g.store.get(order.Id); ok {
+		for sessionID := range g.sessions {
+			if sessionID.String() == stored.sessionID {
+				co = &clientOrder{sessionID: sessionID, clOrdId: stored.clOrdId}
+			}
+		}
+	} else {
+		sessionID, ok := g.sessionIDs[originator{originatorId: order.OriginatorId, user: order.RootOriginatorRef}]
+		if ok && g.newOrders[sessionID][order.OriginatorRef] {
+			co = &clientOrder{sessionID: sessionID, clOrdId: order.OriginatorRef}
+		}
+	}
+
+	if co == nil {
+		return nil, false
+	}
+
+	g.orders[order.Id] = co
+	g.orderIds[co.sessionID][order.OriginatorRef] = order.Id
+	g.orderIds[co.sessionID][co.clOrdId] = order.Id
+
+	return co, true
+}
+
+func (g *orderEntryGateway) onOrderUpdate(order *model.Order) {
+	g.mux.Lock()
+	co, ok := g.getClientOrder(order)
+	if !ok {
+		g.mux.Unlock()
+		return
+	}
+
+	previousClOrdId := co.clOrdId
+	reports, reject := co.onUpdate(order)
+	clOrdId := co.clOrdId
+	sessionID := co.sessionID
+	g.mux.Unlock()
+
+	if clOrdId != previousClOrdId {
+		if err := g.store.put(order.Id, sessionID.String(), clOrdId); err != nil {
+			slog.Error("failed to store ClOrdID", "orderId", order.Id, "clOrdId", clOrdId, "error", err)
+		}
+	}
+
+	if len(reports) > 0 {
+		listing, err := g.listings.getListing(g.ctx, order.ListingId)
+		if err != nil {
+			slog.Error("failed to get listing, execution reports not sent", "orderId", order.Id, "listingId",
+				order.ListingId, "error", err)
+		} else {
+			for i, report := range reports {
+				g.sendToSession(newExecutionReportMessage(order, listing, report, newExecId(order, i), time.Now()),
+					sessionID)
+			}
+		}
+	}
+
+	if reject != nil {
+		g.sendToSession(newCancelRejectMessage(order.Id, getOrdStatus(order), reject.request.clOrdId,
+			reject.request.origClOrdId, reject.request.isCancel, reject.reason, reject.errorMsg), sessionID)
+	}
+}
+
+func (g *orderEntryGateway) sendToSession(msg *quickfix.Message, sessionID quickfix.SessionID) {
+	if err := g.send(msg, sessionID); err != nil {
+		slog.Error("failed to send message", "sessionID", sessionID.String(), "message", msg.String(), "error", err)
+	}
+}
+
+// newRequestContext returns the context of a request to the order router, the request carries the session's user name
+// so that the user's risk limits are applied.
+func (g *orderEntryGateway) newRequestContext(session clientSession) (context.Context, context.CancelFunc) {
+	ctx := metadata.AppendToOutgoingContext(g.ctx, "user-name", session.user)
+	return context.WithTimeout(ctx, g.requestTimeout)
+}
+
+func (g *orderEntryGateway) onNewOrderSingle(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	session, ok := g.sessions[sessionID]
+	if !ok {
+		return quickfix.UnsupportedMessageType()
+	}
+
+	request, rejectErr, err := parseNewOrderSingle(msg)
+	if rejectErr != nil {
+		return rejectErr
+	}
+
+	reject := func(reason enum.OrdRejReason, text string) {
+		slog.Warn("new order rejected", "sessionID", sessionID.String(), "clOrdId", request.clOrdId, "reason", text)
+		g.sendToSession(newOrderRejectMessage(request, uuid.New().String(), reason, text, time.Now()), sessionID)
+	}
+
+	if err != nil {
+		reject(enum.OrdRejReason_UNSUPPORTED_ORDER_CHARACTERISTIC, err.Error())
+		return nil
+	}
+
+	if !g.tradingUsers[session.user] {
+		reject(enum.OrdRejReason_BROKER, "trading permissions required")
+		return nil
+	}
+
+	g.mux.Lock()
+	_, duplicate := g.orderIds[sessionID][request.clOrdId]
+	duplicate = duplicate || g.newOrders[sessionID][request.clOrdId]
+	if !duplicate {
+		g.newOrders[sessionID][request.clOrdId] = true
+	}
+	g.mux.Unlock()
+	if duplicate {
+		reject(enum.OrdRejReason_DUPLICATE_ORDER, "duplicate ClOrdID")
+		return nil
+	}
+
+	defer func() {
+		g.mux.Lock()
+		delete(g.newOrders[sessionID], request.clOrdId)
+		g.mux.Unlock()
+	}()
+
+	listing, err := g.listings.getListingMatching(g.ctx, request.symbol, request.mic)
+	if err != nil {
+		reject(enum.OrdRejReason_UNKNOWN_SYMBOL, err.Error())
+		return nil
+	}
+
+	destination := request.destination
+	if destination == "" && listing.Market != nil {
+		destination = listing.Market.Mic
+	}
+
+	paramsJson, err := request.params.toJson()
+	if err != nil {
+		reject(enum.OrdRejReason_OTHER, err.Error())
+		return nil
+	}
+
+	params := &executionvenue.CreateAndRouteOrderParams{
+		OrderSide:          toModelSide(request.side),
+		Quantity:           model.ToDecimal64(request.quantity),
+		ListingId:          listing.Id,
+		Destination:        destination,
+		OriginatorId:       session.originatorId,
+		OriginatorRef:      request.clOrdId,
+		RootOriginatorId:   session.originatorId,
+		RootOriginatorRef:  session.user,
+		ExecParametersJson: paramsJson,
+	}
+	if request.price != nil {
+		params.Price = model.ToDecimal64(*request.price)
+	}
+
+	ctx, cancel := g.newRequestContext(session)
+	defer cancel()
+	orderId, err := g.orderRouter.CreateAndRouteOrder(ctx, params)
+	if err != nil {
+		reject(enum.OrdRejReason_OTHER, status.Convert(err).Message())
+		return nil
+	}
+
+	if err := g.store.put(orderId.OrderId, sessionID.String(), request.clOrdId); err != nil {
+		slog.Error("failed to store client order", "orderId", orderId.OrderId, "clOrdId", request.clOrdId,
+			"error", err)
+	}
+
+	g.mux.Lock()
+	if _, ok := g.orders[orderId.OrderId]; !ok {
+		g.orders[orderId.OrderId] = &clientOrder{sessionID: sessionID, clOrdId: request.clOrdId}
+	}
+	g.orderIds[sessionID][request.clOrdId] = orderId.OrderId
+	g.mux.Unlock()
+
+	slog.Info("created order", "sessionID", sessionID.String(), "clOrdId", request.clOrdId, "orderId",
+		orderId.OrderId)
+
+	return nil
+}
+
+func toModelSide(side enum.Side) model.Side {
+	if side == enum.Side_SELL {
+		return model.Side_SELL
+	}
+
+	return model.Side_BUY
+}
+
+func (g *orderEntryGateway) onOrderCancelRequest(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	return g.onCancelRequest(msg, sessionID, false)
+}
+
+func (g *orderEntryGateway) onOrderCancelReplaceRequest(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	return g.onCancelRequest(msg, sessionID, true)
+}
+
+func (g *orderEntryGateway) onCancelRequest(msg *quickfix.Message, sessionID quickfix.SessionID,
+	isReplace bool) quickfix.MessageRejectError {
+
+	session, ok := g.sessions[sessionID]
+	if !ok {
+		return quickfix.UnsupportedMessageType()
+	}
+
+	request, rejectErr := parseCancelRequest(msg, isReplace)
+	if rejectErr != nil {
+		return rejectErr
+	}
+
+	reject := func(orderId string, ordStatus enum.OrdStatus, reason enum.CxlRejReason, text string) {
+		slog.Warn("cancel request rejected", "sessionID", sessionID.String(), "clOrdId", request.clOrdId,
+			"origClOrdId", request.origClOrdId, "isReplace", isReplace, "reason", text)
+		g.sendToSession(newCancelRejectMessage(orderId, ordStatus, request.clOrdId, request.origClOrdId, !isReplace,
+			reason, text), sessionID)
+	}
+
+	if !g.tradingUsers[session.user] {
+		reject("", enum.OrdStatus_REJECTED, enum.CxlRejReason_OTHER, "trading permissions required")
+		return nil
+	}
+
+	g.mux.Lock()
+	co, order, reason, text := g.getOrderToCancel(sessionID, request, isReplace)
+	if text != "" {
+		g.mux.Unlock()
+		if order != nil {
+			reject(order.Id, getOrdStatus(order), reason, text)
+		} else {
+			reject("", enum.OrdStatus_REJECTED, reason, text)
+		}
+		return nil
+	}
+
+	pending := &pendingRequest{clOrdId: request.clOrdId, origClOrdId: request.origClOrdId, isCancel: !isReplace}
+	co.pending = pending
+	g.orderIds[sessionID][request.clOrdId] = order.Id
+	g.mux.Unlock()
+
+	ctx, cancel := g.newRequestContext(session)
+	defer cancel()
+
+	var err error
+	if isReplace {
+		params := &executionvenue.ModifyOrderParams{
+			OrderId:   order.Id,
+			ListingId: order.ListingId,
+			OwnerId:   order.OwnerId,
+			Quantity:  model.ToDecimal64(request.quantity),
+			Price:     order.Price,
+		}
+		if request.price != nil {
+			params.Price = model.ToDecimal64(*request.price)
+		}
+		_, err = g.orderRouter.ModifyOrder(ctx, params)
+	} else {
+		_, err = g.orderRouter.CancelOrder(ctx, &executionvenue.CancelOrderParams{
+			OrderId:   order.Id,
+			ListingId: order.ListingId,
+			OwnerId:   order.OwnerId,
+		})
+	}
+
+	if err != nil {
+		g.mux.Lock()
+		if co.pending == pending {
+			co.pending = nil
+		}
+		g.mux.Unlock()
+		reject(order.Id, getOrdStatus(order), enum.CxlRejReason_OTHER, status.Convert(err).Message())
+	}
+
+	return nil
+}
+
+// getOrderToCancel returns the client order and its current state that is the target of the cancel request, if the
+// request cannot be accepted the reject reason and text are returned.  Must be called with the lock held.
+func (g *orderEntryGateway) getOrderToCancel(sessionID quickfix.SessionID, request *cancelRequest,
+	isReplace bool) (*clientOrder, *model.Order, enum.CxlRejReason, string) {
+
+	orderId, ok := g.orderIds[sessionID][request.origClOrdId]
+	if !ok {
+		return nil, nil, enum.CxlRejReason_UNKNOWN_ORDER, "unknown order"
+	}
+
+	co := g.orders[orderId]
+	if co.order == nil {
+		return nil, nil, enum.CxlRejReason_OTHER, "the order has not yet been acknowledged"
+	}
+
+	order := co.order
+	if order.IsTerminalState() {
+		return nil, order, enum.CxlRejReason_TOO_LATE_TO_CANCEL, "the order is done"
+	}
+
+	if co.pending != nil {
+		return nil, order, enum.CxlRejReason_ORDER_ALREADY_IN_PENDING_CANCEL_OR_PENDING_REPLACE_STATUS,
+			"the order has a pending cancel or replace request"
+	}
+
+	if _, ok := g.orderIds[sessionID][request.clOrdId]; ok {
+		return nil, order, enum.CxlRejReason_DUPLICATE_CLORDID, "duplicate ClOrdID"
+	}
+
+	if isReplace {
+		quantity := model.ToDecimal64(request.quantity)
+		price := order.Price
+		if request.price != nil {
+			price = model.ToDecimal64(*request.price)
+		}
+
+		if decimalsEqual(quantity, order.Quantity) && decimalsEqual(price, order.Price) {
+			return nil, order, enum.CxlRejReason_OTHER, "the request does not change the order's quantity or price"
+		}
+	}
+
+	return co, order, "", ""
+}
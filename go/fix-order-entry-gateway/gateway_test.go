@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/model"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testOrderRouter struct {
+	createParams []*executionvenue.CreateAndRouteOrderParams
+	cancelParams []*executionvenue.CancelOrderParams
+	modifyParams []*executionvenue.ModifyOrderParams
+	users        []string
+	err          error
+}
+
+func (r *testOrderRouter) recordUser(ctx context.Context) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		r.users = append(r.users, md.Get("user-name")...)
+	}
+}
+
+func (r *testOrderRouter) CreateAndRouteOrder(ctx context.Context, in *executionvenue.CreateAndRouteOrderParams,
+	_ ...grpc.CallOption) (*executionvenue.OrderId, error) {
+	r.recordUser(ctx)
+	r.createParams = append(r.createParams, in)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &executionvenue.OrderId{OrderId: "o1"}, nil
+}
+
+func (r *testOrderRouter) CancelOrder(ctx context.Context, in *executionvenue.CancelOrderParams,
+	_ ...grpc.CallOption) (*model.Empty, error) {
+	r.recordUser(ctx)
+	r.cancelParams = append(r.cancelParams, in)
+	return &model.Empty{}, r.err
+}
+
+func (r *testOrderRouter) ModifyOrder(ctx context.Context, in *executionvenue.ModifyOrderParams,
+	_ ...grpc.CallOption) (*model.Empty, error) {
+	r.recordUser(ctx)
+	r.modifyParams = append(r.modifyParams, in)
+	return &model.Empty{}, r.err
+}
+
+func (r *testOrderRouter) GetExecutionParametersMetaData(context.Context, *model.Empty,
+	...grpc.CallOption) (*executionvenue.ExecParamsMetaDataJson, error) {
+	return &executionvenue.ExecParamsMetaDataJson{}, nil
+}
+
+var testListing = &model.Listing{Id: 1, Market: &model.Market{Mic: "XNAS"},
+	Instrument: &model.Instrument{DisplaySymbol: "AAPL"}}
+
+type testListings struct{}
+
+func (l testListings) getListing(context.Context, int32) (*model.Listing, error) {
+	return testListing, nil
+}
+
+func (l testListings) getListingMatching(_ context.Context, symbol string, mic string) (*model.Listing, error) {
+	if symbol == "AAPL" && mic == "XNAS" {
+		return testListing, nil
+	}
+	return nil, errors.New("no listing found")
+}
+
+var testSession = clientSession{
+	sessionID:    quickfix.SessionID{BeginString: enum.BeginStringFIX44, SenderCompID: "OTPORDERENTRY", TargetCompID: "CLIENTA"},
+	originatorId: "clienta",
+	user:         "trader1",
+}
+
+type sentMessages struct {
+	messages []*quickfix.Message
+}
+
+func (s *sentMessages) send(m quickfix.Messagable, _ quickfix.SessionID) error {
+	s.messages = append(s.messages, m.ToMessage())
+	return nil
+}
+
+func (s *sentMessages) last(t *testing.T) *quickfix.Message {
+	if assert.NotEmpty(t, s.messages) {
+		return s.messages[len(s.messages)-1]
+	}
+	return quickfix.NewMessage()
+}
+
+func newTestGateway(t *testing.T, storePath string, router *testOrderRouter,
+	tradingUsers map[string]bool) (*orderEntryGateway, *sentMessages) {
+	store, err := newClientOrderStore(storePath)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = store.close() })
+
+	sent := &sentMessages{}
+	return newOrderEntryGateway(context.Background(), []clientSession{testSession}, router, testListings{},
+		tradingUsers, store, sent.send, time.Second), sent
+}
+
+func getBodyString(t *testing.T, msg *quickfix.Message, fieldTag quickfix.Tag) string {
+	value, err := msg.Body.GetString(fieldTag)
+	assert.NoError(t, err)
+	return value
+}
+
+func newTestCancelRequest(clOrdId string, origClOrdId string, qty *decimal.Decimal) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	if qty == nil {
+		msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_CANCEL_REQUEST))
+	} else {
+		msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST))
+		msg.Body.Set(field.NewOrderQty(*qty, 0))
+	}
+	msg.Body.Set(field.NewClOrdID(clOrdId))
+	msg.Body.Set(field.NewOrigClOrdID(origClOrdId))
+	return msg
+}
+
+func liveOrder(qty int64, version int32) *model.Order {
+	return &model.Order{Id: "o1", Version: version, Side: model.Side_BUY, Status: model.OrderStatus_LIVE, ListingId: 1,
+		Quantity: &model.Decimal64{Mantissa: qty}, Price: &model.Decimal64{Mantissa: 100},
+		RemainingQuantity: &model.Decimal64{Mantissa: qty}, TradedQuantity: &model.Decimal64{},
+		OwnerId: "xnas-order-gateway-0", OriginatorId: "clienta", OriginatorRef: "c1",
+		RootOriginatorId: "clienta", RootOriginatorRef: "trader1"}
+}
+
+func TestNewOrderIsRoutedAndAcknowledged(t *testing.T) {
+	router := &testOrderRouter{}
+	gateway, sent := newTestGateway(t, filepath.Join(t.TempDir(), "clientorders"), router, map[string]bool{"trader1": true})
+
+	price := decimal.New(100, 0)
+	assert.Nil(t, gateway.onNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price), testSession.sessionID))
+
+	if assert.Len(t, router.createParams, 1) {
+		params := router.createParams[0]
+		assert.Equal(t, model.Side_BUY, params.OrderSide)
+		assert.Equal(t, int32(1), params.ListingId)
+		assert.Equal(t, "XNAS", params.Destination)
+		assert.Equal(t, "clienta", params.OriginatorId)
+		assert.Equal(t, "c1", params.OriginatorRef)
+		assert.Equal(t, "clienta", params.RootOriginatorId)
+		assert.Equal(t, "trader1", params.RootOriginatorRef)
+	}
+	assert.Equal(t, []string{"trader1"}, router.users)
+	assert.Empty(t, sent.messages)
+
+	gateway.onOrderUpdate(liveOrder(100, 1))
+
+	msg := sent.last(t)
+	assert.Equal(t, string(enum.ExecType_NEW), getBodyString(t, msg, tag.ExecType))
+	assert.Equal(t, "c1", getBodyString(t, msg, tag.ClOrdID))
+	assert.Equal(t, "o1", getBodyString(t, msg, tag.OrderID))
+	assert.Equal(t, "AAPL", getBodyString(t, msg, tag.Symbol))
+
+	// duplicate ClOrdIDs are rejected
+	assert.Nil(t, gateway.onNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price), testSession.sessionID))
+	assert.Len(t, router.createParams, 1)
+	msg = sent.last(t)
+	assert.Equal(t, string(enum.ExecType_REJECTED), getBodyString(t, msg, tag.ExecType))
+	assert.Equal(t, string(enum.OrdRejReason_DUPLICATE_ORDER), getBodyString(t, msg, tag.OrdRejReason))
+}
+
+func TestNewOrderRejections(t *testing.T) {
+	price := decimal.New(100, 0)
+
+	router := &testOrderRouter{}
+	gateway, sent := newTestGateway(t, filepath.Join(t.TempDir(), "clientorders"), router, map[string]bool{})
+	assert.Nil(t, gateway.onNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price), testSession.sessionID))
+	assert.Empty(t, router.createParams)
+	assert.Equal(t, "trading permissions required", getBodyString(t, sent.last(t), tag.Text))
+
+	router = &testOrderRouter{err: errors.New("risk limit breached")}
+	gateway, sent = newTestGateway(t, filepath.Join(t.TempDir(), "clientorders"), router, map[string]bool{"trader1": true})
+	assert.Nil(t, gateway.onNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price), testSession.sessionID))
+	msg := sent.last(t)
+	assert.Equal(t, string(enum.OrdStatus_REJECTED), getBodyString(t, msg, tag.OrdStatus))
+	assert.Equal(t, "risk limit breached", getBodyString(t, msg, tag.Text))
+
+	// the rejected ClOrdID can be reused
+	router.err = nil
+	assert.Nil(t, gateway.onNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price), testSession.sessionID))
+	assert.Len(t, router.createParams, 2)
+}
+
+func TestUpdatesOfOtherOrdersAreIgnored(t *testing.T) {
+	gateway, sent := newTestGateway(t, filepath.Join(t.TempDir(), "clientorders"), &testOrderRouter{},
+		map[string]bool{"trader1": true})
+
+	// an order with the session's originator and user that was not created by the session, e.g. from the gui
+	gateway.onOrderUpdate(liveOrder(100, 1))
+	assert.Empty(t, sent.messages)
+}
+
+func TestCancelReplaceAndCancel(t *testing.T) {
+	router := &testOrderRouter{}
+	storePath := filepath.Join(t.TempDir(), "clientorders")
+	gateway, sent := newTestGateway(t, storePath, router, map[string]bool{"trader1": true})
+
+	price := decimal.New(100, 0)
+	assert.Nil(t, gateway.onNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price), testSession.sessionID))
+	gateway.onOrderUpdate(liveOrder(100, 1))
+
+	qty := decimal.New(200, 0)
+	assert.Nil(t, gateway.onOrderCancelReplaceRequest(newTestCancelRequest("c2", "c1", &qty), testSession.sessionID))
+	if assert.Len(t, router.modifyParams, 1) {
+		assert.Equal(t, "xnas-order-gateway-0", router.modifyParams[0].OwnerId)
+		assert.Equal(t, int64(200), router.modifyParams[0].Quantity.Mantissa)
+		assert.Equal(t, int64(100), router.modifyParams[0].Price.Mantissa)
+	}
+
+	// a second request whilst the replace is pending is rejected
+	assert.Nil(t, gateway.onOrderCancelRequest(newTestCancelRequest("c3", "c1", nil), testSession.sessionID))
+	msg := sent.last(t)
+	assert.Equal(t, string(enum.MsgType_ORDER_CANCEL_REJECT), getMsgType(t, msg))
+	assert.Equal(t, string(enum.CxlRejReason_ORDER_ALREADY_IN_PENDING_CANCEL_OR_PENDING_REPLACE_STATUS),
+		getBodyString(t, msg, tag.CxlRejReason))
+
+	gateway.onOrderUpdate(liveOrder(200, 2))
+	msg = sent.last(t)
+	assert.Equal(t, string(enum.ExecType_REPLACED), getBodyString(t, msg, tag.ExecType))
+	assert.Equal(t, "c2", getBodyString(t, msg, tag.ClOrdID))
+	assert.Equal(t, "c1", getBodyString(t, msg, tag.OrigClOrdID))
+
+	assert.Nil(t, gateway.onOrderCancelRequest(newTestCancelRequest("c3", "c2", nil), testSession.sessionID))
+	assert.Len(t, router.cancelParams, 1)
+
+	cancelled := liveOrder(200, 3)
+	cancelled.Status = model.OrderStatus_CANCELLED
+	gateway.onOrderUpdate(cancelled)
+	msg = sent.last(t)
+	assert.Equal(t, string(enum.ExecType_CANCELED), getBodyString(t, msg, tag.ExecType))
+	assert.Equal(t, "c3", getBodyString(t, msg, tag.ClOrdID))
+	assert.Equal(t, "c2", getBodyString(t, msg, tag.OrigClOrdID))
+	assert.Equal(t, "0", getBodyString(t, msg, tag.LeavesQty))
+
+	// the order's current ClOrdID is restored on restart
+	restarted, restartedSent := newTestGateway(t, storePath, router, map[string]bool{"trader1": true})
+	updates := make(chan *model.Order)
+	close(updates)
+	restarted.run(map[string]*model.Order{"o1": liveOrder(200, 2)}, updates)
+
+	restarted.mux.Lock()
+	restoredOrderId := restarted.orderIds[testSession.sessionID]["c3"]
+	restarted.mux.Unlock()
+	assert.Equal(t, "o1", restoredOrderId)
+	assert.Empty(t, restartedSent.messages)
+}
+
+func TestCancelRequestRejections(t *testing.T) {
+	router := &testOrderRouter{}
+	gateway, sent := newTestGateway(t, filepath.Join(t.TempDir(), "clientorders"), router, map[string]bool{"trader1": true})
+
+	assert.Nil(t, gateway.onOrderCancelRequest(newTestCancelRequest("c2", "unknown", nil), testSession.sessionID))
+	assert.Equal(t, string(enum.CxlRejReason_UNKNOWN_ORDER), getBodyString(t, sent.last(t), tag.CxlRejReason))
+
+	price := decimal.New(100, 0)
+	assert.Nil(t, gateway.onNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price), testSession.sessionID))
+	gateway.onOrderUpdate(liveOrder(100, 1))
+
+	router.err = errors.New("venue unavailable")
+	assert.Nil(t, gateway.onOrderCancelRequest(newTestCancelRequest("c2", "c1", nil), testSession.sessionID))
+	msg := sent.last(t)
+	assert.Equal(t, "venue unavailable", getBodyString(t, msg, tag.Text))
+	assert.Equal(t, string(enum.CxlRejResponseTo_ORDER_CANCEL_REQUEST), getBodyString(t, msg, tag.CxlRejResponseTo))
+
+	// the failed request is no longer pending
+	router.err = nil
+	assert.Nil(t, gateway.onOrderCancelRequest(newTestCancelRequest("c3", "c1", nil), testSession.sessionID))
+	assert.Len(t, router.cancelParams, 2)
+}
+
+func getMsgType(t *testing.T, msg *quickfix.Message) string {
+	msgType, err := msg.Header.GetString(tag.MsgType)
+	assert.NoError(t, err)
+	return msgType
+}
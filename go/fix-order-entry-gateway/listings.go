@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ettec/otp-common/api/staticdataservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/staticdata"
+	"time"
+)
+
+// staticDataListings returns listings from the static data service, a listing that is not found is reported as an
+// error once the lookup timeout expires as the static data source does not report missing listings.
+type staticDataListings struct {
+	source        *staticdata.Source
+	lookupTimeout time.Duration
+}
+
+func (s *staticDataListings) getListing(ctx context.Context, listingId int32) (*model.Listing, error) {
+	resultChan := make(chan staticdata.ListingResult, 1)
+	s.source.GetListing(ctx, listingId, resultChan)
+	return s.waitForListing(ctx, resultChan, fmt.Sprintf("listing %v", listingId))
+}
+
+func (s *staticDataListings) getListingMatching(ctx context.Context, symbol string, mic string) (*model.Listing, error) {
+	resultChan := make(chan staticdata.ListingResult, 1)
+	s.source.GetListingMatching(ctx, &staticdataservice.ExactMatchParameters{Symbol: symbol, Mic: mic}, resultChan)
+	return s.waitForListing(ctx, resultChan, fmt.Sprintf("listing for symbol %v and market %v", symbol, mic))
+}
+
+func (s *staticDataListings) waitForListing(ctx context.Context, resultChan <-chan staticdata.ListingResult,
+	description string) (*model.Listing, error) {
+	select {
+	case result := <-resultChan:
+		if result.Err != nil {
+			return nil, fmt.Errorf("failed to get %v: %w", description, result.Err)
+		}
+		return result.Listing, nil
+	case <-time.After(s.lookupTimeout):
+		return nil, fmt.Errorf("no %v found", description)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
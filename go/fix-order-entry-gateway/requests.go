@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/shopspring/decimal"
+	"strings"
+	"time"
+)
+
+// newOrderRequest is a parsed NewOrderSingle.
+type newOrderRequest struct {
+	clOrdId     string
+	side        enum.Side
+	symbol      string
+	mic         string
+	destination string
+	quantity    decimal.Decimal
+	price       *decimal.Decimal
+	params      executionParameters
+}
+
+// executionParameters are the order type and time in force of an order, they are sent to the order's destination as
+// its execution parameters using the field names of the fix sim execution venue's parameters schema.
+type executionParameters struct {
+	OrderType         string  `json:"orderType,omitempty"`
+	TimeInForce       string  `json:"timeInForce,omitempty"`
+	StopPrice         float64 `json:"stopPrice,omitempty"`
+	UtcExpireTimeSecs int64   `json:"utcExpireTimeSecs,omitempty"`
+}
+
+// toJson returns the parameters as json, an empty string is returned for a limit order that is good for the day as
+// these are the defaults of every destination.
+func (p executionParameters) toJson() (string, error) {
+	if p.OrderType == "LIMIT" && p.TimeInForce == "DAY" {
+		return "", nil
+	}
+
+	paramsJson, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal execution parameters: %w", err)
+	}
+
+	return string(paramsJson), nil
+}
+
+// requestError is an error in the content of a request that is reported to the client in the request's rejection.
+type requestError struct {
+	text string
+}
+
+func (e requestError) Error() string {
+	return e.text
+}
+
+func newRequestError(format string, a ...interface{}) requestError {
+	return requestError{text: fmt.Sprintf(format, a...)}
+}
+
+func getRequiredString(fieldMap quickfix.FieldMap, t quickfix.Tag) (string, quickfix.MessageRejectError) {
+	if !fieldMap.Has(t) {
+		return "", quickfix.RequiredTagMissing(t)
+	}
+
+	return fieldMap.GetString(t)
+}
+
+func getOptionalString(fieldMap quickfix.FieldMap, t quickfix.Tag) (string, quickfix.MessageRejectError) {
+	if !fieldMap.Has(t) {
+		return "", nil
+	}
+
+	return fieldMap.GetString(t)
+}
+
+func getRequiredDecimal(fieldMap quickfix.FieldMap, t quickfix.Tag) (decimal.Decimal, quickfix.MessageRejectError) {
+	if !fieldMap.Has(t) {
+		return decimal.Zero, quickfix.RequiredTagMissing(t)
+	}
+
+	var value quickfix.FIXDecimal
+	if err := fieldMap.GetField(t, &value); err != nil {
+		return decimal.Zero, err
+	}
+
+	return value.Decimal, nil
+}
+
+func getOptionalDecimal(fieldMap quickfix.FieldMap, t quickfix.Tag) (*decimal.Decimal, quickfix.MessageRejectError) {
+	if !fieldMap.Has(t) {
+		return nil, nil
+	}
+
+	value, err := getRequiredDecimal(fieldMap, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+// parseNewOrderSingle returns the request of a NewOrderSingle, a reject error is returned if a required field is
+// missing or a field is malformed, a requestError is returned if the request is not supported.
+func parseNewOrderSingle(msg *quickfix.Message) (*newOrderRequest, quickfix.MessageRejectError, error) {
+	request := &newOrderRequest{}
+
+	var rejectErr quickfix.MessageRejectError
+	if request.clOrdId, rejectErr = getRequiredString(msg.Body.FieldMap, tag.ClOrdID); rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	side, rejectErr := getRequiredString(msg.Body.FieldMap, tag.Side)
+	if rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+	request.side = enum.Side(side)
+
+	if request.symbol, rejectErr = getRequiredString(msg.Body.FieldMap, tag.Symbol); rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	if request.mic, rejectErr = getOptionalString(msg.Body.FieldMap, tag.SecurityExchange); rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	if request.destination, rejectErr = getOptionalString(msg.Body.FieldMap, tag.ExDestination); rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	if request.quantity, rejectErr = getRequiredDecimal(msg.Body.FieldMap, tag.OrderQty); rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	if request.price, rejectErr = getOptionalDecimal(msg.Body.FieldMap, tag.Price); rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	ordType, rejectErr := getRequiredString(msg.Body.FieldMap, tag.OrdType)
+	if rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	timeInForce, rejectErr := getOptionalString(msg.Body.FieldMap, tag.TimeInForce)
+	if rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	stopPx, rejectErr := getOptionalDecimal(msg.Body.FieldMap, tag.StopPx)
+	if rejectErr != nil {
+		return nil, rejectErr, nil
+	}
+
+	var expireTime *time.Time
+	if msg.Body.Has(tag.ExpireTime) {
+		var expireTimeField field.ExpireTimeField
+		if rejectErr = msg.Body.Get(&expireTimeField); rejectErr != nil {
+			return nil, rejectErr, nil
+		}
+		expireTime = &expireTimeField.Time
+	}
+
+	if request.side != enum.Side_BUY && request.side != enum.Side_SELL {
+		return nil, nil, newRequestError("side %v is not supported", request.side)
+	}
+
+	if !request.quantity.IsPositive() {
+		return nil, nil, newRequestError("order quantity must be greater than zero")
+	}
+
+	if strings.Contains(request.symbol, "'") || strings.Contains(request.mic, "'") {
+		return nil, nil, newRequestError("invalid symbol or security exchange")
+	}
+
+	params, err := getExecutionParameters(enum.OrdType(ordType), enum.TimeInForce(timeInForce), stopPx, expireTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.params = params
+
+	if (params.OrderType == "LIMIT" || params.OrderType == "STOP_LIMIT") && request.price == nil {
+		return nil, nil, newRequestError("price is required for order type %v", params.OrderType)
+	}
+
+	return request, nil, nil
+}
+
+func getExecutionParameters(ordType enum.OrdType, timeInForce enum.TimeInForce, stopPx *decimal.Decimal,
+	expireTime *time.Time) (executionParameters, error) {
+
+	params := executionParameters{}
+
+	switch ordType {
+	case enum.OrdType_MARKET:
+		params.OrderType = "MARKET"
+	case enum.OrdType_LIMIT:
+		params.OrderType = "LIMIT"
+	case enum.OrdType_STOP:
+		params.OrderType = "STOP"
+	case enum.OrdType_STOP_LIMIT:
+		params.OrderType = "STOP_LIMIT"
+	default:
+		return params, newRequestError("order type %v is not supported", ordType)
+	}
+
+	if params.OrderType == "STOP" || params.OrderType == "STOP_LIMIT" {
+		if stopPx == nil {
+			return params, newRequestError("stop price is required for order type %v", params.OrderType)
+		}
+		params.StopPrice, _ = stopPx.Float64()
+	}
+
+	switch timeInForce {
+	case "", enum.TimeInForce_DAY:
+		params.TimeInForce = "DAY"
+	case enum.TimeInForce_IMMEDIATE_OR_CANCEL:
+		params.TimeInForce = "IOC"
+	case enum.TimeInForce_FILL_OR_KILL:
+		params.TimeInForce = "FOK"
+	case enum.TimeInForce_GOOD_TILL_DATE:
+		if expireTime == nil {
+			return params, newRequestError("expire time is required for time in force %v", timeInForce)
+		}
+		params.TimeInForce = "GTD"
+		params.UtcExpireTimeSecs = expireTime.Unix()
+	default:
+		return params, newRequestError("time in force %v is not supported", timeInForce)
+	}
+
+	return params, nil
+}
+
+// cancelRequest is a parsed OrderCancelRequest or OrderCancelReplaceRequest, the quantity and price are only set for
+// a replace request.
+type cancelRequest struct {
+	clOrdId     string
+	origClOrdId string
+	quantity    decimal.Decimal
+	price       *decimal.Decimal
+}
+
+func parseCancelRequest(msg *quickfix.Message, isReplace bool) (*cancelRequest, quickfix.MessageRejectError) {
+	request := &cancelRequest{}
+
+	var rejectErr quickfix.MessageRejectError
+	if request.clOrdId, rejectErr = getRequiredString(msg.Body.FieldMap, tag.ClOrdID); rejectErr != nil {
+		return nil, rejectErr
+	}
+
+	if request.origClOrdId, rejectErr = getRequiredString(msg.Body.FieldMap, tag.OrigClOrdID); rejectErr != nil {
+		return nil, rejectErr
+	}
+
+	if isReplace {
+		if request.quantity, rejectErr = getRequiredDecimal(msg.Body.FieldMap, tag.OrderQty); rejectErr != nil {
+			return nil, rejectErr
+		}
+
+		if request.price, rejectErr = getOptionalDecimal(msg.Body.FieldMap, tag.Price); rejectErr != nil {
+			return nil, rejectErr
+		}
+	}
+
+	return request, nil
+}
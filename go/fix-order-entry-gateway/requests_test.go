@@ -0,0 +1,114 @@
+package main
+
+import (
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"github.com/quickfixgo/quickfix/field"
+	"github.com/quickfixgo/quickfix/tag"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestNewOrderSingle(clOrdId string, ordType enum.OrdType, price *decimal.Decimal) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_SINGLE))
+	if clOrdId != "" {
+		msg.Body.Set(field.NewClOrdID(clOrdId))
+	}
+	msg.Body.Set(field.NewSide(enum.Side_BUY))
+	msg.Body.Set(field.NewSymbol("AAPL"))
+	msg.Body.Set(field.NewSecurityExchange("XNAS"))
+	msg.Body.Set(field.NewOrderQty(decimal.New(100, 0), 0))
+	msg.Body.Set(field.NewOrdType(ordType))
+	if price != nil {
+		msg.Body.Set(field.NewPrice(*price, 2))
+	}
+	msg.Body.Set(field.NewTransactTime(time.Now()))
+	return msg
+}
+
+func TestParseNewOrderSingle(t *testing.T) {
+	price := decimal.RequireFromString("101.25")
+	request, rejectErr, err := parseNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price))
+	assert.Nil(t, rejectErr)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "c1", request.clOrdId)
+	assert.Equal(t, enum.Side_BUY, request.side)
+	assert.Equal(t, "AAPL", request.symbol)
+	assert.Equal(t, "XNAS", request.mic)
+	assert.True(t, decimal.New(100, 0).Equal(request.quantity))
+	assert.True(t, price.Equal(*request.price))
+	assert.Equal(t, executionParameters{OrderType: "LIMIT", TimeInForce: "DAY"}, request.params)
+
+	paramsJson, err := request.params.toJson()
+	assert.NoError(t, err)
+	assert.Equal(t, "", paramsJson)
+}
+
+func TestParseNewOrderSingleRejections(t *testing.T) {
+	price := decimal.New(100, 0)
+
+	_, rejectErr, _ := parseNewOrderSingle(newTestNewOrderSingle("", enum.OrdType_LIMIT, &price))
+	if assert.NotNil(t, rejectErr) {
+		assert.Equal(t, tag.ClOrdID, *rejectErr.RefTagID())
+	}
+
+	_, rejectErr, err := parseNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_LIMIT, nil))
+	assert.Nil(t, rejectErr)
+	assert.IsType(t, requestError{}, err)
+
+	_, rejectErr, err = parseNewOrderSingle(newTestNewOrderSingle("c1", enum.OrdType_PEGGED, &price))
+	assert.Nil(t, rejectErr)
+	assert.IsType(t, requestError{}, err)
+
+	zeroQty := newTestNewOrderSingle("c1", enum.OrdType_LIMIT, &price)
+	zeroQty.Body.Set(field.NewOrderQty(decimal.Zero, 0))
+	_, rejectErr, err = parseNewOrderSingle(zeroQty)
+	assert.Nil(t, rejectErr)
+	assert.IsType(t, requestError{}, err)
+}
+
+func TestGetExecutionParameters(t *testing.T) {
+	stopPx := decimal.RequireFromString("99.5")
+	expireTime := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name        string
+		ordType     enum.OrdType
+		timeInForce enum.TimeInForce
+		stopPx      *decimal.Decimal
+		expireTime  *time.Time
+		json        string
+		wantErr     bool
+	}{
+		{name: "limit day", ordType: enum.OrdType_LIMIT, timeInForce: enum.TimeInForce_DAY, json: ""},
+		{name: "market ioc", ordType: enum.OrdType_MARKET, timeInForce: enum.TimeInForce_IMMEDIATE_OR_CANCEL,
+			json: `{"orderType":"MARKET","timeInForce":"IOC"}`},
+		{name: "stop limit gtd", ordType: enum.OrdType_STOP_LIMIT, timeInForce: enum.TimeInForce_GOOD_TILL_DATE,
+			stopPx: &stopPx, expireTime: &expireTime,
+			json: `{"orderType":"STOP_LIMIT","timeInForce":"GTD","stopPrice":99.5,"utcExpireTimeSecs":1700000000}`},
+		{name: "stop without stop price", ordType: enum.OrdType_STOP, wantErr: true},
+		{name: "gtd without expire time", ordType: enum.OrdType_LIMIT, timeInForce: enum.TimeInForce_GOOD_TILL_DATE,
+			wantErr: true},
+		{name: "unsupported time in force", ordType: enum.OrdType_LIMIT,
+			timeInForce: enum.TimeInForce_GOOD_TILL_CANCEL, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params, err := getExecutionParameters(test.ordType, test.timeInForce, test.stopPx, test.expireTime)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			paramsJson, err := params.toJson()
+			assert.NoError(t, err)
+			assert.Equal(t, test.json, paramsJson)
+		})
+	}
+}
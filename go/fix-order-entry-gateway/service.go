@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	common "github.com/ettec/otp-common"
+	"github.com/ettec/otp-common/api"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/orderstore"
+	"github.com/ettec/otp-common/staticdata"
+	_ "github.com/lib/pq"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/enum"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	maxConnectRetry := time.Duration(bootstrap.GetOptionalIntEnvVar("MAX_CONNECT_RETRY_SECONDS", 60)) * time.Second
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+	senderCompID := bootstrap.GetOptionalEnvVar("FIX_SENDER_COMP_ID", "OTPORDERENTRY")
+	acceptPort := bootstrap.GetOptionalEnvVar("FIX_SOCKET_ACCEPT_PORT", "9879")
+	fileLogPath := bootstrap.GetEnvVar("FIX_LOG_FILE_PATH")
+	fileStorePath := bootstrap.GetEnvVar("FIX_FILE_STORE_PATH")
+	requestTimeout := time.Duration(bootstrap.GetOptionalIntEnvVar("ORDER_REQUEST_TIMEOUT_SECS", 10)) * time.Second
+	listingLookupTimeout := time.Duration(bootstrap.GetOptionalIntEnvVar("LISTING_LOOKUP_TIMEOUT_SECS", 5)) * time.Second
+
+	sessions, err := parseSessions(senderCompID, bootstrap.GetEnvVar("ORDER_ENTRY_SESSIONS"))
+	if err != nil {
+		log.Panicf("failed to parse order entry sessions: %v", err)
+	}
+
+	tradingUsers := getTradingUsers()
+	for _, session := range sessions {
+		if !tradingUsers[session.user] {
+			slog.Warn("session user does not have trading permissions, order requests from the session will be rejected",
+				"sessionID", session.sessionID.String(), "user", session.user)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sds, err := staticdata.NewStaticDataSource(ctx)
+	if err != nil {
+		log.Panicf("failed to create static data source:%v", err)
+	}
+
+	orderRouter, err := api.GetOrderRouter(k8s.GetK8sClientSet(false), maxConnectRetry)
+	if err != nil {
+		log.Panicf("failed to get order router: %v", err)
+	}
+
+	id, err := os.Hostname()
+	if err != nil {
+		log.Panicf("failed to get hostname: %v", err)
+	}
+
+	orderStore, err := orderstore.NewKafkaStore(orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers),
+		orderstore.DefaultWriterConfig(common.ORDERS_TOPIC, kafkaBrokers), id)
+	if err != nil {
+		log.Panicf("failed to create order store: %v", err)
+	}
+
+	if err := os.MkdirAll(fileStorePath, 0755); err != nil {
+		log.Panicf("failed to create file store directory: %v", err)
+	}
+
+	clientOrders, err := newClientOrderStore(filepath.Join(fileStorePath, "clientorders"))
+	if err != nil {
+		log.Panicf("failed to create client order store: %v", err)
+	}
+	defer func() {
+		if err := clientOrders.close(); err != nil {
+			slog.Error("failed to close client order store", "error", err)
+		}
+	}()
+
+	gateway := newOrderEntryGateway(ctx, sessions, orderRouter,
+		&staticDataListings{source: sds, lookupTimeout: listingLookupTimeout}, tradingUsers, clientOrders,
+		quickfix.SendToTarget, requestTimeout)
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	initialOrders, orderUpdates, err := orderStore.SubscribeToAllOrders(ctx, startOfDay,
+		bootstrap.GetOptionalIntEnvVar("ORDER_UPDATES_BUFFER_SIZE", 1000))
+	if err != nil {
+		log.Panicf("failed to subscribe to order updates: %v", err)
+	}
+
+	fixConfig := getFixConfig(sessions, acceptPort, fileLogPath, fileStorePath)
+	slog.Info("Creating fix engine", "config", fixConfig)
+
+	appSettings, err := quickfix.ParseSettings(strings.NewReader(fixConfig))
+	if err != nil {
+		log.Panicf("failed parse config: %v", err)
+	}
+
+	storeFactory := quickfix.NewFileStoreFactory(appSettings)
+	logFactory, err := quickfix.NewFileLogFactory(appSettings)
+	if err != nil {
+		log.Panicf("failed to create logFactory: %v", err)
+	}
+
+	acceptor, err := quickfix.NewAcceptor(newOrderEntryApplication(gateway), storeFactory, appSettings, logFactory)
+	if err != nil {
+		log.Panicf("failed to create acceptor: %v", err)
+	}
+
+	if err = acceptor.Start(); err != nil {
+		log.Panicf("failed to start the fix engine: %v", err)
+	}
+	defer acceptor.Stop()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	slog.Info("Starting order entry gateway", "port", acceptPort, "sessions", len(sessions))
+
+	gateway.run(initialOrders, orderUpdates)
+}
+
+func getTradingUsers() map[string]bool {
+	db, err := sql.Open(bootstrap.GetEnvVar("DB_DRIVER_NAME"), bootstrap.GetEnvVar("DB_CONN_STRING"))
+	if err != nil {
+		log.Panicf("failed to open database connection: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			slog.Error("error when closing database connection", "error", err)
+		}
+	}()
+
+	tradingUsers, err := loadTradingUsers(db)
+	if err != nil {
+		log.Panicf("failed to load trading users: %v", err)
+	}
+
+	return tradingUsers
+}
+
+// parseSessions parses a comma separated list of sessions of the form
+// <beginString>:<targetCompID>:<originatorId>:<user>, e.g. "FIX.4.4:CLIENTA:clienta:trader1".
+func parseSessions(senderCompID string, sessionsString string) ([]clientSession, error) {
+	var sessions []clientSession
+	originators := map[originator]bool{}
+	for _, entry := range strings.Split(sessionsString, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) != 4 || fields[1] == "" || fields[2] == "" || fields[3] == "" {
+			return nil, fmt.Errorf("invalid session %q, expected <beginString>:<targetCompID>:<originatorId>:<user>",
+				entry)
+		}
+
+		beginString := fields[0]
+		if beginString != enum.BeginStringFIX44 && beginString != enum.BeginStringFIXT11 {
+			return nil, fmt.Errorf("invalid session %q, begin string must be %v or %v", entry,
+				enum.BeginStringFIX44, enum.BeginStringFIXT11)
+		}
+
+		o := originator{originatorId: fields[2], user: fields[3]}
+		if originators[o] {
+			return nil, fmt.Errorf("invalid session %q, the originator and user of each session must be unique", entry)
+		}
+		originators[o] = true
+
+		sessions = append(sessions, clientSession{
+			sessionID:    quickfix.SessionID{BeginString: beginString, SenderCompID: senderCompID, TargetCompID: fields[1]},
+			originatorId: o.originatorId,
+			user:         o.user,
+		})
+	}
+
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sessions configured")
+	}
+
+	return sessions, nil
+}
+
+func getFixConfig(sessions []clientSession, acceptPort string, fileLogPath string, fileStorePath string) string {
+
+	if tproot, exists := os.LookupEnv("TELEPRESENCE_ROOT"); exists {
+		fileLogPath = tproot + fileLogPath
+		fileStorePath = tproot + fileStorePath
+	}
+
+	template :=
+		"[DEFAULT]\n" +
+			"ConnectionType=acceptor\n" +
+			"SocketAcceptPort=" + acceptPort + "\n" +
+			"FileStorePath=" + fileStorePath + "\n" +
+			"FileLogPath=" + fileLogPath + "\n" +
+			"StartTime=00:00:00\n" +
+			"EndTime=00:00:00\n" +
+			"HeartBtInt=20\n"
+
+	for _, session := range sessions {
+		template +=
+			"\n" +
+				"[SESSION]\n" +
+				"BeginString=" + session.sessionID.BeginString + "\n" +
+				"SenderCompID=" + session.sessionID.SenderCompID + "\n" +
+				"TargetCompID=" + session.sessionID.TargetCompID + "\n"
+
+		if session.sessionID.BeginString == enum.BeginStringFIXT11 {
+			template += "DefaultApplVerID=FIX.5.0SP2\n"
+		}
+	}
+
+	return template
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/quickfixgo/quickfix"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSessions(t *testing.T) {
+	sessions, err := parseSessions("OTPORDERENTRY", "FIX.4.4:CLIENTA:clienta:trader1, FIXT.1.1:CLIENTB:clientb:trader2")
+	assert.NoError(t, err)
+	assert.Equal(t, []clientSession{
+		{sessionID: quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "OTPORDERENTRY", TargetCompID: "CLIENTA"},
+			originatorId: "clienta", user: "trader1"},
+		{sessionID: quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "OTPORDERENTRY", TargetCompID: "CLIENTB"},
+			originatorId: "clientb", user: "trader2"},
+	}, sessions)
+
+	for _, invalid := range []string{"", "FIX.4.4:CLIENTA", "FIX.4.4:CLIENTA:clienta:", "FIX.4.2:CLIENTA:clienta:trader1",
+		"FIX.4.4:CLIENTA:clienta:trader1,FIX.4.4:CLIENTB:clienta:trader1"} {
+		_, err := parseSessions("OTPORDERENTRY", invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// loadTradingUsers returns the set of users that have trading permissions.
+func loadTradingUsers(db *sql.DB) (map[string]bool, error) {
+	r, err := db.Query("SELECT id, permissionflags FROM users.users")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users from database: %w", err)
+	}
+	defer r.Close()
+
+	users := map[string]bool{}
+	for r.Next() {
+		var id, permissionFlags string
+		if err := r.Scan(&id, &permissionFlags); err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+
+		if strings.Contains(permissionFlags, "T") {
+			users[id] = true
+		}
+	}
+
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	return users, nil
+}
@@ -3,6 +3,7 @@ module github.com/ettec/open-trading-platform/go/market-data/market-data-gateway
 go 1.21
 
 require (
+	github.com/ettec/open-trading-platform/go/shared v0.0.0
 	github.com/ettec/otp-common v1.4.2
 	github.com/golang/protobuf v1.4.2
 	github.com/prometheus/client_golang v1.7.1
@@ -47,3 +48,5 @@ require (
 	k8s.io/utils v0.0.0-20191114184206-e782cd3c129f // indirect
 	sigs.k8s.io/yaml v1.1.0 // indirect
 )
+
+replace github.com/ettec/open-trading-platform/go/shared => ../../shared
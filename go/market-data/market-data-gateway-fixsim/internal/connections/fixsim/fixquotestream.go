@@ -8,6 +8,7 @@ import (
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/golang/protobuf/proto"
 	"log/slog"
+	"sync"
 )
 
 type GetListingFn func(ctx context.Context, listingId int32, resultChan chan<- staticdata.ListingResult)
@@ -20,6 +21,10 @@ type FixQuoteStream struct {
 	fixMarketDataClient  fixMarketDataClient
 	cancelCtx            func()
 	getListing           GetListingFn
+	unsubscribeChan      chan int32
+
+	mutex         sync.Mutex
+	subscriptions map[int32]bool
 }
 
 func (n *FixQuoteStream) Chan() <-chan *model.ClobQuote {
@@ -31,12 +36,43 @@ func (n *FixQuoteStream) Close() {
 }
 
 func (n *FixQuoteStream) Subscribe(listingId int32) error {
+	n.mutex.Lock()
+	n.subscriptions[listingId] = true
+	n.mutex.Unlock()
+
 	n.getListing(n.ctx, listingId, n.getListingResultChan)
 	return nil
 }
 
+// Unsubscribe releases the subscription to the listing's symbol on the fix simulator, any quote for the listing
+// already queued to the stream is not removed.
+func (n *FixQuoteStream) Unsubscribe(listingId int32) error {
+	n.mutex.Lock()
+	subscribed := n.subscriptions[listingId]
+	delete(n.subscriptions, listingId)
+	n.mutex.Unlock()
+
+	if !subscribed {
+		return nil
+	}
+
+	select {
+	case n.unsubscribeChan <- listingId:
+		return nil
+	case <-n.ctx.Done():
+		return n.ctx.Err()
+	}
+}
+
+func (n *FixQuoteStream) isSubscribed(listingId int32) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.subscriptions[listingId]
+}
+
 type fixMarketDataClient interface {
 	Subscribe(symbol string) error
+	Unsubscribe(symbol string) error
 	Chan() <-chan *marketdata.MarketDataIncrementalRefresh
 }
 
@@ -55,10 +91,13 @@ func NewQuoteStreamFromFixClient(parentCtx context.Context,
 		fixMarketDataClient:  fixMarketDataClient,
 		cancelCtx:            cancel,
 		getListing:           symbolLookup,
+		unsubscribeChan:      make(chan int32, 1000),
+		subscriptions:        map[int32]bool{},
 	}
 
 	log := slog.With(slog.Default(), "connectionName", connectionName)
 	symbolToListingId := make(map[string]int32)
+	listingIdToSymbol := make(map[int32]string)
 	idToQuote := map[int32]*model.ClobQuote{}
 
 	go func() {
@@ -68,19 +107,35 @@ func NewQuoteStreamFromFixClient(parentCtx context.Context,
 		for {
 			select {
 			case <-ctx.Done():
-				break
+				return
 			case lr := <-quoteStream.getListingResultChan:
 
 				if lr.Err != nil {
 					log.Error("failed to get listing", "error", lr.Err)
 					continue
 				}
+
+				if !quoteStream.isSubscribed(lr.Listing.Id) {
+					continue
+				}
+
 				symbolToListingId[lr.Listing.MarketSymbol] = lr.Listing.Id
-				go func() {
-					if err := quoteStream.fixMarketDataClient.Subscribe(lr.Listing.MarketSymbol); err != nil {
-						log.Error("failed to subscribe", "error", err)
-					}
-				}()
+				listingIdToSymbol[lr.Listing.Id] = lr.Listing.MarketSymbol
+				if err := quoteStream.fixMarketDataClient.Subscribe(lr.Listing.MarketSymbol); err != nil {
+					log.Error("failed to subscribe", "error", err)
+				}
+			case listingId := <-quoteStream.unsubscribeChan:
+				symbol, ok := listingIdToSymbol[listingId]
+				if !ok || quoteStream.isSubscribed(listingId) {
+					continue
+				}
+
+				delete(listingIdToSymbol, listingId)
+				delete(symbolToListingId, symbol)
+				delete(idToQuote, listingId)
+				if err := quoteStream.fixMarketDataClient.Unsubscribe(symbol); err != nil {
+					log.Error("failed to unsubscribe", "symbol", symbol, "error", err)
+				}
 			case r, ok := <-quoteStream.fixMarketDataClient.Chan():
 				if !ok {
 					log.Warn("fix sim client closed")
@@ -57,14 +57,16 @@ func Test_QuoteClone(t *testing.T) {
 }
 
 type testFixClient struct {
-	refreshChan   chan *md.MarketDataIncrementalRefresh
-	subscribeChan chan string
+	refreshChan     chan *md.MarketDataIncrementalRefresh
+	subscribeChan   chan string
+	unsubscribeChan chan string
 }
 
 func newTestMarketDataClient() (*testFixClient, error) {
 	t := &testFixClient{
-		refreshChan:   make(chan *md.MarketDataIncrementalRefresh, 100),
-		subscribeChan: make(chan string, 100),
+		refreshChan:     make(chan *md.MarketDataIncrementalRefresh, 100),
+		subscribeChan:   make(chan string, 100),
+		unsubscribeChan: make(chan string, 100),
 	}
 	return t, nil
 }
@@ -78,6 +80,11 @@ func (t *testFixClient) Subscribe(symbol string) error {
 	return nil
 }
 
+func (t *testFixClient) Unsubscribe(symbol string) error {
+	t.unsubscribeChan <- symbol
+	return nil
+}
+
 func Test_quoteNormaliser_nilRefreshResetsAllQuote(t *testing.T) {
 	fixClient, quoteStream := setupTestClient(t)
 
@@ -147,6 +154,29 @@ func TestQuoteStreamInterruptedFlagResetOnUpdate(t *testing.T) {
 
 }
 
+func TestUnsubscribedListingsQuotesAreNotForwarded(t *testing.T) {
+	fixClient, quoteStream := setupTestClient(t)
+
+	assert.NoError(t, quoteStream.Subscribe(1))
+	assert.Equal(t, "A", <-fixClient.subscribeChan)
+
+	assert.NoError(t, quoteStream.Unsubscribe(1))
+	assert.Equal(t, "A", <-fixClient.unsubscribeChan)
+
+	assert.NoError(t, quoteStream.Unsubscribe(1))
+
+	assert.NoError(t, quoteStream.Subscribe(2))
+	assert.Equal(t, "B", <-fixClient.subscribeChan)
+
+	fixClient.refreshChan <- &md.MarketDataIncrementalRefresh{MdIncGrp: []*md.MDIncGrp{
+		getEntry(md.MDEntryTypeEnum_MD_ENTRY_TYPE_BID, md.MDUpdateActionEnum_MD_UPDATE_ACTION_NEW, 10, 5, "A"),
+		getEntry(md.MDEntryTypeEnum_MD_ENTRY_TYPE_BID, md.MDUpdateActionEnum_MD_UPDATE_ACTION_NEW, 10, 5, "B")}}
+
+	q := <-quoteStream.Chan()
+	assert.Equal(t, int32(2), q.ListingId)
+	assert.Empty(t, fixClient.unsubscribeChan)
+}
+
 func TestProcessingMDIncRefreshMessages(t *testing.T) {
 
 	fixClient, quoteStream := setupTestClient(t)
@@ -7,18 +7,55 @@ import (
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/metadata"
 	"log/slog"
+	"sync"
 )
 
+// fixSimMarketDataClient maintains a market data connection to the fix simulator.  Subscribe and Unsubscribe record
+// the symbols the client should be subscribed to and do not block, the subscriptions on the connection are then brought
+// in line with them by sending a market data request per changed symbol.
 type fixSimMarketDataClient struct {
-	subscriptionsChan chan string
-	out               chan *marketdata.MarketDataIncrementalRefresh
+	mutex         sync.Mutex
+	subscriptions map[string]bool
+	changed       chan struct{}
+	out           chan *marketdata.MarketDataIncrementalRefresh
 }
 
 func (fsc *fixSimMarketDataClient) Subscribe(symbol string) error {
-	fsc.subscriptionsChan <- symbol
+	fsc.setSubscribed(symbol, true)
 	return nil
 }
 
+func (fsc *fixSimMarketDataClient) Unsubscribe(symbol string) error {
+	fsc.setSubscribed(symbol, false)
+	return nil
+}
+
+func (fsc *fixSimMarketDataClient) setSubscribed(symbol string, subscribed bool) {
+	fsc.mutex.Lock()
+	if subscribed {
+		fsc.subscriptions[symbol] = true
+	} else {
+		delete(fsc.subscriptions, symbol)
+	}
+	fsc.mutex.Unlock()
+
+	select {
+	case fsc.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (fsc *fixSimMarketDataClient) getSubscriptions() map[string]bool {
+	fsc.mutex.Lock()
+	defer fsc.mutex.Unlock()
+
+	result := make(map[string]bool, len(fsc.subscriptions))
+	for symbol := range fsc.subscriptions {
+		result[symbol] = true
+	}
+	return result
+}
+
 func (fsc *fixSimMarketDataClient) Chan() <-chan *marketdata.MarketDataIncrementalRefresh {
 	return fsc.out
 }
@@ -28,52 +65,74 @@ type GrpcConnection interface {
 	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
 }
 
+func newMarketDataRequest(id string, symbol string, requestType marketdata.SubscriptionRequestTypeEnum) *marketdata.MarketDataRequest {
+	return &marketdata.MarketDataRequest{Parties: []*common.Parties{{PartyId: id}},
+		SubscriptionRequestType: requestType,
+		InstrmtMdReqGrp:         []*common.InstrmtMDReqGrp{{Instrument: &common.Instrument{Symbol: symbol}}}}
+}
+
+// syncSubscriptions sends a subscribe request for each symbol subscribed to that has not been requested on the stream
+// and a request with a subscription request type of disable previous snapshot, FIX value 2, for each symbol requested
+// on the stream that is no longer subscribed to.
+func syncSubscriptions(id string, stream FixSimMarketDataService_ConnectClient, requested map[string]bool,
+	subscriptions map[string]bool) {
+
+	for symbol := range subscriptions {
+		if !requested[symbol] {
+			err := stream.Send(newMarketDataRequest(id, symbol,
+				marketdata.SubscriptionRequestTypeEnum_SUBSCRIPTION_REQUEST_TYPE_SNAPSHOT_AND_UPDATES))
+			if err != nil {
+				slog.Error("failed to subscribe to quote", "symbol", symbol, "error", err)
+				return
+			}
+			requested[symbol] = true
+		}
+	}
+
+	for symbol := range requested {
+		if !subscriptions[symbol] {
+			err := stream.Send(newMarketDataRequest(id, symbol,
+				marketdata.SubscriptionRequestTypeEnum_SUBSCRIPTION_REQUEST_TYPE_DISABLE_PREVIOUS_SNAPSHOT))
+			if err != nil {
+				slog.Error("failed to unsubscribe from quote", "symbol", symbol, "error", err)
+				return
+			}
+			delete(requested, symbol)
+		}
+	}
+}
+
 func NewFixSimMarketDataClient(ctx context.Context, id string, client FixSimMarketDataServiceClient, conn GrpcConnection,
 	outBufferSize int) (*fixSimMarketDataClient, error) {
 
 	mdClient := &fixSimMarketDataClient{
-		subscriptionsChan: make(chan string, 100),
-		out:               make(chan *marketdata.MarketDataIncrementalRefresh, outBufferSize),
+		subscriptions: map[string]bool{},
+		changed:       make(chan struct{}, 1),
+		out:           make(chan *marketdata.MarketDataIncrementalRefresh, outBufferSize),
 	}
 
 	streamChan := make(chan FixSimMarketDataService_ConnectClient, 1)
 
 	go func() {
-		subscriptions := map[string]bool{}
-
 		var stream FixSimMarketDataService_ConnectClient
+		var requested map[string]bool
 		for {
 
 			select {
+			case <-ctx.Done():
+				return
 			case newStream := <-streamChan:
 				stream = newStream
+				requested = map[string]bool{}
 				if stream != nil {
 					slog.Info("new stream connected, resubscribing to all listings")
-					for symbol := range subscriptions {
-						err := stream.Send(&marketdata.MarketDataRequest{Parties: []*common.Parties{{PartyId: id}},
-							InstrmtMdReqGrp: []*common.InstrmtMDReqGrp{{Instrument: &common.Instrument{Symbol: symbol}}}})
-
-						if err != nil {
-							slog.Error("failed to resubscribe to quote", "symbol", symbol, "error", err)
-							break
-						}
-					}
-
-					slog.Info("resubscribed to all quotes", "numSubscriptions", len(subscriptions))
+					syncSubscriptions(id, stream, requested, mdClient.getSubscriptions())
+					slog.Info("resubscribed to all quotes", "numSubscriptions", len(requested))
 				}
-			case symbol := <-mdClient.subscriptionsChan:
-				if !subscriptions[symbol] {
-					subscriptions[symbol] = true
-					if stream != nil {
-						err := stream.Send(&marketdata.MarketDataRequest{Parties: []*common.Parties{{PartyId: id}},
-							InstrmtMdReqGrp: []*common.InstrmtMDReqGrp{{Instrument: &common.Instrument{Symbol: symbol}}}})
-
-						if err != nil {
-							slog.Error("failed so subscribe to quote", "symbol", symbol, "error", err)
-						}
-					}
+			case <-mdClient.changed:
+				if stream != nil {
+					syncSubscriptions(id, stream, requested, mdClient.getSubscriptions())
 				}
-
 			}
 		}
 
@@ -103,6 +103,36 @@ func TestResubscribesToListingsOnConnect(t *testing.T) {
 
 }
 
+func TestUnsubscribeSendsADisablePreviousSnapshotRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, stream, conn, toTest := setup(t, ctx)
+
+	conn.getStateChan <- connectivity.Ready
+
+	client.streamOutChan <- stream
+
+	assert.NoError(t, toTest.Subscribe("A"))
+
+	s := <-stream.subsInChan
+	assert.Equal(t, "A", s.InstrmtMdReqGrp[0].Instrument.Symbol)
+	assert.Equal(t, marketdata.SubscriptionRequestTypeEnum_SUBSCRIPTION_REQUEST_TYPE_SNAPSHOT_AND_UPDATES, s.SubscriptionRequestType)
+
+	assert.NoError(t, toTest.Unsubscribe("A"))
+
+	s = <-stream.subsInChan
+	assert.Equal(t, "testId", s.Parties[0].PartyId)
+	assert.Equal(t, "A", s.InstrmtMdReqGrp[0].Instrument.Symbol)
+	assert.Equal(t, marketdata.SubscriptionRequestTypeEnum_SUBSCRIPTION_REQUEST_TYPE_DISABLE_PREVIOUS_SNAPSHOT, s.SubscriptionRequestType)
+
+	assert.NoError(t, toTest.Unsubscribe("A"))
+	assert.NoError(t, toTest.Subscribe("B"))
+
+	s = <-stream.subsInChan
+	assert.Equal(t, "B", s.InstrmtMdReqGrp[0].Instrument.Symbol)
+}
+
 func setup(t *testing.T, ctx context.Context) (testClient, testClientStream, testConnection, *fixSimMarketDataClient) {
 
 	client := testClient{
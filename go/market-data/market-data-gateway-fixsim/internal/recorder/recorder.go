@@ -0,0 +1,77 @@
+package recorder
+
+import (
+	"fmt"
+	"github.com/ettec/open-trading-platform/go/shared/recording"
+	"github.com/ettec/otp-common/model"
+	"os"
+	"time"
+)
+
+// Recorder records quotes to a file per UTC day in a directory, a day's file is appended to if it already exists.
+type Recorder struct {
+	dir    string
+	day    string
+	file   *os.File
+	writer *recording.Writer
+}
+
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory %v: %w", dir, err)
+	}
+
+	return &Recorder{dir: dir}, nil
+}
+
+func (r *Recorder) Record(timestamp time.Time, quote *model.ClobQuote) error {
+	if day := timestamp.UTC().Format("2006-01-02"); day != r.day {
+		if err := r.Close(); err != nil {
+			return err
+		}
+
+		fileName := recording.FileName(r.dir, timestamp)
+		file, err := os.OpenFile(fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open recording file %v: %w", fileName, err)
+		}
+
+		r.day = day
+		r.file = file
+		r.writer = recording.NewWriter(file)
+	}
+
+	return r.writer.Write(timestamp, quote)
+}
+
+func (r *Recorder) Flush() error {
+	if r.writer == nil {
+		return nil
+	}
+
+	return r.writer.Flush()
+}
+
+// Close completes the current day's file, a later record reopens the day's file.
+func (r *Recorder) Close() error {
+	if r.file == nil {
+		return nil
+	}
+
+	defer func() {
+		r.day = ""
+		r.file = nil
+		r.writer = nil
+	}()
+
+	if err := r.writer.Close(); err != nil {
+		r.file.Close()
+		return fmt.Errorf("failed to close recording writer: %w", err)
+	}
+
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close recording file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package recorder
+
+import (
+	"context"
+	"github.com/ettec/open-trading-platform/go/shared/recording"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+type testQuoteStream struct {
+	quotes     chan *model.ClobQuote
+	subscribed chan int32
+}
+
+func (t *testQuoteStream) Subscribe(listingId int32) error {
+	t.subscribed <- listingId
+	return nil
+}
+
+func (t *testQuoteStream) Unsubscribe(int32) error {
+	return nil
+}
+
+func (t *testQuoteStream) Chan() <-chan *model.ClobQuote {
+	return t.quotes
+}
+
+func (t *testQuoteStream) Close() {
+}
+
+func readRecording(t *testing.T, fileName string) []recording.Record {
+	file, err := os.Open(fileName)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	reader, err := recording.NewReader(file)
+	assert.NoError(t, err)
+
+	var records []recording.Record
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records
+		}
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestRecorderAppendsToTheDaysFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "recording")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	day := time.Date(2020, 3, 2, 10, 0, 0, 123, time.UTC)
+
+	recorder, err := NewRecorder(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.Record(day, &model.ClobQuote{ListingId: 1}))
+	assert.NoError(t, recorder.Record(day.Add(24*time.Hour), &model.ClobQuote{ListingId: 2}))
+	assert.NoError(t, recorder.Close())
+
+	recorder, err = NewRecorder(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.Record(day.Add(time.Second), &model.ClobQuote{ListingId: 3}))
+	assert.NoError(t, recorder.Close())
+
+	records := readRecording(t, recording.FileName(dir, day))
+	assert.Len(t, records, 2)
+	assert.Equal(t, day.UnixNano(), records[0].Timestamp.UnixNano())
+	assert.Equal(t, int32(1), records[0].Quote.ListingId)
+	assert.Equal(t, day.Add(time.Second).UnixNano(), records[1].Timestamp.UnixNano())
+	assert.Equal(t, int32(3), records[1].Quote.ListingId)
+
+	records = readRecording(t, recording.FileName(dir, day.Add(24*time.Hour)))
+	assert.Len(t, records, 1)
+	assert.Equal(t, int32(2), records[0].Quote.ListingId)
+}
+
+func TestRecordingQuoteStreamRecordsAndForwardsQuotes(t *testing.T) {
+	dir, err := os.MkdirTemp("", "recording")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	recorder, err := NewRecorder(dir)
+	assert.NoError(t, err)
+
+	source := &testQuoteStream{quotes: make(chan *model.ClobQuote, 10), subscribed: make(chan int32, 10)}
+	stream := NewRecordingQuoteStream(context.Background(), source, recorder, 10)
+
+	assert.NoError(t, stream.Subscribe(1))
+	assert.Equal(t, int32(1), <-source.subscribed)
+
+	before := time.Now()
+	source.quotes <- &model.ClobQuote{ListingId: 1, StreamStatusMsg: "a"}
+	source.quotes <- &model.ClobQuote{ListingId: 1, StreamStatusMsg: "b"}
+	assert.Equal(t, "a", (<-stream.Chan()).StreamStatusMsg)
+	assert.Equal(t, "b", (<-stream.Chan()).StreamStatusMsg)
+
+	close(source.quotes)
+	_, ok := <-stream.Chan()
+	assert.False(t, ok)
+
+	records := readRecording(t, recording.FileName(dir, before))
+	assert.Len(t, records, 2)
+	assert.Equal(t, "a", records[0].Quote.StreamStatusMsg)
+	assert.Equal(t, "b", records[1].Quote.StreamStatusMsg)
+	assert.False(t, records[0].Timestamp.Before(before))
+}
@@ -0,0 +1,92 @@
+package recorder
+
+import (
+	"context"
+	md "github.com/ettec/open-trading-platform/go/shared/marketdata"
+	"github.com/ettec/otp-common/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"time"
+)
+
+var quotesRecorded = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "quotes_recorded",
+	Help: "The number of quotes recorded",
+})
+
+const flushInterval = time.Second
+
+type recordingQuoteStream struct {
+	stream md.QuoteStream
+	out    chan *model.ClobQuote
+	cancel context.CancelFunc
+}
+
+// NewRecordingQuoteStream returns a quote stream that records each quote of the given stream, timestamped with the time
+// it was received, before passing it on.  A quote that fails to be recorded is still passed on.  The recorder is closed
+// before the stream's channel is closed, once the stream is closed or the given stream's channel is closed.
+func NewRecordingQuoteStream(parentCtx context.Context, stream md.QuoteStream, recorder *Recorder,
+	bufferSize int) md.QuoteStream {
+
+	ctx, cancel := context.WithCancel(parentCtx)
+	out := make(chan *model.ClobQuote, bufferSize)
+
+	go func() {
+		defer close(out)
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				slog.Error("failed to close recorder", "error", err)
+			}
+		}()
+
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := recorder.Flush(); err != nil {
+					slog.Error("failed to flush recorder", "error", err)
+				}
+			case quote, ok := <-stream.Chan():
+				if !ok {
+					return
+				}
+
+				if err := recorder.Record(time.Now(), quote); err != nil {
+					slog.Error("failed to record quote", "listingId", quote.ListingId, "error", err)
+				} else {
+					quotesRecorded.Inc()
+				}
+
+				select {
+				case out <- quote:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return &recordingQuoteStream{stream: stream, out: out, cancel: cancel}
+}
+
+func (r *recordingQuoteStream) Subscribe(listingId int32) error {
+	return r.stream.Subscribe(listingId)
+}
+
+func (r *recordingQuoteStream) Unsubscribe(listingId int32) error {
+	return r.stream.Unsubscribe(listingId)
+}
+
+func (r *recordingQuoteStream) Chan() <-chan *model.ClobQuote {
+	return r.out
+}
+
+func (r *recordingQuoteStream) Close() {
+	r.stream.Close()
+	r.cancel()
+}
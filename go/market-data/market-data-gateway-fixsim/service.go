@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
-	"github.com/ettec/otp-common/api/marketdatasource"
+	"github.com/ettec/open-trading-platform/go/shared/api/marketdatasource"
 	"github.com/ettec/otp-common/bootstrap"
 	"log/slog"
 	"os"
@@ -11,7 +11,8 @@ import (
 	"syscall"
 
 	"github.com/ettec/open-trading-platform/go/market-data/market-data-gateway-fixsim/internal/connections/fixsim"
-	md "github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/open-trading-platform/go/market-data/market-data-gateway-fixsim/internal/recorder"
+	md "github.com/ettec/open-trading-platform/go/shared/marketdata"
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
@@ -24,7 +25,7 @@ import (
 )
 
 func newService(ctx context.Context, id string, fixSimAddress string, maxReconnectInterval time.Duration,
-	inboundQuoteBufferSize int, clientQuoteBufferSize int) (marketdatasource.MarketDataSourceServer, error) {
+	inboundQuoteBufferSize int, clientQuoteBufferSize int, recordingDir string) (marketdatasource.MarketDataSourceServer, error) {
 
 	listingSrc, err := staticdata.NewStaticDataSource(ctx)
 	if err != nil {
@@ -43,15 +44,26 @@ func newService(ctx context.Context, id string, fixSimAddress string, maxReconne
 		return nil, fmt.Errorf("failed to create fix simulator market data client: %w", err)
 	}
 
-	fixSimQuoteStream, err := fixsim.NewQuoteStreamFromFixClient(ctx, fixSimClient, id, listingSrc.GetListing,
+	var fixSimQuoteStream md.QuoteStream
+	fixSimQuoteStream, err = fixsim.NewQuoteStreamFromFixClient(ctx, fixSimClient, id, listingSrc.GetListing,
 		inboundQuoteBufferSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fix quote stream: %w", err)
 	}
 
+	if recordingDir != "" {
+		quoteRecorder, err := recorder.NewRecorder(recordingDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create quote recorder: %w", err)
+		}
+
+		slog.Info("recording quotes", "recordingDir", recordingDir)
+		fixSimQuoteStream = recorder.NewRecordingQuoteStream(ctx, fixSimQuoteStream, quoteRecorder, inboundQuoteBufferSize)
+	}
+
 	qd := md.NewQuoteDistributor(ctx, fixSimQuoteStream, clientQuoteBufferSize)
 
-	s := md.NewMarketDataSource(qd)
+	s := md.NewMarketDataSource(qd, bootstrap.GetOptionalIntEnvVar("MARKETDATASOURCE_MAX_SUBSCRIPTIONS", 10000))
 
 	return s, nil
 }
@@ -65,6 +77,7 @@ func main() {
 	maxConnectRetrySecs := bootstrap.GetOptionalIntEnvVar("MAX_CONNECT_RETRY_SECONDS", 60)
 	inboundQuoteBufferSize := bootstrap.GetOptionalIntEnvVar("INBOUND_QUOTE_BUFFER_SIZE", 1000)
 	clientQuoteBufferSize := bootstrap.GetOptionalIntEnvVar("CLIENT_QUOTE_BUFFER_SIZE", 1000)
+	recordingDir := bootstrap.GetOptionalEnvVar("RECORDING_DIR", "")
 
 	port := "50551"
 	slog.Info("Starting Market Data Gateway", "port", port)
@@ -88,7 +101,7 @@ func main() {
 	defer cancel()
 
 	service, err := newService(ctx, id, fixSimAddress, time.Duration(maxConnectRetrySecs)*time.Second, inboundQuoteBufferSize,
-		clientQuoteBufferSize)
+		clientQuoteBufferSize, recordingDir)
 	if err != nil {
 		log.Panicf("error creating service: %v", err)
 	}
@@ -0,0 +1,241 @@
+// Package replay replays recorded quote streams.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ettec/open-trading-platform/go/shared/recording"
+	"github.com/ettec/otp-common/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+var quotesReplayed = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "quotes_replayed",
+	Help: "The number of recorded quotes replayed",
+})
+
+// Replayer is a quote stream of the quotes of a sequence of recordings.  The replay starts when the first listing is
+// subscribed to and runs at the given multiple of the recorded speed, a speed of zero replays the recordings as fast as
+// the stream is read.  The time between the end of one recording and the start of the next is not replayed.
+//
+// The latest replayed quote of every listing is kept, subscribing to a listing sends its latest quote before any later
+// quote of the listing.  Once the last recording has been replayed the stream stays open and a listing subscribed to
+// is sent its final quote.
+type Replayer struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	fileNames []string
+	speed     float64
+	out       chan *model.ClobQuote
+
+	mutex            sync.Mutex
+	subscriptions    map[int32]bool
+	pendingSnapshots []int32
+	subscribed       chan struct{}
+}
+
+func NewReplayer(parentCtx context.Context, fileNames []string, speed float64, bufferSize int) (*Replayer, error) {
+	if speed < 0 {
+		return nil, fmt.Errorf("replay speed must not be negative, speed: %v", speed)
+	}
+
+	ctx, cancel := context.WithCancel(parentCtx)
+
+	r := &Replayer{
+		ctx:           ctx,
+		cancel:        cancel,
+		fileNames:     fileNames,
+		speed:         speed,
+		out:           make(chan *model.ClobQuote, bufferSize),
+		subscriptions: map[int32]bool{},
+		subscribed:    make(chan struct{}, 1),
+	}
+
+	go r.run()
+
+	return r, nil
+}
+
+func (r *Replayer) Subscribe(listingId int32) error {
+	r.mutex.Lock()
+	if !r.subscriptions[listingId] {
+		r.subscriptions[listingId] = true
+		r.pendingSnapshots = append(r.pendingSnapshots, listingId)
+	}
+	r.mutex.Unlock()
+
+	select {
+	case r.subscribed <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (r *Replayer) Unsubscribe(listingId int32) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.subscriptions, listingId)
+	return nil
+}
+
+func (r *Replayer) Chan() <-chan *model.ClobQuote {
+	return r.out
+}
+
+func (r *Replayer) Close() {
+	r.cancel()
+}
+
+func (r *Replayer) run() {
+	lastQuotes := map[int32]*model.ClobQuote{}
+
+	select {
+	case <-r.ctx.Done():
+		return
+	case <-r.subscribed:
+	}
+
+	for _, fileName := range r.fileNames {
+		slog.Info("replaying recording", "fileName", fileName)
+		if err := r.replayFile(fileName, lastQuotes); err != nil {
+			if r.ctx.Err() != nil {
+				return
+			}
+			slog.Error("failed to replay recording", "fileName", fileName, "error", err)
+		}
+	}
+
+	slog.Info("replay complete")
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-r.subscribed:
+			snapshots, _ := r.takePendingSnapshots(0)
+			if !r.sendSnapshots(snapshots, lastQuotes) {
+				return
+			}
+		}
+	}
+}
+
+func (r *Replayer) replayFile(fileName string, lastQuotes map[int32]*model.ClobQuote) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := recording.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var replayStart, recordingStart time.Time
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			slog.Warn("recording ends part way through a record", "fileName", fileName)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read record: %w", err)
+		}
+
+		if r.speed > 0 {
+			if recordingStart.IsZero() {
+				recordingStart = record.Timestamp
+				replayStart = time.Now()
+			}
+
+			replayAt := replayStart.Add(time.Duration(float64(record.Timestamp.Sub(recordingStart)) / r.speed))
+			if !r.waitUntil(replayAt, lastQuotes) {
+				return r.ctx.Err()
+			}
+		}
+
+		quote := record.Quote
+		snapshots, subscribed := r.takePendingSnapshots(quote.ListingId)
+		if !r.sendSnapshots(snapshots, lastQuotes) {
+			return r.ctx.Err()
+		}
+
+		lastQuotes[quote.ListingId] = quote
+		quotesReplayed.Inc()
+
+		if subscribed && !r.send(quote) {
+			return r.ctx.Err()
+		}
+	}
+}
+
+// waitUntil sends the snapshots of listings subscribed to whilst waiting, it returns false if the replayer is closed.
+func (r *Replayer) waitUntil(t time.Time, lastQuotes map[int32]*model.ClobQuote) bool {
+	for {
+		wait := time.Until(t)
+		if wait <= 0 {
+			return true
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return false
+		case <-r.subscribed:
+			timer.Stop()
+			snapshots, _ := r.takePendingSnapshots(0)
+			if !r.sendSnapshots(snapshots, lastQuotes) {
+				return false
+			}
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// takePendingSnapshots returns the listings waiting for their snapshot and whether the given listing is subscribed to,
+// both are read together so that a listing's quote is sent either as its snapshot or as an update but never as both.
+func (r *Replayer) takePendingSnapshots(listingId int32) ([]int32, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	snapshots := r.pendingSnapshots
+	r.pendingSnapshots = nil
+
+	return snapshots, r.subscriptions[listingId]
+}
+
+func (r *Replayer) sendSnapshots(listingIds []int32, lastQuotes map[int32]*model.ClobQuote) bool {
+	for _, listingId := range listingIds {
+		if quote, ok := lastQuotes[listingId]; ok {
+			if !r.send(quote) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// send returns false if the replayer is closed.
+func (r *Replayer) send(quote *model.ClobQuote) bool {
+	select {
+	case r.out <- quote:
+		return true
+	case <-r.ctx.Done():
+		return false
+	}
+}
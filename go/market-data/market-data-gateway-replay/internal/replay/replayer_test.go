@@ -0,0 +1,84 @@
+package replay
+
+import (
+	"context"
+	"github.com/ettec/open-trading-platform/go/shared/recording"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeRecording(t *testing.T, fileName string, records []recording.Record) {
+	file, err := os.Create(fileName)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	writer := recording.NewWriter(file)
+	for _, record := range records {
+		assert.NoError(t, writer.Write(record.Timestamp, record.Quote))
+	}
+	assert.NoError(t, writer.Close())
+}
+
+func TestReplaySendsSubscribedListingsAndSnapshotsOnSubscribe(t *testing.T) {
+	dir, err := os.MkdirTemp("", "replay")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	fileName := filepath.Join(dir, "recording.rec.gz")
+	writeRecording(t, fileName, []recording.Record{
+		{Timestamp: start, Quote: &model.ClobQuote{ListingId: 1, StreamStatusMsg: "1a"}},
+		{Timestamp: start.Add(time.Hour), Quote: &model.ClobQuote{ListingId: 2, StreamStatusMsg: "2a"}},
+		{Timestamp: start.Add(2 * time.Hour), Quote: &model.ClobQuote{ListingId: 2, StreamStatusMsg: "2b"}},
+		{Timestamp: start.Add(3 * time.Hour), Quote: &model.ClobQuote{ListingId: 1, StreamStatusMsg: "1b"}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replayer, err := NewReplayer(ctx, []string{fileName}, 0, 10)
+	assert.NoError(t, err)
+
+	assert.NoError(t, replayer.Subscribe(1))
+	assert.Equal(t, "1a", (<-replayer.Chan()).StreamStatusMsg)
+	assert.Equal(t, "1b", (<-replayer.Chan()).StreamStatusMsg)
+
+	assert.NoError(t, replayer.Subscribe(2))
+	assert.Equal(t, "2b", (<-replayer.Chan()).StreamStatusMsg)
+	assert.Empty(t, replayer.Chan())
+}
+
+func TestReplayIsPacedByTheRecordedTimestamps(t *testing.T) {
+	dir, err := os.MkdirTemp("", "replay")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	fileName := filepath.Join(dir, "recording.rec.gz")
+	writeRecording(t, fileName, []recording.Record{
+		{Timestamp: start, Quote: &model.ClobQuote{ListingId: 1, StreamStatusMsg: "a"}},
+		{Timestamp: start.Add(400 * time.Millisecond), Quote: &model.ClobQuote{ListingId: 1, StreamStatusMsg: "b"}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err = NewReplayer(ctx, []string{fileName}, -1, 10)
+	assert.Error(t, err)
+
+	replayer, err := NewReplayer(ctx, []string{fileName}, 2, 10)
+	assert.NoError(t, err)
+
+	assert.NoError(t, replayer.Subscribe(1))
+	assert.Equal(t, "a", (<-replayer.Chan()).StreamStatusMsg)
+	first := time.Now()
+	assert.Equal(t, "b", (<-replayer.Chan()).StreamStatusMsg)
+
+	elapsed := time.Since(first)
+	assert.GreaterOrEqual(t, int64(elapsed), int64(150*time.Millisecond))
+	assert.Less(t, int64(elapsed), int64(400*time.Millisecond))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ettec/open-trading-platform/go/market-data/market-data-gateway-replay/internal/replay"
+	"github.com/ettec/open-trading-platform/go/shared/api/marketdatasource"
+	md "github.com/ettec/open-trading-platform/go/shared/marketdata"
+	"github.com/ettec/open-trading-platform/go/shared/recording"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	_ "net/http/pprof"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// recordingFileNames returns the recording files of a comma separated list of days in the format yyyy-mm-dd.
+func recordingFileNames(recordingDir string, replayDates string) ([]string, error) {
+	var fileNames []string
+	for _, date := range strings.Split(replayDates, ",") {
+		day, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse replay date %v: %w", date, err)
+		}
+
+		fileName := recording.FileName(recordingDir, day)
+		if _, err := os.Stat(fileName); err != nil {
+			return nil, fmt.Errorf("no recording found for %v: %w", date, err)
+		}
+
+		fileNames = append(fileNames, fileName)
+	}
+
+	return fileNames, nil
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	recordingDir := bootstrap.GetEnvVar("RECORDING_DIR")
+	replayDates := bootstrap.GetEnvVar("REPLAY_DATES")
+	replaySpeed := bootstrap.GetOptionalFloatEnvVar("REPLAY_SPEED", 1)
+	clientQuoteBufferSize := bootstrap.GetOptionalIntEnvVar("CLIENT_QUOTE_BUFFER_SIZE", 1000)
+	maxSubscriptions := bootstrap.GetOptionalIntEnvVar("MARKETDATASOURCE_MAX_SUBSCRIPTIONS", 10000)
+
+	fileNames, err := recordingFileNames(recordingDir, replayDates)
+	if err != nil {
+		log.Panicf("failed to get recordings: %v", err)
+	}
+
+	http.Handle("/metrics", promhttp.Handler())
+	go func() {
+		err := http.ListenAndServe(":8080", nil)
+		if err != nil {
+			log.Panicf("Error while serving metrics: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replayer, err := replay.NewReplayer(ctx, fileNames, replaySpeed, clientQuoteBufferSize)
+	if err != nil {
+		log.Panicf("failed to create replayer: %v", err)
+	}
+
+	qd := md.NewQuoteDistributor(ctx, replayer, clientQuoteBufferSize)
+	service := md.NewMarketDataSource(qd, maxSubscriptions)
+
+	port := "50551"
+	slog.Info("Starting Market Data Replay Gateway", "port", port, "recordings", fileNames, "speed", replaySpeed)
+	lis, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	s := grpc.NewServer()
+
+	marketdatasource.RegisterMarketDataSourceServer(s, service)
+
+	reflection.Register(s)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		s.GracefulStop()
+	}()
+
+	if err := s.Serve(lis); err != nil {
+		log.Panicf("Error while serving : %v", err)
+	}
+}
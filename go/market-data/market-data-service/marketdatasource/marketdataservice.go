@@ -3,8 +3,8 @@ package marketdatasource
 import (
 	"context"
 	"fmt"
+	"github.com/ettec/open-trading-platform/go/shared/marketdata"
 	"github.com/ettec/otp-common/loadbalancing"
-	"github.com/ettec/otp-common/marketdata"
 	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/staticdata"
 	"log/slog"
@@ -36,6 +36,7 @@ type MarketDataService struct {
 	bufferSize                int
 	retryConnectSeconds       int
 	maxSubscriptionsPerClient int
+	connectionMetrics         ConnectionMetrics
 
 	sourceMutex sync.Mutex
 
@@ -46,7 +47,8 @@ type MarketDataService struct {
 func NewMarketDataService(ctx context.Context, id string,
 	gatewayStreamSource GatewayStreamSource,
 	getListing getListingFn,
-	toClientBufferSize int, retryConnectSeconds int, maxSubscriptionsPerClient int) *MarketDataService {
+	toClientBufferSize int, retryConnectSeconds int, maxSubscriptionsPerClient int,
+	connectionMetrics ConnectionMetrics) *MarketDataService {
 	return &MarketDataService{
 		ctx:                       ctx,
 		id:                        id,
@@ -56,6 +58,7 @@ func NewMarketDataService(ctx context.Context, id string,
 		bufferSize:                toClientBufferSize,
 		retryConnectSeconds:       retryConnectSeconds,
 		maxSubscriptionsPerClient: maxSubscriptionsPerClient,
+		connectionMetrics:         connectionMetrics,
 
 		subscriberIdToConn:        map[string]*connection{},
 		gatewayToQuoteDistributor: map[MarketDataGateway]*marketdata.QuoteDistributor{},
@@ -83,11 +86,14 @@ func (f *MarketDataService) AddMarketDataGateway(gateway MarketDataGateway) erro
 	return nil
 }
 
-func (f *MarketDataService) Connect(ctx context.Context, subscriberId string) marketdata.QuoteStream {
+// Connect returns the quote stream of a new subscriber connection.  A subscriber that does not keep up with its quotes
+// is sent only the latest quote of each listing, if maxQuotesPerSecond is greater than zero the subscriber's quotes are
+// always conflated and sent at no more than that rate.
+func (f *MarketDataService) Connect(ctx context.Context, subscriberId string, maxQuotesPerSecond int) marketdata.QuoteStream {
 	f.sourceMutex.Lock()
 	defer f.sourceMutex.Unlock()
 
-	conn := newConnection(ctx, subscriberId, f.getListing, f.bufferSize)
+	conn := newConnection(ctx, subscriberId, f.getListing, f.bufferSize, maxQuotesPerSecond, f.connectionMetrics)
 	f.subscriberIdToConn[subscriberId] = conn
 
 	for gateway, quoteDistributor := range f.gatewayToQuoteDistributor {
@@ -104,20 +110,25 @@ type connection struct {
 	subscriberId         string
 	getListingFn         getListingFn
 	gatewayToQuoteStream map[MarketDataGateway]marketdata.QuoteStream
+	listingToQuoteStream map[int32]marketdata.QuoteStream
 	out                  chan *model.ClobQuote
+	conflator            *quoteConflator
 
 	mutex sync.Mutex
 }
 
 func newConnection(parentCtx context.Context, subscriberId string, getListingFn getListingFn,
-	bufferSize int) *connection {
+	bufferSize int, maxQuotesPerSecond int, metrics ConnectionMetrics) *connection {
 
 	ctx, cancel := context.WithCancel(parentCtx)
 
+	out := make(chan *model.ClobQuote, bufferSize)
 	conn := &connection{ctx: ctx, cancel: cancel, subscriberId: subscriberId,
 		getListingFn:         getListingFn,
 		gatewayToQuoteStream: map[MarketDataGateway]marketdata.QuoteStream{},
-		out:                  make(chan *model.ClobQuote, bufferSize),
+		listingToQuoteStream: map[int32]marketdata.QuoteStream{},
+		out:                  out,
+		conflator:            newQuoteConflator(ctx, subscriberId, out, maxQuotesPerSecond, metrics),
 		log:                  slog.With("subsriberId", subscriberId),
 	}
 
@@ -141,7 +152,8 @@ func (c *connection) addGateway(gateway MarketDataGateway, quoteDistributor *mar
 				if !ok {
 					return
 				}
-				c.out <- quote
+
+				c.conflator.send(quote)
 			}
 		}
 	}()
@@ -180,6 +192,7 @@ func (c *connection) Subscribe(listingId int32) error {
 		if err := stream.Subscribe(listingResult.Listing.Id); err != nil {
 			return fmt.Errorf("failed to subscribe to market quote for subscriber %v, listing %v, error: %w", c.subscriberId, listingResult.Listing.Id, err)
 		}
+		c.listingToQuoteStream[listingResult.Listing.Id] = stream
 	} else {
 		return fmt.Errorf("no market data gateway found for mic %v", mic)
 	}
@@ -187,6 +200,27 @@ func (c *connection) Subscribe(listingId int32) error {
 	return nil
 }
 
+// Unsubscribe releases the connection's subscription to the listing on the gateway stream it was subscribed through,
+// unsubscribing from a listing the connection is not subscribed to has no effect.
+func (c *connection) Unsubscribe(listingId int32) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.log.Info("unsubscribe request", "listingId", listingId)
+
+	stream, ok := c.listingToQuoteStream[listingId]
+	if !ok {
+		return nil
+	}
+
+	if err := stream.Unsubscribe(listingId); err != nil {
+		return fmt.Errorf("failed to unsubscribe from market quote for subscriber %v, listing %v, error: %w", c.subscriberId, listingId, err)
+	}
+	delete(c.listingToQuoteStream, listingId)
+
+	return nil
+}
+
 func (c *connection) Chan() <-chan *model.ClobQuote {
 	return c.out
 }
@@ -11,7 +11,7 @@ import (
 	"time"
 )
 
-//go:generate go run github.com/golang/mock/mockgen -destination mocks/quotestream.go -package mocks github.com/ettec/otp-common/marketdata QuoteStream
+//go:generate go run github.com/golang/mock/mockgen -destination mocks/quotestream.go -package mocks github.com/ettech/open-trading-platform/go/market-data/market-data-service/marketdata QuoteStream
 
 func TestConnectAndSubscribe(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
@@ -31,11 +31,11 @@ func TestConnectAndSubscribe(t *testing.T) {
 	gatewayStreamSource := mocks.NewMockGatewayStreamSource(mockCtrl)
 	gatewayStreamSource.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress", 0*time.Second, 100).Return(quoteStream, nil)
 
-	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100)
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100, testConnectionMetrics{})
 	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress", ordinal: 1, marketMic: "XTST"})
 	assert.NoError(t, err)
 
-	stream := mds.Connect(ctx, "testSubscriber")
+	stream := mds.Connect(ctx, "testSubscriber", 0)
 
 	err = stream.Subscribe(1)
 	assert.NoError(t, err)
@@ -65,15 +65,17 @@ func TestClosingStream(t *testing.T) {
 	quoteStream := mocks.NewMockQuoteStream(mockCtrl)
 	quoteStream.EXPECT().Chan().Return(inboundQuotes)
 	quoteStream.EXPECT().Subscribe(int32(1))
+	unsubscribed := make(chan bool)
+	quoteStream.EXPECT().Unsubscribe(int32(1)).Do(func(listingId int32) { close(unsubscribed) })
 
 	gatewayStreamSource := mocks.NewMockGatewayStreamSource(mockCtrl)
 	gatewayStreamSource.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress", 0*time.Second, 100).Return(quoteStream, nil)
 
-	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100)
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100, testConnectionMetrics{})
 	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress", ordinal: 1, marketMic: "XTST"})
 	assert.NoError(t, err)
 
-	stream := mds.Connect(ctx, "testSubscriber")
+	stream := mds.Connect(ctx, "testSubscriber", 0)
 
 	err = stream.Subscribe(1)
 	assert.NoError(t, err)
@@ -90,6 +92,13 @@ func TestClosingStream(t *testing.T) {
 
 	stream.Close()
 
+	// the gateway subscription is released once the distributor has removed the closed stream
+	select {
+	case <-unsubscribed:
+	case <-time.After(1 * time.Second):
+		t.Fatal("expected the gateway subscription to be released")
+	}
+
 	inboundQuotes <- &model.ClobQuote{ListingId: 1}
 
 	timer := time.NewTimer(1 * time.Second)
@@ -118,12 +127,12 @@ func TestConnectAndSubscribeFromMultipleClients(t *testing.T) {
 	gatewayStreamSource := mocks.NewMockGatewayStreamSource(mockCtrl)
 	gatewayStreamSource.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress", 0*time.Second, 100).Return(quoteStream, nil)
 
-	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100)
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100, testConnectionMetrics{})
 	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress", ordinal: 1, marketMic: "XTST"})
 	assert.NoError(t, err)
 
-	stream1 := mds.Connect(ctx, "testSubscriber1")
-	stream2 := mds.Connect(ctx, "testSubscriber2")
+	stream1 := mds.Connect(ctx, "testSubscriber1", 0)
+	stream2 := mds.Connect(ctx, "testSubscriber2", 0)
 
 	err = stream1.Subscribe(1)
 	assert.NoError(t, err)
@@ -177,14 +186,14 @@ func TestSubscriptionsAcrossMarketSentToCorrectGateways(t *testing.T) {
 	gatewayStreamSource1.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress1", 0*time.Second, 100).Return(quoteStream1, nil)
 	gatewayStreamSource1.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress2", 0*time.Second, 100).Return(quoteStream2, nil)
 
-	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource1, getListing, 100, 0, 100)
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource1, getListing, 100, 0, 100, testConnectionMetrics{})
 	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress1", ordinal: 0, marketMic: "XTST"})
 	assert.NoError(t, err)
 
 	err = mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress2", ordinal: 0, marketMic: "XTST2"})
 	assert.NoError(t, err)
 
-	stream := mds.Connect(ctx, "testSubscriber")
+	stream := mds.Connect(ctx, "testSubscriber", 0)
 
 	err = stream.Subscribe(1)
 	assert.NoError(t, err)
@@ -235,14 +244,14 @@ func TestSubscriptionsAreLoadBalancedAcrossGatewaysForTheSameMarket(t *testing.T
 	gatewayStreamSource1.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress1", 0*time.Second, 100).Return(quoteStream1, nil)
 	gatewayStreamSource1.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress2", 0*time.Second, 100).Return(quoteStream2, nil)
 
-	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource1, getListing, 100, 0, 100)
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource1, getListing, 100, 0, 100, testConnectionMetrics{})
 	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress1", ordinal: 0, marketMic: "XTST"})
 	assert.NoError(t, err)
 
 	err = mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress2", ordinal: 1, marketMic: "XTST"})
 	assert.NoError(t, err)
 
-	stream := mds.Connect(ctx, "testSubscriber")
+	stream := mds.Connect(ctx, "testSubscriber", 0)
 
 	err = stream.Subscribe(1)
 	assert.NoError(t, err)
@@ -269,6 +278,111 @@ func TestSubscriptionsAreLoadBalancedAcrossGatewaysForTheSameMarket(t *testing.T
 
 }
 
+func TestNewSubscriberReceivesTheLatestQuoteFollowedByUpdates(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var getListing getListingFn = func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: listingId, Market: &model.Market{Mic: "XTST"}}}
+	}
+
+	inboundQuotes := make(chan *model.ClobQuote, 100)
+	quoteStream := mocks.NewMockQuoteStream(mockCtrl)
+	quoteStream.EXPECT().Chan().Return(inboundQuotes)
+	quoteStream.EXPECT().Subscribe(int32(1))
+
+	gatewayStreamSource := mocks.NewMockGatewayStreamSource(mockCtrl)
+	gatewayStreamSource.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress", 0*time.Second, 100).Return(quoteStream, nil)
+
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100, testConnectionMetrics{})
+	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress", ordinal: 1, marketMic: "XTST"})
+	assert.NoError(t, err)
+
+	stream1 := mds.Connect(ctx, "testSubscriber1", 0)
+	err = stream1.Subscribe(1)
+	assert.NoError(t, err)
+
+	inboundQuotes <- &model.ClobQuote{ListingId: 1, StreamInterrupted: true}
+	inboundQuotes <- &model.ClobQuote{ListingId: 1}
+
+	assert.Equal(t, &model.ClobQuote{ListingId: 1, StreamInterrupted: true}, <-stream1.Chan())
+	assert.Equal(t, &model.ClobQuote{ListingId: 1}, <-stream1.Chan())
+
+	stream2 := mds.Connect(ctx, "testSubscriber2", 0)
+	err = stream2.Subscribe(1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &model.ClobQuote{ListingId: 1}, <-stream2.Chan())
+
+	err = stream2.Subscribe(1)
+	assert.NoError(t, err)
+
+	inboundQuotes <- &model.ClobQuote{ListingId: 1, StreamStatusMsg: "update"}
+
+	assert.Equal(t, &model.ClobQuote{ListingId: 1, StreamStatusMsg: "update"}, <-stream2.Chan())
+	assert.Equal(t, &model.ClobQuote{ListingId: 1, StreamStatusMsg: "update"}, <-stream1.Chan())
+
+	timer := time.NewTimer(100 * time.Millisecond)
+	select {
+	case quote := <-stream2.Chan():
+		t.Errorf("unexpected quote %v", quote)
+	case <-timer.C:
+	}
+}
+
+func TestGatewaySubscriptionIsReleasedWhenTheLastSubscriberUnsubscribes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var getListing getListingFn = func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: listingId, Market: &model.Market{Mic: "XTST"}}}
+	}
+
+	inboundQuotes := make(chan *model.ClobQuote, 100)
+	quoteStream := mocks.NewMockQuoteStream(mockCtrl)
+	quoteStream.EXPECT().Chan().Return(inboundQuotes)
+	subscribe := quoteStream.EXPECT().Subscribe(int32(1))
+	unsubscribe := quoteStream.EXPECT().Unsubscribe(int32(1)).After(subscribe)
+	quoteStream.EXPECT().Subscribe(int32(1)).After(unsubscribe)
+
+	gatewayStreamSource := mocks.NewMockGatewayStreamSource(mockCtrl)
+	gatewayStreamSource.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress", 0*time.Second, 100).Return(quoteStream, nil)
+
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100, testConnectionMetrics{})
+	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress", ordinal: 1, marketMic: "XTST"})
+	assert.NoError(t, err)
+
+	stream1 := mds.Connect(ctx, "testSubscriber1", 0)
+	stream2 := mds.Connect(ctx, "testSubscriber2", 0)
+	assert.NoError(t, stream1.Subscribe(1))
+	assert.NoError(t, stream2.Subscribe(1))
+
+	inboundQuotes <- &model.ClobQuote{ListingId: 1}
+	assert.Equal(t, &model.ClobQuote{ListingId: 1}, <-stream1.Chan())
+	assert.Equal(t, &model.ClobQuote{ListingId: 1}, <-stream2.Chan())
+
+	assert.NoError(t, stream1.Unsubscribe(1))
+	assert.NoError(t, stream1.Unsubscribe(1))
+
+	inboundQuotes <- &model.ClobQuote{ListingId: 1, StreamStatusMsg: "update"}
+	assert.Equal(t, &model.ClobQuote{ListingId: 1, StreamStatusMsg: "update"}, <-stream2.Chan())
+
+	assert.NoError(t, stream2.Unsubscribe(1))
+
+	assert.NoError(t, stream1.Subscribe(1))
+
+	timer := time.NewTimer(100 * time.Millisecond)
+	select {
+	case quote := <-stream1.Chan():
+		t.Errorf("unexpected quote %v, the cached quote should be discarded when the listing is released", quote)
+	case <-timer.C:
+	}
+}
+
 type TestMarketDataGateway struct {
 	address   string
 	ordinal   int
@@ -286,3 +400,94 @@ func (t TestMarketDataGateway) GetOrdinal() int {
 func (t TestMarketDataGateway) GetMarketMic() string {
 	return t.marketMic
 }
+
+func TestSlowSubscriberIsSentTheLatestQuote(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var getListing getListingFn = func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: listingId, Market: &model.Market{Mic: "XTST"}}}
+	}
+
+	inboundQuotes := make(chan *model.ClobQuote, 100)
+	quoteStream := mocks.NewMockQuoteStream(mockCtrl)
+	quoteStream.EXPECT().Chan().Return(inboundQuotes)
+	quoteStream.EXPECT().Subscribe(int32(1))
+
+	gatewayStreamSource := mocks.NewMockGatewayStreamSource(mockCtrl)
+	gatewayStreamSource.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress", 0*time.Second, 100).Return(quoteStream, nil)
+
+	metrics := testConnectionMetrics{conflated: make(chan string, 10)}
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 1, 0, 100, metrics)
+	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress", ordinal: 1, marketMic: "XTST"})
+	assert.NoError(t, err)
+
+	stream := mds.Connect(ctx, "testSubscriber", 0)
+	assert.NoError(t, stream.Subscribe(1))
+
+	for _, version := range []string{"1", "2", "3", "4"} {
+		inboundQuotes <- &model.ClobQuote{ListingId: 1, StreamStatusMsg: version}
+	}
+
+	// At least one quote is conflated, the second may already be in flight when the later quotes arrive
+	assert.Equal(t, "testSubscriber", <-metrics.conflated)
+
+	var received []string
+	for len(received) == 0 || received[len(received)-1] != "4" {
+		received = append(received, (<-stream.Chan()).StreamStatusMsg)
+	}
+
+	assert.Equal(t, "1", received[0])
+	assert.Less(t, len(received), 4)
+}
+
+func TestSubscriberQuotesAreThrottledToTheMaxQuoteRate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var getListing getListingFn = func(ctx context.Context, listingId int32, result chan<- staticdata.ListingResult) {
+		result <- staticdata.ListingResult{Listing: &model.Listing{Id: listingId, Market: &model.Market{Mic: "XTST"}}}
+	}
+
+	inboundQuotes := make(chan *model.ClobQuote, 100)
+	quoteStream := mocks.NewMockQuoteStream(mockCtrl)
+	quoteStream.EXPECT().Chan().Return(inboundQuotes)
+	quoteStream.EXPECT().Subscribe(int32(1))
+	quoteStream.EXPECT().Subscribe(int32(2))
+
+	gatewayStreamSource := mocks.NewMockGatewayStreamSource(mockCtrl)
+	gatewayStreamSource.EXPECT().NewQuoteStreamFromMdSource(ctx, "testMds", "testAddress", 0*time.Second, 100).Return(quoteStream, nil)
+
+	mds := NewMarketDataService(ctx, "testMds", gatewayStreamSource, getListing, 100, 0, 100, testConnectionMetrics{})
+	err := mds.AddMarketDataGateway(TestMarketDataGateway{address: "testAddress", ordinal: 1, marketMic: "XTST"})
+	assert.NoError(t, err)
+
+	stream := mds.Connect(ctx, "testSubscriber", 10)
+	assert.NoError(t, stream.Subscribe(1))
+	assert.NoError(t, stream.Subscribe(2))
+
+	inboundQuotes <- &model.ClobQuote{ListingId: 1}
+	inboundQuotes <- &model.ClobQuote{ListingId: 2}
+
+	assert.Equal(t, &model.ClobQuote{ListingId: 1}, <-stream.Chan())
+	first := time.Now()
+	assert.Equal(t, &model.ClobQuote{ListingId: 2}, <-stream.Chan())
+	assert.GreaterOrEqual(t, int64(time.Since(first)), int64(90*time.Millisecond))
+}
+
+type testConnectionMetrics struct {
+	conflated chan string
+}
+
+func (t testConnectionMetrics) QuoteConflated(subscriberId string) {
+	if t.conflated != nil {
+		t.conflated <- subscriberId
+	}
+}
+
+func (t testConnectionMetrics) QuoteDelivered(string, time.Duration) {
+}
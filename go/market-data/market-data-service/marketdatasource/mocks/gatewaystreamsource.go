@@ -9,7 +9,7 @@ import (
 	reflect "reflect"
 	time "time"
 
-	marketdata "github.com/ettec/otp-common/marketdata"
+	marketdata "github.com/ettec/open-trading-platform/go/shared/marketdata"
 	gomock "github.com/golang/mock/gomock"
 )
 
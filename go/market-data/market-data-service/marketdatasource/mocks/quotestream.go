@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/ettec/otp-common/marketdata (interfaces: QuoteStream)
+// Source: github.com/ettech/open-trading-platform/go/market-data/market-data-service/marketdata (interfaces: QuoteStream)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -73,3 +73,17 @@ func (mr *MockQuoteStreamMockRecorder) Subscribe(arg0 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockQuoteStream)(nil).Subscribe), arg0)
 }
+
+// Unsubscribe mocks base method.
+func (m *MockQuoteStream) Unsubscribe(arg0 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockQuoteStreamMockRecorder) Unsubscribe(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockQuoteStream)(nil).Unsubscribe), arg0)
+}
@@ -0,0 +1,157 @@
+package marketdatasource
+
+import (
+	"context"
+	"github.com/ettec/otp-common/model"
+	"sync"
+	"time"
+)
+
+// ConnectionMetrics is notified of the quotes conflated and delivered on each subscriber's connection.
+type ConnectionMetrics interface {
+	QuoteConflated(subscriberId string)
+	// QuoteDelivered is called with the time the delivered quote's listing waited to be delivered, zero if the quote
+	// was delivered without waiting.
+	QuoteDelivered(subscriberId string, lag time.Duration)
+}
+
+type pendingQuote struct {
+	quote *model.ClobQuote
+	since time.Time
+}
+
+// quoteConflator delivers a connection's quotes to its out channel without blocking the sender.  Quotes are sent
+// straight to the out channel whilst it has capacity, otherwise only the latest quote of each listing is kept and the
+// pending quotes are delivered in the order their listings became pending as the client reads them.  If a minimum
+// interval between quotes is set every quote is delivered through the pending quotes at no more than the implied rate.
+type quoteConflator struct {
+	ctx          context.Context
+	subscriberId string
+	out          chan<- *model.ClobQuote
+	minInterval  time.Duration
+	metrics      ConnectionMetrics
+
+	mutex    sync.Mutex
+	pending  map[int32]pendingQuote
+	order    []int32
+	inFlight bool
+	ready    chan struct{}
+}
+
+func newQuoteConflator(ctx context.Context, subscriberId string, out chan<- *model.ClobQuote, maxQuotesPerSecond int,
+	metrics ConnectionMetrics) *quoteConflator {
+
+	var minInterval time.Duration
+	if maxQuotesPerSecond > 0 {
+		minInterval = time.Second / time.Duration(maxQuotesPerSecond)
+	}
+
+	c := &quoteConflator{
+		ctx:          ctx,
+		subscriberId: subscriberId,
+		out:          out,
+		minInterval:  minInterval,
+		metrics:      metrics,
+		pending:      map[int32]pendingQuote{},
+		ready:        make(chan struct{}, 1),
+	}
+
+	go c.run()
+
+	return c
+}
+
+func (c *quoteConflator) send(quote *model.ClobQuote) {
+	c.mutex.Lock()
+
+	if c.minInterval == 0 && len(c.order) == 0 && !c.inFlight {
+		select {
+		case c.out <- quote:
+			c.mutex.Unlock()
+			c.metrics.QuoteDelivered(c.subscriberId, 0)
+			return
+		default:
+		}
+	}
+
+	p, conflated := c.pending[quote.ListingId]
+	if conflated {
+		p.quote = quote
+	} else {
+		p = pendingQuote{quote: quote, since: time.Now()}
+		c.order = append(c.order, quote.ListingId)
+	}
+	c.pending[quote.ListingId] = p
+
+	c.mutex.Unlock()
+
+	if conflated {
+		c.metrics.QuoteConflated(c.subscriberId)
+	}
+
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// next removes and returns the quote of the listing that has been pending longest, the conflator is marked as having a
+// quote in flight until delivered is called so that later quotes are not sent ahead of it.
+func (c *quoteConflator) next() (pendingQuote, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.order) == 0 {
+		return pendingQuote{}, false
+	}
+
+	listingId := c.order[0]
+	c.order = c.order[1:]
+	p := c.pending[listingId]
+	delete(c.pending, listingId)
+	c.inFlight = true
+
+	return p, true
+}
+
+func (c *quoteConflator) delivered() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inFlight = false
+}
+
+func (c *quoteConflator) run() {
+	var lastSent time.Time
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-c.ready:
+		}
+
+		for {
+			if wait := time.Until(lastSent.Add(c.minInterval)); wait > 0 {
+				select {
+				case <-c.ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+
+			p, ok := c.next()
+			if !ok {
+				break
+			}
+
+			select {
+			case c.out <- p.quote:
+			case <-c.ctx.Done():
+				return
+			}
+
+			lastSent = time.Now()
+			c.delivered()
+			c.metrics.QuoteDelivered(c.subscriberId, lastSent.Sub(p.since))
+		}
+	}
+}
@@ -3,11 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
-	api "github.com/ettec/otp-common/api/marketdataservice"
+	api "github.com/ettec/open-trading-platform/go/shared/api/marketdataservice"
+	"github.com/ettec/open-trading-platform/go/shared/marketdata"
 	"github.com/ettec/otp-common/bootstrap"
 	"github.com/ettec/otp-common/k8s"
 	"github.com/ettec/otp-common/loadbalancing"
-	"github.com/ettec/otp-common/marketdata"
 	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/ettech/open-trading-platform/go/market-data/market-data-service/marketdatasource"
@@ -41,6 +41,26 @@ var quotesSent = promauto.NewCounter(prometheus.CounterOpts{
 	Help: "The number of quotes sent across all clients",
 })
 
+var quotesConflated = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "mds_quotes_conflated",
+	Help: "The number of quotes replaced by a later quote for the same listing before being sent to the client",
+}, []string{"subscriberId"})
+
+var subscriberLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "mds_subscriber_lag_seconds",
+	Help: "The time the last quote sent to the client waited to be sent",
+}, []string{"subscriberId"})
+
+type prometheusConnectionMetrics struct{}
+
+func (p prometheusConnectionMetrics) QuoteConflated(subscriberId string) {
+	quotesConflated.WithLabelValues(subscriberId).Inc()
+}
+
+func (p prometheusConnectionMetrics) QuoteDelivered(subscriberId string, lag time.Duration) {
+	subscriberLag.WithLabelValues(subscriberId).Set(lag.Seconds())
+}
+
 type NewQuoteStreamFromMdSourceFunc func(ctx context.Context, id string, targetAddress string, maxReconnectInterval time.Duration,
 	quoteBufferSize int) (marketdata.QuoteStream, error)
 
@@ -50,12 +70,13 @@ func (f NewQuoteStreamFromMdSourceFunc) NewQuoteStreamFromMdSource(ctx context.C
 }
 
 type connectionFactory interface {
-	Connect(ctx context.Context, subscriberId string) marketdata.QuoteStream
+	Connect(ctx context.Context, subscriberId string, maxQuotesPerSecond int) marketdata.QuoteStream
 	AddMarketDataGateway(gateway marketdatasource.MarketDataGateway) error
 }
 
 type service struct {
 	connectionFactory        connectionFactory
+	maxQuotesPerSecond       int
 	subscriberIdToConnection map[string]marketdata.QuoteStream
 	mutex                    sync.Mutex
 }
@@ -75,6 +96,21 @@ func (s *service) Subscribe(_ context.Context, r *api.MdsSubscribeRequest) (*mod
 	return nil, nil
 }
 
+func (s *service) Unsubscribe(_ context.Context, r *api.MdsUnsubscribeRequest) (*model.Empty, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if quoteStream, ok := s.subscriberIdToConnection[r.SubscriberId]; ok {
+		if err := quoteStream.Unsubscribe(r.ListingId); err != nil {
+			return nil, fmt.Errorf("failed to unsubscribe, subscriber %v, listing %v, error: %w", r.SubscriberId, r.ListingId, err)
+		}
+	} else {
+		return nil, fmt.Errorf("failed to unsubscribe, no connection exists for subscriber " + r.SubscriberId)
+	}
+
+	return &model.Empty{}, nil
+}
+
 func (s *service) Connect(request *api.MdsConnectRequest, stream api.MarketDataService_ConnectServer) error {
 	subscriberId := request.GetSubscriberId()
 	slog.Info("connect request received", "subscriberId", subscriberId)
@@ -87,11 +123,18 @@ func (s *service) Connect(request *api.MdsConnectRequest, stream api.MarketDataS
 		connections.Dec()
 	}
 
-	connection := s.connectionFactory.Connect(stream.Context(), subscriberId)
+	maxQuotesPerSecond := int(request.GetMaxQuotePerSecond())
+	if maxQuotesPerSecond <= 0 {
+		maxQuotesPerSecond = s.maxQuotesPerSecond
+	}
+
+	connection := s.connectionFactory.Connect(stream.Context(), subscriberId, maxQuotesPerSecond)
 	connections.Inc()
 	defer func() {
 		connection.Close()
 		connections.Dec()
+		quotesConflated.DeleteLabelValues(subscriberId)
+		subscriberLag.DeleteLabelValues(subscriberId)
 	}()
 
 	s.subscriberIdToConnection[subscriberId] = connection
@@ -120,6 +163,7 @@ func main() {
 	connectRetrySecs := bootstrap.GetOptionalIntEnvVar("CONNECT_RETRY_SECONDS", 60)
 	maxSubscriptions := bootstrap.GetOptionalIntEnvVar("MAX_SUBSCRIPTIONS", 10000)
 	toClientBufferSize := bootstrap.GetOptionalIntEnvVar("TO_CLIENT_BUFFER_SIZE", 1000)
+	maxQuotesPerSecond := bootstrap.GetOptionalIntEnvVar("MAX_QUOTES_PER_SECOND", 0)
 
 	http.Handle("/metrics", promhttp.Handler())
 	go func() {
@@ -139,7 +183,7 @@ func main() {
 
 	cf := marketdatasource.NewMarketDataService(ctx, id,
 		NewQuoteStreamFromMdSourceFunc(marketdata.NewQuoteStreamFromMdSource),
-		sds.GetListing, toClientBufferSize, connectRetrySecs, maxSubscriptions)
+		sds.GetListing, toClientBufferSize, connectRetrySecs, maxSubscriptions, prometheusConnectionMetrics{})
 
 	namespace := "default"
 	clientSet := k8s.GetK8sClientSet(false)
@@ -177,7 +221,7 @@ func main() {
 		log.Panicf("Error while listening : %v", err)
 	}
 
-	service := &service{connectionFactory: cf, subscriberIdToConnection: map[string]marketdata.QuoteStream{}}
+	service := &service{connectionFactory: cf, maxQuotesPerSecond: maxQuotesPerSecond, subscriberIdToConnection: map[string]marketdata.QuoteStream{}}
 
 	s := grpc.NewServer()
 
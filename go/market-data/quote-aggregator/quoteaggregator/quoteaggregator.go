@@ -2,8 +2,8 @@ package quoteaggregator
 
 import (
 	"context"
+	"github.com/ettec/open-trading-platform/go/shared/marketdata"
 	common "github.com/ettec/otp-common"
-	"github.com/ettec/otp-common/marketdata"
 	"github.com/ettec/otp-common/model"
 	"github.com/ettec/otp-common/staticdata"
 	"log/slog"
@@ -16,6 +16,7 @@ type quoteAggregator struct {
 	cancel                        context.CancelFunc
 	getListingsWithSameInstrument getListingsWithSameInstrument
 	listingGroupsIn               chan staticdata.ListingsResult
+	unsubscribeIn                 chan int32
 	outChan                       chan *model.ClobQuote
 }
 
@@ -28,10 +29,26 @@ func (q *quoteAggregator) Subscribe(listingId int32) error {
 	return nil
 }
 
+// Unsubscribe releases the subscriptions to the listings whose quotes are combined into the aggregated quote of the
+// listing.
+func (q *quoteAggregator) Unsubscribe(listingId int32) error {
+	select {
+	case q.unsubscribeIn <- listingId:
+		return nil
+	case <-q.ctx.Done():
+		return q.ctx.Err()
+	}
+}
+
 func (q *quoteAggregator) Close() {
 	q.cancel()
 }
 
+type aggregatedListing struct {
+	listingIds []int32
+	cancel     context.CancelFunc
+}
+
 func New(ctx context.Context, getListingsWithSameInstrument getListingsWithSameInstrument, stream marketdata.QuoteStream,
 	inboundListingsBufferSize int) *quoteAggregator {
 
@@ -42,19 +59,36 @@ func New(ctx context.Context, getListingsWithSameInstrument getListingsWithSameI
 		cancel:                        cancel,
 		getListingsWithSameInstrument: getListingsWithSameInstrument,
 		listingGroupsIn:               make(chan staticdata.ListingsResult, inboundListingsBufferSize),
+		unsubscribeIn:                 make(chan int32, inboundListingsBufferSize),
 		outChan:                       make(chan *model.ClobQuote),
 	}
 
 	go func() {
 		listingIdToQuoteChan := map[int32]chan<- *model.ClobQuote{}
-		subscribedListings := map[int32]bool{}
+		aggregatedListings := map[int32]*aggregatedListing{}
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case q := <-stream.Chan():
-				listingIdToQuoteChan[q.ListingId] <- q
+				if quoteChan, ok := listingIdToQuoteChan[q.ListingId]; ok {
+					quoteChan <- q
+				}
+			case listingId := <-qa.unsubscribeIn:
+				aggregated, ok := aggregatedListings[listingId]
+				if !ok {
+					continue
+				}
+
+				aggregated.cancel()
+				for _, id := range aggregated.listingIds {
+					delete(listingIdToQuoteChan, id)
+					if err := stream.Unsubscribe(id); err != nil {
+						slog.Error("failed to unsubscribe from quote stream", "listingId", id, "error", err)
+					}
+				}
+				delete(aggregatedListings, listingId)
 			case listingsResult := <-qa.listingGroupsIn:
 				if listingsResult.Err != nil {
 					slog.Error("failed to get listings", "error", listingsResult.Err)
@@ -65,20 +99,24 @@ func New(ctx context.Context, getListingsWithSameInstrument getListingsWithSameI
 				for _, listing := range listingsResult.Listings {
 					if listing.Market.Mic == common.SR_MIC {
 						quoteAggListingId = listing.Id
-						if _, ok := subscribedListings[listing.Id]; ok {
-							slog.Warn("already subscribed to quote stream", "listingId", listing.Id)
-							continue
-						} else {
-							subscribedListings[listing.Id] = true
-						}
 					}
 				}
 
+				if _, ok := aggregatedListings[quoteAggListingId]; ok {
+					slog.Warn("already subscribed to quote stream", "listingId", quoteAggListingId)
+					continue
+				}
+
+				groupCtx, groupCancel := context.WithCancel(ctx)
+				aggregated := &aggregatedListing{cancel: groupCancel}
+				aggregatedListings[quoteAggListingId] = aggregated
+
 				quoteChan := make(chan *model.ClobQuote)
 				numStreams := 0
 				for _, listing := range listingsResult.Listings {
 					if listing.Market.Mic != common.SR_MIC {
 						listingIdToQuoteChan[listing.Id] = quoteChan
+						aggregated.listingIds = append(aggregated.listingIds, listing.Id)
 						if err := stream.Subscribe(listing.Id); err != nil {
 							slog.Error("failed to subscribe to quote stream", "listingId", listing.Id, "error", err)
 						}
@@ -91,7 +129,7 @@ func New(ctx context.Context, getListingsWithSameInstrument getListingsWithSameI
 					quotes := make([]*model.ClobQuote, 0, numStreams)
 					for {
 						select {
-						case <-ctx.Done():
+						case <-groupCtx.Done():
 							return
 						case q := <-quoteChan:
 							listingIdToLastQuote[q.ListingId] = q
@@ -99,7 +137,12 @@ func New(ctx context.Context, getListingsWithSameInstrument getListingsWithSameI
 							for _, q := range listingIdToLastQuote {
 								quotes = append(quotes, q)
 							}
-							qa.outChan <- combineQuotes(quoteAggListingId, quotes, q)
+
+							select {
+							case qa.outChan <- combineQuotes(quoteAggListingId, quotes, q):
+							case <-groupCtx.Done():
+								return
+							}
 						}
 					}
 				}()
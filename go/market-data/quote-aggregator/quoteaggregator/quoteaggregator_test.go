@@ -525,18 +525,58 @@ func TestCombinedTradeData(t *testing.T) {
 	}
 }
 
+func TestUnsubscribeReleasesTheAggregatedListings(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mdsqs := newTestQuoteStream()
+
+	qa := New(ctx, func(ctx context.Context, listingId int32, listingGroupsIn chan<- staticdata.ListingsResult) {
+		listingGroupsIn <- staticdata.ListingsResult{Listings: []*model.Listing{
+			{Id: 1, Market: &model.Market{Mic: "XOSR"}},
+			{Id: 2, Market: &model.Market{Mic: "IEXG"}},
+			{Id: 3, Market: &model.Market{Mic: "XNAS"}},
+		}}
+	}, mdsqs, 1000)
+
+	assert.NoError(t, qa.Subscribe(1))
+	assert.Equal(t, int32(2), <-mdsqs.subscribeChan)
+	assert.Equal(t, int32(3), <-mdsqs.subscribeChan)
+
+	assert.NoError(t, qa.Unsubscribe(1))
+	assert.Equal(t, int32(2), <-mdsqs.unsubscribeChan)
+	assert.Equal(t, int32(3), <-mdsqs.unsubscribeChan)
+
+	assert.NoError(t, qa.Unsubscribe(1))
+
+	mdsqs.refreshChan <- &model.ClobQuote{ListingId: 2}
+
+	assert.NoError(t, qa.Subscribe(1))
+	assert.Equal(t, int32(2), <-mdsqs.subscribeChan)
+	assert.Equal(t, int32(3), <-mdsqs.subscribeChan)
+
+	mdsqs.refreshChan <- &model.ClobQuote{ListingId: 3, StreamStatusMsg: "update"}
+
+	q := <-qa.Chan()
+	assert.Equal(t, int32(1), q.ListingId)
+	assert.Equal(t, "update", q.StreamStatusMsg)
+	assert.Empty(t, mdsqs.unsubscribeChan)
+}
+
 func d64(mantissa int) *model.Decimal64 {
 	return &model.Decimal64{Mantissa: int64(mantissa), Exponent: 0}
 }
 
 type testMdsQuoteStream struct {
-	subscribeChan chan int32
-	refreshChan   chan *model.ClobQuote
+	subscribeChan   chan int32
+	unsubscribeChan chan int32
+	refreshChan     chan *model.ClobQuote
 }
 
 func newTestQuoteStream() *testMdsQuoteStream {
 	s := &testMdsQuoteStream{}
 	s.subscribeChan = make(chan int32, 10)
+	s.unsubscribeChan = make(chan int32, 10)
 	s.refreshChan = make(chan *model.ClobQuote)
 	return s
 }
@@ -546,6 +586,11 @@ func (s *testMdsQuoteStream) Subscribe(listingId int32) error {
 	return nil
 }
 
+func (s *testMdsQuoteStream) Unsubscribe(listingId int32) error {
+	s.unsubscribeChan <- listingId
+	return nil
+}
+
 func (s *testMdsQuoteStream) Chan() <-chan *model.ClobQuote {
 	return s.refreshChan
 }
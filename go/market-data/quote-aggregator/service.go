@@ -3,10 +3,10 @@ package main
 import (
 	"context"
 	"github.com/ettec/open-trading-platform/go/market-data/quote-aggregator/quoteaggregator"
-	"github.com/ettec/otp-common/api/marketdatasource"
+	"github.com/ettec/open-trading-platform/go/shared/api/marketdatasource"
+	"github.com/ettec/open-trading-platform/go/shared/marketdata"
 	"github.com/ettec/otp-common/bootstrap"
 	"github.com/ettec/otp-common/k8s"
-	"github.com/ettec/otp-common/marketdata"
 	"github.com/ettec/otp-common/staticdata"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
@@ -60,7 +60,8 @@ func main() {
 
 	quoteAggregator := quoteaggregator.New(ctx, sds.GetListingsWithSameInstrument, mdsQuoteStream, inboundListingsBufferSize)
 
-	mdSource := marketdata.NewMarketDataSource(marketdata.NewQuoteDistributor(ctx, quoteAggregator, toClientBufferSize))
+	mdSource := marketdata.NewMarketDataSource(marketdata.NewQuoteDistributor(ctx, quoteAggregator, toClientBufferSize),
+		bootstrap.GetOptionalIntEnvVar("MARKETDATASOURCE_MAX_SUBSCRIPTIONS", 10000))
 
 	port := "50551"
 	slog.Info("Starting Quote Aggregator", "port", port)
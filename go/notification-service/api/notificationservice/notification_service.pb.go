@@ -0,0 +1,1005 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: notification_service.proto
+
+package notificationservice
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Notification struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type                 string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Title                string   `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Body                 string   `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	Timestamp            int64    `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Status               string   `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Channel              string   `protobuf:"bytes,8,opt,name=channel,proto3" json:"channel,omitempty"`
+	Data                 string   `protobuf:"bytes,9,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Notification) Reset()         { *m = Notification{} }
+func (m *Notification) String() string { return proto.CompactTextString(m) }
+func (*Notification) ProtoMessage()    {}
+func (*Notification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{0}
+}
+
+func (m *Notification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Notification.Unmarshal(m, b)
+}
+func (m *Notification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Notification.Marshal(b, m, deterministic)
+}
+func (m *Notification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Notification.Merge(m, src)
+}
+func (m *Notification) XXX_Size() int {
+	return xxx_messageInfo_Notification.Size(m)
+}
+func (m *Notification) XXX_DiscardUnknown() {
+	xxx_messageInfo_Notification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Notification proto.InternalMessageInfo
+
+func (m *Notification) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Notification) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Notification) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Notification) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *Notification) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *Notification) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Notification) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Notification) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *Notification) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+type SendNotificationRequest struct {
+	Notification         *Notification `protobuf:"bytes,1,opt,name=notification,proto3" json:"notification,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *SendNotificationRequest) Reset()         { *m = SendNotificationRequest{} }
+func (m *SendNotificationRequest) String() string { return proto.CompactTextString(m) }
+func (*SendNotificationRequest) ProtoMessage()    {}
+func (*SendNotificationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{1}
+}
+
+func (m *SendNotificationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendNotificationRequest.Unmarshal(m, b)
+}
+func (m *SendNotificationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendNotificationRequest.Marshal(b, m, deterministic)
+}
+func (m *SendNotificationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendNotificationRequest.Merge(m, src)
+}
+func (m *SendNotificationRequest) XXX_Size() int {
+	return xxx_messageInfo_SendNotificationRequest.Size(m)
+}
+func (m *SendNotificationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendNotificationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendNotificationRequest proto.InternalMessageInfo
+
+func (m *SendNotificationRequest) GetNotification() *Notification {
+	if m != nil {
+		return m.Notification
+	}
+	return nil
+}
+
+type SendNotificationResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendNotificationResponse) Reset()         { *m = SendNotificationResponse{} }
+func (m *SendNotificationResponse) String() string { return proto.CompactTextString(m) }
+func (*SendNotificationResponse) ProtoMessage()    {}
+func (*SendNotificationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{2}
+}
+
+func (m *SendNotificationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendNotificationResponse.Unmarshal(m, b)
+}
+func (m *SendNotificationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendNotificationResponse.Marshal(b, m, deterministic)
+}
+func (m *SendNotificationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendNotificationResponse.Merge(m, src)
+}
+func (m *SendNotificationResponse) XXX_Size() int {
+	return xxx_messageInfo_SendNotificationResponse.Size(m)
+}
+func (m *SendNotificationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendNotificationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendNotificationResponse proto.InternalMessageInfo
+
+func (m *SendNotificationResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+type SubscribeNotificationsRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeNotificationsRequest) Reset()         { *m = SubscribeNotificationsRequest{} }
+func (m *SubscribeNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeNotificationsRequest) ProtoMessage()    {}
+func (*SubscribeNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{3}
+}
+
+func (m *SubscribeNotificationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeNotificationsRequest.Unmarshal(m, b)
+}
+func (m *SubscribeNotificationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeNotificationsRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeNotificationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeNotificationsRequest.Merge(m, src)
+}
+func (m *SubscribeNotificationsRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeNotificationsRequest.Size(m)
+}
+func (m *SubscribeNotificationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeNotificationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeNotificationsRequest proto.InternalMessageInfo
+
+func (m *SubscribeNotificationsRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type GetNotificationHistoryRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Page                 int32    `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetNotificationHistoryRequest) Reset()         { *m = GetNotificationHistoryRequest{} }
+func (m *GetNotificationHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetNotificationHistoryRequest) ProtoMessage()    {}
+func (*GetNotificationHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{4}
+}
+
+func (m *GetNotificationHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetNotificationHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetNotificationHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetNotificationHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetNotificationHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetNotificationHistoryRequest.Merge(m, src)
+}
+func (m *GetNotificationHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetNotificationHistoryRequest.Size(m)
+}
+func (m *GetNotificationHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetNotificationHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetNotificationHistoryRequest proto.InternalMessageInfo
+
+func (m *GetNotificationHistoryRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *GetNotificationHistoryRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *GetNotificationHistoryRequest) GetPage() int32 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+type GetNotificationHistoryResponse struct {
+	Notifications        []*Notification `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	TotalCount           int32           `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetNotificationHistoryResponse) Reset()         { *m = GetNotificationHistoryResponse{} }
+func (m *GetNotificationHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*GetNotificationHistoryResponse) ProtoMessage()    {}
+func (*GetNotificationHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{5}
+}
+
+func (m *GetNotificationHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetNotificationHistoryResponse.Unmarshal(m, b)
+}
+func (m *GetNotificationHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetNotificationHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *GetNotificationHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetNotificationHistoryResponse.Merge(m, src)
+}
+func (m *GetNotificationHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_GetNotificationHistoryResponse.Size(m)
+}
+func (m *GetNotificationHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetNotificationHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetNotificationHistoryResponse proto.InternalMessageInfo
+
+func (m *GetNotificationHistoryResponse) GetNotifications() []*Notification {
+	if m != nil {
+		return m.Notifications
+	}
+	return nil
+}
+
+func (m *GetNotificationHistoryResponse) GetTotalCount() int32 {
+	if m != nil {
+		return m.TotalCount
+	}
+	return 0
+}
+
+type NotificationPreference struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	EnabledChannels      []string `protobuf:"bytes,2,rep,name=enabled_channels,json=enabledChannels,proto3" json:"enabled_channels,omitempty"`
+	EnabledTypes         []string `protobuf:"bytes,3,rep,name=enabled_types,json=enabledTypes,proto3" json:"enabled_types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NotificationPreference) Reset()         { *m = NotificationPreference{} }
+func (m *NotificationPreference) String() string { return proto.CompactTextString(m) }
+func (*NotificationPreference) ProtoMessage()    {}
+func (*NotificationPreference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{6}
+}
+
+func (m *NotificationPreference) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NotificationPreference.Unmarshal(m, b)
+}
+func (m *NotificationPreference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NotificationPreference.Marshal(b, m, deterministic)
+}
+func (m *NotificationPreference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotificationPreference.Merge(m, src)
+}
+func (m *NotificationPreference) XXX_Size() int {
+	return xxx_messageInfo_NotificationPreference.Size(m)
+}
+func (m *NotificationPreference) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotificationPreference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NotificationPreference proto.InternalMessageInfo
+
+func (m *NotificationPreference) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *NotificationPreference) GetEnabledChannels() []string {
+	if m != nil {
+		return m.EnabledChannels
+	}
+	return nil
+}
+
+func (m *NotificationPreference) GetEnabledTypes() []string {
+	if m != nil {
+		return m.EnabledTypes
+	}
+	return nil
+}
+
+type SetNotificationPreferencesRequest struct {
+	Preference           *NotificationPreference `protobuf:"bytes,1,opt,name=preference,proto3" json:"preference,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *SetNotificationPreferencesRequest) Reset()         { *m = SetNotificationPreferencesRequest{} }
+func (m *SetNotificationPreferencesRequest) String() string { return proto.CompactTextString(m) }
+func (*SetNotificationPreferencesRequest) ProtoMessage()    {}
+func (*SetNotificationPreferencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{7}
+}
+
+func (m *SetNotificationPreferencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetNotificationPreferencesRequest.Unmarshal(m, b)
+}
+func (m *SetNotificationPreferencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetNotificationPreferencesRequest.Marshal(b, m, deterministic)
+}
+func (m *SetNotificationPreferencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetNotificationPreferencesRequest.Merge(m, src)
+}
+func (m *SetNotificationPreferencesRequest) XXX_Size() int {
+	return xxx_messageInfo_SetNotificationPreferencesRequest.Size(m)
+}
+func (m *SetNotificationPreferencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetNotificationPreferencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetNotificationPreferencesRequest proto.InternalMessageInfo
+
+func (m *SetNotificationPreferencesRequest) GetPreference() *NotificationPreference {
+	if m != nil {
+		return m.Preference
+	}
+	return nil
+}
+
+type SetNotificationPreferencesResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetNotificationPreferencesResponse) Reset()         { *m = SetNotificationPreferencesResponse{} }
+func (m *SetNotificationPreferencesResponse) String() string { return proto.CompactTextString(m) }
+func (*SetNotificationPreferencesResponse) ProtoMessage()    {}
+func (*SetNotificationPreferencesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{8}
+}
+
+func (m *SetNotificationPreferencesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetNotificationPreferencesResponse.Unmarshal(m, b)
+}
+func (m *SetNotificationPreferencesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetNotificationPreferencesResponse.Marshal(b, m, deterministic)
+}
+func (m *SetNotificationPreferencesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetNotificationPreferencesResponse.Merge(m, src)
+}
+func (m *SetNotificationPreferencesResponse) XXX_Size() int {
+	return xxx_messageInfo_SetNotificationPreferencesResponse.Size(m)
+}
+func (m *SetNotificationPreferencesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetNotificationPreferencesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetNotificationPreferencesResponse proto.InternalMessageInfo
+
+func (m *SetNotificationPreferencesResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+type GetNotificationPreferencesRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetNotificationPreferencesRequest) Reset()         { *m = GetNotificationPreferencesRequest{} }
+func (m *GetNotificationPreferencesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetNotificationPreferencesRequest) ProtoMessage()    {}
+func (*GetNotificationPreferencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{9}
+}
+
+func (m *GetNotificationPreferencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetNotificationPreferencesRequest.Unmarshal(m, b)
+}
+func (m *GetNotificationPreferencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetNotificationPreferencesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetNotificationPreferencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetNotificationPreferencesRequest.Merge(m, src)
+}
+func (m *GetNotificationPreferencesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetNotificationPreferencesRequest.Size(m)
+}
+func (m *GetNotificationPreferencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetNotificationPreferencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetNotificationPreferencesRequest proto.InternalMessageInfo
+
+func (m *GetNotificationPreferencesRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type GetNotificationPreferencesResponse struct {
+	Preference           *NotificationPreference `protobuf:"bytes,1,opt,name=preference,proto3" json:"preference,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *GetNotificationPreferencesResponse) Reset()         { *m = GetNotificationPreferencesResponse{} }
+func (m *GetNotificationPreferencesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetNotificationPreferencesResponse) ProtoMessage()    {}
+func (*GetNotificationPreferencesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{10}
+}
+
+func (m *GetNotificationPreferencesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetNotificationPreferencesResponse.Unmarshal(m, b)
+}
+func (m *GetNotificationPreferencesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetNotificationPreferencesResponse.Marshal(b, m, deterministic)
+}
+func (m *GetNotificationPreferencesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetNotificationPreferencesResponse.Merge(m, src)
+}
+func (m *GetNotificationPreferencesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetNotificationPreferencesResponse.Size(m)
+}
+func (m *GetNotificationPreferencesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetNotificationPreferencesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetNotificationPreferencesResponse proto.InternalMessageInfo
+
+func (m *GetNotificationPreferencesResponse) GetPreference() *NotificationPreference {
+	if m != nil {
+		return m.Preference
+	}
+	return nil
+}
+
+type AcknowledgeNotificationRequest struct {
+	NotificationId       string   `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcknowledgeNotificationRequest) Reset()         { *m = AcknowledgeNotificationRequest{} }
+func (m *AcknowledgeNotificationRequest) String() string { return proto.CompactTextString(m) }
+func (*AcknowledgeNotificationRequest) ProtoMessage()    {}
+func (*AcknowledgeNotificationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{11}
+}
+
+func (m *AcknowledgeNotificationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcknowledgeNotificationRequest.Unmarshal(m, b)
+}
+func (m *AcknowledgeNotificationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcknowledgeNotificationRequest.Marshal(b, m, deterministic)
+}
+func (m *AcknowledgeNotificationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcknowledgeNotificationRequest.Merge(m, src)
+}
+func (m *AcknowledgeNotificationRequest) XXX_Size() int {
+	return xxx_messageInfo_AcknowledgeNotificationRequest.Size(m)
+}
+func (m *AcknowledgeNotificationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcknowledgeNotificationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcknowledgeNotificationRequest proto.InternalMessageInfo
+
+func (m *AcknowledgeNotificationRequest) GetNotificationId() string {
+	if m != nil {
+		return m.NotificationId
+	}
+	return ""
+}
+
+func (m *AcknowledgeNotificationRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type AcknowledgeNotificationResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcknowledgeNotificationResponse) Reset()         { *m = AcknowledgeNotificationResponse{} }
+func (m *AcknowledgeNotificationResponse) String() string { return proto.CompactTextString(m) }
+func (*AcknowledgeNotificationResponse) ProtoMessage()    {}
+func (*AcknowledgeNotificationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c524632753ba8b80, []int{12}
+}
+
+func (m *AcknowledgeNotificationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcknowledgeNotificationResponse.Unmarshal(m, b)
+}
+func (m *AcknowledgeNotificationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcknowledgeNotificationResponse.Marshal(b, m, deterministic)
+}
+func (m *AcknowledgeNotificationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcknowledgeNotificationResponse.Merge(m, src)
+}
+func (m *AcknowledgeNotificationResponse) XXX_Size() int {
+	return xxx_messageInfo_AcknowledgeNotificationResponse.Size(m)
+}
+func (m *AcknowledgeNotificationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcknowledgeNotificationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcknowledgeNotificationResponse proto.InternalMessageInfo
+
+func (m *AcknowledgeNotificationResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Notification)(nil), "notificationservice.Notification")
+	proto.RegisterType((*SendNotificationRequest)(nil), "notificationservice.SendNotificationRequest")
+	proto.RegisterType((*SendNotificationResponse)(nil), "notificationservice.SendNotificationResponse")
+	proto.RegisterType((*SubscribeNotificationsRequest)(nil), "notificationservice.SubscribeNotificationsRequest")
+	proto.RegisterType((*GetNotificationHistoryRequest)(nil), "notificationservice.GetNotificationHistoryRequest")
+	proto.RegisterType((*GetNotificationHistoryResponse)(nil), "notificationservice.GetNotificationHistoryResponse")
+	proto.RegisterType((*NotificationPreference)(nil), "notificationservice.NotificationPreference")
+	proto.RegisterType((*SetNotificationPreferencesRequest)(nil), "notificationservice.SetNotificationPreferencesRequest")
+	proto.RegisterType((*SetNotificationPreferencesResponse)(nil), "notificationservice.SetNotificationPreferencesResponse")
+	proto.RegisterType((*GetNotificationPreferencesRequest)(nil), "notificationservice.GetNotificationPreferencesRequest")
+	proto.RegisterType((*GetNotificationPreferencesResponse)(nil), "notificationservice.GetNotificationPreferencesResponse")
+	proto.RegisterType((*AcknowledgeNotificationRequest)(nil), "notificationservice.AcknowledgeNotificationRequest")
+	proto.RegisterType((*AcknowledgeNotificationResponse)(nil), "notificationservice.AcknowledgeNotificationResponse")
+}
+
+func init() { proto.RegisterFile("notification_service.proto", fileDescriptor_c524632753ba8b80) }
+
+var fileDescriptor_c524632753ba8b80 = []byte{
+	// 616 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5d, 0x6f, 0xd3, 0x30,
+	0x14, 0x95, 0xdb, 0xb5, 0xa5, 0x77, 0xdd, 0x87, 0x3c, 0xd4, 0x5a, 0x11, 0xdb, 0x5a, 0xf3, 0x40,
+	0x11, 0x50, 0xa1, 0x0e, 0xf1, 0x21, 0xed, 0x05, 0x4d, 0x28, 0x4c, 0x48, 0x08, 0xa5, 0xbc, 0x97,
+	0x7c, 0x78, 0xc3, 0x22, 0x4d, 0xd2, 0xd8, 0x05, 0xf5, 0x11, 0x89, 0x27, 0x24, 0xfe, 0x05, 0x7f,
+	0x8a, 0x7f, 0x83, 0x9c, 0xa4, 0x9d, 0x0b, 0x49, 0xd3, 0x22, 0xde, 0xec, 0x13, 0x1f, 0xdf, 0x73,
+	0x8f, 0xef, 0xbd, 0x0a, 0x18, 0x41, 0x28, 0xf9, 0x15, 0x77, 0x6d, 0xc9, 0xc3, 0x60, 0x2c, 0x58,
+	0xfc, 0x99, 0xbb, 0x6c, 0x10, 0xc5, 0xa1, 0x0c, 0xf1, 0x91, 0xfe, 0x2d, 0xfb, 0x44, 0x7f, 0x21,
+	0x68, 0xbd, 0xd5, 0x70, 0xbc, 0x0f, 0x15, 0xee, 0x11, 0xd4, 0x45, 0xfd, 0xa6, 0x55, 0xe1, 0x1e,
+	0xee, 0x40, 0x63, 0x26, 0x58, 0x3c, 0xe6, 0x1e, 0xa9, 0x24, 0x60, 0x5d, 0x6d, 0x2f, 0x3d, 0x8c,
+	0x61, 0x47, 0xce, 0x23, 0x46, 0xaa, 0x09, 0x9a, 0xac, 0xf1, 0x6d, 0xa8, 0x49, 0x2e, 0x7d, 0x46,
+	0x76, 0x12, 0x30, 0xdd, 0xa8, 0x93, 0x4e, 0xe8, 0xcd, 0x49, 0x2d, 0x3d, 0xa9, 0xd6, 0xf8, 0x0e,
+	0x34, 0x25, 0x9f, 0x30, 0x21, 0xed, 0x49, 0x44, 0xea, 0x5d, 0xd4, 0xaf, 0x5a, 0x37, 0x00, 0x6e,
+	0x43, 0x5d, 0x48, 0x5b, 0xce, 0x04, 0x69, 0xa4, 0x31, 0xd3, 0x1d, 0x26, 0xd0, 0x70, 0x3f, 0xda,
+	0x41, 0xc0, 0x7c, 0x72, 0x2b, 0xf9, 0xb0, 0xd8, 0xaa, 0x18, 0x9e, 0x2d, 0x6d, 0xd2, 0x4c, 0x63,
+	0xa8, 0x35, 0xfd, 0x00, 0x9d, 0x11, 0x0b, 0x3c, 0x3d, 0x3d, 0x8b, 0x4d, 0x67, 0x4c, 0x48, 0xfc,
+	0x0a, 0x5a, 0xba, 0x1b, 0x49, 0xbe, 0xbb, 0xc3, 0xde, 0x20, 0xc7, 0xa2, 0xc1, 0x0a, 0x7f, 0x85,
+	0x46, 0x87, 0x40, 0xfe, 0x8e, 0x20, 0x22, 0xc5, 0xd5, 0x72, 0x40, 0x7a, 0x0e, 0xf4, 0x39, 0x1c,
+	0x8f, 0x66, 0x8e, 0x70, 0x63, 0xee, 0x30, 0x9d, 0x28, 0x16, 0xda, 0x34, 0xc7, 0x91, 0xee, 0x38,
+	0x75, 0xe0, 0xd8, 0x64, 0x52, 0xe7, 0xbc, 0xe6, 0x42, 0x86, 0xf1, 0xbc, 0x8c, 0xa9, 0xde, 0xc5,
+	0xe7, 0x13, 0x2e, 0x93, 0x27, 0xac, 0x59, 0xe9, 0x46, 0x79, 0x16, 0xd9, 0xd7, 0xe9, 0x0b, 0xd6,
+	0xac, 0x64, 0x4d, 0xbf, 0x23, 0x38, 0x29, 0x0a, 0x92, 0x25, 0x66, 0xc2, 0xde, 0x8a, 0x4d, 0x04,
+	0x75, 0xab, 0x9b, 0x99, 0xb7, 0xca, 0xc3, 0xa7, 0xb0, 0x2b, 0x43, 0x69, 0xfb, 0x63, 0x37, 0x9c,
+	0x05, 0x0b, 0x6d, 0x90, 0x40, 0x17, 0x0a, 0xa1, 0x5f, 0x11, 0xb4, 0xf5, 0x0b, 0xde, 0xc5, 0xec,
+	0x8a, 0xc5, 0x2c, 0x70, 0x59, 0x71, 0xaa, 0xf7, 0xe1, 0x90, 0x05, 0xb6, 0xe3, 0x33, 0x6f, 0x9c,
+	0xd5, 0x86, 0x20, 0x95, 0x6e, 0xb5, 0xdf, 0xb4, 0x0e, 0x32, 0xfc, 0x22, 0x83, 0xf1, 0x5d, 0xd8,
+	0x5b, 0x1c, 0x55, 0xd5, 0x2b, 0x48, 0x35, 0x39, 0xd7, 0xca, 0xc0, 0xf7, 0x0a, 0xa3, 0x11, 0xf4,
+	0x46, 0x4c, 0xe6, 0xab, 0x58, 0x3e, 0xd9, 0x1b, 0x80, 0x68, 0x89, 0x66, 0xc5, 0xf4, 0xa0, 0xd4,
+	0x8f, 0x9b, 0x8b, 0x2c, 0x8d, 0x4e, 0xcf, 0x81, 0xae, 0x8b, 0x58, 0x52, 0x5e, 0xe7, 0xd0, 0x33,
+	0x4b, 0xf5, 0x16, 0x96, 0xd8, 0x14, 0xa8, 0x59, 0x1e, 0xfb, 0xbf, 0xa6, 0xeb, 0xc0, 0xc9, 0x4b,
+	0xf7, 0x53, 0x10, 0x7e, 0xf1, 0x99, 0x77, 0xcd, 0xf2, 0x9a, 0xf5, 0x1e, 0x1c, 0xac, 0x8c, 0xb5,
+	0xa5, 0xea, 0x7d, 0x1d, 0xbe, 0x2c, 0x9e, 0x55, 0xf4, 0x05, 0x9c, 0x16, 0xc6, 0x58, 0xef, 0xe7,
+	0xf0, 0x67, 0x1d, 0x8e, 0x74, 0xc2, 0x28, 0xcd, 0x0c, 0x87, 0x70, 0xf8, 0x67, 0xeb, 0xe3, 0x87,
+	0xb9, 0x1e, 0x14, 0xcc, 0x20, 0xe3, 0xd1, 0x86, 0xa7, 0x33, 0x81, 0x53, 0x68, 0xe7, 0xcf, 0x0d,
+	0x3c, 0xcc, 0xbf, 0x68, 0xdd, 0x90, 0x31, 0xca, 0xbb, 0xf5, 0x31, 0xc2, 0xaa, 0xff, 0xf2, 0x87,
+	0x41, 0x41, 0xcc, 0xb5, 0xe3, 0xc9, 0x38, 0xdb, 0x8a, 0x93, 0xa5, 0xfd, 0x03, 0x81, 0x51, 0xdc,
+	0x0e, 0xf8, 0x69, 0x81, 0x89, 0x25, 0x1d, 0x60, 0x3c, 0xdb, 0x9a, 0xa7, 0xe9, 0x31, 0xb7, 0xd5,
+	0x63, 0xfe, 0xa3, 0x9e, 0x0d, 0x7a, 0xf1, 0x1b, 0x82, 0x4e, 0x41, 0x6d, 0xe3, 0x7c, 0xc3, 0xd7,
+	0x77, 0x9b, 0xf1, 0x64, 0x3b, 0x52, 0x2a, 0xc3, 0xa9, 0x27, 0xff, 0x18, 0x67, 0xbf, 0x07, 0x00,
+	0x0b, 0x0e, 0xc3, 0x42, 0x81, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// NotificationServiceClient is the client API for NotificationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NotificationServiceClient interface {
+	// Send a notification to a user or group
+	SendNotification(ctx context.Context, in *SendNotificationRequest, opts ...grpc.CallOption) (*SendNotificationResponse, error)
+	// Subscribe to real-time notifications (streaming)
+	SubscribeNotifications(ctx context.Context, in *SubscribeNotificationsRequest, opts ...grpc.CallOption) (NotificationService_SubscribeNotificationsClient, error)
+	// Get notification history for a user
+	GetNotificationHistory(ctx context.Context, in *GetNotificationHistoryRequest, opts ...grpc.CallOption) (*GetNotificationHistoryResponse, error)
+	// Set user notification preferences
+	SetNotificationPreferences(ctx context.Context, in *SetNotificationPreferencesRequest, opts ...grpc.CallOption) (*SetNotificationPreferencesResponse, error)
+	// Get user notification preferences
+	GetNotificationPreferences(ctx context.Context, in *GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*GetNotificationPreferencesResponse, error)
+	// Mark a notification as read/acknowledged
+	AcknowledgeNotification(ctx context.Context, in *AcknowledgeNotificationRequest, opts ...grpc.CallOption) (*AcknowledgeNotificationResponse, error)
+}
+
+type notificationServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNotificationServiceClient(cc *grpc.ClientConn) NotificationServiceClient {
+	return &notificationServiceClient{cc}
+}
+
+func (c *notificationServiceClient) SendNotification(ctx context.Context, in *SendNotificationRequest, opts ...grpc.CallOption) (*SendNotificationResponse, error) {
+	out := new(SendNotificationResponse)
+	err := c.cc.Invoke(ctx, "/notificationservice.NotificationService/SendNotification", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) SubscribeNotifications(ctx context.Context, in *SubscribeNotificationsRequest, opts ...grpc.CallOption) (NotificationService_SubscribeNotificationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_NotificationService_serviceDesc.Streams[0], "/notificationservice.NotificationService/SubscribeNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &notificationServiceSubscribeNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NotificationService_SubscribeNotificationsClient interface {
+	Recv() (*Notification, error)
+	grpc.ClientStream
+}
+
+type notificationServiceSubscribeNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *notificationServiceSubscribeNotificationsClient) Recv() (*Notification, error) {
+	m := new(Notification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *notificationServiceClient) GetNotificationHistory(ctx context.Context, in *GetNotificationHistoryRequest, opts ...grpc.CallOption) (*GetNotificationHistoryResponse, error) {
+	out := new(GetNotificationHistoryResponse)
+	err := c.cc.Invoke(ctx, "/notificationservice.NotificationService/GetNotificationHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) SetNotificationPreferences(ctx context.Context, in *SetNotificationPreferencesRequest, opts ...grpc.CallOption) (*SetNotificationPreferencesResponse, error) {
+	out := new(SetNotificationPreferencesResponse)
+	err := c.cc.Invoke(ctx, "/notificationservice.NotificationService/SetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) GetNotificationPreferences(ctx context.Context, in *GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*GetNotificationPreferencesResponse, error) {
+	out := new(GetNotificationPreferencesResponse)
+	err := c.cc.Invoke(ctx, "/notificationservice.NotificationService/GetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) AcknowledgeNotification(ctx context.Context, in *AcknowledgeNotificationRequest, opts ...grpc.CallOption) (*AcknowledgeNotificationResponse, error) {
+	out := new(AcknowledgeNotificationResponse)
+	err := c.cc.Invoke(ctx, "/notificationservice.NotificationService/AcknowledgeNotification", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotificationServiceServer is the server API for NotificationService service.
+type NotificationServiceServer interface {
+	// Send a notification to a user or group
+	SendNotification(context.Context, *SendNotificationRequest) (*SendNotificationResponse, error)
+	// Subscribe to real-time notifications (streaming)
+	SubscribeNotifications(*SubscribeNotificationsRequest, NotificationService_SubscribeNotificationsServer) error
+	// Get notification history for a user
+	GetNotificationHistory(context.Context, *GetNotificationHistoryRequest) (*GetNotificationHistoryResponse, error)
+	// Set user notification preferences
+	SetNotificationPreferences(context.Context, *SetNotificationPreferencesRequest) (*SetNotificationPreferencesResponse, error)
+	// Get user notification preferences
+	GetNotificationPreferences(context.Context, *GetNotificationPreferencesRequest) (*GetNotificationPreferencesResponse, error)
+	// Mark a notification as read/acknowledged
+	AcknowledgeNotification(context.Context, *AcknowledgeNotificationRequest) (*AcknowledgeNotificationResponse, error)
+}
+
+// UnimplementedNotificationServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedNotificationServiceServer struct {
+}
+
+func (*UnimplementedNotificationServiceServer) SendNotification(ctx context.Context, req *SendNotificationRequest) (*SendNotificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendNotification not implemented")
+}
+func (*UnimplementedNotificationServiceServer) SubscribeNotifications(req *SubscribeNotificationsRequest, srv NotificationService_SubscribeNotificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeNotifications not implemented")
+}
+func (*UnimplementedNotificationServiceServer) GetNotificationHistory(ctx context.Context, req *GetNotificationHistoryRequest) (*GetNotificationHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationHistory not implemented")
+}
+func (*UnimplementedNotificationServiceServer) SetNotificationPreferences(ctx context.Context, req *SetNotificationPreferencesRequest) (*SetNotificationPreferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNotificationPreferences not implemented")
+}
+func (*UnimplementedNotificationServiceServer) GetNotificationPreferences(ctx context.Context, req *GetNotificationPreferencesRequest) (*GetNotificationPreferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationPreferences not implemented")
+}
+func (*UnimplementedNotificationServiceServer) AcknowledgeNotification(ctx context.Context, req *AcknowledgeNotificationRequest) (*AcknowledgeNotificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcknowledgeNotification not implemented")
+}
+
+func RegisterNotificationServiceServer(s *grpc.Server, srv NotificationServiceServer) {
+	s.RegisterService(&_NotificationService_serviceDesc, srv)
+}
+
+func _NotificationService_SendNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).SendNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notificationservice.NotificationService/SendNotification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).SendNotification(ctx, req.(*SendNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_SubscribeNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotificationServiceServer).SubscribeNotifications(m, &notificationServiceSubscribeNotificationsServer{stream})
+}
+
+type NotificationService_SubscribeNotificationsServer interface {
+	Send(*Notification) error
+	grpc.ServerStream
+}
+
+type notificationServiceSubscribeNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *notificationServiceSubscribeNotificationsServer) Send(m *Notification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _NotificationService_GetNotificationHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetNotificationHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notificationservice.NotificationService/GetNotificationHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetNotificationHistory(ctx, req.(*GetNotificationHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_SetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNotificationPreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).SetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notificationservice.NotificationService/SetNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).SetNotificationPreferences(ctx, req.(*SetNotificationPreferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationPreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notificationservice.NotificationService/GetNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetNotificationPreferences(ctx, req.(*GetNotificationPreferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_AcknowledgeNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcknowledgeNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).AcknowledgeNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notificationservice.NotificationService/AcknowledgeNotification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).AcknowledgeNotification(ctx, req.(*AcknowledgeNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NotificationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "notificationservice.NotificationService",
+	HandlerType: (*NotificationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendNotification",
+			Handler:    _NotificationService_SendNotification_Handler,
+		},
+		{
+			MethodName: "GetNotificationHistory",
+			Handler:    _NotificationService_GetNotificationHistory_Handler,
+		},
+		{
+			MethodName: "SetNotificationPreferences",
+			Handler:    _NotificationService_SetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _NotificationService_GetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "AcknowledgeNotification",
+			Handler:    _NotificationService_AcknowledgeNotification_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeNotifications",
+			Handler:       _NotificationService_SubscribeNotifications_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "notification_service.proto",
+}
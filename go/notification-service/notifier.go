@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	"sync"
+)
+
+type store interface {
+	add(ctx context.Context, n *api.Notification) (bool, error)
+	getHistory(ctx context.Context, userId string, page int32, pageSize int32) ([]*api.Notification, int32, error)
+	acknowledge(ctx context.Context, notificationId string, userId string) error
+	setPreferences(ctx context.Context, userId string, p preferences) error
+	getPreferences(ctx context.Context, userId string) (preferences, error)
+}
+
+// notifier stores the notifications allowed by their user's preferences and delivers them to the user's subscribers.
+// Preferences are cached once read as the service runs as a single replica.
+type notifier struct {
+	store         store
+	subscriptions *subscriptions
+
+	mutex       sync.Mutex
+	preferences map[string]preferences
+}
+
+func newNotifier(store store, subscriptions *subscriptions) *notifier {
+	return &notifier{store: store, subscriptions: subscriptions, preferences: map[string]preferences{}}
+}
+
+// notify returns false if the notification is not allowed by the user's preferences or has already been sent.
+func (n *notifier) notify(ctx context.Context, notification *api.Notification) (bool, error) {
+	p, err := n.getPreferences(ctx, notification.UserId)
+	if err != nil {
+		return false, err
+	}
+
+	if !p.allows(notification) {
+		return false, nil
+	}
+
+	added, err := n.store.add(ctx, notification)
+	if err != nil || !added {
+		return false, err
+	}
+
+	n.subscriptions.publish(notification)
+	return true, nil
+}
+
+func (n *notifier) getPreferences(ctx context.Context, userId string) (preferences, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if p, ok := n.preferences[userId]; ok {
+		return p, nil
+	}
+
+	p, err := n.store.getPreferences(ctx, userId)
+	if err != nil {
+		return preferences{}, err
+	}
+
+	n.preferences[userId] = p
+	return p, nil
+}
+
+func (n *notifier) setPreferences(ctx context.Context, userId string, p preferences) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if err := n.store.setPreferences(ctx, userId, p); err != nil {
+		return err
+	}
+
+	n.preferences[userId] = p
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testStore struct {
+	notifications       []*api.Notification
+	preferences         map[string]preferences
+	getPreferencesCalls int
+	consumedOffsets     map[string]int64
+}
+
+func newTestStore() *testStore {
+	return &testStore{preferences: map[string]preferences{}, consumedOffsets: map[string]int64{}}
+}
+
+func (t *testStore) add(_ context.Context, n *api.Notification) (bool, error) {
+	for _, existing := range t.notifications {
+		if existing.Id == n.Id {
+			return false, nil
+		}
+	}
+
+	t.notifications = append(t.notifications, n)
+	return true, nil
+}
+
+func (t *testStore) getHistory(_ context.Context, userId string, _ int32, _ int32) ([]*api.Notification, int32, error) {
+	var result []*api.Notification
+	for i := len(t.notifications) - 1; i >= 0; i-- {
+		if t.notifications[i].UserId == userId {
+			result = append(result, t.notifications[i])
+		}
+	}
+	return result, int32(len(result)), nil
+}
+
+func (t *testStore) acknowledge(_ context.Context, notificationId string, userId string) error {
+	for _, n := range t.notifications {
+		if n.Id == notificationId && n.UserId == userId {
+			n.Status = statusRead
+			return nil
+		}
+	}
+	return errNoNotification
+}
+
+func (t *testStore) setPreferences(_ context.Context, userId string, p preferences) error {
+	t.preferences[userId] = p
+	return nil
+}
+
+func (t *testStore) getPreferences(_ context.Context, userId string) (preferences, error) {
+	t.getPreferencesCalls++
+	return t.preferences[userId], nil
+}
+
+func (t *testStore) getConsumedOffset(_ context.Context, topic string) (int64, error) {
+	if offset, ok := t.consumedOffsets[topic]; ok {
+		return offset, nil
+	}
+	return -1, nil
+}
+
+func (t *testStore) setConsumedOffset(_ context.Context, topic string, offset int64) error {
+	t.consumedOffsets[topic] = offset
+	return nil
+}
+
+func newTestNotification(id string, notificationType string) *api.Notification {
+	return &api.Notification{Id: id, UserId: "user1", Type: notificationType, Channel: channelInApp}
+}
+
+func TestNotificationsAreStoredAndDeliveredOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	subscriptions := newSubscriptions(10)
+	n := newNotifier(store, subscriptions)
+
+	subscription := subscriptions.subscribe("user1")
+	otherUser := subscriptions.subscribe("user2")
+
+	sent, err := n.notify(ctx, newTestNotification("1", typeFill))
+	assert.NoError(t, err)
+	assert.True(t, sent)
+
+	sent, err = n.notify(ctx, newTestNotification("1", typeFill))
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	assert.Len(t, store.notifications, 1)
+	assert.Len(t, subscription.notifications, 1)
+	assert.Empty(t, otherUser.notifications)
+	assert.Equal(t, 1, store.getPreferencesCalls)
+}
+
+func TestNotificationsAreFilteredByPreferences(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+	store.preferences["user1"] = preferences{types: []string{typeReject}}
+	n := newNotifier(store, newSubscriptions(10))
+
+	sent, err := n.notify(ctx, newTestNotification("1", typeFill))
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	sent, err = n.notify(ctx, newTestNotification("2", typeReject))
+	assert.NoError(t, err)
+	assert.True(t, sent)
+
+	assert.NoError(t, n.setPreferences(ctx, "user1", preferences{channels: []string{"email"}}))
+
+	sent, err = n.notify(ctx, newTestNotification("3", typeReject))
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	assert.Equal(t, []string{"2"}, notificationIds(store.notifications))
+}
+
+func TestSubscribersThatFallBehindAreDropped(t *testing.T) {
+	subscriptions := newSubscriptions(1)
+	subscription := subscriptions.subscribe("user1")
+
+	subscriptions.publish(newTestNotification("1", typeFill))
+	subscriptions.publish(newTestNotification("2", typeFill))
+
+	select {
+	case <-subscription.dropped:
+	default:
+		t.Fatal("expected subscription to be dropped")
+	}
+
+	assert.Empty(t, subscriptions.subscriptions)
+	subscriptions.unsubscribe(subscription)
+}
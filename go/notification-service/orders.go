@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
+	"github.com/shopspring/decimal"
+	"hash/fnv"
+	"log/slog"
+	"time"
+)
+
+// The types of the notifications generated from order updates
+const (
+	typeFill   = "fill"
+	typeReject = "reject"
+	typeCancel = "cancel"
+	typeError  = "error"
+)
+
+const channelInApp = "in-app"
+
+const (
+	statusUnread = "unread"
+	statusRead   = "read"
+)
+
+// orderUpdate is an order update and its offset in the orders topic.
+type orderUpdate struct {
+	order  *model.Order
+	offset int64
+}
+
+// streamOrders returns a channel of all the order updates in the orders topic from the first available offset, the
+// channel is closed if the context is cancelled or an update cannot be read.
+func streamOrders(ctx context.Context, readerConfig kafka.ReaderConfig, bufferSize int) <-chan orderUpdate {
+	out := make(chan orderUpdate, bufferSize)
+
+	go func() {
+		defer close(out)
+		reader := kafka.NewReader(readerConfig)
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing kafka reader", "error", err)
+			}
+		}()
+
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("failed to read order update", "error", err)
+				}
+				return
+			}
+
+			order := &model.Order{}
+			if err = proto.Unmarshal(msg.Value, order); err != nil {
+				slog.Error("failed to unmarshal order", "offset", msg.Offset, "error", err)
+				return
+			}
+
+			select {
+			case out <- orderUpdate{order: order, offset: msg.Offset}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// orderState is the state of an order when its last notifications were generated, the error message is the last
+// error reported to the user.
+type orderState struct {
+	tradedQuantity decimal.Decimal
+	status         model.OrderStatus
+	errorMessage   string
+}
+
+// orderNotifications generates the notifications of the user of each order as the order is filled, rejected or
+// cancelled, or an error is reported against the order, e.g. by the strategy executing it.  A rejected order is a new
+// order that is cancelled with an error message before becoming live, the error of an order that is not yet live is
+// only reported if the order is rejected.  The user of an order is its root originator ref and only orders placed by
+// the originator of the root order are notified on, the child orders of strategies are not.  Notification ids are
+// derived from the order update so that replaying the orders topic generates the same notifications.
+type orderNotifications struct {
+	orders map[string]orderState
+}
+
+func newOrderNotifications() *orderNotifications {
+	return &orderNotifications{orders: map[string]orderState{}}
+}
+
+func (o *orderNotifications) onOrder(order *model.Order) []*api.Notification {
+	if order.OriginatorId != order.RootOriginatorId || order.RootOriginatorRef == "" {
+		return nil
+	}
+
+	previous := o.orders[order.Id]
+	current := orderState{tradedQuantity: order.TradedQuantity.AsDecimal(), status: order.Status,
+		errorMessage: previous.errorMessage}
+
+	var result []*api.Notification
+
+	if current.tradedQuantity.GreaterThan(previous.tradedQuantity) {
+		fillId := order.LastExecId
+		if fillId == "" {
+			fillId = current.tradedQuantity.String()
+		}
+
+		title := "Order partially filled"
+		if order.Status == model.OrderStatus_FILLED {
+			title = "Order filled"
+		}
+
+		result = append(result, newOrderNotification(order, typeFill, fillId, title,
+			fmt.Sprintf("%v %v of listing %v at %v, %v of %v filled at an average price of %v", sideVerb(order.Side),
+				order.LastExecQuantity.AsDecimal(), order.ListingId, order.LastExecPrice.AsDecimal(),
+				current.tradedQuantity, order.Quantity.AsDecimal(), order.AvgTradePrice.AsDecimal())))
+	}
+
+	rejected := order.Status == model.OrderStatus_CANCELLED && previous.status == model.OrderStatus_NONE &&
+		order.ErrorMessage != ""
+
+	if rejected {
+		current.errorMessage = order.ErrorMessage
+		result = append(result, newOrderNotification(order, typeReject, "", "Order rejected",
+			fmt.Sprintf("%v order for %v of listing %v rejected: %v", order.Side, order.Quantity.AsDecimal(),
+				order.ListingId, order.ErrorMessage)))
+	} else if order.Status == model.OrderStatus_CANCELLED && previous.status != model.OrderStatus_CANCELLED {
+		result = append(result, newOrderNotification(order, typeCancel, "", "Order cancelled",
+			fmt.Sprintf("%v order for %v of listing %v cancelled, %v filled", order.Side,
+				order.Quantity.AsDecimal(), order.ListingId, current.tradedQuantity)))
+	}
+
+	if !rejected && order.ErrorMessage != "" && order.ErrorMessage != previous.errorMessage &&
+		order.Status != model.OrderStatus_NONE {
+		current.errorMessage = order.ErrorMessage
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(order.ErrorMessage))
+		result = append(result, newOrderNotification(order, typeError, fmt.Sprintf("%x", hash.Sum64()),
+			"Order error", order.ErrorMessage))
+	}
+
+	if order.IsTerminalState() {
+		delete(o.orders, order.Id)
+	} else {
+		o.orders[order.Id] = current
+	}
+
+	return result
+}
+
+func sideVerb(side model.Side) string {
+	if side == model.Side_SELL {
+		return "Sold"
+	}
+
+	return "Bought"
+}
+
+type orderData struct {
+	OrderId        string  `json:"orderId"`
+	ListingId      int32   `json:"listingId"`
+	Side           string  `json:"side"`
+	Status         string  `json:"status"`
+	Quantity       float64 `json:"quantity"`
+	TradedQuantity float64 `json:"tradedQuantity"`
+	AvgTradePrice  float64 `json:"avgTradePrice"`
+}
+
+func newOrderNotification(order *model.Order, notificationType string, eventId string, title string,
+	body string) *api.Notification {
+
+	id := order.Id + ":" + notificationType
+	if eventId != "" {
+		id += ":" + eventId
+	}
+
+	data, err := json.Marshal(orderData{
+		OrderId:        order.Id,
+		ListingId:      order.ListingId,
+		Side:           order.Side.String(),
+		Status:         order.Status.String(),
+		Quantity:       order.Quantity.ToFloat(),
+		TradedQuantity: order.TradedQuantity.ToFloat(),
+		AvgTradePrice:  order.AvgTradePrice.ToFloat(),
+	})
+	if err != nil {
+		slog.Error("failed to marshal order notification data", "orderId", order.Id, "error", err)
+	}
+
+	return &api.Notification{
+		Id:        id,
+		UserId:    order.RootOriginatorRef,
+		Type:      notificationType,
+		Title:     title,
+		Body:      body,
+		Timestamp: time.Now().UnixMilli(),
+		Status:    statusUnread,
+		Channel:   channelInApp,
+		Data:      string(data),
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestOrder(status model.OrderStatus, tradedQuantity int, errorMessage string) *model.Order {
+	return &model.Order{
+		Id:                "order1",
+		Side:              model.Side_BUY,
+		Status:            status,
+		Quantity:          model.IasD(10),
+		Price:             model.IasD(20),
+		ListingId:         1,
+		TradedQuantity:    model.IasD(tradedQuantity),
+		AvgTradePrice:     model.IasD(20),
+		LastExecQuantity:  model.IasD(tradedQuantity),
+		LastExecPrice:     model.IasD(20),
+		ErrorMessage:      errorMessage,
+		OriginatorId:      "desk1",
+		OriginatorRef:     "user1",
+		RootOriginatorId:  "desk1",
+		RootOriginatorRef: "user1",
+	}
+}
+
+func notificationIds(notifications []*api.Notification) []string {
+	var result []string
+	for _, n := range notifications {
+		result = append(result, n.Id)
+	}
+	return result
+}
+
+func TestFillsAndCancelsAreNotified(t *testing.T) {
+	o := newOrderNotifications()
+
+	assert.Empty(t, o.onOrder(newTestOrder(model.OrderStatus_NONE, 0, "")))
+	assert.Empty(t, o.onOrder(newTestOrder(model.OrderStatus_LIVE, 0, "")))
+
+	order := newTestOrder(model.OrderStatus_LIVE, 4, "")
+	order.LastExecId = "exec1"
+	notifications := o.onOrder(order)
+	assert.Equal(t, []string{"order1:fill:exec1"}, notificationIds(notifications))
+	assert.Equal(t, "user1", notifications[0].UserId)
+	assert.Equal(t, typeFill, notifications[0].Type)
+	assert.Equal(t, "Order partially filled", notifications[0].Title)
+	assert.Equal(t, "Bought 4 of listing 1 at 20, 4 of 10 filled at an average price of 20", notifications[0].Body)
+	assert.Equal(t, statusUnread, notifications[0].Status)
+	assert.Equal(t, channelInApp, notifications[0].Channel)
+	assert.Contains(t, notifications[0].Data, `"orderId":"order1"`)
+
+	assert.Empty(t, o.onOrder(order))
+
+	notifications = o.onOrder(newTestOrder(model.OrderStatus_CANCELLED, 4, ""))
+	assert.Equal(t, []string{"order1:cancel"}, notificationIds(notifications))
+	assert.Equal(t, "BUY order for 10 of listing 1 cancelled, 4 filled", notifications[0].Body)
+	assert.Empty(t, o.orders)
+}
+
+func TestOrderFilledInASingleUpdateIsNotifiedOnce(t *testing.T) {
+	o := newOrderNotifications()
+
+	notifications := o.onOrder(newTestOrder(model.OrderStatus_FILLED, 10, ""))
+	assert.Equal(t, []string{"order1:fill:10"}, notificationIds(notifications))
+	assert.Equal(t, "Order filled", notifications[0].Title)
+}
+
+func TestRejectsAreNotifiedWithTheirError(t *testing.T) {
+	o := newOrderNotifications()
+
+	assert.Empty(t, o.onOrder(newTestOrder(model.OrderStatus_NONE, 0, "")))
+	assert.Empty(t, o.onOrder(newTestOrder(model.OrderStatus_NONE, 0, "unknown symbol")))
+
+	notifications := o.onOrder(newTestOrder(model.OrderStatus_CANCELLED, 0, "unknown symbol"))
+	assert.Equal(t, []string{"order1:reject"}, notificationIds(notifications))
+	assert.Equal(t, typeReject, notifications[0].Type)
+	assert.Equal(t, "BUY order for 10 of listing 1 rejected: unknown symbol", notifications[0].Body)
+}
+
+func TestStrategyErrorsAreNotifiedOnceEach(t *testing.T) {
+	o := newOrderNotifications()
+
+	assert.Empty(t, o.onOrder(newTestOrder(model.OrderStatus_LIVE, 0, "")))
+
+	notifications := o.onOrder(newTestOrder(model.OrderStatus_LIVE, 0, "failed to send child order"))
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, typeError, notifications[0].Type)
+	assert.Equal(t, "failed to send child order", notifications[0].Body)
+
+	assert.Empty(t, o.onOrder(newTestOrder(model.OrderStatus_LIVE, 0, "failed to send child order")))
+
+	notifications = o.onOrder(newTestOrder(model.OrderStatus_CANCELLED, 0, "child order rejected"))
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, typeCancel, notifications[0].Type)
+	assert.Equal(t, typeError, notifications[1].Type)
+}
+
+func TestChildOrdersAreNotNotified(t *testing.T) {
+	o := newOrderNotifications()
+
+	order := newTestOrder(model.OrderStatus_FILLED, 10, "")
+	order.OriginatorId = "XVWAP"
+	order.OriginatorRef = "parentOrder1"
+	assert.Empty(t, o.onOrder(order))
+}
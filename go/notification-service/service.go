@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	common "github.com/ettec/otp-common"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/orderstore"
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	statusSent       = "sent"
+	statusSuppressed = "suppressed"
+	statusCompleted  = "completed"
+)
+
+type service struct {
+	notifier      *notifier
+	subscriptions *subscriptions
+	nextId        func() string
+}
+
+func newService(notifier *notifier, subscriptions *subscriptions) *service {
+	return &service{notifier: notifier, subscriptions: subscriptions, nextId: func() string {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}}
+}
+
+// toStatusError maps store errors to the grpc status returned to the client.
+func toStatusError(err error) error {
+	if errors.Is(err, errNoNotification) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// SendNotification sends the notification to its user if allowed by the user's preferences, the notification's id,
+// timestamp and status are set by the service and the channel defaults to in-app.
+func (s *service) SendNotification(ctx context.Context, request *api.SendNotificationRequest) (*api.SendNotificationResponse, error) {
+	n := request.Notification
+	if n == nil || n.UserId == "" || n.Type == "" {
+		return nil, status.Error(codes.InvalidArgument, "notification user id and type must be specified")
+	}
+
+	notification := &api.Notification{
+		Id:        "sent:" + s.nextId(),
+		UserId:    n.UserId,
+		Type:      n.Type,
+		Title:     n.Title,
+		Body:      n.Body,
+		Timestamp: time.Now().UnixMilli(),
+		Status:    statusUnread,
+		Channel:   n.Channel,
+		Data:      n.Data,
+	}
+
+	if notification.Channel == "" {
+		notification.Channel = channelInApp
+	}
+
+	sent, err := s.notifier.notify(ctx, notification)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if !sent {
+		return &api.SendNotificationResponse{Status: statusSuppressed}, nil
+	}
+
+	return &api.SendNotificationResponse{Status: statusSent}, nil
+}
+
+// SubscribeNotifications streams the user's notifications as they are sent, previously sent notifications are
+// retrieved with GetNotificationHistory.
+func (s *service) SubscribeNotifications(request *api.SubscribeNotificationsRequest, stream api.NotificationService_SubscribeNotificationsServer) error {
+	if request.UserId == "" {
+		return status.Error(codes.InvalidArgument, "user id must be specified")
+	}
+
+	slog.Info("subscribing to notifications", "userId", request.UserId)
+
+	subscription := s.subscriptions.subscribe(request.UserId)
+	defer func() {
+		s.subscriptions.unsubscribe(subscription)
+		slog.Info("unsubscribed from notifications", "userId", request.UserId)
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-subscription.dropped:
+			return status.Error(codes.ResourceExhausted, "subscriber fell behind the notifications sent, resubscribe and get the notification history")
+		case notification := <-subscription.notifications:
+			if err := stream.Send(notification); err != nil {
+				return fmt.Errorf("failed to send notification: %w", err)
+			}
+		}
+	}
+}
+
+func (s *service) GetNotificationHistory(ctx context.Context, request *api.GetNotificationHistoryRequest) (*api.GetNotificationHistoryResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id must be specified")
+	}
+
+	notifications, totalCount, err := s.notifier.store.getHistory(ctx, request.UserId, request.Page, request.Limit)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.GetNotificationHistoryResponse{Notifications: notifications, TotalCount: totalCount}, nil
+}
+
+func (s *service) SetNotificationPreferences(ctx context.Context, request *api.SetNotificationPreferencesRequest) (*api.SetNotificationPreferencesResponse, error) {
+	p := request.Preference
+	if p == nil || p.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "preference user id must be specified")
+	}
+
+	if err := s.notifier.setPreferences(ctx, p.UserId, preferences{channels: p.EnabledChannels,
+		types: p.EnabledTypes}); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.SetNotificationPreferencesResponse{Status: statusCompleted}, nil
+}
+
+func (s *service) GetNotificationPreferences(ctx context.Context, request *api.GetNotificationPreferencesRequest) (*api.GetNotificationPreferencesResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id must be specified")
+	}
+
+	p, err := s.notifier.getPreferences(ctx, request.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.GetNotificationPreferencesResponse{Preference: &api.NotificationPreference{UserId: request.UserId,
+		EnabledChannels: p.channels, EnabledTypes: p.types}}, nil
+}
+
+func (s *service) AcknowledgeNotification(ctx context.Context, request *api.AcknowledgeNotificationRequest) (*api.AcknowledgeNotificationResponse, error) {
+	if request.NotificationId == "" || request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "notification id and user id must be specified")
+	}
+
+	if err := s.notifier.store.acknowledge(ctx, request.NotificationId, request.UserId); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.AcknowledgeNotificationResponse{Status: statusCompleted}, nil
+}
+
+type offsetStore interface {
+	getConsumedOffset(ctx context.Context, topic string) (int64, error)
+	setConsumedOffset(ctx context.Context, topic string, offset int64) error
+}
+
+// notifyOrders sends the notifications generated from the order updates until the context is cancelled or the orders
+// channel is closed.  The updates are replayed from the start of the topic to rebuild the state of each order, but
+// only the updates after the consumed offset are notified on so that notifications the user's preferences suppressed
+// are not sent if the preferences have since changed.  The consumed offset is stored after each update that generates
+// notifications.
+func notifyOrders(ctx context.Context, notifier *notifier, offsets offsetStore, topic string,
+	updates <-chan orderUpdate) error {
+
+	consumedOffset, err := offsets.getConsumedOffset(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("notifying order updates", "topic", topic, "consumedOffset", consumedOffset)
+
+	orderNotifications := newOrderNotifications()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return errors.New("orders channel closed")
+			}
+
+			notifications := orderNotifications.onOrder(update.order)
+			if update.offset <= consumedOffset || len(notifications) == 0 {
+				continue
+			}
+
+			for _, notification := range notifications {
+				if _, err := notifier.notify(ctx, notification); err != nil {
+					return fmt.Errorf("failed to send notification %v: %w", notification.Id, err)
+				}
+			}
+
+			if err := offsets.setConsumedOffset(ctx, topic, update.offset); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	dbString := bootstrap.GetEnvVar("DB_CONN_STRING")
+	dbDriverName := bootstrap.GetEnvVar("DB_DRIVER_NAME")
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+	subscriberBufferSize := bootstrap.GetOptionalIntEnvVar("SUBSCRIBER_BUFFER_SIZE", 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sqlStore, err := newSqlStore(dbDriverName, dbString)
+	if err != nil {
+		log.Panicf("failed to create notification store: %v", err)
+	}
+	defer func() {
+		if err := sqlStore.Close(); err != nil {
+			slog.Error("error closing notification store", "error", err)
+		}
+	}()
+
+	subscriptions := newSubscriptions(subscriberBufferSize)
+	n := newNotifier(sqlStore, subscriptions)
+
+	port := "50551"
+	slog.Info("Starting notification service", "port", port)
+	listener, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	s := grpc.NewServer()
+	api.RegisterNotificationServiceServer(s, newService(n, subscriptions))
+	reflection.Register(s)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+		s.GracefulStop()
+	}()
+
+	go func() {
+		updates := streamOrders(ctx, orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers),
+			bootstrap.GetOptionalIntEnvVar("ORDERS_BUFFER_SIZE", 1000))
+		if err := notifyOrders(ctx, n, sqlStore, common.ORDERS_TOPIC, updates); err != nil {
+			log.Panicf("order notifications failed: %v", err)
+		}
+	}()
+
+	if err := s.Serve(listener); err != nil {
+		log.Panicf("Error while serving : %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func newTestService() *service {
+	subscriptions := newSubscriptions(10)
+	return newService(newNotifier(newTestStore(), subscriptions), subscriptions)
+}
+
+func TestInvalidRequestsAreRejected(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService()
+
+	_, err := s.SendNotification(ctx, &api.SendNotificationRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.SendNotification(ctx, &api.SendNotificationRequest{Notification: &api.Notification{UserId: "user1"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.GetNotificationHistory(ctx, &api.GetNotificationHistoryRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.SetNotificationPreferences(ctx, &api.SetNotificationPreferencesRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.AcknowledgeNotification(ctx, &api.AcknowledgeNotificationRequest{NotificationId: "1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSentNotificationsAreAcknowledged(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService()
+
+	response, err := s.SendNotification(ctx, &api.SendNotificationRequest{Notification: &api.Notification{
+		UserId: "user1", Type: "alert", Title: "Market closing", Status: statusRead}})
+	assert.NoError(t, err)
+	assert.Equal(t, statusSent, response.Status)
+
+	history, err := s.GetNotificationHistory(ctx, &api.GetNotificationHistoryRequest{UserId: "user1"})
+	assert.NoError(t, err)
+	if !assert.Len(t, history.Notifications, 1) {
+		return
+	}
+
+	notification := history.Notifications[0]
+	assert.NotEmpty(t, notification.Id)
+	assert.Equal(t, statusUnread, notification.Status)
+	assert.Equal(t, channelInApp, notification.Channel)
+
+	_, err = s.AcknowledgeNotification(ctx, &api.AcknowledgeNotificationRequest{NotificationId: notification.Id,
+		UserId: "user2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.AcknowledgeNotification(ctx, &api.AcknowledgeNotificationRequest{NotificationId: notification.Id,
+		UserId: "user1"})
+	assert.NoError(t, err)
+	assert.Equal(t, statusRead, notification.Status)
+
+	_, err = s.SetNotificationPreferences(ctx, &api.SetNotificationPreferencesRequest{Preference: &api.NotificationPreference{
+		UserId: "user1", EnabledTypes: []string{typeFill}}})
+	assert.NoError(t, err)
+
+	preference, err := s.GetNotificationPreferences(ctx, &api.GetNotificationPreferencesRequest{UserId: "user1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{typeFill}, preference.Preference.EnabledTypes)
+
+	response, err = s.SendNotification(ctx, &api.SendNotificationRequest{Notification: &api.Notification{
+		UserId: "user1", Type: "alert"}})
+	assert.NoError(t, err)
+	assert.Equal(t, statusSuppressed, response.Status)
+}
+
+func replayOrders(t *testing.T, store *testStore, orders ...*model.Order) {
+	updates := make(chan orderUpdate, len(orders))
+	for i, order := range orders {
+		updates <- orderUpdate{order: order, offset: int64(i)}
+	}
+	close(updates)
+
+	err := notifyOrders(context.Background(), newNotifier(store, newSubscriptions(10)), store, "orders", updates)
+	assert.EqualError(t, err, "orders channel closed")
+}
+
+func TestReplayedOrdersAreNotNotifiedAgain(t *testing.T) {
+	store := newTestStore()
+	store.preferences["user1"] = preferences{types: []string{typeCancel}}
+
+	fill := newTestOrder(model.OrderStatus_LIVE, 4, "")
+	fill.LastExecId = "exec1"
+	orders := []*model.Order{newTestOrder(model.OrderStatus_LIVE, 0, ""), fill,
+		newTestOrder(model.OrderStatus_LIVE, 4, "")}
+
+	replayOrders(t, store, orders...)
+	assert.Empty(t, store.notifications)
+	assert.Equal(t, int64(1), store.consumedOffsets["orders"])
+
+	// the fill suppressed by the user's preferences is not sent when the orders are replayed after the preferences
+	// change, the state of the order is still rebuilt from the replayed updates
+	store.preferences["user1"] = preferences{}
+	orders = append(orders, newTestOrder(model.OrderStatus_CANCELLED, 4, ""))
+	replayOrders(t, store, orders...)
+
+	assert.Equal(t, []string{"order1:cancel"}, notificationIds(store.notifications))
+	assert.Equal(t, "BUY order for 10 of listing 1 cancelled, 4 filled", store.notifications[0].Body)
+	assert.Equal(t, int64(3), store.consumedOffsets["orders"])
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	"github.com/lib/pq"
+	"time"
+)
+
+var errNoNotification = errors.New("no notification found")
+
+const defaultPageSize = 50
+const maxPageSize = 1000
+
+// preferences are the types and channels of notification a user receives, an empty list enables all types or
+// channels.
+type preferences struct {
+	channels []string
+	types    []string
+}
+
+func (p preferences) allows(notification *api.Notification) bool {
+	return enabled(p.types, notification.Type) && enabled(p.channels, notification.Channel)
+}
+
+func enabled(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sqlStore persists notifications and user preferences in the notifications schema.
+type sqlStore struct {
+	db *sql.DB
+}
+
+func newSqlStore(driverName string, dbConnString string) (*sqlStore, error) {
+	db, err := sql.Open(driverName, dbConnString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &sqlStore{db: db}, nil
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// add stores the notification and returns false if a notification with the same id has already been stored.
+func (s *sqlStore) add(ctx context.Context, n *api.Notification) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `INSERT INTO notifications.notifications
+		(id, user_id, type, title, body, created, status, channel, data) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING`, n.Id, n.UserId, n.Type, n.Title, n.Body,
+		time.UnixMilli(n.Timestamp), n.Status, n.Channel, n.Data)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert notification: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get inserted notification count: %w", err)
+	}
+
+	return inserted == 1, nil
+}
+
+// getHistory returns a page of the user's notifications, most recent first, and the total number of the user's
+// notifications.  Pages are numbered from 1.
+func (s *sqlStore) getHistory(ctx context.Context, userId string, page int32, pageSize int32) ([]*api.Notification,
+	int32, error) {
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	} else if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var totalCount int32
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications.notifications WHERE user_id = $1`,
+		userId).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, user_id, type, title, body, created, status, channel, data
+		FROM notifications.notifications WHERE user_id = $1 ORDER BY created DESC, id DESC LIMIT $2 OFFSET $3`,
+		userId, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*api.Notification
+	for rows.Next() {
+		n := &api.Notification{}
+		var created time.Time
+		if err := rows.Scan(&n.Id, &n.UserId, &n.Type, &n.Title, &n.Body, &created, &n.Status, &n.Channel,
+			&n.Data); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+
+		n.Timestamp = created.UnixMilli()
+		result = append(result, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read notifications: %w", err)
+	}
+
+	return result, totalCount, nil
+}
+
+// acknowledge marks the user's notification as read.
+func (s *sqlStore) acknowledge(ctx context.Context, notificationId string, userId string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE notifications.notifications SET status = $1
+		WHERE id = $2 AND user_id = $3`, statusRead, notificationId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge notification: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get acknowledged notification count: %w", err)
+	}
+
+	if updated == 0 {
+		return errNoNotification
+	}
+
+	return nil
+}
+
+func (s *sqlStore) setPreferences(ctx context.Context, userId string, p preferences) error {
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO notifications.preferences (user_id, enabled_channels, enabled_types)
+		VALUES ($1, $2, $3) ON CONFLICT (user_id) DO UPDATE SET enabled_channels = $2, enabled_types = $3`,
+		userId, pq.Array(p.channels), pq.Array(p.types)); err != nil {
+		return fmt.Errorf("failed to set preferences: %w", err)
+	}
+
+	return nil
+}
+
+// getPreferences returns the user's preferences, a user that has not set any preferences receives all notifications.
+func (s *sqlStore) getPreferences(ctx context.Context, userId string) (preferences, error) {
+	p := preferences{}
+	err := s.db.QueryRowContext(ctx, `SELECT enabled_channels, enabled_types FROM notifications.preferences
+		WHERE user_id = $1`, userId).Scan(pq.Array(&p.channels), pq.Array(&p.types))
+	if errors.Is(err, sql.ErrNoRows) {
+		return preferences{}, nil
+	}
+	if err != nil {
+		return preferences{}, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	return p, nil
+}
+
+// getConsumedOffset returns the offset of the last update of the topic that notifications were generated from, or -1
+// if no notifications have been generated from the topic.
+func (s *sqlStore) getConsumedOffset(ctx context.Context, topic string) (int64, error) {
+	var offset int64
+	err := s.db.QueryRowContext(ctx, `SELECT last_offset FROM notifications.consumed_offsets WHERE topic = $1`,
+		topic).Scan(&offset)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get consumed offset: %w", err)
+	}
+
+	return offset, nil
+}
+
+func (s *sqlStore) setConsumedOffset(ctx context.Context, topic string, offset int64) error {
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO notifications.consumed_offsets (topic, last_offset) VALUES ($1, $2)
+		ON CONFLICT (topic) DO UPDATE SET last_offset = $2`, topic, offset); err != nil {
+		return fmt.Errorf("failed to set consumed offset: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	api "github.com/ettec/open-trading-platform/go/notification-service/api/notificationservice"
+	"sync"
+)
+
+// subscriptions distributes notifications to the subscribers of the notification's user.  Notifications are not
+// conflated, a subscriber whose buffer is full is dropped and must resubscribe and retrieve the notifications it
+// missed from the history.
+type subscriptions struct {
+	mutex         sync.Mutex
+	bufferSize    int
+	subscriptions map[string]map[*subscription]bool
+}
+
+type subscription struct {
+	userId        string
+	notifications chan *api.Notification
+	dropped       chan struct{}
+}
+
+func newSubscriptions(bufferSize int) *subscriptions {
+	return &subscriptions{bufferSize: bufferSize, subscriptions: map[string]map[*subscription]bool{}}
+}
+
+func (s *subscriptions) subscribe(userId string) *subscription {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub := &subscription{
+		userId:        userId,
+		notifications: make(chan *api.Notification, s.bufferSize),
+		dropped:       make(chan struct{}),
+	}
+
+	if s.subscriptions[userId] == nil {
+		s.subscriptions[userId] = map[*subscription]bool{}
+	}
+	s.subscriptions[userId][sub] = true
+
+	return sub
+}
+
+func (s *subscriptions) unsubscribe(sub *subscription) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.remove(sub)
+}
+
+func (s *subscriptions) remove(sub *subscription) {
+	delete(s.subscriptions[sub.userId], sub)
+	if len(s.subscriptions[sub.userId]) == 0 {
+		delete(s.subscriptions, sub.userId)
+	}
+}
+
+func (s *subscriptions) publish(notification *api.Notification) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for sub := range s.subscriptions[notification.UserId] {
+		select {
+		case sub.notifications <- notification:
+		default:
+			s.remove(sub)
+			close(sub.dropped)
+		}
+	}
+}
@@ -6,7 +6,7 @@ package ordermonitor
 import (
 	context "context"
 	fmt "fmt"
-	"github.com/ettec/otp-common/model"
+	model "github.com/ettec/otp-common/model"
 	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -25,6 +25,37 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type HaltScope int32
+
+const (
+	HaltScope_GLOBAL     HaltScope = 0
+	HaltScope_ORIGINATOR HaltScope = 1
+	HaltScope_DESK       HaltScope = 2
+	HaltScope_LISTING    HaltScope = 3
+)
+
+var HaltScope_name = map[int32]string{
+	0: "GLOBAL",
+	1: "ORIGINATOR",
+	2: "DESK",
+	3: "LISTING",
+}
+
+var HaltScope_value = map[string]int32{
+	"GLOBAL":     0,
+	"ORIGINATOR": 1,
+	"DESK":       2,
+	"LISTING":    3,
+}
+
+func (x HaltScope) String() string {
+	return proto.EnumName(HaltScope_name, int32(x))
+}
+
+func (HaltScope) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c65b7020bfdcf3d2, []int{0}
+}
+
 type CancelAllOrdersForOriginatorIdParams struct {
 	OriginatorId         string   `protobuf:"bytes,1,opt,name=originatorId,proto3" json:"originatorId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -64,24 +95,258 @@ func (m *CancelAllOrdersForOriginatorIdParams) GetOriginatorId() string {
 	return ""
 }
 
+// The scopeId is the originator id, desk or listing id of the halt and is empty for a global halt
+type HaltTradingParams struct {
+	Scope                HaltScope `protobuf:"varint,1,opt,name=scope,proto3,enum=ordermonitor.HaltScope" json:"scope,omitempty"`
+	ScopeId              string    `protobuf:"bytes,2,opt,name=scopeId,proto3" json:"scopeId,omitempty"`
+	Reason               string    `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *HaltTradingParams) Reset()         { *m = HaltTradingParams{} }
+func (m *HaltTradingParams) String() string { return proto.CompactTextString(m) }
+func (*HaltTradingParams) ProtoMessage()    {}
+func (*HaltTradingParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c65b7020bfdcf3d2, []int{1}
+}
+
+func (m *HaltTradingParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HaltTradingParams.Unmarshal(m, b)
+}
+func (m *HaltTradingParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HaltTradingParams.Marshal(b, m, deterministic)
+}
+func (m *HaltTradingParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HaltTradingParams.Merge(m, src)
+}
+func (m *HaltTradingParams) XXX_Size() int {
+	return xxx_messageInfo_HaltTradingParams.Size(m)
+}
+func (m *HaltTradingParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_HaltTradingParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HaltTradingParams proto.InternalMessageInfo
+
+func (m *HaltTradingParams) GetScope() HaltScope {
+	if m != nil {
+		return m.Scope
+	}
+	return HaltScope_GLOBAL
+}
+
+func (m *HaltTradingParams) GetScopeId() string {
+	if m != nil {
+		return m.ScopeId
+	}
+	return ""
+}
+
+func (m *HaltTradingParams) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type LiftHaltParams struct {
+	Scope                HaltScope `protobuf:"varint,1,opt,name=scope,proto3,enum=ordermonitor.HaltScope" json:"scope,omitempty"`
+	ScopeId              string    `protobuf:"bytes,2,opt,name=scopeId,proto3" json:"scopeId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *LiftHaltParams) Reset()         { *m = LiftHaltParams{} }
+func (m *LiftHaltParams) String() string { return proto.CompactTextString(m) }
+func (*LiftHaltParams) ProtoMessage()    {}
+func (*LiftHaltParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c65b7020bfdcf3d2, []int{2}
+}
+
+func (m *LiftHaltParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LiftHaltParams.Unmarshal(m, b)
+}
+func (m *LiftHaltParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LiftHaltParams.Marshal(b, m, deterministic)
+}
+func (m *LiftHaltParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LiftHaltParams.Merge(m, src)
+}
+func (m *LiftHaltParams) XXX_Size() int {
+	return xxx_messageInfo_LiftHaltParams.Size(m)
+}
+func (m *LiftHaltParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_LiftHaltParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LiftHaltParams proto.InternalMessageInfo
+
+func (m *LiftHaltParams) GetScope() HaltScope {
+	if m != nil {
+		return m.Scope
+	}
+	return HaltScope_GLOBAL
+}
+
+func (m *LiftHaltParams) GetScopeId() string {
+	if m != nil {
+		return m.ScopeId
+	}
+	return ""
+}
+
+type Halt struct {
+	Scope                HaltScope        `protobuf:"varint,1,opt,name=scope,proto3,enum=ordermonitor.HaltScope" json:"scope,omitempty"`
+	ScopeId              string           `protobuf:"bytes,2,opt,name=scopeId,proto3" json:"scopeId,omitempty"`
+	Reason               string           `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	HaltedBy             string           `protobuf:"bytes,4,opt,name=haltedBy,proto3" json:"haltedBy,omitempty"`
+	Created              *model.Timestamp `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Halt) Reset()         { *m = Halt{} }
+func (m *Halt) String() string { return proto.CompactTextString(m) }
+func (*Halt) ProtoMessage()    {}
+func (*Halt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c65b7020bfdcf3d2, []int{3}
+}
+
+func (m *Halt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Halt.Unmarshal(m, b)
+}
+func (m *Halt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Halt.Marshal(b, m, deterministic)
+}
+func (m *Halt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Halt.Merge(m, src)
+}
+func (m *Halt) XXX_Size() int {
+	return xxx_messageInfo_Halt.Size(m)
+}
+func (m *Halt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Halt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Halt proto.InternalMessageInfo
+
+func (m *Halt) GetScope() HaltScope {
+	if m != nil {
+		return m.Scope
+	}
+	return HaltScope_GLOBAL
+}
+
+func (m *Halt) GetScopeId() string {
+	if m != nil {
+		return m.ScopeId
+	}
+	return ""
+}
+
+func (m *Halt) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Halt) GetHaltedBy() string {
+	if m != nil {
+		return m.HaltedBy
+	}
+	return ""
+}
+
+func (m *Halt) GetCreated() *model.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+type Halts struct {
+	Halts                []*Halt  `protobuf:"bytes,1,rep,name=halts,proto3" json:"halts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Halts) Reset()         { *m = Halts{} }
+func (m *Halts) String() string { return proto.CompactTextString(m) }
+func (*Halts) ProtoMessage()    {}
+func (*Halts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c65b7020bfdcf3d2, []int{4}
+}
+
+func (m *Halts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Halts.Unmarshal(m, b)
+}
+func (m *Halts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Halts.Marshal(b, m, deterministic)
+}
+func (m *Halts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Halts.Merge(m, src)
+}
+func (m *Halts) XXX_Size() int {
+	return xxx_messageInfo_Halts.Size(m)
+}
+func (m *Halts) XXX_DiscardUnknown() {
+	xxx_messageInfo_Halts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Halts proto.InternalMessageInfo
+
+func (m *Halts) GetHalts() []*Halt {
+	if m != nil {
+		return m.Halts
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("ordermonitor.HaltScope", HaltScope_name, HaltScope_value)
 	proto.RegisterType((*CancelAllOrdersForOriginatorIdParams)(nil), "ordermonitor.CancelAllOrdersForOriginatorIdParams")
+	proto.RegisterType((*HaltTradingParams)(nil), "ordermonitor.HaltTradingParams")
+	proto.RegisterType((*LiftHaltParams)(nil), "ordermonitor.LiftHaltParams")
+	proto.RegisterType((*Halt)(nil), "ordermonitor.Halt")
+	proto.RegisterType((*Halts)(nil), "ordermonitor.Halts")
 }
 
 func init() { proto.RegisterFile("ordermonitor.proto", fileDescriptor_c65b7020bfdcf3d2) }
 
 var fileDescriptor_c65b7020bfdcf3d2 = []byte{
-	// 159 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xca, 0x2f, 0x4a, 0x49,
-	0x2d, 0xca, 0xcd, 0xcf, 0xcb, 0x2c, 0xc9, 0x2f, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2,
-	0x41, 0x16, 0x93, 0x12, 0xcc, 0xcd, 0x4f, 0x49, 0xcd, 0x49, 0xce, 0xcf, 0xcd, 0xcd, 0xcf, 0x83,
-	0x28, 0x50, 0xf2, 0xe2, 0x52, 0x71, 0x4e, 0xcc, 0x4b, 0x4e, 0xcd, 0x71, 0xcc, 0xc9, 0xf1, 0x07,
-	0xa9, 0x2d, 0x76, 0xcb, 0x2f, 0xf2, 0x2f, 0xca, 0x4c, 0xcf, 0xcc, 0x4b, 0x2c, 0xc9, 0x2f, 0xf2,
-	0x4c, 0x09, 0x48, 0x2c, 0x4a, 0xcc, 0x2d, 0x16, 0x52, 0xe2, 0xe2, 0xc9, 0x47, 0x12, 0x95, 0x60,
-	0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x42, 0x11, 0x33, 0x2a, 0xe1, 0xe2, 0x01, 0x1b, 0xe1, 0x0b, 0xb1,
-	0x4e, 0x28, 0x85, 0x4b, 0x0e, 0xbf, 0xd9, 0x42, 0x46, 0x7a, 0x28, 0x6e, 0x26, 0xc6, 0x25, 0x52,
-	0x3c, 0x7a, 0x60, 0x5f, 0xe8, 0xb9, 0xe6, 0x16, 0x94, 0x54, 0x2a, 0x31, 0x24, 0xb1, 0x81, 0x3d,
-	0x62, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0x3f, 0x0b, 0xc4, 0xd9, 0xff, 0x00, 0x00, 0x00,
+	// 410 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x53, 0x4d, 0x8b, 0xd4, 0x40,
+	0x10, 0x9d, 0xcc, 0xf7, 0xd6, 0x0c, 0x43, 0xb6, 0x04, 0x0d, 0x83, 0xe8, 0xd0, 0x78, 0x18, 0x16,
+	0x8c, 0x18, 0x2f, 0x9e, 0x16, 0x66, 0x75, 0x8d, 0xd1, 0xb8, 0x91, 0x4c, 0x2e, 0x1e, 0xdb, 0x74,
+	0xbb, 0x06, 0xd2, 0xe9, 0xd0, 0xdd, 0x97, 0xfd, 0x35, 0xfe, 0x04, 0xff, 0xa2, 0xa4, 0x33, 0xb3,
+	0x4c, 0x08, 0x88, 0x07, 0xf7, 0x96, 0xaa, 0x7a, 0xaf, 0xea, 0xbd, 0xe4, 0x05, 0x50, 0x2a, 0xc6,
+	0x95, 0x90, 0x55, 0x61, 0xa4, 0xf2, 0x6b, 0x25, 0x8d, 0xc4, 0xe5, 0x69, 0x6f, 0x7d, 0x2e, 0x24,
+	0xe3, 0x65, 0x2e, 0x85, 0x90, 0x55, 0x0b, 0x20, 0x9f, 0xe0, 0xc5, 0x3b, 0x5a, 0xe5, 0xbc, 0xdc,
+	0x95, 0x65, 0xd2, 0x60, 0xf5, 0x07, 0xa9, 0x12, 0x55, 0xdc, 0x16, 0x15, 0x35, 0x52, 0x45, 0xec,
+	0x2b, 0x55, 0x54, 0x68, 0x24, 0xb0, 0x94, 0x27, 0x5d, 0xcf, 0xd9, 0x38, 0xdb, 0xb3, 0xb4, 0xd3,
+	0x23, 0x06, 0xce, 0x3f, 0xd2, 0xd2, 0x64, 0x8a, 0xb2, 0xa2, 0xba, 0x3d, 0x10, 0x5f, 0xc2, 0x44,
+	0xe7, 0xb2, 0xe6, 0x96, 0xb1, 0x0a, 0x9e, 0xf8, 0x1d, 0x95, 0x0d, 0x7e, 0xdf, 0x8c, 0xd3, 0x16,
+	0x85, 0x1e, 0xcc, 0xec, 0x43, 0xc4, 0xbc, 0xa1, 0x3d, 0x71, 0x2c, 0xf1, 0x31, 0x4c, 0x15, 0xa7,
+	0x5a, 0x56, 0xde, 0xc8, 0x0e, 0x0e, 0x15, 0xf9, 0x06, 0xab, 0xb8, 0xf8, 0x61, 0x9a, 0x4d, 0xff,
+	0xf9, 0x24, 0xf9, 0xed, 0xc0, 0xb8, 0x81, 0x3f, 0xb8, 0x09, 0x5c, 0xc3, 0xfc, 0x27, 0x2d, 0x0d,
+	0x67, 0x57, 0x77, 0xde, 0xd8, 0x4e, 0xee, 0x6b, 0xbc, 0x80, 0x59, 0xae, 0x38, 0x35, 0x9c, 0x79,
+	0x93, 0x8d, 0xb3, 0x5d, 0x04, 0xae, 0x6f, 0xbf, 0xa3, 0x9f, 0x15, 0x82, 0x6b, 0x43, 0x45, 0x9d,
+	0x1e, 0x01, 0xe4, 0x35, 0x4c, 0x1a, 0x35, 0x1a, 0xb7, 0x30, 0x69, 0x16, 0x68, 0xcf, 0xd9, 0x8c,
+	0xb6, 0x8b, 0x00, 0xfb, 0x8a, 0xd3, 0x16, 0x70, 0x71, 0x09, 0x67, 0xf7, 0x06, 0x10, 0x60, 0x1a,
+	0xc6, 0xc9, 0xd5, 0x2e, 0x76, 0x07, 0xb8, 0x02, 0x48, 0xd2, 0x28, 0x8c, 0x6e, 0x76, 0x59, 0x92,
+	0xba, 0x0e, 0xce, 0x61, 0xfc, 0xfe, 0x7a, 0xff, 0xd9, 0x1d, 0xe2, 0x02, 0x66, 0x71, 0xb4, 0xcf,
+	0xa2, 0x9b, 0xd0, 0x1d, 0x05, 0xbf, 0x86, 0xb0, 0xb4, 0xc9, 0xf9, 0xd2, 0x2e, 0x47, 0x06, 0xcf,
+	0xfe, 0x1e, 0x29, 0x0c, 0xba, 0x6a, 0xfe, 0x25, 0x80, 0xeb, 0xe5, 0xc1, 0xf4, 0xb5, 0xa8, 0xcd,
+	0x1d, 0x19, 0xe0, 0x25, 0x2c, 0x4e, 0xc2, 0x86, 0xcf, 0xfb, 0x06, 0x3b, 0x39, 0xec, 0xf1, 0xdf,
+	0xc2, 0xfc, 0x18, 0x1b, 0x7c, 0xda, 0x25, 0x77, 0xe3, 0xd4, 0x63, 0xbe, 0x82, 0x79, 0xc8, 0x4d,
+	0xfb, 0x9a, 0x3b, 0xb3, 0xf5, 0xa3, 0xbe, 0x08, 0x4d, 0x06, 0xdf, 0xa7, 0xf6, 0x57, 0x7b, 0xf3,
+	0x67, 0x00, 0x14, 0x51, 0x9f, 0x40, 0xa1, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -97,6 +362,9 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type OrderMonitorClient interface {
 	CancelAllOrdersForOriginatorId(ctx context.Context, in *CancelAllOrdersForOriginatorIdParams, opts ...grpc.CallOption) (*model.Empty, error)
+	HaltTrading(ctx context.Context, in *HaltTradingParams, opts ...grpc.CallOption) (*model.Empty, error)
+	LiftHalt(ctx context.Context, in *LiftHaltParams, opts ...grpc.CallOption) (*model.Empty, error)
+	GetHalts(ctx context.Context, in *model.Empty, opts ...grpc.CallOption) (*Halts, error)
 }
 
 type orderMonitorClient struct {
@@ -116,9 +384,39 @@ func (c *orderMonitorClient) CancelAllOrdersForOriginatorId(ctx context.Context,
 	return out, nil
 }
 
+func (c *orderMonitorClient) HaltTrading(ctx context.Context, in *HaltTradingParams, opts ...grpc.CallOption) (*model.Empty, error) {
+	out := new(model.Empty)
+	err := c.cc.Invoke(ctx, "/ordermonitor.OrderMonitor/HaltTrading", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderMonitorClient) LiftHalt(ctx context.Context, in *LiftHaltParams, opts ...grpc.CallOption) (*model.Empty, error) {
+	out := new(model.Empty)
+	err := c.cc.Invoke(ctx, "/ordermonitor.OrderMonitor/LiftHalt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderMonitorClient) GetHalts(ctx context.Context, in *model.Empty, opts ...grpc.CallOption) (*Halts, error) {
+	out := new(Halts)
+	err := c.cc.Invoke(ctx, "/ordermonitor.OrderMonitor/GetHalts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderMonitorServer is the server API for OrderMonitor service.
 type OrderMonitorServer interface {
 	CancelAllOrdersForOriginatorId(context.Context, *CancelAllOrdersForOriginatorIdParams) (*model.Empty, error)
+	HaltTrading(context.Context, *HaltTradingParams) (*model.Empty, error)
+	LiftHalt(context.Context, *LiftHaltParams) (*model.Empty, error)
+	GetHalts(context.Context, *model.Empty) (*Halts, error)
 }
 
 // UnimplementedOrderMonitorServer can be embedded to have forward compatible implementations.
@@ -128,6 +426,15 @@ type UnimplementedOrderMonitorServer struct {
 func (*UnimplementedOrderMonitorServer) CancelAllOrdersForOriginatorId(ctx context.Context, req *CancelAllOrdersForOriginatorIdParams) (*model.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelAllOrdersForOriginatorId not implemented")
 }
+func (*UnimplementedOrderMonitorServer) HaltTrading(ctx context.Context, req *HaltTradingParams) (*model.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HaltTrading not implemented")
+}
+func (*UnimplementedOrderMonitorServer) LiftHalt(ctx context.Context, req *LiftHaltParams) (*model.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LiftHalt not implemented")
+}
+func (*UnimplementedOrderMonitorServer) GetHalts(ctx context.Context, req *model.Empty) (*Halts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHalts not implemented")
+}
 
 func RegisterOrderMonitorServer(s *grpc.Server, srv OrderMonitorServer) {
 	s.RegisterService(&_OrderMonitor_serviceDesc, srv)
@@ -151,6 +458,60 @@ func _OrderMonitor_CancelAllOrdersForOriginatorId_Handler(srv interface{}, ctx c
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderMonitor_HaltTrading_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HaltTradingParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderMonitorServer).HaltTrading(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ordermonitor.OrderMonitor/HaltTrading",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderMonitorServer).HaltTrading(ctx, req.(*HaltTradingParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderMonitor_LiftHalt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LiftHaltParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderMonitorServer).LiftHalt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ordermonitor.OrderMonitor/LiftHalt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderMonitorServer).LiftHalt(ctx, req.(*LiftHaltParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderMonitor_GetHalts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(model.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderMonitorServer).GetHalts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ordermonitor.OrderMonitor/GetHalts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderMonitorServer).GetHalts(ctx, req.(*model.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _OrderMonitor_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "ordermonitor.OrderMonitor",
 	HandlerType: (*OrderMonitorServer)(nil),
@@ -159,6 +520,18 @@ var _OrderMonitor_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CancelAllOrdersForOriginatorId",
 			Handler:    _OrderMonitor_CancelAllOrdersForOriginatorId_Handler,
 		},
+		{
+			MethodName: "HaltTrading",
+			Handler:    _OrderMonitor_HaltTrading_Handler,
+		},
+		{
+			MethodName: "LiftHalt",
+			Handler:    _OrderMonitor_LiftHalt_Handler,
+		},
+		{
+			MethodName: "GetHalts",
+			Handler:    _OrderMonitor_GetHalts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "ordermonitor.proto",
@@ -3,9 +3,12 @@ module github.com/ettech/open-trading-platform/go/order-monitor
 go 1.21
 
 require (
+	github.com/ettec/open-trading-platform/go/shared v0.0.0
 	github.com/ettec/otp-common v1.4.2
 	github.com/golang/protobuf v1.4.2
 	github.com/prometheus/client_golang v1.7.1
+	github.com/segmentio/kafka-go v0.3.4
+	github.com/stretchr/testify v1.4.0
 	google.golang.org/grpc v1.25.1
 )
 
@@ -21,10 +24,10 @@ require (
 	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/prometheus/client_model v0.2.0 // indirect
 	github.com/prometheus/common v0.10.0 // indirect
 	github.com/prometheus/procfs v0.1.3 // indirect
-	github.com/segmentio/kafka-go v0.3.4 // indirect
 	github.com/shopspring/decimal v0.0.0-20191009025716-f1972eb1d1f5 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/crypto v0.0.0-20200220183623-bac4c82f6975 // indirect
@@ -45,3 +48,5 @@ require (
 	k8s.io/utils v0.0.0-20191114184206-e782cd3c129f // indirect
 	sigs.k8s.io/yaml v1.1.0 // indirect
 )
+
+replace github.com/ettec/open-trading-platform/go/shared => ../shared
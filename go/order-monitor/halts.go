@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ettec/open-trading-platform/go/shared/halts"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettech/open-trading-platform/go/order-monitor/api/ordermonitor"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+	"sync"
+)
+
+type haltsReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	ReadLag(ctx context.Context) (int64, error)
+	Close() error
+}
+
+type haltsWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// haltStore persists the active trading halts to a compacted kafka topic keyed by halt scope and scope id, lifting a
+// halt writes a tombstone for its key.  The order router reads this topic to reject new orders within the scope of a
+// halt.  The topic must have a single partition.
+type haltStore struct {
+	mutex  sync.Mutex
+	halts  map[string]*halts.Halt
+	writer haltsWriter
+}
+
+// newHaltStore loads the active halts from the reader, which is closed once the halts have been loaded.
+func newHaltStore(ctx context.Context, reader haltsReader, writer haltsWriter) (*haltStore, error) {
+	halts, err := loadHalts(ctx, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load halts: %w", err)
+	}
+
+	return &haltStore{
+		halts:  halts,
+		writer: writer,
+	}, nil
+}
+
+func loadHalts(ctx context.Context, reader haltsReader) (map[string]*halts.Halt, error) {
+	defer func() {
+		if err := reader.Close(); err != nil {
+			slog.Error("error closing halts reader", "error", err)
+		}
+	}()
+
+	result := map[string]*halts.Halt{}
+	for {
+		lag, err := reader.ReadLag(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lag: %w", err)
+		}
+
+		if lag <= 0 {
+			break
+		}
+
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message: %w", err)
+		}
+
+		key := string(msg.Key)
+		if len(msg.Value) == 0 {
+			delete(result, key)
+			continue
+		}
+
+		halt := &halts.Halt{}
+		if err := json.Unmarshal(msg.Value, halt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal halt: %w", err)
+		}
+
+		if _, ok := ordermonitor.HaltScope_value[halt.Scope]; !ok {
+			return nil, fmt.Errorf("unknown halt scope: %v", halt.Scope)
+		}
+
+		result[key] = halt
+	}
+
+	slog.Info("loaded trading halts", "halts", result)
+
+	return result, nil
+}
+
+func (h *haltStore) add(ctx context.Context, halt *halts.Halt) error {
+	haltJson, err := json.Marshal(halt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal halt: %w", err)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := halt.Key()
+	if err := h.writer.WriteMessages(ctx, kafka.Message{Key: []byte(key), Value: haltJson}); err != nil {
+		return fmt.Errorf("failed to write halt: %w", err)
+	}
+
+	h.halts[key] = halt
+
+	return nil
+}
+
+func (h *haltStore) remove(ctx context.Context, scope ordermonitor.HaltScope, scopeId string) (bool, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := halts.Key(scope.String(), scopeId)
+	if _, ok := h.halts[key]; !ok {
+		return false, nil
+	}
+
+	if err := h.writer.WriteMessages(ctx, kafka.Message{Key: []byte(key)}); err != nil {
+		return false, fmt.Errorf("failed to write halt tombstone: %w", err)
+	}
+
+	delete(h.halts, key)
+
+	return true, nil
+}
+
+func (h *haltStore) getHalts() []*ordermonitor.Halt {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	result := make([]*ordermonitor.Halt, 0, len(h.halts))
+	for _, halt := range h.halts {
+		result = append(result, &ordermonitor.Halt{
+			Scope:    ordermonitor.HaltScope(ordermonitor.HaltScope_value[halt.Scope]),
+			ScopeId:  halt.ScopeId,
+			Reason:   halt.Reason,
+			HaltedBy: halt.HaltedBy,
+			Created:  model.NewTimeStamp(halt.Created),
+		})
+	}
+
+	return result
+}
+
+// getHaltForOrder returns the first active halt that applies to the order, or nil if the order is not halted.
+func (h *haltStore) getHaltForOrder(order *model.Order) *halts.Halt {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, halt := range h.halts {
+		if haltAppliesToOrder(halt, order) {
+			return halt
+		}
+	}
+
+	return nil
+}
+
+func haltAppliesToOrder(halt *halts.Halt, order *model.Order) bool {
+	return halt.AppliesTo(order.GetOriginatorId(), order.GetRootOriginatorId(), order.GetListingId())
+}
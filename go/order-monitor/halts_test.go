@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/ettec/open-trading-platform/go/shared/halts"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettech/open-trading-platform/go/order-monitor/api/ordermonitor"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testHaltsReader struct {
+	msgs   []kafka.Message
+	closed bool
+}
+
+func (t *testHaltsReader) ReadMessage(context.Context) (kafka.Message, error) {
+	if len(t.msgs) == 0 {
+		return kafka.Message{}, errors.New("no more messages")
+	}
+
+	msg := t.msgs[0]
+	t.msgs = t.msgs[1:]
+	return msg, nil
+}
+
+func (t *testHaltsReader) ReadLag(context.Context) (int64, error) {
+	return int64(len(t.msgs)), nil
+}
+
+func (t *testHaltsReader) Close() error {
+	t.closed = true
+	return nil
+}
+
+type testHaltsWriter struct {
+	written []kafka.Message
+	err     error
+}
+
+func (t *testHaltsWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if t.err != nil {
+		return t.err
+	}
+
+	t.written = append(t.written, msgs...)
+	return nil
+}
+
+func haltMessage(key string, value string) kafka.Message {
+	msg := kafka.Message{Key: []byte(key)}
+	if value != "" {
+		msg.Value = []byte(value)
+	}
+	return msg
+}
+
+func newTestHaltStore(t *testing.T, writer *testHaltsWriter, msgs ...kafka.Message) *haltStore {
+	store, err := newHaltStore(context.Background(), &testHaltsReader{msgs: msgs}, writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestHaltStoreLoadsActiveHalts(t *testing.T) {
+	reader := &testHaltsReader{msgs: []kafka.Message{
+		haltMessage("DESK/deskA", `{"scope":"DESK","scopeId":"deskA","reason":"desk limit","haltedBy":"bob","created":"2024-03-01T10:00:00Z"}`),
+		haltMessage("LISTING/7", `{"scope":"LISTING","scopeId":"7","reason":"news pending"}`),
+		haltMessage("LISTING/7", ""),
+		haltMessage("DESK/deskA", `{"scope":"DESK","scopeId":"deskA","reason":"desk loss limit","haltedBy":"alice","created":"2024-03-01T11:00:00Z"}`),
+	}}
+
+	store, err := newHaltStore(context.Background(), reader, &testHaltsWriter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, reader.closed)
+	assert.Equal(t, []*ordermonitor.Halt{{
+		Scope:    ordermonitor.HaltScope_DESK,
+		ScopeId:  "deskA",
+		Reason:   "desk loss limit",
+		HaltedBy: "alice",
+		Created:  model.NewTimeStamp(time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)),
+	}}, store.getHalts())
+}
+
+func TestHaltStoreFailsToLoadUnknownScope(t *testing.T) {
+	_, err := newHaltStore(context.Background(), &testHaltsReader{msgs: []kafka.Message{
+		haltMessage("MARKET/XNAS", `{"scope":"MARKET","scopeId":"XNAS"}`),
+	}}, &testHaltsWriter{})
+
+	assert.Error(t, err)
+}
+
+func TestHaltStorePersistsHalts(t *testing.T) {
+	writer := &testHaltsWriter{}
+	store := newTestHaltStore(t, writer)
+
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	err := store.add(context.Background(), &halts.Halt{Scope: halts.Desk, ScopeId: "deskA", Reason: "desk limit",
+		HaltedBy: "bob", Created: created})
+	assert.NoError(t, err)
+
+	lifted, err := store.remove(context.Background(), ordermonitor.HaltScope_DESK, "deskA")
+	assert.NoError(t, err)
+	assert.True(t, lifted)
+
+	lifted, err = store.remove(context.Background(), ordermonitor.HaltScope_DESK, "deskA")
+	assert.NoError(t, err)
+	assert.False(t, lifted)
+
+	assert.Equal(t, []kafka.Message{
+		haltMessage("DESK/deskA", `{"scope":"DESK","scopeId":"deskA","reason":"desk limit","haltedBy":"bob","created":"2024-03-01T10:00:00Z"}`),
+		haltMessage("DESK/deskA", ""),
+	}, writer.written)
+	assert.Empty(t, store.getHalts())
+
+	// the persisted messages restore the same halts
+	reloaded := newTestHaltStore(t, &testHaltsWriter{}, writer.written[:1]...)
+	assert.Equal(t, []*ordermonitor.Halt{{Scope: ordermonitor.HaltScope_DESK, ScopeId: "deskA", Reason: "desk limit",
+		HaltedBy: "bob", Created: model.NewTimeStamp(created)}}, reloaded.getHalts())
+}
+
+func TestHaltStoreDoesNotChangeHaltsThatFailToPersist(t *testing.T) {
+	writer := &testHaltsWriter{}
+	store := newTestHaltStore(t, writer)
+
+	assert.NoError(t, store.add(context.Background(), &halts.Halt{Scope: halts.Listing, ScopeId: "7"}))
+
+	writer.err = errors.New("broker unavailable")
+	assert.Error(t, store.add(context.Background(), &halts.Halt{Scope: halts.Global}))
+
+	_, err := store.remove(context.Background(), ordermonitor.HaltScope_LISTING, "7")
+	assert.Error(t, err)
+
+	halted := store.getHalts()
+	assert.Equal(t, 1, len(halted))
+	assert.Equal(t, ordermonitor.HaltScope_LISTING, halted[0].Scope)
+}
+
+func TestGetHaltForOrder(t *testing.T) {
+	store := newTestHaltStore(t, &testHaltsWriter{},
+		haltMessage("DESK/deskA", `{"scope":"DESK","scopeId":"deskA"}`),
+		haltMessage("LISTING/7", `{"scope":"LISTING","scopeId":"7"}`))
+
+	tests := []struct {
+		name      string
+		order     *model.Order
+		wantScope string
+	}{
+		{"halted desk", &model.Order{OriginatorId: "strategy-1", RootOriginatorId: "deskA", ListingId: 8}, halts.Desk},
+		{"halted listing", &model.Order{OriginatorId: "strategy-2", RootOriginatorId: "deskB", ListingId: 7}, halts.Listing},
+		{"not halted", &model.Order{OriginatorId: "strategy-2", RootOriginatorId: "deskB", ListingId: 8}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			halt := store.getHaltForOrder(tt.order)
+			if tt.wantScope == "" {
+				assert.Nil(t, halt)
+			} else if assert.NotNil(t, halt) {
+				assert.Equal(t, tt.wantScope, halt.Scope)
+			}
+		})
+	}
+}
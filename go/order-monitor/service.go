@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/ettec/open-trading-platform/go/shared/halts"
 	common "github.com/ettec/otp-common"
 	"github.com/ettec/otp-common/api"
 	"github.com/ettec/otp-common/api/executionvenue"
@@ -14,13 +16,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"log"
 	"log/slog"
 	"net"
 	"net/http"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"os"
@@ -66,18 +73,115 @@ var pendingCancelOrders = promauto.NewGauge(prometheus.GaugeOpts{
 	Help: "The number of pending cancel orders",
 })
 
+var riskRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "risk_rejections",
+	Help: "The number of orders rejected by the order router's pre-trade risk checks",
+}, []string{"reason", "scope"})
+
+type cancelOrdersRequest struct {
+	description string
+	matches     func(order *model.Order) bool
+}
+
 type orderMonitor struct {
-	cancelOrdersForOriginatorChan chan *ordermonitor.CancelAllOrdersForOriginatorIdParams
-	kafkaBrokers                  []string
-	orderUpdatesBufSize           int
-	ordersAfter                   time.Time
+	cancelOrdersChan    chan cancelOrdersRequest
+	kafkaBrokers        []string
+	orderUpdatesBufSize int
+	ordersAfter         time.Time
+	halts               *haltStore
 }
 
 func (m *orderMonitor) CancelAllOrdersForOriginatorId(_ context.Context, params *ordermonitor.CancelAllOrdersForOriginatorIdParams) (*model.Empty, error) {
-	m.cancelOrdersForOriginatorChan <- params
+	m.cancelOrdersChan <- cancelOrdersRequest{
+		description: "cancel all orders for originator " + params.OriginatorId,
+		matches: func(order *model.Order) bool {
+			return order.GetOriginatorId() == params.OriginatorId
+		},
+	}
+	return &model.Empty{}, nil
+}
+
+func (m *orderMonitor) HaltTrading(ctx context.Context, params *ordermonitor.HaltTradingParams) (*model.Empty, error) {
+	if err := validateHaltScope(params.Scope, params.ScopeId); err != nil {
+		return nil, err
+	}
+
+	halt := &halts.Halt{
+		Scope:    params.Scope.String(),
+		ScopeId:  params.ScopeId,
+		Reason:   params.Reason,
+		HaltedBy: getUserName(ctx),
+		Created:  time.Now(),
+	}
+
+	if err := m.halts.add(ctx, halt); err != nil {
+		slog.Error("failed to halt trading", "halt", halt, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to halt trading: %v", err)
+	}
+
+	slog.Info("trading halted", "halt", halt)
+
+	m.cancelOrdersChan <- cancelOrdersRequest{
+		description: "halt trading for " + halt.Key(),
+		matches: func(order *model.Order) bool {
+			return haltAppliesToOrder(halt, order)
+		},
+	}
+
+	return &model.Empty{}, nil
+}
+
+func (m *orderMonitor) LiftHalt(ctx context.Context, params *ordermonitor.LiftHaltParams) (*model.Empty, error) {
+	lifted, err := m.halts.remove(ctx, params.Scope, params.ScopeId)
+	if err != nil {
+		slog.Error("failed to lift halt", "params", params, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to lift halt: %v", err)
+	}
+
+	if !lifted {
+		return nil, status.Errorf(codes.NotFound, "no halt found for %v", halts.Key(params.Scope.String(), params.ScopeId))
+	}
+
+	slog.Info("trading halt lifted", "params", params, "liftedBy", getUserName(ctx))
+
 	return &model.Empty{}, nil
 }
 
+func (m *orderMonitor) GetHalts(context.Context, *model.Empty) (*ordermonitor.Halts, error) {
+	return &ordermonitor.Halts{Halts: m.halts.getHalts()}, nil
+}
+
+func validateHaltScope(scope ordermonitor.HaltScope, scopeId string) error {
+	switch scope {
+	case ordermonitor.HaltScope_GLOBAL:
+		if scopeId != "" {
+			return status.Error(codes.InvalidArgument, "a global halt must not have a scope id")
+		}
+	case ordermonitor.HaltScope_ORIGINATOR, ordermonitor.HaltScope_DESK:
+		if scopeId == "" {
+			return status.Errorf(codes.InvalidArgument, "a scope id is required for a %v halt", scope)
+		}
+	case ordermonitor.HaltScope_LISTING:
+		if _, err := strconv.Atoi(scopeId); err != nil {
+			return status.Errorf(codes.InvalidArgument, "the scope id of a listing halt must be a listing id: %v", err)
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown halt scope %v", scope)
+	}
+
+	return nil
+}
+
+func getUserName(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if usernames := md.Get("user-name"); len(usernames) == 1 {
+			return usernames[0]
+		}
+	}
+
+	return ""
+}
+
 func main() {
 
 	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
@@ -87,16 +191,29 @@ func main() {
 	kafkaBrokersString := bootstrap.GetEnvVar("KAFKA_BROKERS")
 	cancelTimeoutDuration := time.Duration(bootstrap.GetOptionalIntEnvVar("CANCEL_TIMEOUT_SECS", 5)) * time.Second
 	orderUpdatesBufSize := bootstrap.GetOptionalIntEnvVar("INBOUND_ORDER_UPDATES_BUFFER_SIZE", 1000)
+	riskRejectionsTopic := bootstrap.GetOptionalEnvVar("RISK_REJECTIONS_TOPIC", "risk-rejections")
+	haltsTopic := bootstrap.GetOptionalEnvVar("TRADING_HALTS_TOPIC", "trading-halts")
 
-	cancelAllCmdChan := make(chan *ordermonitor.CancelAllOrdersForOriginatorIdParams)
+	kafkaBrokers := strings.Split(kafkaBrokersString, ",")
 	now := time.Now()
 	ordersAfter := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
 
+	haltsWriterConfig := orderstore.DefaultWriterConfig(haltsTopic, kafkaBrokers)
+	haltsWriterConfig.Async = false
+	haltsWriterConfig.Balancer = &kafka.Hash{}
+
+	haltStore, err := newHaltStore(context.Background(), kafka.NewReader(orderstore.DefaultReaderConfig(haltsTopic, kafkaBrokers)),
+		kafka.NewWriter(haltsWriterConfig))
+	if err != nil {
+		log.Panicf("failed to create halt store: %v", err)
+	}
+
 	om := &orderMonitor{
-		cancelOrdersForOriginatorChan: cancelAllCmdChan,
-		kafkaBrokers:                  strings.Split(kafkaBrokersString, ","),
-		orderUpdatesBufSize:           orderUpdatesBufSize,
-		ordersAfter:                   ordersAfter,
+		cancelOrdersChan:    make(chan cancelOrdersRequest),
+		kafkaBrokers:        kafkaBrokers,
+		orderUpdatesBufSize: orderUpdatesBufSize,
+		ordersAfter:         ordersAfter,
+		halts:               haltStore,
 	}
 
 	http.Handle("/metrics", promhttp.Handler())
@@ -118,6 +235,8 @@ func main() {
 		log.Panicf("failed to start cancel all handler: %v", err)
 	}
 
+	om.startRiskRejectionMonitoring(ctx, riskRejectionsTopic)
+
 	s := grpc.NewServer()
 	ordermonitor.RegisterOrderMonitorServer(s, om)
 	reflection.Register(s)
@@ -169,61 +288,86 @@ func (m *orderMonitor) startCancelAllHandler(ctx context.Context, maxConnectRetr
 			return
 		}
 
-		nonTerminatedOrders := make(map[string]*model.Order)
-		for _, order := range orders {
-			if !order.IsTerminalState() {
-				nonTerminatedOrders[order.GetId()] = order
-			}
+		m.handleCancelRequests(ctx, orders, updates, func(cancelParams []*executionvenue.CancelOrderParams) {
+			go cancelOrders(ctx, orderRouter, cancelParams, cancelTimeoutDuration)
+		})
+	}()
+	return err
+}
+
+// handleCancelRequests tracks the non terminated orders and cancels those that match the cancel requests, orders first
+// seen after trading has been halted for their scope are also cancelled.  It returns when the context is cancelled.
+func (m *orderMonitor) handleCancelRequests(ctx context.Context, orders map[string]*model.Order, updates <-chan *model.Order,
+	cancel func(cancelParams []*executionvenue.CancelOrderParams)) {
+
+	nonTerminatedOrders := make(map[string]*model.Order)
+	for _, order := range orders {
+		if !order.IsTerminalState() {
+			nonTerminatedOrders[order.GetId()] = order
 		}
+	}
 
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case update := <-updates:
-				slog.Info("got order update", "order", update)
-				if update.IsTerminalState() {
-					delete(nonTerminatedOrders, update.GetId())
-				} else {
-					nonTerminatedOrders[update.GetId()] = update
-				}
-			case cr := <-m.cancelOrdersForOriginatorChan:
-				slog.Info("cancelling all orders for root originator", "originatorId", cr.OriginatorId)
-				var cancelParams []*executionvenue.CancelOrderParams
-				for _, order := range nonTerminatedOrders {
-					if order.GetOriginatorId() == cr.OriginatorId {
-						cancelParams = append(cancelParams, &executionvenue.CancelOrderParams{
-							OrderId:   order.GetId(),
-							ListingId: order.GetListingId(),
-							OwnerId:   order.GetOwnerId(),
-						})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update := <-updates:
+			slog.Info("got order update", "order", update)
+			if update.IsTerminalState() {
+				delete(nonTerminatedOrders, update.GetId())
+			} else {
+				_, known := nonTerminatedOrders[update.GetId()]
+				nonTerminatedOrders[update.GetId()] = update
+
+				// Orders routed before the order router received a halt are cancelled as they are seen
+				if !known && update.GetTargetStatus() != model.OrderStatus_CANCELLED {
+					if halt := m.halts.getHaltForOrder(update); halt != nil {
+						slog.Info("cancelling order created after trading halt", "orderId", update.GetId(), "halt", halt)
+						cancel([]*executionvenue.CancelOrderParams{getCancelParams(update)})
 					}
 				}
+			}
+		case cr := <-m.cancelOrdersChan:
+			slog.Info("cancelling orders", "request", cr.description)
+			var cancelParams []*executionvenue.CancelOrderParams
+			for _, order := range nonTerminatedOrders {
+				if cr.matches(order) {
+					cancelParams = append(cancelParams, getCancelParams(order))
+				}
+			}
 
-				numOrdersToCancel := len(cancelParams)
-				slog.Info("cancellable orders found for originator id", "numCancellableOrders", numOrdersToCancel,
-					"originatorId", cr.OriginatorId)
-
-				go func() {
-					for _, params := range cancelParams {
-						deadline, cancel := context.WithDeadline(ctx, time.Now().Add(cancelTimeoutDuration))
-						_, err = orderRouter.CancelOrder(deadline, params)
-						cancel()
-						if err != nil {
-							if !errors.Is(err, context.DeadlineExceeded) {
-								slog.Error("Failed to cancel order", "orderId", params.GetOrderId(), "error", err)
-							} else {
-								slog.Error("Deadline exceed, failed to cancel order", "orderId", params.GetOrderId())
-							}
-						} else {
-							slog.Info("Cancelled order", "orderId", params.GetOrderId())
-						}
-					}
-				}()
+			slog.Info("cancellable orders found", "numCancellableOrders", len(cancelParams),
+				"request", cr.description)
+
+			cancel(cancelParams)
+		}
+	}
+}
+
+func getCancelParams(order *model.Order) *executionvenue.CancelOrderParams {
+	return &executionvenue.CancelOrderParams{
+		OrderId:   order.GetId(),
+		ListingId: order.GetListingId(),
+		OwnerId:   order.GetOwnerId(),
+	}
+}
+
+func cancelOrders(ctx context.Context, orderRouter executionvenue.ExecutionVenueClient,
+	cancelParams []*executionvenue.CancelOrderParams, cancelTimeoutDuration time.Duration) {
+	for _, params := range cancelParams {
+		deadline, cancel := context.WithDeadline(ctx, time.Now().Add(cancelTimeoutDuration))
+		_, err := orderRouter.CancelOrder(deadline, params)
+		cancel()
+		if err != nil {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				slog.Error("Failed to cancel order", "orderId", params.GetOrderId(), "error", err)
+			} else {
+				slog.Error("Deadline exceed, failed to cancel order", "orderId", params.GetOrderId())
 			}
+		} else {
+			slog.Info("Cancelled order", "orderId", params.GetOrderId())
 		}
-	}()
-	return err
+	}
 }
 
 func (m *orderMonitor) startOrderStatusMonitoring(ctx context.Context, logAllOrderUpdates bool) error {
@@ -291,6 +435,78 @@ func (m *orderMonitor) startOrderStatusMonitoring(ctx context.Context, logAllOrd
 	return nil
 }
 
+type riskRejection struct {
+	Reason           string `json:"reason"`
+	Scope            string `json:"scope"`
+	User             string `json:"user"`
+	OriginatorId     string `json:"originatorId"`
+	RootOriginatorId string `json:"rootOriginatorId"`
+	Detail           string `json:"detail"`
+}
+
+const maxRiskRejectionReadBackoff = 30 * time.Second
+
+type riskRejectionReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+}
+
+// startRiskRejectionMonitoring counts the pre-trade risk rejections published by the order router since the start of
+// the day.
+func (m *orderMonitor) startRiskRejectionMonitoring(ctx context.Context, topic string) {
+	reader := kafka.NewReader(orderstore.DefaultReaderConfig(topic, m.kafkaBrokers))
+
+	go func() {
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing risk rejections reader", "error", err)
+			}
+		}()
+
+		if err := reader.SetOffsetAt(ctx, m.ordersAfter); err != nil {
+			slog.Warn("failed to set risk rejections reader offset to start of day, counting from the start of the topic",
+				"error", err)
+		}
+
+		countRiskRejections(ctx, reader, time.Second)
+	}()
+}
+
+// countRiskRejections counts the risk rejections read until the context is cancelled, a failed read is retried with an
+// exponential backoff.
+func countRiskRejections(ctx context.Context, reader riskRejectionReader, initialBackoff time.Duration) {
+	backoff := initialBackoff
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			slog.Error("failed to read risk rejection, retrying", "backoff", backoff, "error", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(2*backoff, maxRiskRejectionReadBackoff)
+			continue
+		}
+
+		backoff = initialBackoff
+
+		rejection := riskRejection{}
+		if err := json.Unmarshal(msg.Value, &rejection); err != nil {
+			slog.Error("failed to unmarshal risk rejection", "error", err)
+			continue
+		}
+
+		slog.Info("order rejected by pre-trade risk check", "rejection", rejection)
+		riskRejections.WithLabelValues(rejection.Reason, rejection.Scope).Inc()
+	}
+}
+
 func getOrderStatusGauge(order *model.Order) (prometheus.Gauge, error) {
 
 	if order.TargetStatus == model.OrderStatus_CANCELLED {
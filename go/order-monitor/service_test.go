@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/ettec/otp-common/api/executionvenue"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettech/open-trading-platform/go/order-monitor/api/ordermonitor"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func newTestOrderMonitor(ctx context.Context, t *testing.T, writer *testHaltsWriter, orders map[string]*model.Order) (
+	*orderMonitor, chan<- *model.Order, <-chan []*executionvenue.CancelOrderParams) {
+
+	om := &orderMonitor{
+		cancelOrdersChan: make(chan cancelOrdersRequest),
+		halts:            newTestHaltStore(t, writer),
+	}
+
+	updates := make(chan *model.Order)
+	cancelled := make(chan []*executionvenue.CancelOrderParams, 10)
+	go om.handleCancelRequests(ctx, orders, updates, func(cancelParams []*executionvenue.CancelOrderParams) {
+		cancelled <- cancelParams
+	})
+
+	return om, updates, cancelled
+}
+
+func liveOrder(id string, rootOriginatorId string, listingId int32) *model.Order {
+	return &model.Order{Id: id, OwnerId: "owner", OriginatorId: rootOriginatorId + "-strategy",
+		RootOriginatorId: rootOriginatorId, ListingId: listingId, Status: model.OrderStatus_LIVE,
+		TargetStatus: model.OrderStatus_NONE}
+}
+
+func cancelParamsFor(orders ...*model.Order) []*executionvenue.CancelOrderParams {
+	var result []*executionvenue.CancelOrderParams
+	for _, order := range orders {
+		result = append(result, getCancelParams(order))
+	}
+	return result
+}
+
+func nextCancel(t *testing.T, cancelled <-chan []*executionvenue.CancelOrderParams) []*executionvenue.CancelOrderParams {
+	select {
+	case cancelParams := <-cancelled:
+		return cancelParams
+	case <-time.After(time.Second):
+		t.Fatal("expected orders to be cancelled")
+		return nil
+	}
+}
+
+func TestHaltTradingCancelsOrdersWithinScope(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deskAOrder := liveOrder("1", "deskA", 7)
+	deskBOrder := liveOrder("2", "deskB", 7)
+	filledDeskAOrder := liveOrder("3", "deskA", 7)
+	filledDeskAOrder.Status = model.OrderStatus_FILLED
+
+	writer := &testHaltsWriter{}
+	om, updates, cancelled := newTestOrderMonitor(ctx, t, writer, map[string]*model.Order{
+		"1": deskAOrder, "2": deskBOrder, "3": filledDeskAOrder})
+
+	userCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("user-name", "bob"))
+	_, err := om.HaltTrading(userCtx, &ordermonitor.HaltTradingParams{Scope: ordermonitor.HaltScope_DESK,
+		ScopeId: "deskA", Reason: "desk limit"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, cancelParamsFor(deskAOrder), nextCancel(t, cancelled))
+	assert.Equal(t, 1, len(writer.written))
+
+	halted := om.halts.getHalts()
+	if assert.Equal(t, 1, len(halted)) {
+		assert.Equal(t, "bob", halted[0].HaltedBy)
+		assert.Equal(t, "desk limit", halted[0].Reason)
+	}
+
+	// orders the order router accepted before it received the halt are cancelled when first seen
+	newDeskBOrder := liveOrder("4", "deskB", 8)
+	newDeskAOrder := liveOrder("5", "deskA", 8)
+	updates <- newDeskBOrder
+	updates <- newDeskAOrder
+	assert.Equal(t, cancelParamsFor(newDeskAOrder), nextCancel(t, cancelled))
+
+	// an update to an order that is already known does not cancel it again
+	pendingCancel := liveOrder("5", "deskA", 8)
+	pendingCancel.TargetStatus = model.OrderStatus_CANCELLED
+	updates <- pendingCancel
+
+	_, err = om.LiftHalt(userCtx, &ordermonitor.LiftHaltParams{Scope: ordermonitor.HaltScope_DESK, ScopeId: "deskA"})
+	assert.NoError(t, err)
+	assert.Empty(t, om.halts.getHalts())
+	assert.Equal(t, 2, len(writer.written))
+
+	updates <- liveOrder("6", "deskA", 8)
+
+	_, err = om.CancelAllOrdersForOriginatorId(ctx, &ordermonitor.CancelAllOrdersForOriginatorIdParams{
+		OriginatorId: "deskB-strategy"})
+	assert.NoError(t, err)
+
+	cancelParams := nextCancel(t, cancelled)
+	assert.ElementsMatch(t, cancelParamsFor(deskBOrder, newDeskBOrder), cancelParams)
+}
+
+func TestHaltTradingFailsIfHaltNotPersisted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	order := liveOrder("1", "deskA", 7)
+	writer := &testHaltsWriter{err: errors.New("broker unavailable")}
+	om, _, cancelled := newTestOrderMonitor(ctx, t, writer, map[string]*model.Order{"1": order})
+
+	_, err := om.HaltTrading(ctx, &ordermonitor.HaltTradingParams{Scope: ordermonitor.HaltScope_GLOBAL})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Empty(t, om.halts.getHalts())
+	assert.Empty(t, cancelled)
+}
+
+func TestHaltTradingRejectsInvalidScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *ordermonitor.HaltTradingParams
+	}{
+		{"global with scope id", &ordermonitor.HaltTradingParams{Scope: ordermonitor.HaltScope_GLOBAL, ScopeId: "deskA"}},
+		{"originator without scope id", &ordermonitor.HaltTradingParams{Scope: ordermonitor.HaltScope_ORIGINATOR}},
+		{"desk without scope id", &ordermonitor.HaltTradingParams{Scope: ordermonitor.HaltScope_DESK}},
+		{"listing with non numeric id", &ordermonitor.HaltTradingParams{Scope: ordermonitor.HaltScope_LISTING, ScopeId: "XNAS"}},
+		{"unknown scope", &ordermonitor.HaltTradingParams{Scope: ordermonitor.HaltScope(99), ScopeId: "deskA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &testHaltsWriter{}
+			om := &orderMonitor{halts: newTestHaltStore(t, writer)}
+
+			_, err := om.HaltTrading(context.Background(), tt.params)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Empty(t, writer.written)
+		})
+	}
+}
+
+func TestLiftHaltNotFound(t *testing.T) {
+	writer := &testHaltsWriter{}
+	om := &orderMonitor{halts: newTestHaltStore(t, writer)}
+
+	_, err := om.LiftHalt(context.Background(), &ordermonitor.LiftHaltParams{Scope: ordermonitor.HaltScope_DESK,
+		ScopeId: "deskA"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Empty(t, writer.written)
+}
+
+type testRiskRejectionReader struct {
+	results chan func() (kafka.Message, error)
+}
+
+func (t *testRiskRejectionReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case result := <-t.results:
+		return result()
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func TestRiskRejectionsAreCountedAfterReadErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	counter := riskRejections.WithLabelValues("TEST_READ_RETRY", "desk")
+	initialCount := testutil.ToFloat64(counter)
+
+	reader := &testRiskRejectionReader{results: make(chan func() (kafka.Message, error))}
+	done := make(chan bool)
+	go func() {
+		countRiskRejections(ctx, reader, time.Millisecond)
+		close(done)
+	}()
+
+	readErr := func() (kafka.Message, error) { return kafka.Message{}, errors.New("broker unavailable") }
+	rejection := func() (kafka.Message, error) {
+		return kafka.Message{Value: []byte(`{"reason":"TEST_READ_RETRY","scope":"desk"}`)}, nil
+	}
+
+	reader.results <- rejection
+	reader.results <- readErr
+	reader.results <- readErr
+	reader.results <- rejection
+
+	cancel()
+	<-done
+
+	assert.Equal(t, initialCount+2, testutil.ToFloat64(counter))
+}
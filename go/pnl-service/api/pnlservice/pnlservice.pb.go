@@ -0,0 +1,482 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pnlservice.proto
+
+package pnlservice
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type PnlScope int32
+
+const (
+	PnlScope_ORDER      PnlScope = 0
+	PnlScope_ORIGINATOR PnlScope = 1
+	PnlScope_DESK       PnlScope = 2
+	PnlScope_LISTING    PnlScope = 3
+)
+
+var PnlScope_name = map[int32]string{
+	0: "ORDER",
+	1: "ORIGINATOR",
+	2: "DESK",
+	3: "LISTING",
+}
+
+var PnlScope_value = map[string]int32{
+	"ORDER":      0,
+	"ORIGINATOR": 1,
+	"DESK":       2,
+	"LISTING":    3,
+}
+
+func (x PnlScope) String() string {
+	return proto.EnumName(PnlScope_name, int32(x))
+}
+
+func (PnlScope) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_54d2a46ae083a4c1, []int{0}
+}
+
+// The scopeId is the order id, originator id, desk or listing id of the pnl.  The day pnl is the change in total pnl
+// since the last end of day snapshot.
+type Pnl struct {
+	Scope                PnlScope         `protobuf:"varint,1,opt,name=scope,proto3,enum=pnlservice.PnlScope" json:"scope,omitempty"`
+	ScopeId              string           `protobuf:"bytes,2,opt,name=scopeId,proto3" json:"scopeId,omitempty"`
+	RealisedPnl          float64          `protobuf:"fixed64,3,opt,name=realisedPnl,proto3" json:"realisedPnl,omitempty"`
+	UnrealisedPnl        float64          `protobuf:"fixed64,4,opt,name=unrealisedPnl,proto3" json:"unrealisedPnl,omitempty"`
+	TotalPnl             float64          `protobuf:"fixed64,5,opt,name=totalPnl,proto3" json:"totalPnl,omitempty"`
+	PreviousDayTotalPnl  float64          `protobuf:"fixed64,6,opt,name=previousDayTotalPnl,proto3" json:"previousDayTotalPnl,omitempty"`
+	DayPnl               float64          `protobuf:"fixed64,7,opt,name=dayPnl,proto3" json:"dayPnl,omitempty"`
+	LastUpdated          *model.Timestamp `protobuf:"bytes,8,opt,name=lastUpdated,proto3" json:"lastUpdated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Pnl) Reset()         { *m = Pnl{} }
+func (m *Pnl) String() string { return proto.CompactTextString(m) }
+func (*Pnl) ProtoMessage()    {}
+func (*Pnl) Descriptor() ([]byte, []int) {
+	return fileDescriptor_54d2a46ae083a4c1, []int{0}
+}
+
+func (m *Pnl) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Pnl.Unmarshal(m, b)
+}
+func (m *Pnl) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Pnl.Marshal(b, m, deterministic)
+}
+func (m *Pnl) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pnl.Merge(m, src)
+}
+func (m *Pnl) XXX_Size() int {
+	return xxx_messageInfo_Pnl.Size(m)
+}
+func (m *Pnl) XXX_DiscardUnknown() {
+	xxx_messageInfo_Pnl.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Pnl proto.InternalMessageInfo
+
+func (m *Pnl) GetScope() PnlScope {
+	if m != nil {
+		return m.Scope
+	}
+	return PnlScope_ORDER
+}
+
+func (m *Pnl) GetScopeId() string {
+	if m != nil {
+		return m.ScopeId
+	}
+	return ""
+}
+
+func (m *Pnl) GetRealisedPnl() float64 {
+	if m != nil {
+		return m.RealisedPnl
+	}
+	return 0
+}
+
+func (m *Pnl) GetUnrealisedPnl() float64 {
+	if m != nil {
+		return m.UnrealisedPnl
+	}
+	return 0
+}
+
+func (m *Pnl) GetTotalPnl() float64 {
+	if m != nil {
+		return m.TotalPnl
+	}
+	return 0
+}
+
+func (m *Pnl) GetPreviousDayTotalPnl() float64 {
+	if m != nil {
+		return m.PreviousDayTotalPnl
+	}
+	return 0
+}
+
+func (m *Pnl) GetDayPnl() float64 {
+	if m != nil {
+		return m.DayPnl
+	}
+	return 0
+}
+
+func (m *Pnl) GetLastUpdated() *model.Timestamp {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return nil
+}
+
+// An empty scopeId matches all the pnls of the scope
+type GetPnlParams struct {
+	Scope                PnlScope `protobuf:"varint,1,opt,name=scope,proto3,enum=pnlservice.PnlScope" json:"scope,omitempty"`
+	ScopeId              string   `protobuf:"bytes,2,opt,name=scopeId,proto3" json:"scopeId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPnlParams) Reset()         { *m = GetPnlParams{} }
+func (m *GetPnlParams) String() string { return proto.CompactTextString(m) }
+func (*GetPnlParams) ProtoMessage()    {}
+func (*GetPnlParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_54d2a46ae083a4c1, []int{1}
+}
+
+func (m *GetPnlParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPnlParams.Unmarshal(m, b)
+}
+func (m *GetPnlParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPnlParams.Marshal(b, m, deterministic)
+}
+func (m *GetPnlParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPnlParams.Merge(m, src)
+}
+func (m *GetPnlParams) XXX_Size() int {
+	return xxx_messageInfo_GetPnlParams.Size(m)
+}
+func (m *GetPnlParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPnlParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPnlParams proto.InternalMessageInfo
+
+func (m *GetPnlParams) GetScope() PnlScope {
+	if m != nil {
+		return m.Scope
+	}
+	return PnlScope_ORDER
+}
+
+func (m *GetPnlParams) GetScopeId() string {
+	if m != nil {
+		return m.ScopeId
+	}
+	return ""
+}
+
+type Pnls struct {
+	Pnls                 []*Pnl   `protobuf:"bytes,1,rep,name=pnls,proto3" json:"pnls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Pnls) Reset()         { *m = Pnls{} }
+func (m *Pnls) String() string { return proto.CompactTextString(m) }
+func (*Pnls) ProtoMessage()    {}
+func (*Pnls) Descriptor() ([]byte, []int) {
+	return fileDescriptor_54d2a46ae083a4c1, []int{2}
+}
+
+func (m *Pnls) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Pnls.Unmarshal(m, b)
+}
+func (m *Pnls) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Pnls.Marshal(b, m, deterministic)
+}
+func (m *Pnls) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pnls.Merge(m, src)
+}
+func (m *Pnls) XXX_Size() int {
+	return xxx_messageInfo_Pnls.Size(m)
+}
+func (m *Pnls) XXX_DiscardUnknown() {
+	xxx_messageInfo_Pnls.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Pnls proto.InternalMessageInfo
+
+func (m *Pnls) GetPnls() []*Pnl {
+	if m != nil {
+		return m.Pnls
+	}
+	return nil
+}
+
+// A conflation interval of zero uses the service's default conflation interval
+type SubscribeToPnlParams struct {
+	Scope                    PnlScope `protobuf:"varint,1,opt,name=scope,proto3,enum=pnlservice.PnlScope" json:"scope,omitempty"`
+	ScopeId                  string   `protobuf:"bytes,2,opt,name=scopeId,proto3" json:"scopeId,omitempty"`
+	ConflationIntervalMillis int32    `protobuf:"varint,3,opt,name=conflationIntervalMillis,proto3" json:"conflationIntervalMillis,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{} `json:"-"`
+	XXX_unrecognized         []byte   `json:"-"`
+	XXX_sizecache            int32    `json:"-"`
+}
+
+func (m *SubscribeToPnlParams) Reset()         { *m = SubscribeToPnlParams{} }
+func (m *SubscribeToPnlParams) String() string { return proto.CompactTextString(m) }
+func (*SubscribeToPnlParams) ProtoMessage()    {}
+func (*SubscribeToPnlParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_54d2a46ae083a4c1, []int{3}
+}
+
+func (m *SubscribeToPnlParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeToPnlParams.Unmarshal(m, b)
+}
+func (m *SubscribeToPnlParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeToPnlParams.Marshal(b, m, deterministic)
+}
+func (m *SubscribeToPnlParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeToPnlParams.Merge(m, src)
+}
+func (m *SubscribeToPnlParams) XXX_Size() int {
+	return xxx_messageInfo_SubscribeToPnlParams.Size(m)
+}
+func (m *SubscribeToPnlParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeToPnlParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeToPnlParams proto.InternalMessageInfo
+
+func (m *SubscribeToPnlParams) GetScope() PnlScope {
+	if m != nil {
+		return m.Scope
+	}
+	return PnlScope_ORDER
+}
+
+func (m *SubscribeToPnlParams) GetScopeId() string {
+	if m != nil {
+		return m.ScopeId
+	}
+	return ""
+}
+
+func (m *SubscribeToPnlParams) GetConflationIntervalMillis() int32 {
+	if m != nil {
+		return m.ConflationIntervalMillis
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("pnlservice.PnlScope", PnlScope_name, PnlScope_value)
+	proto.RegisterType((*Pnl)(nil), "pnlservice.Pnl")
+	proto.RegisterType((*GetPnlParams)(nil), "pnlservice.GetPnlParams")
+	proto.RegisterType((*Pnls)(nil), "pnlservice.Pnls")
+	proto.RegisterType((*SubscribeToPnlParams)(nil), "pnlservice.SubscribeToPnlParams")
+}
+
+func init() { proto.RegisterFile("pnlservice.proto", fileDescriptor_54d2a46ae083a4c1) }
+
+var fileDescriptor_54d2a46ae083a4c1 = []byte{
+	// 417 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x93, 0xcf, 0x8a, 0xdb, 0x30,
+	0x10, 0xc6, 0xa3, 0xfc, 0xdf, 0x71, 0x9b, 0xba, 0xd3, 0xa5, 0x98, 0x9c, 0x8c, 0xdb, 0x83, 0xd9,
+	0x42, 0x58, 0x5c, 0xe8, 0xa1, 0xf4, 0x52, 0xc8, 0x12, 0x4c, 0xdb, 0x8d, 0x51, 0xdc, 0x07, 0x50,
+	0x6c, 0x15, 0x04, 0xb2, 0x64, 0x2c, 0x25, 0xb0, 0x0f, 0xd1, 0x7b, 0x5f, 0xa2, 0xef, 0x58, 0x2c,
+	0x37, 0xad, 0x37, 0xec, 0xde, 0xf6, 0xa6, 0x99, 0xef, 0x37, 0xa3, 0x99, 0x4f, 0x08, 0xfc, 0x5a,
+	0x49, 0xc3, 0x9b, 0xa3, 0x28, 0xf8, 0xaa, 0x6e, 0xb4, 0xd5, 0x08, 0xff, 0x33, 0xcb, 0x97, 0x95,
+	0x2e, 0xb9, 0x2c, 0x74, 0x55, 0x69, 0xd5, 0xc9, 0xd1, 0xef, 0x21, 0x8c, 0x32, 0x25, 0xf1, 0x0a,
+	0x26, 0xa6, 0xd0, 0x35, 0x0f, 0x48, 0x48, 0xe2, 0x45, 0x72, 0xb9, 0xea, 0x35, 0xca, 0x94, 0xdc,
+	0xb5, 0x1a, 0xed, 0x10, 0x0c, 0x60, 0xe6, 0x0e, 0x69, 0x19, 0x0c, 0x43, 0x12, 0x5f, 0xd0, 0x53,
+	0x88, 0x21, 0x78, 0x0d, 0x67, 0x52, 0x18, 0x5e, 0x66, 0x4a, 0x06, 0xa3, 0x90, 0xc4, 0x84, 0xf6,
+	0x53, 0xf8, 0x16, 0x9e, 0x1f, 0x54, 0x9f, 0x19, 0x3b, 0xe6, 0x7e, 0x12, 0x97, 0x30, 0xb7, 0xda,
+	0x32, 0xd9, 0x02, 0x13, 0x07, 0xfc, 0x8b, 0xf1, 0x1a, 0x5e, 0xd5, 0x0d, 0x3f, 0x0a, 0x7d, 0x30,
+	0x6b, 0x76, 0x97, 0x9f, 0xb0, 0xa9, 0xc3, 0x1e, 0x92, 0xf0, 0x35, 0x4c, 0x4b, 0x76, 0xd7, 0x42,
+	0x33, 0x07, 0xfd, 0x8d, 0x30, 0x01, 0x4f, 0x32, 0x63, 0xbf, 0xd7, 0x25, 0xb3, 0xbc, 0x0c, 0xe6,
+	0x21, 0x89, 0xbd, 0xc4, 0x5f, 0x39, 0x93, 0x56, 0xb9, 0xa8, 0xb8, 0xb1, 0xac, 0xaa, 0x69, 0x1f,
+	0x8a, 0x72, 0x78, 0xb6, 0xe1, 0x36, 0x53, 0x32, 0x63, 0x0d, 0xab, 0xcc, 0xd3, 0xf8, 0x16, 0xbd,
+	0x83, 0x71, 0xa6, 0xa4, 0xc1, 0x37, 0x30, 0x6e, 0xeb, 0x03, 0x12, 0x8e, 0x62, 0x2f, 0x79, 0x71,
+	0xd6, 0x8c, 0x3a, 0x31, 0xfa, 0x45, 0xe0, 0x72, 0x77, 0xd8, 0x9b, 0xa2, 0x11, 0x7b, 0x9e, 0xeb,
+	0x27, 0x9e, 0x05, 0x3f, 0x42, 0x50, 0x68, 0xf5, 0x43, 0x32, 0x2b, 0xb4, 0x4a, 0x95, 0xe5, 0xcd,
+	0x91, 0xc9, 0x6f, 0x42, 0x4a, 0x61, 0xdc, 0x83, 0x4e, 0xe8, 0xa3, 0xfa, 0xd5, 0x27, 0x98, 0x9f,
+	0x2e, 0xc2, 0x0b, 0x98, 0x6c, 0xe9, 0xfa, 0x86, 0xfa, 0x03, 0x5c, 0x00, 0x6c, 0x69, 0xba, 0x49,
+	0x6f, 0x3f, 0xe7, 0x5b, 0xea, 0x13, 0x9c, 0xc3, 0x78, 0x7d, 0xb3, 0xfb, 0xe2, 0x0f, 0xd1, 0x83,
+	0xd9, 0xd7, 0x74, 0x97, 0xa7, 0xb7, 0x1b, 0x7f, 0x94, 0xfc, 0x24, 0x00, 0x6d, 0x79, 0x37, 0x32,
+	0x7e, 0x80, 0x69, 0x67, 0x35, 0x06, 0xfd, 0x4d, 0xfa, 0xf6, 0x2f, 0xfd, 0xb3, 0x1d, 0x4d, 0x34,
+	0xc0, 0x0d, 0x2c, 0xee, 0xdb, 0x83, 0x61, 0x9f, 0x7a, 0xc8, 0xba, 0xe5, 0xb9, 0xd5, 0xd1, 0xe0,
+	0x9a, 0xec, 0xa7, 0xee, 0x8b, 0xbc, 0xff, 0x33, 0x00, 0xa2, 0xc1, 0x3d, 0x58, 0x55, 0x03, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PnlServiceClient is the client API for PnlService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PnlServiceClient interface {
+	GetPnl(ctx context.Context, in *GetPnlParams, opts ...grpc.CallOption) (*Pnls, error)
+	SubscribeToPnl(ctx context.Context, in *SubscribeToPnlParams, opts ...grpc.CallOption) (PnlService_SubscribeToPnlClient, error)
+}
+
+type pnlServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPnlServiceClient(cc *grpc.ClientConn) PnlServiceClient {
+	return &pnlServiceClient{cc}
+}
+
+func (c *pnlServiceClient) GetPnl(ctx context.Context, in *GetPnlParams, opts ...grpc.CallOption) (*Pnls, error) {
+	out := new(Pnls)
+	err := c.cc.Invoke(ctx, "/pnlservice.PnlService/GetPnl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pnlServiceClient) SubscribeToPnl(ctx context.Context, in *SubscribeToPnlParams, opts ...grpc.CallOption) (PnlService_SubscribeToPnlClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PnlService_serviceDesc.Streams[0], "/pnlservice.PnlService/SubscribeToPnl", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pnlServiceSubscribeToPnlClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PnlService_SubscribeToPnlClient interface {
+	Recv() (*Pnl, error)
+	grpc.ClientStream
+}
+
+type pnlServiceSubscribeToPnlClient struct {
+	grpc.ClientStream
+}
+
+func (x *pnlServiceSubscribeToPnlClient) Recv() (*Pnl, error) {
+	m := new(Pnl)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PnlServiceServer is the server API for PnlService service.
+type PnlServiceServer interface {
+	GetPnl(context.Context, *GetPnlParams) (*Pnls, error)
+	SubscribeToPnl(*SubscribeToPnlParams, PnlService_SubscribeToPnlServer) error
+}
+
+// UnimplementedPnlServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPnlServiceServer struct {
+}
+
+func (*UnimplementedPnlServiceServer) GetPnl(ctx context.Context, req *GetPnlParams) (*Pnls, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPnl not implemented")
+}
+func (*UnimplementedPnlServiceServer) SubscribeToPnl(req *SubscribeToPnlParams, srv PnlService_SubscribeToPnlServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToPnl not implemented")
+}
+
+func RegisterPnlServiceServer(s *grpc.Server, srv PnlServiceServer) {
+	s.RegisterService(&_PnlService_serviceDesc, srv)
+}
+
+func _PnlService_GetPnl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPnlParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PnlServiceServer).GetPnl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pnlservice.PnlService/GetPnl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PnlServiceServer).GetPnl(ctx, req.(*GetPnlParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PnlService_SubscribeToPnl_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToPnlParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PnlServiceServer).SubscribeToPnl(m, &pnlServiceSubscribeToPnlServer{stream})
+}
+
+type PnlService_SubscribeToPnlServer interface {
+	Send(*Pnl) error
+	grpc.ServerStream
+}
+
+type pnlServiceSubscribeToPnlServer struct {
+	grpc.ServerStream
+}
+
+func (x *pnlServiceSubscribeToPnlServer) Send(m *Pnl) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _PnlService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pnlservice.PnlService",
+	HandlerType: (*PnlServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPnl",
+			Handler:    _PnlService_GetPnl_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeToPnl",
+			Handler:       _PnlService_SubscribeToPnl_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pnlservice.proto",
+}
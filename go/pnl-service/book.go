@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/ettec/open-trading-platform/go/shared/averagecost"
+	"github.com/shopspring/decimal"
+)
+
+var zero = decimal.New(0, 0)
+
+// book is the net position at its average cost in a listing of an order, originator, desk or of all orders in the
+// listing.
+type book struct {
+	averagecost.Position
+	markPrice decimal.Decimal
+	hasMark   bool
+}
+
+func newBook() *book {
+	return &book{Position: averagecost.NewPosition(), markPrice: zero}
+}
+
+// setMarkPrice sets the price the book is marked to market at, it returns true if the price has changed.
+func (b *book) setMarkPrice(price decimal.Decimal) bool {
+	if b.hasMark && b.markPrice.Equal(price) {
+		return false
+	}
+
+	b.markPrice = price
+	b.hasMark = true
+	return true
+}
+
+// unrealisedPnl is the pnl of the open quantity at the mark price, it is zero until the book has a mark price.
+func (b *book) unrealisedPnl() decimal.Decimal {
+	if !b.hasMark {
+		return zero
+	}
+
+	return b.UnrealisedPnl(b.markPrice)
+}
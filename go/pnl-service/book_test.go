@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBookApplyTrade(t *testing.T) {
+	tests := []struct {
+		name            string
+		trades          [][2]int64
+		wantQuantity    int64
+		wantAvgPrice    string
+		wantRealisedPnl int64
+	}{
+		{"open long", [][2]int64{{10, 100}}, 10, "100", 0},
+		{"increase short", [][2]int64{{-10, 100}, {-10, 110}}, -20, "105", 0},
+		{"reduce long", [][2]int64{{10, 100}, {-4, 110}}, 6, "100", 40},
+		{"close short", [][2]int64{{-10, 100}, {10, 90}}, 0, "0", 100},
+		{"reverse long to short", [][2]int64{{10, 100}, {-15, 105}}, -5, "105", 50},
+		{"cancelled trade", [][2]int64{{10, 100}, {-10, 100}}, 0, "0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBook()
+			for _, trade := range tt.trades {
+				b.ApplyTrade(decimal.New(trade[0], 0), decimal.New(trade[1], 0))
+			}
+
+			assert.True(t, b.Quantity.Equal(decimal.New(tt.wantQuantity, 0)), "quantity %v", b.Quantity)
+			assert.Equal(t, tt.wantAvgPrice, b.AvgPrice.String())
+			assert.True(t, b.RealisedPnl.Equal(decimal.New(tt.wantRealisedPnl, 0)), "realised pnl %v", b.RealisedPnl)
+		})
+	}
+}
+
+func TestBookUnrealisedPnl(t *testing.T) {
+	b := newBook()
+	b.ApplyTrade(decimal.New(10, 0), decimal.New(100, 0))
+
+	assert.True(t, b.unrealisedPnl().Equal(zero), "no mark price")
+
+	assert.True(t, b.setMarkPrice(decimal.New(103, 0)))
+	assert.False(t, b.setMarkPrice(decimal.New(103, 0)))
+	assert.True(t, b.unrealisedPnl().Equal(decimal.New(30, 0)))
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/pnl-service/api/pnlservice"
+	"github.com/ettec/open-trading-platform/go/pnl-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type scopeKey struct {
+	scope   api.PnlScope
+	scopeId string
+}
+
+type bookKey struct {
+	scopeKey
+	listingId int32
+}
+
+// tradeUpdate is a trade read from the trades topic.
+type tradeUpdate struct {
+	offset int64
+	trade  *tradeservice.Trade
+}
+
+// pnlEngine maintains the pnl of each order, originator, desk and listing from the trades in the trades topic and the
+// quotes of the traded listings.  The pnl of a scope is the sum of the pnl of its books, one per listing.  Every trade
+// is applied to the order and originator of the trade, only the trades of root orders are applied to the desk and
+// listing as the trades of a strategy's child orders are also trades of the strategy's parent order.
+type pnlEngine struct {
+	mutex              sync.Mutex
+	books              map[bookKey]*book
+	scopeBooks         map[scopeKey]map[int32]*book
+	listingBooks       map[int32]map[bookKey]*book
+	lastUpdated        map[scopeKey]time.Time
+	previousDayTotals  map[scopeKey]decimal.Decimal
+	marks              map[int32]decimal.Decimal
+	lastTradeOffset    int64
+	subscriptions      map[*pnlSubscription]bool
+	subscribeToQuotes  func(listingId int32) error
+	subscribedListings map[int32]bool
+}
+
+// pnlSubscription records the scopes whose pnl has changed since the subscriber last took the changes.
+type pnlSubscription struct {
+	scope   api.PnlScope
+	scopeId string
+	pending map[scopeKey]bool
+	changed chan struct{}
+}
+
+func newPnlEngine(subscribeToQuotes func(listingId int32) error) *pnlEngine {
+	return &pnlEngine{
+		books:              map[bookKey]*book{},
+		scopeBooks:         map[scopeKey]map[int32]*book{},
+		listingBooks:       map[int32]map[bookKey]*book{},
+		lastUpdated:        map[scopeKey]time.Time{},
+		previousDayTotals:  map[scopeKey]decimal.Decimal{},
+		marks:              map[int32]decimal.Decimal{},
+		lastTradeOffset:    -1,
+		subscriptions:      map[*pnlSubscription]bool{},
+		subscribeToQuotes:  subscribeToQuotes,
+		subscribedListings: map[int32]bool{},
+	}
+}
+
+// onTrade applies the trade to the books of its order, originator and, for the trade of a root order, its desk and
+// listing.
+func (e *pnlEngine) onTrade(update tradeUpdate) {
+	trade := update.trade
+
+	// A trade of negative quantity reverses a cancelled or corrected trade of the order's side
+	quantity := trade.Quantity.AsDecimal().Abs()
+	if trade.Side == model.Side_SELL {
+		quantity = quantity.Neg()
+	}
+	reversal := trade.Quantity.AsDecimal().IsNegative()
+	price := trade.Price.AsDecimal()
+
+	scopes := []scopeKey{
+		{scope: api.PnlScope_ORDER, scopeId: trade.OrderId},
+		{scope: api.PnlScope_ORIGINATOR, scopeId: trade.OriginatorId},
+	}
+
+	if trade.OriginatorId == trade.RootOriginatorId && trade.OriginatorRef == trade.RootOriginatorRef {
+		scopes = append(scopes,
+			scopeKey{scope: api.PnlScope_DESK, scopeId: trade.RootOriginatorId},
+			scopeKey{scope: api.PnlScope_LISTING, scopeId: strconv.Itoa(int(trade.ListingId))})
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.lastTradeOffset = update.offset
+
+	now := time.Now()
+	for _, scope := range scopes {
+		b := e.getOrCreateBook(bookKey{scopeKey: scope, listingId: trade.ListingId})
+		if reversal {
+			b.ReverseTrade(quantity, price)
+		} else {
+			b.ApplyTrade(quantity, price)
+		}
+		e.onScopeChanged(scope, now)
+	}
+}
+
+func (e *pnlEngine) getOrCreateBook(key bookKey) *book {
+	if b, exists := e.books[key]; exists {
+		return b
+	}
+
+	b := newBook()
+	if mark, ok := e.marks[key.listingId]; ok {
+		b.setMarkPrice(mark)
+	}
+
+	e.books[key] = b
+
+	if e.scopeBooks[key.scopeKey] == nil {
+		e.scopeBooks[key.scopeKey] = map[int32]*book{}
+	}
+	e.scopeBooks[key.scopeKey][key.listingId] = b
+
+	if e.listingBooks[key.listingId] == nil {
+		e.listingBooks[key.listingId] = map[bookKey]*book{}
+	}
+	e.listingBooks[key.listingId][key] = b
+
+	e.subscribeToListing(key.listingId)
+
+	return b
+}
+
+func (e *pnlEngine) removeBook(key bookKey) {
+	delete(e.books, key)
+	delete(e.listingBooks[key.listingId], key)
+	delete(e.scopeBooks[key.scopeKey], key.listingId)
+	if len(e.scopeBooks[key.scopeKey]) == 0 {
+		delete(e.scopeBooks, key.scopeKey)
+		delete(e.lastUpdated, key.scopeKey)
+	}
+}
+
+func (e *pnlEngine) subscribeToListing(listingId int32) {
+	if e.subscribedListings[listingId] {
+		return
+	}
+
+	if err := e.subscribeToQuotes(listingId); err != nil {
+		slog.Error("failed to subscribe to quotes, unrealised pnl of the listing will not be updated",
+			"listingId", listingId, "error", err)
+		return
+	}
+
+	e.subscribedListings[listingId] = true
+}
+
+// onQuote marks the books of the quote's listing at the quote's last price, or if the listing has not traded, at the
+// mid-price of the best bid and offer.
+func (e *pnlEngine) onQuote(quote *model.ClobQuote) {
+	markPrice, ok := getMarkPrice(quote)
+	if !ok {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.marks[quote.ListingId] = markPrice
+
+	now := time.Now()
+	for key, b := range e.listingBooks[quote.ListingId] {
+		if b.setMarkPrice(markPrice) {
+			e.onScopeChanged(key.scopeKey, now)
+		}
+	}
+}
+
+func getMarkPrice(quote *model.ClobQuote) (decimal.Decimal, bool) {
+	if quote.StreamInterrupted {
+		return zero, false
+	}
+
+	if quote.LastPrice != nil {
+		return quote.LastPrice.AsDecimal(), true
+	}
+
+	if len(quote.Bids) > 0 && len(quote.Offers) > 0 {
+		return quote.Bids[0].Price.AsDecimal().Add(quote.Offers[0].Price.AsDecimal()).Div(decimal.New(2, 0)), true
+	}
+
+	return zero, false
+}
+
+func (e *pnlEngine) onScopeChanged(key scopeKey, now time.Time) {
+	e.lastUpdated[key] = now
+
+	for subscription := range e.subscriptions {
+		if subscription.matches(key) {
+			subscription.pending[key] = true
+			select {
+			case subscription.changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (s *pnlSubscription) matches(key scopeKey) bool {
+	return key.scope == s.scope && (s.scopeId == "" || key.scopeId == s.scopeId)
+}
+
+func (e *pnlEngine) getPnl(key scopeKey) *api.Pnl {
+	realisedPnl, unrealisedPnl := zero, zero
+	for _, b := range e.scopeBooks[key] {
+		realisedPnl = realisedPnl.Add(b.RealisedPnl)
+		unrealisedPnl = unrealisedPnl.Add(b.unrealisedPnl())
+	}
+
+	totalPnl := realisedPnl.Add(unrealisedPnl)
+	previousDayTotalPnl, ok := e.previousDayTotals[key]
+	if !ok {
+		previousDayTotalPnl = zero
+	}
+
+	return &api.Pnl{
+		Scope:               key.scope,
+		ScopeId:             key.scopeId,
+		RealisedPnl:         toFloat(realisedPnl),
+		UnrealisedPnl:       toFloat(unrealisedPnl),
+		TotalPnl:            toFloat(totalPnl),
+		PreviousDayTotalPnl: toFloat(previousDayTotalPnl),
+		DayPnl:              toFloat(totalPnl.Sub(previousDayTotalPnl)),
+		LastUpdated:         model.NewTimeStamp(e.lastUpdated[key]),
+	}
+}
+
+// getPnls returns the pnls of the scope ordered by scope id, or only the pnl of the given scope id if it is not empty.
+func (e *pnlEngine) getPnls(scope api.PnlScope, scopeId string) []*api.Pnl {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	subscription := &pnlSubscription{scope: scope, scopeId: scopeId}
+	return e.getMatchingPnls(subscription.matches)
+}
+
+func (e *pnlEngine) getMatchingPnls(matches func(key scopeKey) bool) []*api.Pnl {
+	var keys []scopeKey
+	for key := range e.scopeBooks {
+		if matches(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].scopeId < keys[j].scopeId })
+
+	result := make([]*api.Pnl, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, e.getPnl(key))
+	}
+
+	return result
+}
+
+// subscribe returns the current pnls of the scope, or of only the given scope id if it is not empty, and a subscription
+// to the subsequent changes to them.
+func (e *pnlEngine) subscribe(scope api.PnlScope, scopeId string) ([]*api.Pnl, *pnlSubscription) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	subscription := &pnlSubscription{
+		scope:   scope,
+		scopeId: scopeId,
+		pending: map[scopeKey]bool{},
+		changed: make(chan struct{}, 1),
+	}
+	e.subscriptions[subscription] = true
+
+	return e.getMatchingPnls(subscription.matches), subscription
+}
+
+// takeChanges returns the current pnl of each scope that has changed since the subscription's changes were last taken.
+func (e *pnlEngine) takeChanges(subscription *pnlSubscription) []*api.Pnl {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	result := make([]*api.Pnl, 0, len(subscription.pending))
+	for key := range subscription.pending {
+		if _, exists := e.scopeBooks[key]; exists {
+			result = append(result, e.getPnl(key))
+		}
+		delete(subscription.pending, key)
+	}
+
+	return result
+}
+
+func (e *pnlEngine) unsubscribe(subscription *pnlSubscription) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	delete(e.subscriptions, subscription)
+}
+
+// endOfDay returns a snapshot of the books of every originator, desk and listing and starts a new day, the total pnl
+// of each scope becomes its previous day total.  The pnl of orders is intraday and the order books are discarded.
+func (e *pnlEngine) endOfDay(snapshotTime time.Time) []snapshotRecord {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var records []snapshotRecord
+	for key, b := range e.books {
+		if key.scope == api.PnlScope_ORDER {
+			e.removeBook(key)
+			continue
+		}
+
+		records = append(records, snapshotRecord{
+			Scope:           key.scope.String(),
+			ScopeId:         key.scopeId,
+			ListingId:       key.listingId,
+			Quantity:        b.Quantity,
+			AvgPrice:        b.AvgPrice,
+			RealisedPnl:     b.RealisedPnl,
+			MarkPrice:       b.markPrice,
+			HasMark:         b.hasMark,
+			LastTradeOffset: e.lastTradeOffset,
+			SnapshotTime:    snapshotTime,
+		})
+	}
+
+	previousDayTotals := map[scopeKey]decimal.Decimal{}
+	for key, books := range e.scopeBooks {
+		total := zero
+		for _, b := range books {
+			total = total.Add(b.RealisedPnl).Add(b.unrealisedPnl())
+		}
+		previousDayTotals[key] = total
+	}
+	e.previousDayTotals = previousDayTotals
+
+	for key := range e.scopeBooks {
+		e.onScopeChanged(key, snapshotTime)
+	}
+
+	return records
+}
+
+// restore restores the books from the last end of day snapshot, it returns the offset in the trades topic of the last
+// trade applied to the snapshot or -1 if there are no snapshot records.
+func (e *pnlEngine) restore(records []snapshotRecord) (int64, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, record := range records {
+		scope, ok := api.PnlScope_value[record.Scope]
+		if !ok {
+			return 0, fmt.Errorf("unknown pnl scope: %v", record.Scope)
+		}
+
+		key := bookKey{scopeKey: scopeKey{scope: api.PnlScope(scope), scopeId: record.ScopeId}, listingId: record.ListingId}
+		b := e.getOrCreateBook(key)
+		b.Quantity = record.Quantity
+		b.AvgPrice = record.AvgPrice
+		b.RealisedPnl = record.RealisedPnl
+		b.markPrice = record.MarkPrice
+		b.hasMark = record.HasMark
+
+		previousDayTotal, ok := e.previousDayTotals[key.scopeKey]
+		if !ok {
+			previousDayTotal = zero
+		}
+		e.previousDayTotals[key.scopeKey] = previousDayTotal.Add(b.RealisedPnl).Add(b.unrealisedPnl())
+		e.lastUpdated[key.scopeKey] = record.SnapshotTime
+
+		if record.LastTradeOffset > e.lastTradeOffset {
+			e.lastTradeOffset = record.LastTradeOffset
+		}
+	}
+
+	return e.lastTradeOffset, nil
+}
+
+func toFloat(d decimal.Decimal) float64 {
+	f, _ := d.Float64()
+	return f
+}
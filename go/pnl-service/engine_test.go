@@ -0,0 +1,210 @@
+package main
+
+import (
+	api "github.com/ettec/open-trading-platform/go/pnl-service/api/pnlservice"
+	"github.com/ettec/open-trading-platform/go/pnl-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestEngine() (*pnlEngine, *[]int32) {
+	var subscribed []int32
+	return newPnlEngine(func(listingId int32) error {
+		subscribed = append(subscribed, listingId)
+		return nil
+	}), &subscribed
+}
+
+func newTestTrade(offset int64, orderId string, desk string, trader string, listingId int32, side model.Side,
+	quantity int, price int) tradeUpdate {
+	return tradeUpdate{
+		offset: offset,
+		trade: &tradeservice.Trade{
+			OrderId:           orderId,
+			Side:              side,
+			ListingId:         listingId,
+			Price:             model.IasD(price),
+			Quantity:          model.IasD(quantity),
+			OriginatorId:      desk,
+			OriginatorRef:     trader,
+			RootOriginatorId:  desk,
+			RootOriginatorRef: trader,
+		},
+	}
+}
+
+func newTestChildTrade(offset int64, orderId string, parentOrderId string, strategy string, desk string,
+	listingId int32, side model.Side, quantity int, price int) tradeUpdate {
+	update := newTestTrade(offset, orderId, desk, "trader1", listingId, side, quantity, price)
+	update.trade.OriginatorId = strategy
+	update.trade.OriginatorRef = parentOrderId
+	return update
+}
+
+func getTestPnl(t *testing.T, engine *pnlEngine, scope api.PnlScope, scopeId string) *api.Pnl {
+	pnls := engine.getPnls(scope, scopeId)
+	if !assert.Len(t, pnls, 1) {
+		t.FailNow()
+	}
+	return pnls[0]
+}
+
+func TestTradesAreAppliedToOrderOriginatorDeskAndListing(t *testing.T) {
+	engine, subscribed := newTestEngine()
+
+	engine.onTrade(newTestTrade(0, "order1", "desk1", "trader1", 1, model.Side_BUY, 10, 100))
+	engine.onTrade(newTestTrade(1, "order2", "desk1", "trader1", 1, model.Side_SELL, 4, 110))
+	engine.onTrade(newTestTrade(2, "order3", "desk2", "trader2", 1, model.Side_SELL, 5, 120))
+	engine.onTrade(newTestTrade(3, "order4", "desk1", "trader1", 2, model.Side_BUY, 1, 50))
+
+	assert.Equal(t, []int32{1, 2}, *subscribed)
+
+	assert.Equal(t, 0.0, getTestPnl(t, engine, api.PnlScope_ORDER, "order1").RealisedPnl)
+	assert.Equal(t, 40.0, getTestPnl(t, engine, api.PnlScope_DESK, "desk1").RealisedPnl)
+	assert.Equal(t, 40.0, getTestPnl(t, engine, api.PnlScope_ORIGINATOR, "desk1").RealisedPnl)
+	assert.Equal(t, 0.0, getTestPnl(t, engine, api.PnlScope_DESK, "desk2").RealisedPnl)
+
+	// The listing book nets the trades of both desks, the sale of desk2 closes the remaining long at a profit of 20
+	assert.Equal(t, 140.0, getTestPnl(t, engine, api.PnlScope_LISTING, "1").RealisedPnl)
+
+	orders := engine.getPnls(api.PnlScope_ORDER, "")
+	assert.Len(t, orders, 4)
+	assert.Equal(t, "order1", orders[0].ScopeId)
+	assert.Equal(t, "order4", orders[3].ScopeId)
+}
+
+func TestTradesOfChildOrdersAreNotAppliedToDeskAndListing(t *testing.T) {
+	engine, _ := newTestEngine()
+
+	engine.onTrade(newTestChildTrade(0, "child1", "parent1", "vwap-strategy", "desk1", 1, model.Side_BUY, 10, 100))
+	engine.onTrade(newTestTrade(1, "parent1", "desk1", "trader1", 1, model.Side_BUY, 10, 100))
+	engine.onTrade(newTestChildTrade(2, "child2", "parent1", "vwap-strategy", "desk1", 1, model.Side_SELL, 10, 105))
+	engine.onTrade(newTestTrade(3, "parent1", "desk1", "trader1", 1, model.Side_SELL, 10, 105))
+
+	assert.Equal(t, 50.0, getTestPnl(t, engine, api.PnlScope_ORIGINATOR, "vwap-strategy").RealisedPnl)
+	assert.Equal(t, 50.0, getTestPnl(t, engine, api.PnlScope_ORDER, "parent1").RealisedPnl)
+	assert.Equal(t, 50.0, getTestPnl(t, engine, api.PnlScope_DESK, "desk1").RealisedPnl)
+	assert.Equal(t, 50.0, getTestPnl(t, engine, api.PnlScope_LISTING, "1").RealisedPnl)
+}
+
+func TestCancelledTradeIsTakenOutOfBooksWithOtherTrades(t *testing.T) {
+	engine, _ := newTestEngine()
+
+	engine.onTrade(newTestTrade(0, "order1", "desk1", "trader1", 1, model.Side_BUY, 10, 100))
+	engine.onTrade(newTestTrade(1, "order2", "desk1", "trader1", 1, model.Side_BUY, 10, 110))
+	engine.onTrade(newTestTrade(2, "order3", "desk1", "trader1", 1, model.Side_SELL, 5, 120))
+	engine.onTrade(newTestTrade(3, "order1", "desk1", "trader1", 1, model.Side_BUY, -10, 100))
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(125)})
+
+	// The desk is left long 5 at 115, the cost of the 110 fill less the 5 sold against the average cost of 105
+	desk := getTestPnl(t, engine, api.PnlScope_DESK, "desk1")
+	assert.Equal(t, 75.0, desk.RealisedPnl)
+	assert.Equal(t, 50.0, desk.UnrealisedPnl)
+
+	order := getTestPnl(t, engine, api.PnlScope_ORDER, "order1")
+	assert.Equal(t, 0.0, order.RealisedPnl)
+	assert.Equal(t, 0.0, order.UnrealisedPnl)
+}
+
+func TestUnrealisedPnlIsMarkedToLastOrMidPrice(t *testing.T) {
+	engine, _ := newTestEngine()
+	engine.onTrade(newTestTrade(0, "order1", "desk1", "trader1", 1, model.Side_BUY, 10, 100))
+
+	assert.Equal(t, 0.0, getTestPnl(t, engine, api.PnlScope_DESK, "desk1").UnrealisedPnl)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1,
+		Bids:   []*model.ClobLine{{Price: model.IasD(101), Size: model.IasD(1)}},
+		Offers: []*model.ClobLine{{Price: model.IasD(103), Size: model.IasD(1)}}})
+	assert.Equal(t, 20.0, getTestPnl(t, engine, api.PnlScope_DESK, "desk1").UnrealisedPnl)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(99)})
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(200), StreamInterrupted: true})
+
+	pnl := getTestPnl(t, engine, api.PnlScope_DESK, "desk1")
+	assert.Equal(t, -10.0, pnl.UnrealisedPnl)
+	assert.Equal(t, -10.0, pnl.TotalPnl)
+	assert.Equal(t, -10.0, pnl.DayPnl)
+
+	// A book opened after the listing has been quoted is marked at the last known price
+	engine.onTrade(newTestTrade(1, "order2", "desk2", "trader2", 1, model.Side_SELL, 5, 100))
+	assert.Equal(t, 5.0, getTestPnl(t, engine, api.PnlScope_DESK, "desk2").UnrealisedPnl)
+}
+
+func TestEndOfDaySnapshotIsRestored(t *testing.T) {
+	engine, _ := newTestEngine()
+	engine.onTrade(newTestTrade(0, "order1", "desk1", "trader1", 1, model.Side_BUY, 10, 100))
+	engine.onTrade(newTestTrade(1, "order2", "desk1", "trader1", 1, model.Side_SELL, 4, 110))
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(105)})
+
+	records := engine.endOfDay(time.Now())
+
+	assert.Empty(t, engine.getPnls(api.PnlScope_ORDER, ""))
+
+	pnl := getTestPnl(t, engine, api.PnlScope_DESK, "desk1")
+	assert.Equal(t, 70.0, pnl.TotalPnl)
+	assert.Equal(t, 70.0, pnl.PreviousDayTotalPnl)
+	assert.Equal(t, 0.0, pnl.DayPnl)
+
+	restored, subscribed := newTestEngine()
+	lastTradeOffset, err := restored.restore(records)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), lastTradeOffset)
+	assert.Equal(t, []int32{1}, *subscribed)
+
+	for _, scope := range []api.PnlScope{api.PnlScope_ORIGINATOR, api.PnlScope_DESK, api.PnlScope_LISTING} {
+		before := engine.getPnls(scope, "")
+		after := restored.getPnls(scope, "")
+		assert.Len(t, after, len(before))
+		for i := range before {
+			assert.Equal(t, before[i].TotalPnl, after[i].TotalPnl)
+			assert.Equal(t, before[i].PreviousDayTotalPnl, after[i].PreviousDayTotalPnl)
+		}
+	}
+
+	restored.onTrade(newTestTrade(2, "order3", "desk1", "trader1", 1, model.Side_SELL, 6, 108))
+	pnl = getTestPnl(t, restored, api.PnlScope_DESK, "desk1")
+	assert.Equal(t, 88.0, pnl.TotalPnl)
+	assert.Equal(t, 18.0, pnl.DayPnl)
+}
+
+func TestRestoreOfEmptySnapshot(t *testing.T) {
+	engine, _ := newTestEngine()
+	lastTradeOffset, err := engine.restore(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), lastTradeOffset)
+
+	_, err = engine.restore([]snapshotRecord{{Scope: "UNKNOWN"}})
+	assert.Error(t, err)
+}
+
+func TestSubscriptionReceivesLatestPnlOfChangedScopes(t *testing.T) {
+	engine, _ := newTestEngine()
+	engine.onTrade(newTestTrade(0, "order1", "desk1", "trader1", 1, model.Side_BUY, 10, 100))
+	engine.onTrade(newTestTrade(1, "order2", "desk2", "trader2", 1, model.Side_BUY, 10, 100))
+
+	initial, subscription := engine.subscribe(api.PnlScope_DESK, "")
+	assert.Len(t, initial, 2)
+
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(101)})
+	engine.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(102)})
+	engine.onTrade(newTestTrade(2, "order3", "desk1", "trader1", 2, model.Side_BUY, 1, 100))
+
+	<-subscription.changed
+	changes := engine.takeChanges(subscription)
+	assert.Len(t, changes, 2)
+
+	byDesk := map[string]*api.Pnl{}
+	for _, change := range changes {
+		byDesk[change.ScopeId] = change
+	}
+	assert.Equal(t, 20.0, byDesk["desk1"].UnrealisedPnl)
+	assert.Equal(t, 20.0, byDesk["desk2"].UnrealisedPnl)
+
+	assert.Empty(t, engine.takeChanges(subscription))
+
+	engine.unsubscribe(subscription)
+	engine.onTrade(newTestTrade(3, "order4", "desk1", "trader1", 1, model.Side_BUY, 10, 100))
+	assert.Empty(t, engine.takeChanges(subscription))
+}
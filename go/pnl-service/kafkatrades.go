@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ettec/open-trading-platform/go/pnl-service/api/tradeservice"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+)
+
+const tradesTopic = "trades"
+
+// streamTrades returns a channel of the trades in the trades topic from the given offset, or from the first available
+// offset if the offset is negative.  The channel is closed if the context is cancelled or a trade cannot be read.  The
+// trades topic has a single partition so the offset of a trade identifies its position in the trade sequence.
+func streamTrades(ctx context.Context, readerConfig kafka.ReaderConfig, fromOffset int64, bufferSize int) (<-chan tradeUpdate, error) {
+	reader := kafka.NewReader(readerConfig)
+	if fromOffset >= 0 {
+		if err := reader.SetOffset(fromOffset); err != nil {
+			_ = reader.Close()
+			return nil, fmt.Errorf("failed to set trades reader offset to %v: %w", fromOffset, err)
+		}
+	}
+
+	out := make(chan tradeUpdate, bufferSize)
+
+	go func() {
+		defer close(out)
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing kafka reader", "error", err)
+			}
+		}()
+
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("failed to read trade", "error", err)
+				}
+				return
+			}
+
+			trade := &tradeservice.Trade{}
+			if err = proto.Unmarshal(msg.Value, trade); err != nil {
+				slog.Error("failed to unmarshal trade", "offset", msg.Offset, "error", err)
+				return
+			}
+
+			select {
+			case out <- tradeUpdate{offset: msg.Offset, trade: trade}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/pnl-service/api/pnlservice"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/orderstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const maxConnectRetry = 2 * time.Second
+
+type service struct {
+	engine                    *pnlEngine
+	defaultConflationInterval time.Duration
+}
+
+func newService(engine *pnlEngine, defaultConflationInterval time.Duration) *service {
+	return &service{engine: engine, defaultConflationInterval: defaultConflationInterval}
+}
+
+func (s *service) GetPnl(_ context.Context, params *api.GetPnlParams) (*api.Pnls, error) {
+	return &api.Pnls{Pnls: s.engine.getPnls(params.Scope, params.ScopeId)}, nil
+}
+
+// SubscribeToPnl sends the current pnls of the subscribed scope followed by the changes to them.  Changes are conflated,
+// after a change the stream waits for the conflation interval and then sends the latest pnl of every scope that has
+// changed since the last send.
+func (s *service) SubscribeToPnl(params *api.SubscribeToPnlParams, stream api.PnlService_SubscribeToPnlServer) error {
+	if params.ConflationIntervalMillis < 0 {
+		return status.Errorf(codes.InvalidArgument, "conflation interval must not be negative: %v", params.ConflationIntervalMillis)
+	}
+
+	conflationInterval := time.Duration(params.ConflationIntervalMillis) * time.Millisecond
+	if conflationInterval == 0 {
+		conflationInterval = s.defaultConflationInterval
+	}
+
+	slog.Info("subscribing to pnl", "scope", params.Scope, "scopeId", params.ScopeId,
+		"conflationInterval", conflationInterval)
+
+	pnls, subscription := s.engine.subscribe(params.Scope, params.ScopeId)
+	defer func() {
+		s.engine.unsubscribe(subscription)
+		slog.Info("unsubscribed from pnl", "scope", params.Scope, "scopeId", params.ScopeId)
+	}()
+
+	for {
+		for _, pnl := range pnls {
+			if err := stream.Send(pnl); err != nil {
+				return fmt.Errorf("failed to send pnl: %w", err)
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-subscription.changed:
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-time.After(conflationInterval):
+		}
+
+		pnls = s.engine.takeChanges(subscription)
+	}
+}
+
+// endOfDayTime is the local time of day at which the end of day snapshot is taken.
+type endOfDayTime struct {
+	hour   int
+	minute int
+}
+
+func parseEndOfDayTime(value string) (endOfDayTime, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return endOfDayTime{}, fmt.Errorf("end of day time %q is not of the form HH:MM: %w", value, err)
+	}
+
+	return endOfDayTime{hour: t.Hour(), minute: t.Minute()}, nil
+}
+
+// next returns the first end of day after the given time.
+func (e endOfDayTime) next(after time.Time) time.Time {
+	next := time.Date(after.Year(), after.Month(), after.Day(), e.hour, e.minute, 0, 0, after.Location())
+	if !next.After(after) {
+		next = time.Date(after.Year(), after.Month(), after.Day()+1, e.hour, e.minute, 0, 0, after.Location())
+	}
+
+	return next
+}
+
+type snapshotWriter interface {
+	write(ctx context.Context, records []snapshotRecord) error
+}
+
+// run applies the trades and quotes to the pnl engine and takes the end of day snapshot, until the context is
+// cancelled, either channel is closed or the snapshot cannot be written.  The snapshot is taken on the same goroutine
+// as the trades are applied so that it includes exactly the trades up to its last trade offset.
+func run(ctx context.Context, engine *pnlEngine, trades <-chan tradeUpdate, quotes <-chan *model.ClobQuote,
+	endOfDay endOfDayTime, snapshots snapshotWriter) error {
+
+	endOfDayTimer := time.NewTimer(time.Until(endOfDay.next(time.Now())))
+	defer endOfDayTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case trade, ok := <-trades:
+			if !ok {
+				return errors.New("trades channel closed")
+			}
+			engine.onTrade(trade)
+		case quote, ok := <-quotes:
+			if !ok {
+				return errors.New("quote channel closed")
+			}
+			engine.onQuote(quote)
+		case now := <-endOfDayTimer.C:
+			records := engine.endOfDay(now)
+			if err := snapshots.write(ctx, records); err != nil {
+				return fmt.Errorf("failed to write end of day snapshot: %w", err)
+			}
+			slog.Info("end of day snapshot taken", "records", len(records))
+			endOfDayTimer.Reset(time.Until(endOfDay.next(now)))
+		}
+	}
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+	snapshotsTopic := bootstrap.GetOptionalEnvVar("PNL_SNAPSHOTS_TOPIC", "pnl-snapshots")
+	defaultConflationInterval := time.Duration(bootstrap.GetOptionalIntEnvVar("PNL_CONFLATION_INTERVAL_MILLIS", 1000)) * time.Millisecond
+
+	endOfDay, err := parseEndOfDayTime(bootstrap.GetOptionalEnvVar("END_OF_DAY_TIME", "17:00"))
+	if err != nil {
+		log.Panicf("invalid end of day time: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id, err := os.Hostname()
+	if err != nil {
+		log.Panicf("failed to get hostname: %v", err)
+	}
+
+	mdsAddress := bootstrap.GetOptionalEnvVar("MARKET_DATA_SERVICE_ADDRESS", "")
+	if mdsAddress == "" {
+		mdsAddress, err = k8s.GetServiceAddress("market-data-service")
+		if err != nil {
+			log.Panicf("failed to get market data service address: %v", err)
+		}
+	}
+
+	quoteStream, err := marketdata.NewQuoteStreamFromMarketDataService(ctx, id, mdsAddress, maxConnectRetry,
+		bootstrap.GetOptionalIntEnvVar("QUOTE_BUFFER_SIZE", 1000))
+	if err != nil {
+		log.Panicf("failed to create quote stream: %v", err)
+	}
+	defer quoteStream.Close()
+
+	engine := newPnlEngine(quoteStream.Subscribe)
+
+	snapshots := newSnapshotStore(orderstore.DefaultReaderConfig(snapshotsTopic, kafkaBrokers),
+		orderstore.DefaultWriterConfig(snapshotsTopic, kafkaBrokers))
+	defer func() {
+		if err := snapshots.Close(); err != nil {
+			slog.Error("error closing snapshot store", "error", err)
+		}
+	}()
+
+	records, err := snapshots.load(ctx)
+	if err != nil {
+		log.Panicf("failed to load end of day snapshot: %v", err)
+	}
+
+	lastTradeOffset, err := engine.restore(records)
+	if err != nil {
+		log.Panicf("failed to restore end of day snapshot: %v", err)
+	}
+
+	fromOffset := int64(-1)
+	if lastTradeOffset >= 0 {
+		fromOffset = lastTradeOffset + 1
+	}
+
+	trades, err := streamTrades(ctx, orderstore.DefaultReaderConfig(tradesTopic, kafkaBrokers), fromOffset,
+		bootstrap.GetOptionalIntEnvVar("TRADES_BUFFER_SIZE", 1000))
+	if err != nil {
+		log.Panicf("failed to stream trades: %v", err)
+	}
+
+	port := "50551"
+	slog.Info("Starting pnl service", "port", port)
+	listener, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	s := grpc.NewServer()
+	api.RegisterPnlServiceServer(s, newService(engine, defaultConflationInterval))
+	reflection.Register(s)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+		s.GracefulStop()
+	}()
+
+	go func() {
+		if err := run(ctx, engine, trades, quoteStream.Chan(), endOfDay, snapshots); err != nil {
+			log.Panicf("pnl calculation failed: %v", err)
+		}
+	}()
+
+	if err := s.Serve(listener); err != nil {
+		log.Panicf("Error while serving : %v", err)
+	}
+}
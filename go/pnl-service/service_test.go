@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNextEndOfDay(t *testing.T) {
+	endOfDay, err := parseEndOfDayTime("17:30")
+	assert.NoError(t, err)
+
+	day := func(d int, hour int, minute int) time.Time {
+		return time.Date(2024, time.March, d, hour, minute, 0, 0, time.UTC)
+	}
+
+	assert.Equal(t, day(4, 17, 30), endOfDay.next(day(4, 9, 0)))
+	assert.Equal(t, day(5, 17, 30), endOfDay.next(day(4, 17, 30)))
+	assert.Equal(t, day(5, 17, 30), endOfDay.next(day(4, 23, 0)))
+	assert.Equal(t, time.Date(2024, time.April, 1, 17, 30, 0, 0, time.UTC), endOfDay.next(day(31, 18, 0)))
+
+	_, err = parseEndOfDayTime("5pm")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// snapshotRecord is the json representation of the end of day state of a book in the snapshots topic.  The last trade
+// offset is the offset in the trades topic of the last trade applied to the books before the snapshot was taken.
+type snapshotRecord struct {
+	Scope           string          `json:"scope"`
+	ScopeId         string          `json:"scopeId"`
+	ListingId       int32           `json:"listingId"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	AvgPrice        decimal.Decimal `json:"avgPrice"`
+	RealisedPnl     decimal.Decimal `json:"realisedPnl"`
+	MarkPrice       decimal.Decimal `json:"markPrice"`
+	HasMark         bool            `json:"hasMark"`
+	LastTradeOffset int64           `json:"lastTradeOffset"`
+	SnapshotTime    time.Time       `json:"snapshotTime"`
+}
+
+func (r snapshotRecord) key() string {
+	return r.Scope + "/" + r.ScopeId + "/" + strconv.Itoa(int(r.ListingId))
+}
+
+// snapshotStore persists the end of day snapshot to a compacted kafka topic keyed by book, each snapshot overwrites the
+// records of the previous snapshot.  The topic must have a single partition.
+type snapshotStore struct {
+	readerConfig kafka.ReaderConfig
+	writer       *kafka.Writer
+}
+
+func newSnapshotStore(readerConfig kafka.ReaderConfig, writerConfig kafka.WriterConfig) *snapshotStore {
+	writerConfig.Async = false
+	writerConfig.Balancer = &kafka.Hash{}
+
+	return &snapshotStore{
+		readerConfig: readerConfig,
+		writer:       kafka.NewWriter(writerConfig),
+	}
+}
+
+// load returns the records of the latest snapshot of each book.
+func (s *snapshotStore) load(ctx context.Context) ([]snapshotRecord, error) {
+	reader := kafka.NewReader(s.readerConfig)
+	defer func() {
+		if err := reader.Close(); err != nil {
+			slog.Error("error closing snapshots reader", "error", err)
+		}
+	}()
+
+	records := map[string]snapshotRecord{}
+	for {
+		lag, err := reader.ReadLag(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lag: %w", err)
+		}
+
+		if lag <= 0 {
+			break
+		}
+
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message: %w", err)
+		}
+
+		record := snapshotRecord{}
+		if err := json.Unmarshal(msg.Value, &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal snapshot record: %w", err)
+		}
+
+		records[string(msg.Key)] = record
+	}
+
+	result := make([]snapshotRecord, 0, len(records))
+	for _, record := range records {
+		result = append(result, record)
+	}
+
+	slog.Info("loaded end of day snapshot", "records", len(result))
+
+	return result, nil
+}
+
+func (s *snapshotStore) write(ctx context.Context, records []snapshotRecord) error {
+	messages := make([]kafka.Message, 0, len(records))
+	for _, record := range records {
+		recordJson, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot record: %w", err)
+		}
+
+		messages = append(messages, kafka.Message{Key: []byte(record.key()), Value: recordJson})
+	}
+
+	if err := s.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to write snapshot records: %w", err)
+	}
+
+	return nil
+}
+
+func (s *snapshotStore) Close() error {
+	return s.writer.Close()
+}
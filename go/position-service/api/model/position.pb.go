@@ -0,0 +1,267 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: position.proto
+
+package model
+
+import (
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Position struct {
+	UserId               string           `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenId              string           `protobuf:"bytes,2,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	LongQuantity         float64          `protobuf:"fixed64,3,opt,name=long_quantity,json=longQuantity,proto3" json:"long_quantity,omitempty"`
+	ShortQuantity        float64          `protobuf:"fixed64,4,opt,name=short_quantity,json=shortQuantity,proto3" json:"short_quantity,omitempty"`
+	AverageLongPrice     float64          `protobuf:"fixed64,5,opt,name=average_long_price,json=averageLongPrice,proto3" json:"average_long_price,omitempty"`
+	AverageShortPrice    float64          `protobuf:"fixed64,6,opt,name=average_short_price,json=averageShortPrice,proto3" json:"average_short_price,omitempty"`
+	UnrealizedPnl        float64          `protobuf:"fixed64,7,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	MarginUsed           float64          `protobuf:"fixed64,8,opt,name=margin_used,json=marginUsed,proto3" json:"margin_used,omitempty"`
+	MarginAvailable      float64          `protobuf:"fixed64,9,opt,name=margin_available,json=marginAvailable,proto3" json:"margin_available,omitempty"`
+	LastUpdated          *model.Timestamp `protobuf:"bytes,10,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	Desk                 string           `protobuf:"bytes,11,opt,name=desk,proto3" json:"desk,omitempty"`
+	ListingId            int32            `protobuf:"varint,12,opt,name=listing_id,json=listingId,proto3" json:"listing_id,omitempty"`
+	RealizedPnl          float64          `protobuf:"fixed64,13,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	MarkPrice            float64          `protobuf:"fixed64,14,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Position) Reset()         { *m = Position{} }
+func (m *Position) String() string { return proto.CompactTextString(m) }
+func (*Position) ProtoMessage()    {}
+func (*Position) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56e266f1a28a7893, []int{0}
+}
+
+func (m *Position) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Position.Unmarshal(m, b)
+}
+func (m *Position) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Position.Marshal(b, m, deterministic)
+}
+func (m *Position) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Position.Merge(m, src)
+}
+func (m *Position) XXX_Size() int {
+	return xxx_messageInfo_Position.Size(m)
+}
+func (m *Position) XXX_DiscardUnknown() {
+	xxx_messageInfo_Position.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Position proto.InternalMessageInfo
+
+func (m *Position) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Position) GetTokenId() string {
+	if m != nil {
+		return m.TokenId
+	}
+	return ""
+}
+
+func (m *Position) GetLongQuantity() float64 {
+	if m != nil {
+		return m.LongQuantity
+	}
+	return 0
+}
+
+func (m *Position) GetShortQuantity() float64 {
+	if m != nil {
+		return m.ShortQuantity
+	}
+	return 0
+}
+
+func (m *Position) GetAverageLongPrice() float64 {
+	if m != nil {
+		return m.AverageLongPrice
+	}
+	return 0
+}
+
+func (m *Position) GetAverageShortPrice() float64 {
+	if m != nil {
+		return m.AverageShortPrice
+	}
+	return 0
+}
+
+func (m *Position) GetUnrealizedPnl() float64 {
+	if m != nil {
+		return m.UnrealizedPnl
+	}
+	return 0
+}
+
+func (m *Position) GetMarginUsed() float64 {
+	if m != nil {
+		return m.MarginUsed
+	}
+	return 0
+}
+
+func (m *Position) GetMarginAvailable() float64 {
+	if m != nil {
+		return m.MarginAvailable
+	}
+	return 0
+}
+
+func (m *Position) GetLastUpdated() *model.Timestamp {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return nil
+}
+
+func (m *Position) GetDesk() string {
+	if m != nil {
+		return m.Desk
+	}
+	return ""
+}
+
+func (m *Position) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func (m *Position) GetRealizedPnl() float64 {
+	if m != nil {
+		return m.RealizedPnl
+	}
+	return 0
+}
+
+func (m *Position) GetMarkPrice() float64 {
+	if m != nil {
+		return m.MarkPrice
+	}
+	return 0
+}
+
+type PositionSummary struct {
+	UserId               string      `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Positions            []*Position `protobuf:"bytes,2,rep,name=positions,proto3" json:"positions,omitempty"`
+	TotalUnrealizedPnl   float64     `protobuf:"fixed64,3,opt,name=total_unrealized_pnl,json=totalUnrealizedPnl,proto3" json:"total_unrealized_pnl,omitempty"`
+	TotalMarginUsed      float64     `protobuf:"fixed64,4,opt,name=total_margin_used,json=totalMarginUsed,proto3" json:"total_margin_used,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *PositionSummary) Reset()         { *m = PositionSummary{} }
+func (m *PositionSummary) String() string { return proto.CompactTextString(m) }
+func (*PositionSummary) ProtoMessage()    {}
+func (*PositionSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56e266f1a28a7893, []int{1}
+}
+
+func (m *PositionSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PositionSummary.Unmarshal(m, b)
+}
+func (m *PositionSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PositionSummary.Marshal(b, m, deterministic)
+}
+func (m *PositionSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PositionSummary.Merge(m, src)
+}
+func (m *PositionSummary) XXX_Size() int {
+	return xxx_messageInfo_PositionSummary.Size(m)
+}
+func (m *PositionSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_PositionSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PositionSummary proto.InternalMessageInfo
+
+func (m *PositionSummary) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *PositionSummary) GetPositions() []*Position {
+	if m != nil {
+		return m.Positions
+	}
+	return nil
+}
+
+func (m *PositionSummary) GetTotalUnrealizedPnl() float64 {
+	if m != nil {
+		return m.TotalUnrealizedPnl
+	}
+	return 0
+}
+
+func (m *PositionSummary) GetTotalMarginUsed() float64 {
+	if m != nil {
+		return m.TotalMarginUsed
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Position)(nil), "model.Position")
+	proto.RegisterType((*PositionSummary)(nil), "model.PositionSummary")
+}
+
+func init() { proto.RegisterFile("position.proto", fileDescriptor_56e266f1a28a7893) }
+
+var fileDescriptor_56e266f1a28a7893 = []byte{
+	// 436 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xdd, 0x6e, 0xd4, 0x30,
+	0x10, 0x85, 0x95, 0x76, 0xff, 0x32, 0xd9, 0x5f, 0x83, 0x84, 0x41, 0xaa, 0x08, 0x45, 0x95, 0x02,
+	0x82, 0x15, 0x6a, 0x9f, 0x00, 0xee, 0x56, 0x02, 0x69, 0x49, 0xd9, 0x1b, 0x6e, 0x22, 0xb7, 0xb6,
+	0x82, 0xb5, 0x8e, 0x1d, 0x62, 0xa7, 0x52, 0xb9, 0xe7, 0x99, 0x78, 0x3d, 0xe4, 0x71, 0xd2, 0xed,
+	0x22, 0x71, 0x95, 0xf8, 0x9c, 0xcf, 0x23, 0xcf, 0xcc, 0x81, 0x79, 0x6d, 0xac, 0x74, 0xd2, 0xe8,
+	0x75, 0xdd, 0x18, 0x67, 0xc8, 0xb0, 0x32, 0x5c, 0xa8, 0x17, 0x2b, 0xfc, 0xdc, 0x9a, 0xaa, 0xea,
+	0x9d, 0xf3, 0xdf, 0x03, 0x98, 0x6c, 0x3b, 0x98, 0x3c, 0x83, 0x71, 0x6b, 0x45, 0x53, 0x48, 0x4e,
+	0xa3, 0x34, 0xca, 0xe2, 0x7c, 0xe4, 0x8f, 0x1b, 0x4e, 0x9e, 0xc3, 0xc4, 0x99, 0xbd, 0xd0, 0xde,
+	0x39, 0x41, 0x67, 0x8c, 0xe7, 0x0d, 0x27, 0xaf, 0x61, 0xa6, 0x8c, 0x2e, 0x8b, 0x9f, 0x2d, 0xd3,
+	0x4e, 0xba, 0x7b, 0x7a, 0x9a, 0x46, 0x59, 0x94, 0x4f, 0xbd, 0xf8, 0xb5, 0xd3, 0xc8, 0x05, 0xcc,
+	0xed, 0x0f, 0xd3, 0xb8, 0x03, 0x35, 0x40, 0x6a, 0x86, 0xea, 0x03, 0xf6, 0x0e, 0x08, 0xbb, 0x13,
+	0x0d, 0x2b, 0x45, 0x81, 0x35, 0xeb, 0x46, 0xde, 0x0a, 0x3a, 0x44, 0x74, 0xd9, 0x39, 0x9f, 0x8d,
+	0x2e, 0xb7, 0x5e, 0x27, 0x6b, 0x78, 0xd2, 0xd3, 0xa1, 0x78, 0xc0, 0x47, 0x88, 0xaf, 0x3a, 0xeb,
+	0xda, 0x3b, 0x81, 0xbf, 0x80, 0x79, 0xab, 0x1b, 0xc1, 0x94, 0xfc, 0x25, 0x78, 0x51, 0x6b, 0x45,
+	0xc7, 0xe1, 0x11, 0x07, 0x75, 0xab, 0x15, 0x79, 0x09, 0x49, 0xc5, 0x9a, 0x52, 0xea, 0xa2, 0xb5,
+	0x82, 0xd3, 0x09, 0x32, 0x10, 0xa4, 0x9d, 0x15, 0x9c, 0xbc, 0x81, 0x65, 0x07, 0xb0, 0x3b, 0x26,
+	0x15, 0xbb, 0x51, 0x82, 0xc6, 0x48, 0x2d, 0x82, 0xfe, 0xb1, 0x97, 0xc9, 0x15, 0x4c, 0x15, 0xb3,
+	0xae, 0x68, 0x6b, 0xce, 0x9c, 0xe0, 0x14, 0xd2, 0x28, 0x4b, 0x2e, 0x97, 0x6b, 0xdc, 0xc3, 0xfa,
+	0x9b, 0xac, 0x84, 0x75, 0xac, 0xaa, 0xf3, 0xc4, 0x53, 0xbb, 0x00, 0x11, 0x02, 0x03, 0x2e, 0xec,
+	0x9e, 0x26, 0x38, 0x68, 0xfc, 0x27, 0x67, 0x00, 0x4a, 0x5a, 0x27, 0x75, 0xe9, 0x57, 0x30, 0x4d,
+	0xa3, 0x6c, 0x98, 0xc7, 0x9d, 0xb2, 0xe1, 0xe4, 0x15, 0x4c, 0x8f, 0x1a, 0x9b, 0xe1, 0x73, 0x92,
+	0xc7, 0x6d, 0x9d, 0x81, 0xef, 0x61, 0xdf, 0x0d, 0x69, 0x8e, 0x40, 0xec, 0x15, 0x1c, 0xce, 0xf9,
+	0x9f, 0x08, 0x16, 0x7d, 0x0e, 0xae, 0xdb, 0xaa, 0x62, 0xcd, 0xfd, 0xff, 0xe3, 0xf0, 0x1e, 0xe2,
+	0x3e, 0x60, 0x96, 0x9e, 0xa4, 0xa7, 0x59, 0x72, 0xb9, 0xe8, 0x7a, 0xea, 0x6b, 0xe4, 0x07, 0x82,
+	0x7c, 0x80, 0xa7, 0xce, 0x38, 0xa6, 0x8a, 0x7f, 0xc6, 0x1f, 0x92, 0x42, 0xd0, 0xdb, 0x1d, 0xed,
+	0xe0, 0x2d, 0xac, 0xc2, 0x8d, 0xc7, 0x9b, 0x08, 0x91, 0x59, 0xa0, 0xf1, 0xe5, 0x61, 0x1d, 0x9f,
+	0xc6, 0xdf, 0x43, 0xba, 0x6f, 0x46, 0x98, 0xe8, 0xab, 0xbf, 0x03, 0x00, 0x59, 0xb9, 0xae, 0xaa,
+	0xfd, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,889 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: position_service.proto
+
+package positionservice
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/open-trading-platform/go/position-service/api/model"
+	_ "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetPositionRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenId              string   `protobuf:"bytes,2,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	Desk                 string   `protobuf:"bytes,3,opt,name=desk,proto3" json:"desk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPositionRequest) Reset()         { *m = GetPositionRequest{} }
+func (m *GetPositionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPositionRequest) ProtoMessage()    {}
+func (*GetPositionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{0}
+}
+
+func (m *GetPositionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPositionRequest.Unmarshal(m, b)
+}
+func (m *GetPositionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPositionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPositionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPositionRequest.Merge(m, src)
+}
+func (m *GetPositionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPositionRequest.Size(m)
+}
+func (m *GetPositionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPositionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPositionRequest proto.InternalMessageInfo
+
+func (m *GetPositionRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *GetPositionRequest) GetTokenId() string {
+	if m != nil {
+		return m.TokenId
+	}
+	return ""
+}
+
+func (m *GetPositionRequest) GetDesk() string {
+	if m != nil {
+		return m.Desk
+	}
+	return ""
+}
+
+type GetPositionResponse struct {
+	Position             *model.Position `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetPositionResponse) Reset()         { *m = GetPositionResponse{} }
+func (m *GetPositionResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPositionResponse) ProtoMessage()    {}
+func (*GetPositionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{1}
+}
+
+func (m *GetPositionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPositionResponse.Unmarshal(m, b)
+}
+func (m *GetPositionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPositionResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPositionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPositionResponse.Merge(m, src)
+}
+func (m *GetPositionResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPositionResponse.Size(m)
+}
+func (m *GetPositionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPositionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPositionResponse proto.InternalMessageInfo
+
+func (m *GetPositionResponse) GetPosition() *model.Position {
+	if m != nil {
+		return m.Position
+	}
+	return nil
+}
+
+type ListPositionsRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Desk                 string   `protobuf:"bytes,2,opt,name=desk,proto3" json:"desk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPositionsRequest) Reset()         { *m = ListPositionsRequest{} }
+func (m *ListPositionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListPositionsRequest) ProtoMessage()    {}
+func (*ListPositionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{2}
+}
+
+func (m *ListPositionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPositionsRequest.Unmarshal(m, b)
+}
+func (m *ListPositionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPositionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListPositionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPositionsRequest.Merge(m, src)
+}
+func (m *ListPositionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListPositionsRequest.Size(m)
+}
+func (m *ListPositionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPositionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPositionsRequest proto.InternalMessageInfo
+
+func (m *ListPositionsRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ListPositionsRequest) GetDesk() string {
+	if m != nil {
+		return m.Desk
+	}
+	return ""
+}
+
+type ListPositionsResponse struct {
+	Positions            []*model.Position `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ListPositionsResponse) Reset()         { *m = ListPositionsResponse{} }
+func (m *ListPositionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListPositionsResponse) ProtoMessage()    {}
+func (*ListPositionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{3}
+}
+
+func (m *ListPositionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPositionsResponse.Unmarshal(m, b)
+}
+func (m *ListPositionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPositionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListPositionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPositionsResponse.Merge(m, src)
+}
+func (m *ListPositionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListPositionsResponse.Size(m)
+}
+func (m *ListPositionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPositionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPositionsResponse proto.InternalMessageInfo
+
+func (m *ListPositionsResponse) GetPositions() []*model.Position {
+	if m != nil {
+		return m.Positions
+	}
+	return nil
+}
+
+type GetAllPositionsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAllPositionsRequest) Reset()         { *m = GetAllPositionsRequest{} }
+func (m *GetAllPositionsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAllPositionsRequest) ProtoMessage()    {}
+func (*GetAllPositionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{4}
+}
+
+func (m *GetAllPositionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAllPositionsRequest.Unmarshal(m, b)
+}
+func (m *GetAllPositionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAllPositionsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetAllPositionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAllPositionsRequest.Merge(m, src)
+}
+func (m *GetAllPositionsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAllPositionsRequest.Size(m)
+}
+func (m *GetAllPositionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAllPositionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAllPositionsRequest proto.InternalMessageInfo
+
+type GetAllPositionsResponse struct {
+	Positions            []*model.Position `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetAllPositionsResponse) Reset()         { *m = GetAllPositionsResponse{} }
+func (m *GetAllPositionsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetAllPositionsResponse) ProtoMessage()    {}
+func (*GetAllPositionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{5}
+}
+
+func (m *GetAllPositionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAllPositionsResponse.Unmarshal(m, b)
+}
+func (m *GetAllPositionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAllPositionsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetAllPositionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAllPositionsResponse.Merge(m, src)
+}
+func (m *GetAllPositionsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetAllPositionsResponse.Size(m)
+}
+func (m *GetAllPositionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAllPositionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAllPositionsResponse proto.InternalMessageInfo
+
+func (m *GetAllPositionsResponse) GetPositions() []*model.Position {
+	if m != nil {
+		return m.Positions
+	}
+	return nil
+}
+
+type SubscribePositionsRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Desk                 string   `protobuf:"bytes,2,opt,name=desk,proto3" json:"desk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribePositionsRequest) Reset()         { *m = SubscribePositionsRequest{} }
+func (m *SubscribePositionsRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribePositionsRequest) ProtoMessage()    {}
+func (*SubscribePositionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{6}
+}
+
+func (m *SubscribePositionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribePositionsRequest.Unmarshal(m, b)
+}
+func (m *SubscribePositionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribePositionsRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribePositionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribePositionsRequest.Merge(m, src)
+}
+func (m *SubscribePositionsRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribePositionsRequest.Size(m)
+}
+func (m *SubscribePositionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribePositionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribePositionsRequest proto.InternalMessageInfo
+
+func (m *SubscribePositionsRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *SubscribePositionsRequest) GetDesk() string {
+	if m != nil {
+		return m.Desk
+	}
+	return ""
+}
+
+type GetPositionHistoryRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenId              string   `protobuf:"bytes,2,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	Desk                 string   `protobuf:"bytes,3,opt,name=desk,proto3" json:"desk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPositionHistoryRequest) Reset()         { *m = GetPositionHistoryRequest{} }
+func (m *GetPositionHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPositionHistoryRequest) ProtoMessage()    {}
+func (*GetPositionHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{7}
+}
+
+func (m *GetPositionHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPositionHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetPositionHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPositionHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPositionHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPositionHistoryRequest.Merge(m, src)
+}
+func (m *GetPositionHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPositionHistoryRequest.Size(m)
+}
+func (m *GetPositionHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPositionHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPositionHistoryRequest proto.InternalMessageInfo
+
+func (m *GetPositionHistoryRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *GetPositionHistoryRequest) GetTokenId() string {
+	if m != nil {
+		return m.TokenId
+	}
+	return ""
+}
+
+func (m *GetPositionHistoryRequest) GetDesk() string {
+	if m != nil {
+		return m.Desk
+	}
+	return ""
+}
+
+type GetPositionHistoryResponse struct {
+	Positions            []*model.Position `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetPositionHistoryResponse) Reset()         { *m = GetPositionHistoryResponse{} }
+func (m *GetPositionHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPositionHistoryResponse) ProtoMessage()    {}
+func (*GetPositionHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{8}
+}
+
+func (m *GetPositionHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPositionHistoryResponse.Unmarshal(m, b)
+}
+func (m *GetPositionHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPositionHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPositionHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPositionHistoryResponse.Merge(m, src)
+}
+func (m *GetPositionHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPositionHistoryResponse.Size(m)
+}
+func (m *GetPositionHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPositionHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPositionHistoryResponse proto.InternalMessageInfo
+
+func (m *GetPositionHistoryResponse) GetPositions() []*model.Position {
+	if m != nil {
+		return m.Positions
+	}
+	return nil
+}
+
+type GetPortfolioStatsRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Desk                 string   `protobuf:"bytes,2,opt,name=desk,proto3" json:"desk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPortfolioStatsRequest) Reset()         { *m = GetPortfolioStatsRequest{} }
+func (m *GetPortfolioStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPortfolioStatsRequest) ProtoMessage()    {}
+func (*GetPortfolioStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{9}
+}
+
+func (m *GetPortfolioStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPortfolioStatsRequest.Unmarshal(m, b)
+}
+func (m *GetPortfolioStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPortfolioStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPortfolioStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPortfolioStatsRequest.Merge(m, src)
+}
+func (m *GetPortfolioStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPortfolioStatsRequest.Size(m)
+}
+func (m *GetPortfolioStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPortfolioStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPortfolioStatsRequest proto.InternalMessageInfo
+
+func (m *GetPortfolioStatsRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *GetPortfolioStatsRequest) GetDesk() string {
+	if m != nil {
+		return m.Desk
+	}
+	return ""
+}
+
+type GetPortfolioStatsResponse struct {
+	TotalValue           float64  `protobuf:"fixed64,1,opt,name=total_value,json=totalValue,proto3" json:"total_value,omitempty"`
+	TotalUnrealizedPnl   float64  `protobuf:"fixed64,2,opt,name=total_unrealized_pnl,json=totalUnrealizedPnl,proto3" json:"total_unrealized_pnl,omitempty"`
+	MarginUsed           float64  `protobuf:"fixed64,3,opt,name=margin_used,json=marginUsed,proto3" json:"margin_used,omitempty"`
+	MarginAvailable      float64  `protobuf:"fixed64,4,opt,name=margin_available,json=marginAvailable,proto3" json:"margin_available,omitempty"`
+	TotalRealizedPnl     float64  `protobuf:"fixed64,5,opt,name=total_realized_pnl,json=totalRealizedPnl,proto3" json:"total_realized_pnl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPortfolioStatsResponse) Reset()         { *m = GetPortfolioStatsResponse{} }
+func (m *GetPortfolioStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPortfolioStatsResponse) ProtoMessage()    {}
+func (*GetPortfolioStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d5546d779581f29b, []int{10}
+}
+
+func (m *GetPortfolioStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPortfolioStatsResponse.Unmarshal(m, b)
+}
+func (m *GetPortfolioStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPortfolioStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPortfolioStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPortfolioStatsResponse.Merge(m, src)
+}
+func (m *GetPortfolioStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPortfolioStatsResponse.Size(m)
+}
+func (m *GetPortfolioStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPortfolioStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPortfolioStatsResponse proto.InternalMessageInfo
+
+func (m *GetPortfolioStatsResponse) GetTotalValue() float64 {
+	if m != nil {
+		return m.TotalValue
+	}
+	return 0
+}
+
+func (m *GetPortfolioStatsResponse) GetTotalUnrealizedPnl() float64 {
+	if m != nil {
+		return m.TotalUnrealizedPnl
+	}
+	return 0
+}
+
+func (m *GetPortfolioStatsResponse) GetMarginUsed() float64 {
+	if m != nil {
+		return m.MarginUsed
+	}
+	return 0
+}
+
+func (m *GetPortfolioStatsResponse) GetMarginAvailable() float64 {
+	if m != nil {
+		return m.MarginAvailable
+	}
+	return 0
+}
+
+func (m *GetPortfolioStatsResponse) GetTotalRealizedPnl() float64 {
+	if m != nil {
+		return m.TotalRealizedPnl
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*GetPositionRequest)(nil), "positionservice.GetPositionRequest")
+	proto.RegisterType((*GetPositionResponse)(nil), "positionservice.GetPositionResponse")
+	proto.RegisterType((*ListPositionsRequest)(nil), "positionservice.ListPositionsRequest")
+	proto.RegisterType((*ListPositionsResponse)(nil), "positionservice.ListPositionsResponse")
+	proto.RegisterType((*GetAllPositionsRequest)(nil), "positionservice.GetAllPositionsRequest")
+	proto.RegisterType((*GetAllPositionsResponse)(nil), "positionservice.GetAllPositionsResponse")
+	proto.RegisterType((*SubscribePositionsRequest)(nil), "positionservice.SubscribePositionsRequest")
+	proto.RegisterType((*GetPositionHistoryRequest)(nil), "positionservice.GetPositionHistoryRequest")
+	proto.RegisterType((*GetPositionHistoryResponse)(nil), "positionservice.GetPositionHistoryResponse")
+	proto.RegisterType((*GetPortfolioStatsRequest)(nil), "positionservice.GetPortfolioStatsRequest")
+	proto.RegisterType((*GetPortfolioStatsResponse)(nil), "positionservice.GetPortfolioStatsResponse")
+}
+
+func init() { proto.RegisterFile("position_service.proto", fileDescriptor_d5546d779581f29b) }
+
+var fileDescriptor_d5546d779581f29b = []byte{
+	// 517 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xdb, 0x6e, 0xd3, 0x40,
+	0x14, 0x94, 0xdb, 0xd0, 0xcb, 0x89, 0xc0, 0xed, 0xa1, 0xb4, 0x8e, 0x5f, 0xa8, 0xcc, 0xad, 0x17,
+	0xb0, 0xaa, 0xf2, 0x05, 0x05, 0x89, 0xa4, 0x82, 0x87, 0xca, 0x51, 0x11, 0x0f, 0x08, 0xcb, 0x89,
+	0x17, 0xb4, 0x74, 0xed, 0x0d, 0xde, 0x75, 0x24, 0xf8, 0x5b, 0xde, 0xf8, 0x0c, 0xe4, 0xf5, 0xda,
+	0x4d, 0xec, 0x2d, 0xa9, 0x42, 0xdf, 0xe2, 0x39, 0xe3, 0x99, 0xd9, 0x63, 0x4f, 0x0c, 0xbb, 0x13,
+	0x2e, 0xa8, 0xa4, 0x3c, 0x0d, 0x05, 0xc9, 0xa6, 0x74, 0x4c, 0xfc, 0x49, 0xc6, 0x25, 0x47, 0xbb,
+	0xc2, 0x35, 0xec, 0x3e, 0xa8, 0x80, 0x92, 0xe0, 0x6e, 0x27, 0x3c, 0x26, 0x6c, 0xcc, 0x93, 0xa4,
+	0x82, 0xbc, 0xcf, 0x80, 0x7d, 0x22, 0x2f, 0x34, 0x2f, 0x20, 0x3f, 0x72, 0x22, 0x24, 0xee, 0xc1,
+	0x7a, 0x2e, 0x48, 0x16, 0xd2, 0xd8, 0xb1, 0xf6, 0xad, 0x83, 0xcd, 0x60, 0xad, 0xb8, 0x3c, 0x8f,
+	0xb1, 0x07, 0x1b, 0x92, 0x5f, 0x91, 0xb4, 0x98, 0xac, 0xa8, 0xc9, 0xba, 0xba, 0x3e, 0x8f, 0x11,
+	0xa1, 0x13, 0x13, 0x71, 0xe5, 0xac, 0x2a, 0x58, 0xfd, 0xf6, 0xde, 0xc0, 0xc3, 0x39, 0x75, 0x31,
+	0x29, 0xc2, 0xe1, 0x31, 0x6c, 0x54, 0xc9, 0x94, 0x7e, 0xf7, 0xd4, 0xf6, 0x55, 0x34, 0xbf, 0xa6,
+	0xd6, 0x04, 0xef, 0x2d, 0xec, 0x7c, 0xa0, 0xa2, 0x16, 0x11, 0x0b, 0x33, 0x56, 0x41, 0x56, 0x66,
+	0x82, 0xbc, 0x83, 0x47, 0x0d, 0x11, 0x1d, 0xe5, 0x15, 0x6c, 0xd6, 0x5b, 0x73, 0xac, 0xfd, 0x55,
+	0x53, 0x96, 0x6b, 0x86, 0xe7, 0xc0, 0x6e, 0x9f, 0xc8, 0x33, 0xc6, 0x9a, 0x71, 0xbc, 0x01, 0xec,
+	0xb5, 0x26, 0xcb, 0x79, 0x0c, 0xa0, 0x37, 0xcc, 0x47, 0x62, 0x9c, 0xd1, 0x11, 0xf9, 0xbf, 0x53,
+	0x8f, 0xa1, 0x37, 0xb3, 0xfe, 0x01, 0x15, 0x92, 0x67, 0x3f, 0xef, 0xfa, 0x19, 0xbf, 0x07, 0xd7,
+	0x64, 0xb2, 0xdc, 0xd9, 0xfb, 0xe0, 0x28, 0xb1, 0x4c, 0x7e, 0xe5, 0x8c, 0xf2, 0xa1, 0x8c, 0xe4,
+	0x72, 0x47, 0xff, 0x63, 0x41, 0xcf, 0xa0, 0xa4, 0x53, 0x3d, 0x86, 0xae, 0xe4, 0x32, 0x62, 0xe1,
+	0x34, 0x62, 0x39, 0x51, 0x72, 0x56, 0x00, 0x0a, 0xfa, 0x58, 0x20, 0x78, 0x02, 0x3b, 0x25, 0x21,
+	0x4f, 0x33, 0x12, 0x31, 0xfa, 0x8b, 0xc4, 0xe1, 0x24, 0x65, 0xca, 0xc2, 0x0a, 0x50, 0xcd, 0x2e,
+	0xeb, 0xd1, 0x45, 0xca, 0x0a, 0xc9, 0x24, 0xca, 0xbe, 0xd1, 0x34, 0xcc, 0x05, 0x89, 0xd5, 0x86,
+	0xac, 0x00, 0x4a, 0xe8, 0x52, 0x90, 0x18, 0x0f, 0x61, 0x4b, 0x13, 0xa2, 0x69, 0x44, 0x59, 0x34,
+	0x62, 0xc4, 0xe9, 0x28, 0x96, 0x5d, 0xe2, 0x67, 0x15, 0x8c, 0x2f, 0xa1, 0x74, 0x08, 0xe7, 0xbc,
+	0xef, 0x29, 0xf2, 0x96, 0x9a, 0x04, 0xd7, 0xce, 0xa7, 0xbf, 0x3b, 0x60, 0x57, 0xbb, 0x1c, 0x96,
+	0xcd, 0xc7, 0x4f, 0xd0, 0x9d, 0x79, 0x28, 0xf8, 0xc4, 0x6f, 0xfc, 0x35, 0xf8, 0xed, 0xd2, 0xbb,
+	0x4f, 0xff, 0x4d, 0xd2, 0xab, 0xfb, 0x02, 0xf7, 0xe7, 0x9a, 0x84, 0xcf, 0x5a, 0xb7, 0x99, 0xea,
+	0xea, 0x3e, 0x5f, 0x44, 0xd3, 0xfa, 0x31, 0xd8, 0x8d, 0x1e, 0xe1, 0x0b, 0x53, 0x30, 0x43, 0x07,
+	0xdd, 0x83, 0xc5, 0x44, 0xed, 0x32, 0x04, 0x6c, 0x77, 0x0c, 0x8f, 0x5a, 0xf7, 0xdf, 0x58, 0x44,
+	0xb7, 0xf9, 0x16, 0x9f, 0x58, 0x98, 0x00, 0xb6, 0x9b, 0x60, 0x10, 0xbd, 0xb1, 0x93, 0xee, 0xf1,
+	0xad, 0xb8, 0xfa, 0x0c, 0xdf, 0x61, 0xbb, 0xf5, 0x86, 0xe3, 0xa1, 0x59, 0xc1, 0xd0, 0x27, 0xf7,
+	0xe8, 0x36, 0xd4, 0xd2, 0x6b, 0xb4, 0xa6, 0xbe, 0x16, 0xaf, 0xff, 0x0e, 0x00, 0xd7, 0xdd, 0x6d,
+	0xb3, 0x7b, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PositionServiceClient is the client API for PositionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PositionServiceClient interface {
+	// Get a user's position in a specific asset
+	GetPosition(ctx context.Context, in *GetPositionRequest, opts ...grpc.CallOption) (*GetPositionResponse, error)
+	// List all positions for a user (their portfolio)
+	ListPositions(ctx context.Context, in *ListPositionsRequest, opts ...grpc.CallOption) (*ListPositionsResponse, error)
+	// (Admin) List all positions for all users
+	GetAllPositions(ctx context.Context, in *GetAllPositionsRequest, opts ...grpc.CallOption) (*GetAllPositionsResponse, error)
+	// Stream real-time position updates for a user
+	SubscribePositions(ctx context.Context, in *SubscribePositionsRequest, opts ...grpc.CallOption) (PositionService_SubscribePositionsClient, error)
+	// Get the history of a position (for audit/compliance)
+	GetPositionHistory(ctx context.Context, in *GetPositionHistoryRequest, opts ...grpc.CallOption) (*GetPositionHistoryResponse, error)
+	// Get aggregated portfolio stats for a user
+	GetPortfolioStats(ctx context.Context, in *GetPortfolioStatsRequest, opts ...grpc.CallOption) (*GetPortfolioStatsResponse, error)
+}
+
+type positionServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPositionServiceClient(cc *grpc.ClientConn) PositionServiceClient {
+	return &positionServiceClient{cc}
+}
+
+func (c *positionServiceClient) GetPosition(ctx context.Context, in *GetPositionRequest, opts ...grpc.CallOption) (*GetPositionResponse, error) {
+	out := new(GetPositionResponse)
+	err := c.cc.Invoke(ctx, "/positionservice.PositionService/GetPosition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *positionServiceClient) ListPositions(ctx context.Context, in *ListPositionsRequest, opts ...grpc.CallOption) (*ListPositionsResponse, error) {
+	out := new(ListPositionsResponse)
+	err := c.cc.Invoke(ctx, "/positionservice.PositionService/ListPositions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *positionServiceClient) GetAllPositions(ctx context.Context, in *GetAllPositionsRequest, opts ...grpc.CallOption) (*GetAllPositionsResponse, error) {
+	out := new(GetAllPositionsResponse)
+	err := c.cc.Invoke(ctx, "/positionservice.PositionService/GetAllPositions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *positionServiceClient) SubscribePositions(ctx context.Context, in *SubscribePositionsRequest, opts ...grpc.CallOption) (PositionService_SubscribePositionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PositionService_serviceDesc.Streams[0], "/positionservice.PositionService/SubscribePositions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &positionServiceSubscribePositionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PositionService_SubscribePositionsClient interface {
+	Recv() (*model.Position, error)
+	grpc.ClientStream
+}
+
+type positionServiceSubscribePositionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *positionServiceSubscribePositionsClient) Recv() (*model.Position, error) {
+	m := new(model.Position)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *positionServiceClient) GetPositionHistory(ctx context.Context, in *GetPositionHistoryRequest, opts ...grpc.CallOption) (*GetPositionHistoryResponse, error) {
+	out := new(GetPositionHistoryResponse)
+	err := c.cc.Invoke(ctx, "/positionservice.PositionService/GetPositionHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *positionServiceClient) GetPortfolioStats(ctx context.Context, in *GetPortfolioStatsRequest, opts ...grpc.CallOption) (*GetPortfolioStatsResponse, error) {
+	out := new(GetPortfolioStatsResponse)
+	err := c.cc.Invoke(ctx, "/positionservice.PositionService/GetPortfolioStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PositionServiceServer is the server API for PositionService service.
+type PositionServiceServer interface {
+	// Get a user's position in a specific asset
+	GetPosition(context.Context, *GetPositionRequest) (*GetPositionResponse, error)
+	// List all positions for a user (their portfolio)
+	ListPositions(context.Context, *ListPositionsRequest) (*ListPositionsResponse, error)
+	// (Admin) List all positions for all users
+	GetAllPositions(context.Context, *GetAllPositionsRequest) (*GetAllPositionsResponse, error)
+	// Stream real-time position updates for a user
+	SubscribePositions(*SubscribePositionsRequest, PositionService_SubscribePositionsServer) error
+	// Get the history of a position (for audit/compliance)
+	GetPositionHistory(context.Context, *GetPositionHistoryRequest) (*GetPositionHistoryResponse, error)
+	// Get aggregated portfolio stats for a user
+	GetPortfolioStats(context.Context, *GetPortfolioStatsRequest) (*GetPortfolioStatsResponse, error)
+}
+
+// UnimplementedPositionServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPositionServiceServer struct {
+}
+
+func (*UnimplementedPositionServiceServer) GetPosition(ctx context.Context, req *GetPositionRequest) (*GetPositionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPosition not implemented")
+}
+func (*UnimplementedPositionServiceServer) ListPositions(ctx context.Context, req *ListPositionsRequest) (*ListPositionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPositions not implemented")
+}
+func (*UnimplementedPositionServiceServer) GetAllPositions(ctx context.Context, req *GetAllPositionsRequest) (*GetAllPositionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllPositions not implemented")
+}
+func (*UnimplementedPositionServiceServer) SubscribePositions(req *SubscribePositionsRequest, srv PositionService_SubscribePositionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribePositions not implemented")
+}
+func (*UnimplementedPositionServiceServer) GetPositionHistory(ctx context.Context, req *GetPositionHistoryRequest) (*GetPositionHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPositionHistory not implemented")
+}
+func (*UnimplementedPositionServiceServer) GetPortfolioStats(ctx context.Context, req *GetPortfolioStatsRequest) (*GetPortfolioStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortfolioStats not implemented")
+}
+
+func RegisterPositionServiceServer(s *grpc.Server, srv PositionServiceServer) {
+	s.RegisterService(&_PositionService_serviceDesc, srv)
+}
+
+func _PositionService_GetPosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PositionServiceServer).GetPosition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/positionservice.PositionService/GetPosition",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PositionServiceServer).GetPosition(ctx, req.(*GetPositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PositionService_ListPositions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPositionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PositionServiceServer).ListPositions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/positionservice.PositionService/ListPositions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PositionServiceServer).ListPositions(ctx, req.(*ListPositionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PositionService_GetAllPositions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllPositionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PositionServiceServer).GetAllPositions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/positionservice.PositionService/GetAllPositions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PositionServiceServer).GetAllPositions(ctx, req.(*GetAllPositionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PositionService_SubscribePositions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribePositionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PositionServiceServer).SubscribePositions(m, &positionServiceSubscribePositionsServer{stream})
+}
+
+type PositionService_SubscribePositionsServer interface {
+	Send(*model.Position) error
+	grpc.ServerStream
+}
+
+type positionServiceSubscribePositionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *positionServiceSubscribePositionsServer) Send(m *model.Position) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PositionService_GetPositionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPositionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PositionServiceServer).GetPositionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/positionservice.PositionService/GetPositionHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PositionServiceServer).GetPositionHistory(ctx, req.(*GetPositionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PositionService_GetPortfolioStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPortfolioStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PositionServiceServer).GetPortfolioStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/positionservice.PositionService/GetPortfolioStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PositionServiceServer).GetPortfolioStats(ctx, req.(*GetPortfolioStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PositionService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "positionservice.PositionService",
+	HandlerType: (*PositionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPosition",
+			Handler:    _PositionService_GetPosition_Handler,
+		},
+		{
+			MethodName: "ListPositions",
+			Handler:    _PositionService_ListPositions_Handler,
+		},
+		{
+			MethodName: "GetAllPositions",
+			Handler:    _PositionService_GetAllPositions_Handler,
+		},
+		{
+			MethodName: "GetPositionHistory",
+			Handler:    _PositionService_GetPositionHistory_Handler,
+		},
+		{
+			MethodName: "GetPortfolioStats",
+			Handler:    _PositionService_GetPortfolioStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribePositions",
+			Handler:       _PositionService_SubscribePositions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "position_service.proto",
+}
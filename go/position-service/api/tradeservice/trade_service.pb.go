@@ -0,0 +1,486 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: trade_service.proto
+
+package tradeservice
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// A trade is a single execution of an order, a trade with a negative quantity reverses a previous trade of the order
+// that has been cancelled or corrected by the execution venue.
+type Trade struct {
+	Id                   string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId              string           `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	OrderVersion         int32            `protobuf:"varint,3,opt,name=orderVersion,proto3" json:"orderVersion,omitempty"`
+	ExecId               string           `protobuf:"bytes,4,opt,name=execId,proto3" json:"execId,omitempty"`
+	Side                 model.Side       `protobuf:"varint,5,opt,name=side,proto3,enum=model.Side" json:"side,omitempty"`
+	ListingId            int32            `protobuf:"varint,6,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	Price                *model.Decimal64 `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             *model.Decimal64 `protobuf:"bytes,8,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	OriginatorId         string           `protobuf:"bytes,9,opt,name=originatorId,proto3" json:"originatorId,omitempty"`
+	OriginatorRef        string           `protobuf:"bytes,10,opt,name=originatorRef,proto3" json:"originatorRef,omitempty"`
+	RootOriginatorId     string           `protobuf:"bytes,11,opt,name=rootOriginatorId,proto3" json:"rootOriginatorId,omitempty"`
+	RootOriginatorRef    string           `protobuf:"bytes,12,opt,name=rootOriginatorRef,proto3" json:"rootOriginatorRef,omitempty"`
+	OwnerId              string           `protobuf:"bytes,13,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	Destination          string           `protobuf:"bytes,14,opt,name=destination,proto3" json:"destination,omitempty"`
+	TradeTime            *model.Timestamp `protobuf:"bytes,15,opt,name=tradeTime,proto3" json:"tradeTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Trade) Reset()         { *m = Trade{} }
+func (m *Trade) String() string { return proto.CompactTextString(m) }
+func (*Trade) ProtoMessage()    {}
+func (*Trade) Descriptor() ([]byte, []int) {
+	return fileDescriptor_189c1b66dd05fd4b, []int{0}
+}
+
+func (m *Trade) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Trade.Unmarshal(m, b)
+}
+func (m *Trade) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Trade.Marshal(b, m, deterministic)
+}
+func (m *Trade) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Trade.Merge(m, src)
+}
+func (m *Trade) XXX_Size() int {
+	return xxx_messageInfo_Trade.Size(m)
+}
+func (m *Trade) XXX_DiscardUnknown() {
+	xxx_messageInfo_Trade.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Trade proto.InternalMessageInfo
+
+func (m *Trade) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Trade) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *Trade) GetOrderVersion() int32 {
+	if m != nil {
+		return m.OrderVersion
+	}
+	return 0
+}
+
+func (m *Trade) GetExecId() string {
+	if m != nil {
+		return m.ExecId
+	}
+	return ""
+}
+
+func (m *Trade) GetSide() model.Side {
+	if m != nil {
+		return m.Side
+	}
+	return model.Side_BUY
+}
+
+func (m *Trade) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func (m *Trade) GetPrice() *model.Decimal64 {
+	if m != nil {
+		return m.Price
+	}
+	return nil
+}
+
+func (m *Trade) GetQuantity() *model.Decimal64 {
+	if m != nil {
+		return m.Quantity
+	}
+	return nil
+}
+
+func (m *Trade) GetOriginatorId() string {
+	if m != nil {
+		return m.OriginatorId
+	}
+	return ""
+}
+
+func (m *Trade) GetOriginatorRef() string {
+	if m != nil {
+		return m.OriginatorRef
+	}
+	return ""
+}
+
+func (m *Trade) GetRootOriginatorId() string {
+	if m != nil {
+		return m.RootOriginatorId
+	}
+	return ""
+}
+
+func (m *Trade) GetRootOriginatorRef() string {
+	if m != nil {
+		return m.RootOriginatorRef
+	}
+	return ""
+}
+
+func (m *Trade) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *Trade) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *Trade) GetTradeTime() *model.Timestamp {
+	if m != nil {
+		return m.TradeTime
+	}
+	return nil
+}
+
+// Empty fields of the filter match all trades, from and to are an inclusive range of trade times
+type TradeFilter struct {
+	OrderId              string           `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	ListingId            int32            `protobuf:"varint,2,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	OriginatorId         string           `protobuf:"bytes,3,opt,name=originatorId,proto3" json:"originatorId,omitempty"`
+	RootOriginatorId     string           `protobuf:"bytes,4,opt,name=rootOriginatorId,proto3" json:"rootOriginatorId,omitempty"`
+	From                 *model.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *model.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *TradeFilter) Reset()         { *m = TradeFilter{} }
+func (m *TradeFilter) String() string { return proto.CompactTextString(m) }
+func (*TradeFilter) ProtoMessage()    {}
+func (*TradeFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_189c1b66dd05fd4b, []int{1}
+}
+
+func (m *TradeFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TradeFilter.Unmarshal(m, b)
+}
+func (m *TradeFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TradeFilter.Marshal(b, m, deterministic)
+}
+func (m *TradeFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TradeFilter.Merge(m, src)
+}
+func (m *TradeFilter) XXX_Size() int {
+	return xxx_messageInfo_TradeFilter.Size(m)
+}
+func (m *TradeFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_TradeFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TradeFilter proto.InternalMessageInfo
+
+func (m *TradeFilter) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *TradeFilter) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func (m *TradeFilter) GetOriginatorId() string {
+	if m != nil {
+		return m.OriginatorId
+	}
+	return ""
+}
+
+func (m *TradeFilter) GetRootOriginatorId() string {
+	if m != nil {
+		return m.RootOriginatorId
+	}
+	return ""
+}
+
+func (m *TradeFilter) GetFrom() *model.Timestamp {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *TradeFilter) GetTo() *model.Timestamp {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+type Trades struct {
+	Trades               []*Trade `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Trades) Reset()         { *m = Trades{} }
+func (m *Trades) String() string { return proto.CompactTextString(m) }
+func (*Trades) ProtoMessage()    {}
+func (*Trades) Descriptor() ([]byte, []int) {
+	return fileDescriptor_189c1b66dd05fd4b, []int{2}
+}
+
+func (m *Trades) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Trades.Unmarshal(m, b)
+}
+func (m *Trades) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Trades.Marshal(b, m, deterministic)
+}
+func (m *Trades) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Trades.Merge(m, src)
+}
+func (m *Trades) XXX_Size() int {
+	return xxx_messageInfo_Trades.Size(m)
+}
+func (m *Trades) XXX_DiscardUnknown() {
+	xxx_messageInfo_Trades.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Trades proto.InternalMessageInfo
+
+func (m *Trades) GetTrades() []*Trade {
+	if m != nil {
+		return m.Trades
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Trade)(nil), "tradeservice.Trade")
+	proto.RegisterType((*TradeFilter)(nil), "tradeservice.TradeFilter")
+	proto.RegisterType((*Trades)(nil), "tradeservice.Trades")
+}
+
+func init() { proto.RegisterFile("trade_service.proto", fileDescriptor_189c1b66dd05fd4b) }
+
+var fileDescriptor_189c1b66dd05fd4b = []byte{
+	// 470 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xed, 0x3a, 0xb6, 0x5b, 0x8f, 0xd3, 0xd0, 0x6c, 0x11, 0x5a, 0x22, 0x24, 0x2c, 0xab, 0x42,
+	0x16, 0x54, 0x16, 0x0a, 0x1f, 0x47, 0x4e, 0x88, 0x2a, 0x27, 0x24, 0x27, 0xe2, 0x8a, 0x1c, 0xef,
+	0xb4, 0x5a, 0x29, 0xf6, 0x86, 0xf5, 0x96, 0x8f, 0xbf, 0xc1, 0x85, 0x5f, 0xc7, 0x7f, 0x41, 0x1e,
+	0x1b, 0x12, 0xb7, 0x8e, 0xb8, 0xed, 0xbc, 0xf7, 0x66, 0xb4, 0xf3, 0x66, 0x06, 0xce, 0xad, 0xc9,
+	0x25, 0x7e, 0xae, 0xd1, 0x7c, 0x55, 0x05, 0xa6, 0x5b, 0xa3, 0xad, 0xe6, 0x63, 0x02, 0x3b, 0x6c,
+	0x36, 0x2d, 0xb5, 0xc4, 0x4d, 0xa1, 0xcb, 0x52, 0x57, 0xad, 0x60, 0x16, 0x6a, 0x23, 0xd1, 0xb4,
+	0x41, 0xfc, 0xd3, 0x05, 0x6f, 0xd5, 0x24, 0xf0, 0x09, 0x38, 0x4a, 0x0a, 0x16, 0xb1, 0x24, 0xc8,
+	0x1c, 0x25, 0xb9, 0x80, 0x63, 0x12, 0x2e, 0xa4, 0x70, 0x08, 0xfc, 0x1b, 0xf2, 0x18, 0xc6, 0xf4,
+	0xfc, 0x84, 0xa6, 0x56, 0xba, 0x12, 0xa3, 0x88, 0x25, 0x5e, 0xd6, 0xc3, 0xf8, 0x23, 0xf0, 0xf1,
+	0x3b, 0x16, 0x0b, 0x29, 0x5c, 0x4a, 0xee, 0x22, 0xfe, 0x14, 0xdc, 0x5a, 0x49, 0x14, 0x5e, 0xc4,
+	0x92, 0xc9, 0x3c, 0x4c, 0xe9, 0x7b, 0xe9, 0x52, 0x49, 0xcc, 0x88, 0xe0, 0x4f, 0x20, 0xd8, 0xa8,
+	0xda, 0xaa, 0xea, 0x66, 0x21, 0x85, 0x4f, 0x95, 0x77, 0x00, 0x7f, 0x06, 0xde, 0xd6, 0xa8, 0x02,
+	0xc5, 0x71, 0xc4, 0x92, 0x70, 0x7e, 0xd6, 0xe5, 0xbf, 0xc7, 0x42, 0x95, 0xf9, 0xe6, 0xed, 0xeb,
+	0xac, 0xa5, 0xf9, 0x25, 0x9c, 0x7c, 0xb9, 0xcd, 0x2b, 0xab, 0xec, 0x0f, 0x71, 0x72, 0x40, 0xfa,
+	0x4f, 0xd1, 0x36, 0xa4, 0x6e, 0x54, 0x95, 0x5b, 0xdd, 0xf4, 0x1b, 0xd0, 0x97, 0x7b, 0x18, 0xbf,
+	0x80, 0xd3, 0x5d, 0x9c, 0xe1, 0xb5, 0x00, 0x12, 0xf5, 0x41, 0xfe, 0x1c, 0xce, 0x8c, 0xd6, 0xf6,
+	0xe3, 0x7e, 0xb5, 0x90, 0x84, 0xf7, 0x70, 0x7e, 0x09, 0xd3, 0x3e, 0xd6, 0x54, 0x1d, 0x93, 0xf8,
+	0x3e, 0x41, 0xe3, 0xf8, 0x56, 0xd1, 0x38, 0x4e, 0xbb, 0x71, 0xb4, 0x21, 0x8f, 0x20, 0x94, 0xd8,
+	0x18, 0x94, 0xdb, 0x66, 0x1a, 0x13, 0x62, 0xf7, 0x21, 0x9e, 0x42, 0x40, 0x4b, 0xb1, 0x52, 0x25,
+	0x8a, 0x07, 0x3d, 0x3b, 0x1a, 0xa8, 0xb6, 0x79, 0xb9, 0xcd, 0x76, 0x92, 0xf8, 0x37, 0x83, 0x90,
+	0x96, 0xe2, 0x83, 0xda, 0x58, 0x34, 0xfb, 0xab, 0xc0, 0xfa, 0xab, 0xd0, 0x9b, 0x96, 0x73, 0x77,
+	0x5a, 0x77, 0x7d, 0x1d, 0x0d, 0xf8, 0x3a, 0xe4, 0x98, 0x7b, 0xc0, 0xb1, 0x0b, 0x70, 0xaf, 0x8d,
+	0x2e, 0x85, 0x77, 0xa0, 0x05, 0x62, 0x79, 0x04, 0x8e, 0xd5, 0xc2, 0x3f, 0xa0, 0x71, 0xac, 0x8e,
+	0xdf, 0x80, 0x4f, 0xed, 0xd5, 0xfc, 0x05, 0xf8, 0xed, 0xb9, 0x08, 0x16, 0x8d, 0x92, 0x70, 0x7e,
+	0x9e, 0xee, 0x5f, 0x4f, 0x4a, 0xaa, 0xac, 0x93, 0xcc, 0x7f, 0x31, 0x18, 0x13, 0xb2, 0x6c, 0x69,
+	0xfe, 0x0e, 0x82, 0x2b, 0xb4, 0x5d, 0xa9, 0xc7, 0x03, 0xa9, 0xad, 0x7f, 0xb3, 0x87, 0x03, 0x54,
+	0x1d, 0x1f, 0xf1, 0x2b, 0x98, 0x2e, 0x6f, 0xd7, 0x75, 0x61, 0xd4, 0x1a, 0x57, 0xfa, 0xff, 0x75,
+	0x86, 0x7e, 0x17, 0x1f, 0xbd, 0x64, 0x6b, 0x9f, 0x8e, 0xf9, 0xd5, 0x9f, 0x01, 0x00, 0xc2, 0x07,
+	0x70, 0x87, 0x11, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TradeServiceClient is the client API for TradeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TradeServiceClient interface {
+	GetTrades(ctx context.Context, in *TradeFilter, opts ...grpc.CallOption) (*Trades, error)
+	// Streams the trades matching the filter that have already been captured followed by new trades as they are captured
+	SubscribeToTrades(ctx context.Context, in *TradeFilter, opts ...grpc.CallOption) (TradeService_SubscribeToTradesClient, error)
+}
+
+type tradeServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTradeServiceClient(cc *grpc.ClientConn) TradeServiceClient {
+	return &tradeServiceClient{cc}
+}
+
+func (c *tradeServiceClient) GetTrades(ctx context.Context, in *TradeFilter, opts ...grpc.CallOption) (*Trades, error) {
+	out := new(Trades)
+	err := c.cc.Invoke(ctx, "/tradeservice.TradeService/GetTrades", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradeServiceClient) SubscribeToTrades(ctx context.Context, in *TradeFilter, opts ...grpc.CallOption) (TradeService_SubscribeToTradesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TradeService_serviceDesc.Streams[0], "/tradeservice.TradeService/SubscribeToTrades", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tradeServiceSubscribeToTradesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TradeService_SubscribeToTradesClient interface {
+	Recv() (*Trade, error)
+	grpc.ClientStream
+}
+
+type tradeServiceSubscribeToTradesClient struct {
+	grpc.ClientStream
+}
+
+func (x *tradeServiceSubscribeToTradesClient) Recv() (*Trade, error) {
+	m := new(Trade)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TradeServiceServer is the server API for TradeService service.
+type TradeServiceServer interface {
+	GetTrades(context.Context, *TradeFilter) (*Trades, error)
+	// Streams the trades matching the filter that have already been captured followed by new trades as they are captured
+	SubscribeToTrades(*TradeFilter, TradeService_SubscribeToTradesServer) error
+}
+
+// UnimplementedTradeServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedTradeServiceServer struct {
+}
+
+func (*UnimplementedTradeServiceServer) GetTrades(ctx context.Context, req *TradeFilter) (*Trades, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrades not implemented")
+}
+func (*UnimplementedTradeServiceServer) SubscribeToTrades(req *TradeFilter, srv TradeService_SubscribeToTradesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToTrades not implemented")
+}
+
+func RegisterTradeServiceServer(s *grpc.Server, srv TradeServiceServer) {
+	s.RegisterService(&_TradeService_serviceDesc, srv)
+}
+
+func _TradeService_GetTrades_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TradeFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradeServiceServer).GetTrades(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tradeservice.TradeService/GetTrades",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradeServiceServer).GetTrades(ctx, req.(*TradeFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradeService_SubscribeToTrades_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TradeFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TradeServiceServer).SubscribeToTrades(m, &tradeServiceSubscribeToTradesServer{stream})
+}
+
+type TradeService_SubscribeToTradesServer interface {
+	Send(*Trade) error
+	grpc.ServerStream
+}
+
+type tradeServiceSubscribeToTradesServer struct {
+	grpc.ServerStream
+}
+
+func (x *tradeServiceSubscribeToTradesServer) Send(m *Trade) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TradeService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tradeservice.TradeService",
+	HandlerType: (*TradeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTrades",
+			Handler:    _TradeService_GetTrades_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeToTrades",
+			Handler:       _TradeService_SubscribeToTrades_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "trade_service.proto",
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/ettec/open-trading-platform/go/position-service/api/tradeservice"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+)
+
+const tradesTopic = "trades"
+
+// streamTrades returns a channel of all the trades in the trades topic from the first available offset, the channel is
+// closed if the context is cancelled or a trade cannot be read.
+func streamTrades(ctx context.Context, readerConfig kafka.ReaderConfig, bufferSize int) <-chan *tradeservice.Trade {
+	out := make(chan *tradeservice.Trade, bufferSize)
+
+	go func() {
+		defer close(out)
+		reader := kafka.NewReader(readerConfig)
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing kafka reader", "error", err)
+			}
+		}()
+
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("failed to read trade", "error", err)
+				}
+				return
+			}
+
+			trade := &tradeservice.Trade{}
+			if err = proto.Unmarshal(msg.Value, trade); err != nil {
+				slog.Error("failed to unmarshal trade", "offset", msg.Offset, "error", err)
+				return
+			}
+
+			select {
+			case out <- trade:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
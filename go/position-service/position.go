@@ -0,0 +1,100 @@
+package main
+
+import (
+	positionmodel "github.com/ettec/open-trading-platform/go/position-service/api/model"
+	"github.com/ettec/open-trading-platform/go/shared/averagecost"
+	"github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"time"
+)
+
+var zero = decimal.New(0, 0)
+
+// positionKey identifies a position, positions are kept per user, desk and listing.
+type positionKey struct {
+	userId    string
+	desk      string
+	listingId int32
+}
+
+// position is the net position of a user on a desk in a listing at its average cost.
+type position struct {
+	averagecost.Position
+	key         positionKey
+	markPrice   decimal.Decimal
+	hasMark     bool
+	lastUpdated time.Time
+}
+
+func newPosition(key positionKey) *position {
+	return &position{Position: averagecost.NewPosition(), key: key, markPrice: zero}
+}
+
+// applyTrade applies a trade of the given signed quantity, positive for a buy, at the given price.
+func (p *position) applyTrade(quantity decimal.Decimal, price decimal.Decimal, tradeTime time.Time) {
+	p.lastUpdated = tradeTime
+	p.ApplyTrade(quantity, price)
+}
+
+// reverseTrade reverses a trade of the given signed quantity and price that has been cancelled or corrected by the
+// venue.
+func (p *position) reverseTrade(quantity decimal.Decimal, price decimal.Decimal, tradeTime time.Time) {
+	p.lastUpdated = tradeTime
+	p.ReverseTrade(quantity, price)
+}
+
+// setMarkPrice sets the price the position is marked to market at, it returns true if the price has changed.
+func (p *position) setMarkPrice(price decimal.Decimal, markTime time.Time) bool {
+	if p.hasMark && p.markPrice.Equal(price) {
+		return false
+	}
+
+	p.markPrice = price
+	p.hasMark = true
+	p.lastUpdated = markTime
+	return true
+}
+
+// unrealisedPnl is the profit or loss of the open quantity at the mark price, it is zero until the position has a mark
+// price.
+func (p *position) unrealisedPnl() decimal.Decimal {
+	if !p.hasMark {
+		return zero
+	}
+
+	return p.UnrealisedPnl(p.markPrice)
+}
+
+// marketValue is the signed value of the open quantity at the mark price.
+func (p *position) marketValue() decimal.Decimal {
+	return p.markPrice.Mul(p.Quantity)
+}
+
+func (p *position) toProto() *positionmodel.Position {
+	result := &positionmodel.Position{
+		UserId:        p.key.userId,
+		TokenId:       strconv.Itoa(int(p.key.listingId)),
+		Desk:          p.key.desk,
+		ListingId:     p.key.listingId,
+		UnrealizedPnl: toFloat(p.unrealisedPnl()),
+		RealizedPnl:   toFloat(p.RealisedPnl),
+		MarkPrice:     toFloat(p.markPrice),
+		LastUpdated:   model.NewTimeStamp(p.lastUpdated),
+	}
+
+	if p.Quantity.GreaterThan(zero) {
+		result.LongQuantity = toFloat(p.Quantity)
+		result.AverageLongPrice = toFloat(p.AvgPrice)
+	} else if p.Quantity.LessThan(zero) {
+		result.ShortQuantity = toFloat(p.Quantity.Neg())
+		result.AverageShortPrice = toFloat(p.AvgPrice)
+	}
+
+	return result
+}
+
+func toFloat(d decimal.Decimal) float64 {
+	f, _ := d.Float64()
+	return f
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testTrade struct {
+	quantity int64
+	price    int64
+}
+
+func TestApplyTrade(t *testing.T) {
+	tests := []struct {
+		name            string
+		trades          []testTrade
+		wantQuantity    int64
+		wantAvgPrice    string
+		wantRealisedPnl int64
+	}{
+		{"open long", []testTrade{{10, 100}}, 10, "100", 0},
+		{"open short", []testTrade{{-10, 100}}, -10, "100", 0},
+		{"increase long", []testTrade{{10, 100}, {30, 104}}, 40, "103", 0},
+		{"increase short", []testTrade{{-10, 100}, {-10, 110}}, -20, "105", 0},
+		{"reduce long", []testTrade{{10, 100}, {-4, 110}}, 6, "100", 40},
+		{"reduce short", []testTrade{{-10, 100}, {4, 110}}, -6, "100", -40},
+		{"close long", []testTrade{{10, 100}, {-10, 90}}, 0, "0", -100},
+		{"reverse long to short", []testTrade{{10, 100}, {-15, 105}}, -5, "105", 50},
+		{"reverse short to long", []testTrade{{-10, 100}, {15, 105}}, 5, "105", -50},
+		{"reopen after close", []testTrade{{10, 100}, {-10, 101}, {5, 120}}, 5, "120", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPosition(positionKey{userId: "user1", desk: "desk1", listingId: 1})
+			for _, trade := range tt.trades {
+				p.applyTrade(decimal.New(trade.quantity, 0), decimal.New(trade.price, 0), time.Now())
+			}
+
+			assert.True(t, p.Quantity.Equal(decimal.New(tt.wantQuantity, 0)), "quantity %v", p.Quantity)
+			assert.Equal(t, tt.wantAvgPrice, p.AvgPrice.String())
+			assert.True(t, p.RealisedPnl.Equal(decimal.New(tt.wantRealisedPnl, 0)), "realised pnl %v", p.RealisedPnl)
+		})
+	}
+}
+
+func TestUnrealisedPnl(t *testing.T) {
+	p := newPosition(positionKey{userId: "user1", desk: "desk1", listingId: 1})
+	p.applyTrade(decimal.New(-10, 0), decimal.New(100, 0), time.Now())
+
+	assert.True(t, p.unrealisedPnl().Equal(zero), "no mark price")
+
+	assert.True(t, p.setMarkPrice(decimal.New(95, 0), time.Now()))
+	assert.False(t, p.setMarkPrice(decimal.New(95, 0), time.Now()))
+	assert.True(t, p.unrealisedPnl().Equal(decimal.New(50, 0)))
+	assert.True(t, p.marketValue().Equal(decimal.New(-950, 0)))
+}
+
+func TestPositionToProto(t *testing.T) {
+	lastUpdated := time.Unix(1000, 0)
+	p := newPosition(positionKey{userId: "user1", desk: "desk1", listingId: 7})
+	p.applyTrade(decimal.New(10, 0), decimal.New(100, 0), lastUpdated)
+
+	long := p.toProto()
+	assert.Equal(t, "user1", long.UserId)
+	assert.Equal(t, "desk1", long.Desk)
+	assert.Equal(t, "7", long.TokenId)
+	assert.Equal(t, int32(7), long.ListingId)
+	assert.Equal(t, 10.0, long.LongQuantity)
+	assert.Equal(t, 100.0, long.AverageLongPrice)
+	assert.Equal(t, 0.0, long.ShortQuantity)
+	assert.Equal(t, int64(1000), long.LastUpdated.Seconds)
+
+	p.applyTrade(decimal.New(-12, 0), decimal.New(101, 0), lastUpdated)
+	short := p.toProto()
+	assert.Equal(t, 0.0, short.LongQuantity)
+	assert.Equal(t, 2.0, short.ShortQuantity)
+	assert.Equal(t, 101.0, short.AverageShortPrice)
+	assert.Equal(t, 10.0, short.RealizedPnl)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	positionmodel "github.com/ettec/open-trading-platform/go/position-service/api/model"
+	"github.com/ettec/open-trading-platform/go/position-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// positionKeeper maintains the positions of each user from the trades of their orders and marks the positions to
+// market from the quotes of the positions' listings.  Only the trades of root orders are applied, the trades of the
+// child orders of a strategy are also trades of the strategy's parent order.
+type positionKeeper struct {
+	mutex              sync.Mutex
+	positions          map[positionKey]*position
+	listingPositions   map[int32][]*position
+	history            map[positionKey][]*positionmodel.Position
+	subscriptions      map[*positionSubscription]bool
+	subscribeToQuotes  func(listingId int32) error
+	subscribedListings map[int32]bool
+}
+
+// positionSubscription holds the latest state of the user's positions that have changed since they were last taken by
+// the subscriber, so a subscriber that falls behind receives only the latest state of each position.
+type positionSubscription struct {
+	userId  string
+	desk    string
+	pending map[positionKey]*positionmodel.Position
+	changed chan struct{}
+}
+
+func newPositionKeeper(subscribeToQuotes func(listingId int32) error) *positionKeeper {
+	return &positionKeeper{
+		positions:          map[positionKey]*position{},
+		listingPositions:   map[int32][]*position{},
+		history:            map[positionKey][]*positionmodel.Position{},
+		subscriptions:      map[*positionSubscription]bool{},
+		subscribeToQuotes:  subscribeToQuotes,
+		subscribedListings: map[int32]bool{},
+	}
+}
+
+// onTrade applies the trade to the position of the trade's user, desk and listing.
+func (k *positionKeeper) onTrade(trade *tradeservice.Trade) {
+	if trade.OriginatorId != trade.RootOriginatorId || trade.OriginatorRef != trade.RootOriginatorRef {
+		return
+	}
+
+	// A trade of negative quantity reverses a cancelled or corrected trade of the order's side
+	quantity := trade.Quantity.AsDecimal().Abs()
+	if trade.Side == model.Side_SELL {
+		quantity = quantity.Neg()
+	}
+	reversal := trade.Quantity.AsDecimal().IsNegative()
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	key := positionKey{userId: trade.RootOriginatorRef, desk: trade.RootOriginatorId, listingId: trade.ListingId}
+	p, exists := k.positions[key]
+	if !exists {
+		p = newPosition(key)
+		k.positions[key] = p
+		k.listingPositions[key.listingId] = append(k.listingPositions[key.listingId], p)
+		k.subscribeToListing(key.listingId)
+	}
+
+	if reversal {
+		p.reverseTrade(quantity, trade.Price.AsDecimal(), tradeTime(trade))
+	} else {
+		p.applyTrade(quantity, trade.Price.AsDecimal(), tradeTime(trade))
+	}
+
+	snapshot := p.toProto()
+	k.history[key] = append(k.history[key], snapshot)
+	k.publish(key, snapshot)
+}
+
+func (k *positionKeeper) subscribeToListing(listingId int32) {
+	if k.subscribedListings[listingId] {
+		return
+	}
+
+	if err := k.subscribeToQuotes(listingId); err != nil {
+		slog.Error("failed to subscribe to quotes, positions in the listing will not be marked to market",
+			"listingId", listingId, "error", err)
+		return
+	}
+
+	k.subscribedListings[listingId] = true
+}
+
+// onQuote marks the positions in the quote's listing to market at the quote's last price, or if the listing has not
+// traded, at the mid-price of the best bid and offer.
+func (k *positionKeeper) onQuote(quote *model.ClobQuote) {
+	markPrice, ok := getMarkPrice(quote)
+	if !ok {
+		return
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	now := time.Now()
+	for _, p := range k.listingPositions[quote.ListingId] {
+		if p.setMarkPrice(markPrice, now) {
+			k.publish(p.key, p.toProto())
+		}
+	}
+}
+
+func getMarkPrice(quote *model.ClobQuote) (decimal.Decimal, bool) {
+	if quote.StreamInterrupted {
+		return zero, false
+	}
+
+	if quote.LastPrice != nil {
+		return quote.LastPrice.AsDecimal(), true
+	}
+
+	if len(quote.Bids) > 0 && len(quote.Offers) > 0 {
+		return quote.Bids[0].Price.AsDecimal().Add(quote.Offers[0].Price.AsDecimal()).Div(decimal.New(2, 0)), true
+	}
+
+	return zero, false
+}
+
+func (k *positionKeeper) publish(key positionKey, snapshot *positionmodel.Position) {
+	for subscription := range k.subscriptions {
+		if subscription.matches(key) {
+			subscription.pending[key] = snapshot
+			select {
+			case subscription.changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (s *positionSubscription) matches(key positionKey) bool {
+	return key.userId == s.userId && (s.desk == "" || key.desk == s.desk)
+}
+
+// getPosition returns the user's position in the listing, the desk may be omitted if the user holds the listing on
+// only one desk.
+func (k *positionKeeper) getPosition(userId string, desk string, listingId int32) (*positionmodel.Position, bool, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	key, exists, err := k.findPositionKey(userId, desk, listingId)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+
+	return k.positions[key].toProto(), true, nil
+}
+
+// getPositionHistory returns the state of the user's position in the listing after each of its trades.
+func (k *positionKeeper) getPositionHistory(userId string, desk string, listingId int32) ([]*positionmodel.Position, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	key, exists, err := k.findPositionKey(userId, desk, listingId)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	return append([]*positionmodel.Position{}, k.history[key]...), nil
+}
+
+func (k *positionKeeper) findPositionKey(userId string, desk string, listingId int32) (positionKey, bool, error) {
+	if desk != "" {
+		key := positionKey{userId: userId, desk: desk, listingId: listingId}
+		_, exists := k.positions[key]
+		return key, exists, nil
+	}
+
+	var keys []positionKey
+	for key := range k.positions {
+		if key.userId == userId && key.listingId == listingId {
+			keys = append(keys, key)
+		}
+	}
+
+	switch len(keys) {
+	case 0:
+		return positionKey{}, false, nil
+	case 1:
+		return keys[0], true, nil
+	default:
+		return positionKey{}, false, fmt.Errorf("user %v holds listing %v on more than one desk, the desk must be specified",
+			userId, listingId)
+	}
+}
+
+// getPositions returns the positions of the user on the desk, or on all of the user's desks if the desk is empty.
+func (k *positionKeeper) getPositions(userId string, desk string) []*positionmodel.Position {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	subscription := &positionSubscription{userId: userId, desk: desk}
+	return k.getMatchingPositions(subscription.matches)
+}
+
+func (k *positionKeeper) getAllPositions() []*positionmodel.Position {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	return k.getMatchingPositions(func(key positionKey) bool { return true })
+}
+
+// getMatchingPositions returns the matching positions ordered by user, desk and listing.
+func (k *positionKeeper) getMatchingPositions(matches func(key positionKey) bool) []*positionmodel.Position {
+	var keys []positionKey
+	for key := range k.positions {
+		if matches(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].userId != keys[j].userId {
+			return keys[i].userId < keys[j].userId
+		}
+		if keys[i].desk != keys[j].desk {
+			return keys[i].desk < keys[j].desk
+		}
+		return keys[i].listingId < keys[j].listingId
+	})
+
+	result := make([]*positionmodel.Position, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, k.positions[key].toProto())
+	}
+
+	return result
+}
+
+// portfolioStats are the totals across a user's positions.
+type portfolioStats struct {
+	totalValue         float64
+	totalUnrealisedPnl float64
+	totalRealisedPnl   float64
+}
+
+func (k *positionKeeper) getPortfolioStats(userId string, desk string) portfolioStats {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	subscription := &positionSubscription{userId: userId, desk: desk}
+	value, unrealisedPnl, realisedPnl := zero, zero, zero
+	for key, p := range k.positions {
+		if subscription.matches(key) {
+			value = value.Add(p.marketValue())
+			unrealisedPnl = unrealisedPnl.Add(p.unrealisedPnl())
+			realisedPnl = realisedPnl.Add(p.RealisedPnl)
+		}
+	}
+
+	return portfolioStats{
+		totalValue:         toFloat(value),
+		totalUnrealisedPnl: toFloat(unrealisedPnl),
+		totalRealisedPnl:   toFloat(realisedPnl),
+	}
+}
+
+// subscribe returns the current positions of the user on the desk, or on all the user's desks if the desk is empty,
+// and a subscription to the subsequent changes to them.
+func (k *positionKeeper) subscribe(userId string, desk string) ([]*positionmodel.Position, *positionSubscription) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	subscription := &positionSubscription{
+		userId:  userId,
+		desk:    desk,
+		pending: map[positionKey]*positionmodel.Position{},
+		changed: make(chan struct{}, 1),
+	}
+	k.subscriptions[subscription] = true
+
+	return k.getMatchingPositions(subscription.matches), subscription
+}
+
+// takeChanges returns the latest state of the positions that have changed since the subscription's changes were last
+// taken.
+func (k *positionKeeper) takeChanges(subscription *positionSubscription) []*positionmodel.Position {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	result := make([]*positionmodel.Position, 0, len(subscription.pending))
+	for key, snapshot := range subscription.pending {
+		result = append(result, snapshot)
+		delete(subscription.pending, key)
+	}
+
+	return result
+}
+
+func (k *positionKeeper) unsubscribe(subscription *positionSubscription) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	delete(k.subscriptions, subscription)
+}
+
+func tradeTime(trade *tradeservice.Trade) time.Time {
+	if trade.TradeTime == nil {
+		return time.Now()
+	}
+
+	return time.Unix(trade.TradeTime.Seconds, int64(trade.TradeTime.Nanoseconds))
+}
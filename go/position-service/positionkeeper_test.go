@@ -0,0 +1,202 @@
+package main
+
+import (
+	positionmodel "github.com/ettec/open-trading-platform/go/position-service/api/model"
+	"github.com/ettec/open-trading-platform/go/position-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestKeeper() (*positionKeeper, *[]int32) {
+	var subscribed []int32
+	return newPositionKeeper(func(listingId int32) error {
+		subscribed = append(subscribed, listingId)
+		return nil
+	}), &subscribed
+}
+
+func newTestTrade(user string, desk string, listingId int32, side model.Side, quantity int, price int) *tradeservice.Trade {
+	return &tradeservice.Trade{
+		Side:              side,
+		ListingId:         listingId,
+		Price:             model.IasD(price),
+		Quantity:          model.IasD(quantity),
+		OriginatorId:      desk,
+		OriginatorRef:     user,
+		RootOriginatorId:  desk,
+		RootOriginatorRef: user,
+		TradeTime:         &model.Timestamp{Seconds: 1000},
+	}
+}
+
+func TestTradesAreAppliedToUserDeskAndListingPositions(t *testing.T) {
+	keeper, subscribed := newTestKeeper()
+
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_SELL, 4, 110))
+	keeper.onTrade(newTestTrade("user1", "desk2", 1, model.Side_SELL, 5, 100))
+	keeper.onTrade(newTestTrade("user1", "desk1", 2, model.Side_BUY, 1, 50))
+	keeper.onTrade(newTestTrade("user2", "desk1", 1, model.Side_BUY, 1, 100))
+
+	assert.Equal(t, []int32{1, 2}, *subscribed)
+
+	positions := keeper.getPositions("user1", "")
+	assert.Len(t, positions, 3)
+	assert.Equal(t, "desk1", positions[0].Desk)
+	assert.Equal(t, int32(1), positions[0].ListingId)
+	assert.Equal(t, 6.0, positions[0].LongQuantity)
+	assert.Equal(t, 40.0, positions[0].RealizedPnl)
+	assert.Equal(t, "desk1", positions[1].Desk)
+	assert.Equal(t, int32(2), positions[1].ListingId)
+	assert.Equal(t, "desk2", positions[2].Desk)
+	assert.Equal(t, 5.0, positions[2].ShortQuantity)
+
+	assert.Len(t, keeper.getPositions("user1", "desk2"), 1)
+	assert.Len(t, keeper.getAllPositions(), 4)
+}
+
+func TestTradesOfChildOrdersAreNotApplied(t *testing.T) {
+	keeper, _ := newTestKeeper()
+
+	childTrade := newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100)
+	childTrade.OriginatorId = "vwap-strategy"
+	childTrade.OriginatorRef = "parentOrder1"
+	keeper.onTrade(childTrade)
+
+	assert.Empty(t, keeper.getAllPositions())
+}
+
+func TestCancelledTradeReversesPosition(t *testing.T) {
+	keeper, _ := newTestKeeper()
+
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, -10, 100))
+
+	position, exists, err := keeper.getPosition("user1", "desk1", 1)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 0.0, position.LongQuantity)
+	assert.Equal(t, 0.0, position.ShortQuantity)
+	assert.Equal(t, 0.0, position.RealizedPnl)
+}
+
+func TestCancelledTradeIsTakenOutOfPositionWithOtherTrades(t *testing.T) {
+	keeper, _ := newTestKeeper()
+
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 110))
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, -10, 100))
+
+	position, _, err := keeper.getPosition("user1", "desk1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, position.LongQuantity)
+	assert.Equal(t, 110.0, position.AverageLongPrice)
+	assert.Equal(t, 0.0, position.RealizedPnl)
+
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_SELL, 20, 100))
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_SELL, 10, 90))
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_SELL, -10, 90))
+
+	position, _, err = keeper.getPosition("user1", "desk1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, position.ShortQuantity)
+	assert.Equal(t, 100.0, position.AverageShortPrice)
+	assert.Equal(t, -100.0, position.RealizedPnl)
+}
+
+func TestGetPositionWithoutDesk(t *testing.T) {
+	keeper, _ := newTestKeeper()
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+
+	position, exists, err := keeper.getPosition("user1", "", 1)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "desk1", position.Desk)
+
+	_, exists, err = keeper.getPosition("user1", "", 2)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	keeper.onTrade(newTestTrade("user1", "desk2", 1, model.Side_BUY, 10, 100))
+	_, _, err = keeper.getPosition("user1", "", 1)
+	assert.Error(t, err)
+
+	_, exists, err = keeper.getPosition("user1", "desk2", 1)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestPositionHistory(t *testing.T) {
+	keeper, _ := newTestKeeper()
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	keeper.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(105)})
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_SELL, 4, 110))
+
+	history, err := keeper.getPositionHistory("user1", "desk1", 1)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, 10.0, history[0].LongQuantity)
+	assert.Equal(t, 6.0, history[1].LongQuantity)
+}
+
+func TestPositionsAreMarkedToMarket(t *testing.T) {
+	keeper, _ := newTestKeeper()
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	keeper.onTrade(newTestTrade("user1", "desk2", 1, model.Side_SELL, 5, 100))
+	keeper.onTrade(newTestTrade("user1", "desk2", 2, model.Side_SELL, 5, 100))
+
+	keeper.onQuote(&model.ClobQuote{ListingId: 1,
+		Bids:   []*model.ClobLine{{Price: model.IasD(101), Size: model.IasD(1)}},
+		Offers: []*model.ClobLine{{Price: model.IasD(103), Size: model.IasD(1)}}})
+
+	positions := keeper.getPositions("user1", "")
+	assert.Equal(t, 102.0, positions[0].MarkPrice)
+	assert.Equal(t, 20.0, positions[0].UnrealizedPnl)
+	assert.Equal(t, 102.0, positions[1].MarkPrice)
+	assert.Equal(t, -10.0, positions[1].UnrealizedPnl)
+	assert.Equal(t, 0.0, positions[2].MarkPrice)
+
+	keeper.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(99)})
+	keeper.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(200), StreamInterrupted: true})
+
+	stats := keeper.getPortfolioStats("user1", "")
+	assert.Equal(t, 495.0, stats.totalValue)
+	assert.Equal(t, -5.0, stats.totalUnrealisedPnl)
+	assert.Equal(t, 0.0, stats.totalRealisedPnl)
+
+	stats = keeper.getPortfolioStats("user1", "desk1")
+	assert.Equal(t, 990.0, stats.totalValue)
+}
+
+func TestSubscriptionReceivesLatestStateOfChangedPositions(t *testing.T) {
+	keeper, _ := newTestKeeper()
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	keeper.onTrade(newTestTrade("user2", "desk1", 1, model.Side_BUY, 10, 100))
+
+	initial, subscription := keeper.subscribe("user1", "")
+	assert.Len(t, initial, 1)
+
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	keeper.onQuote(&model.ClobQuote{ListingId: 1, LastPrice: model.IasD(101)})
+	keeper.onTrade(newTestTrade("user1", "desk1", 2, model.Side_BUY, 1, 100))
+
+	<-subscription.changed
+	changes := keeper.takeChanges(subscription)
+	assert.Len(t, changes, 2)
+
+	byListing := map[int32]*positionmodel.Position{}
+	for _, change := range changes {
+		assert.Equal(t, "user1", change.UserId)
+		byListing[change.ListingId] = change
+	}
+	assert.Equal(t, 20.0, byListing[1].LongQuantity)
+	assert.Equal(t, 101.0, byListing[1].MarkPrice)
+	assert.Equal(t, 1.0, byListing[2].LongQuantity)
+
+	assert.Empty(t, keeper.takeChanges(subscription))
+
+	keeper.unsubscribe(subscription)
+	keeper.onTrade(newTestTrade("user1", "desk1", 1, model.Side_BUY, 10, 100))
+	assert.Empty(t, keeper.takeChanges(subscription))
+}
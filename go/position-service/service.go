@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/position-service/api/positionservice"
+	"github.com/ettec/open-trading-platform/go/position-service/api/tradeservice"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/k8s"
+	"github.com/ettec/otp-common/marketdata"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/orderstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const maxConnectRetry = 2 * time.Second
+
+type service struct {
+	keeper *positionKeeper
+}
+
+func newService(keeper *positionKeeper) *service {
+	return &service{keeper: keeper}
+}
+
+func parseListingId(tokenId string) (int32, error) {
+	listingId, err := strconv.ParseInt(tokenId, 10, 32)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "token id %q is not a listing id", tokenId)
+	}
+
+	return int32(listingId), nil
+}
+
+func (s *service) GetPosition(_ context.Context, request *api.GetPositionRequest) (*api.GetPositionResponse, error) {
+	listingId, err := parseListingId(request.TokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	position, exists, err := s.keeper.getPosition(request.UserId, request.Desk, listingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "no position found for user %v in listing %v", request.UserId, listingId)
+	}
+
+	return &api.GetPositionResponse{Position: position}, nil
+}
+
+func (s *service) ListPositions(_ context.Context, request *api.ListPositionsRequest) (*api.ListPositionsResponse, error) {
+	return &api.ListPositionsResponse{Positions: s.keeper.getPositions(request.UserId, request.Desk)}, nil
+}
+
+func (s *service) GetAllPositions(context.Context, *api.GetAllPositionsRequest) (*api.GetAllPositionsResponse, error) {
+	return &api.GetAllPositionsResponse{Positions: s.keeper.getAllPositions()}, nil
+}
+
+func (s *service) SubscribePositions(request *api.SubscribePositionsRequest, stream api.PositionService_SubscribePositionsServer) error {
+	slog.Info("subscribing to positions", "userId", request.UserId, "desk", request.Desk)
+
+	positions, subscription := s.keeper.subscribe(request.UserId, request.Desk)
+	defer func() {
+		s.keeper.unsubscribe(subscription)
+		slog.Info("unsubscribed from positions", "userId", request.UserId, "desk", request.Desk)
+	}()
+
+	for {
+		for _, position := range positions {
+			if err := stream.Send(position); err != nil {
+				return fmt.Errorf("failed to send position: %w", err)
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-subscription.changed:
+			positions = s.keeper.takeChanges(subscription)
+		}
+	}
+}
+
+func (s *service) GetPositionHistory(_ context.Context, request *api.GetPositionHistoryRequest) (*api.GetPositionHistoryResponse, error) {
+	listingId, err := parseListingId(request.TokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.keeper.getPositionHistory(request.UserId, request.Desk, listingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &api.GetPositionHistoryResponse{Positions: positions}, nil
+}
+
+// GetPortfolioStats returns the totals of the user's positions, margin is not tracked by the position service and is
+// reported as zero.
+func (s *service) GetPortfolioStats(_ context.Context, request *api.GetPortfolioStatsRequest) (*api.GetPortfolioStatsResponse, error) {
+	stats := s.keeper.getPortfolioStats(request.UserId, request.Desk)
+
+	return &api.GetPortfolioStatsResponse{
+		TotalValue:         stats.totalValue,
+		TotalUnrealizedPnl: stats.totalUnrealisedPnl,
+		TotalRealizedPnl:   stats.totalRealisedPnl,
+	}, nil
+}
+
+// run applies the trades and quotes to the positions until the context is cancelled or either channel is closed.
+func run(ctx context.Context, keeper *positionKeeper, trades <-chan *tradeservice.Trade, quotes <-chan *model.ClobQuote) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case trade, ok := <-trades:
+			if !ok {
+				return errors.New("trades channel closed")
+			}
+			keeper.onTrade(trade)
+		case quote, ok := <-quotes:
+			if !ok {
+				return errors.New("quote channel closed")
+			}
+			keeper.onQuote(quote)
+		}
+	}
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id, err := os.Hostname()
+	if err != nil {
+		log.Panicf("failed to get hostname: %v", err)
+	}
+
+	mdsAddress := bootstrap.GetOptionalEnvVar("MARKET_DATA_SERVICE_ADDRESS", "")
+	if mdsAddress == "" {
+		mdsAddress, err = k8s.GetServiceAddress("market-data-service")
+		if err != nil {
+			log.Panicf("failed to get market data service address: %v", err)
+		}
+	}
+
+	quoteStream, err := marketdata.NewQuoteStreamFromMarketDataService(ctx, id, mdsAddress, maxConnectRetry,
+		bootstrap.GetOptionalIntEnvVar("QUOTE_BUFFER_SIZE", 1000))
+	if err != nil {
+		log.Panicf("failed to create quote stream: %v", err)
+	}
+	defer quoteStream.Close()
+
+	keeper := newPositionKeeper(quoteStream.Subscribe)
+
+	port := "50551"
+	slog.Info("Starting position service", "port", port)
+	listener, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	s := grpc.NewServer()
+	api.RegisterPositionServiceServer(s, newService(keeper))
+	reflection.Register(s)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+		s.GracefulStop()
+	}()
+
+	go func() {
+		trades := streamTrades(ctx, orderstore.DefaultReaderConfig(tradesTopic, kafkaBrokers),
+			bootstrap.GetOptionalIntEnvVar("TRADES_BUFFER_SIZE", 1000))
+		if err := run(ctx, keeper, trades, quoteStream.Chan()); err != nil {
+			log.Panicf("position keeping failed: %v", err)
+		}
+	}()
+
+	if err := s.Serve(listener); err != nil {
+		log.Panicf("Error while serving : %v", err)
+	}
+}
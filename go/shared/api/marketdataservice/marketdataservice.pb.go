@@ -0,0 +1,377 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: marketdataservice.proto
+
+package api
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type MdsConnectRequest struct {
+	SubscriberId         string   `protobuf:"bytes,1,opt,name=subscriberId,proto3" json:"subscriberId,omitempty"`
+	MaxQuotePerSecond    int32    `protobuf:"varint,2,opt,name=maxQuotePerSecond,proto3" json:"maxQuotePerSecond,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MdsConnectRequest) Reset()         { *m = MdsConnectRequest{} }
+func (m *MdsConnectRequest) String() string { return proto.CompactTextString(m) }
+func (*MdsConnectRequest) ProtoMessage()    {}
+func (*MdsConnectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7024fcdb73f982f7, []int{0}
+}
+
+func (m *MdsConnectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MdsConnectRequest.Unmarshal(m, b)
+}
+func (m *MdsConnectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MdsConnectRequest.Marshal(b, m, deterministic)
+}
+func (m *MdsConnectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MdsConnectRequest.Merge(m, src)
+}
+func (m *MdsConnectRequest) XXX_Size() int {
+	return xxx_messageInfo_MdsConnectRequest.Size(m)
+}
+func (m *MdsConnectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MdsConnectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MdsConnectRequest proto.InternalMessageInfo
+
+func (m *MdsConnectRequest) GetSubscriberId() string {
+	if m != nil {
+		return m.SubscriberId
+	}
+	return ""
+}
+
+func (m *MdsConnectRequest) GetMaxQuotePerSecond() int32 {
+	if m != nil {
+		return m.MaxQuotePerSecond
+	}
+	return 0
+}
+
+type MdsSubscribeRequest struct {
+	SubscriberId         string   `protobuf:"bytes,1,opt,name=subscriberId,proto3" json:"subscriberId,omitempty"`
+	ListingId            int32    `protobuf:"varint,2,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MdsSubscribeRequest) Reset()         { *m = MdsSubscribeRequest{} }
+func (m *MdsSubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*MdsSubscribeRequest) ProtoMessage()    {}
+func (*MdsSubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7024fcdb73f982f7, []int{1}
+}
+
+func (m *MdsSubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MdsSubscribeRequest.Unmarshal(m, b)
+}
+func (m *MdsSubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MdsSubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *MdsSubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MdsSubscribeRequest.Merge(m, src)
+}
+func (m *MdsSubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_MdsSubscribeRequest.Size(m)
+}
+func (m *MdsSubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MdsSubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MdsSubscribeRequest proto.InternalMessageInfo
+
+func (m *MdsSubscribeRequest) GetSubscriberId() string {
+	if m != nil {
+		return m.SubscriberId
+	}
+	return ""
+}
+
+func (m *MdsSubscribeRequest) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+type MdsUnsubscribeRequest struct {
+	SubscriberId         string   `protobuf:"bytes,1,opt,name=subscriberId,proto3" json:"subscriberId,omitempty"`
+	ListingId            int32    `protobuf:"varint,2,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MdsUnsubscribeRequest) Reset()         { *m = MdsUnsubscribeRequest{} }
+func (m *MdsUnsubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*MdsUnsubscribeRequest) ProtoMessage()    {}
+func (*MdsUnsubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7024fcdb73f982f7, []int{2}
+}
+
+func (m *MdsUnsubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MdsUnsubscribeRequest.Unmarshal(m, b)
+}
+func (m *MdsUnsubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MdsUnsubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *MdsUnsubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MdsUnsubscribeRequest.Merge(m, src)
+}
+func (m *MdsUnsubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_MdsUnsubscribeRequest.Size(m)
+}
+func (m *MdsUnsubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MdsUnsubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MdsUnsubscribeRequest proto.InternalMessageInfo
+
+func (m *MdsUnsubscribeRequest) GetSubscriberId() string {
+	if m != nil {
+		return m.SubscriberId
+	}
+	return ""
+}
+
+func (m *MdsUnsubscribeRequest) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*MdsConnectRequest)(nil), "marketdataservice.MdsConnectRequest")
+	proto.RegisterType((*MdsSubscribeRequest)(nil), "marketdataservice.MdsSubscribeRequest")
+	proto.RegisterType((*MdsUnsubscribeRequest)(nil), "marketdataservice.MdsUnsubscribeRequest")
+}
+
+func init() { proto.RegisterFile("marketdataservice.proto", fileDescriptor_7024fcdb73f982f7) }
+
+var fileDescriptor_7024fcdb73f982f7 = []byte{
+	// 285 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x92, 0xc1, 0x4a, 0xf4, 0x30,
+	0x10, 0xc7, 0xb7, 0xdf, 0xc7, 0x2a, 0x1d, 0x17, 0xb4, 0x11, 0x71, 0x29, 0x1e, 0x96, 0x20, 0xd2,
+	0x83, 0x14, 0xd1, 0x37, 0xb0, 0x2e, 0xb2, 0x87, 0x82, 0xb6, 0x88, 0xe8, 0x2d, 0x4d, 0x06, 0x29,
+	0x36, 0x49, 0x37, 0x49, 0x45, 0xdf, 0xd8, 0xc7, 0x10, 0xbb, 0x5d, 0x75, 0x69, 0x2f, 0x82, 0xd7,
+	0x99, 0xe4, 0x37, 0xf9, 0xcf, 0x2f, 0x70, 0x28, 0x99, 0x79, 0x46, 0x27, 0x98, 0x63, 0x16, 0xcd,
+	0x4b, 0xc9, 0x31, 0xae, 0x8d, 0x76, 0x9a, 0x04, 0xbd, 0x46, 0x18, 0x48, 0x2d, 0xb0, 0xe2, 0x5a,
+	0x4a, 0xad, 0x56, 0xa7, 0xc2, 0x5d, 0x5e, 0xe9, 0x62, 0xd9, 0x68, 0xd7, 0x5d, 0xa3, 0x08, 0x41,
+	0x2a, 0x6c, 0xa2, 0x95, 0x42, 0xee, 0x32, 0x5c, 0x36, 0x68, 0x1d, 0xa1, 0x30, 0xb1, 0x4d, 0x61,
+	0xb9, 0x29, 0x0b, 0x34, 0x0b, 0x31, 0xf5, 0x66, 0x5e, 0xe4, 0x67, 0x1b, 0x35, 0x72, 0x0a, 0x81,
+	0x64, 0xaf, 0xb7, 0x9f, 0xa8, 0x1b, 0x34, 0x39, 0x72, 0xad, 0xc4, 0xf4, 0xdf, 0xcc, 0x8b, 0xc6,
+	0x59, 0xbf, 0x41, 0xef, 0x61, 0x3f, 0x15, 0x36, 0x5f, 0x03, 0x7e, 0x33, 0xe8, 0x08, 0xfc, 0xaa,
+	0xb4, 0xae, 0x54, 0x4f, 0x8b, 0xf5, 0x80, 0xef, 0x02, 0x7d, 0x80, 0x83, 0x54, 0xd8, 0x3b, 0x65,
+	0xff, 0x1c, 0x7d, 0xfe, 0xee, 0x41, 0x90, 0xb6, 0x4b, 0xbd, 0x62, 0x8e, 0xe5, 0xab, 0xa5, 0x92,
+	0x04, 0xfc, 0xaf, 0x18, 0xe4, 0x24, 0xee, 0xeb, 0x18, 0xc8, 0x19, 0x4e, 0xe2, 0x56, 0x45, 0x3c,
+	0x97, 0xb5, 0x7b, 0xa3, 0x23, 0x72, 0x0d, 0x3b, 0x3f, 0x9e, 0x4c, 0xa2, 0x61, 0x4c, 0x3f, 0x55,
+	0x0f, 0x34, 0x87, 0xed, 0xce, 0x1d, 0x39, 0x1e, 0x86, 0x6c, 0xaa, 0x0d, 0xf7, 0x3a, 0x40, 0x52,
+	0xe9, 0xa2, 0x75, 0x44, 0x47, 0x67, 0xde, 0xe5, 0xf8, 0xf1, 0x3f, 0xab, 0xcb, 0x62, 0xab, 0xfd,
+	0x13, 0x17, 0x1f, 0x03, 0x00, 0x75, 0xf3, 0x25, 0x51, 0x65, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// MarketDataServiceClient is the client API for MarketDataService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MarketDataServiceClient interface {
+	Subscribe(ctx context.Context, in *MdsSubscribeRequest, opts ...grpc.CallOption) (*model.Empty, error)
+	Unsubscribe(ctx context.Context, in *MdsUnsubscribeRequest, opts ...grpc.CallOption) (*model.Empty, error)
+	Connect(ctx context.Context, in *MdsConnectRequest, opts ...grpc.CallOption) (MarketDataService_ConnectClient, error)
+}
+
+type marketDataServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMarketDataServiceClient(cc *grpc.ClientConn) MarketDataServiceClient {
+	return &marketDataServiceClient{cc}
+}
+
+func (c *marketDataServiceClient) Subscribe(ctx context.Context, in *MdsSubscribeRequest, opts ...grpc.CallOption) (*model.Empty, error) {
+	out := new(model.Empty)
+	err := c.cc.Invoke(ctx, "/marketdataservice.MarketDataService/Subscribe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) Unsubscribe(ctx context.Context, in *MdsUnsubscribeRequest, opts ...grpc.CallOption) (*model.Empty, error) {
+	out := new(model.Empty)
+	err := c.cc.Invoke(ctx, "/marketdataservice.MarketDataService/Unsubscribe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) Connect(ctx context.Context, in *MdsConnectRequest, opts ...grpc.CallOption) (MarketDataService_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MarketDataService_serviceDesc.Streams[0], "/marketdataservice.MarketDataService/Connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketDataServiceConnectClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketDataService_ConnectClient interface {
+	Recv() (*model.ClobQuote, error)
+	grpc.ClientStream
+}
+
+type marketDataServiceConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketDataServiceConnectClient) Recv() (*model.ClobQuote, error) {
+	m := new(model.ClobQuote)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MarketDataServiceServer is the server API for MarketDataService service.
+type MarketDataServiceServer interface {
+	Subscribe(context.Context, *MdsSubscribeRequest) (*model.Empty, error)
+	Unsubscribe(context.Context, *MdsUnsubscribeRequest) (*model.Empty, error)
+	Connect(*MdsConnectRequest, MarketDataService_ConnectServer) error
+}
+
+// UnimplementedMarketDataServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMarketDataServiceServer struct {
+}
+
+func (*UnimplementedMarketDataServiceServer) Subscribe(ctx context.Context, req *MdsSubscribeRequest) (*model.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (*UnimplementedMarketDataServiceServer) Unsubscribe(ctx context.Context, req *MdsUnsubscribeRequest) (*model.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unsubscribe not implemented")
+}
+func (*UnimplementedMarketDataServiceServer) Connect(req *MdsConnectRequest, srv MarketDataService_ConnectServer) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+
+func RegisterMarketDataServiceServer(s *grpc.Server, srv MarketDataServiceServer) {
+	s.RegisterService(&_MarketDataService_serviceDesc, srv)
+}
+
+func _MarketDataService_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MdsSubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdataservice.MarketDataService/Subscribe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).Subscribe(ctx, req.(*MdsSubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_Unsubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MdsUnsubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).Unsubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdataservice.MarketDataService/Unsubscribe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).Unsubscribe(ctx, req.(*MdsUnsubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MdsConnectRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServiceServer).Connect(m, &marketDataServiceConnectServer{stream})
+}
+
+type MarketDataService_ConnectServer interface {
+	Send(*model.ClobQuote) error
+	grpc.ServerStream
+}
+
+type marketDataServiceConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketDataServiceConnectServer) Send(m *model.ClobQuote) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _MarketDataService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "marketdataservice.MarketDataService",
+	HandlerType: (*MarketDataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Subscribe",
+			Handler:    _MarketDataService_Subscribe_Handler,
+		},
+		{
+			MethodName: "Unsubscribe",
+			Handler:    _MarketDataService_Unsubscribe_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _MarketDataService_Connect_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "marketdataservice.proto",
+}
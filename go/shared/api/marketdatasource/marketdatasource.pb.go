@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: marketdatasource.proto
+
+package marketdatasource
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type SubscribeRequest struct {
+	ListingId int32 `protobuf:"varint,1,opt,name=listingId,proto3" json:"listingId,omitempty"`
+	// When set the subscription to the listing is released
+	Unsubscribe          bool     `protobuf:"varint,2,opt,name=unsubscribe,proto3" json:"unsubscribe,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0d1a67e6fa25534, []int{0}
+}
+
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+func (m *SubscribeRequest) GetListingId() int32 {
+	if m != nil {
+		return m.ListingId
+	}
+	return 0
+}
+
+func (m *SubscribeRequest) GetUnsubscribe() bool {
+	if m != nil {
+		return m.Unsubscribe
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*SubscribeRequest)(nil), "marketdatasource.SubscribeRequest")
+}
+
+func init() { proto.RegisterFile("marketdatasource.proto", fileDescriptor_c0d1a67e6fa25534) }
+
+var fileDescriptor_c0d1a67e6fa25534 = []byte{
+	// 194 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0xcf, 0xbf, 0x6e, 0xc3, 0x20,
+	0x10, 0xc7, 0xf1, 0x52, 0xa9, 0xff, 0xe8, 0x50, 0xca, 0x50, 0x59, 0x56, 0x07, 0xcb, 0x93, 0x27,
+	0x54, 0xb5, 0x8f, 0xe0, 0x76, 0xe8, 0xd0, 0xa1, 0x78, 0xca, 0x08, 0x18, 0x45, 0x56, 0x80, 0x8b,
+	0xe1, 0x78, 0xff, 0x28, 0x24, 0x51, 0x22, 0x67, 0xfd, 0xea, 0x4e, 0xfa, 0xfc, 0xe8, 0x9b, 0x57,
+	0x71, 0x63, 0x71, 0x54, 0xa8, 0x12, 0xe4, 0x68, 0xac, 0xd8, 0x46, 0x40, 0xe0, 0x6c, 0xd9, 0xeb,
+	0x17, 0xe3, 0x40, 0xcf, 0x19, 0xf0, 0x78, 0x52, 0xbf, 0x7a, 0x18, 0xad, 0x33, 0xe0, 0x3d, 0x84,
+	0x43, 0x6a, 0x25, 0x65, 0x43, 0xd6, 0xc9, 0xc4, 0x49, 0x5b, 0x69, 0xe7, 0x6c, 0x13, 0xf2, 0x77,
+	0xfa, 0xe4, 0xa6, 0x84, 0x53, 0x58, 0xff, 0x8e, 0x15, 0x69, 0x48, 0x77, 0x27, 0xcf, 0x81, 0x37,
+	0xf4, 0x39, 0x87, 0x74, 0xfa, 0xa9, 0x6e, 0x1b, 0xd2, 0x3d, 0xca, 0xcb, 0xf4, 0xb9, 0xa2, 0xec,
+	0xaf, 0x58, 0xbe, 0x15, 0xaa, 0xa1, 0x58, 0xf8, 0x0f, 0x7d, 0xe8, 0x21, 0x04, 0x6b, 0x90, 0xb7,
+	0xe2, 0x6a, 0xc1, 0x92, 0x50, 0x33, 0x51, 0xa8, 0xa2, 0x77, 0xa0, 0xff, 0xf7, 0x0b, 0xda, 0x9b,
+	0x8e, 0x7c, 0x10, 0x7d, 0x5f, 0xd4, 0x5f, 0xbb, 0x01, 0x00, 0x87, 0x8e, 0xe1, 0x26, 0x05, 0x01,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// MarketDataSourceClient is the client API for MarketDataSource service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MarketDataSourceClient interface {
+	Connect(ctx context.Context, opts ...grpc.CallOption) (MarketDataSource_ConnectClient, error)
+}
+
+type marketDataSourceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMarketDataSourceClient(cc *grpc.ClientConn) MarketDataSourceClient {
+	return &marketDataSourceClient{cc}
+}
+
+func (c *marketDataSourceClient) Connect(ctx context.Context, opts ...grpc.CallOption) (MarketDataSource_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MarketDataSource_serviceDesc.Streams[0], "/marketdatasource.MarketDataSource/Connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketDataSourceConnectClient{stream}
+	return x, nil
+}
+
+type MarketDataSource_ConnectClient interface {
+	Send(*SubscribeRequest) error
+	Recv() (*model.ClobQuote, error)
+	grpc.ClientStream
+}
+
+type marketDataSourceConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketDataSourceConnectClient) Send(m *SubscribeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *marketDataSourceConnectClient) Recv() (*model.ClobQuote, error) {
+	m := new(model.ClobQuote)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MarketDataSourceServer is the server API for MarketDataSource service.
+type MarketDataSourceServer interface {
+	Connect(MarketDataSource_ConnectServer) error
+}
+
+// UnimplementedMarketDataSourceServer can be embedded to have forward compatible implementations.
+type UnimplementedMarketDataSourceServer struct {
+}
+
+func (*UnimplementedMarketDataSourceServer) Connect(srv MarketDataSource_ConnectServer) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+
+func RegisterMarketDataSourceServer(s *grpc.Server, srv MarketDataSourceServer) {
+	s.RegisterService(&_MarketDataSource_serviceDesc, srv)
+}
+
+func _MarketDataSource_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MarketDataSourceServer).Connect(&marketDataSourceConnectServer{stream})
+}
+
+type MarketDataSource_ConnectServer interface {
+	Send(*model.ClobQuote) error
+	Recv() (*SubscribeRequest, error)
+	grpc.ServerStream
+}
+
+type marketDataSourceConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketDataSourceConnectServer) Send(m *model.ClobQuote) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *marketDataSourceConnectServer) Recv() (*SubscribeRequest, error) {
+	m := new(SubscribeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _MarketDataSource_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "marketdatasource.MarketDataSource",
+	HandlerType: (*MarketDataSourceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _MarketDataSource_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "marketdatasource.proto",
+}
@@ -0,0 +1,77 @@
+// Package averagecost maintains a net position and its profit and loss from trades valued at their average cost.
+package averagecost
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+var zero = decimal.New(0, 0)
+
+// Position is a net position.  The quantity is signed, positive for a long position and negative for a short position,
+// and the average price is the average cost of the open quantity.
+type Position struct {
+	Quantity    decimal.Decimal
+	AvgPrice    decimal.Decimal
+	RealisedPnl decimal.Decimal
+}
+
+func NewPosition() Position {
+	return Position{Quantity: zero, AvgPrice: zero, RealisedPnl: zero}
+}
+
+// ApplyTrade applies a trade of the given signed quantity, positive for a buy, at the given price.  A trade that
+// increases the position is added to its average cost, a trade that reduces the position realises the profit or loss
+// of the quantity closed against the average cost and a trade that reverses the position opens the remaining quantity
+// at the trade price.
+func (p *Position) ApplyTrade(quantity decimal.Decimal, price decimal.Decimal) {
+	if p.Quantity.Equal(zero) || p.Quantity.Sign() == quantity.Sign() {
+		cost := p.AvgPrice.Mul(p.Quantity.Abs()).Add(price.Mul(quantity.Abs()))
+		p.Quantity = p.Quantity.Add(quantity)
+		if !p.Quantity.Equal(zero) {
+			p.AvgPrice = cost.Div(p.Quantity.Abs())
+		}
+		return
+	}
+
+	closedQuantity := decimal.Min(quantity.Abs(), p.Quantity.Abs())
+	p.RealisedPnl = p.RealisedPnl.Add(price.Sub(p.AvgPrice).Mul(closedQuantity).Mul(decimal.New(int64(p.Quantity.Sign()), 0)))
+
+	reversed := quantity.Abs().GreaterThan(p.Quantity.Abs())
+	p.Quantity = p.Quantity.Add(quantity)
+
+	switch {
+	case p.Quantity.Equal(zero):
+		p.AvgPrice = zero
+	case reversed:
+		p.AvgPrice = price
+	}
+}
+
+// ReverseTrade reverses a previously applied trade of the given signed quantity and price that has been cancelled or
+// corrected.  While the position still holds the trade's quantity, the trade's own quantity and cost are taken out of
+// the position and the realised profit or loss is unchanged, any cost left when the position is closed by the reversal
+// was realised by trades that closed other quantity against the trade's cost and is realised at that point.  A trade
+// that closed an earlier position cannot be taken out as the cost of the quantity it closed is not kept, it is instead
+// reversed by an opposite trade at its price, which restores the total profit or loss of the position but not its split
+// between realised and unrealised.
+func (p *Position) ReverseTrade(quantity decimal.Decimal, price decimal.Decimal) {
+	if p.Quantity.Sign() != quantity.Sign() || quantity.Abs().GreaterThan(p.Quantity.Abs()) {
+		p.ApplyTrade(quantity.Neg(), price)
+		return
+	}
+
+	cost := p.AvgPrice.Mul(p.Quantity.Abs()).Sub(price.Mul(quantity.Abs()))
+	p.Quantity = p.Quantity.Sub(quantity)
+	if p.Quantity.Equal(zero) {
+		p.RealisedPnl = p.RealisedPnl.Sub(cost.Mul(decimal.New(int64(quantity.Sign()), 0)))
+		p.AvgPrice = zero
+		return
+	}
+
+	p.AvgPrice = cost.Div(p.Quantity.Abs())
+}
+
+// UnrealisedPnl is the profit or loss of the open quantity at the given mark price.
+func (p *Position) UnrealisedPnl(markPrice decimal.Decimal) decimal.Decimal {
+	return markPrice.Sub(p.AvgPrice).Mul(p.Quantity)
+}
@@ -0,0 +1,128 @@
+package averagecost
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testTrade struct {
+	quantity int64
+	price    int64
+	reversal bool
+}
+
+func TestApplyTrade(t *testing.T) {
+	tests := []struct {
+		name            string
+		trades          []testTrade
+		wantQuantity    int64
+		wantAvgPrice    string
+		wantRealisedPnl int64
+	}{
+		{"open long", []testTrade{{10, 100, false}}, 10, "100", 0},
+		{"open short", []testTrade{{-10, 100, false}}, -10, "100", 0},
+		{"increase long", []testTrade{{10, 100, false}, {30, 104, false}}, 40, "103", 0},
+		{"increase short", []testTrade{{-10, 100, false}, {-10, 110, false}}, -20, "105", 0},
+		{"reduce long", []testTrade{{10, 100, false}, {-4, 110, false}}, 6, "100", 40},
+		{"reduce short", []testTrade{{-10, 100, false}, {4, 110, false}}, -6, "100", -40},
+		{"close long", []testTrade{{10, 100, false}, {-10, 90, false}}, 0, "0", -100},
+		{"close short", []testTrade{{-10, 100, false}, {10, 90, false}}, 0, "0", 100},
+		{"reverse long to short", []testTrade{{10, 100, false}, {-15, 105, false}}, -5, "105", 50},
+		{"reverse short to long", []testTrade{{-10, 100, false}, {15, 105, false}}, 5, "105", -50},
+		{"reopen after close", []testTrade{{10, 100, false}, {-10, 101, false}, {5, 120, false}}, 5, "120", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPosition(t, applyTrades(tt.trades), tt.wantQuantity, tt.wantAvgPrice, tt.wantRealisedPnl)
+		})
+	}
+}
+
+func TestReverseTrade(t *testing.T) {
+	tests := []struct {
+		name            string
+		trades          []testTrade
+		wantQuantity    int64
+		wantAvgPrice    string
+		wantRealisedPnl int64
+	}{
+		{"only trade", []testTrade{{10, 100, false}, {10, 100, true}}, 0, "0", 0},
+		{"first of two buys", []testTrade{{10, 100, false}, {10, 110, false}, {10, 100, true}}, 10, "110", 0},
+		{"second of two buys", []testTrade{{10, 100, false}, {10, 110, false}, {10, 110, true}}, 10, "100", 0},
+		{"first of two sells", []testTrade{{-10, 100, false}, {-10, 110, false}, {-10, 100, true}}, -10, "110", 0},
+		{"part of a reduced long", []testTrade{{10, 100, false}, {10, 110, false}, {-5, 120, false}, {10, 100, true}}, 5, "115", 75},
+		{"buy of a closed long", []testTrade{{10, 100, false}, {10, 110, false}, {-10, 120, false}, {10, 110, true}}, 0, "0", 200},
+		{"sell of a closed short", []testTrade{{-10, 100, false}, {-10, 110, false}, {10, 20, false}, {-10, 110, true}}, 0, "0", 800},
+		{"trade that closed a position", []testTrade{{10, 100, false}, {-10, 110, false}, {-10, 110, true}}, 10, "110", 100},
+		{"trade that reversed a position", []testTrade{{10, 100, false}, {-15, 110, false}, {-15, 110, true}}, 10, "110", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPosition(t, applyTrades(tt.trades), tt.wantQuantity, tt.wantAvgPrice, tt.wantRealisedPnl)
+		})
+	}
+}
+
+func TestReversalRestoresTotalPnl(t *testing.T) {
+	markPrice := decimal.New(130, 0)
+	tests := []struct {
+		name     string
+		trades   []testTrade
+		reversed testTrade
+	}{
+		{"reduced long", []testTrade{{10, 100, false}, {10, 110, false}, {-5, 120, false}}, testTrade{10, 110, true}},
+		{"closed short", []testTrade{{-10, 100, false}, {-10, 110, false}, {10, 20, false}}, testTrade{-10, 100, true}},
+		{"closing sell", []testTrade{{10, 100, false}, {-4, 110, false}}, testTrade{-4, 110, true}},
+		{"reversing buy", []testTrade{{-10, 100, false}, {15, 90, false}}, testTrade{15, 90, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var remaining []testTrade
+			removed := false
+			for _, trade := range tt.trades {
+				if !removed && trade.quantity == tt.reversed.quantity && trade.price == tt.reversed.price {
+					removed = true
+					continue
+				}
+				remaining = append(remaining, trade)
+			}
+
+			want := applyTrades(remaining)
+			got := applyTrades(append(tt.trades, tt.reversed))
+
+			assert.True(t, got.Quantity.Equal(want.Quantity), "quantity %v", got.Quantity)
+			wantTotal := want.RealisedPnl.Add(want.UnrealisedPnl(markPrice))
+			gotTotal := got.RealisedPnl.Add(got.UnrealisedPnl(markPrice))
+			assert.True(t, gotTotal.Equal(wantTotal), "total pnl %v, want %v", gotTotal, wantTotal)
+		})
+	}
+}
+
+func TestUnrealisedPnl(t *testing.T) {
+	p := NewPosition()
+	p.ApplyTrade(decimal.New(-10, 0), decimal.New(100, 0))
+
+	assert.True(t, p.UnrealisedPnl(decimal.New(95, 0)).Equal(decimal.New(50, 0)))
+}
+
+func applyTrades(trades []testTrade) Position {
+	p := NewPosition()
+	for _, trade := range trades {
+		if trade.reversal {
+			p.ReverseTrade(decimal.New(trade.quantity, 0), decimal.New(trade.price, 0))
+		} else {
+			p.ApplyTrade(decimal.New(trade.quantity, 0), decimal.New(trade.price, 0))
+		}
+	}
+	return p
+}
+
+func assertPosition(t *testing.T, p Position, wantQuantity int64, wantAvgPrice string, wantRealisedPnl int64) {
+	assert.True(t, p.Quantity.Equal(decimal.New(wantQuantity, 0)), "quantity %v", p.Quantity)
+	assert.Equal(t, wantAvgPrice, p.AvgPrice.String())
+	assert.True(t, p.RealisedPnl.Equal(decimal.New(wantRealisedPnl, 0)), "realised pnl %v", p.RealisedPnl)
+}
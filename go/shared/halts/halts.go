@@ -0,0 +1,52 @@
+// Package halts defines the trading halts that the order monitor publishes to the compacted halts topic and the orders
+// within the scope of a halt.  The order monitor cancels the orders within the scope of a halt and the order router
+// rejects them.
+package halts
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	Global     = "GLOBAL"
+	Originator = "ORIGINATOR"
+	Desk       = "DESK"
+	Listing    = "LISTING"
+)
+
+// Halt is the json representation of a trading halt in the halts topic.
+type Halt struct {
+	Scope    string    `json:"scope"`
+	ScopeId  string    `json:"scopeId"`
+	Reason   string    `json:"reason"`
+	HaltedBy string    `json:"haltedBy"`
+	Created  time.Time `json:"created"`
+}
+
+// Key returns the key of the halts topic messages for the given halt scope and scope id, lifting a halt writes a
+// tombstone for its key.
+func Key(scope string, scopeId string) string {
+	return scope + "/" + scopeId
+}
+
+func (h *Halt) Key() string {
+	return Key(h.Scope, h.ScopeId)
+}
+
+// AppliesTo returns true if an order with the given originator, root originator (desk) and listing is within the scope
+// of the halt.
+func (h *Halt) AppliesTo(originatorId string, rootOriginatorId string, listingId int32) bool {
+	switch h.Scope {
+	case Global:
+		return true
+	case Originator:
+		return originatorId == h.ScopeId
+	case Desk:
+		return rootOriginatorId == h.ScopeId
+	case Listing:
+		return strconv.Itoa(int(listingId)) == h.ScopeId
+	default:
+		return false
+	}
+}
@@ -0,0 +1,38 @@
+package halts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAppliesTo(t *testing.T) {
+	tests := []struct {
+		name             string
+		halt             Halt
+		originatorId     string
+		rootOriginatorId string
+		listingId        int32
+		want             bool
+	}{
+		{"global", Halt{Scope: Global}, "strategy-1", "deskA", 7, true},
+		{"originator", Halt{Scope: Originator, ScopeId: "strategy-1"}, "strategy-1", "deskA", 7, true},
+		{"other originator", Halt{Scope: Originator, ScopeId: "strategy-2"}, "strategy-1", "deskA", 7, false},
+		{"originator id of the desk", Halt{Scope: Originator, ScopeId: "deskA"}, "strategy-1", "deskA", 7, false},
+		{"desk", Halt{Scope: Desk, ScopeId: "deskA"}, "strategy-1", "deskA", 7, true},
+		{"other desk", Halt{Scope: Desk, ScopeId: "deskB"}, "strategy-1", "deskA", 7, false},
+		{"listing", Halt{Scope: Listing, ScopeId: "7"}, "strategy-1", "deskA", 7, true},
+		{"other listing", Halt{Scope: Listing, ScopeId: "8"}, "strategy-1", "deskA", 7, false},
+		{"unknown scope", Halt{Scope: "MARKET", ScopeId: "XNAS"}, "strategy-1", "deskA", 7, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.halt.AppliesTo(tt.originatorId, tt.rootOriginatorId, tt.listingId))
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "DESK/deskA", (&Halt{Scope: Desk, ScopeId: "deskA"}).Key())
+	assert.Equal(t, "GLOBAL/", (&Halt{Scope: Global}).Key())
+}
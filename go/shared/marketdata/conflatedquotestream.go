@@ -0,0 +1,69 @@
+package marketdata
+
+import (
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"log/slog"
+)
+
+// conflatedQuoteStream conflates quotes from a quote stream such that the most recent quote for a listing is read from
+// the stream even if the client is reading quotes at a slower rate than they are being published.
+type conflatedQuoteStream struct {
+	maxSubscriptions   int
+	quotesIn           QuoteStream
+	conflatedQuoteChan <-chan *model.ClobQuote
+	subscriptions      map[int32]bool
+	log                *slog.Logger
+}
+
+func newConflatedQuoteStream(id string, stream QuoteStream, maxSubscriptions int) *conflatedQuoteStream {
+	return &conflatedQuoteStream{
+		maxSubscriptions:   maxSubscriptions,
+		subscriptions:      map[int32]bool{},
+		quotesIn:           stream,
+		conflatedQuoteChan: conflateQuoteChan(stream.Chan(), maxSubscriptions),
+		log:                slog.Default().With("conflatedQuoteConnectionId", id),
+	}
+}
+
+func (c *conflatedQuoteStream) Subscribe(listingId int32) error {
+	if c.subscriptions[listingId] {
+		return nil
+	}
+
+	if len(c.subscriptions) == c.maxSubscriptions {
+		return fmt.Errorf("max number of subscriptions, %v, for this connection has been reached", c.maxSubscriptions)
+	}
+
+	if err := c.quotesIn.Subscribe(listingId); err != nil {
+		return fmt.Errorf("failed to subscribe to listing %v: %w", listingId, err)
+	}
+	c.subscriptions[listingId] = true
+
+	c.log.Info("Subscribed to listing", "listingId", listingId)
+
+	return nil
+}
+
+func (c *conflatedQuoteStream) Unsubscribe(listingId int32) error {
+	if !c.subscriptions[listingId] {
+		return nil
+	}
+
+	if err := c.quotesIn.Unsubscribe(listingId); err != nil {
+		return fmt.Errorf("failed to unsubscribe from listing %v: %w", listingId, err)
+	}
+	delete(c.subscriptions, listingId)
+
+	c.log.Info("Unsubscribed from listing", "listingId", listingId)
+
+	return nil
+}
+
+func (c *conflatedQuoteStream) Chan() <-chan *model.ClobQuote {
+	return c.conflatedQuoteChan
+}
+
+func (c *conflatedQuoteStream) Close() {
+	c.quotesIn.Close()
+}
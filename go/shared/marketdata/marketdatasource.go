@@ -0,0 +1,100 @@
+package marketdata
+
+import (
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/shared/api/marketdatasource"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/metadata"
+	"log/slog"
+)
+
+var connections = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "active_connections",
+	Help: "The number of active connections",
+})
+
+var quotesSent = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "quotes_sent",
+	Help: "The number of quotes sent across all clients",
+})
+
+const SubscriberIdKey = "subscriber_id"
+
+type marketDataSourceServer struct {
+	quoteDistributor *QuoteDistributor
+	maxSubscriptions int
+}
+
+// NewMarketDataSource returns a market data source api server that streams the quotes of the distributor to each
+// connection through a conflated quote stream.
+func NewMarketDataSource(quoteDistributor *QuoteDistributor, maxSubscriptions int) api.MarketDataSourceServer {
+	return &marketDataSourceServer{quoteDistributor: quoteDistributor, maxSubscriptions: maxSubscriptions}
+}
+
+func (s *marketDataSourceServer) Connect(stream api.MarketDataSource_ConnectServer) error {
+
+	metaData, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok {
+		return fmt.Errorf("failed to get metadata from incoming context")
+	}
+
+	values := metaData.Get(SubscriberIdKey)
+	if len(values) != 1 {
+		return fmt.Errorf("meta data does not contain an entry for required subscriber id key %v", SubscriberIdKey)
+	}
+
+	fromClientId := values[0]
+	subscriberId := fromClientId + ":" + uuid.New().String()
+
+	log := slog.Default().With("subscriberId", subscriberId)
+
+	log.Info("connect request received", "subscriber", fromClientId)
+
+	quoteStream := newConflatedQuoteStream(subscriberId, s.quoteDistributor.NewQuoteStream(), s.maxSubscriptions)
+	defer quoteStream.Close()
+
+	go func() {
+		for {
+			request, err := stream.Recv()
+			if err != nil {
+				log.Error("error receiving from grpc stream", "error", err)
+				return
+			}
+
+			if request.Unsubscribe {
+				log.Info("unsubscribing from listing id", "listingId", request.ListingId)
+				if err := quoteStream.Unsubscribe(request.ListingId); err != nil {
+					log.Error("error unsubscribing from listing", "listingId", request.ListingId, "error", err)
+				}
+			} else {
+				log.Info("subscribing to listing id", "listingId", request.ListingId)
+				if err := quoteStream.Subscribe(request.ListingId); err != nil {
+					log.Error("error subscribing to listing", "listingId", request.ListingId, "error", err)
+				}
+			}
+		}
+	}()
+
+	connections.Inc()
+	defer connections.Dec()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case quote, ok := <-quoteStream.Chan():
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(quote); err != nil {
+				log.Error("failed to send quote, closing connection", "error", err)
+				return nil
+			}
+
+			quotesSent.Inc()
+		}
+	}
+}
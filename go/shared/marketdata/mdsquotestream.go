@@ -0,0 +1,299 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	marketdataservice "github.com/ettec/open-trading-platform/go/shared/api/marketdataservice"
+	marketdatasource "github.com/ettec/open-trading-platform/go/shared/api/marketdatasource"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var quotesReceived = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "quotes_received",
+	Help: "The number of quotes received from all streams",
+})
+
+type grpcConnection interface {
+	GetState() connectivity.State
+	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
+}
+
+type getMdsClientFn = func(targetAddress string) (commonMds, grpcConnection, error)
+
+// commonMds is the api common to a market data service and a market data source.
+type commonMds interface {
+	Connect(ctx context.Context) (mdsClient, error)
+}
+
+type mdsClient interface {
+	Subscribe(listingId int32) error
+	Unsubscribe(listingId int32) error
+	Recv() (*model.ClobQuote, error)
+}
+
+type serviceToCommonMds struct {
+	subscriberId string
+	client       marketdataservice.MarketDataServiceClient
+}
+
+func (s *serviceToCommonMds) Connect(ctx context.Context) (mdsClient, error) {
+	cc, err := s.client.Connect(ctx, &marketdataservice.MdsConnectRequest{SubscriberId: s.subscriberId})
+	if err != nil {
+		return nil, err
+	}
+
+	return &serviceToCommonMdsClient{ctx: ctx, connectClient: cc, client: s.client, subscriberId: s.subscriberId}, nil
+}
+
+type serviceToCommonMdsClient struct {
+	ctx           context.Context
+	connectClient marketdataservice.MarketDataService_ConnectClient
+	client        marketdataservice.MarketDataServiceClient
+	subscriberId  string
+}
+
+func (s *serviceToCommonMdsClient) Subscribe(listingId int32) error {
+	_, err := s.client.Subscribe(s.ctx, &marketdataservice.MdsSubscribeRequest{SubscriberId: s.subscriberId,
+		ListingId: listingId})
+	return err
+}
+
+func (s *serviceToCommonMdsClient) Unsubscribe(listingId int32) error {
+	_, err := s.client.Unsubscribe(s.ctx, &marketdataservice.MdsUnsubscribeRequest{SubscriberId: s.subscriberId,
+		ListingId: listingId})
+	return err
+}
+
+func (s *serviceToCommonMdsClient) Recv() (*model.ClobQuote, error) {
+	return s.connectClient.Recv()
+}
+
+type sourceToCommonMds struct {
+	client marketdatasource.MarketDataSourceClient
+}
+
+func (s *sourceToCommonMds) Connect(ctx context.Context) (mdsClient, error) {
+	cc, err := s.client.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sourceToCommonMdsClient{cc: cc}, nil
+}
+
+type sourceToCommonMdsClient struct {
+	cc marketdatasource.MarketDataSource_ConnectClient
+}
+
+func (s *sourceToCommonMdsClient) Subscribe(listingId int32) error {
+	return s.cc.Send(&marketdatasource.SubscribeRequest{ListingId: listingId})
+}
+
+func (s *sourceToCommonMdsClient) Unsubscribe(listingId int32) error {
+	return s.cc.Send(&marketdatasource.SubscribeRequest{ListingId: listingId, Unsubscribe: true})
+}
+
+func (s *sourceToCommonMdsClient) Recv() (*model.ClobQuote, error) {
+	return s.cc.Recv()
+}
+
+// mdsQuoteStream wraps the API of a market data service or market data source such that it conforms to the QuoteStream
+// interface.
+type mdsQuoteStream struct {
+	subscriptionLock sync.Mutex
+	client           mdsClient
+	subscriptions    map[int32]bool
+	out              chan *model.ClobQuote
+	cancelContext    func()
+	log              *slog.Logger
+}
+
+// NewQuoteStreamFromMarketDataService returns a quote stream that sources quote data from a market data service.
+func NewQuoteStreamFromMarketDataService(ctx context.Context, subscriberId string, targetAddress string,
+	maxReconnectInterval time.Duration, quoteBufferSize int) (QuoteStream, error) {
+
+	getClient := func(targetAddress string) (commonMds, grpcConnection, error) {
+		conn, err := grpc.Dial(targetAddress, grpc.WithInsecure(), grpc.WithBackoffMaxDelay(maxReconnectInterval))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &serviceToCommonMds{subscriberId: subscriberId, client: marketdataservice.NewMarketDataServiceClient(conn)},
+			conn, nil
+	}
+
+	return newMdsQuoteStreamFromFn(ctx, subscriberId, targetAddress, quoteBufferSize, getClient)
+}
+
+// NewQuoteStreamFromMdSource returns a quote stream that sources quote data from a market data source such as a
+// market data gateway.
+func NewQuoteStreamFromMdSource(ctx context.Context, id string, targetAddress string, maxReconnectInterval time.Duration,
+	quoteBufferSize int) (QuoteStream, error) {
+
+	getClient := func(targetAddress string) (commonMds, grpcConnection, error) {
+		conn, err := grpc.Dial(targetAddress, grpc.WithInsecure(), grpc.WithBackoffMaxDelay(maxReconnectInterval))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &sourceToCommonMds{client: marketdatasource.NewMarketDataSourceClient(conn)}, conn, nil
+	}
+
+	return newMdsQuoteStreamFromFn(ctx, id, targetAddress, quoteBufferSize, getClient)
+}
+
+func newMdsQuoteStreamFromFn(parentCtx context.Context, id string, targetAddress string, outBufferSize int,
+	getClient getMdsClientFn) (*mdsQuoteStream, error) {
+
+	maxReconnectWaitTime := time.Duration(bootstrap.GetOptionalIntEnvVar("MDSQUOTESTREAM_MAX_RECONNECT_WAIT_SECS", 30)) * time.Second
+
+	log := slog.With("target", targetAddress)
+	log.Info("connecting to market data source", "targetAddress", targetAddress)
+
+	client, conn, err := getClient(targetAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(parentCtx)
+	out := make(chan *model.ClobQuote, outBufferSize)
+
+	mqs := &mdsQuoteStream{
+		out:           out,
+		subscriptions: map[int32]bool{},
+		cancelContext: cancel,
+		log:           log,
+	}
+
+	go func() {
+		defer close(out)
+
+		sentQuoteListingIds := map[int32]bool{}
+		for {
+			state := conn.GetState()
+			retryWait := time.Duration(1) * time.Second
+			for state != connectivity.Ready {
+				log.Info(fmt.Sprintf("waiting %v before checking market data source connection state", retryWait))
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(retryWait):
+				}
+
+				retryWait = retryWait * 2
+
+				if retryWait > maxReconnectWaitTime {
+					retryWait = maxReconnectWaitTime
+				}
+
+				conn.WaitForStateChange(ctx, state)
+
+				state = conn.GetState()
+				log.Info("market data source connection state", "state", state)
+			}
+
+			connectedClient, err := client.Connect(metadata.AppendToOutgoingContext(ctx, SubscriberIdKey, id))
+			if err != nil {
+				log.Error("failed to connect to quote stream", "error", err)
+				continue
+			}
+
+			log.Info("connected to quote stream, resubscribing to all listings")
+
+			mqs.setClient(connectedClient)
+
+			for {
+				clobQuote, err := connectedClient.Recv()
+				if err != nil {
+					log.Error("inbound stream error", "error", err)
+					break
+				}
+				out <- clobQuote
+				sentQuoteListingIds[clobQuote.ListingId] = true
+				quotesReceived.Inc()
+			}
+
+			mqs.setClient(nil)
+
+			log.Info("inbound stream closed, resetting all quotes")
+			for listingId := range sentQuoteListingIds {
+				out <- &model.ClobQuote{
+					ListingId:         listingId,
+					Bids:              []*model.ClobLine{},
+					Offers:            []*model.ClobLine{},
+					StreamInterrupted: true,
+					StreamStatusMsg:   "market data source client stream interrupted",
+				}
+			}
+			sentQuoteListingIds = map[int32]bool{}
+		}
+	}()
+
+	return mqs, nil
+}
+
+func (m *mdsQuoteStream) setClient(client mdsClient) {
+	m.subscriptionLock.Lock()
+	defer m.subscriptionLock.Unlock()
+	m.client = client
+	if client == nil {
+		return
+	}
+
+	for listingId := range m.subscriptions {
+		if err := client.Subscribe(listingId); err != nil {
+			m.log.Error("failed to subscribe to quote for listing", "listingId", listingId, "error", err)
+			break
+		}
+	}
+}
+
+func (m *mdsQuoteStream) Chan() <-chan *model.ClobQuote {
+	return m.out
+}
+
+func (m *mdsQuoteStream) Subscribe(listingId int32) error {
+	m.subscriptionLock.Lock()
+	defer m.subscriptionLock.Unlock()
+	m.subscriptions[listingId] = true
+	if m.client != nil {
+		if err := m.client.Subscribe(listingId); err != nil {
+			return fmt.Errorf("failed to subscribe to quote for listing %v: %w", listingId, err)
+		}
+	}
+
+	return nil
+}
+
+// Unsubscribe releases the subscription to the listing at the market data source, if the source is not connected the
+// listing is not resubscribed to on connection.
+func (m *mdsQuoteStream) Unsubscribe(listingId int32) error {
+	m.subscriptionLock.Lock()
+	defer m.subscriptionLock.Unlock()
+	if !m.subscriptions[listingId] {
+		return nil
+	}
+
+	delete(m.subscriptions, listingId)
+	if m.client != nil {
+		if err := m.client.Unsubscribe(listingId); err != nil {
+			return fmt.Errorf("failed to unsubscribe from quote for listing %v: %w", listingId, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *mdsQuoteStream) Close() {
+	m.cancelContext()
+}
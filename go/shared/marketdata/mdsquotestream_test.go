@@ -0,0 +1,342 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	marketdataservice "github.com/ettec/open-trading-platform/go/shared/api/marketdataservice"
+	marketdatasource "github.com/ettec/open-trading-platform/go/shared/api/marketdatasource"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"io"
+	"testing"
+)
+
+type testConnection struct {
+	state        connectivity.State
+	getStateChan chan connectivity.State
+}
+
+func (t testConnection) GetState() connectivity.State {
+	t.state = <-t.getStateChan
+	return t.state
+}
+
+func (t testConnection) WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool {
+
+	for {
+		if t.state != sourceState {
+			return true
+		}
+		t.state = <-t.getStateChan
+	}
+
+}
+
+type testClient struct {
+	streamOutChan chan marketdatasource.MarketDataSource_ConnectClient
+}
+
+func (t testClient) Connect(ctx context.Context, opts ...grpc.CallOption) (marketdatasource.MarketDataSource_ConnectClient, error) {
+	return <-t.streamOutChan, nil
+}
+
+type testClientStream struct {
+	refreshChan    chan *model.ClobQuote
+	refreshErrChan chan error
+	subscribeChan  chan *marketdatasource.SubscribeRequest
+}
+
+func (t testClientStream) Send(request *marketdatasource.SubscribeRequest) error {
+	t.subscribeChan <- request
+	return nil
+}
+
+func (t testClientStream) Recv() (*model.ClobQuote, error) {
+	select {
+	case r := <-t.refreshChan:
+		return r, nil
+	case e := <-t.refreshErrChan:
+		return nil, e
+	}
+}
+
+func (t testClientStream) Header() (metadata.MD, error) {
+	panic("implement me")
+}
+
+func (t testClientStream) Trailer() metadata.MD {
+	panic("implement me")
+}
+
+func (t testClientStream) CloseSend() error {
+	panic("implement me")
+}
+
+func (t testClientStream) Context() context.Context {
+	panic("implement me")
+}
+
+func (t testClientStream) SendMsg(m interface{}) error {
+	panic("implement me")
+}
+
+func (t testClientStream) RecvMsg(m interface{}) error {
+	panic("implement me")
+}
+
+func TestMarketDataGatewayClient_refreshesForwaredToOut(t *testing.T) {
+
+	client, stream, conn, mdsQuoteStream := setup(t)
+
+	conn.getStateChan <- connectivity.Ready
+
+	client.streamOutChan <- stream
+
+	stream.refreshChan <- &model.ClobQuote{}
+
+	<-mdsQuoteStream.Chan()
+}
+
+func TestMarketDataGatewayClient_sendsEmptyQuoteForAllListingsOnConnectionError(t *testing.T) {
+
+	client, stream, conn, mdc := setup(t)
+
+	conn.getStateChan <- connectivity.Ready
+
+	err := mdc.Subscribe(1)
+	assert.NoError(t, err)
+	err = mdc.Subscribe(2)
+	assert.NoError(t, err)
+
+	client.streamOutChan <- stream
+
+	stream.refreshChan <- &model.ClobQuote{ListingId: 1}
+	<-mdc.Chan()
+
+	stream.refreshErrChan <- fmt.Errorf("testerror")
+	r := <-mdc.Chan()
+
+	if !r.StreamInterrupted {
+		t.FailNow()
+	}
+
+	if r.ListingId != 1 {
+		t.FailNow()
+	}
+
+	if len(r.Bids) != 0 || len(r.Offers) != 0 {
+		t.FailNow()
+	}
+
+}
+
+func TestMarketDataGatewayClient_testReconnectAfterError(t *testing.T) {
+
+	client, stream, conn, toTest := setup(t)
+
+	conn.getStateChan <- connectivity.Ready
+
+	client.streamOutChan <- stream
+
+	toTest.Subscribe(1)
+
+	stream.refreshChan <- &model.ClobQuote{}
+	<-toTest.Chan()
+
+	stream.refreshErrChan <- fmt.Errorf("testerror")
+	r := <-toTest.Chan()
+	if r.StreamInterrupted != true {
+		t.FailNow()
+	}
+
+	conn.getStateChan <- connectivity.TransientFailure
+	conn.getStateChan <- connectivity.Ready
+	client.streamOutChan <- stream
+
+	<-stream.subscribeChan
+
+}
+
+func TestMarketDataGatewayClient_resubscribedOnConnect(t *testing.T) {
+
+	client, stream, conn, toTest := setup(t)
+
+	toTest.Subscribe(1)
+
+	conn.getStateChan <- connectivity.Ready
+
+	client.streamOutChan <- stream
+
+	s := <-stream.subscribeChan
+
+	if s.ListingId != 1 {
+		t.FailNow()
+	}
+
+}
+
+func setup(t *testing.T) (testClient, testClientStream, testConnection, QuoteStream) {
+
+	client := testClient{
+
+		streamOutChan: make(chan marketdatasource.MarketDataSource_ConnectClient),
+	}
+
+	stream := testClientStream{refreshChan: make(chan *model.ClobQuote),
+		refreshErrChan: make(chan error),
+		subscribeChan:  make(chan *marketdatasource.SubscribeRequest, 10)}
+
+	conn := testConnection{
+		getStateChan: make(chan connectivity.State),
+	}
+
+	c, err := newMdsQuoteStreamFromFn(context.Background(), "testId", "testTarget", 0,
+		func(targetAddress string) (commonMds, grpcConnection, error) {
+
+			return &sourceToCommonMds{client: client}, conn, nil
+		})
+
+	if err != nil {
+		t.FailNow()
+	}
+	return client, stream, conn, c
+}
+
+type readyConnection struct {
+}
+
+func (t readyConnection) GetState() connectivity.State {
+	return connectivity.Ready
+}
+
+func (t readyConnection) WaitForStateChange(context.Context, connectivity.State) bool {
+	return true
+}
+
+type testSourceConnectClient struct {
+	grpc.ClientStream
+	requests chan *marketdatasource.SubscribeRequest
+	quotes   chan *model.ClobQuote
+}
+
+func (t *testSourceConnectClient) Send(request *marketdatasource.SubscribeRequest) error {
+	t.requests <- request
+	return nil
+}
+
+func (t *testSourceConnectClient) Recv() (*model.ClobQuote, error) {
+	quote, ok := <-t.quotes
+	if !ok {
+		return nil, io.EOF
+	}
+	return quote, nil
+}
+
+type testMdSourceClient struct {
+	connectClients chan *testSourceConnectClient
+}
+
+func (t *testMdSourceClient) Connect(context.Context, ...grpc.CallOption) (marketdatasource.MarketDataSource_ConnectClient, error) {
+	return <-t.connectClients, nil
+}
+
+func newTestSourceConnectClient() *testSourceConnectClient {
+	return &testSourceConnectClient{requests: make(chan *marketdatasource.SubscribeRequest, 10),
+		quotes: make(chan *model.ClobQuote)}
+}
+
+func TestUnsubscribedListingsAreNotResubscribedOnReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &testMdSourceClient{connectClients: make(chan *testSourceConnectClient)}
+	stream, err := newMdsQuoteStreamFromFn(ctx, "testMds", "testAddress", 10,
+		func(string) (commonMds, grpcConnection, error) {
+			return &sourceToCommonMds{client: client}, readyConnection{}, nil
+		})
+	assert.NoError(t, err)
+
+	assert.NoError(t, stream.Subscribe(1))
+	assert.NoError(t, stream.Subscribe(2))
+
+	first := newTestSourceConnectClient()
+	client.connectClients <- first
+	resubscribed := map[int32]bool{}
+	resubscribed[(<-first.requests).ListingId] = true
+	resubscribed[(<-first.requests).ListingId] = true
+	assert.Equal(t, map[int32]bool{1: true, 2: true}, resubscribed)
+
+	assert.NoError(t, stream.Unsubscribe(1))
+	assert.Equal(t, &marketdatasource.SubscribeRequest{ListingId: 1, Unsubscribe: true}, <-first.requests)
+
+	assert.NoError(t, stream.Unsubscribe(1))
+	assert.Empty(t, first.requests)
+
+	close(first.quotes)
+
+	second := newTestSourceConnectClient()
+	client.connectClients <- second
+	assert.Equal(t, &marketdatasource.SubscribeRequest{ListingId: 2}, <-second.requests)
+	assert.Empty(t, second.requests)
+}
+
+type testServiceConnectClient struct {
+	grpc.ClientStream
+	ctx context.Context
+}
+
+func (t *testServiceConnectClient) Recv() (*model.ClobQuote, error) {
+	<-t.ctx.Done()
+	return nil, t.ctx.Err()
+}
+
+type testMdsClient struct {
+	connected    chan bool
+	subscribed   chan *marketdataservice.MdsSubscribeRequest
+	unsubscribed chan *marketdataservice.MdsUnsubscribeRequest
+}
+
+func (t *testMdsClient) Subscribe(_ context.Context, in *marketdataservice.MdsSubscribeRequest, _ ...grpc.CallOption) (*model.Empty, error) {
+	t.subscribed <- in
+	return &model.Empty{}, nil
+}
+
+func (t *testMdsClient) Unsubscribe(_ context.Context, in *marketdataservice.MdsUnsubscribeRequest, _ ...grpc.CallOption) (*model.Empty, error) {
+	t.unsubscribed <- in
+	return &model.Empty{}, nil
+}
+
+func (t *testMdsClient) Connect(ctx context.Context, _ *marketdataservice.MdsConnectRequest, _ ...grpc.CallOption) (marketdataservice.MarketDataService_ConnectClient, error) {
+	t.connected <- true
+	return &testServiceConnectClient{ctx: ctx}, nil
+}
+
+func TestUnsubscribeIsSentToTheMarketDataService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &testMdsClient{
+		connected:    make(chan bool),
+		subscribed:   make(chan *marketdataservice.MdsSubscribeRequest, 10),
+		unsubscribed: make(chan *marketdataservice.MdsUnsubscribeRequest, 10),
+	}
+
+	stream, err := newMdsQuoteStreamFromFn(ctx, "testAggregator", "testAddress", 10,
+		func(string) (commonMds, grpcConnection, error) {
+			return &serviceToCommonMds{subscriberId: "testAggregator", client: client}, readyConnection{}, nil
+		})
+	assert.NoError(t, err)
+
+	assert.NoError(t, stream.Subscribe(1))
+	<-client.connected
+	assert.Equal(t, &marketdataservice.MdsSubscribeRequest{SubscriberId: "testAggregator", ListingId: 1}, <-client.subscribed)
+
+	assert.NoError(t, stream.Unsubscribe(1))
+	assert.Equal(t, &marketdataservice.MdsUnsubscribeRequest{SubscriberId: "testAggregator", ListingId: 1}, <-client.unsubscribed)
+
+	assert.NoError(t, stream.Unsubscribe(1))
+	assert.Empty(t, client.unsubscribed)
+}
@@ -0,0 +1,224 @@
+package marketdata
+
+import (
+	"context"
+	"github.com/ettec/otp-common/model"
+	"log/slog"
+	"sync"
+)
+
+type distributorSubscription struct {
+	stream      *distributorQuoteStream
+	listingId   int32
+	unsubscribe bool
+	done        chan struct{}
+}
+
+// QuoteDistributor fans out the quotes of a quote stream to the quote streams of a service's connections.  The latest
+// quote of each listing is cached and a stream that subscribes to the listing is sent the cached quote, which holds the
+// listing's full depth, before any later quote.  Subscriptions and quotes are processed in turn by a single goroutine,
+// so a subscriber receives the cached quote followed by every later quote of the listing with no gap or duplicate
+// between the two.
+//
+// Subscriptions to the source stream are reference counted, when the last stream subscribed to a listing unsubscribes
+// or is closed the subscription is released and the listing's cached quote is discarded.
+type QuoteDistributor struct {
+	ctx            context.Context
+	subscriptions  chan distributorSubscription
+	closedStreams  chan *distributorQuoteStream
+	sendBufferSize int
+}
+
+func NewQuoteDistributor(ctx context.Context, stream QuoteStream, sendBufferSize int) *QuoteDistributor {
+	d := &QuoteDistributor{
+		ctx:            ctx,
+		subscriptions:  make(chan distributorSubscription),
+		closedStreams:  make(chan *distributorQuoteStream),
+		sendBufferSize: sendBufferSize,
+	}
+
+	go d.run(stream)
+
+	return d
+}
+
+func (d *QuoteDistributor) run(stream QuoteStream) {
+	listingToStreams := map[int32]map[*distributorQuoteStream]bool{}
+	streamToListings := map[*distributorQuoteStream]map[int32]bool{}
+	lastQuotes := map[int32]*model.ClobQuote{}
+	subscribedToSource := map[int32]bool{}
+
+	releaseIfUnused := func(listingId int32) {
+		if len(listingToStreams[listingId]) > 0 {
+			return
+		}
+
+		delete(listingToStreams, listingId)
+		delete(lastQuotes, listingId)
+		delete(subscribedToSource, listingId)
+		if err := stream.Unsubscribe(listingId); err != nil {
+			slog.Error("failed to unsubscribe from listing", "listingId", listingId, "error", err)
+		}
+	}
+
+	unsubscribeStream := func(s *distributorQuoteStream, listingId int32) {
+		if !listingToStreams[listingId][s] {
+			return
+		}
+
+		delete(listingToStreams[listingId], s)
+		delete(streamToListings[s], listingId)
+		releaseIfUnused(listingId)
+	}
+
+	removeStream := func(s *distributorQuoteStream) {
+		for listingId := range streamToListings[s] {
+			unsubscribeStream(s, listingId)
+		}
+		delete(streamToListings, s)
+	}
+
+	streamChan := stream.Chan()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case s := <-d.subscriptions:
+			if s.unsubscribe {
+				unsubscribeStream(s.stream, s.listingId)
+				close(s.done)
+				continue
+			}
+
+			if !subscribedToSource[s.listingId] {
+				if err := stream.Subscribe(s.listingId); err != nil {
+					slog.Error("failed to subscribe to listing", "listingId", s.listingId, "error", err)
+				} else {
+					subscribedToSource[s.listingId] = true
+				}
+			}
+
+			if !listingToStreams[s.listingId][s.stream] {
+				if listingToStreams[s.listingId] == nil {
+					listingToStreams[s.listingId] = map[*distributorQuoteStream]bool{}
+				}
+				listingToStreams[s.listingId][s.stream] = true
+
+				if streamToListings[s.stream] == nil {
+					streamToListings[s.stream] = map[int32]bool{}
+				}
+				streamToListings[s.stream][s.listingId] = true
+
+				if lastQuote, ok := lastQuotes[s.listingId]; ok {
+					if !s.stream.send(lastQuote) {
+						removeStream(s.stream)
+					}
+				}
+			}
+
+			close(s.done)
+		case quote, ok := <-streamChan:
+			if !ok {
+				slog.Error("source quote stream closed")
+				return
+			}
+
+			if len(listingToStreams[quote.ListingId]) == 0 {
+				continue
+			}
+
+			lastQuotes[quote.ListingId] = quote
+
+			for s := range listingToStreams[quote.ListingId] {
+				if !s.send(quote) {
+					removeStream(s)
+				}
+			}
+		case s := <-d.closedStreams:
+			removeStream(s)
+			close(s.out)
+		}
+	}
+}
+
+func (d *QuoteDistributor) NewQuoteStream() *distributorQuoteStream {
+	return &distributorQuoteStream{
+		distributor: d,
+		out:         make(chan *model.ClobQuote, d.sendBufferSize),
+		closed:      make(chan struct{}),
+	}
+}
+
+// distributorQuoteStream is a connection's stream of the distributed quotes, its channel is closed once the stream has
+// been closed and removed from the distributor.
+type distributorQuoteStream struct {
+	distributor *QuoteDistributor
+	out         chan *model.ClobQuote
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+// Subscribe returns once the subscription has been made and, if the listing has a cached quote, the quote has been
+// queued to the stream.
+func (s *distributorQuoteStream) Subscribe(listingId int32) error {
+	return s.request(listingId, false)
+}
+
+// Unsubscribe returns once the subscription has been released, quotes for the listing that are already queued to the
+// stream are not removed.
+func (s *distributorQuoteStream) Unsubscribe(listingId int32) error {
+	return s.request(listingId, true)
+}
+
+func (s *distributorQuoteStream) request(listingId int32, unsubscribe bool) error {
+	subscription := distributorSubscription{stream: s, listingId: listingId, unsubscribe: unsubscribe,
+		done: make(chan struct{})}
+
+	select {
+	case s.distributor.subscriptions <- subscription:
+	case <-s.closed:
+		return nil
+	case <-s.distributor.ctx.Done():
+		return s.distributor.ctx.Err()
+	}
+
+	select {
+	case <-subscription.done:
+		return nil
+	case <-s.distributor.ctx.Done():
+		return s.distributor.ctx.Err()
+	}
+}
+
+func (s *distributorQuoteStream) Chan() <-chan *model.ClobQuote {
+	return s.out
+}
+
+func (s *distributorQuoteStream) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		go func() {
+			select {
+			case s.distributor.closedStreams <- s:
+			case <-s.distributor.ctx.Done():
+			}
+		}()
+	})
+}
+
+// send returns false if the stream has been closed.
+func (s *distributorQuoteStream) send(quote *model.ClobQuote) bool {
+	select {
+	case <-s.closed:
+		return false
+	default:
+	}
+
+	select {
+	case s.out <- quote:
+		return true
+	case <-s.closed:
+		return false
+	}
+}
@@ -0,0 +1,264 @@
+package marketdata
+
+import (
+	"context"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_quoteDistributor_Send(t *testing.T) {
+
+	in := make(chan *model.ClobQuote, 10)
+
+	d := NewQuoteDistributor(context.Background(), testMdsQuoteStream{
+		func(listingId int32) {
+		}, in}, 100)
+
+	s1 := d.NewQuoteStream()
+
+	s2 := d.NewQuoteStream()
+
+	err := s1.Subscribe(1)
+	assert.NoError(t, err)
+	err = s2.Subscribe(1)
+	assert.NoError(t, err)
+
+	in <- &model.ClobQuote{ListingId: 1}
+
+	if q := <-s1.Chan(); q.ListingId != 1 {
+		t.Errorf("expected quote not received")
+	}
+
+	if q := <-s2.Chan(); q.ListingId != 1 {
+		t.Errorf("expected quote not received")
+	}
+
+}
+
+func Test_subscriptionReceivesLastSentQuote(t *testing.T) {
+
+	in := make(chan *model.ClobQuote, 10)
+
+	d := NewQuoteDistributor(context.Background(), testMdsQuoteStream{
+		func(listingId int32) {
+		}, in}, 100)
+
+	s1 := d.NewQuoteStream()
+	s2 := d.NewQuoteStream()
+
+	err := s1.Subscribe(1)
+	assert.NoError(t, err)
+
+	in <- &model.ClobQuote{ListingId: 1}
+
+	if q := <-s1.Chan(); q.ListingId != 1 {
+		t.Errorf("expected quote note received")
+	}
+
+	err = s2.Subscribe(1)
+	assert.NoError(t, err)
+
+	if q := <-s2.Chan(); q.ListingId != 1 {
+		t.Errorf("expected quote note received")
+	}
+
+}
+
+func Test_subscribeCalledOnceForAGivenListing(t *testing.T) {
+
+	in := make(chan *model.ClobQuote)
+
+	subscribeCalls := make(chan int32, 10)
+	d := NewQuoteDistributor(context.Background(), testMdsQuoteStream{
+		func(listingId int32) {
+			subscribeCalls <- listingId
+		}, in}, 100)
+
+	s1 := d.NewQuoteStream()
+	d.NewQuoteStream()
+
+	err := s1.Subscribe(1)
+	assert.NoError(t, err)
+
+	time.Sleep(2 * time.Second)
+
+	if len(subscribeCalls) != 1 {
+		t.FailNow()
+	}
+
+}
+
+func Test_subscribeOnlyCalledOnceForAGivenListing(t *testing.T) {
+
+	in := make(chan *model.ClobQuote)
+
+	subscribeCalls := make(chan int32, 10)
+	d := NewQuoteDistributor(context.Background(), testMdsQuoteStream{
+		func(listingId int32) {
+			subscribeCalls <- listingId
+		}, in}, 100)
+
+	s1 := d.NewQuoteStream()
+	s2 := d.NewQuoteStream()
+
+	err := s1.Subscribe(1)
+	assert.NoError(t, err)
+	err = s2.Subscribe(1)
+	assert.NoError(t, err)
+
+	time.Sleep(2 * time.Second)
+
+	if len(subscribeCalls) != 1 {
+		t.FailNow()
+	}
+
+}
+
+func Test_onlySubscribedQuotesReceived(t *testing.T) {
+
+	in := make(chan *model.ClobQuote)
+
+	d := NewQuoteDistributor(context.Background(), testMdsQuoteStream{
+		func(listingId int32) {
+		}, in}, 100)
+
+	s1 := d.NewQuoteStream()
+
+	err := s1.Subscribe(1)
+	assert.NoError(t, err)
+	err = s1.Subscribe(2)
+	assert.NoError(t, err)
+
+	in <- &model.ClobQuote{ListingId: 3}
+	in <- &model.ClobQuote{ListingId: 1}
+	in <- &model.ClobQuote{ListingId: 4}
+	in <- &model.ClobQuote{ListingId: 2}
+
+	q := <-s1.Chan()
+	if q.ListingId != 1 {
+		t.Errorf("unexpected quote")
+	}
+
+	q = <-s1.Chan()
+	if q.ListingId != 2 {
+		t.Errorf("unexpected quote")
+	}
+
+}
+
+type testQuoteStream struct {
+	quotes       chan *model.ClobQuote
+	subscribed   chan int32
+	unsubscribed chan int32
+	closed       bool
+}
+
+func newTestQuoteStream() *testQuoteStream {
+	return &testQuoteStream{
+		quotes:       make(chan *model.ClobQuote, 10),
+		subscribed:   make(chan int32, 10),
+		unsubscribed: make(chan int32, 10),
+	}
+}
+
+func (t *testQuoteStream) Subscribe(listingId int32) error {
+	t.subscribed <- listingId
+	return nil
+}
+
+func (t *testQuoteStream) Unsubscribe(listingId int32) error {
+	t.unsubscribed <- listingId
+	return nil
+}
+
+func (t *testQuoteStream) Chan() <-chan *model.ClobQuote {
+	return t.quotes
+}
+
+func (t *testQuoteStream) Close() {
+	t.closed = true
+}
+
+func TestSourceSubscriptionIsReleasedWhenTheLastStreamLeaves(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := newTestQuoteStream()
+	distributor := NewQuoteDistributor(ctx, source, 10)
+
+	stream1 := distributor.NewQuoteStream()
+	stream2 := distributor.NewQuoteStream()
+	assert.NoError(t, stream1.Subscribe(1))
+	assert.NoError(t, stream2.Subscribe(1))
+	assert.NoError(t, stream2.Subscribe(2))
+	assert.Equal(t, int32(1), <-source.subscribed)
+	assert.Equal(t, int32(2), <-source.subscribed)
+
+	assert.NoError(t, stream1.Unsubscribe(1))
+	assert.Empty(t, source.unsubscribed)
+
+	source.quotes <- &model.ClobQuote{ListingId: 1}
+	assert.Equal(t, &model.ClobQuote{ListingId: 1}, <-stream2.Chan())
+
+	stream2.Close()
+
+	_, ok := <-stream2.Chan()
+	assert.False(t, ok)
+
+	released := map[int32]bool{<-source.unsubscribed: true, <-source.unsubscribed: true}
+	assert.Equal(t, map[int32]bool{1: true, 2: true}, released)
+	assert.Empty(t, stream1.Chan())
+}
+
+func TestSubscribingWhilstQuotesAreReceivedHasNoGapOrDuplicate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := newTestQuoteStream()
+	distributor := NewQuoteDistributor(ctx, source, 1000)
+
+	first := distributor.NewQuoteStream()
+	assert.NoError(t, first.Subscribe(1))
+
+	const numQuotes = 500
+	go func() {
+		for i := 1; i <= numQuotes; i++ {
+			source.quotes <- &model.ClobQuote{ListingId: 1, TradedVolume: model.IasD(i)}
+		}
+	}()
+
+	for i := 1; i <= numQuotes/2; i++ {
+		assert.Equal(t, int64(i), (<-first.Chan()).TradedVolume.Mantissa)
+	}
+
+	second := distributor.NewQuoteStream()
+	assert.NoError(t, second.Subscribe(1))
+
+	snapshot := <-second.Chan()
+	for volume := snapshot.TradedVolume.Mantissa + 1; volume <= numQuotes; volume++ {
+		assert.Equal(t, volume, (<-second.Chan()).TradedVolume.Mantissa)
+	}
+}
+
+func TestConflatedStreamOnlyUnsubscribesFromSubscribedListings(t *testing.T) {
+	source := newTestQuoteStream()
+	stream := newConflatedQuoteStream("test", source, 1)
+
+	assert.NoError(t, stream.Unsubscribe(1))
+	assert.Empty(t, source.unsubscribed)
+
+	assert.NoError(t, stream.Subscribe(1))
+	assert.NoError(t, stream.Subscribe(1))
+	assert.Error(t, stream.Subscribe(2))
+	assert.Len(t, source.subscribed, 1)
+
+	assert.NoError(t, stream.Unsubscribe(1))
+	assert.Equal(t, int32(1), <-source.unsubscribed)
+
+	assert.NoError(t, stream.Subscribe(2))
+
+	stream.Close()
+	assert.True(t, source.closed)
+}
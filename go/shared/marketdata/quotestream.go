@@ -0,0 +1,15 @@
+// Package marketdata contains the quote streams, quote distribution and market data source api implementation shared by
+// the market data services.  It is based on the otp-common marketdata package and extends it with the release of
+// subscriptions, which the otp-common market data apis do not support.
+package marketdata
+
+import "github.com/ettec/otp-common/model"
+
+// QuoteStream is a stream of quotes for the listings subscribed to, it extends the otp-common quote stream with the
+// ability to release a subscription.
+type QuoteStream interface {
+	Subscribe(listingId int32) error
+	Unsubscribe(listingId int32) error
+	Chan() <-chan *model.ClobQuote
+	Close()
+}
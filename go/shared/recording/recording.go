@@ -0,0 +1,108 @@
+// Package recording reads and writes recordings of quote streams.
+//
+// A recording is a gzip compressed sequence of records, each record being the time the quote was received as a big
+// endian int64 of nanoseconds since the unix epoch, followed by the length of the marshalled quote as a big endian
+// uint32 and the marshalled quote.  A recording may hold several gzip members, so a day's file is appended to when a
+// recorder is restarted.
+package recording
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+const recordHeaderSize = 12
+
+// Record is a quote and the time it was received.
+type Record struct {
+	Timestamp time.Time
+	Quote     *model.ClobQuote
+}
+
+// FileName returns the path of the file in the directory that holds the recording of the given UTC day.
+func FileName(dir string, day time.Time) string {
+	return filepath.Join(dir, "quotes-"+day.UTC().Format("2006-01-02")+".rec.gz")
+}
+
+type Writer struct {
+	gz *gzip.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{gz: gzip.NewWriter(w)}
+}
+
+func (w *Writer) Write(timestamp time.Time, quote *model.ClobQuote) error {
+	bytes, err := proto.Marshal(quote)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quote: %w", err)
+	}
+
+	header := make([]byte, recordHeaderSize)
+	binary.BigEndian.PutUint64(header, uint64(timestamp.UnixNano()))
+	binary.BigEndian.PutUint32(header[8:], uint32(len(bytes)))
+
+	if _, err := w.gz.Write(header); err != nil {
+		return fmt.Errorf("failed to write record header: %w", err)
+	}
+
+	if _, err := w.gz.Write(bytes); err != nil {
+		return fmt.Errorf("failed to write quote: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Writer) Flush() error {
+	return w.gz.Flush()
+}
+
+// Close flushes the writer and completes the gzip member, it does not close the underlying writer.
+func (w *Writer) Close() error {
+	return w.gz.Close()
+}
+
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+
+	return &Reader{r: bufio.NewReader(gz)}, nil
+}
+
+// Read returns the next record, io.EOF is returned at the end of the recording and io.ErrUnexpectedEOF if the recording
+// ends part way through a record, as happens if the recorder was stopped without being closed.
+func (r *Reader) Read() (Record, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		return Record{}, err
+	}
+
+	bytes := make([]byte, binary.BigEndian.Uint32(header[8:]))
+	if _, err := io.ReadFull(r.r, bytes); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.ErrUnexpectedEOF
+		}
+		return Record{}, err
+	}
+
+	quote := &model.ClobQuote{}
+	if err := proto.Unmarshal(bytes, quote); err != nil {
+		return Record{}, fmt.Errorf("failed to unmarshal quote: %w", err)
+	}
+
+	return Record{Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(header))), Quote: quote}, nil
+}
@@ -0,0 +1,87 @@
+package recording
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func writeRecording(t *testing.T, buf *bytes.Buffer, records ...Record) {
+	writer := NewWriter(buf)
+	for _, record := range records {
+		assert.NoError(t, writer.Write(record.Timestamp, record.Quote))
+	}
+	assert.NoError(t, writer.Close())
+}
+
+func readAll(t *testing.T, r io.Reader) ([]Record, error) {
+	reader, err := NewReader(r)
+	assert.NoError(t, err)
+
+	var records []Record
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestRecordingsAreReadInTheOrderWritten(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	first := Record{Timestamp: start, Quote: &model.ClobQuote{ListingId: 1, LastPrice: model.IasD(100)}}
+	second := Record{Timestamp: start.Add(time.Millisecond), Quote: &model.ClobQuote{ListingId: 2}}
+	third := Record{Timestamp: start.Add(time.Second), Quote: &model.ClobQuote{ListingId: 1, LastPrice: model.IasD(101)}}
+
+	// a restarted recorder appends a further gzip member to the day's file
+	buf := &bytes.Buffer{}
+	writeRecording(t, buf, first, second)
+	writeRecording(t, buf, third)
+
+	records, err := readAll(t, buf)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 3) {
+		for i, want := range []Record{first, second, third} {
+			assert.True(t, want.Timestamp.Equal(records[i].Timestamp))
+			assert.True(t, proto.Equal(want.Quote, records[i].Quote))
+		}
+	}
+}
+
+func TestRecordingEndingPartWayThroughARecordIsUnexpectedEOF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writeRecording(t, buf, Record{Timestamp: time.Now(), Quote: &model.ClobQuote{ListingId: 1}},
+		Record{Timestamp: time.Now(), Quote: &model.ClobQuote{ListingId: 2, LastPrice: model.IasD(100)}})
+
+	gz, err := gzip.NewReader(buf)
+	assert.NoError(t, err)
+	uncompressed, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+
+	// drop the end of the last record, as happens if the recorder is stopped without being closed
+	truncated := &bytes.Buffer{}
+	gzw := gzip.NewWriter(truncated)
+	_, err = gzw.Write(uncompressed[:len(uncompressed)-1])
+	assert.NoError(t, err)
+	assert.NoError(t, gzw.Close())
+
+	records, err := readAll(t, truncated)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, int32(1), records[0].Quote.ListingId)
+	}
+}
+
+func TestFileNameIsTheUTCDay(t *testing.T) {
+	day := time.Date(2024, 3, 1, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+	assert.Equal(t, "dir/quotes-2024-03-02.rec.gz", FileName("dir", day))
+}
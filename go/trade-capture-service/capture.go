@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/trade-capture-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"log/slog"
+	"time"
+)
+
+// orderUpdate is an order update read from the orders topic.
+type orderUpdate struct {
+	order     *model.Order
+	writeTime time.Time
+}
+
+// tradeCapture derives the trades from the updates of each order and publishes those not already in the store.  Every
+// update in the orders topic is processed on start, the trades of updates processed before a restart are already in the
+// store and are not published again.
+type tradeCapture struct {
+	store   *tradeStore
+	publish func(ctx context.Context, trade *api.Trade) error
+}
+
+func newTradeCapture(store *tradeStore, publish func(ctx context.Context, trade *api.Trade) error) *tradeCapture {
+	return &tradeCapture{store: store, publish: publish}
+}
+
+// run captures the trades of the order updates until the context is cancelled or the updates channel is closed.
+func (c *tradeCapture) run(ctx context.Context, updates <-chan orderUpdate) error {
+
+	orders := map[string]*model.Order{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return errors.New("order updates channel closed")
+			}
+
+			current := update.order
+			previous, exists := orders[current.Id]
+			orders[current.Id] = current
+
+			if !exists && current.Version > 0 {
+				// the earlier versions of the order are beyond the orders topic's retention period, so the changes
+				// to the order cannot be determined from this version
+				slog.Info("earliest available version of order is not its first version, trades will be captured from the next version",
+					"orderId", current.Id, "version", current.Version)
+				continue
+			}
+
+			for _, trade := range getTrades(previous, current, update.writeTime) {
+				if c.store.contains(trade.Id) {
+					continue
+				}
+
+				if err := c.publish(ctx, trade); err != nil {
+					return fmt.Errorf("failed to publish trade %v: %w", trade.Id, err)
+				}
+
+				c.store.add(trade)
+				slog.Info("trade captured", "tradeId", trade.Id, "orderId", trade.OrderId,
+					"quantity", trade.Quantity.AsDecimal(), "price", trade.Price.AsDecimal())
+			}
+		}
+	}
+}
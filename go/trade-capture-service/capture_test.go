@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	api "github.com/ettec/open-trading-platform/go/trade-capture-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func runCapture(store *tradeStore, publish func(ctx context.Context, trade *api.Trade) error,
+	orders ...*model.Order) error {
+	updates := make(chan orderUpdate, len(orders))
+	for _, order := range orders {
+		updates <- orderUpdate{order: order, writeTime: time.Now()}
+	}
+	close(updates)
+
+	return newTradeCapture(store, publish).run(context.Background(), updates)
+}
+
+func TestCapturePublishesTradesOfEachOrderVersion(t *testing.T) {
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+	v2 := nextVersion(v1, addExecution(t, "exec2", 101, 6))
+	other := newTestOrder("order2")
+
+	var published []*api.Trade
+	store := newTradeStore(nil)
+	err := runCapture(store, func(ctx context.Context, trade *api.Trade) error {
+		published = append(published, trade)
+		return nil
+	}, v0, other, v1, v2)
+
+	assert.EqualError(t, err, "order updates channel closed")
+	assert.Equal(t, []string{"order1.exec1", "order1.exec2"}, tradeIds(published))
+	assert.Equal(t, []string{"order1.exec1", "order1.exec2"}, tradeIds(store.getTrades(&api.TradeFilter{})))
+}
+
+func TestCaptureDoesNotRepublishTradesAlreadyInTheStore(t *testing.T) {
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+	v2 := nextVersion(v1, addExecution(t, "exec2", 101, 6))
+
+	var published []*api.Trade
+	store := newTradeStore([]*api.Trade{{Id: "order1.exec1"}})
+	_ = runCapture(store, func(ctx context.Context, trade *api.Trade) error {
+		published = append(published, trade)
+		return nil
+	}, v0, v1, v2)
+
+	assert.Equal(t, []string{"order1.exec2"}, tradeIds(published))
+}
+
+func TestCaptureStartsFromNextVersionWhenFirstVersionIsUnavailable(t *testing.T) {
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+	v2 := nextVersion(v1, addExecution(t, "exec2", 101, 6))
+
+	var published []*api.Trade
+	_ = runCapture(newTradeStore(nil), func(ctx context.Context, trade *api.Trade) error {
+		published = append(published, trade)
+		return nil
+	}, v1, v2)
+
+	assert.Equal(t, []string{"order1.exec2"}, tradeIds(published))
+}
+
+func TestCaptureFailsWhenTradeCannotBePublished(t *testing.T) {
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+
+	store := newTradeStore(nil)
+	err := runCapture(store, func(ctx context.Context, trade *api.Trade) error {
+		return errors.New("kafka unavailable")
+	}, v0, v1)
+
+	assert.Contains(t, err.Error(), "kafka unavailable")
+	assert.False(t, store.contains("order1.exec1"))
+}
+
+type testMessageReader struct {
+	messages []kafka.Message
+}
+
+func (r *testMessageReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func TestLoadTradesStopsAtTradeWrittenAfterStart(t *testing.T) {
+	reader := &testMessageReader{}
+	for _, trade := range []*api.Trade{{Id: "t1"}, {Id: "t2"}, {Id: "t3"}} {
+		value, err := proto.Marshal(trade)
+		assert.NoError(t, err)
+		reader.messages = append(reader.messages, kafka.Message{Key: []byte(trade.Id), Value: value})
+	}
+	reader.messages[0].Time = time.Now().Add(-time.Hour)
+	reader.messages[1].Time = time.Now().Add(time.Hour)
+
+	trades, err := loadTrades(context.Background(), reader)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2"}, tradeIds(trades))
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/trade-capture-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
+	"log/slog"
+	"time"
+)
+
+const tradesTopic = "trades"
+
+// maxTimeBetweenMessages is the time after which the end of the trades topic is considered to have been reached, the
+// kafka-go api does not provide a way to determine if the end of a topic has been reached.
+const maxTimeBetweenMessages = 5 * time.Second
+
+// streamOrderUpdates returns a channel of all the order updates in the orders topic from the first available offset,
+// the channel is closed if the context is cancelled or an update cannot be read.
+func streamOrderUpdates(ctx context.Context, readerConfig kafka.ReaderConfig, bufferSize int) <-chan orderUpdate {
+	out := make(chan orderUpdate, bufferSize)
+
+	go func() {
+		defer close(out)
+		reader := kafka.NewReader(readerConfig)
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing kafka reader", "error", err)
+			}
+		}()
+
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("failed to read order update", "error", err)
+				}
+				return
+			}
+
+			order := &model.Order{}
+			if err = proto.Unmarshal(msg.Value, order); err != nil {
+				slog.Error("failed to unmarshal order", "offset", msg.Offset, "error", err)
+				return
+			}
+
+			select {
+			case out <- orderUpdate{order: order, writeTime: msg.Time}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+type messageReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+}
+
+// loadTrades reads the trades in the trades topic until a trade written after the load started is read or no trade is
+// read within maxTimeBetweenMessages.
+func loadTrades(ctx context.Context, reader messageReader) ([]*api.Trade, error) {
+	var trades []*api.Trade
+	startTime := time.Now()
+
+	for {
+		deadline, cancel := context.WithDeadline(ctx, time.Now().Add(maxTimeBetweenMessages))
+		msg, err := reader.ReadMessage(deadline)
+		cancel()
+
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read trade: %w", err)
+		}
+
+		trade := &api.Trade{}
+		if err = proto.Unmarshal(msg.Value, trade); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trade at offset %v: %w", msg.Offset, err)
+		}
+
+		trades = append(trades, trade)
+
+		if msg.Time.After(startTime) {
+			break
+		}
+	}
+
+	slog.Info("trades loaded", "trades", len(trades))
+	return trades, nil
+}
+
+type kafkaTradeWriter struct {
+	writer *kafka.Writer
+}
+
+// newKafkaTradeWriter returns a writer of trades to the trades topic, the writes are synchronous so that a trade is
+// only considered captured once it has been written to the topic.
+func newKafkaTradeWriter(kafkaBrokers []string) *kafkaTradeWriter {
+	return &kafkaTradeWriter{writer: kafka.NewWriter(kafka.WriterConfig{
+		Brokers:      kafkaBrokers,
+		Topic:        tradesTopic,
+		Balancer:     &kafka.LeastBytes{},
+		BatchTimeout: 10 * time.Millisecond,
+	})}
+}
+
+// write writes the trade to the trades topic keyed by trade id.
+func (k *kafkaTradeWriter) write(ctx context.Context, trade *api.Trade) error {
+	tradeBytes, err := proto.Marshal(trade)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trade: %w", err)
+	}
+
+	if err = k.writer.WriteMessages(ctx, kafka.Message{Key: []byte(trade.Id), Value: tradeBytes}); err != nil {
+		return fmt.Errorf("failed to write trade to kafka: %w", err)
+	}
+
+	return nil
+}
+
+func (k *kafkaTradeWriter) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/trade-capture-service/api/tradeservice"
+	common "github.com/ettec/otp-common"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/orderstore"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+type service struct {
+	store              *tradeStore
+	toClientBufferSize int
+}
+
+func newService(store *tradeStore, toClientBufferSize int) *service {
+	return &service{store: store, toClientBufferSize: toClientBufferSize}
+}
+
+func (s *service) GetTrades(_ context.Context, filter *api.TradeFilter) (*api.Trades, error) {
+	return &api.Trades{Trades: s.store.getTrades(filter)}, nil
+}
+
+func (s *service) SubscribeToTrades(filter *api.TradeFilter, stream api.TradeService_SubscribeToTradesServer) error {
+	slog.Info("subscribing to trades", "filter", filter)
+
+	trades, subscription := s.store.subscribe(filter, s.toClientBufferSize)
+	defer func() {
+		s.store.unsubscribe(subscription)
+		slog.Info("unsubscribed from trades", "filter", filter)
+	}()
+
+	for _, trade := range trades {
+		if err := stream.Send(trade); err != nil {
+			return fmt.Errorf("failed to send trade: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case trade, ok := <-subscription.trades:
+			if !ok {
+				return s.store.error(subscription)
+			}
+
+			if err := stream.Send(trade); err != nil {
+				return fmt.Errorf("failed to send trade: %w", err)
+			}
+		}
+	}
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+	toClientBufferSize := bootstrap.GetOptionalIntEnvVar("TO_CLIENT_BUFFER_SIZE", 1000)
+	orderUpdatesBufferSize := bootstrap.GetOptionalIntEnvVar("ORDER_UPDATES_BUFFER_SIZE", 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tradesReader := kafka.NewReader(orderstore.DefaultReaderConfig(tradesTopic, kafkaBrokers))
+	trades, err := loadTrades(ctx, tradesReader)
+	if err != nil {
+		log.Panicf("failed to load trades: %v", err)
+	}
+	if err = tradesReader.Close(); err != nil {
+		slog.Error("error closing kafka reader", "error", err)
+	}
+
+	store := newTradeStore(trades)
+
+	tradeWriter := newKafkaTradeWriter(kafkaBrokers)
+	defer func() {
+		if err := tradeWriter.Close(); err != nil {
+			slog.Error("error closing kafka writer", "error", err)
+		}
+	}()
+
+	port := "50551"
+	slog.Info("Starting trade capture service", "port", port)
+	listener, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	s := grpc.NewServer()
+	api.RegisterTradeServiceServer(s, newService(store, toClientBufferSize))
+	reflection.Register(s)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+		s.GracefulStop()
+	}()
+
+	go func() {
+		updates := streamOrderUpdates(ctx, orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers),
+			orderUpdatesBufferSize)
+		if err := newTradeCapture(store, tradeWriter.write).run(ctx, updates); err != nil {
+			log.Panicf("trade capture failed: %v", err)
+		}
+	}()
+
+	if err := s.Serve(listener); err != nil {
+		log.Panicf("Error while serving : %v", err)
+	}
+}
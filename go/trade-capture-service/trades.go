@@ -0,0 +1,80 @@
+package main
+
+import (
+	api "github.com/ettec/open-trading-platform/go/trade-capture-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// reversalPricePlaces is the number of decimal places to which the price of a reversal derived from the change in an
+// order's traded value is rounded, the order's average price is itself rounded so the derived price may not be exact.
+const reversalPricePlaces = 8
+
+// getTrades returns the trades for the change between the previous and current versions of an order, previous is nil
+// for a new order.  A new execution id on the order is a trade of the order's last execution, the execution venue
+// reports a cancelled execution as an execution of negative quantity and a corrected execution as a single execution
+// of the corrected quantity, so any change in the order's traded quantity not accounted for by its last execution is
+// captured as the reversal of the corrected execution.
+func getTrades(previous *model.Order, current *model.Order, tradeTime time.Time) []*api.Trade {
+
+	var previousLastExecId string
+	if previous != nil {
+		previousLastExecId = previous.LastExecId
+	}
+
+	if current.LastExecId == "" || current.LastExecId == previousLastExecId {
+		return nil
+	}
+
+	lastQty := current.GetLastExecQuantity().AsDecimal()
+	lastPrice := current.GetLastExecPrice().AsDecimal()
+
+	var trades []*api.Trade
+
+	tradedQtyChange := current.GetTradedQuantity().AsDecimal().Sub(previous.GetTradedQuantity().AsDecimal())
+	reversedQty := tradedQtyChange.Sub(lastQty)
+	if !reversedQty.Equal(decimal.New(0, 0)) {
+		tradedValueChange := tradedValue(current).Sub(tradedValue(previous))
+		reversedValue := tradedValueChange.Sub(lastPrice.Mul(lastQty))
+		reversedPrice := reversedValue.Div(reversedQty).Round(reversalPricePlaces)
+
+		trades = append(trades, newTrade(current, current.LastExecId+".reversal", reversedPrice, reversedQty, tradeTime))
+	}
+
+	if !lastQty.Equal(decimal.New(0, 0)) {
+		trades = append(trades, newTrade(current, current.LastExecId, lastPrice, lastQty, tradeTime))
+	}
+
+	return trades
+}
+
+func tradedValue(order *model.Order) decimal.Decimal {
+	return order.GetAvgTradePrice().AsDecimal().Mul(order.GetTradedQuantity().AsDecimal())
+}
+
+func newTrade(order *model.Order, execId string, price decimal.Decimal, qty decimal.Decimal, tradeTime time.Time) *api.Trade {
+	return &api.Trade{
+		Id:                getTradeId(order.Id, execId),
+		OrderId:           order.Id,
+		OrderVersion:      order.Version,
+		ExecId:            execId,
+		Side:              order.Side,
+		ListingId:         order.ListingId,
+		Price:             model.ToDecimal64(price),
+		Quantity:          model.ToDecimal64(qty),
+		OriginatorId:      order.OriginatorId,
+		OriginatorRef:     order.OriginatorRef,
+		RootOriginatorId:  order.RootOriginatorId,
+		RootOriginatorRef: order.RootOriginatorRef,
+		OwnerId:           order.OwnerId,
+		Destination:       order.Destination,
+		TradeTime:         model.NewTimeStamp(tradeTime),
+	}
+}
+
+// getTradeId returns the id of a trade, execution ids are unique only within an execution venue so the trade id is
+// qualified by the id of the order.
+func getTradeId(orderId string, execId string) string {
+	return orderId + "." + execId
+}
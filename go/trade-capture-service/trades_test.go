@@ -0,0 +1,123 @@
+package main
+
+import (
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestOrder(id string) *model.Order {
+	order := model.NewOrder(id, model.Side_BUY, model.IasD(10), model.IasD(100), 1, "desk1", "trader1",
+		"rootDesk", "rootTrader", "XNAS")
+	order.OwnerId = "XNAS"
+	_ = order.SetTargetStatus(model.OrderStatus_LIVE)
+	return order
+}
+
+func nextVersion(order *model.Order, update func(order *model.Order)) *model.Order {
+	next := proto.Clone(order).(*model.Order)
+	next.Version++
+	update(next)
+	return next
+}
+
+func addExecution(t *testing.T, id string, price int, qty int) func(order *model.Order) {
+	return func(order *model.Order) {
+		assert.NoError(t, order.AddExecution(model.Execution{Id: id, Price: *model.IasD(price), Qty: *model.IasD(qty)}))
+	}
+}
+
+func TestNewExecutionIsATrade(t *testing.T) {
+	tradeTime := time.Unix(1000, 0)
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+
+	trades := getTrades(v0, v1, tradeTime)
+
+	assert.Len(t, trades, 1)
+	trade := trades[0]
+	assert.Equal(t, "order1.exec1", trade.Id)
+	assert.Equal(t, "order1", trade.OrderId)
+	assert.Equal(t, int32(1), trade.OrderVersion)
+	assert.Equal(t, "exec1", trade.ExecId)
+	assert.Equal(t, model.Side_BUY, trade.Side)
+	assert.Equal(t, int32(1), trade.ListingId)
+	assert.True(t, trade.Price.Equal(model.IasD(100)))
+	assert.True(t, trade.Quantity.Equal(model.IasD(4)))
+	assert.Equal(t, "desk1", trade.OriginatorId)
+	assert.Equal(t, "trader1", trade.OriginatorRef)
+	assert.Equal(t, "rootDesk", trade.RootOriginatorId)
+	assert.Equal(t, "rootTrader", trade.RootOriginatorRef)
+	assert.Equal(t, "XNAS", trade.OwnerId)
+	assert.Equal(t, "XNAS", trade.Destination)
+	assert.Equal(t, model.NewTimeStamp(tradeTime), trade.TradeTime)
+}
+
+func TestFirstVersionOfOrderWithExecution(t *testing.T) {
+	v0 := newTestOrder("order1")
+	addExecution(t, "exec1", 100, 4)(v0)
+
+	trades := getTrades(nil, v0, time.Now())
+
+	assert.Len(t, trades, 1)
+	assert.Equal(t, "order1.exec1", trades[0].Id)
+	assert.True(t, trades[0].Quantity.Equal(model.IasD(4)))
+}
+
+func TestOrderChangeWithoutExecutionIsNotATrade(t *testing.T) {
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+	v2 := nextVersion(v1, func(order *model.Order) {
+		order.ErrorMessage = "an error"
+	})
+
+	assert.Empty(t, getTrades(nil, v0, time.Now()))
+	assert.Empty(t, getTrades(v1, v2, time.Now()))
+}
+
+func TestCancelledExecutionIsANegativeTrade(t *testing.T) {
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+	v2 := nextVersion(v1, func(order *model.Order) {
+		order.TradedQuantity = model.IasD(0)
+		order.RemainingQuantity = model.IasD(10)
+		order.AvgTradePrice = model.IasD(0)
+		order.LastExecId = "bust1"
+		order.LastExecPrice = model.IasD(100)
+		order.LastExecQuantity = model.IasD(-4)
+	})
+
+	trades := getTrades(v1, v2, time.Now())
+
+	assert.Len(t, trades, 1)
+	assert.Equal(t, "order1.bust1", trades[0].Id)
+	assert.True(t, trades[0].Price.Equal(model.IasD(100)))
+	assert.True(t, trades[0].Quantity.Equal(model.IasD(-4)))
+}
+
+func TestCorrectedExecutionIsAReversalAndATrade(t *testing.T) {
+	v0 := newTestOrder("order1")
+	v1 := nextVersion(v0, addExecution(t, "exec1", 100, 4))
+	v2 := nextVersion(v1, addExecution(t, "exec2", 103, 2))
+	v3 := nextVersion(v2, func(order *model.Order) {
+		// exec2 corrected to 3@102, the order's traded quantity is 7 at an average of 100.857...
+		order.TradedQuantity = model.IasD(7)
+		order.RemainingQuantity = model.IasD(3)
+		order.AvgTradePrice = model.ToDecimal64(model.IasD(706).AsDecimal().Div(model.IasD(7).AsDecimal()))
+		order.LastExecId = "correct1"
+		order.LastExecPrice = model.IasD(102)
+		order.LastExecQuantity = model.IasD(3)
+	})
+
+	trades := getTrades(v2, v3, time.Now())
+
+	assert.Len(t, trades, 2)
+	assert.Equal(t, "order1.correct1.reversal", trades[0].Id)
+	assert.True(t, trades[0].Price.Equal(model.IasD(103)), "reversal price %v", trades[0].Price.AsDecimal())
+	assert.True(t, trades[0].Quantity.Equal(model.IasD(-2)))
+	assert.Equal(t, "order1.correct1", trades[1].Id)
+	assert.True(t, trades[1].Price.Equal(model.IasD(102)))
+	assert.True(t, trades[1].Quantity.Equal(model.IasD(3)))
+}
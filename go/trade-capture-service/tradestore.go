@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	api "github.com/ettec/open-trading-platform/go/trade-capture-service/api/tradeservice"
+	"sync"
+)
+
+var errSubscriberTooSlow = errors.New("subscriber is not keeping up with the trade stream")
+
+// tradeStore holds the captured trades in the order they were captured and distributes new trades to subscribers.
+type tradeStore struct {
+	mutex       sync.Mutex
+	trades      []*api.Trade
+	ids         map[string]bool
+	subscribers map[*tradeSubscription]bool
+}
+
+// tradeSubscription receives the new trades matching its filter, if the subscription's buffer is full the subscription
+// is closed with errSubscriberTooSlow rather than holding up the capture of trades.
+type tradeSubscription struct {
+	filter *api.TradeFilter
+	trades chan *api.Trade
+	err    error
+}
+
+func newTradeStore(trades []*api.Trade) *tradeStore {
+	s := &tradeStore{
+		ids:         map[string]bool{},
+		subscribers: map[*tradeSubscription]bool{},
+	}
+
+	for _, trade := range trades {
+		if !s.ids[trade.Id] {
+			s.ids[trade.Id] = true
+			s.trades = append(s.trades, trade)
+		}
+	}
+
+	return s
+}
+
+func (s *tradeStore) contains(tradeId string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.ids[tradeId]
+}
+
+// add adds the trade to the store and sends it to the matching subscribers, it returns false if a trade with the same
+// id is already in the store.
+func (s *tradeStore) add(trade *api.Trade) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.ids[trade.Id] {
+		return false
+	}
+
+	s.ids[trade.Id] = true
+	s.trades = append(s.trades, trade)
+
+	for subscription := range s.subscribers {
+		if !matches(subscription.filter, trade) {
+			continue
+		}
+
+		select {
+		case subscription.trades <- trade:
+		default:
+			subscription.err = errSubscriberTooSlow
+			s.closeSubscription(subscription)
+		}
+	}
+
+	return true
+}
+
+// getTrades returns the trades that match the filter in the order they were captured.
+func (s *tradeStore) getTrades(filter *api.TradeFilter) []*api.Trade {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.getMatchingTrades(filter)
+}
+
+// subscribe returns the trades in the store that match the filter and a subscription to the matching trades added
+// after them.
+func (s *tradeStore) subscribe(filter *api.TradeFilter, bufferSize int) ([]*api.Trade, *tradeSubscription) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	subscription := &tradeSubscription{filter: filter, trades: make(chan *api.Trade, bufferSize)}
+	s.subscribers[subscription] = true
+
+	return s.getMatchingTrades(filter), subscription
+}
+
+func (s *tradeStore) unsubscribe(subscription *tradeSubscription) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closeSubscription(subscription)
+}
+
+func (s *tradeStore) closeSubscription(subscription *tradeSubscription) {
+	if s.subscribers[subscription] {
+		delete(s.subscribers, subscription)
+		close(subscription.trades)
+	}
+}
+
+// error returns the reason the subscription was closed by the store, it is valid once the subscription's trades
+// channel is closed.
+func (s *tradeStore) error(subscription *tradeSubscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return subscription.err
+}
+
+func (s *tradeStore) getMatchingTrades(filter *api.TradeFilter) []*api.Trade {
+	var result []*api.Trade
+	for _, trade := range s.trades {
+		if matches(filter, trade) {
+			result = append(result, trade)
+		}
+	}
+
+	return result
+}
+
+func matches(filter *api.TradeFilter, trade *api.Trade) bool {
+	if filter.OrderId != "" && filter.OrderId != trade.OrderId {
+		return false
+	}
+
+	if filter.ListingId != 0 && filter.ListingId != trade.ListingId {
+		return false
+	}
+
+	if filter.OriginatorId != "" && filter.OriginatorId != trade.OriginatorId {
+		return false
+	}
+
+	if filter.RootOriginatorId != "" && filter.RootOriginatorId != trade.RootOriginatorId {
+		return false
+	}
+
+	if filter.From != nil && trade.TradeTime.Before(filter.From) {
+		return false
+	}
+
+	if filter.To != nil && trade.TradeTime.After(filter.To) {
+		return false
+	}
+
+	return true
+}
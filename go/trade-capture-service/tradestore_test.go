@@ -0,0 +1,97 @@
+package main
+
+import (
+	api "github.com/ettec/open-trading-platform/go/trade-capture-service/api/tradeservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestTrade(id string, orderId string, listingId int32, originatorId string, seconds int64) *api.Trade {
+	return &api.Trade{Id: id, OrderId: orderId, ListingId: listingId, OriginatorId: originatorId,
+		RootOriginatorId: originatorId, TradeTime: &model.Timestamp{Seconds: seconds}}
+}
+
+func tradeIds(trades []*api.Trade) []string {
+	var ids []string
+	for _, trade := range trades {
+		ids = append(ids, trade.Id)
+	}
+	return ids
+}
+
+func TestGetTrades(t *testing.T) {
+	store := newTradeStore([]*api.Trade{
+		newTestTrade("t1", "o1", 1, "desk1", 10),
+		newTestTrade("t2", "o2", 2, "desk1", 20),
+		newTestTrade("t3", "o3", 1, "desk2", 30),
+		newTestTrade("t1", "o1", 1, "desk1", 10),
+	})
+
+	tests := []struct {
+		name   string
+		filter *api.TradeFilter
+		want   []string
+	}{
+		{"all", &api.TradeFilter{}, []string{"t1", "t2", "t3"}},
+		{"order", &api.TradeFilter{OrderId: "o2"}, []string{"t2"}},
+		{"listing", &api.TradeFilter{ListingId: 1}, []string{"t1", "t3"}},
+		{"originator", &api.TradeFilter{OriginatorId: "desk1"}, []string{"t1", "t2"}},
+		{"root originator", &api.TradeFilter{RootOriginatorId: "desk2"}, []string{"t3"}},
+		{"from", &api.TradeFilter{From: &model.Timestamp{Seconds: 20}}, []string{"t2", "t3"}},
+		{"to", &api.TradeFilter{To: &model.Timestamp{Seconds: 20}}, []string{"t1", "t2"}},
+		{"listing and time range", &api.TradeFilter{ListingId: 1, From: &model.Timestamp{Seconds: 11},
+			To: &model.Timestamp{Seconds: 30}}, []string{"t3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tradeIds(store.getTrades(tt.filter)))
+		})
+	}
+}
+
+func TestAddIgnoresDuplicateTrades(t *testing.T) {
+	store := newTradeStore([]*api.Trade{newTestTrade("t1", "o1", 1, "desk1", 10)})
+
+	assert.False(t, store.add(newTestTrade("t1", "o1", 1, "desk1", 10)))
+	assert.True(t, store.add(newTestTrade("t2", "o1", 1, "desk1", 10)))
+	assert.True(t, store.contains("t2"))
+	assert.Equal(t, []string{"t1", "t2"}, tradeIds(store.getTrades(&api.TradeFilter{})))
+}
+
+func TestSubscribeReturnsExistingTradesThenNewTrades(t *testing.T) {
+	store := newTradeStore([]*api.Trade{
+		newTestTrade("t1", "o1", 1, "desk1", 10),
+		newTestTrade("t2", "o2", 1, "desk2", 10),
+	})
+
+	existing, subscription := store.subscribe(&api.TradeFilter{OriginatorId: "desk1"}, 10)
+	assert.Equal(t, []string{"t1"}, tradeIds(existing))
+
+	store.add(newTestTrade("t3", "o3", 1, "desk2", 20))
+	store.add(newTestTrade("t4", "o4", 1, "desk1", 20))
+
+	assert.Equal(t, "t4", (<-subscription.trades).Id)
+
+	store.unsubscribe(subscription)
+	_, ok := <-subscription.trades
+	assert.False(t, ok)
+	assert.NoError(t, store.error(subscription))
+}
+
+func TestSlowSubscriberIsClosed(t *testing.T) {
+	store := newTradeStore(nil)
+
+	_, subscription := store.subscribe(&api.TradeFilter{}, 1)
+
+	store.add(newTestTrade("t1", "o1", 1, "desk1", 10))
+	store.add(newTestTrade("t2", "o1", 1, "desk1", 10))
+
+	assert.Equal(t, "t1", (<-subscription.trades).Id)
+	_, ok := <-subscription.trades
+	assert.False(t, ok)
+	assert.Equal(t, errSubscriberTooSlow, store.error(subscription))
+
+	store.unsubscribe(subscription)
+}
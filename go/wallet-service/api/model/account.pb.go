@@ -0,0 +1,406 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: account.proto
+
+package model
+
+import (
+	fmt "fmt"
+	model "github.com/ettec/otp-common/model"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Account struct {
+	Id                   string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               string           `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string           `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Balance              float64          `protobuf:"fixed64,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	Available            float64          `protobuf:"fixed64,5,opt,name=available,proto3" json:"available,omitempty"`
+	Locked               float64          `protobuf:"fixed64,6,opt,name=locked,proto3" json:"locked,omitempty"`
+	LastUpdated          *model.Timestamp `protobuf:"bytes,7,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Account) Reset()         { *m = Account{} }
+func (m *Account) String() string { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()    {}
+func (*Account) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{0}
+}
+
+func (m *Account) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Account.Unmarshal(m, b)
+}
+func (m *Account) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Account.Marshal(b, m, deterministic)
+}
+func (m *Account) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Account.Merge(m, src)
+}
+func (m *Account) XXX_Size() int {
+	return xxx_messageInfo_Account.Size(m)
+}
+func (m *Account) XXX_DiscardUnknown() {
+	xxx_messageInfo_Account.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Account proto.InternalMessageInfo
+
+func (m *Account) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Account) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Account) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *Account) GetBalance() float64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *Account) GetAvailable() float64 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+func (m *Account) GetLocked() float64 {
+	if m != nil {
+		return m.Locked
+	}
+	return 0
+}
+
+func (m *Account) GetLastUpdated() *model.Timestamp {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return nil
+}
+
+type Balance struct {
+	UserId               string           `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string           `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Balance              float64          `protobuf:"fixed64,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	Available            float64          `protobuf:"fixed64,4,opt,name=available,proto3" json:"available,omitempty"`
+	Locked               float64          `protobuf:"fixed64,5,opt,name=locked,proto3" json:"locked,omitempty"`
+	LastUpdated          *model.Timestamp `protobuf:"bytes,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Balance) Reset()         { *m = Balance{} }
+func (m *Balance) String() string { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()    {}
+func (*Balance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{1}
+}
+
+func (m *Balance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Balance.Unmarshal(m, b)
+}
+func (m *Balance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Balance.Marshal(b, m, deterministic)
+}
+func (m *Balance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Balance.Merge(m, src)
+}
+func (m *Balance) XXX_Size() int {
+	return xxx_messageInfo_Balance.Size(m)
+}
+func (m *Balance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Balance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Balance proto.InternalMessageInfo
+
+func (m *Balance) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Balance) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *Balance) GetBalance() float64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *Balance) GetAvailable() float64 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+func (m *Balance) GetLocked() float64 {
+	if m != nil {
+		return m.Locked
+	}
+	return 0
+}
+
+func (m *Balance) GetLastUpdated() *model.Timestamp {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return nil
+}
+
+type GetBalanceRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceRequest) Reset()         { *m = GetBalanceRequest{} }
+func (m *GetBalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceRequest) ProtoMessage()    {}
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{2}
+}
+
+func (m *GetBalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceRequest.Unmarshal(m, b)
+}
+func (m *GetBalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceRequest.Merge(m, src)
+}
+func (m *GetBalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceRequest.Size(m)
+}
+func (m *GetBalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceRequest proto.InternalMessageInfo
+
+func (m *GetBalanceRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *GetBalanceRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+type GetBalanceResponse struct {
+	Balance              float64  `protobuf:"fixed64,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Available            float64  `protobuf:"fixed64,2,opt,name=available,proto3" json:"available,omitempty"`
+	Locked               float64  `protobuf:"fixed64,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceResponse) Reset()         { *m = GetBalanceResponse{} }
+func (m *GetBalanceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceResponse) ProtoMessage()    {}
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{3}
+}
+
+func (m *GetBalanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceResponse.Unmarshal(m, b)
+}
+func (m *GetBalanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceResponse.Merge(m, src)
+}
+func (m *GetBalanceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceResponse.Size(m)
+}
+func (m *GetBalanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceResponse proto.InternalMessageInfo
+
+func (m *GetBalanceResponse) GetBalance() float64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *GetBalanceResponse) GetAvailable() float64 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+func (m *GetBalanceResponse) GetLocked() float64 {
+	if m != nil {
+		return m.Locked
+	}
+	return 0
+}
+
+type Transaction struct {
+	Id                   string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId            string           `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Type                 string           `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Amount               float64          `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reference            string           `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
+	Timestamp            *model.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{4}
+}
+
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Transaction) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *Transaction) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Transaction) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *Transaction) GetReference() string {
+	if m != nil {
+		return m.Reference
+	}
+	return ""
+}
+
+func (m *Transaction) GetTimestamp() *model.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Account)(nil), "model.Account")
+	proto.RegisterType((*Balance)(nil), "model.Balance")
+	proto.RegisterType((*GetBalanceRequest)(nil), "model.GetBalanceRequest")
+	proto.RegisterType((*GetBalanceResponse)(nil), "model.GetBalanceResponse")
+	proto.RegisterType((*Transaction)(nil), "model.Transaction")
+}
+
+func init() { proto.RegisterFile("account.proto", fileDescriptor_8e28828dcb8d24f0) }
+
+var fileDescriptor_8e28828dcb8d24f0 = []byte{
+	// 355 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0xc1, 0x4e, 0xb3, 0x40,
+	0x14, 0x85, 0x33, 0xb4, 0x85, 0x9f, 0xdb, 0x5f, 0x63, 0x67, 0xa1, 0x93, 0x46, 0x93, 0x86, 0x55,
+	0x57, 0x2c, 0xec, 0x13, 0xd8, 0x8d, 0x76, 0x4b, 0xea, 0xc6, 0x4d, 0x33, 0x9d, 0xb9, 0x26, 0x44,
+	0x60, 0x90, 0x19, 0x4c, 0xfa, 0x62, 0xbe, 0x80, 0x8f, 0xe0, 0x0b, 0x19, 0x86, 0xa9, 0x90, 0x18,
+	0x1a, 0xe3, 0x0a, 0xce, 0x3d, 0x27, 0x0c, 0xdf, 0xcd, 0x19, 0x38, 0xe3, 0x42, 0xa8, 0xba, 0x30,
+	0x71, 0x59, 0x29, 0xa3, 0xe8, 0x24, 0x57, 0x12, 0xb3, 0xf9, 0xcc, 0x3e, 0x84, 0xca, 0x73, 0x55,
+	0xb4, 0x4e, 0xf4, 0x49, 0x20, 0xb8, 0x6b, 0xb3, 0xf4, 0x1c, 0xbc, 0x54, 0x32, 0xb2, 0x20, 0xcb,
+	0x30, 0xf1, 0x52, 0x49, 0xaf, 0x20, 0xa8, 0x35, 0x56, 0xbb, 0x54, 0x32, 0xcf, 0x0e, 0xfd, 0x46,
+	0x6e, 0x24, 0x9d, 0xc3, 0x3f, 0x51, 0x57, 0x15, 0x16, 0xe2, 0xc0, 0x46, 0xd6, 0xf9, 0xd6, 0x94,
+	0x41, 0xb0, 0xe7, 0x19, 0x2f, 0x04, 0xb2, 0xf1, 0x82, 0x2c, 0x49, 0x72, 0x94, 0xf4, 0x1a, 0x42,
+	0xfe, 0xc6, 0xd3, 0x8c, 0xef, 0x33, 0x64, 0x13, 0xeb, 0x75, 0x03, 0x7a, 0x09, 0x7e, 0xa6, 0xc4,
+	0x0b, 0x4a, 0xe6, 0x5b, 0xcb, 0x29, 0xba, 0x82, 0xff, 0x19, 0xd7, 0x66, 0x57, 0x97, 0x92, 0x1b,
+	0x94, 0x2c, 0x58, 0x90, 0xe5, 0xf4, 0xf6, 0x22, 0xb6, 0x28, 0xf1, 0x36, 0xcd, 0x51, 0x1b, 0x9e,
+	0x97, 0xc9, 0xb4, 0x49, 0x3d, 0xb6, 0xa1, 0xe8, 0x83, 0x40, 0xb0, 0x76, 0xc7, 0xf6, 0x28, 0xc8,
+	0x20, 0x85, 0x37, 0x4c, 0x31, 0x3a, 0x41, 0x31, 0x1e, 0xa6, 0x98, 0x9c, 0xa4, 0xf0, 0x7f, 0x43,
+	0xf1, 0x00, 0xb3, 0x7b, 0x34, 0x8e, 0x23, 0xc1, 0xd7, 0x1a, 0xb5, 0xf9, 0x13, 0x4e, 0x24, 0x81,
+	0xf6, 0xbf, 0xa4, 0x4b, 0x55, 0x68, 0xec, 0x43, 0x92, 0x13, 0x90, 0xde, 0x30, 0xe4, 0xa8, 0x0f,
+	0x19, 0xbd, 0x13, 0x98, 0x6e, 0x2b, 0x5e, 0x68, 0x2e, 0x4c, 0xaa, 0x8a, 0x1f, 0x7d, 0xba, 0x01,
+	0x70, 0xb5, 0xec, 0x2a, 0x15, 0xba, 0xc9, 0x46, 0x52, 0x0a, 0x63, 0x73, 0x28, 0xd1, 0x35, 0xca,
+	0xbe, 0x37, 0x47, 0xf1, 0xbc, 0xf1, 0xdd, 0xaa, 0x9d, 0x6a, 0x7e, 0xb0, 0xc2, 0x67, 0x6c, 0xe8,
+	0xda, 0x2e, 0x85, 0x49, 0x37, 0xa0, 0x31, 0x84, 0xe6, 0xb8, 0xd2, 0xc1, 0x55, 0x77, 0x91, 0x75,
+	0xf0, 0xd4, 0x5e, 0x90, 0xbd, 0x6f, 0x2f, 0xc5, 0xea, 0x6b, 0x00, 0x56, 0x5e, 0x93, 0x42, 0x3f,
+	0x03, 0x00, 0x00,
+}
@@ -0,0 +1,1492 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: wallet_service.proto
+
+package walletservice
+
+import (
+	context "context"
+	fmt "fmt"
+	model "github.com/ettec/open-trading-platform/go/wallet-service/api/model"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetBalanceRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceRequest) Reset()         { *m = GetBalanceRequest{} }
+func (m *GetBalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceRequest) ProtoMessage()    {}
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{0}
+}
+
+func (m *GetBalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceRequest.Unmarshal(m, b)
+}
+func (m *GetBalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceRequest.Merge(m, src)
+}
+func (m *GetBalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceRequest.Size(m)
+}
+func (m *GetBalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceRequest proto.InternalMessageInfo
+
+func (m *GetBalanceRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *GetBalanceRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+type GetBalanceResponse struct {
+	Balance              *model.Balance `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetBalanceResponse) Reset()         { *m = GetBalanceResponse{} }
+func (m *GetBalanceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceResponse) ProtoMessage()    {}
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{1}
+}
+
+func (m *GetBalanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceResponse.Unmarshal(m, b)
+}
+func (m *GetBalanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceResponse.Merge(m, src)
+}
+func (m *GetBalanceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceResponse.Size(m)
+}
+func (m *GetBalanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceResponse proto.InternalMessageInfo
+
+func (m *GetBalanceResponse) GetBalance() *model.Balance {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+type ListBalancesRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListBalancesRequest) Reset()         { *m = ListBalancesRequest{} }
+func (m *ListBalancesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListBalancesRequest) ProtoMessage()    {}
+func (*ListBalancesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{2}
+}
+
+func (m *ListBalancesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBalancesRequest.Unmarshal(m, b)
+}
+func (m *ListBalancesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBalancesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListBalancesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBalancesRequest.Merge(m, src)
+}
+func (m *ListBalancesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListBalancesRequest.Size(m)
+}
+func (m *ListBalancesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBalancesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBalancesRequest proto.InternalMessageInfo
+
+func (m *ListBalancesRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type ListBalancesResponse struct {
+	Balances             []*model.Balance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListBalancesResponse) Reset()         { *m = ListBalancesResponse{} }
+func (m *ListBalancesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListBalancesResponse) ProtoMessage()    {}
+func (*ListBalancesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{3}
+}
+
+func (m *ListBalancesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBalancesResponse.Unmarshal(m, b)
+}
+func (m *ListBalancesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBalancesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListBalancesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBalancesResponse.Merge(m, src)
+}
+func (m *ListBalancesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListBalancesResponse.Size(m)
+}
+func (m *ListBalancesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBalancesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBalancesResponse proto.InternalMessageInfo
+
+func (m *ListBalancesResponse) GetBalances() []*model.Balance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type DepositRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount               float64  `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Method               string   `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DepositRequest) Reset()         { *m = DepositRequest{} }
+func (m *DepositRequest) String() string { return proto.CompactTextString(m) }
+func (*DepositRequest) ProtoMessage()    {}
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{4}
+}
+
+func (m *DepositRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DepositRequest.Unmarshal(m, b)
+}
+func (m *DepositRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DepositRequest.Marshal(b, m, deterministic)
+}
+func (m *DepositRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DepositRequest.Merge(m, src)
+}
+func (m *DepositRequest) XXX_Size() int {
+	return xxx_messageInfo_DepositRequest.Size(m)
+}
+func (m *DepositRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DepositRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DepositRequest proto.InternalMessageInfo
+
+func (m *DepositRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *DepositRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *DepositRequest) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *DepositRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+type DepositResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	DepositId            string   `protobuf:"bytes,2,opt,name=deposit_id,json=depositId,proto3" json:"deposit_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DepositResponse) Reset()         { *m = DepositResponse{} }
+func (m *DepositResponse) String() string { return proto.CompactTextString(m) }
+func (*DepositResponse) ProtoMessage()    {}
+func (*DepositResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{5}
+}
+
+func (m *DepositResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DepositResponse.Unmarshal(m, b)
+}
+func (m *DepositResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DepositResponse.Marshal(b, m, deterministic)
+}
+func (m *DepositResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DepositResponse.Merge(m, src)
+}
+func (m *DepositResponse) XXX_Size() int {
+	return xxx_messageInfo_DepositResponse.Size(m)
+}
+func (m *DepositResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DepositResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DepositResponse proto.InternalMessageInfo
+
+func (m *DepositResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *DepositResponse) GetDepositId() string {
+	if m != nil {
+		return m.DepositId
+	}
+	return ""
+}
+
+type WithdrawRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount               float64  `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Destination          string   `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WithdrawRequest) Reset()         { *m = WithdrawRequest{} }
+func (m *WithdrawRequest) String() string { return proto.CompactTextString(m) }
+func (*WithdrawRequest) ProtoMessage()    {}
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{6}
+}
+
+func (m *WithdrawRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawRequest.Unmarshal(m, b)
+}
+func (m *WithdrawRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawRequest.Marshal(b, m, deterministic)
+}
+func (m *WithdrawRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawRequest.Merge(m, src)
+}
+func (m *WithdrawRequest) XXX_Size() int {
+	return xxx_messageInfo_WithdrawRequest.Size(m)
+}
+func (m *WithdrawRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawRequest proto.InternalMessageInfo
+
+func (m *WithdrawRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *WithdrawRequest) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+type WithdrawResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	WithdrawalId         string   `protobuf:"bytes,2,opt,name=withdrawal_id,json=withdrawalId,proto3" json:"withdrawal_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WithdrawResponse) Reset()         { *m = WithdrawResponse{} }
+func (m *WithdrawResponse) String() string { return proto.CompactTextString(m) }
+func (*WithdrawResponse) ProtoMessage()    {}
+func (*WithdrawResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{7}
+}
+
+func (m *WithdrawResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawResponse.Unmarshal(m, b)
+}
+func (m *WithdrawResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawResponse.Marshal(b, m, deterministic)
+}
+func (m *WithdrawResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawResponse.Merge(m, src)
+}
+func (m *WithdrawResponse) XXX_Size() int {
+	return xxx_messageInfo_WithdrawResponse.Size(m)
+}
+func (m *WithdrawResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawResponse proto.InternalMessageInfo
+
+func (m *WithdrawResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *WithdrawResponse) GetWithdrawalId() string {
+	if m != nil {
+		return m.WithdrawalId
+	}
+	return ""
+}
+
+type TransferRequest struct {
+	FromUserId           string   `protobuf:"bytes,1,opt,name=from_user_id,json=fromUserId,proto3" json:"from_user_id,omitempty"`
+	ToUserId             string   `protobuf:"bytes,2,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`
+	Currency             string   `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount               float64  `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferRequest) Reset()         { *m = TransferRequest{} }
+func (m *TransferRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferRequest) ProtoMessage()    {}
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{8}
+}
+
+func (m *TransferRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferRequest.Unmarshal(m, b)
+}
+func (m *TransferRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferRequest.Marshal(b, m, deterministic)
+}
+func (m *TransferRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferRequest.Merge(m, src)
+}
+func (m *TransferRequest) XXX_Size() int {
+	return xxx_messageInfo_TransferRequest.Size(m)
+}
+func (m *TransferRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferRequest proto.InternalMessageInfo
+
+func (m *TransferRequest) GetFromUserId() string {
+	if m != nil {
+		return m.FromUserId
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetToUserId() string {
+	if m != nil {
+		return m.ToUserId
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+type TransferResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	TransferId           string   `protobuf:"bytes,2,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferResponse) Reset()         { *m = TransferResponse{} }
+func (m *TransferResponse) String() string { return proto.CompactTextString(m) }
+func (*TransferResponse) ProtoMessage()    {}
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{9}
+}
+
+func (m *TransferResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferResponse.Unmarshal(m, b)
+}
+func (m *TransferResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferResponse.Marshal(b, m, deterministic)
+}
+func (m *TransferResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferResponse.Merge(m, src)
+}
+func (m *TransferResponse) XXX_Size() int {
+	return xxx_messageInfo_TransferResponse.Size(m)
+}
+func (m *TransferResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferResponse proto.InternalMessageInfo
+
+func (m *TransferResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *TransferResponse) GetTransferId() string {
+	if m != nil {
+		return m.TransferId
+	}
+	return ""
+}
+
+type GetTransactionHistoryRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Page                 int32    `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize             int32    `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionHistoryRequest) Reset()         { *m = GetTransactionHistoryRequest{} }
+func (m *GetTransactionHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionHistoryRequest) ProtoMessage()    {}
+func (*GetTransactionHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{10}
+}
+
+func (m *GetTransactionHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetTransactionHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTransactionHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionHistoryRequest.Merge(m, src)
+}
+func (m *GetTransactionHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionHistoryRequest.Size(m)
+}
+func (m *GetTransactionHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionHistoryRequest proto.InternalMessageInfo
+
+func (m *GetTransactionHistoryRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *GetTransactionHistoryRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *GetTransactionHistoryRequest) GetPage() int32 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+func (m *GetTransactionHistoryRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+type GetTransactionHistoryResponse struct {
+	Transactions         []*model.Transaction `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	TotalCount           int32                `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetTransactionHistoryResponse) Reset()         { *m = GetTransactionHistoryResponse{} }
+func (m *GetTransactionHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionHistoryResponse) ProtoMessage()    {}
+func (*GetTransactionHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{11}
+}
+
+func (m *GetTransactionHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionHistoryResponse.Unmarshal(m, b)
+}
+func (m *GetTransactionHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTransactionHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionHistoryResponse.Merge(m, src)
+}
+func (m *GetTransactionHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionHistoryResponse.Size(m)
+}
+func (m *GetTransactionHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionHistoryResponse proto.InternalMessageInfo
+
+func (m *GetTransactionHistoryResponse) GetTransactions() []*model.Transaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+func (m *GetTransactionHistoryResponse) GetTotalCount() int32 {
+	if m != nil {
+		return m.TotalCount
+	}
+	return 0
+}
+
+type ReserveFundsRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount               float64  `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	OrderId              string   `protobuf:"bytes,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReserveFundsRequest) Reset()         { *m = ReserveFundsRequest{} }
+func (m *ReserveFundsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReserveFundsRequest) ProtoMessage()    {}
+func (*ReserveFundsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{12}
+}
+
+func (m *ReserveFundsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReserveFundsRequest.Unmarshal(m, b)
+}
+func (m *ReserveFundsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReserveFundsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReserveFundsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReserveFundsRequest.Merge(m, src)
+}
+func (m *ReserveFundsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReserveFundsRequest.Size(m)
+}
+func (m *ReserveFundsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReserveFundsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReserveFundsRequest proto.InternalMessageInfo
+
+func (m *ReserveFundsRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ReserveFundsRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *ReserveFundsRequest) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *ReserveFundsRequest) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+type ReserveFundsResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	ReservationId        string   `protobuf:"bytes,2,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReserveFundsResponse) Reset()         { *m = ReserveFundsResponse{} }
+func (m *ReserveFundsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReserveFundsResponse) ProtoMessage()    {}
+func (*ReserveFundsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{13}
+}
+
+func (m *ReserveFundsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReserveFundsResponse.Unmarshal(m, b)
+}
+func (m *ReserveFundsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReserveFundsResponse.Marshal(b, m, deterministic)
+}
+func (m *ReserveFundsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReserveFundsResponse.Merge(m, src)
+}
+func (m *ReserveFundsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReserveFundsResponse.Size(m)
+}
+func (m *ReserveFundsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReserveFundsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReserveFundsResponse proto.InternalMessageInfo
+
+func (m *ReserveFundsResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ReserveFundsResponse) GetReservationId() string {
+	if m != nil {
+		return m.ReservationId
+	}
+	return ""
+}
+
+type AssignReservationRequest struct {
+	ReservationId        string   `protobuf:"bytes,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	OrderId              string   `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AssignReservationRequest) Reset()         { *m = AssignReservationRequest{} }
+func (m *AssignReservationRequest) String() string { return proto.CompactTextString(m) }
+func (*AssignReservationRequest) ProtoMessage()    {}
+func (*AssignReservationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{14}
+}
+
+func (m *AssignReservationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssignReservationRequest.Unmarshal(m, b)
+}
+func (m *AssignReservationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssignReservationRequest.Marshal(b, m, deterministic)
+}
+func (m *AssignReservationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssignReservationRequest.Merge(m, src)
+}
+func (m *AssignReservationRequest) XXX_Size() int {
+	return xxx_messageInfo_AssignReservationRequest.Size(m)
+}
+func (m *AssignReservationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssignReservationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssignReservationRequest proto.InternalMessageInfo
+
+func (m *AssignReservationRequest) GetReservationId() string {
+	if m != nil {
+		return m.ReservationId
+	}
+	return ""
+}
+
+func (m *AssignReservationRequest) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+type AssignReservationResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AssignReservationResponse) Reset()         { *m = AssignReservationResponse{} }
+func (m *AssignReservationResponse) String() string { return proto.CompactTextString(m) }
+func (*AssignReservationResponse) ProtoMessage()    {}
+func (*AssignReservationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{15}
+}
+
+func (m *AssignReservationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssignReservationResponse.Unmarshal(m, b)
+}
+func (m *AssignReservationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssignReservationResponse.Marshal(b, m, deterministic)
+}
+func (m *AssignReservationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssignReservationResponse.Merge(m, src)
+}
+func (m *AssignReservationResponse) XXX_Size() int {
+	return xxx_messageInfo_AssignReservationResponse.Size(m)
+}
+func (m *AssignReservationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssignReservationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssignReservationResponse proto.InternalMessageInfo
+
+func (m *AssignReservationResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+// One of the order id or reservation id must be set
+type ReleaseFundsRequest struct {
+	OrderId              string   `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ReservationId        string   `protobuf:"bytes,2,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseFundsRequest) Reset()         { *m = ReleaseFundsRequest{} }
+func (m *ReleaseFundsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReleaseFundsRequest) ProtoMessage()    {}
+func (*ReleaseFundsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{16}
+}
+
+func (m *ReleaseFundsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseFundsRequest.Unmarshal(m, b)
+}
+func (m *ReleaseFundsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseFundsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReleaseFundsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseFundsRequest.Merge(m, src)
+}
+func (m *ReleaseFundsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReleaseFundsRequest.Size(m)
+}
+func (m *ReleaseFundsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseFundsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseFundsRequest proto.InternalMessageInfo
+
+func (m *ReleaseFundsRequest) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *ReleaseFundsRequest) GetReservationId() string {
+	if m != nil {
+		return m.ReservationId
+	}
+	return ""
+}
+
+type ReleaseFundsResponse struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	ReleasedAmount       float64  `protobuf:"fixed64,2,opt,name=released_amount,json=releasedAmount,proto3" json:"released_amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseFundsResponse) Reset()         { *m = ReleaseFundsResponse{} }
+func (m *ReleaseFundsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReleaseFundsResponse) ProtoMessage()    {}
+func (*ReleaseFundsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{17}
+}
+
+func (m *ReleaseFundsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseFundsResponse.Unmarshal(m, b)
+}
+func (m *ReleaseFundsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseFundsResponse.Marshal(b, m, deterministic)
+}
+func (m *ReleaseFundsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseFundsResponse.Merge(m, src)
+}
+func (m *ReleaseFundsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReleaseFundsResponse.Size(m)
+}
+func (m *ReleaseFundsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseFundsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseFundsResponse proto.InternalMessageInfo
+
+func (m *ReleaseFundsResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ReleaseFundsResponse) GetReleasedAmount() float64 {
+	if m != nil {
+		return m.ReleasedAmount
+	}
+	return 0
+}
+
+type SubscribeBalanceUpdatesRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeBalanceUpdatesRequest) Reset()         { *m = SubscribeBalanceUpdatesRequest{} }
+func (m *SubscribeBalanceUpdatesRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBalanceUpdatesRequest) ProtoMessage()    {}
+func (*SubscribeBalanceUpdatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_616f512c458f1e31, []int{18}
+}
+
+func (m *SubscribeBalanceUpdatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeBalanceUpdatesRequest.Unmarshal(m, b)
+}
+func (m *SubscribeBalanceUpdatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeBalanceUpdatesRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeBalanceUpdatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeBalanceUpdatesRequest.Merge(m, src)
+}
+func (m *SubscribeBalanceUpdatesRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeBalanceUpdatesRequest.Size(m)
+}
+func (m *SubscribeBalanceUpdatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeBalanceUpdatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeBalanceUpdatesRequest proto.InternalMessageInfo
+
+func (m *SubscribeBalanceUpdatesRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*GetBalanceRequest)(nil), "walletservice.GetBalanceRequest")
+	proto.RegisterType((*GetBalanceResponse)(nil), "walletservice.GetBalanceResponse")
+	proto.RegisterType((*ListBalancesRequest)(nil), "walletservice.ListBalancesRequest")
+	proto.RegisterType((*ListBalancesResponse)(nil), "walletservice.ListBalancesResponse")
+	proto.RegisterType((*DepositRequest)(nil), "walletservice.DepositRequest")
+	proto.RegisterType((*DepositResponse)(nil), "walletservice.DepositResponse")
+	proto.RegisterType((*WithdrawRequest)(nil), "walletservice.WithdrawRequest")
+	proto.RegisterType((*WithdrawResponse)(nil), "walletservice.WithdrawResponse")
+	proto.RegisterType((*TransferRequest)(nil), "walletservice.TransferRequest")
+	proto.RegisterType((*TransferResponse)(nil), "walletservice.TransferResponse")
+	proto.RegisterType((*GetTransactionHistoryRequest)(nil), "walletservice.GetTransactionHistoryRequest")
+	proto.RegisterType((*GetTransactionHistoryResponse)(nil), "walletservice.GetTransactionHistoryResponse")
+	proto.RegisterType((*ReserveFundsRequest)(nil), "walletservice.ReserveFundsRequest")
+	proto.RegisterType((*ReserveFundsResponse)(nil), "walletservice.ReserveFundsResponse")
+	proto.RegisterType((*AssignReservationRequest)(nil), "walletservice.AssignReservationRequest")
+	proto.RegisterType((*AssignReservationResponse)(nil), "walletservice.AssignReservationResponse")
+	proto.RegisterType((*ReleaseFundsRequest)(nil), "walletservice.ReleaseFundsRequest")
+	proto.RegisterType((*ReleaseFundsResponse)(nil), "walletservice.ReleaseFundsResponse")
+	proto.RegisterType((*SubscribeBalanceUpdatesRequest)(nil), "walletservice.SubscribeBalanceUpdatesRequest")
+}
+
+func init() { proto.RegisterFile("wallet_service.proto", fileDescriptor_616f512c458f1e31) }
+
+var fileDescriptor_616f512c458f1e31 = []byte{
+	// 794 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xeb, 0x4b, 0x1b, 0x41,
+	0x10, 0xe7, 0x7c, 0x24, 0x71, 0xcc, 0x43, 0xd7, 0x54, 0xe3, 0xd5, 0x47, 0xba, 0xa1, 0x18, 0xfa,
+	0x08, 0x45, 0xa1, 0xd0, 0x2f, 0x05, 0x6d, 0xa9, 0x09, 0x16, 0x4a, 0xcf, 0x86, 0x40, 0x29, 0x84,
+	0xcb, 0xed, 0xaa, 0x07, 0xc9, 0x5d, 0x7a, 0xbb, 0x57, 0xab, 0x50, 0xe8, 0x87, 0xf6, 0x43, 0xff,
+	0xeb, 0x72, 0x7b, 0x7b, 0xb9, 0x57, 0x2e, 0x11, 0xc4, 0x4f, 0xba, 0x33, 0xbf, 0xfd, 0xcd, 0xfc,
+	0x66, 0x6f, 0x66, 0x02, 0xd5, 0x6b, 0x7d, 0x38, 0xa4, 0xbc, 0xcf, 0xa8, 0xf3, 0xc3, 0x34, 0x68,
+	0x6b, 0xec, 0xd8, 0xdc, 0x46, 0x25, 0xdf, 0x2a, 0x8d, 0x6a, 0x49, 0x37, 0x0c, 0xdb, 0xb5, 0xb8,
+	0xef, 0xc5, 0x6d, 0x58, 0x3f, 0xa5, 0xfc, 0x44, 0x1f, 0xea, 0x96, 0x41, 0x35, 0xfa, 0xdd, 0xa5,
+	0x8c, 0xa3, 0x2d, 0xc8, 0xbb, 0x8c, 0x3a, 0x7d, 0x93, 0xd4, 0x94, 0xba, 0xd2, 0x5c, 0xd1, 0x72,
+	0xde, 0xb1, 0x43, 0x90, 0x0a, 0x05, 0xc3, 0x75, 0x1c, 0x6a, 0x19, 0x37, 0xb5, 0x05, 0xe1, 0x99,
+	0x9c, 0xf1, 0x5b, 0x40, 0x51, 0x26, 0x36, 0xb6, 0x2d, 0x46, 0x51, 0x13, 0xf2, 0x03, 0xdf, 0x24,
+	0xa8, 0x56, 0x0f, 0xcb, 0xad, 0x91, 0x4d, 0xe8, 0xb0, 0x15, 0x00, 0x03, 0x37, 0x6e, 0xc1, 0xc6,
+	0x47, 0x93, 0x05, 0x04, 0x6c, 0x5e, 0x2e, 0xf8, 0x04, 0xaa, 0x71, 0xbc, 0x8c, 0xf8, 0x0c, 0x0a,
+	0x92, 0x92, 0xd5, 0x94, 0xfa, 0xe2, 0x94, 0x90, 0x13, 0x3f, 0x76, 0xa1, 0xfc, 0x9e, 0x8e, 0x6d,
+	0x66, 0xf2, 0xfb, 0x48, 0x47, 0x9b, 0x90, 0xd3, 0x47, 0x5e, 0x51, 0x6b, 0x8b, 0x75, 0xa5, 0xa9,
+	0x68, 0xf2, 0xe4, 0xd9, 0x47, 0x94, 0x5f, 0xd9, 0xa4, 0xb6, 0xe4, 0x73, 0xf9, 0x27, 0xdc, 0x86,
+	0xca, 0x24, 0xac, 0xcc, 0x7a, 0x13, 0x72, 0x8c, 0xeb, 0xdc, 0x65, 0x41, 0x58, 0xff, 0x84, 0x76,
+	0x01, 0x88, 0x0f, 0xf5, 0x52, 0xf2, 0x03, 0xaf, 0x48, 0x4b, 0x87, 0xe0, 0xdf, 0x0a, 0x54, 0x7a,
+	0x26, 0xbf, 0x22, 0x8e, 0x7e, 0xfd, 0x20, 0x12, 0xea, 0xb0, 0x4a, 0x28, 0xe3, 0xa6, 0xa5, 0x73,
+	0xd3, 0xb6, 0xa4, 0x8e, 0xa8, 0x09, 0x7f, 0x82, 0xb5, 0x30, 0x83, 0x39, 0x6a, 0x1a, 0x50, 0xba,
+	0x96, 0x58, 0x7d, 0x18, 0x0a, 0x2a, 0x86, 0xc6, 0x0e, 0xc1, 0x7f, 0x15, 0xa8, 0x7c, 0x71, 0x74,
+	0x8b, 0x5d, 0x50, 0x27, 0xd0, 0x54, 0x87, 0xe2, 0x85, 0x63, 0x8f, 0xfa, 0x71, 0x61, 0xe0, 0xd9,
+	0xba, 0xbe, 0xb8, 0x1d, 0x00, 0x6e, 0x4f, 0xfc, 0x52, 0x1e, 0xb7, 0xbb, 0x69, 0xe9, 0x8b, 0x99,
+	0xd2, 0x97, 0xa2, 0xd2, 0xf1, 0x19, 0xac, 0x85, 0x69, 0xcc, 0x11, 0xb6, 0x0f, 0xab, 0x5c, 0x62,
+	0xc3, 0xf0, 0x10, 0x98, 0x3a, 0x04, 0xff, 0x51, 0x60, 0xe7, 0x94, 0x72, 0x41, 0xa8, 0x1b, 0x5e,
+	0xe1, 0xda, 0x26, 0xe3, 0xb6, 0x73, 0x73, 0xaf, 0x57, 0x43, 0xb0, 0x34, 0xd6, 0x2f, 0xa9, 0x90,
+	0xb4, 0xac, 0x89, 0xff, 0xd1, 0x63, 0x58, 0xf1, 0xfe, 0xf6, 0x99, 0x79, 0x4b, 0x85, 0xa2, 0x65,
+	0xad, 0xe0, 0x19, 0xce, 0xcd, 0x5b, 0x8a, 0x7f, 0xc2, 0x6e, 0x46, 0x16, 0x52, 0xe0, 0x6b, 0x28,
+	0xf2, 0xd0, 0x1b, 0x74, 0x10, 0x92, 0x1d, 0x14, 0xb9, 0xa8, 0xc5, 0x70, 0xa2, 0x00, 0x36, 0xd7,
+	0x87, 0x7d, 0x31, 0x5c, 0x44, 0xa2, 0xcb, 0x1a, 0x08, 0xd3, 0x3b, 0x51, 0xcd, 0x5f, 0xb0, 0xa1,
+	0x51, 0x6f, 0x08, 0xd1, 0x0f, 0xae, 0x45, 0xd8, 0x83, 0x7c, 0xac, 0xdb, 0x50, 0xb0, 0x1d, 0xe2,
+	0xb3, 0xf9, 0x5f, 0x6a, 0x5e, 0x9c, 0x3b, 0x04, 0x77, 0xa1, 0x1a, 0x0f, 0x3f, 0xe7, 0x41, 0x9f,
+	0x42, 0xd9, 0x11, 0x78, 0xf1, 0x91, 0x87, 0x6f, 0x5a, 0x8a, 0x58, 0x3b, 0x04, 0x7f, 0x83, 0xda,
+	0x31, 0x63, 0xe6, 0xa5, 0xa5, 0x85, 0xe6, 0x40, 0x5a, 0x9a, 0x42, 0x99, 0x42, 0x11, 0x4b, 0x7a,
+	0x21, 0x9e, 0xf4, 0x11, 0x6c, 0x4f, 0x61, 0x9f, 0x9d, 0x39, 0xee, 0x79, 0x85, 0x1e, 0x52, 0x9d,
+	0xc5, 0x0b, 0x1d, 0x0d, 0xa3, 0xc4, 0xc2, 0xdc, 0x55, 0x6b, 0x0f, 0xaa, 0x71, 0xe2, 0x39, 0x25,
+	0x3c, 0x80, 0x8a, 0xe3, 0xe3, 0x49, 0x5f, 0x3e, 0xd7, 0x82, 0x78, 0xae, 0x72, 0x60, 0x3e, 0xf6,
+	0x1b, 0xed, 0x0d, 0xec, 0x9d, 0xbb, 0x03, 0x66, 0x38, 0xe6, 0x80, 0xca, 0x19, 0xdd, 0x1d, 0x13,
+	0x9d, 0xcf, 0x5f, 0x02, 0x87, 0xff, 0xf2, 0x50, 0xea, 0x89, 0xfd, 0x76, 0xee, 0xef, 0x37, 0xf4,
+	0x19, 0x20, 0x5c, 0x43, 0xa8, 0xde, 0x8a, 0x6d, 0xbf, 0x56, 0x6a, 0xd7, 0xa9, 0x4f, 0x66, 0x20,
+	0xa4, 0xc0, 0x1e, 0x14, 0xa3, 0x9b, 0x06, 0xe1, 0xc4, 0x95, 0x29, 0x6b, 0x4b, 0x6d, 0xcc, 0xc4,
+	0x48, 0xe2, 0x36, 0xe4, 0xe5, 0x1e, 0x40, 0xbb, 0x09, 0x7c, 0x7c, 0x2d, 0xa9, 0x7b, 0x59, 0x6e,
+	0xc9, 0x74, 0x06, 0x85, 0x60, 0x08, 0xa3, 0x24, 0x36, 0xb1, 0x1f, 0xd4, 0xfd, 0x4c, 0x7f, 0x48,
+	0x16, 0x0c, 0xbe, 0x14, 0x59, 0x62, 0x30, 0xab, 0xfb, 0x99, 0x7e, 0x49, 0xe6, 0xc0, 0xa3, 0xa9,
+	0x13, 0x07, 0x3d, 0x4f, 0x17, 0x3e, 0x73, 0x3a, 0xaa, 0x2f, 0xee, 0x06, 0x0e, 0x1f, 0x2c, 0xda,
+	0xec, 0xa9, 0x07, 0x9b, 0x32, 0x88, 0xd4, 0xc6, 0x4c, 0x8c, 0x24, 0xbe, 0x80, 0xf5, 0x54, 0x43,
+	0xa2, 0x83, 0xc4, 0xcd, 0xac, 0x81, 0xa0, 0x36, 0xe7, 0x03, 0xa3, 0x02, 0xc2, 0x56, 0x9b, 0x22,
+	0x20, 0xd5, 0xe0, 0x6a, 0x63, 0x26, 0x46, 0x12, 0x7f, 0x85, 0xad, 0x8c, 0x56, 0x43, 0x2f, 0x13,
+	0xf7, 0x67, 0xb7, 0xa4, 0x9a, 0xf8, 0x51, 0xf5, 0x4a, 0x19, 0xe4, 0xc4, 0x2f, 0xca, 0xa3, 0xff,
+	0x03, 0x00, 0xd3, 0x89, 0x95, 0xa9, 0x87, 0x0a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// WalletServiceClient is the client API for WalletService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WalletServiceClient interface {
+	// Get a user's balance for a specific currency/token
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	// List all balances for a user
+	ListBalances(ctx context.Context, in *ListBalancesRequest, opts ...grpc.CallOption) (*ListBalancesResponse, error)
+	// Deposit funds into a user's account
+	Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*DepositResponse, error)
+	// Withdraw funds from a user's account
+	Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*WithdrawResponse, error)
+	// Transfer funds between accounts (internal transfer)
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	// Get transaction history for a user/account
+	GetTransactionHistory(ctx context.Context, in *GetTransactionHistoryRequest, opts ...grpc.CallOption) (*GetTransactionHistoryResponse, error)
+	// Reserve funds for an order, reserving funds for an order that already has a reservation has no effect
+	ReserveFunds(ctx context.Context, in *ReserveFundsRequest, opts ...grpc.CallOption) (*ReserveFundsResponse, error)
+	// Assign a reservation made before the order was created to the order, the reservation is then adjusted as the
+	// order fills and released when the order is done
+	AssignReservation(ctx context.Context, in *AssignReservationRequest, opts ...grpc.CallOption) (*AssignReservationResponse, error)
+	// Release the funds that remain reserved for an order or reservation
+	ReleaseFunds(ctx context.Context, in *ReleaseFundsRequest, opts ...grpc.CallOption) (*ReleaseFundsResponse, error)
+	// Subscribe to real-time balance updates for a user
+	SubscribeBalanceUpdates(ctx context.Context, in *SubscribeBalanceUpdatesRequest, opts ...grpc.CallOption) (WalletService_SubscribeBalanceUpdatesClient, error)
+}
+
+type walletServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWalletServiceClient(cc *grpc.ClientConn) WalletServiceClient {
+	return &walletServiceClient{cc}
+}
+
+func (c *walletServiceClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	out := new(GetBalanceResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ListBalances(ctx context.Context, in *ListBalancesRequest, opts ...grpc.CallOption) (*ListBalancesResponse, error) {
+	out := new(ListBalancesResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/ListBalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*DepositResponse, error) {
+	out := new(DepositResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/Deposit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*WithdrawResponse, error) {
+	out := new(WithdrawResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/Withdraw", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/Transfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetTransactionHistory(ctx context.Context, in *GetTransactionHistoryRequest, opts ...grpc.CallOption) (*GetTransactionHistoryResponse, error) {
+	out := new(GetTransactionHistoryResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/GetTransactionHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ReserveFunds(ctx context.Context, in *ReserveFundsRequest, opts ...grpc.CallOption) (*ReserveFundsResponse, error) {
+	out := new(ReserveFundsResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/ReserveFunds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) AssignReservation(ctx context.Context, in *AssignReservationRequest, opts ...grpc.CallOption) (*AssignReservationResponse, error) {
+	out := new(AssignReservationResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/AssignReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ReleaseFunds(ctx context.Context, in *ReleaseFundsRequest, opts ...grpc.CallOption) (*ReleaseFundsResponse, error) {
+	out := new(ReleaseFundsResponse)
+	err := c.cc.Invoke(ctx, "/walletservice.WalletService/ReleaseFunds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) SubscribeBalanceUpdates(ctx context.Context, in *SubscribeBalanceUpdatesRequest, opts ...grpc.CallOption) (WalletService_SubscribeBalanceUpdatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WalletService_serviceDesc.Streams[0], "/walletservice.WalletService/SubscribeBalanceUpdates", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceSubscribeBalanceUpdatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_SubscribeBalanceUpdatesClient interface {
+	Recv() (*model.Balance, error)
+	grpc.ClientStream
+}
+
+type walletServiceSubscribeBalanceUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceSubscribeBalanceUpdatesClient) Recv() (*model.Balance, error) {
+	m := new(model.Balance)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WalletServiceServer is the server API for WalletService service.
+type WalletServiceServer interface {
+	// Get a user's balance for a specific currency/token
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	// List all balances for a user
+	ListBalances(context.Context, *ListBalancesRequest) (*ListBalancesResponse, error)
+	// Deposit funds into a user's account
+	Deposit(context.Context, *DepositRequest) (*DepositResponse, error)
+	// Withdraw funds from a user's account
+	Withdraw(context.Context, *WithdrawRequest) (*WithdrawResponse, error)
+	// Transfer funds between accounts (internal transfer)
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	// Get transaction history for a user/account
+	GetTransactionHistory(context.Context, *GetTransactionHistoryRequest) (*GetTransactionHistoryResponse, error)
+	// Reserve funds for an order, reserving funds for an order that already has a reservation has no effect
+	ReserveFunds(context.Context, *ReserveFundsRequest) (*ReserveFundsResponse, error)
+	// Assign a reservation made before the order was created to the order, the reservation is then adjusted as the
+	// order fills and released when the order is done
+	AssignReservation(context.Context, *AssignReservationRequest) (*AssignReservationResponse, error)
+	// Release the funds that remain reserved for an order or reservation
+	ReleaseFunds(context.Context, *ReleaseFundsRequest) (*ReleaseFundsResponse, error)
+	// Subscribe to real-time balance updates for a user
+	SubscribeBalanceUpdates(*SubscribeBalanceUpdatesRequest, WalletService_SubscribeBalanceUpdatesServer) error
+}
+
+// UnimplementedWalletServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedWalletServiceServer struct {
+}
+
+func (*UnimplementedWalletServiceServer) GetBalance(ctx context.Context, req *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (*UnimplementedWalletServiceServer) ListBalances(ctx context.Context, req *ListBalancesRequest) (*ListBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBalances not implemented")
+}
+func (*UnimplementedWalletServiceServer) Deposit(ctx context.Context, req *DepositRequest) (*DepositResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deposit not implemented")
+}
+func (*UnimplementedWalletServiceServer) Withdraw(ctx context.Context, req *WithdrawRequest) (*WithdrawResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (*UnimplementedWalletServiceServer) Transfer(ctx context.Context, req *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (*UnimplementedWalletServiceServer) GetTransactionHistory(ctx context.Context, req *GetTransactionHistoryRequest) (*GetTransactionHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionHistory not implemented")
+}
+func (*UnimplementedWalletServiceServer) ReserveFunds(ctx context.Context, req *ReserveFundsRequest) (*ReserveFundsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveFunds not implemented")
+}
+func (*UnimplementedWalletServiceServer) AssignReservation(ctx context.Context, req *AssignReservationRequest) (*AssignReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignReservation not implemented")
+}
+func (*UnimplementedWalletServiceServer) ReleaseFunds(ctx context.Context, req *ReleaseFundsRequest) (*ReleaseFundsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseFunds not implemented")
+}
+func (*UnimplementedWalletServiceServer) SubscribeBalanceUpdates(req *SubscribeBalanceUpdatesRequest, srv WalletService_SubscribeBalanceUpdatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBalanceUpdates not implemented")
+}
+
+func RegisterWalletServiceServer(s *grpc.Server, srv WalletServiceServer) {
+	s.RegisterService(&_WalletService_serviceDesc, srv)
+}
+
+func _WalletService_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ListBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/ListBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListBalances(ctx, req.(*ListBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/Deposit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Deposit(ctx, req.(*DepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/Withdraw",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Withdraw(ctx, req.(*WithdrawRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/Transfer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_GetTransactionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetTransactionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/GetTransactionHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetTransactionHistory(ctx, req.(*GetTransactionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ReserveFunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveFundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ReserveFunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/ReserveFunds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ReserveFunds(ctx, req.(*ReserveFundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_AssignReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).AssignReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/AssignReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).AssignReservation(ctx, req.(*AssignReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ReleaseFunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseFundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ReleaseFunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletservice.WalletService/ReleaseFunds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ReleaseFunds(ctx, req.(*ReleaseFundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_SubscribeBalanceUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBalanceUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).SubscribeBalanceUpdates(m, &walletServiceSubscribeBalanceUpdatesServer{stream})
+}
+
+type WalletService_SubscribeBalanceUpdatesServer interface {
+	Send(*model.Balance) error
+	grpc.ServerStream
+}
+
+type walletServiceSubscribeBalanceUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletServiceSubscribeBalanceUpdatesServer) Send(m *model.Balance) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _WalletService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "walletservice.WalletService",
+	HandlerType: (*WalletServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBalance",
+			Handler:    _WalletService_GetBalance_Handler,
+		},
+		{
+			MethodName: "ListBalances",
+			Handler:    _WalletService_ListBalances_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _WalletService_Deposit_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _WalletService_Withdraw_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _WalletService_Transfer_Handler,
+		},
+		{
+			MethodName: "GetTransactionHistory",
+			Handler:    _WalletService_GetTransactionHistory_Handler,
+		},
+		{
+			MethodName: "ReserveFunds",
+			Handler:    _WalletService_ReserveFunds_Handler,
+		},
+		{
+			MethodName: "AssignReservation",
+			Handler:    _WalletService_AssignReservation_Handler,
+		},
+		{
+			MethodName: "ReleaseFunds",
+			Handler:    _WalletService_ReleaseFunds_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBalanceUpdates",
+			Handler:       _WalletService_SubscribeBalanceUpdates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "wallet_service.proto",
+}
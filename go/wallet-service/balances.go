@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/ettec/open-trading-platform/go/wallet-service/api/model"
+	"sort"
+	"sync"
+)
+
+// balanceSubscriptions distributes balance updates to the subscribers of the balance's user.  Updates are conflated
+// per currency, a subscriber that falls behind receives only the latest balance of each currency.
+type balanceSubscriptions struct {
+	mutex         sync.Mutex
+	subscriptions map[string]map[*balanceSubscription]bool
+}
+
+type balanceSubscription struct {
+	userId  string
+	pending map[string]*model.Balance
+	changed chan struct{}
+}
+
+func newBalanceSubscriptions() *balanceSubscriptions {
+	return &balanceSubscriptions{subscriptions: map[string]map[*balanceSubscription]bool{}}
+}
+
+func (b *balanceSubscriptions) subscribe(userId string) *balanceSubscription {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	subscription := &balanceSubscription{
+		userId:  userId,
+		pending: map[string]*model.Balance{},
+		changed: make(chan struct{}, 1),
+	}
+
+	if b.subscriptions[userId] == nil {
+		b.subscriptions[userId] = map[*balanceSubscription]bool{}
+	}
+	b.subscriptions[userId][subscription] = true
+
+	return subscription
+}
+
+func (b *balanceSubscriptions) unsubscribe(subscription *balanceSubscription) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.subscriptions[subscription.userId], subscription)
+	if len(b.subscriptions[subscription.userId]) == 0 {
+		delete(b.subscriptions, subscription.userId)
+	}
+}
+
+func (b *balanceSubscriptions) publish(balance *model.Balance) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for subscription := range b.subscriptions[balance.UserId] {
+		subscription.pending[balance.Currency] = balance
+		select {
+		case subscription.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// takeChanges returns the latest balance of each currency that has changed since the subscription's changes were last
+// taken, ordered by currency.
+func (b *balanceSubscriptions) takeChanges(subscription *balanceSubscription) []*model.Balance {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	result := make([]*model.Balance, 0, len(subscription.pending))
+	for currency, balance := range subscription.pending {
+		result = append(result, balance)
+		delete(subscription.pending, currency)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Currency < result[j].Currency })
+
+	return result
+}
@@ -0,0 +1,616 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ettec/open-trading-platform/go/wallet-service/api/model"
+	common "github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var errInsufficientFunds = errors.New("insufficient funds")
+var errNoReservation = errors.New("no reservation found")
+var errReservationAssigned = errors.New("reservation is assigned to another order")
+
+// The kinds of ledger account.  Each user has an available and a locked account per currency, funds reserved for
+// open orders are moved from the available account to the locked account.  Deposits and withdrawals are posted against
+// the external account of the currency, which is the only kind of account that may have a negative balance.
+const (
+	accountAvailable = "available"
+	accountLocked    = "locked"
+	accountExternal  = "external"
+)
+
+const externalAccountOwner = "external"
+
+// The types of ledger transaction
+const (
+	transactionDeposit     = "deposit"
+	transactionWithdrawal  = "withdrawal"
+	transactionTransfer    = "transfer"
+	transactionReservation = "reservation"
+	transactionRelease     = "release"
+	transactionTrade       = "trade"
+)
+
+// The states of an order's reservation
+const (
+	reservationReserved = "reserved"
+	reservationReleased = "released"
+)
+
+const defaultPageSize = 50
+const maxPageSize = 1000
+
+// releasedFunds are the funds returned to a user's available balance when an order's reservation is released.
+type releasedFunds struct {
+	userId   string
+	currency string
+	amount   decimal.Decimal
+}
+
+type accountKey struct {
+	userId   string
+	currency string
+	kind     string
+}
+
+// posting is one side of a ledger transaction, a positive amount credits the account and a negative amount debits it.
+type posting struct {
+	account accountKey
+	amount  decimal.Decimal
+}
+
+// sqlLedger is a double-entry ledger in the wallet schema.  Every movement of funds is a transaction with a journal
+// entry per account it posts to, the entries of a transaction sum to zero and the journal is immutable.  The balance of
+// each account is maintained alongside the journal in the same database transaction, and a posting that would
+// overdraw a user's account fails the whole transaction.
+type sqlLedger struct {
+	db *sql.DB
+}
+
+func newSqlLedger(driverName string, dbConnString string) (*sqlLedger, error) {
+	db, err := sql.Open(driverName, dbConnString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &sqlLedger{db: db}, nil
+}
+
+func (l *sqlLedger) Close() error {
+	return l.db.Close()
+}
+
+func (l *sqlLedger) deposit(ctx context.Context, userId string, currency string, amount decimal.Decimal,
+	method string) (string, error) {
+	return l.post(ctx, transactionDeposit, userId, currency, amount, method, []posting{
+		{account: accountKey{externalAccountOwner, currency, accountExternal}, amount: amount.Neg()},
+		{account: accountKey{userId, currency, accountAvailable}, amount: amount},
+	})
+}
+
+func (l *sqlLedger) withdraw(ctx context.Context, userId string, currency string, amount decimal.Decimal,
+	destination string) (string, error) {
+	return l.post(ctx, transactionWithdrawal, userId, currency, amount, destination, []posting{
+		{account: accountKey{userId, currency, accountAvailable}, amount: amount.Neg()},
+		{account: accountKey{externalAccountOwner, currency, accountExternal}, amount: amount},
+	})
+}
+
+func (l *sqlLedger) transfer(ctx context.Context, fromUserId string, toUserId string, currency string,
+	amount decimal.Decimal) (string, error) {
+	return l.post(ctx, transactionTransfer, fromUserId, currency, amount, toUserId, []posting{
+		{account: accountKey{fromUserId, currency, accountAvailable}, amount: amount.Neg()},
+		{account: accountKey{toUserId, currency, accountAvailable}, amount: amount},
+	})
+}
+
+func (l *sqlLedger) post(ctx context.Context, transactionType string, userId string, currency string,
+	amount decimal.Decimal, reference string, postings []posting) (string, error) {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	transactionId, err := postTransaction(ctx, tx, transactionType, userId, currency, amount, reference, postings)
+	if err != nil {
+		_ = tx.Rollback()
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return strconv.FormatInt(transactionId, 10), nil
+}
+
+// postTransaction records the transaction and its journal entries and applies the postings to the account balances,
+// postings of zero are omitted.  The accounts are updated in id order so that concurrent transactions cannot deadlock.
+func postTransaction(ctx context.Context, tx *sql.Tx, transactionType string, userId string, currency string,
+	amount decimal.Decimal, reference string, postings []posting) (int64, error) {
+
+	total := decimal.New(0, 0)
+	for _, p := range postings {
+		total = total.Add(p.amount)
+	}
+	if !total.Equal(decimal.New(0, 0)) {
+		return 0, fmt.Errorf("postings of %v transaction do not balance, total: %v", transactionType, total)
+	}
+
+	now := time.Now()
+
+	var transactionId int64
+	err := tx.QueryRowContext(ctx, `INSERT INTO wallet.transactions (type, user_id, currency, amount, reference, created)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, transactionType, userId, currency, amount, reference, now).Scan(&transactionId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert transaction: %w", err)
+	}
+
+	type entry struct {
+		accountId int64
+		amount    decimal.Decimal
+	}
+
+	entries := make([]entry, 0, len(postings))
+	for _, p := range postings {
+		if p.amount.IsZero() {
+			continue
+		}
+
+		accountId, err := getOrCreateAccount(ctx, tx, p.account)
+		if err != nil {
+			return 0, err
+		}
+		entries = append(entries, entry{accountId: accountId, amount: p.amount})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].accountId < entries[j].accountId })
+
+	for _, e := range entries {
+		result, err := tx.ExecContext(ctx, `UPDATE wallet.accounts SET balance = balance + $1, last_updated = $2
+			WHERE id = $3 AND (kind = $4 OR balance + $1 >= 0)`, e.amount, now, e.accountId, accountExternal)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update account balance: %w", err)
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get updated account count: %w", err)
+		}
+
+		if updated == 0 {
+			return 0, errInsufficientFunds
+		}
+
+		if _, err := tx.ExecContext(ctx, `INSERT INTO wallet.journal_entries (transaction_id, account_id, amount)
+			VALUES ($1, $2, $3)`, transactionId, e.accountId, e.amount); err != nil {
+			return 0, fmt.Errorf("failed to insert journal entry: %w", err)
+		}
+	}
+
+	return transactionId, nil
+}
+
+func getOrCreateAccount(ctx context.Context, tx *sql.Tx, key accountKey) (int64, error) {
+	if _, err := tx.ExecContext(ctx, `INSERT INTO wallet.accounts (user_id, currency, kind) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, currency, kind) DO NOTHING`, key.userId, key.currency, key.kind); err != nil {
+		return 0, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	var accountId int64
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM wallet.accounts WHERE user_id = $1 AND currency = $2 AND kind = $3`,
+		key.userId, key.currency, key.kind).Scan(&accountId); err != nil {
+		return 0, fmt.Errorf("failed to get account id: %w", err)
+	}
+
+	return accountId, nil
+}
+
+// reserve moves the amount from the user's available account to their locked account and records the reservation.
+// The order id may be empty if the order has not yet been created, in which case the reservation is assigned to the
+// order later.  Reserving funds for an order that already has a reservation returns the existing reservation.
+func (l *sqlLedger) reserve(ctx context.Context, orderId string, userId string, currency string,
+	amount decimal.Decimal) (string, error) {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if orderId != "" {
+		var reservationId int64
+		err := tx.QueryRowContext(ctx, `SELECT id FROM wallet.reservations WHERE order_id = $1`, orderId).Scan(&reservationId)
+		if err == nil {
+			return strconv.FormatInt(reservationId, 10), nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("failed to get reservation: %w", err)
+		}
+	}
+
+	if _, err := postTransaction(ctx, tx, transactionReservation, userId, currency, amount, orderId, []posting{
+		{account: accountKey{userId, currency, accountAvailable}, amount: amount.Neg()},
+		{account: accountKey{userId, currency, accountLocked}, amount: amount},
+	}); err != nil {
+		return "", err
+	}
+
+	var reservationId int64
+	if err := tx.QueryRowContext(ctx, `INSERT INTO wallet.reservations (order_id, user_id, currency, amount, status)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5) RETURNING id`, orderId, userId, currency, amount, reservationReserved).
+		Scan(&reservationId); err != nil {
+		return "", fmt.Errorf("failed to insert reservation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return strconv.FormatInt(reservationId, 10), nil
+}
+
+// assign assigns a reservation of the user made before its order was created to the order.
+func (l *sqlLedger) assign(ctx context.Context, userId string, reservationId string, orderId string) error {
+	id, err := strconv.ParseInt(reservationId, 10, 64)
+	if err != nil {
+		return errNoReservation
+	}
+
+	result, err := l.db.ExecContext(ctx, `UPDATE wallet.reservations SET order_id = $1
+		WHERE id = $2 AND user_id = $3 AND (order_id IS NULL OR order_id = $1)`, orderId, id, userId)
+	if err != nil {
+		return fmt.Errorf("failed to assign reservation: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get assigned reservation count: %w", err)
+	}
+
+	if updated == 0 {
+		var exists bool
+		if err := l.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM wallet.reservations WHERE id = $1 AND user_id = $2)`,
+			id, userId).
+			Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check reservation exists: %w", err)
+		}
+
+		if !exists {
+			return errNoReservation
+		}
+
+		return errReservationAssigned
+	}
+
+	return nil
+}
+
+// assignedOrderIds returns the ids of the orders that have funds reserved.
+func (l *sqlLedger) assignedOrderIds(ctx context.Context) ([]string, error) {
+	rows, err := l.db.QueryContext(ctx, `SELECT order_id FROM wallet.reservations WHERE order_id IS NOT NULL
+		AND status = $1`, reservationReserved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reserved orders: %w", err)
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var orderId string
+		if err := rows.Scan(&orderId); err != nil {
+			return nil, fmt.Errorf("failed to scan order id: %w", err)
+		}
+		result = append(result, orderId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read reserved orders: %w", err)
+	}
+
+	return result, nil
+}
+
+type reservation struct {
+	id       int64
+	orderId  string
+	userId   string
+	currency string
+	amount   decimal.Decimal
+	debited  decimal.Decimal
+	status   string
+}
+
+// lockReservation returns the reservation with the given id, or if the id is empty the reservation of the order, and
+// locks it until the end of the transaction.
+func lockReservation(ctx context.Context, tx *sql.Tx, reservationId string, orderId string) (reservation, error) {
+	query := `SELECT id, COALESCE(order_id, ''), user_id, currency, amount, debited, status FROM wallet.reservations `
+	var row *sql.Row
+	if reservationId != "" {
+		id, err := strconv.ParseInt(reservationId, 10, 64)
+		if err != nil {
+			return reservation{}, errNoReservation
+		}
+		row = tx.QueryRowContext(ctx, query+`WHERE id = $1 FOR UPDATE`, id)
+	} else {
+		row = tx.QueryRowContext(ctx, query+`WHERE order_id = $1 FOR UPDATE`, orderId)
+	}
+
+	r := reservation{}
+	err := row.Scan(&r.id, &r.orderId, &r.userId, &r.currency, &r.amount, &r.debited, &r.status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return reservation{}, errNoReservation
+	}
+	if err != nil {
+		return reservation{}, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return r, nil
+}
+
+// release returns the funds that remain reserved to the user's available account, the reservation is identified by
+// its id or if the id is empty by its order and must be a reservation of the user.  Releasing a reservation that has
+// already been released returns zero released funds.
+func (l *sqlLedger) release(ctx context.Context, userId string, reservationId string, orderId string) (releasedFunds, error) {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return releasedFunds{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	r, err := lockReservation(ctx, tx, reservationId, orderId)
+	if err != nil {
+		return releasedFunds{}, err
+	}
+
+	if r.userId != userId {
+		return releasedFunds{}, errNoReservation
+	}
+
+	released := releasedFunds{userId: r.userId, currency: r.currency, amount: decimal.New(0, 0)}
+	if r.status != reservationReserved {
+		return released, nil
+	}
+
+	if err := adjustReservation(ctx, tx, r, decimal.New(0, 0)); err != nil {
+		return releasedFunds{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE wallet.reservations SET amount = 0, status = $1 WHERE id = $2`,
+		reservationReleased, r.id); err != nil {
+		return releasedFunds{}, fmt.Errorf("failed to update reservation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return releasedFunds{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	released.amount = r.amount
+	return released, nil
+}
+
+// orderFunds is the state of an order's funds, the traded value is the total cost of the order's fills and the
+// reserved value is the cost of the order's remaining quantity at its limit price, or zero if the order is done.
+type orderFunds struct {
+	tradedValue   decimal.Decimal
+	reservedValue decimal.Decimal
+	done          bool
+}
+
+// settle brings the order's reservation up to date with the order's funds.  The increase in the order's traded value
+// since the reservation was last settled is debited, from the locked account and then from the available account if
+// the fills exceed the reservation, and a decrease, e.g. due to a trade cancel, is refunded to the available account.
+// The locked amount is then adjusted to the reserved value, the reservation is released once the order is done.
+// Settling is idempotent, settling an order again with the same funds has no effect.  It returns false if the
+// reservation is unchanged.
+func (l *sqlLedger) settle(ctx context.Context, orderId string, funds orderFunds) (releasedFunds, bool, error) {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return releasedFunds{}, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	r, err := lockReservation(ctx, tx, "", orderId)
+	if err != nil {
+		return releasedFunds{}, false, err
+	}
+
+	settled := releasedFunds{userId: r.userId, currency: r.currency, amount: decimal.New(0, 0)}
+	if r.status != reservationReserved {
+		return settled, false, nil
+	}
+
+	debit := funds.tradedValue.Sub(r.debited)
+	fromLocked := decimal.Min(decimal.Max(debit, decimal.New(0, 0)), r.amount)
+	fromAvailable := debit.Sub(fromLocked)
+
+	if !debit.IsZero() {
+		if _, err := postTransaction(ctx, tx, transactionTrade, r.userId, r.currency, debit, orderId, []posting{
+			{account: accountKey{r.userId, r.currency, accountLocked}, amount: fromLocked.Neg()},
+			{account: accountKey{r.userId, r.currency, accountAvailable}, amount: fromAvailable.Neg()},
+			{account: accountKey{externalAccountOwner, r.currency, accountExternal}, amount: debit},
+		}); err != nil {
+			return releasedFunds{}, false, err
+		}
+		r.amount = r.amount.Sub(fromLocked)
+	}
+
+	reservedValue := funds.reservedValue
+	if funds.done {
+		reservedValue = decimal.New(0, 0)
+	}
+
+	if debit.IsZero() && reservedValue.Equal(r.amount) && !funds.done {
+		return settled, false, nil
+	}
+
+	if err := adjustReservation(ctx, tx, r, reservedValue); err != nil {
+		return releasedFunds{}, false, err
+	}
+
+	status := reservationReserved
+	if funds.done {
+		status = reservationReleased
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE wallet.reservations SET amount = $1, debited = $2, status = $3 WHERE id = $4`,
+		reservedValue, funds.tradedValue, status, r.id); err != nil {
+		return releasedFunds{}, false, fmt.Errorf("failed to update reservation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return releasedFunds{}, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return settled, true, nil
+}
+
+// adjustReservation moves funds between the user's available and locked accounts so that the reservation's locked
+// amount becomes the given amount.
+func adjustReservation(ctx context.Context, tx *sql.Tx, r reservation, amount decimal.Decimal) error {
+	change := amount.Sub(r.amount)
+	if change.IsZero() {
+		return nil
+	}
+
+	transactionType := transactionReservation
+	if change.IsNegative() {
+		transactionType = transactionRelease
+	}
+
+	_, err := postTransaction(ctx, tx, transactionType, r.userId, r.currency, change.Abs(), r.orderId, []posting{
+		{account: accountKey{r.userId, r.currency, accountAvailable}, amount: change.Neg()},
+		{account: accountKey{r.userId, r.currency, accountLocked}, amount: change},
+	})
+
+	return err
+}
+
+const balancesSelect = `SELECT currency,
+	COALESCE(SUM(balance) FILTER (WHERE kind = 'available'), 0),
+	COALESCE(SUM(balance) FILTER (WHERE kind = 'locked'), 0),
+	MAX(last_updated)
+	FROM wallet.accounts WHERE user_id = $1 AND kind IN ('available', 'locked')`
+
+// getBalance returns the user's balance in the currency, a user without an account in the currency has a zero balance.
+func (l *sqlLedger) getBalance(ctx context.Context, userId string, currency string) (*model.Balance, error) {
+	balances, err := l.queryBalances(ctx, balancesSelect+` AND currency = $2 GROUP BY currency`, userId, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(balances) == 0 {
+		return &model.Balance{UserId: userId, Currency: currency}, nil
+	}
+
+	return balances[0], nil
+}
+
+func (l *sqlLedger) listBalances(ctx context.Context, userId string) ([]*model.Balance, error) {
+	return l.queryBalances(ctx, balancesSelect+` GROUP BY currency ORDER BY currency`, userId)
+}
+
+func (l *sqlLedger) queryBalances(ctx context.Context, query string, userId string, args ...interface{}) ([]*model.Balance, error) {
+	rows, err := l.db.QueryContext(ctx, query, append([]interface{}{userId}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balances: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Balance
+	for rows.Next() {
+		var currency string
+		var available, locked decimal.Decimal
+		var lastUpdated time.Time
+		if err := rows.Scan(&currency, &available, &locked, &lastUpdated); err != nil {
+			return nil, fmt.Errorf("failed to scan balance: %w", err)
+		}
+
+		result = append(result, &model.Balance{
+			UserId:      userId,
+			Currency:    currency,
+			Balance:     toFloat(available.Add(locked)),
+			Available:   toFloat(available),
+			Locked:      toFloat(locked),
+			LastUpdated: common.NewTimeStamp(lastUpdated),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read balances: %w", err)
+	}
+
+	return result, nil
+}
+
+// getTransactionHistory returns a page of the journal entries of the user's accounts, most recent first, and the total
+// number of entries.  Pages are numbered from 1, an empty currency returns the entries of all currencies.
+func (l *sqlLedger) getTransactionHistory(ctx context.Context, userId string, currency string, page int32,
+	pageSize int32) ([]*model.Transaction, int32, error) {
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	} else if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	const where = ` FROM wallet.journal_entries e INNER JOIN wallet.transactions t ON e.transaction_id = t.id
+		INNER JOIN wallet.accounts a ON e.account_id = a.id WHERE a.user_id = $1 AND ($2 = '' OR a.currency = $2)`
+
+	var totalCount int32
+	if err := l.db.QueryRowContext(ctx, `SELECT COUNT(*)`+where, userId, currency).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count journal entries: %w", err)
+	}
+
+	rows, err := l.db.QueryContext(ctx, `SELECT t.id, e.account_id, t.type, e.amount, t.reference, t.created`+where+
+		` ORDER BY e.id DESC LIMIT $3 OFFSET $4`, userId, currency, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Transaction
+	for rows.Next() {
+		var transactionId, accountId int64
+		var transactionType, reference string
+		var amount decimal.Decimal
+		var created time.Time
+		if err := rows.Scan(&transactionId, &accountId, &transactionType, &amount, &reference, &created); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+
+		result = append(result, &model.Transaction{
+			Id:        strconv.FormatInt(transactionId, 10),
+			AccountId: strconv.FormatInt(accountId, 10),
+			Type:      transactionType,
+			Amount:    toFloat(amount),
+			Reference: reference,
+			Timestamp: common.NewTimeStamp(created),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read journal entries: %w", err)
+	}
+
+	return result, totalCount, nil
+}
+
+func toFloat(d decimal.Decimal) float64 {
+	f, _ := d.Float64()
+	return f
+}
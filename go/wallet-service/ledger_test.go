@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+)
+
+var (
+	externalUSD  = testAccount{key: accountKey{externalAccountOwner, "USD", accountExternal}, id: 1}
+	availableUSD = testAccount{key: accountKey{"user1", "USD", accountAvailable}, id: 2}
+	lockedUSD    = testAccount{key: accountKey{"user1", "USD", accountLocked}, id: 3}
+)
+
+type testAccount struct {
+	key accountKey
+	id  int64
+}
+
+type testPosting struct {
+	account testAccount
+	amount  int64
+}
+
+type testTransaction struct {
+	id              int64
+	transactionType string
+	amount          int64
+	reference       string
+	postings        []testPosting
+	// overdrawn is the id of the account whose balance update affects no rows as the posting would overdraw it
+	overdrawn int64
+}
+
+func newMockLedger(t *testing.T) (*sqlLedger, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock database: %v", err)
+	}
+
+	return &sqlLedger{db: db}, mock
+}
+
+func amount(value int64) decimal.Decimal {
+	return decimal.New(value, 0)
+}
+
+// expectTransaction expects the transaction and its journal entries to be written and the postings to be applied to
+// the account balances in account id order.  The entries of a transaction must sum to zero.
+func expectTransaction(t *testing.T, mock sqlmock.Sqlmock, transaction testTransaction) {
+	total := amount(0)
+	for _, p := range transaction.postings {
+		total = total.Add(amount(p.amount))
+	}
+	assert.True(t, total.IsZero(), "postings of test transaction do not balance")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO wallet.transactions`)).
+		WithArgs(transaction.transactionType, "user1", "USD", amount(transaction.amount), transaction.reference,
+			sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transaction.id))
+
+	var postings []testPosting
+	for _, p := range transaction.postings {
+		if p.amount == 0 {
+			continue
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO wallet.accounts`)).
+			WithArgs(p.account.key.userId, p.account.key.currency, p.account.key.kind).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM wallet.accounts`)).
+			WithArgs(p.account.key.userId, p.account.key.currency, p.account.key.kind).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(p.account.id))
+		postings = append(postings, p)
+	}
+
+	sort.Slice(postings, func(i, j int) bool { return postings[i].account.id < postings[j].account.id })
+
+	for _, p := range postings {
+		var updated int64 = 1
+		if p.account.id == transaction.overdrawn {
+			updated = 0
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE wallet.accounts SET balance = balance + $1`)).
+			WithArgs(amount(p.amount), sqlmock.AnyArg(), p.account.id, accountExternal).
+			WillReturnResult(sqlmock.NewResult(0, updated))
+
+		if updated == 0 {
+			return
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO wallet.journal_entries`)).
+			WithArgs(transaction.id, p.account.id, amount(p.amount)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
+func expectReservation(mock sqlmock.Sqlmock, id int64, orderId string, reserved int64, debited int64, status string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, COALESCE(order_id, ''), user_id, currency, amount, debited, status FROM wallet.reservations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "user_id", "currency", "amount", "debited", "status"}).
+			AddRow(id, orderId, "user1", "USD", amount(reserved).String(), amount(debited).String(), status))
+}
+
+func TestDepositPostsBalancedJournalEntries(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectTransaction(t, mock, testTransaction{id: 10, transactionType: transactionDeposit, amount: 100,
+		reference: "bank_transfer", postings: []testPosting{{externalUSD, -100}, {availableUSD, 100}}})
+	mock.ExpectCommit()
+
+	id, err := ledger.deposit(context.Background(), "user1", "USD", amount(100), "bank_transfer")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithdrawalThatWouldOverdrawTheAccountIsRejected(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectTransaction(t, mock, testTransaction{id: 10, transactionType: transactionWithdrawal, amount: 150,
+		reference: "bank1", postings: []testPosting{{availableUSD, -150}, {externalUSD, 150}},
+		overdrawn: availableUSD.id})
+	mock.ExpectRollback()
+
+	_, err := ledger.withdraw(context.Background(), "user1", "USD", amount(150), "bank1")
+	assert.True(t, errors.Is(err, errInsufficientFunds), "unexpected error: %v", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnbalancedPostingsAreRejected(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	tx, err := ledger.db.Begin()
+	assert.NoError(t, err)
+
+	_, err = postTransaction(context.Background(), tx, transactionTransfer, "user1", "USD", amount(100), "user2",
+		[]posting{{account: availableUSD.key, amount: amount(-100)}, {account: lockedUSD.key, amount: amount(90)}})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConcurrentReservesCannotOverdrawTheAvailableAccount(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+	mock.MatchExpectationsInOrder(false)
+
+	// Both reserves read the same balance, only the database's conditional update of the available balance decides
+	// which of them succeeds
+	for i, overdrawn := range []int64{0, availableUSD.id} {
+		mock.ExpectBegin()
+		expectTransaction(t, mock, testTransaction{id: int64(10 + i), transactionType: transactionReservation,
+			amount: 60, postings: []testPosting{{availableUSD, -60}, {lockedUSD, 60}}, overdrawn: overdrawn})
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO wallet.reservations`)).
+		WithArgs("", "user1", "USD", amount(60), reservationReserved).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = ledger.reserve(context.Background(), "", "user1", "USD", amount(60))
+		}(i)
+	}
+	wg.Wait()
+
+	var insufficientFunds int
+	for _, err := range errs {
+		if errors.Is(err, errInsufficientFunds) {
+			insufficientFunds++
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	assert.Equal(t, 1, insufficientFunds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveForAnOrderWithAReservationReturnsTheExistingReservation(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM wallet.reservations WHERE order_id = $1`)).WithArgs("order1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectRollback()
+
+	id, err := ledger.reserve(context.Background(), "order1", "user1", "USD", amount(60))
+	assert.NoError(t, err)
+	assert.Equal(t, "5", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseReturnsTheReservedFundsToTheAvailableAccount(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectReservation(mock, 5, "order1", 60, 0, reservationReserved)
+	expectTransaction(t, mock, testTransaction{id: 10, transactionType: transactionRelease, amount: 60,
+		reference: "order1", postings: []testPosting{{availableUSD, 60}, {lockedUSD, -60}}})
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE wallet.reservations SET amount = 0, status = $1 WHERE id = $2`)).
+		WithArgs(reservationReleased, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	released, err := ledger.release(context.Background(), "user1", "5", "")
+	assert.NoError(t, err)
+	assert.True(t, released.amount.Equal(amount(60)), "released %v", released.amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseOfAReleasedReservationHasNoEffect(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectReservation(mock, 5, "order1", 0, 0, reservationReleased)
+	mock.ExpectRollback()
+
+	released, err := ledger.release(context.Background(), "user1", "", "order1")
+	assert.NoError(t, err)
+	assert.True(t, released.amount.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseOfTheReservationOfAnotherUserIsRejected(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectReservation(mock, 5, "order1", 60, 0, reservationReserved)
+	mock.ExpectRollback()
+
+	_, err := ledger.release(context.Background(), "user2", "5", "")
+	assert.Equal(t, errNoReservation, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettleDebitsTheFillsAndReducesTheReservationToTheRemainingQuantity(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectReservation(mock, 5, "order1", 200, 0, reservationReserved)
+	expectTransaction(t, mock, testTransaction{id: 10, transactionType: transactionTrade, amount: 76,
+		reference: "order1", postings: []testPosting{{lockedUSD, -76}, {availableUSD, 0}, {externalUSD, 76}}})
+	expectTransaction(t, mock, testTransaction{id: 11, transactionType: transactionRelease, amount: 4,
+		reference: "order1", postings: []testPosting{{availableUSD, 4}, {lockedUSD, -4}}})
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE wallet.reservations SET amount = $1, debited = $2, status = $3 WHERE id = $4`)).
+		WithArgs(amount(120), amount(76), reservationReserved, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, changed, err := ledger.settle(context.Background(), "order1",
+		orderFunds{tradedValue: amount(76), reservedValue: amount(120)})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettleDebitsFillsExceedingTheReservationFromTheAvailableAccount(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectReservation(mock, 5, "order1", 100, 0, reservationReserved)
+	expectTransaction(t, mock, testTransaction{id: 10, transactionType: transactionTrade, amount: 110,
+		reference: "order1", postings: []testPosting{{lockedUSD, -100}, {availableUSD, -10}, {externalUSD, 110}}})
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE wallet.reservations SET amount = $1, debited = $2, status = $3 WHERE id = $4`)).
+		WithArgs(amount(0), amount(110), reservationReleased, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, changed, err := ledger.settle(context.Background(), "order1",
+		orderFunds{tradedValue: amount(110), reservedValue: amount(0), done: true})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettlingAgainWithTheSameFundsHasNoEffect(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	expectReservation(mock, 5, "order1", 120, 76, reservationReserved)
+	mock.ExpectRollback()
+
+	_, changed, err := ledger.settle(context.Background(), "order1",
+		orderFunds{tradedValue: amount(76), reservedValue: amount(120)})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettleOfAnOrderWithoutAReservationFails(t *testing.T) {
+	ledger, mock := newMockLedger(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, COALESCE(order_id, '')`)).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, _, err := ledger.settle(context.Background(), "order1", orderFunds{tradedValue: amount(76)})
+	assert.Equal(t, errNoReservation, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
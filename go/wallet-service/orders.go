@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ettec/otp-common/model"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/kafka-go"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// streamOrders returns a channel of all the order updates in the orders topic from the first available offset, the
+// channel is closed if the context is cancelled or an update cannot be read.
+func streamOrders(ctx context.Context, readerConfig kafka.ReaderConfig, bufferSize int) <-chan *model.Order {
+	out := make(chan *model.Order, bufferSize)
+
+	go func() {
+		defer close(out)
+		reader := kafka.NewReader(readerConfig)
+		defer func() {
+			if err := reader.Close(); err != nil {
+				slog.Error("error closing kafka reader", "error", err)
+			}
+		}()
+
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("failed to read order update", "error", err)
+				}
+				return
+			}
+
+			order := &model.Order{}
+			if err = proto.Unmarshal(msg.Value, order); err != nil {
+				slog.Error("failed to unmarshal order", "offset", msg.Offset, "error", err)
+				return
+			}
+
+			select {
+			case out <- order:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+type funds interface {
+	assign(ctx context.Context, userId string, reservationId string, orderId string) error
+	settle(ctx context.Context, orderId string, funds orderFunds) error
+}
+
+// orderSettler settles the funds reserved for orders as the orders are updated, partial fills reduce the reservation
+// to the cost of the remaining quantity and debit the cost of the fills, and the reservation is released when the
+// order is done.  The user of an order is its root originator ref and only the root buy orders with a limit price can
+// have funds reserved.  A reservation is usually made before its order is created and assigned to the order once the
+// order has been routed, so the latest funds of an order created within the assignment timeout are kept until its
+// reservation is assigned or the timeout expires.  Orders are no longer tracked once they are done.  Settling is
+// idempotent so the orders topic is replayed from the start when the service starts.
+type orderSettler struct {
+	mutex             sync.Mutex
+	funds             funds
+	assignmentTimeout time.Duration
+	now               func() time.Time
+	assigned          map[string]bool
+	unassigned        map[string]unassignedOrder
+	lastPurge         time.Time
+}
+
+type unassignedOrder struct {
+	funds   orderFunds
+	created time.Time
+}
+
+func newOrderSettler(funds funds, assignedOrderIds []string, assignmentTimeout time.Duration) *orderSettler {
+	s := &orderSettler{
+		funds:             funds,
+		assignmentTimeout: assignmentTimeout,
+		now:               time.Now,
+		assigned:          map[string]bool{},
+		unassigned:        map[string]unassignedOrder{},
+	}
+
+	for _, orderId := range assignedOrderIds {
+		s.assigned[orderId] = true
+	}
+
+	return s
+}
+
+func getOrderFunds(order *model.Order) orderFunds {
+	funds := orderFunds{tradedValue: decimal.New(0, 0), reservedValue: decimal.New(0, 0), done: order.IsTerminalState()}
+
+	if order.TradedQuantity != nil && order.AvgTradePrice != nil {
+		funds.tradedValue = order.TradedQuantity.AsDecimal().Mul(order.AvgTradePrice.AsDecimal())
+	}
+
+	if order.RemainingQuantity != nil && !funds.done {
+		funds.reservedValue = order.Price.AsDecimal().Mul(order.RemainingQuantity.AsDecimal())
+	}
+
+	return funds
+}
+
+func (s *orderSettler) onOrder(ctx context.Context, order *model.Order) error {
+	if order.Side != model.Side_BUY || order.Price == nil ||
+		order.OriginatorId != order.RootOriginatorId || order.OriginatorRef != order.RootOriginatorRef {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	funds := getOrderFunds(order)
+
+	if s.assigned[order.Id] {
+		return s.settle(ctx, order.Id, funds)
+	}
+
+	now := s.now()
+	s.purgeUnassigned(now)
+
+	if order.Created == nil {
+		return nil
+	}
+
+	created := time.Unix(order.Created.Seconds, int64(order.Created.Nanoseconds))
+	if now.Sub(created) > s.assignmentTimeout {
+		return nil
+	}
+
+	s.unassigned[order.Id] = unassignedOrder{funds: funds, created: created}
+	return nil
+}
+
+// purgeUnassigned removes the orders that were created longer ago than the assignment timeout, they are not expected
+// to be assigned a reservation.
+func (s *orderSettler) purgeUnassigned(now time.Time) {
+	if now.Sub(s.lastPurge) < s.assignmentTimeout {
+		return
+	}
+	s.lastPurge = now
+
+	for orderId, order := range s.unassigned {
+		if now.Sub(order.created) > s.assignmentTimeout {
+			delete(s.unassigned, orderId)
+		}
+	}
+}
+
+// assign assigns the user's reservation to the order and settles the reservation with the order's latest funds.
+func (s *orderSettler) assign(ctx context.Context, userId string, reservationId string, orderId string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.funds.assign(ctx, userId, reservationId, orderId); err != nil {
+		return err
+	}
+
+	s.assigned[orderId] = true
+
+	order, ok := s.unassigned[orderId]
+	if !ok {
+		return nil
+	}
+	delete(s.unassigned, orderId)
+
+	return s.settle(ctx, orderId, order.funds)
+}
+
+func (s *orderSettler) settle(ctx context.Context, orderId string, funds orderFunds) error {
+	err := s.funds.settle(ctx, orderId, funds)
+	if errors.Is(err, errInsufficientFunds) {
+		slog.Error("insufficient funds to settle the fills of order", "orderId", orderId, "tradedValue",
+			funds.tradedValue)
+		err = nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to settle funds of order %v: %w", orderId, err)
+	}
+
+	if funds.done {
+		delete(s.assigned, orderId)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestOrder(id string, status model.OrderStatus, quantity int, price int, tradedQuantity int,
+	avgTradePrice int) *model.Order {
+	return &model.Order{
+		Id:                id,
+		Side:              model.Side_BUY,
+		Status:            status,
+		Quantity:          model.IasD(quantity),
+		Price:             model.IasD(price),
+		RemainingQuantity: model.IasD(quantity - tradedQuantity),
+		TradedQuantity:    model.IasD(tradedQuantity),
+		AvgTradePrice:     model.IasD(avgTradePrice),
+		OriginatorId:      "desk1",
+		OriginatorRef:     "user1",
+		RootOriginatorId:  "desk1",
+		RootOriginatorRef: "user1",
+		Created:           model.NewTimeStamp(time.Now()),
+	}
+}
+
+func newTestSettler(t *testing.T) (*orderSettler, *testLedger, *wallet) {
+	ledger := newTestLedger()
+	w := newWallet(ledger)
+	_, err := w.deposit(context.Background(), "user1", "USD", decimal.New(10000, 0), "bank_transfer")
+	assert.NoError(t, err)
+
+	return newOrderSettler(w, nil, time.Minute), ledger, w
+}
+
+func assertSettled(t *testing.T, ledger *testLedger, orderId string, tradedValue int64, reservedValue int64, done bool) {
+	funds, ok := ledger.settled[orderId]
+	if !assert.True(t, ok, "order %v not settled", orderId) {
+		return
+	}
+
+	assert.True(t, funds.tradedValue.Equal(decimal.New(tradedValue, 0)), "traded value %v", funds.tradedValue)
+	assert.True(t, funds.reservedValue.Equal(decimal.New(reservedValue, 0)), "reserved value %v", funds.reservedValue)
+	assert.Equal(t, done, funds.done)
+}
+
+func TestPartialFillsAdjustTheReservationAndTheOrderIsReleasedWhenDone(t *testing.T) {
+	ctx := context.Background()
+	settler, ledger, w := newTestSettler(t)
+
+	reservationId, err := w.reserve(ctx, "", "user1", "USD", decimal.New(200, 0))
+	assert.NoError(t, err)
+	assert.NoError(t, settler.assign(ctx, "user1", reservationId, "order1"))
+	assert.Empty(t, ledger.settled)
+
+	assert.NoError(t, settler.onOrder(ctx, newTestOrder("order1", model.OrderStatus_LIVE, 10, 20, 4, 19)))
+	assertSettled(t, ledger, "order1", 76, 120, false)
+
+	assert.NoError(t, settler.onOrder(ctx, newTestOrder("order1", model.OrderStatus_CANCELLED, 10, 20, 4, 19)))
+	assertSettled(t, ledger, "order1", 76, 0, true)
+	assert.Empty(t, settler.unassigned)
+	assert.Empty(t, settler.assigned)
+}
+
+func TestUpdatesReceivedBeforeTheReservationIsAssignedAreSettledOnAssignment(t *testing.T) {
+	ctx := context.Background()
+	settler, ledger, w := newTestSettler(t)
+
+	reservationId, err := w.reserve(ctx, "", "user1", "USD", decimal.New(200, 0))
+	assert.NoError(t, err)
+
+	assert.NoError(t, settler.onOrder(ctx, newTestOrder("order1", model.OrderStatus_FILLED, 10, 20, 10, 18)))
+	assert.Empty(t, ledger.settled)
+
+	assert.NoError(t, settler.assign(ctx, "user1", reservationId, "order1"))
+	assertSettled(t, ledger, "order1", 180, 0, true)
+	assert.Empty(t, settler.unassigned)
+	assert.Empty(t, settler.assigned)
+
+	assert.Equal(t, errNoReservation, settler.assign(ctx, "user1", "99", "order2"))
+}
+
+func TestOnlyRootBuyLimitOrdersAreSettled(t *testing.T) {
+	ctx := context.Background()
+	ledger := newTestLedger()
+	settler := newOrderSettler(newWallet(ledger), []string{"order1", "order2", "order3"}, time.Minute)
+
+	sellOrder := newTestOrder("order1", model.OrderStatus_LIVE, 10, 20, 0, 0)
+	sellOrder.Side = model.Side_SELL
+	assert.NoError(t, settler.onOrder(ctx, sellOrder))
+
+	marketOrder := newTestOrder("order2", model.OrderStatus_LIVE, 10, 20, 0, 0)
+	marketOrder.Price = nil
+	assert.NoError(t, settler.onOrder(ctx, marketOrder))
+
+	childOrder := newTestOrder("order3", model.OrderStatus_LIVE, 10, 20, 0, 0)
+	childOrder.OriginatorId = "vwap-strategy"
+	childOrder.OriginatorRef = "parentOrder1"
+	assert.NoError(t, settler.onOrder(ctx, childOrder))
+
+	assert.Empty(t, settler.unassigned)
+	assert.Empty(t, ledger.settled)
+}
+
+func TestOrdersThatAreNotAssignedAReservationAreNotKept(t *testing.T) {
+	ctx := context.Background()
+	settler, ledger, _ := newTestSettler(t)
+
+	now := time.Now()
+	settler.now = func() time.Time { return now }
+
+	replayed := newTestOrder("order1", model.OrderStatus_LIVE, 10, 20, 0, 0)
+	replayed.Created = model.NewTimeStamp(now.Add(-2 * time.Minute))
+	assert.NoError(t, settler.onOrder(ctx, replayed))
+	assert.Empty(t, settler.unassigned)
+
+	recent := newTestOrder("order2", model.OrderStatus_LIVE, 10, 20, 0, 0)
+	recent.Created = model.NewTimeStamp(now)
+	assert.NoError(t, settler.onOrder(ctx, recent))
+	assert.Len(t, settler.unassigned, 1)
+
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, settler.onOrder(ctx, replayed))
+	assert.Empty(t, settler.unassigned)
+	assert.Empty(t, ledger.settled)
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/ettec/open-trading-platform/go/wallet-service/api/walletservice"
+	common "github.com/ettec/otp-common"
+	"github.com/ettec/otp-common/bootstrap"
+	"github.com/ettec/otp-common/model"
+	"github.com/ettec/otp-common/orderstore"
+	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"log"
+	"log/slog"
+	"math"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const statusCompleted = "completed"
+
+type service struct {
+	wallet  *wallet
+	settler *orderSettler
+}
+
+func newService(wallet *wallet, settler *orderSettler) *service {
+	return &service{wallet: wallet, settler: settler}
+}
+
+func toAmount(amount float64) (decimal.Decimal, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) || amount <= 0 {
+		return decimal.Decimal{}, status.Errorf(codes.InvalidArgument, "amount must be a positive number: %v", amount)
+	}
+
+	return decimal.NewFromFloat(amount), nil
+}
+
+func validateAccount(userId string, currency string) error {
+	if userId == "" {
+		return status.Error(codes.InvalidArgument, "user id must be specified")
+	}
+
+	if currency == "" {
+		return status.Error(codes.InvalidArgument, "currency must be specified")
+	}
+
+	return nil
+}
+
+// getUser returns the authenticated user making the request, the user name is set in the request metadata by the
+// platform's authorization layer.
+func getUser(ctx context.Context) (string, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if usernames := md.Get("user-name"); len(usernames) == 1 && usernames[0] != "" {
+			return usernames[0], nil
+		}
+	}
+
+	return "", status.Error(codes.Unauthenticated, "unable to retrieve user-name from metadata")
+}
+
+// authorize returns an error unless the request is made by the user whose account it acts on.
+func authorize(ctx context.Context, userId string) error {
+	user, err := getUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if user != userId {
+		return status.Errorf(codes.PermissionDenied, "user %v is not permitted to access the account of user %v", user,
+			userId)
+	}
+
+	return nil
+}
+
+// toStatusError maps ledger errors to the grpc status returned to the client.
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, errInsufficientFunds):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errNoReservation):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errReservationAssigned):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func (s *service) GetBalance(ctx context.Context, request *api.GetBalanceRequest) (*api.GetBalanceResponse, error) {
+	if err := validateAccount(request.UserId, request.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, request.UserId); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.wallet.ledger.getBalance(ctx, request.UserId, request.Currency)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.GetBalanceResponse{Balance: balance}, nil
+}
+
+func (s *service) ListBalances(ctx context.Context, request *api.ListBalancesRequest) (*api.ListBalancesResponse, error) {
+	if err := authorize(ctx, request.UserId); err != nil {
+		return nil, err
+	}
+
+	balances, err := s.wallet.ledger.listBalances(ctx, request.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.ListBalancesResponse{Balances: balances}, nil
+}
+
+func (s *service) Deposit(ctx context.Context, request *api.DepositRequest) (*api.DepositResponse, error) {
+	if err := validateAccount(request.UserId, request.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, request.UserId); err != nil {
+		return nil, err
+	}
+
+	amount, err := toAmount(request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.wallet.deposit(ctx, request.UserId, request.Currency, amount, request.Method)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.DepositResponse{Status: statusCompleted, DepositId: id}, nil
+}
+
+func (s *service) Withdraw(ctx context.Context, request *api.WithdrawRequest) (*api.WithdrawResponse, error) {
+	if err := validateAccount(request.UserId, request.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, request.UserId); err != nil {
+		return nil, err
+	}
+
+	amount, err := toAmount(request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.wallet.withdraw(ctx, request.UserId, request.Currency, amount, request.Destination)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.WithdrawResponse{Status: statusCompleted, WithdrawalId: id}, nil
+}
+
+func (s *service) Transfer(ctx context.Context, request *api.TransferRequest) (*api.TransferResponse, error) {
+	if err := validateAccount(request.FromUserId, request.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, request.FromUserId); err != nil {
+		return nil, err
+	}
+
+	if request.ToUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "to user id must be specified")
+	}
+
+	if request.ToUserId == request.FromUserId {
+		return nil, status.Error(codes.InvalidArgument, "cannot transfer funds to the same user")
+	}
+
+	amount, err := toAmount(request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.wallet.transfer(ctx, request.FromUserId, request.ToUserId, request.Currency, amount)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.TransferResponse{Status: statusCompleted, TransferId: id}, nil
+}
+
+func (s *service) GetTransactionHistory(ctx context.Context, request *api.GetTransactionHistoryRequest) (*api.GetTransactionHistoryResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id must be specified")
+	}
+
+	if err := authorize(ctx, request.UserId); err != nil {
+		return nil, err
+	}
+
+	transactions, totalCount, err := s.wallet.ledger.getTransactionHistory(ctx, request.UserId, request.Currency,
+		request.Page, request.PageSize)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.GetTransactionHistoryResponse{Transactions: transactions, TotalCount: totalCount}, nil
+}
+
+func (s *service) ReserveFunds(ctx context.Context, request *api.ReserveFundsRequest) (*api.ReserveFundsResponse, error) {
+	if err := validateAccount(request.UserId, request.Currency); err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, request.UserId); err != nil {
+		return nil, err
+	}
+
+	amount, err := toAmount(request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.wallet.reserve(ctx, request.OrderId, request.UserId, request.Currency, amount)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.ReserveFundsResponse{Status: statusCompleted, ReservationId: id}, nil
+}
+
+func (s *service) AssignReservation(ctx context.Context, request *api.AssignReservationRequest) (*api.AssignReservationResponse, error) {
+	if request.ReservationId == "" || request.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "reservation id and order id must be specified")
+	}
+
+	user, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.settler.assign(ctx, user, request.ReservationId, request.OrderId); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.AssignReservationResponse{Status: statusCompleted}, nil
+}
+
+func (s *service) ReleaseFunds(ctx context.Context, request *api.ReleaseFundsRequest) (*api.ReleaseFundsResponse, error) {
+	if request.ReservationId == "" && request.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order id or reservation id must be specified")
+	}
+
+	user, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	released, err := s.wallet.release(ctx, user, request.ReservationId, request.OrderId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &api.ReleaseFundsResponse{Status: statusCompleted, ReleasedAmount: toFloat(released.amount)}, nil
+}
+
+func (s *service) SubscribeBalanceUpdates(request *api.SubscribeBalanceUpdatesRequest, stream api.WalletService_SubscribeBalanceUpdatesServer) error {
+	if err := authorize(stream.Context(), request.UserId); err != nil {
+		return err
+	}
+
+	slog.Info("subscribing to balance updates", "userId", request.UserId)
+
+	balances, subscription, err := s.wallet.subscribe(stream.Context(), request.UserId)
+	if err != nil {
+		return toStatusError(err)
+	}
+	defer func() {
+		s.wallet.balances.unsubscribe(subscription)
+		slog.Info("unsubscribed from balance updates", "userId", request.UserId)
+	}()
+
+	for {
+		for _, balance := range balances {
+			if err := stream.Send(balance); err != nil {
+				return fmt.Errorf("failed to send balance: %w", err)
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-subscription.changed:
+			balances = s.wallet.balances.takeChanges(subscription)
+		}
+	}
+}
+
+const maxSettleBackoff = 30 * time.Second
+
+// settleOrderFunds applies the order updates to the funds reserved for the orders until the context is cancelled or
+// the orders channel is closed.  An update that fails to settle is retried with an exponential backoff, and skipped
+// once it has failed the maximum number of attempts so that later updates are still settled.  As an order update
+// carries the order's cumulative fills a skipped update is settled by the order's next update, or when the orders
+// topic is replayed on restart.
+func settleOrderFunds(ctx context.Context, settler *orderSettler, orders <-chan *model.Order, maxAttempts int,
+	initialBackoff time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case order, ok := <-orders:
+			if !ok {
+				return errors.New("orders channel closed")
+			}
+
+			settleWithRetry(ctx, settler, order, maxAttempts, initialBackoff)
+		}
+	}
+}
+
+func settleWithRetry(ctx context.Context, settler *orderSettler, order *model.Order, maxAttempts int,
+	initialBackoff time.Duration) {
+	backoff := initialBackoff
+	for attempt := 1; ; attempt++ {
+		err := settler.onOrder(ctx, order)
+		if err == nil {
+			return
+		}
+
+		if attempt >= maxAttempts {
+			slog.Error("failed to settle order update, the update has been skipped", "orderId", order.Id,
+				"version", order.Version, "attempts", attempt, "error", err)
+			return
+		}
+
+		slog.Warn("failed to settle order update, retrying", "orderId", order.Id, "attempt", attempt,
+			"backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(2*backoff, maxSettleBackoff)
+	}
+}
+
+func main() {
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
+
+	dbString := bootstrap.GetEnvVar("DB_CONN_STRING")
+	dbDriverName := bootstrap.GetEnvVar("DB_DRIVER_NAME")
+	kafkaBrokers := strings.Split(bootstrap.GetEnvVar("KAFKA_BROKERS"), ",")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sqlLedger, err := newSqlLedger(dbDriverName, dbString)
+	if err != nil {
+		log.Panicf("failed to create ledger: %v", err)
+	}
+	defer func() {
+		if err := sqlLedger.Close(); err != nil {
+			slog.Error("error closing ledger", "error", err)
+		}
+	}()
+
+	w := newWallet(sqlLedger)
+
+	assignedOrderIds, err := sqlLedger.assignedOrderIds(ctx)
+	if err != nil {
+		log.Panicf("failed to get orders with reserved funds: %v", err)
+	}
+
+	assignmentTimeout := time.Duration(bootstrap.GetOptionalIntEnvVar("RESERVATION_ASSIGNMENT_TIMEOUT_SECONDS", 60)) * time.Second
+	settler := newOrderSettler(w, assignedOrderIds, assignmentTimeout)
+
+	port := "50551"
+	slog.Info("Starting wallet service", "port", port)
+	listener, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		log.Panicf("Error while listening : %v", err)
+	}
+
+	s := grpc.NewServer()
+	api.RegisterWalletServiceServer(s, newService(w, settler))
+	reflection.Register(s)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh,
+		syscall.SIGKILL,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+	go func() {
+		<-sigCh
+		cancel()
+		s.GracefulStop()
+	}()
+
+	go func() {
+		orders := streamOrders(ctx, orderstore.DefaultReaderConfig(common.ORDERS_TOPIC, kafkaBrokers),
+			bootstrap.GetOptionalIntEnvVar("ORDERS_BUFFER_SIZE", 1000))
+		maxAttempts := bootstrap.GetOptionalIntEnvVar("SETTLE_MAX_ATTEMPTS", 10)
+		initialBackoff := time.Duration(bootstrap.GetOptionalIntEnvVar("SETTLE_RETRY_INITIAL_BACKOFF_MILLIS", 100)) *
+			time.Millisecond
+		if err := settleOrderFunds(ctx, settler, orders, maxAttempts, initialBackoff); err != nil {
+			log.Panicf("order funds settlement failed: %v", err)
+		}
+	}()
+
+	if err := s.Serve(listener); err != nil {
+		log.Panicf("Error while serving : %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	api "github.com/ettec/open-trading-platform/go/wallet-service/api/walletservice"
+	"github.com/ettec/otp-common/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math"
+	"testing"
+	"time"
+)
+
+func newUserContext(user string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-name", user))
+}
+
+func TestInvalidRequestsAreRejected(t *testing.T) {
+	ctx := newUserContext("user1")
+	w := newWallet(newTestLedger())
+	s := newService(w, newOrderSettler(w, nil, time.Minute))
+
+	_, err := s.Deposit(ctx, &api.DepositRequest{UserId: "user1", Currency: "USD", Amount: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Deposit(ctx, &api.DepositRequest{UserId: "user1", Currency: "USD", Amount: math.NaN()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Deposit(ctx, &api.DepositRequest{UserId: "user1", Amount: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Transfer(ctx, &api.TransferRequest{FromUserId: "user1", ToUserId: "user1", Currency: "USD", Amount: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.ReserveFunds(ctx, &api.ReserveFundsRequest{UserId: "user1", Amount: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.AssignReservation(ctx, &api.AssignReservationRequest{ReservationId: "1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.ReleaseFunds(ctx, &api.ReleaseFundsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLedgerErrorsAreMappedToStatusCodes(t *testing.T) {
+	ctx := newUserContext("user1")
+	w := newWallet(newTestLedger())
+	s := newService(w, newOrderSettler(w, nil, time.Minute))
+
+	response, err := s.Deposit(ctx, &api.DepositRequest{UserId: "user1", Currency: "USD", Amount: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, statusCompleted, response.Status)
+
+	_, err = s.Withdraw(ctx, &api.WithdrawRequest{UserId: "user1", Currency: "USD", Amount: 100.01})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = s.ReserveFunds(ctx, &api.ReserveFundsRequest{UserId: "user1", Currency: "USD", Amount: 150, OrderId: "order1"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = s.ReleaseFunds(ctx, &api.ReleaseFundsRequest{OrderId: "order2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.ReserveFunds(ctx, &api.ReserveFundsRequest{UserId: "user1", Currency: "USD", Amount: 60, OrderId: "order2"})
+	assert.NoError(t, err)
+
+	released, err := s.ReleaseFunds(ctx, &api.ReleaseFundsRequest{OrderId: "order2"})
+	assert.NoError(t, err)
+	assert.Equal(t, 60.0, released.ReleasedAmount)
+
+	released, err = s.ReleaseFunds(ctx, &api.ReleaseFundsRequest{OrderId: "order2"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, released.ReleasedAmount)
+
+	reserved, err := s.ReserveFunds(ctx, &api.ReserveFundsRequest{UserId: "user1", Currency: "USD", Amount: 40})
+	assert.NoError(t, err)
+
+	_, err = s.AssignReservation(ctx, &api.AssignReservationRequest{ReservationId: "99", OrderId: "order3"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.AssignReservation(ctx, &api.AssignReservationRequest{ReservationId: reserved.ReservationId, OrderId: "order3"})
+	assert.NoError(t, err)
+
+	released, err = s.ReleaseFunds(ctx, &api.ReleaseFundsRequest{ReservationId: reserved.ReservationId})
+	assert.NoError(t, err)
+	assert.Equal(t, 40.0, released.ReleasedAmount)
+
+	balance, err := s.GetBalance(ctx, &api.GetBalanceRequest{UserId: "user1", Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, balance.Balance.Available)
+}
+
+func TestRequestsForTheAccountOfAnotherUserAreRejected(t *testing.T) {
+	w := newWallet(newTestLedger())
+	s := newService(w, newOrderSettler(w, nil, time.Minute))
+
+	_, err := s.Deposit(newUserContext("user1"), &api.DepositRequest{UserId: "user1", Currency: "USD", Amount: 100})
+	assert.NoError(t, err)
+
+	reserved, err := s.ReserveFunds(newUserContext("user1"), &api.ReserveFundsRequest{UserId: "user1", Currency: "USD",
+		Amount: 40})
+	assert.NoError(t, err)
+
+	_, err = s.Withdraw(context.Background(), &api.WithdrawRequest{UserId: "user1", Currency: "USD", Amount: 10})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := newUserContext("user2")
+
+	_, err = s.Withdraw(ctx, &api.WithdrawRequest{UserId: "user1", Currency: "USD", Amount: 10})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.Transfer(ctx, &api.TransferRequest{FromUserId: "user1", ToUserId: "user2", Currency: "USD", Amount: 10})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.ReserveFunds(ctx, &api.ReserveFundsRequest{UserId: "user1", Currency: "USD", Amount: 10})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.GetBalance(ctx, &api.GetBalanceRequest{UserId: "user1", Currency: "USD"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.AssignReservation(ctx, &api.AssignReservationRequest{ReservationId: reserved.ReservationId,
+		OrderId: "order1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.ReleaseFunds(ctx, &api.ReleaseFundsRequest{ReservationId: reserved.ReservationId})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	balance, err := s.GetBalance(newUserContext("user1"), &api.GetBalanceRequest{UserId: "user1", Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, 60.0, balance.Balance.Available)
+	assert.Equal(t, 40.0, balance.Balance.Locked)
+}
+
+// failingFunds fails to settle the funds of an order the given number of times before settling them.
+type failingFunds struct {
+	*wallet
+	failures map[string]int
+}
+
+func (f *failingFunds) settle(ctx context.Context, orderId string, funds orderFunds) error {
+	if f.failures[orderId] > 0 {
+		f.failures[orderId]--
+		return errors.New("connection reset")
+	}
+
+	return f.wallet.settle(ctx, orderId, funds)
+}
+
+func TestFailedSettlementsAreRetriedAndSkippedAfterTheMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	ledger := newTestLedger()
+	w := newWallet(ledger)
+	_, err := w.deposit(ctx, "user1", "USD", decimal.New(10000, 0), "bank_transfer")
+	assert.NoError(t, err)
+
+	for _, orderId := range []string{"order1", "order2"} {
+		_, err := w.reserve(ctx, orderId, "user1", "USD", decimal.New(200, 0))
+		assert.NoError(t, err)
+	}
+
+	funds := &failingFunds{wallet: w, failures: map[string]int{"order1": 5, "order2": 2}}
+	settler := newOrderSettler(funds, []string{"order1", "order2"}, time.Minute)
+
+	orders := make(chan *model.Order, 2)
+	orders <- newTestOrder("order1", model.OrderStatus_LIVE, 10, 20, 4, 19)
+	orders <- newTestOrder("order2", model.OrderStatus_LIVE, 10, 20, 4, 19)
+	close(orders)
+
+	err = settleOrderFunds(ctx, settler, orders, 3, time.Millisecond)
+	assert.Error(t, err)
+
+	assert.Equal(t, 2, funds.failures["order1"])
+	assert.NotContains(t, ledger.settled, "order1")
+	assertSettled(t, ledger, "order2", 76, 120, false)
+}
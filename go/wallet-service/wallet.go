@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"github.com/ettec/open-trading-platform/go/wallet-service/api/model"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"sync"
+)
+
+type ledger interface {
+	deposit(ctx context.Context, userId string, currency string, amount decimal.Decimal, method string) (string, error)
+	withdraw(ctx context.Context, userId string, currency string, amount decimal.Decimal, destination string) (string, error)
+	transfer(ctx context.Context, fromUserId string, toUserId string, currency string, amount decimal.Decimal) (string, error)
+	reserve(ctx context.Context, orderId string, userId string, currency string, amount decimal.Decimal) (string, error)
+	assign(ctx context.Context, userId string, reservationId string, orderId string) error
+	release(ctx context.Context, userId string, reservationId string, orderId string) (releasedFunds, error)
+	settle(ctx context.Context, orderId string, funds orderFunds) (releasedFunds, bool, error)
+	assignedOrderIds(ctx context.Context) ([]string, error)
+	getBalance(ctx context.Context, userId string, currency string) (*model.Balance, error)
+	listBalances(ctx context.Context, userId string) ([]*model.Balance, error)
+	getTransactionHistory(ctx context.Context, userId string, currency string, page int32, pageSize int32) ([]*model.Transaction, int32, error)
+}
+
+// wallet posts the movements of funds to the ledger and publishes the resulting balances to subscribers.
+type wallet struct {
+	ledger   ledger
+	balances *balanceSubscriptions
+
+	// publishMutex serialises the reading and publishing of balances so that a balance read after a later movement
+	// cannot be overtaken by one read before it.
+	publishMutex sync.Mutex
+}
+
+func newWallet(ledger ledger) *wallet {
+	return &wallet{ledger: ledger, balances: newBalanceSubscriptions()}
+}
+
+func (w *wallet) deposit(ctx context.Context, userId string, currency string, amount decimal.Decimal, method string) (string, error) {
+	id, err := w.ledger.deposit(ctx, userId, currency, amount, method)
+	if err != nil {
+		return "", err
+	}
+
+	w.publishBalance(ctx, userId, currency)
+	return id, nil
+}
+
+func (w *wallet) withdraw(ctx context.Context, userId string, currency string, amount decimal.Decimal, destination string) (string, error) {
+	id, err := w.ledger.withdraw(ctx, userId, currency, amount, destination)
+	if err != nil {
+		return "", err
+	}
+
+	w.publishBalance(ctx, userId, currency)
+	return id, nil
+}
+
+func (w *wallet) transfer(ctx context.Context, fromUserId string, toUserId string, currency string, amount decimal.Decimal) (string, error) {
+	id, err := w.ledger.transfer(ctx, fromUserId, toUserId, currency, amount)
+	if err != nil {
+		return "", err
+	}
+
+	w.publishBalance(ctx, fromUserId, currency)
+	w.publishBalance(ctx, toUserId, currency)
+	return id, nil
+}
+
+func (w *wallet) reserve(ctx context.Context, orderId string, userId string, currency string, amount decimal.Decimal) (string, error) {
+	id, err := w.ledger.reserve(ctx, orderId, userId, currency, amount)
+	if err != nil {
+		return "", err
+	}
+
+	w.publishBalance(ctx, userId, currency)
+	return id, nil
+}
+
+func (w *wallet) assign(ctx context.Context, userId string, reservationId string, orderId string) error {
+	return w.ledger.assign(ctx, userId, reservationId, orderId)
+}
+
+func (w *wallet) release(ctx context.Context, userId string, reservationId string, orderId string) (releasedFunds, error) {
+	released, err := w.ledger.release(ctx, userId, reservationId, orderId)
+	if err != nil {
+		return releasedFunds{}, err
+	}
+
+	if !released.amount.IsZero() {
+		w.publishBalance(ctx, released.userId, released.currency)
+	}
+	return released, nil
+}
+
+func (w *wallet) settle(ctx context.Context, orderId string, funds orderFunds) error {
+	settled, changed, err := w.ledger.settle(ctx, orderId, funds)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		w.publishBalance(ctx, settled.userId, settled.currency)
+	}
+	return nil
+}
+
+func (w *wallet) subscribe(ctx context.Context, userId string) ([]*model.Balance, *balanceSubscription, error) {
+	subscription := w.balances.subscribe(userId)
+
+	balances, err := w.ledger.listBalances(ctx, userId)
+	if err != nil {
+		w.balances.unsubscribe(subscription)
+		return nil, nil, err
+	}
+
+	return balances, subscription, nil
+}
+
+// publishBalance publishes the user's current balance in the currency, the movement of funds has already been
+// committed to the ledger so a failure to read the balance is logged rather than returned.
+func (w *wallet) publishBalance(ctx context.Context, userId string, currency string) {
+	w.publishMutex.Lock()
+	defer w.publishMutex.Unlock()
+
+	balance, err := w.ledger.getBalance(ctx, userId, currency)
+	if err != nil {
+		slog.Error("failed to get balance to publish", "userId", userId, "currency", currency, "error", err)
+		return
+	}
+
+	w.balances.publish(balance)
+}
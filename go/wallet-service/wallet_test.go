@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"github.com/ettec/open-trading-platform/go/wallet-service/api/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+// testLedger is an in memory ledger of available and locked balances keyed by user and currency.
+type testLedger struct {
+	available    map[accountKey]decimal.Decimal
+	reservations map[string]releasedFunds
+	orderIds     map[string]string
+	settled      map[string]orderFunds
+}
+
+func newTestLedger() *testLedger {
+	return &testLedger{
+		available:    map[accountKey]decimal.Decimal{},
+		reservations: map[string]releasedFunds{},
+		orderIds:     map[string]string{},
+		settled:      map[string]orderFunds{},
+	}
+}
+
+func (l *testLedger) move(userId string, currency string, amount decimal.Decimal) error {
+	key := accountKey{userId: userId, currency: currency, kind: accountAvailable}
+	balance := l.available[key].Add(amount)
+	if balance.IsNegative() {
+		return errInsufficientFunds
+	}
+	l.available[key] = balance
+	return nil
+}
+
+func (l *testLedger) deposit(_ context.Context, userId string, currency string, amount decimal.Decimal, _ string) (string, error) {
+	return "1", l.move(userId, currency, amount)
+}
+
+func (l *testLedger) withdraw(_ context.Context, userId string, currency string, amount decimal.Decimal, _ string) (string, error) {
+	return "2", l.move(userId, currency, amount.Neg())
+}
+
+func (l *testLedger) transfer(_ context.Context, fromUserId string, toUserId string, currency string, amount decimal.Decimal) (string, error) {
+	if err := l.move(fromUserId, currency, amount.Neg()); err != nil {
+		return "", err
+	}
+	return "3", l.move(toUserId, currency, amount)
+}
+
+func (l *testLedger) reserve(_ context.Context, orderId string, userId string, currency string, amount decimal.Decimal) (string, error) {
+	if reservationId, exists := l.orderIds[orderId]; exists && orderId != "" {
+		return reservationId, nil
+	}
+	if err := l.move(userId, currency, amount.Neg()); err != nil {
+		return "", err
+	}
+	reservationId := strconv.Itoa(len(l.reservations) + 1)
+	l.reservations[reservationId] = releasedFunds{userId: userId, currency: currency, amount: amount}
+	if orderId != "" {
+		l.orderIds[orderId] = reservationId
+	}
+	return reservationId, nil
+}
+
+func (l *testLedger) assign(_ context.Context, userId string, reservationId string, orderId string) error {
+	if reservation, exists := l.reservations[reservationId]; !exists || reservation.userId != userId {
+		return errNoReservation
+	}
+	l.orderIds[orderId] = reservationId
+	return nil
+}
+
+func (l *testLedger) release(_ context.Context, userId string, reservationId string, orderId string) (releasedFunds, error) {
+	if reservationId == "" {
+		reservationId = l.orderIds[orderId]
+	}
+	reservation, exists := l.reservations[reservationId]
+	if !exists || reservation.userId != userId {
+		return releasedFunds{}, errNoReservation
+	}
+	l.reservations[reservationId] = releasedFunds{userId: reservation.userId, currency: reservation.currency, amount: zero()}
+	return reservation, l.move(reservation.userId, reservation.currency, reservation.amount)
+}
+
+func (l *testLedger) settle(_ context.Context, orderId string, funds orderFunds) (releasedFunds, bool, error) {
+	reservation, exists := l.reservations[l.orderIds[orderId]]
+	if !exists {
+		return releasedFunds{}, false, errNoReservation
+	}
+	l.settled[orderId] = funds
+	return reservation, true, nil
+}
+
+func (l *testLedger) assignedOrderIds(context.Context) ([]string, error) {
+	var result []string
+	for orderId := range l.orderIds {
+		result = append(result, orderId)
+	}
+	return result, nil
+}
+
+func (l *testLedger) locked(userId string, currency string) decimal.Decimal {
+	total := zero()
+	for _, reservation := range l.reservations {
+		if reservation.userId == userId && reservation.currency == currency {
+			total = total.Add(reservation.amount)
+		}
+	}
+	return total
+}
+
+func (l *testLedger) getBalance(_ context.Context, userId string, currency string) (*model.Balance, error) {
+	available := l.available[accountKey{userId: userId, currency: currency, kind: accountAvailable}]
+	locked := l.locked(userId, currency)
+	return &model.Balance{UserId: userId, Currency: currency, Balance: toFloat(available.Add(locked)),
+		Available: toFloat(available), Locked: toFloat(locked)}, nil
+}
+
+func (l *testLedger) listBalances(ctx context.Context, userId string) ([]*model.Balance, error) {
+	var result []*model.Balance
+	for key := range l.available {
+		if key.userId == userId {
+			balance, _ := l.getBalance(ctx, userId, key.currency)
+			result = append(result, balance)
+		}
+	}
+	return result, nil
+}
+
+func (l *testLedger) getTransactionHistory(context.Context, string, string, int32, int32) ([]*model.Transaction, int32, error) {
+	return nil, 0, nil
+}
+
+func zero() decimal.Decimal {
+	return decimal.New(0, 0)
+}
+
+func TestSubscriberReceivesBalancesOfItsUser(t *testing.T) {
+	ctx := context.Background()
+	w := newWallet(newTestLedger())
+
+	_, err := w.deposit(ctx, "user1", "USD", decimal.New(100, 0), "bank_transfer")
+	assert.NoError(t, err)
+
+	initial, subscription, err := w.subscribe(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Len(t, initial, 1)
+	assert.Equal(t, 100.0, initial[0].Available)
+
+	_, err = w.deposit(ctx, "user2", "USD", decimal.New(10, 0), "bank_transfer")
+	assert.NoError(t, err)
+	_, err = w.deposit(ctx, "user1", "EUR", decimal.New(10, 0), "bank_transfer")
+	assert.NoError(t, err)
+	_, err = w.reserve(ctx, "order1", "user1", "USD", decimal.New(40, 0))
+	assert.NoError(t, err)
+	_, err = w.transfer(ctx, "user1", "user2", "USD", decimal.New(10, 0))
+	assert.NoError(t, err)
+
+	<-subscription.changed
+	changes := w.balances.takeChanges(subscription)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "EUR", changes[0].Currency)
+	assert.Equal(t, 10.0, changes[0].Available)
+	assert.Equal(t, "USD", changes[1].Currency)
+	assert.Equal(t, 50.0, changes[1].Available)
+	assert.Equal(t, 40.0, changes[1].Locked)
+	assert.Equal(t, 90.0, changes[1].Balance)
+
+	assert.Empty(t, w.balances.takeChanges(subscription))
+
+	w.balances.unsubscribe(subscription)
+	_, err = w.release(ctx, "user1", "", "order1")
+	assert.NoError(t, err)
+	assert.Empty(t, w.balances.takeChanges(subscription))
+}
+
+func TestFailedMovementIsNotPublished(t *testing.T) {
+	ctx := context.Background()
+	w := newWallet(newTestLedger())
+
+	_, subscription, err := w.subscribe(ctx, "user1")
+	assert.NoError(t, err)
+
+	_, err = w.withdraw(ctx, "user1", "USD", decimal.New(1, 0), "bank1")
+	assert.Equal(t, errInsufficientFunds, err)
+	assert.Empty(t, w.balances.takeChanges(subscription))
+}
@@ -74,6 +74,21 @@ kubectl wait pods -n kafka -l run=kafka-opentp-client --for condition=Ready --ti
 #Orders Topic
 kubectl exec --tty -i kafka-opentp-client --namespace kafka -- bash -c "kafka-topics.sh --create --topic orders --bootstrap-server kafka-opentp.kafka.svc.cluster.local:9092"
 
+#Trading Halts Topic, compacted so that the latest state of each halt is retained
+kubectl exec --tty -i kafka-opentp-client --namespace kafka -- bash -c "kafka-topics.sh --create --topic trading-halts --partitions 1 --config cleanup.policy=compact --bootstrap-server kafka-opentp.kafka.svc.cluster.local:9092"
+
+#Fix Simulator Executions Topic, compacted so that the latest executions applied to each order are retained
+kubectl exec --tty -i kafka-opentp-client --namespace kafka -- bash -c "kafka-topics.sh --create --topic fix-sim-executions --partitions 1 --config cleanup.policy=compact --bootstrap-server kafka-opentp.kafka.svc.cluster.local:9092"
+
+#Fix Simulator Reconciliations Topic, the summary of each reconciliation of a fix session's orders with the venue on logon
+kubectl exec --tty -i kafka-opentp-client --namespace kafka -- bash -c "kafka-topics.sh --create --topic fix-sim-reconciliations --partitions 1 --bootstrap-server kafka-opentp.kafka.svc.cluster.local:9092"
+
+#Trades Topic, a single partition so that trades are read back in the order they were captured
+kubectl exec --tty -i kafka-opentp-client --namespace kafka -- bash -c "kafka-topics.sh --create --topic trades --partitions 1 --bootstrap-server kafka-opentp.kafka.svc.cluster.local:9092"
+
+#Pnl Snapshots Topic, compacted so that the latest end of day snapshot of each pnl book is retained
+kubectl exec --tty -i kafka-opentp-client --namespace kafka -- bash -c "kafka-topics.sh --create --topic pnl-snapshots --partitions 1 --config cleanup.policy=compact --bootstrap-server kafka-opentp.kafka.svc.cluster.local:9092"
+
 #Postgres
 
 echo installing Postgresql database...
@@ -33,15 +33,26 @@ public class Connection {
 
         log.info("Received subscription request", msg);
 
+        boolean unsubscribe = msg.getSubscriptionRequestType() ==
+                MarketData.SubscriptionRequestTypeEnum.SUBSCRIPTION_REQUEST_TYPE_DISABLE_PREVIOUS_SNAPSHOT;
+
         for( InstrmtMDReqGrp mdReqGrp : msg.getInstrmtMdReqGrpList() ) {
             String symbol = mdReqGrp.getInstrument().getSymbol();
 
+            if( unsubscribe ) {
+                log.info("Received unsubscribe request for symbol {}", symbol);
+
+                var subscription = subscriptions.remove(symbol);
+                if( subscription != null ) {
+                    subscription.close();
+                }
+                continue;
+            }
+
             log.info("Received subscribe request for symbol {}", symbol);
 
             if( subscriptions.containsKey(symbol)) {
-                var subscription = subscriptions.remove(symbol);
-                subscription.close();
-                return;
+                continue;
             }
 
             OrderBook book = exchange.getOrderBook(symbol);
@@ -26,7 +26,12 @@ cd $line
 COMPNAME=otp-$(basename "$PWD")
 
        echo releasing $COMPNAME
-       docker build -t $REPO/$COMPNAME:$TAG .
+       if grep -qs '=> \.\./' go.mod; then
+           # Components that depend on other modules of the repository are built with the go directory as the build context
+           docker build -t $REPO/$COMPNAME:$TAG -f Dockerfile $DIRECTORY/go
+       else
+           docker build -t $REPO/$COMPNAME:$TAG .
+       fi
        docker push $REPO/$COMPNAME:$TAG
 
 cd $DIRECTORY